@@ -25,6 +25,7 @@ import (
 	"github.com/vmware/govmomi/list"
 	"github.com/vmware/govmomi/object"
 	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/view"
 	"github.com/vmware/govmomi/vim25"
 	"github.com/vmware/govmomi/vim25/mo"
 	"github.com/vmware/govmomi/vim25/types"
@@ -147,7 +148,24 @@ func (f *Finder) find(ctx context.Context, arg string, s *spec) ([]list.Element,
 
 	s.Parents = append(s.Parents, s.Nested...)
 
-	return f.r.Find(ctx, s, root, parts)
+	es, err := f.r.Find(ctx, s, root, parts)
+	if err != nil {
+		return nil, err
+	}
+
+	// An entity can be reached more than once, such as a VM in both a vApp and a vm folder
+	seen := make(map[types.ManagedObjectReference]bool, len(es))
+	var unique []list.Element
+
+	for _, e := range es {
+		ref := e.Object.Reference()
+		if !seen[ref] {
+			seen[ref] = true
+			unique = append(unique, e)
+		}
+	}
+
+	return unique, nil
 }
 
 func (f *Finder) datacenter() (*object.Datacenter, error) {
@@ -780,7 +798,80 @@ func (f *Finder) NetworkList(ctx context.Context, path string) ([]object.Network
 	}
 
 	if len(ns) == 0 {
-		return nil, &NotFoundError{"network", path}
+		ns, err = f.networkByID(ctx, path)
+		if err != nil || len(ns) == 0 {
+			return nil, &NotFoundError{"network", path}
+		}
+	}
+
+	return ns, nil
+}
+
+// networkByID returns the networks backed by the given NSX ID, matching either the
+// OpaqueNetwork opaqueNetworkId or the DistributedVirtualPortgroup logicalSwitchUuid or segmentId.
+func (f *Finder) networkByID(ctx context.Context, id string) ([]object.NetworkReference, error) {
+	root := f.client.ServiceContent.RootFolder
+	if f.dc != nil {
+		folder, err := f.networkFolder(ctx)
+		if err != nil {
+			return nil, err
+		}
+		root = folder.Reference()
+	}
+
+	kind := []string{"OpaqueNetwork", "DistributedVirtualPortgroup"}
+
+	v, err := view.NewManager(f.client).CreateContainerView(ctx, root, kind, true)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = v.Destroy(ctx)
+	}()
+
+	var refs []types.ManagedObjectReference
+
+	var opaque []mo.OpaqueNetwork
+	err = v.Retrieve(ctx, kind[:1], []string{"summary"}, &opaque)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, net := range opaque {
+		if s, ok := net.Summary.(*types.OpaqueNetworkSummary); ok && s.OpaqueNetworkId == id {
+			refs = append(refs, net.Self)
+		}
+	}
+
+	if len(refs) == 0 {
+		// logicalSwitchUuid and segmentId require vSphere 7.0 and are not included in the generated
+		// types.DVPortgroupConfigInfo, so the property values are matched as-is.
+		var pgs []types.ObjectContent
+		err = v.Retrieve(ctx, kind[1:], []string{"config.logicalSwitchUuid", "config.segmentId"}, &pgs)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, pg := range pgs {
+			for _, p := range pg.PropSet {
+				if p.Val == id {
+					refs = append(refs, pg.Obj)
+					break
+				}
+			}
+		}
+	}
+
+	var ns []object.NetworkReference
+
+	for _, ref := range refs {
+		r, err := f.ObjectReference(ctx, ref)
+		if err != nil {
+			return nil, err
+		}
+
+		ns = append(ns, r.(object.NetworkReference))
 	}
 
 	return ns, nil
@@ -980,6 +1071,7 @@ func (f *Finder) VirtualMachine(ctx context.Context, path string) (*object.Virtu
 func (f *Finder) VirtualAppList(ctx context.Context, path string) ([]*object.VirtualApp, error) {
 	s := &spec{
 		Relative: f.vmFolder,
+		Parents:  []string{"VirtualApp"},
 	}
 
 	es, err := f.find(ctx, path, s)
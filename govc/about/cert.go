@@ -37,6 +37,7 @@ type cert struct {
 
 	show       bool
 	thumbprint bool
+	sha256     bool
 }
 
 func init() {
@@ -52,6 +53,7 @@ func (cmd *cert) Register(ctx context.Context, f *flag.FlagSet) {
 
 	f.BoolVar(&cmd.show, "show", false, "Show PEM encoded server certificate only")
 	f.BoolVar(&cmd.thumbprint, "thumbprint", false, "Output host hash and thumbprint only")
+	f.BoolVar(&cmd.sha256, "sha256", false, "Output SHA-256 thumbprint with -thumbprint")
 }
 
 func (cmd *cert) Description() string {
@@ -60,11 +62,14 @@ func (cmd *cert) Description() string {
 If the HOST certificate cannot be verified, about.cert will return with exit code 60 (as curl does).
 If the '-k' flag is provided, about.cert will return with exit code 0 in this case.
 The SHA1 thumbprint can also be used as '-thumbprint' for the 'host.add' and 'cluster.add' commands.
+The '-sha256' flag outputs the SHA-256 thumbprint with '-thumbprint', SHA-1 and SHA-256 thumbprints
+can both be used in the GOVC_TLS_KNOWN_HOSTS file.
 
 Examples:
   govc about.cert -k -json | jq -r .ThumbprintSHA1
   govc about.cert -k -show | sudo tee /usr/local/share/ca-certificates/host.crt
-  govc about.cert -k -thumbprint | tee -a ~/.govmomi/known_hosts`
+  govc about.cert -k -thumbprint | tee -a ~/.govmomi/known_hosts
+  govc about.cert -k -thumbprint -sha256 | tee -a ~/.govmomi/known_hosts`
 }
 
 func (cmd *cert) Process(ctx context.Context) error {
@@ -89,7 +94,11 @@ func (r *certResult) Write(w io.Writer) error {
 
 	if r.cmd.thumbprint {
 		u := r.cmd.URLWithoutPassword()
-		_, err := fmt.Fprintf(w, "%s %s\n", u.Host, r.info.ThumbprintSHA1)
+		thumbprint := r.info.ThumbprintSHA1
+		if r.cmd.sha256 {
+			thumbprint = r.info.ThumbprintSHA256
+		}
+		_, err := fmt.Fprintf(w, "%s %s\n", u.Host, thumbprint)
 		return err
 	}
 
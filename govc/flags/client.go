@@ -18,9 +18,7 @@ package flags
 
 import (
 	"context"
-	"crypto/sha1"
 	"crypto/tls"
-	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
@@ -36,11 +34,11 @@ import (
 	"time"
 
 	"github.com/vmware/govmomi/session"
+	"github.com/vmware/govmomi/session/cache"
 	"github.com/vmware/govmomi/sts"
 	"github.com/vmware/govmomi/vapi/rest"
 	"github.com/vmware/govmomi/vim25"
 	"github.com/vmware/govmomi/vim25/soap"
-	"github.com/vmware/govmomi/vim25/types"
 )
 
 const (
@@ -325,14 +323,12 @@ func (flag *ClientFlag) configure(sc *soap.Client) (soap.RoundTripper, error) {
 	return vim25.Retry(sc, vim25.TemporaryNetworkError(3)), nil
 }
 
-func (flag *ClientFlag) sessionFile() string {
-	url := flag.URLWithoutPassword()
-
-	// Key session file off of full URI and insecure setting.
-	// Hash key to get a predictable, canonical format.
-	key := fmt.Sprintf("%s#insecure=%t", url.String(), flag.insecure)
-	name := fmt.Sprintf("%040x", sha1.Sum([]byte(key)))
-	return filepath.Join(home, "sessions", name)
+func (flag *ClientFlag) session() *cache.Session {
+	return &cache.Session{
+		URL:      flag.url,
+		Insecure: flag.insecure,
+		Dir:      filepath.Join(home, "sessions"),
+	}
 }
 
 func (flag *ClientFlag) saveClient(c *vim25.Client) error {
@@ -340,87 +336,15 @@ func (flag *ClientFlag) saveClient(c *vim25.Client) error {
 		return nil
 	}
 
-	p := flag.sessionFile()
-	err := os.MkdirAll(filepath.Dir(p), 0700)
-	if err != nil {
-		return err
-	}
-
-	f, err := os.OpenFile(p, os.O_CREATE|os.O_WRONLY, 0600)
-	if err != nil {
-		return err
-	}
-	defer f.Close()
-
-	err = json.NewEncoder(f).Encode(c)
-	if err != nil {
-		return err
-	}
-
-	return nil
-}
-
-func (flag *ClientFlag) restoreClient(c *vim25.Client) (bool, error) {
-	if !flag.persist {
-		return false, nil
-	}
-
-	f, err := os.Open(flag.sessionFile())
-	if err != nil {
-		if os.IsNotExist(err) {
-			return false, nil
-		}
-
-		return false, err
-	}
-
-	defer f.Close()
-
-	dec := json.NewDecoder(f)
-	err = dec.Decode(c)
-	if err != nil {
-		return false, err
-	}
-
-	return true, nil
+	return flag.session().Save(c)
 }
 
 func (flag *ClientFlag) loadClient() (*vim25.Client, error) {
-	c := new(vim25.Client)
-	ok, err := flag.restoreClient(c)
-	if err != nil {
-		return nil, err
-	}
-
-	if !ok || !c.Valid() {
-		return nil, nil
-	}
-
-	c.RoundTripper, err = flag.configure(c.Client)
-	if err != nil {
-		return nil, err
-	}
-
-	m := session.NewManager(c)
-	u, err := m.UserSession(context.TODO())
-	if err != nil {
-		if soap.IsSoapFault(err) {
-			fault := soap.ToSoapFault(err).VimFault()
-			// If the PropertyCollector is not found, the saved session for this URL is not valid
-			if _, ok := fault.(types.ManagedObjectNotFound); ok {
-				return nil, nil
-			}
-		}
-
-		return nil, err
-	}
-
-	// If the session is nil, the client is not authenticated
-	if u == nil {
+	if !flag.persist {
 		return nil, nil
 	}
 
-	return c, nil
+	return flag.session().Load(context.TODO(), flag.configure)
 }
 
 func (flag *ClientFlag) SetRootCAs(c *soap.Client) error {
@@ -447,6 +371,11 @@ func (flag *ClientFlag) login(ctx context.Context, c *vim25.Client) error {
 			name = u.Username()
 		}
 	}
+	if name == "" && c.Certificate() != nil && c.IsVC() {
+		// Solution user login, using a holder-of-key token issued for the client certificate
+		_, err := sts.LoginByCertificate(ctx, c, sts.TokenRequest{Delegatable: true})
+		return err
+	}
 	if name == "" {
 		// Skip auto-login if we don't have a username
 		flag.persist = true // Not persisting, but this avoids the call to Logout()
@@ -545,6 +474,15 @@ func (flag *ClientFlag) Client() (*vim25.Client, error) {
 		return flag.client, nil
 	}
 
+	if flag.persist {
+		// Serialize with other processes using the same session, so only one of them logs in
+		unlock, err := flag.session().Lock()
+		if err != nil {
+			return nil, err
+		}
+		defer unlock()
+	}
+
 	c, err := flag.loadClient()
 	if err != nil {
 		return nil, err
@@ -590,19 +528,37 @@ func (flag *ClientFlag) WithRestClient(ctx context.Context, f func(*rest.Client)
 	}
 
 	// TODO: rest.Client session cookie should be persisted as the soap.Client session cookie is.
-	if vc.Certificate() == nil {
+	if token := os.Getenv("GOVC_CSP_API_TOKEN"); token != "" {
+		// VMware Cloud vCenters authenticate via the Cloud Services Platform
+		if err = c.LoginByCSP(ctx, &rest.CSPTokenSource{APIToken: token}); err != nil {
+			return err
+		}
+	} else if vc.Certificate() == nil {
 		if err = c.Login(ctx, flag.Userinfo()); err != nil {
 			return err
 		}
 	} else {
-		// TODO: session.login should support rest.Client SSO login to avoid this env var (depends on the TODO above)
-		token := os.Getenv("GOVC_LOGIN_TOKEN")
-		if token == "" {
-			return errors.New("GOVC_LOGIN_TOKEN must be set for rest.Client SSO login")
-		}
+		// GOVC_LOGIN_TOKEN can be used to reuse a token issued by session.login,
+		// otherwise a holder-of-key token is issued using the client certificate.
 		signer := &sts.Signer{
 			Certificate: c.Certificate(),
-			Token:       token,
+			Token:       os.Getenv("GOVC_LOGIN_TOKEN"),
+		}
+
+		if signer.Token == "" {
+			tokens, err := sts.NewClient(ctx, vc)
+			if err != nil {
+				return err
+			}
+
+			req := sts.TokenRequest{
+				Certificate: vc.Certificate(),
+				Delegatable: true,
+			}
+
+			if signer, err = tokens.Issue(ctx, req); err != nil {
+				return err
+			}
 		}
 
 		if err = c.LoginByToken(c.WithSigner(ctx, signer)); err != nil {
@@ -71,13 +71,21 @@ func (flag *HostConnectFlag) Spec(c *vim25.Client) types.HostConnectSpec {
 	spec := flag.HostConnectSpec
 
 	if spec.SslThumbprint == "" {
-		spec.SslThumbprint = c.Thumbprint(spec.HostName)
+		spec.SslThumbprint = c.ThumbprintSHA1(spec.HostName, nil)
 
-		if spec.SslThumbprint == "" && flag.noverify {
+		known := c.Thumbprint(spec.HostName) != ""
+		if spec.SslThumbprint == "" && (known || flag.noverify) {
 			var info object.HostCertificateInfo
 			t := c.Transport.(*http.Transport)
 			_ = info.FromURL(&url.URL{Host: spec.HostName}, t.TLSClientConfig)
-			spec.SslThumbprint = info.ThumbprintSHA1
+			if known {
+				// only a SHA-256 thumbprint is known, use the SHA-1 thumbprint of the matching certificate
+				if info.Certificate != nil {
+					spec.SslThumbprint = c.ThumbprintSHA1(spec.HostName, info.Certificate)
+				}
+			} else {
+				spec.SslThumbprint = info.ThumbprintSHA1
+			}
 		}
 	}
 
@@ -20,6 +20,7 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"time"
 
 	"github.com/vmware/govmomi/govc/cli"
 	"github.com/vmware/govmomi/govc/flags"
@@ -31,6 +32,7 @@ type enter struct {
 
 	timeout  int32
 	evacuate bool
+	apply    bool
 }
 
 func init() {
@@ -43,6 +45,7 @@ func (cmd *enter) Register(ctx context.Context, f *flag.FlagSet) {
 
 	f.Var(flags.NewInt32(&cmd.timeout), "timeout", "Timeout")
 	f.BoolVar(&cmd.evacuate, "evacuate", false, "Evacuate powered off VMs")
+	f.BoolVar(&cmd.apply, "apply", false, "Apply DRS recommendations to evacuate VMs")
 }
 
 func (cmd *enter) Process(ctx context.Context) error {
@@ -60,10 +63,26 @@ func (cmd *enter) Description() string {
 	return `Put HOST in maintenance mode.
 
 While this task is running and when the host is in maintenance mode,
-no VMs can be powered on and no provisioning operations can be performed on the host.`
+no VMs can be powered on and no provisioning operations can be performed on the host.
+
+When HOST is in a cluster where DRS is not fully automated, the task waits until the
+recommendations to migrate VMs off the host are applied, which the '-apply' flag does automatically.
+
+Examples:
+  govc host.maintenance.enter -apply -timeout 600 $host`
 }
 
 func (cmd *enter) EnterMaintenanceMode(ctx context.Context, host *object.HostSystem) error {
+	if cmd.apply {
+		opts := object.MaintenanceModeOptions{
+			Timeout:               time.Duration(cmd.timeout) * time.Second,
+			EvacuatePoweredOffVms: cmd.evacuate,
+			ApplyRecommendations:  true,
+		}
+
+		return host.EnterMaintenanceModeAndWait(ctx, opts)
+	}
+
 	task, err := host.EnterMaintenanceMode(ctx, cmd.timeout, cmd.evacuate, nil) // TODO: spec param
 	if err != nil {
 		return err
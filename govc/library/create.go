@@ -46,6 +46,7 @@ func (cmd *create) Register(ctx context.Context, f *flag.FlagSet) {
 	cmd.sub.OnDemand = new(bool)
 
 	f.StringVar(&cmd.library.Description, "d", "", "Description of library")
+	f.StringVar(&cmd.library.SecurityPolicyID, "policy", "", "Security policy ID")
 	f.StringVar(&cmd.sub.SubscriptionURL, "sub", "", "Subscribe to library URL")
 	f.StringVar(&cmd.sub.UserName, "sub-username", "", "Subscription username")
 	f.StringVar(&cmd.sub.Password, "sub-password", "", "Subscription password")
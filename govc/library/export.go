@@ -20,25 +20,25 @@ import (
 	"context"
 	"flag"
 	"fmt"
-	"io"
-	"io/ioutil"
-	"net/url"
 	"os"
 	"path/filepath"
-	"time"
 
 	"github.com/vmware/govmomi/govc/cli"
 	"github.com/vmware/govmomi/govc/flags"
 	"github.com/vmware/govmomi/vapi/library"
 	"github.com/vmware/govmomi/vapi/library/finder"
 	"github.com/vmware/govmomi/vapi/rest"
-	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/govmomi/vim25/progress"
 )
 
 type export struct {
 	*flags.ClientFlag
 	*flags.OutputFlag
 	library.Item
+
+	ova      bool
+	manifest bool
+	verify   bool
 }
 
 func init() {
@@ -51,6 +51,10 @@ func (cmd *export) Register(ctx context.Context, f *flag.FlagSet) {
 
 	cmd.OutputFlag, ctx = flags.NewOutputFlag(ctx)
 	cmd.OutputFlag.Register(ctx, f)
+
+	f.BoolVar(&cmd.ova, "ova", false, "Export OVF item as a single OVA file")
+	f.BoolVar(&cmd.manifest, "mf", false, "Regenerate OVF item manifest")
+	f.BoolVar(&cmd.verify, "verify", true, "Verify file checksums")
 }
 
 func (cmd *export) Usage() string {
@@ -69,10 +73,17 @@ If DEST is given for a library item, files are saved there instead of the curren
 If DEST is given for a library item file, the file will be saved with that name.
 If DEST is '-', the file contents are written to stdout instead of saving to a file.
 
+The '-ova' flag exports an OVF library item as a single OVA file, DEST defaults to the item name with an '.ova' extension.
+The '-mf' flag exports an OVF library item with a SHA256 manifest generated from the exported files,
+replacing the item's manifest if any. A manifest is also generated for OVF items that do not have one.
+Unless '-verify=false', file checksums are verified against those reported by vCenter and the item's manifest.
+
 Examples:
   govc library.export library_name/item_name
   govc library.export library_name/item_name/file_name
-  govc library.export library_name/item_name/*.ovf -`
+  govc library.export library_name/item_name/*.ovf -
+  govc library.export -ova library_name/item_name
+  govc library.export -mf library_name/item_name ./item_name`
 }
 
 func (cmd *export) Process(ctx context.Context) error {
@@ -107,21 +118,50 @@ func (cmd *export) Run(ctx context.Context, f *flag.FlagSet) error {
 
 		dst := f.Arg(1)
 		one := len(names) == 1
-		var log io.Writer = os.Stdout
-		isStdout := one && dst == "-"
-		if isStdout {
-			log = ioutil.Discard
+		isStdout := (one || cmd.ova) && dst == "-"
+
+		param := &library.Export{
+			Download: library.Download{Retries: 3, SkipChecksum: !cmd.verify},
+			Manifest: cmd.manifest,
+		}
+
+		var logger interface{ Wait() }
+		wait := func() {
+			if logger != nil {
+				logger.Wait()
+				logger = nil
+			}
+		}
+		defer wait()
+
+		sink := func(name string) progress.Sinker {
+			if isStdout || !cmd.OutputFlag.TTY {
+				return nil
+			}
+			wait()
+			l := cmd.ProgressLogger(fmt.Sprintf("Downloading %s... ", name))
+			logger = l
+			return l
 		}
 
-		session, err := m.CreateLibraryItemDownloadSession(ctx, library.Session{
-			LibraryItemID: cmd.ID,
-		})
+		if cmd.ova || cmd.manifest {
+			if one || cmd.Type != "ovf" {
+				return fmt.Errorf("%q is not an OVF library item", f.Arg(0))
+			}
+			param.Progress = sink
+			return cmd.exportOVF(ctx, m, dst, param)
+		}
+
+		session, err := m.NewDownloadSession(ctx, cmd.ID, &param.Download)
 		if err != nil {
 			return err
 		}
+		defer func() {
+			_ = session.Close(ctx)
+		}()
 
 		if len(names) == 0 {
-			files, err := m.ListLibraryItemDownloadSessionFile(ctx, session)
+			files, err := m.ListLibraryItemDownloadSessionFile(ctx, session.ID())
 			if err != nil {
 				return err
 			}
@@ -132,59 +172,34 @@ func (cmd *export) Run(ctx context.Context, f *flag.FlagSet) error {
 		}
 
 		for _, name := range names {
-			_, err = m.PrepareLibraryItemDownloadSessionFile(ctx, session, name)
+			_, err = m.PrepareLibraryItemDownloadSessionFile(ctx, session.ID(), name)
 			if err != nil {
 				return err
 			}
 		}
 
-		download := func(src *url.URL, name string) error {
-			p := soap.DefaultDownload
-
+		for _, name := range names {
 			if isStdout {
-				s, _, err := c.Download(ctx, src, &p)
-				if err != nil {
+				if err = session.Download(ctx, name, os.Stdout, nil); err != nil {
 					return err
 				}
-				_, err = io.Copy(os.Stdout, s)
-				_ = s.Close()
-				return err
-			}
-
-			if cmd.OutputFlag.TTY {
-				logger := cmd.ProgressLogger(fmt.Sprintf("Downloading %s... ", src.String()))
-				defer logger.Wait()
-				p.Progress = logger
+				continue
 			}
 
+			path := filepath.Join(dst, name)
 			if one && dst != "" {
-				name = dst
-			} else {
-				name = filepath.Join(dst, name)
+				path = dst
 			}
-			return c.DownloadFile(ctx, name, src, &p)
-		}
 
-		for _, name := range names {
-			var info *library.DownloadFile
-			_, _ = fmt.Fprintf(log, "Checking %s... ", name)
-			for {
-				info, err = m.GetLibraryItemDownloadSessionFile(ctx, session, name)
-				if err != nil {
-					return err
-				}
-				if info.Status == "PREPARED" {
-					_, _ = fmt.Fprintln(log, info.Status)
-					break // with this status we have a DownloadEndpoint.URI
-				}
-				time.Sleep(time.Second)
-			}
-
-			src, err := url.Parse(info.DownloadEndpoint.URI)
+			file, err := os.Create(path)
 			if err != nil {
 				return err
 			}
-			err = download(src, name)
+			err = session.Download(ctx, name, file, sink(name))
+			wait()
+			if cerr := file.Close(); err == nil {
+				err = cerr
+			}
 			if err != nil {
 				return err
 			}
@@ -193,3 +208,29 @@ func (cmd *export) Run(ctx context.Context, f *flag.FlagSet) error {
 		return nil
 	})
 }
+
+func (cmd *export) exportOVF(ctx context.Context, m *library.Manager, dst string, param *library.Export) error {
+	if !cmd.ova {
+		if dst == "" {
+			dst = "."
+		}
+		return m.ExportOVF(ctx, cmd.ID, dst, param)
+	}
+
+	if dst == "-" {
+		return m.ExportOVA(ctx, cmd.ID, os.Stdout, param)
+	}
+	if dst == "" {
+		dst = cmd.Name + ".ova"
+	}
+
+	file, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	err = m.ExportOVA(ctx, cmd.ID, file, param)
+	if cerr := file.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
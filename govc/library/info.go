@@ -125,6 +125,11 @@ func (r infoResultsWriter) writeLibrary(
 		fmt.Fprintf(w, "    Auth:\t%s\n", v.Subscription.AuthenticationMethod)
 		fmt.Fprintf(w, "    Download:\t%s\n", dl)
 	}
+	if p := v.Publish; p != nil && p.Published != nil && *p.Published {
+		fmt.Fprintf(w, "  Publication:\n")
+		fmt.Fprintf(w, "    URL:\t%s\n", p.PublishURL)
+		fmt.Fprintf(w, "    Auth:\t%s\n", p.AuthenticationMethod)
+	}
 	return nil
 }
 func (r infoResultsWriter) writeItem(
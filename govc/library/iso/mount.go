@@ -0,0 +1,106 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iso
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strconv"
+
+	"github.com/vmware/govmomi/govc/cli"
+	"github.com/vmware/govmomi/govc/flags"
+	"github.com/vmware/govmomi/vapi/library"
+	"github.com/vmware/govmomi/vapi/library/finder"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vapi/vcenter"
+)
+
+type mount struct {
+	*flags.VirtualMachineFlag
+}
+
+func init() {
+	cli.Register("library.iso.mount", &mount{})
+}
+
+func (cmd *mount) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.VirtualMachineFlag, ctx = flags.NewVirtualMachineFlag(ctx)
+	cmd.VirtualMachineFlag.Register(ctx, f)
+}
+
+func (cmd *mount) Usage() string {
+	return "ITEM"
+}
+
+func (cmd *mount) Description() string {
+	return `Mount library ISO image ITEM on a new CD-ROM device of VM.
+
+The name of the new CD-ROM device is printed, for use with library.iso.unmount.
+
+Examples:
+  govc library.iso.mount -vm vm-1 /library_name/iso_item`
+}
+
+func (cmd *mount) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() != 1 {
+		return flag.ErrHelp
+	}
+
+	vm, err := cmd.VirtualMachine()
+	if err != nil {
+		return err
+	}
+	if vm == nil {
+		return flag.ErrHelp
+	}
+
+	return cmd.WithRestClient(ctx, func(c *rest.Client) error {
+		res, err := finder.NewFinder(library.NewManager(c)).Find(ctx, f.Arg(0))
+		if err != nil {
+			return err
+		}
+		if len(res) != 1 {
+			return fmt.Errorf("%q matches %d items", f.Arg(0), len(res))
+		}
+		item, ok := res[0].GetResult().(library.Item)
+		if !ok {
+			return fmt.Errorf("%q is a %T", f.Arg(0), res[0].GetResult())
+		}
+
+		cdrom, err := vcenter.NewManager(c).MountISO(ctx, item.ID, vm.Reference())
+		if err != nil {
+			return err
+		}
+
+		key, err := strconv.Atoi(cdrom)
+		if err != nil {
+			return err
+		}
+		devices, err := vm.Device(ctx)
+		if err != nil {
+			return err
+		}
+		device := devices.FindByKey(int32(key))
+		if device == nil {
+			return fmt.Errorf("device %s not found", cdrom)
+		}
+
+		fmt.Println(devices.Name(device))
+		return nil
+	})
+}
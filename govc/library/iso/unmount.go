@@ -0,0 +1,81 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iso
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strconv"
+
+	"github.com/vmware/govmomi/govc/cli"
+	"github.com/vmware/govmomi/govc/flags"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vapi/vcenter"
+)
+
+type unmount struct {
+	*flags.VirtualMachineFlag
+}
+
+func init() {
+	cli.Register("library.iso.unmount", &unmount{})
+}
+
+func (cmd *unmount) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.VirtualMachineFlag, ctx = flags.NewVirtualMachineFlag(ctx)
+	cmd.VirtualMachineFlag.Register(ctx, f)
+}
+
+func (cmd *unmount) Usage() string {
+	return "DEVICE"
+}
+
+func (cmd *unmount) Description() string {
+	return `Unmount library ISO image from CD-ROM DEVICE of VM.
+
+Examples:
+  govc library.iso.unmount -vm vm-1 cdrom-3001`
+}
+
+func (cmd *unmount) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() != 1 {
+		return flag.ErrHelp
+	}
+
+	vm, err := cmd.VirtualMachine()
+	if err != nil {
+		return err
+	}
+	if vm == nil {
+		return flag.ErrHelp
+	}
+
+	devices, err := vm.Device(ctx)
+	if err != nil {
+		return err
+	}
+	device := devices.Find(f.Arg(0))
+	if device == nil {
+		return fmt.Errorf("device %q not found", f.Arg(0))
+	}
+	key := strconv.Itoa(int(device.GetVirtualDevice().Key))
+
+	return cmd.WithRestClient(ctx, func(c *rest.Client) error {
+		return vcenter.NewManager(c).UnmountISO(ctx, vm.Reference(), key)
+	})
+}
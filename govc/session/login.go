@@ -81,6 +81,8 @@ func (cmd *login) Description() string {
 	return `Session login.
 
 The session.login command is optional, all other govc commands will auto login when given credentials.
+When connected to vCenter with a client certificate ('-cert' and '-key') and no username,
+govc commands login as the solution user for the certificate, using a holder-of-key token.
 The session.login command can be used to:
 - Persist a session without writing to disk via the '-cookie' flag
 - Acquire a clone ticket
@@ -19,6 +19,8 @@ package association
 import (
 	"context"
 	"flag"
+	"fmt"
+	"strings"
 
 	"github.com/vmware/govmomi/govc/cli"
 	"github.com/vmware/govmomi/govc/flags"
@@ -26,12 +28,26 @@ import (
 	"github.com/vmware/govmomi/vapi/library/finder"
 	"github.com/vmware/govmomi/vapi/rest"
 	"github.com/vmware/govmomi/vapi/tags"
+	"github.com/vmware/govmomi/view"
+	"github.com/vmware/govmomi/vim25/mo"
 	"github.com/vmware/govmomi/vim25/types"
 )
 
 type attach struct {
 	*flags.DatacenterFlag
-	cat string
+	cat  string
+	kind kinds
+}
+
+type kinds []string
+
+func (e *kinds) String() string {
+	return fmt.Sprint(*e)
+}
+
+func (e *kinds) Set(value string) error {
+	*e = append(*e, value)
+	return nil
 }
 
 func init() {
@@ -43,18 +59,26 @@ func (cmd *attach) Register(ctx context.Context, f *flag.FlagSet) {
 	cmd.DatacenterFlag.Register(ctx, f)
 
 	f.StringVar(&cmd.cat, "c", "", "Tag category")
+	f.Var(&cmd.kind, "type", "Attach to objects of this type within PATH")
 }
 
 func (cmd *attach) Usage() string {
-	return "NAME PATH"
+	return "NAME PATH..."
 }
 
 func (cmd *attach) Description() string {
 	return `Attach tag NAME to object PATH.
 
+PATH can be an inventory path pattern matching multiple objects.
+If the '-type' flag is specified, the tag is attached to all objects of the given type(s) within PATH,
+rather than PATH itself.
+
 Examples:
   govc tags.attach k8s-region-us /dc1
-  govc tags.attach -c k8s-region us-ca1 /dc1/host/cluster1`
+  govc tags.attach -c k8s-region us-ca1 /dc1/host/cluster1
+  govc tags.attach k8s-zone-us-ca1 /dc1/host/cluster1 /dc1/host/cluster2
+  govc tags.attach backup-daily /dc1/vm/db-*
+  govc tags.attach -type VirtualMachine -type VirtualApp backup-daily /dc1/vm/prod`
 }
 
 func convertPath(ctx context.Context, c *rest.Client, cmd *flags.DatacenterFlag, managedObj string) (*types.ManagedObjectReference, error) {
@@ -88,24 +112,88 @@ func convertPath(ctx context.Context, c *rest.Client, cmd *flags.DatacenterFlag,
 	return &ref, nil
 }
 
+// objects returns the objects matching the given path,
+// or if the type flag is specified, the objects of those types contained by the objects matching path.
+func (cmd *attach) objects(ctx context.Context, c *rest.Client, path string) ([]types.ManagedObjectReference, error) {
+	if len(cmd.kind) == 0 {
+		if !strings.ContainsAny(path, "*?[") {
+			ref, err := convertPath(ctx, c, cmd.DatacenterFlag, path)
+			if err != nil {
+				return nil, err
+			}
+			return []types.ManagedObjectReference{*ref}, nil
+		}
+		return cmd.ManagedObjects(ctx, []string{path})
+	}
+
+	roots, err := cmd.ManagedObjects(ctx, []string{path})
+	if err != nil {
+		return nil, err
+	}
+
+	vc, err := cmd.Client()
+	if err != nil {
+		return nil, err
+	}
+
+	m := view.NewManager(vc)
+	var refs []types.ManagedObjectReference
+
+	for _, root := range roots {
+		v, err := m.CreateContainerView(ctx, root, cmd.kind, true)
+		if err != nil {
+			return nil, err
+		}
+
+		objs, err := v.Find(ctx, cmd.kind, nil)
+		_ = v.Destroy(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		refs = append(refs, objs...)
+	}
+
+	return refs, nil
+}
+
 func (cmd *attach) Run(ctx context.Context, f *flag.FlagSet) error {
-	if f.NArg() != 2 {
+	if f.NArg() < 2 {
 		return flag.ErrHelp
 	}
 
 	tagID := f.Arg(0)
-	managedObj := f.Arg(1)
 
 	return cmd.WithRestClient(ctx, func(c *rest.Client) error {
-		ref, err := convertPath(ctx, c, cmd.DatacenterFlag, managedObj)
-		if err != nil {
-			return err
+		var refs []mo.Reference
+		seen := make(map[types.ManagedObjectReference]bool)
+
+		for _, path := range f.Args()[1:] {
+			objs, err := cmd.objects(ctx, c, path)
+			if err != nil {
+				return err
+			}
+			for _, ref := range objs {
+				if !seen[ref] {
+					seen[ref] = true
+					refs = append(refs, ref)
+				}
+			}
 		}
+
 		m := tags.NewManager(c)
 		tag, err := m.GetTagForCategory(ctx, tagID, cmd.cat)
 		if err != nil {
 			return err
 		}
-		return m.AttachTag(ctx, tag.ID, ref)
+
+		switch len(refs) {
+		case 0:
+			return nil
+		case 1:
+			return m.AttachTag(ctx, tag.ID, refs[0])
+		default:
+			return m.AttachTagToMultipleObjects(ctx, tag.ID, refs)
+		}
 	})
 }
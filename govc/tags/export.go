@@ -0,0 +1,89 @@
+/*
+Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tags
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+
+	"github.com/vmware/govmomi/govc/cli"
+	"github.com/vmware/govmomi/govc/flags"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vapi/tags"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+type export struct {
+	*flags.DatacenterFlag
+
+	objects bool
+}
+
+func init() {
+	cli.Register("tags.export", &export{})
+}
+
+func (cmd *export) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.DatacenterFlag, ctx = flags.NewDatacenterFlag(ctx)
+	cmd.DatacenterFlag.Register(ctx, f)
+
+	f.BoolVar(&cmd.objects, "a", false, "Include attached objects")
+}
+
+func (cmd *export) Description() string {
+	return `Export categories and tags as JSON.
+
+Attached objects are exported by inventory path, or by managed object reference
+for objects that are not in the inventory, such as content libraries.
+See also: tags.import
+
+Examples:
+  govc tags.export > tags.json
+  govc tags.export -a > tags.json`
+}
+
+func (cmd *export) Run(ctx context.Context, f *flag.FlagSet) error {
+	var path tags.ObjectPathFunc
+
+	if cmd.objects {
+		finder, err := cmd.Finder()
+		if err != nil {
+			return err
+		}
+
+		path = func(ctx context.Context, ref types.ManagedObjectReference) (string, error) {
+			e, err := finder.Element(ctx, ref)
+			if err != nil {
+				return ref.String(), nil
+			}
+			return e.Path, nil
+		}
+	}
+
+	return cmd.WithRestClient(ctx, func(c *rest.Client) error {
+		res, err := tags.NewManager(c).ExportTags(ctx, path)
+		if err != nil {
+			return err
+		}
+
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(res)
+	})
+}
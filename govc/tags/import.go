@@ -0,0 +1,94 @@
+/*
+Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tags
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+
+	"github.com/vmware/govmomi/govc/cli"
+	"github.com/vmware/govmomi/govc/flags"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vapi/tags"
+	"github.com/vmware/govmomi/vim25/mo"
+)
+
+type importx struct {
+	*flags.DatacenterFlag
+
+	objects bool
+}
+
+func init() {
+	cli.Register("tags.import", &importx{})
+}
+
+func (cmd *importx) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.DatacenterFlag, ctx = flags.NewDatacenterFlag(ctx)
+	cmd.DatacenterFlag.Register(ctx, f)
+
+	f.BoolVar(&cmd.objects, "a", false, "Attach tags to objects")
+}
+
+func (cmd *importx) Usage() string {
+	return "[FILE]"
+}
+
+func (cmd *importx) Description() string {
+	return `Import categories and tags from JSON FILE, as produced by tags.export.
+
+Categories and tags are matched by name, those that do not exist are created.
+If FILE is not specified or is "-", JSON is read from STDIN.
+
+Examples:
+  govc tags.import tags.json
+  govc tags.export -a | GOVC_URL=vc2.example.com govc tags.import -a`
+}
+
+func (cmd *importx) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() > 1 {
+		return flag.ErrHelp
+	}
+
+	in := os.Stdin
+	if name := f.Arg(0); name != "" && name != "-" {
+		var err error
+		in, err = os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer in.Close()
+	}
+
+	var t tags.Taxonomy
+	if err := json.NewDecoder(in).Decode(&t); err != nil {
+		return err
+	}
+
+	var find tags.ObjectFindFunc
+	if cmd.objects {
+		find = func(ctx context.Context, path string) (mo.Reference, error) {
+			return cmd.ManagedObject(ctx, path)
+		}
+	}
+
+	return cmd.WithRestClient(ctx, func(c *rest.Client) error {
+		return tags.NewManager(c).ImportTags(ctx, &t, find)
+	})
+}
@@ -66,6 +66,14 @@ load test_helper
   run govc about -k=false -tls-known-hosts <(echo "$thumbprint")
   assert_success
 
+  thumbprint=$(govc about.cert -k=true -thumbprint -sha256)
+
+  run govc about -k=false -tls-known-hosts <(echo "$thumbprint")
+  assert_success
+
+  run govc about -k=false -tls-known-hosts <(echo "$thumbprint" | awk '{print $1, "sha256:" $2}')
+  assert_success
+
   run govc about -k=false -tls-known-hosts <(echo "nope nope")
   assert_failure
 }
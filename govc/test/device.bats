@@ -238,6 +238,39 @@ load test_helper
   [ $result -eq 1 ]
 }
 
+@test "device.nvme" {
+  vcsim_env
+
+  vm=$(new_empty_vm)
+
+  result=$(govc device.ls -vm $vm | grep nvme- | wc -l)
+  [ $result -eq 0 ]
+
+  run govc vm.disk.create -vm $vm -controller nvme -name $vm/nvme-disk -size 1K
+  assert_failure # no controller
+
+  run govc device.nvme.add -vm $vm
+  assert_success
+  id=$output
+
+  result=$(govc device.ls -vm $vm | grep $id | wc -l)
+  [ $result -eq 1 ]
+
+  run govc vm.disk.create -vm $vm -controller nvme -name $vm/nvme-disk -size 1K
+  assert_success
+
+  result=$(govc device.ls -vm $vm | grep disk-${id#nvme-}- | wc -l)
+  [ $result -eq 1 ]
+
+  for i in $(seq 2 4) ; do
+    run govc device.nvme.add -vm $vm
+    assert_success
+  done
+
+  run govc device.nvme.add -vm $vm
+  assert_failure # 4 per vm max
+}
+
 @test "device.usb" {
   esx_env
 
@@ -116,6 +116,23 @@ load test_helper
   assert_equal "$(cat "$GOVC_IMAGES/$TTYLINUX_NAME.ovf")" "$(cat "$name/$TTYLINUX_NAME.ovf")"
   rm -r "$name"
 
+  run govc library.export -mf "/my-content/$TTYLINUX_NAME" "$name"
+  assert_success
+  assert_equal "$(cat "$GOVC_IMAGES/$TTYLINUX_NAME.ovf")" "$(cat "$name/$TTYLINUX_NAME.ovf")"
+  grep -q "SHA256($TTYLINUX_NAME.ovf)=" "$name/$TTYLINUX_NAME.mf"
+  rm -r "$name"
+
+  run govc library.export -ova "/my-content/$TTYLINUX_NAME" "$name.ova"
+  assert_success
+  run tar -tf "$name.ova"
+  assert_success
+  assert_line 0 "$TTYLINUX_NAME.ovf"
+  assert_matches "$TTYLINUX_NAME.mf"
+  rm "$name.ova"
+
+  run govc library.export -ova "/my-content/$TTYLINUX_NAME/*.ovf"
+  assert_failure
+
   run govc library.import /my-content "$GOVC_IMAGES/$TTYLINUX_NAME.ovf"
   assert_failure # already_exists
 
@@ -269,3 +286,37 @@ EOF
   n=$(govc library.info my-content | grep -c Name:)
   [ "$n" == 1 ]
 }
+
+@test "library.iso.mount" {
+  vcsim_env
+
+  run govc library.create my-content
+  assert_success
+
+  echo "iso" > "$BATS_TMPDIR/test.iso"
+  run govc library.import my-content "$BATS_TMPDIR/test.iso"
+  assert_success
+
+  vm=DC0_H0_VM0
+  cdrom=$(govc device.ls -vm $vm | grep cdrom- | awk '{print $1}')
+
+  run govc library.iso.mount -vm $vm /my-content/enoent
+  assert_failure
+
+  run govc library.iso.mount -vm $vm /my-content/test
+  assert_success
+  device="$output"
+
+  run govc device.info -vm $vm "$device"
+  assert_success
+  assert_matches "contentlib-.*test.iso"
+
+  run govc library.iso.unmount -vm $vm "$cdrom"
+  assert_failure # not a library ISO device
+
+  run govc library.iso.unmount -vm $vm "$device"
+  assert_success
+
+  run govc device.info -vm $vm "$device"
+  assert_failure
+}
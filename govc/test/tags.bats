@@ -198,3 +198,65 @@ load test_helper
   govc tags.attached.ls -r /DC1
   govc tags.attached.ls -r /DC1/host/DC1_C0
 }
+
+@test "tags.attach multiple" {
+  vcsim_env -cluster 2
+
+  govc tags.category.create -m backup
+  govc tags.create -c backup backup-daily
+  govc tags.create -c backup backup-weekly
+
+  run govc tags.attach backup-daily /DC0/host/DC0_C0 /DC0/host/DC0_C1
+  assert_success
+
+  run govc tags.attached.ls backup-daily
+  assert_success
+  [ ${#lines[@]} -eq 2 ]
+
+  run govc tags.attach backup-weekly '/DC0/vm/DC0_C0_RP0_VM*'
+  assert_success
+
+  run govc tags.attached.ls backup-weekly
+  assert_success
+  [ ${#lines[@]} -eq 2 ]
+
+  run govc tags.attach -type VirtualMachine backup-daily /DC0/vm
+  assert_success
+
+  run govc tags.attached.ls backup-daily
+  assert_success
+  [ ${#lines[@]} -eq 8 ] # 2 clusters + 6 VMs
+
+  run govc tags.attach backup-daily /DC0/vm/enoent
+  assert_failure
+}
+
+@test "tags.export" {
+  vcsim_env
+
+  govc tags.category.create -m -t VirtualMachine backup
+  govc tags.create -c backup backup-daily
+  govc tags.attach backup-daily /DC0/vm/DC0_H0_VM0
+
+  run govc tags.export -a
+  assert_success
+  export=$output
+  assert_matches DC0_H0_VM0 "$export"
+
+  run govc tags.category.rm -f backup
+  assert_success
+
+  run govc tags.import -a <<<"$export"
+  assert_success
+
+  run govc tags.import -a <<<"$export"
+  assert_success # existing categories and tags are not created again
+
+  run govc tags.attached.ls backup-daily
+  assert_success
+  [ ${#lines[@]} -eq 1 ]
+
+  run govc tags.export -a
+  assert_success
+  assert_equal "$export" "$output"
+}
@@ -683,6 +683,29 @@ load test_helper
   assert_success
 }
 
+@test "vm.instantclone" {
+  vcsim_env
+
+  vm=DC0_H0_VM0
+  clone=$(new_id)
+
+  run govc vm.instantclone -vm "$vm" -g hostname="$clone" "$clone"
+  assert_success
+
+  run govc object.collect -s "vm/$clone" runtime.powerState
+  assert_success poweredOn
+
+  run govc vm.info -e "$clone"
+  assert_success
+  assert_matches "guestinfo.hostname:.*$clone"
+
+  run govc vm.power -off "$vm"
+  assert_success
+
+  run govc vm.instantclone -vm "$vm" "$(new_id)"
+  assert_failure
+}
+
 @test "vm.clone change resources" {
   vcsim_env
 
@@ -357,38 +357,21 @@ func (cmd *clone) cloneVM(ctx context.Context) (*object.VirtualMachine, error) {
 	// clone to storage pod
 	datastoreref := types.ManagedObjectReference{}
 	if cmd.StoragePod != nil && cmd.Datastore == nil {
-		storagePod := cmd.StoragePod.Reference()
+		folder := object.NewFolder(cmd.Client, folderref)
+		storagePlacementSpec := object.NewStoragePlacementSpecClone(cmd.StoragePod, cmd.VirtualMachine, folder, cmd.name, *cloneSpec)
 
-		// Build pod selection spec from config spec
-		podSelectionSpec := types.StorageDrsPodSelectionSpec{
-			StoragePod: &storagePod,
-		}
-
-		// Build the placement spec
-		storagePlacementSpec := types.StoragePlacementSpec{
-			Folder:           &folderref,
-			Vm:               &vmref,
-			CloneName:        cmd.name,
-			CloneSpec:        cloneSpec,
-			PodSelectionSpec: podSelectionSpec,
-			Type:             string(types.StoragePlacementSpecPlacementTypeClone),
-		}
-
-		// Get the storage placement result
+		// Get the first storage placement recommendation
 		storageResourceManager := object.NewStorageResourceManager(cmd.Client)
-		result, err := storageResourceManager.RecommendDatastores(ctx, storagePlacementSpec)
+		recommendation, err := storageResourceManager.RecommendDatastore(ctx, storagePlacementSpec)
 		if err != nil {
 			return nil, err
 		}
 
-		// Get the recommendations
-		recommendations := result.Recommendations
-		if len(recommendations) == 0 {
-			return nil, fmt.Errorf("no datastore-cluster recommendations")
+		dst := object.StoragePlacementDestination(*recommendation)
+		if dst == nil {
+			return nil, object.ErrNoStoragePlacement
 		}
-
-		// Get the first recommendation
-		datastoreref = recommendations[0].Action[0].(*types.StoragePlacementAction).Destination
+		datastoreref = *dst
 	} else if cmd.StoragePod == nil && cmd.Datastore != nil {
 		datastoreref = cmd.Datastore.Reference()
 	} else if cmd.Cluster != nil {
@@ -529,66 +529,19 @@ func (cmd *create) addNetwork(devices object.VirtualDeviceList) (object.VirtualD
 }
 
 func (cmd *create) recommendDatastore(ctx context.Context, spec *types.VirtualMachineConfigSpec) (*object.Datastore, error) {
-	sp := cmd.StoragePod.Reference()
-
-	// Build pod selection spec from config spec
-	podSelectionSpec := types.StorageDrsPodSelectionSpec{
-		StoragePod: &sp,
-	}
-
-	// Keep list of disks that need to be placed
-	var disks []*types.VirtualDisk
-
-	// Collect disks eligible for placement
-	for _, deviceConfigSpec := range spec.DeviceChange {
-		s := deviceConfigSpec.GetVirtualDeviceConfigSpec()
-		if s.Operation != types.VirtualDeviceConfigSpecOperationAdd {
-			continue
-		}
-
-		if s.FileOperation != types.VirtualDeviceConfigSpecFileOperationCreate {
-			continue
-		}
-
-		d, ok := s.Device.(*types.VirtualDisk)
-		if !ok {
-			continue
-		}
-
-		podConfigForPlacement := types.VmPodConfigForPlacement{
-			StoragePod: sp,
-			Disk: []types.PodDiskLocator{
-				{
-					DiskId:          d.Key,
-					DiskBackingInfo: d.Backing,
-				},
-			},
-		}
-
-		podSelectionSpec.InitialVmConfig = append(podSelectionSpec.InitialVmConfig, podConfigForPlacement)
-		disks = append(disks, d)
-	}
-
-	sps := types.StoragePlacementSpec{
-		Type:             string(types.StoragePlacementSpecPlacementTypeCreate),
-		ResourcePool:     types.NewReference(cmd.ResourcePool.Reference()),
-		PodSelectionSpec: podSelectionSpec,
-		ConfigSpec:       spec,
-	}
+	sps := object.NewStoragePlacementSpecCreate(cmd.StoragePod, cmd.ResourcePool, nil, nil, *spec)
 
 	srm := object.NewStorageResourceManager(cmd.Client)
-	result, err := srm.RecommendDatastores(ctx, sps)
+	rec, err := srm.RecommendDatastore(ctx, sps)
 	if err != nil {
 		return nil, err
 	}
 
-	// Use result to pin disks to recommended datastores
-	recs := result.Recommendations
-	if len(recs) == 0 {
-		return nil, fmt.Errorf("no datastore-cluster recommendations")
+	dst := object.StoragePlacementDestination(*rec)
+	if dst == nil {
+		return nil, object.ErrNoStoragePlacement
 	}
-
-	ds := recs[0].Action[0].(*types.StoragePlacementAction).Destination
+	ds := *dst
 
 	var mds mo.Datastore
 	err = property.DefaultCollector(cmd.Client).RetrieveOne(ctx, ds, []string{"name"}, &mds)
@@ -600,9 +553,11 @@ func (cmd *create) recommendDatastore(ctx context.Context, spec *types.VirtualMa
 	datastore.InventoryPath = mds.Name
 
 	// Apply recommendation to eligible disks
-	for _, disk := range disks {
-		backing := disk.Backing.(*types.VirtualDiskFlatVer2BackingInfo)
-		backing.Datastore = &ds
+	for _, placement := range sps.PodSelectionSpec.InitialVmConfig {
+		for _, disk := range placement.Disk {
+			backing := disk.DiskBackingInfo.(*types.VirtualDiskFlatVer2BackingInfo)
+			backing.Datastore = &ds
+		}
 	}
 
 	return datastore, nil
@@ -0,0 +1,158 @@
+/*
+Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vm
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/vmware/govmomi/govc/cli"
+	"github.com/vmware/govmomi/govc/flags"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+type instantclone struct {
+	*flags.DatastoreFlag
+	*flags.ResourcePoolFlag
+	*flags.FolderFlag
+	*flags.VirtualMachineFlag
+
+	extraConfig extraConfig
+	guestInfo   extraConfig
+	uuid        string
+}
+
+func init() {
+	cli.Register("vm.instantclone", &instantclone{})
+}
+
+func (cmd *instantclone) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.DatastoreFlag, ctx = flags.NewDatastoreFlag(ctx)
+	cmd.DatastoreFlag.Register(ctx, f)
+
+	cmd.ResourcePoolFlag, ctx = flags.NewResourcePoolFlag(ctx)
+	cmd.ResourcePoolFlag.Register(ctx, f)
+
+	cmd.FolderFlag, ctx = flags.NewFolderFlag(ctx)
+	cmd.FolderFlag.Register(ctx, f)
+
+	cmd.VirtualMachineFlag, ctx = flags.NewVirtualMachineFlag(ctx)
+	cmd.VirtualMachineFlag.Register(ctx, f)
+
+	f.Var(&cmd.extraConfig, "e", "ExtraConfig. <key>=<value>")
+	f.Var(&cmd.guestInfo, "g", "GuestInfo. <key>=<value>, the key is prefixed with 'guestinfo.' if needed")
+	f.StringVar(&cmd.uuid, "uuid", "", "BIOS UUID of the clone")
+}
+
+func (cmd *instantclone) Process(ctx context.Context) error {
+	if err := cmd.DatastoreFlag.Process(ctx); err != nil {
+		return err
+	}
+	if err := cmd.ResourcePoolFlag.Process(ctx); err != nil {
+		return err
+	}
+	if err := cmd.FolderFlag.Process(ctx); err != nil {
+		return err
+	}
+	if err := cmd.VirtualMachineFlag.Process(ctx); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (cmd *instantclone) Usage() string {
+	return "NAME"
+}
+
+func (cmd *instantclone) Description() string {
+	return `Instant clone VM to NAME.
+
+The source VM must be powered on, the clone shares its memory and disk state and is powered on.
+Guest customization of the clone can be done via guestinfo variables, read by a script in the guest
+using: vmtoolsd --cmd "info-get guestinfo.<key>"
+
+Examples:
+  govc vm.instantclone -vm source-vm new-vm
+  govc vm.instantclone -vm source-vm -g hostname=new-vm -g ipaddress=10.0.0.42 new-vm
+  govc vm.instantclone -vm source-vm -ds datastore1 -pool pool1 -folder folder1 new-vm`
+}
+
+func (cmd *instantclone) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() != 1 {
+		return flag.ErrHelp
+	}
+	name := f.Arg(0)
+
+	vm, err := cmd.VirtualMachine()
+	if err != nil {
+		return err
+	}
+	if vm == nil {
+		return flag.ErrHelp
+	}
+
+	folder, err := cmd.FolderOrDefault("vm")
+	if err != nil {
+		return err
+	}
+	folderref := folder.Reference()
+
+	location := types.VirtualMachineRelocateSpec{
+		Folder: &folderref,
+	}
+
+	ds, err := cmd.DatastoreIfSpecified()
+	if err != nil {
+		return err
+	}
+	if ds != nil {
+		ref := ds.Reference()
+		location.Datastore = &ref
+	}
+
+	pool, err := cmd.ResourcePoolIfSpecified()
+	if err != nil {
+		return err
+	}
+	if pool != nil {
+		ref := pool.Reference()
+		location.Pool = &ref
+	}
+
+	info := make(object.GuestInfo)
+	for _, opt := range cmd.guestInfo {
+		o := opt.GetOptionValue()
+		info[o.Key] = o.Value.(string)
+	}
+
+	spec := object.NewInstantCloneSpec(name, location, info)
+	spec.Config = append(spec.Config, cmd.extraConfig...)
+	spec.BiosUuid = cmd.uuid
+
+	task, err := vm.InstantClone(ctx, spec)
+	if err != nil {
+		return err
+	}
+
+	logger := cmd.DatastoreFlag.ProgressLogger(fmt.Sprintf("Instant cloning %s to %s...", vm.InventoryPath, name))
+	defer logger.Wait()
+
+	_, err = task.WaitForResult(ctx, logger)
+	return err
+}
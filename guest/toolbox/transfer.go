@@ -0,0 +1,383 @@
+/*
+Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package toolbox
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// TransferOptions configures the directory transfers of UploadDir and DownloadDir.
+type TransferOptions struct {
+	// Include patterns, if any, limit the transfer to files matching at least one pattern.
+	Include []string
+	// Exclude patterns skip matching files and directories, including the contents of a matching directory.
+	Exclude []string
+	// Preserve file permissions and modification times, where supported by the guest.
+	Preserve bool
+	// Force overwrites existing files at the destination.
+	Force bool
+	// Parallel is the number of concurrent file transfers, defaults to 1.
+	Parallel int
+}
+
+// match reports whether the given slash separated path, relative to the transfer root, should be transferred.
+// Patterns use path.Match syntax and are matched against both the relative path and its base name.
+func (o *TransferOptions) match(rel string, dir bool) bool {
+	matches := func(patterns []string) bool {
+		for _, pattern := range patterns {
+			if ok, _ := path.Match(pattern, rel); ok {
+				return true
+			}
+			if ok, _ := path.Match(pattern, path.Base(rel)); ok {
+				return true
+			}
+		}
+		return false
+	}
+
+	if matches(o.Exclude) {
+		return false
+	}
+
+	if dir || len(o.Include) == 0 {
+		return true
+	}
+
+	return matches(o.Include)
+}
+
+func (o *TransferOptions) validate() error {
+	for _, patterns := range [][]string{o.Include, o.Exclude} {
+		for _, pattern := range patterns {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// guestSeparator returns the path separator used by the given guest path.
+func guestSeparator(name string) string {
+	if strings.Contains(name, `\`) && !strings.Contains(name, "/") {
+		return `\`
+	}
+	return "/"
+}
+
+// guestJoin joins a guest directory and a slash separated relative path, using the directory's separator.
+func guestJoin(dir string, rel string) string {
+	sep := guestSeparator(dir)
+	return strings.TrimRight(dir, sep) + sep + strings.Replace(rel, "/", sep, -1)
+}
+
+// transfer runs the given jobs using n goroutines, returning the first error encountered.
+// Once a job fails, the context passed to the remaining jobs is canceled and no more jobs are started.
+func transfer(ctx context.Context, n int, jobs []func(context.Context) error) error {
+	if n < 1 {
+		n = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg   sync.WaitGroup
+		once sync.Once
+		err  error
+	)
+
+	ch := make(chan func(context.Context) error)
+
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range ch {
+				if jerr := job(ctx); jerr != nil {
+					once.Do(func() {
+						err = jerr
+						cancel()
+					})
+				}
+			}
+		}()
+	}
+
+send:
+	for _, job := range jobs {
+		select {
+		case ch <- job:
+		case <-ctx.Done():
+			break send
+		}
+	}
+
+	close(ch)
+	wg.Wait()
+
+	if err == nil {
+		err = ctx.Err() // parent context canceled
+	}
+
+	return err
+}
+
+func isFileAlreadyExists(err error) bool {
+	if soap.IsSoapFault(err) {
+		_, ok := soap.ToSoapFault(err).VimFault().(types.FileAlreadyExists)
+		return ok
+	}
+	return false
+}
+
+// UploadDir recursively transfers the local directory src to the guest directory dst,
+// creating dst and any subdirectories as needed. Symbolic links and other non-regular files are skipped.
+func (c *Client) UploadDir(ctx context.Context, src string, dst string, opts TransferOptions) error {
+	if err := opts.validate(); err != nil {
+		return err
+	}
+
+	windows := guestSeparator(dst) == `\`
+	var jobs []func(context.Context) error
+
+	err := filepath.Walk(src, func(name string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, name)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		target := dst
+		if rel != "." {
+			if !opts.match(rel, info.IsDir()) {
+				if info.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			target = guestJoin(dst, rel)
+		}
+
+		if info.IsDir() {
+			err = c.FileManager.MakeDirectory(ctx, c.Authentication, target, true)
+			if err != nil && !isFileAlreadyExists(err) {
+				return err
+			}
+			return nil
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		var attr types.BaseGuestFileAttributes
+		if windows {
+			attr = new(types.GuestWindowsFileAttributes)
+		} else {
+			attr = new(types.GuestPosixFileAttributes)
+		}
+
+		if opts.Preserve {
+			mtime := info.ModTime()
+			attr.GetGuestFileAttributes().ModificationTime = &mtime
+			if a, ok := attr.(*types.GuestPosixFileAttributes); ok {
+				a.Permissions = int64(info.Mode().Perm())
+			}
+		}
+
+		size := info.Size()
+
+		jobs = append(jobs, func(ctx context.Context) error {
+			f, err := os.Open(name)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+
+			p := soap.DefaultUpload
+			p.ContentLength = size
+
+			return c.Upload(ctx, f, target, p, attr, opts.Force)
+		})
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return transfer(ctx, opts.Parallel, jobs)
+}
+
+// listDir returns all entries of the given guest directory, following ListFiles pagination.
+func (c *Client) listDir(ctx context.Context, dir string) ([]types.GuestFileInfo, error) {
+	var files []types.GuestFileInfo
+
+	for {
+		info, err := c.FileManager.ListFiles(ctx, c.Authentication, dir, int32(len(files)), 0, "")
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(files, info.Files...)
+
+		if info.Remaining == 0 || len(info.Files) == 0 {
+			return files, nil
+		}
+	}
+}
+
+// DownloadDir recursively transfers the guest directory src to the local directory dst,
+// creating dst and any subdirectories as needed. Symbolic links are skipped.
+func (c *Client) DownloadDir(ctx context.Context, src string, dst string, opts TransferOptions) error {
+	if err := opts.validate(); err != nil {
+		return err
+	}
+
+	var jobs []func(context.Context) error
+
+	var walk func(string, string) error
+	walk = func(dir string, rel string) error {
+		local := filepath.Join(dst, filepath.FromSlash(rel))
+		if err := os.MkdirAll(local, 0755); err != nil {
+			return err
+		}
+
+		files, err := c.listDir(ctx, dir)
+		if err != nil {
+			return err
+		}
+
+		for _, file := range files {
+			if file.Path == "." || file.Path == ".." {
+				continue
+			}
+
+			target, err := localPath(dst, local, file.Path)
+			if err != nil {
+				return err
+			}
+
+			name := path.Join(rel, file.Path)
+			isDir := file.Type == string(types.GuestFileTypeDirectory)
+
+			if !opts.match(name, isDir) {
+				continue
+			}
+
+			switch types.GuestFileType(file.Type) {
+			case types.GuestFileTypeDirectory:
+				if err = walk(guestJoin(dir, file.Path), name); err != nil {
+					return err
+				}
+			case types.GuestFileTypeFile:
+				jobs = append(jobs, c.downloadFile(guestJoin(dir, file.Path), target, file.Attributes, opts))
+			}
+		}
+
+		return nil
+	}
+
+	if err := walk(src, ""); err != nil {
+		return err
+	}
+
+	return transfer(ctx, opts.Parallel, jobs)
+}
+
+// localPath returns the path of the guest file name within the local directory dir, a descendant of dst.
+// The name is provided by the guest, so any name that is not a single path element within dst is rejected.
+func localPath(dst string, dir string, name string) (string, error) {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return "", fmt.Errorf("invalid guest file name %q", name)
+	}
+
+	p := filepath.Join(dir, name)
+
+	rel, err := filepath.Rel(dst, p)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("guest file name %q resolves outside of %s", name, dst)
+	}
+
+	return p, nil
+}
+
+func (c *Client) downloadFile(src string, dst string, attr types.BaseGuestFileAttributes, opts TransferOptions) func(context.Context) error {
+	return func(ctx context.Context) error {
+		mode := os.FileMode(0644)
+		if a, ok := attr.(*types.GuestPosixFileAttributes); ok && opts.Preserve && a.Permissions != 0 {
+			mode = os.FileMode(a.Permissions).Perm()
+		}
+
+		flag := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+		if !opts.Force {
+			flag |= os.O_EXCL
+		}
+
+		f, err := os.OpenFile(dst, flag, mode)
+		if err != nil {
+			return err
+		}
+
+		r, _, err := c.Download(ctx, src)
+		if err == nil {
+			_, err = f.ReadFrom(r)
+			_ = r.Close()
+		}
+
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return err
+		}
+
+		if !opts.Preserve || attr == nil {
+			return nil
+		}
+
+		if a, ok := attr.(*types.GuestPosixFileAttributes); ok && a.Permissions != 0 {
+			if err = os.Chmod(dst, mode); err != nil { // OpenFile mode is subject to umask
+				return err
+			}
+		}
+
+		if mtime := attr.GetGuestFileAttributes().ModificationTime; mtime != nil {
+			atime := *mtime
+			if a := attr.GetGuestFileAttributes().AccessTime; a != nil {
+				atime = *a
+			}
+			return os.Chtimes(dst, atime, *mtime)
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,138 @@
+/*
+Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package toolbox
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+)
+
+func TestTransferOptionsMatch(t *testing.T) {
+	opts := TransferOptions{
+		Include: []string{"*.go", "Makefile"},
+		Exclude: []string{"vendor", "*_test.go", "docs/*.md"},
+	}
+
+	tests := []struct {
+		name   string
+		dir    bool
+		expect bool
+	}{
+		{"main.go", false, true},
+		{"main_test.go", false, false},
+		{"pkg/util.go", false, true},
+		{"pkg/util_test.go", false, false},
+		{"Makefile", false, true},
+		{"README.md", false, false},
+		{"vendor", true, false},
+		{"pkg/vendor", true, false},
+		{"docs", true, true},
+		{"docs/index.md", false, false},
+	}
+
+	for _, test := range tests {
+		if opts.match(test.name, test.dir) != test.expect {
+			t.Errorf("%s: expected %t", test.name, test.expect)
+		}
+	}
+
+	opts = TransferOptions{Exclude: []string{"["}}
+	if opts.validate() == nil {
+		t.Error("expected bad pattern error")
+	}
+}
+
+func TestGuestJoin(t *testing.T) {
+	tests := []struct {
+		dir, rel, expect string
+	}{
+		{"/tmp", "a/b", "/tmp/a/b"},
+		{"/tmp/", "a", "/tmp/a"},
+		{"/", "a", "/a"},
+		{`C:\Temp`, "a/b", `C:\Temp\a\b`},
+		{`C:\`, "a", `C:\a`},
+	}
+
+	for _, test := range tests {
+		name := guestJoin(test.dir, test.rel)
+		if name != test.expect {
+			t.Errorf("%s != %s", name, test.expect)
+		}
+	}
+}
+
+func TestTransfer(t *testing.T) {
+	ctx := context.Background()
+	var n int32
+
+	jobs := make([]func(context.Context) error, 10)
+	for i := range jobs {
+		jobs[i] = func(context.Context) error {
+			atomic.AddInt32(&n, 1)
+			return nil
+		}
+	}
+
+	if err := transfer(ctx, 3, jobs); err != nil {
+		t.Fatal(err)
+	}
+	if n != 10 {
+		t.Errorf("n=%d", n)
+	}
+
+	fail := errors.New("fail")
+	jobs[5] = func(context.Context) error { return fail }
+
+	if err := transfer(ctx, 1, jobs); err != fail {
+		t.Errorf("err=%v", err)
+	}
+}
+
+func TestLocalPath(t *testing.T) {
+	dst := filepath.Join("tmp", "dst")
+	dir := filepath.Join(dst, "a")
+
+	tests := []struct {
+		name string
+		ok   bool
+	}{
+		{"file.txt", true},
+		{"..file", true},
+		{"", false},
+		{".", false},
+		{"..", false},
+		{"../../etc/passwd", false},
+		{"b/c", false},
+		{`..\..\evil`, false},
+	}
+
+	for _, test := range tests {
+		p, err := localPath(dst, dir, test.name)
+		if test.ok {
+			if err != nil {
+				t.Errorf("%q: %s", test.name, err)
+			} else if p != filepath.Join(dir, test.name) {
+				t.Errorf("%q: path=%s", test.name, p)
+			}
+		} else if err == nil {
+			t.Errorf("%q: expected error, path=%s", test.name, p)
+		}
+	}
+}
@@ -520,6 +520,7 @@ func (l Lister) ListVirtualApp(ctx context.Context) ([]Element, error) {
 
 	childTypes := []string{
 		"ResourcePool",
+		"VirtualApp",
 		"VirtualMachine",
 	}
 
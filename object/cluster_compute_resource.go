@@ -101,3 +101,13 @@ func (c ClusterComputeResource) PlaceVm(ctx context.Context, spec types.Placemen
 
 	return &res.Returnval, nil
 }
+
+func (c ClusterComputeResource) ApplyRecommendation(ctx context.Context, key string) error {
+	req := types.ApplyRecommendation{
+		This: c.Reference(),
+		Key:  key,
+	}
+
+	_, err := methods.ApplyRecommendation(ctx, c.c, &req)
+	return err
+}
@@ -0,0 +1,279 @@
+/*
+Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"context"
+	"errors"
+
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/methods"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// CryptoManagerKmip wraps the vCenter CryptoManagerKmip, which manages the KMIP key management servers (KMS)
+// used for VM encryption. A KMS cluster is identified by its KeyProviderId.
+type CryptoManagerKmip struct {
+	Common
+}
+
+// GetCryptoManagerKmip wraps NewCryptoManagerKmip, returning ErrNotSupported
+// when the client is not connected to a vCenter instance.
+func GetCryptoManagerKmip(c *vim25.Client) (*CryptoManagerKmip, error) {
+	if c.ServiceContent.CryptoManager == nil || c.ServiceContent.CryptoManager.Type != "CryptoManagerKmip" {
+		return nil, ErrNotSupported
+	}
+	return NewCryptoManagerKmip(c), nil
+}
+
+func NewCryptoManagerKmip(c *vim25.Client) *CryptoManagerKmip {
+	m := CryptoManagerKmip{
+		Common: NewCommon(c, *c.ServiceContent.CryptoManager),
+	}
+
+	return &m
+}
+
+// RegisterKmipServer adds a KMS to the cluster given by server.ClusterId, creating the cluster if needed.
+func (m CryptoManagerKmip) RegisterKmipServer(ctx context.Context, server types.KmipServerSpec) error {
+	req := types.RegisterKmipServer{
+		This:   m.Reference(),
+		Server: server,
+	}
+
+	_, err := methods.RegisterKmipServer(ctx, m.c, &req)
+	return err
+}
+
+// UpdateKmipServer updates the configuration of a registered KMS.
+func (m CryptoManagerKmip) UpdateKmipServer(ctx context.Context, server types.KmipServerSpec) error {
+	req := types.UpdateKmipServer{
+		This:   m.Reference(),
+		Server: server,
+	}
+
+	_, err := methods.UpdateKmipServer(ctx, m.c, &req)
+	return err
+}
+
+// RemoveKmipServer removes the named KMS from the given cluster.
+func (m CryptoManagerKmip) RemoveKmipServer(ctx context.Context, clusterID string, serverName string) error {
+	req := types.RemoveKmipServer{
+		This:       m.Reference(),
+		ClusterId:  types.KeyProviderId{Id: clusterID},
+		ServerName: serverName,
+	}
+
+	_, err := methods.RemoveKmipServer(ctx, m.c, &req)
+	return err
+}
+
+// ListKmipServers returns all registered KMS clusters.
+func (m CryptoManagerKmip) ListKmipServers(ctx context.Context) ([]types.KmipClusterInfo, error) {
+	req := types.ListKmipServers{
+		This: m.Reference(),
+	}
+
+	res, err := methods.ListKmipServers(ctx, m.c, &req)
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Returnval, nil
+}
+
+// MarkDefault marks the given cluster as the default KMS cluster, used when no key provider is specified.
+func (m CryptoManagerKmip) MarkDefault(ctx context.Context, clusterID string) error {
+	req := types.MarkDefault{
+		This:      m.Reference(),
+		ClusterId: types.KeyProviderId{Id: clusterID},
+	}
+
+	_, err := methods.MarkDefault(ctx, m.c, &req)
+	return err
+}
+
+// DefaultClusterID returns the ID of the default KMS cluster, or an empty string if there is no default.
+func (m CryptoManagerKmip) DefaultClusterID(ctx context.Context) (string, error) {
+	clusters, err := m.ListKmipServers(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	for _, cluster := range clusters {
+		if cluster.UseAsDefault {
+			return cluster.ClusterId.Id, nil
+		}
+	}
+
+	return "", nil
+}
+
+// RetrieveKmipServerCert retrieves the certificate presented by the given KMS, such that it can be verified
+// by the caller before it is trusted via UploadKmipServerCert.
+func (m CryptoManagerKmip) RetrieveKmipServerCert(ctx context.Context, clusterID string, server types.KmipServerInfo) (*types.CryptoManagerKmipServerCertInfo, error) {
+	req := types.RetrieveKmipServerCert{
+		This:        m.Reference(),
+		KeyProvider: types.KeyProviderId{Id: clusterID},
+		Server:      server,
+	}
+
+	res, err := methods.RetrieveKmipServerCert(ctx, m.c, &req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &res.Returnval, nil
+}
+
+// UploadKmipServerCert establishes vCenter's trust in the KMS cluster, given the PEM encoded certificate of the KMS.
+func (m CryptoManagerKmip) UploadKmipServerCert(ctx context.Context, clusterID string, certificate string) error {
+	req := types.UploadKmipServerCert{
+		This:        m.Reference(),
+		Cluster:     types.KeyProviderId{Id: clusterID},
+		Certificate: certificate,
+	}
+
+	_, err := methods.UploadKmipServerCert(ctx, m.c, &req)
+	return err
+}
+
+// UpdateSelfSignedClientCert sets the vCenter client certificate used with the given KMS cluster
+// to the given PEM encoded self-signed certificate.
+func (m CryptoManagerKmip) UpdateSelfSignedClientCert(ctx context.Context, clusterID string, certificate string) error {
+	req := types.UpdateSelfSignedClientCert{
+		This:        m.Reference(),
+		Cluster:     types.KeyProviderId{Id: clusterID},
+		Certificate: certificate,
+	}
+
+	_, err := methods.UpdateSelfSignedClientCert(ctx, m.c, &req)
+	return err
+}
+
+// RetrieveSelfSignedClientCert returns the vCenter self-signed client certificate used with the given KMS cluster,
+// which must be trusted by the KMS.
+func (m CryptoManagerKmip) RetrieveSelfSignedClientCert(ctx context.Context, clusterID string) (string, error) {
+	req := types.RetrieveSelfSignedClientCert{
+		This:    m.Reference(),
+		Cluster: types.KeyProviderId{Id: clusterID},
+	}
+
+	res, err := methods.RetrieveSelfSignedClientCert(ctx, m.c, &req)
+	if err != nil {
+		return "", err
+	}
+
+	return res.Returnval, nil
+}
+
+// GenerateClientCsr generates a certificate signing request for the vCenter client certificate used with
+// the given KMS cluster, to be signed by the KMS.
+func (m CryptoManagerKmip) GenerateClientCsr(ctx context.Context, clusterID string) (string, error) {
+	req := types.GenerateClientCsr{
+		This:    m.Reference(),
+		Cluster: types.KeyProviderId{Id: clusterID},
+	}
+
+	res, err := methods.GenerateClientCsr(ctx, m.c, &req)
+	if err != nil {
+		return "", err
+	}
+
+	return res.Returnval, nil
+}
+
+// UploadClientCert sets the vCenter client certificate and private key used with the given KMS cluster.
+func (m CryptoManagerKmip) UploadClientCert(ctx context.Context, clusterID string, certificate string, privateKey string) error {
+	req := types.UploadClientCert{
+		This:        m.Reference(),
+		Cluster:     types.KeyProviderId{Id: clusterID},
+		Certificate: certificate,
+		PrivateKey:  privateKey,
+	}
+
+	_, err := methods.UploadClientCert(ctx, m.c, &req)
+	return err
+}
+
+// RetrieveClientCert returns the vCenter client certificate used with the given KMS cluster.
+func (m CryptoManagerKmip) RetrieveClientCert(ctx context.Context, clusterID string) (string, error) {
+	req := types.RetrieveClientCert{
+		This:    m.Reference(),
+		Cluster: types.KeyProviderId{Id: clusterID},
+	}
+
+	res, err := methods.RetrieveClientCert(ctx, m.c, &req)
+	if err != nil {
+		return "", err
+	}
+
+	return res.Returnval, nil
+}
+
+// GenerateKey generates a new key using the given KMS cluster, or the default cluster if clusterID is empty.
+func (m CryptoManagerKmip) GenerateKey(ctx context.Context, clusterID string) (*types.CryptoKeyId, error) {
+	req := types.GenerateKey{
+		This: m.Reference(),
+	}
+
+	if clusterID != "" {
+		req.KeyProvider = &types.KeyProviderId{Id: clusterID}
+	}
+
+	res, err := methods.GenerateKey(ctx, m.c, &req)
+	if err != nil {
+		return nil, err
+	}
+
+	if !res.Returnval.Success {
+		return nil, errors.New(res.Returnval.Reason)
+	}
+
+	return &res.Returnval.KeyId, nil
+}
+
+// ListKeys returns the keys known to vCenter.
+func (m CryptoManagerKmip) ListKeys(ctx context.Context) ([]types.CryptoKeyId, error) {
+	req := types.ListKeys{
+		This: m.Reference(),
+	}
+
+	res, err := methods.ListKeys(ctx, m.c, &req)
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Returnval, nil
+}
+
+// RemoveKeys removes the given keys, keys in use are only removed if force is true.
+// The result of each removal is returned in the CryptoKeyResult list.
+func (m CryptoManagerKmip) RemoveKeys(ctx context.Context, keys []types.CryptoKeyId, force bool) ([]types.CryptoKeyResult, error) {
+	req := types.RemoveKeys{
+		This:  m.Reference(),
+		Keys:  keys,
+		Force: force,
+	}
+
+	res, err := methods.RemoveKeys(ctx, m.c, &req)
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Returnval, nil
+}
@@ -0,0 +1,70 @@
+/*
+Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// NewCryptoSpecEncrypt returns a CryptoSpec to encrypt a VM or disk with the given key.
+func NewCryptoSpecEncrypt(key types.CryptoKeyId) types.BaseCryptoSpec {
+	return &types.CryptoSpecEncrypt{CryptoKeyId: key}
+}
+
+// NewCryptoSpecDecrypt returns a CryptoSpec to decrypt an encrypted VM or disk.
+func NewCryptoSpecDecrypt() types.BaseCryptoSpec {
+	return new(types.CryptoSpecDecrypt)
+}
+
+// NewCryptoSpecRekey returns a CryptoSpec to re-encrypt an encrypted VM or disk with the given key.
+// A shallow rekey only re-encrypts the data encryption keys with the new key, where a deep rekey
+// also re-encrypts the data itself.
+func NewCryptoSpecRekey(key types.CryptoKeyId, deep bool) types.BaseCryptoSpec {
+	if deep {
+		return &types.CryptoSpecDeepRecrypt{NewKeyId: key}
+	}
+	return &types.CryptoSpecShallowRecrypt{NewKeyId: key}
+}
+
+// NewCryptoConfigSpec returns a VirtualMachineConfigSpec to apply the given CryptoSpec to the VM home files
+// and each of the disks in devices, for use with VirtualMachine.Reconfigure. The profile is applied along with
+// the CryptoSpec, as vCenter requires an encryption storage policy when encrypting and a policy without
+// encryption when decrypting.
+func NewCryptoConfigSpec(devices VirtualDeviceList, crypto types.BaseCryptoSpec, profile []types.BaseVirtualMachineProfileSpec) types.VirtualMachineConfigSpec {
+	spec := types.VirtualMachineConfigSpec{
+		Crypto:    crypto,
+		VmProfile: profile,
+	}
+
+	for _, disk := range devices.SelectByType((*types.VirtualDisk)(nil)) {
+		spec.DeviceChange = append(spec.DeviceChange, NewCryptoDeviceConfigSpec(disk, crypto, profile))
+	}
+
+	return spec
+}
+
+// NewCryptoDeviceConfigSpec returns a VirtualDeviceConfigSpec to apply the given CryptoSpec to a single disk.
+func NewCryptoDeviceConfigSpec(disk types.BaseVirtualDevice, crypto types.BaseCryptoSpec, profile []types.BaseVirtualMachineProfileSpec) *types.VirtualDeviceConfigSpec {
+	return &types.VirtualDeviceConfigSpec{
+		Operation: types.VirtualDeviceConfigSpecOperationEdit,
+		Device:    disk,
+		Profile:   profile,
+		Backing: &types.VirtualDeviceConfigSpecBackingSpec{
+			Crypto: crypto,
+		},
+	}
+}
@@ -0,0 +1,66 @@
+/*
+Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"testing"
+
+	"github.com/vmware/govmomi/simulator/esx"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+func TestCryptoConfigSpec(t *testing.T) {
+	key := types.CryptoKeyId{KeyId: "key1", ProviderId: &types.KeyProviderId{Id: "kms1"}}
+	profile := []types.BaseVirtualMachineProfileSpec{&types.VirtualMachineDefinedProfileSpec{ProfileId: "encryption"}}
+
+	devices := VirtualDeviceList(esx.VirtualDevice)
+	disk := devices.CreateDisk(devices.PickController((*types.VirtualIDEController)(nil)), types.ManagedObjectReference{}, "disk1")
+	devices = append(devices, disk)
+
+	tests := []struct {
+		crypto types.BaseCryptoSpec
+		expect types.BaseCryptoSpec
+	}{
+		{NewCryptoSpecEncrypt(key), &types.CryptoSpecEncrypt{CryptoKeyId: key}},
+		{NewCryptoSpecDecrypt(), &types.CryptoSpecDecrypt{}},
+		{NewCryptoSpecRekey(key, false), &types.CryptoSpecShallowRecrypt{NewKeyId: key}},
+		{NewCryptoSpecRekey(key, true), &types.CryptoSpecDeepRecrypt{NewKeyId: key}},
+	}
+
+	for _, test := range tests {
+		if test.crypto.GetCryptoSpec() == nil {
+			t.Fatal("nil spec")
+		}
+
+		spec := NewCryptoConfigSpec(devices, test.crypto, profile)
+		if spec.Crypto != test.crypto || len(spec.VmProfile) != 1 {
+			t.Errorf("spec=%#v", spec)
+		}
+
+		if len(spec.DeviceChange) != 1 {
+			t.Fatalf("changes=%d", len(spec.DeviceChange))
+		}
+
+		change := spec.DeviceChange[0].GetVirtualDeviceConfigSpec()
+		if change.Device != disk || change.Operation != types.VirtualDeviceConfigSpecOperationEdit {
+			t.Errorf("change=%#v", change)
+		}
+		if change.Backing.Crypto != test.crypto {
+			t.Errorf("crypto=%#v", change.Backing.Crypto)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+/*
+Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// DVPortCriteria builds a DistributedVirtualSwitchPortCriteria for use with DistributedVirtualSwitch.FetchDVPorts.
+// Criteria that are not set do not filter ports.
+type DVPortCriteria struct {
+	criteria types.DistributedVirtualSwitchPortCriteria
+}
+
+// NewDVPortCriteria returns a DVPortCriteria that matches all ports of a switch.
+func NewDVPortCriteria() *DVPortCriteria {
+	return new(DVPortCriteria)
+}
+
+// Connected filters ports by connected (true) or disconnected (false) status.
+func (c *DVPortCriteria) Connected(connected bool) *DVPortCriteria {
+	c.criteria.Connected = types.NewBool(connected)
+	return c
+}
+
+// Active filters ports by active (true) or inactive (false) status.
+func (c *DVPortCriteria) Active(active bool) *DVPortCriteria {
+	c.criteria.Active = types.NewBool(active)
+	return c
+}
+
+// Uplink filters for uplink (true) or non-uplink (false) ports.
+func (c *DVPortCriteria) Uplink(uplink bool) *DVPortCriteria {
+	c.criteria.UplinkPort = types.NewBool(uplink)
+	return c
+}
+
+// Inside filters for ports inside the given portgroup keys, or inside any portgroup if no keys are given.
+func (c *DVPortCriteria) Inside(keys ...string) *DVPortCriteria {
+	c.criteria.Inside = types.NewBool(true)
+	c.criteria.PortgroupKey = keys
+	return c
+}
+
+// Outside filters for ports outside the given portgroup keys, or standalone ports if no keys are given.
+func (c *DVPortCriteria) Outside(keys ...string) *DVPortCriteria {
+	c.criteria.Inside = types.NewBool(false)
+	c.criteria.PortgroupKey = keys
+	return c
+}
+
+// PortKey filters for ports with the given keys.
+func (c *DVPortCriteria) PortKey(keys ...string) *DVPortCriteria {
+	c.criteria.PortKey = append(c.criteria.PortKey, keys...)
+	return c
+}
+
+// Host filters for ports on the given hosts.
+func (c *DVPortCriteria) Host(hosts ...types.ManagedObjectReference) *DVPortCriteria {
+	c.criteria.Host = append(c.criteria.Host, hosts...)
+	return c
+}
+
+// Scope filters for ports scoped to the given entity.
+func (c *DVPortCriteria) Scope(ref types.ManagedObjectReference) *DVPortCriteria {
+	c.criteria.Scope = &ref
+	return c
+}
+
+// Criteria returns the DistributedVirtualSwitchPortCriteria built so far.
+func (c *DVPortCriteria) Criteria() *types.DistributedVirtualSwitchPortCriteria {
+	criteria := c.criteria
+	return &criteria
+}
+
+// NewDVPortConfigSpec returns a spec to edit the given port's Setting.
+// The port's ConfigVersion is included, such that the reconfigure fails if the port was changed since it was fetched.
+func NewDVPortConfigSpec(port types.DistributedVirtualPort, setting types.BaseDVPortSetting) types.DVPortConfigSpec {
+	return types.DVPortConfigSpec{
+		Operation:     string(types.ConfigSpecOperationEdit),
+		Key:           port.Key,
+		ConfigVersion: port.Config.ConfigVersion,
+		Setting:       setting,
+	}
+}
@@ -18,6 +18,8 @@ package object
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	"github.com/vmware/govmomi/vim25"
 	"github.com/vmware/govmomi/vim25/methods"
@@ -78,3 +80,89 @@ func (s DistributedVirtualSwitch) FetchDVPorts(ctx context.Context, criteria *ty
 	}
 	return res.Returnval, nil
 }
+
+// FetchDVPortsByVirtualMachine returns the ports connected to the given virtual machine.
+func (s DistributedVirtualSwitch) FetchDVPortsByVirtualMachine(ctx context.Context, vm types.ManagedObjectReference) ([]types.DistributedVirtualPort, error) {
+	ports, err := s.FetchDVPorts(ctx, NewDVPortCriteria().Connected(true).Criteria())
+	if err != nil {
+		return nil, err
+	}
+
+	var res []types.DistributedVirtualPort
+
+	for _, port := range ports {
+		if port.Connectee == nil || port.Connectee.ConnectedEntity == nil {
+			continue
+		}
+
+		if *port.Connectee.ConnectedEntity == vm {
+			res = append(res, port)
+		}
+	}
+
+	return res, nil
+}
+
+// FetchDVPortByMAC returns the connected port with the given MAC address, as reported by the port's runtime info.
+func (s DistributedVirtualSwitch) FetchDVPortByMAC(ctx context.Context, mac string) (*types.DistributedVirtualPort, error) {
+	ports, err := s.FetchDVPorts(ctx, NewDVPortCriteria().Connected(true).Criteria())
+	if err != nil {
+		return nil, err
+	}
+
+	for i, port := range ports {
+		if port.State == nil || port.State.RuntimeInfo == nil {
+			continue
+		}
+
+		if strings.EqualFold(port.State.RuntimeInfo.MacAddress, mac) {
+			return &ports[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("port with MAC address %s not found", mac)
+}
+
+// DVPortStatistics returns the statistics of the ports with the given keys, or of all ports if no keys are given.
+func (s DistributedVirtualSwitch) DVPortStatistics(ctx context.Context, keys ...string) (map[string]types.DistributedVirtualSwitchPortStatistics, error) {
+	ports, err := s.FetchDVPorts(ctx, NewDVPortCriteria().PortKey(keys...).Criteria())
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make(map[string]types.DistributedVirtualSwitchPortStatistics, len(ports))
+
+	for _, port := range ports {
+		if port.State != nil {
+			stats[port.Key] = port.State.Stats
+		}
+	}
+
+	return stats, nil
+}
+
+// RefreshDVPortState refreshes the state of the ports with the given keys, or of all ports if no keys are given.
+func (s DistributedVirtualSwitch) RefreshDVPortState(ctx context.Context, keys ...string) error {
+	req := types.RefreshDVPortState{
+		This:     s.Reference(),
+		PortKeys: keys,
+	}
+
+	_, err := methods.RefreshDVPortState(ctx, s.Client(), &req)
+	return err
+}
+
+// ReconfigureDVPort reconfigures individual ports, see NewDVPortConfigSpec.
+func (s DistributedVirtualSwitch) ReconfigureDVPort(ctx context.Context, spec []types.DVPortConfigSpec) (*Task, error) {
+	req := types.ReconfigureDVPort_Task{
+		This: s.Reference(),
+		Port: spec,
+	}
+
+	res, err := methods.ReconfigureDVPort_Task(ctx, s.Client(), &req)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewTask(s.Client(), res.Returnval), nil
+}
@@ -0,0 +1,61 @@
+/*
+Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+const guestInfoPrefix = "guestinfo."
+
+// GuestInfo is a set of guestinfo variables, which can be read within the guest via VMware Tools,
+// for example using: vmtoolsd --cmd "info-get guestinfo.ipaddress"
+// Instant clones are typically customized by a script in the guest that reads such variables,
+// for example to configure the clone's hostname and network identity.
+// Keys are used with or without the "guestinfo." prefix.
+type GuestInfo map[string]string
+
+// OptionValues returns the variables in the format used by VirtualMachineInstantCloneSpec.Config
+// and VirtualMachineConfigSpec.ExtraConfig, sorted by key.
+func (g GuestInfo) OptionValues() []types.BaseOptionValue {
+	opts := make([]types.BaseOptionValue, 0, len(g))
+	for key, val := range g {
+		if !strings.HasPrefix(key, guestInfoPrefix) {
+			key = guestInfoPrefix + key
+		}
+		opts = append(opts, &types.OptionValue{Key: key, Value: val})
+	}
+
+	sort.Slice(opts, func(i, j int) bool {
+		return opts[i].GetOptionValue().Key < opts[j].GetOptionValue().Key
+	})
+
+	return opts
+}
+
+// NewInstantCloneSpec returns a VirtualMachineInstantCloneSpec for a clone with the given name and location,
+// with guest customization via the given GuestInfo variables.
+func NewInstantCloneSpec(name string, location types.VirtualMachineRelocateSpec, info GuestInfo) types.VirtualMachineInstantCloneSpec {
+	return types.VirtualMachineInstantCloneSpec{
+		Name:     name,
+		Location: location,
+		Config:   info.OptionValues(),
+	}
+}
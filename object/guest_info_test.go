@@ -0,0 +1,47 @@
+/*
+Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"testing"
+
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+func TestGuestInfo(t *testing.T) {
+	info := GuestInfo{
+		"hostname":            "vm1",
+		"guestinfo.ipaddress": "10.0.0.42",
+	}
+
+	opts := info.OptionValues()
+	expect := []types.OptionValue{
+		{Key: "guestinfo.hostname", Value: "vm1"},
+		{Key: "guestinfo.ipaddress", Value: "10.0.0.42"},
+	}
+
+	if len(opts) != len(expect) {
+		t.Fatalf("opts=%d", len(opts))
+	}
+
+	for i := range expect {
+		opt := opts[i].GetOptionValue()
+		if opt.Key != expect[i].Key || opt.Value != expect[i].Value {
+			t.Errorf("%d: %s=%v", i, opt.Key, opt.Value)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+/*
+Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/task"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// MaintenanceModeOptions configures HostSystem.EnterMaintenanceModeAndWait.
+type MaintenanceModeOptions struct {
+	// Timeout for the host to enter maintenance mode, zero waits indefinitely.
+	Timeout time.Duration
+	// EvacuatePoweredOffVms also requires powered off VMs to be moved off the host, only supported in DRS clusters.
+	EvacuatePoweredOffVms bool
+	// Spec is passed through to EnterMaintenanceMode.
+	Spec *types.HostMaintenanceSpec
+	// ApplyRecommendations applies the DRS recommendations generated to evacuate the host,
+	// which are otherwise left pending when the cluster DRS behavior is not fully automated.
+	ApplyRecommendations bool
+}
+
+// MaintenanceModeError is returned by HostSystem.EnterMaintenanceModeAndWait when the host
+// failed to enter maintenance mode, listing the VMs blocking it and any pending DRS recommendations.
+type MaintenanceModeError struct {
+	Host            types.ManagedObjectReference
+	VMs             []types.ManagedObjectReference
+	Recommendations []types.ClusterRecommendation
+	Err             error
+}
+
+func (e *MaintenanceModeError) Error() string {
+	return fmt.Sprintf("%s: %d VMs blocking maintenance mode: %s", e.Host, len(e.VMs), e.Err)
+}
+
+// hostRecommendations returns the recommendations that migrate VMs off the given host.
+func hostRecommendations(host types.ManagedObjectReference, recs []types.ClusterRecommendation) []types.ClusterRecommendation {
+	var res []types.ClusterRecommendation
+
+	for _, rec := range recs {
+		for _, action := range rec.Action {
+			if m, ok := action.(*types.ClusterMigrationAction); ok && m.DrsMigration != nil && m.DrsMigration.Source == host {
+				res = append(res, rec)
+				break
+			}
+		}
+	}
+
+	return res
+}
+
+// blockingVMs returns the VMs that prevent the host from entering maintenance mode.
+func (h HostSystem) blockingVMs(ctx context.Context, poweredOff bool) ([]types.ManagedObjectReference, error) {
+	var mh mo.HostSystem
+
+	err := h.Properties(ctx, h.Reference(), []string{"vm"}, &mh)
+	if err != nil || len(mh.Vm) == 0 {
+		return nil, err
+	}
+
+	var vms []mo.VirtualMachine
+	err = property.DefaultCollector(h.c).Retrieve(ctx, mh.Vm, []string{"runtime.powerState"}, &vms)
+	if err != nil {
+		return nil, err
+	}
+
+	var refs []types.ManagedObjectReference
+	for _, vm := range vms {
+		if poweredOff || vm.Runtime.PowerState != types.VirtualMachinePowerStatePoweredOff {
+			refs = append(refs, vm.Self)
+		}
+	}
+
+	return refs, nil
+}
+
+// EnterMaintenanceModeAndWait puts the host in maintenance mode and waits for the task to complete.
+// When the host is part of a cluster, the cluster recommendations for evacuating the host are tracked
+// and applied if opts.ApplyRecommendations is set. Without this option, a cluster in manual or partially automated
+// DRS mode will not enter maintenance mode until the recommendations are applied by other means or the timeout is reached.
+// If the host fails to enter maintenance mode, a *MaintenanceModeError is returned.
+func (h HostSystem) EnterMaintenanceModeAndWait(ctx context.Context, opts MaintenanceModeOptions) error {
+	var mh mo.HostSystem
+
+	err := h.Properties(ctx, h.Reference(), []string{"parent"}, &mh)
+	if err != nil {
+		return err
+	}
+
+	t, err := h.EnterMaintenanceMode(ctx, int32(opts.Timeout/time.Second), opts.EvacuatePoweredOffVms, opts.Spec)
+	if err != nil {
+		return err
+	}
+
+	filter := new(property.WaitFilter).Add(t.Reference(), t.Reference().Type, []string{"info"})
+
+	var cluster *ClusterComputeResource
+	if mh.Parent != nil && mh.Parent.Type == "ClusterComputeResource" {
+		cluster = NewClusterComputeResource(h.c, *mh.Parent)
+		filter.Add(cluster.Reference(), cluster.Reference().Type, []string{"recommendation"})
+	}
+
+	var (
+		info    *types.TaskInfo
+		pending []types.ClusterRecommendation
+		applied = make(map[string]bool)
+		aerr    error
+	)
+
+	err = property.WaitForUpdates(ctx, property.DefaultCollector(h.c), filter, func(updates []types.ObjectUpdate) bool {
+		for _, update := range updates {
+			for _, change := range update.ChangeSet {
+				if val, ok := change.Val.(types.TaskInfo); ok {
+					switch val.State {
+					case types.TaskInfoStateSuccess, types.TaskInfoStateError:
+						info = &val
+					}
+				}
+			}
+		}
+
+		if info != nil {
+			return true // recommendations are left as they were when the task completed
+		}
+
+		for _, update := range updates {
+			for _, change := range update.ChangeSet {
+				if change.Name != "recommendation" {
+					continue
+				}
+
+				pending = nil
+				if val, ok := change.Val.(types.ArrayOfClusterRecommendation); ok {
+					pending = hostRecommendations(h.Reference(), val.ClusterRecommendation)
+				}
+			}
+		}
+
+		if opts.ApplyRecommendations {
+			for _, rec := range pending {
+				if applied[rec.Key] {
+					continue
+				}
+				applied[rec.Key] = true
+
+				if aerr = cluster.ApplyRecommendation(ctx, rec.Key); aerr != nil {
+					return true
+				}
+			}
+		}
+
+		return false
+	})
+
+	switch {
+	case err != nil:
+	case ctx.Err() != nil:
+		err = ctx.Err()
+	case aerr != nil:
+		err = aerr
+	case info != nil && info.State == types.TaskInfoStateError:
+		err = task.Error{LocalizedMethodFault: info.Error}
+	default:
+		return nil
+	}
+
+	merr := &MaintenanceModeError{
+		Host: h.Reference(),
+		Err:  err,
+	}
+
+	for _, rec := range pending {
+		if !applied[rec.Key] {
+			merr.Recommendations = append(merr.Recommendations, rec)
+		}
+	}
+
+	if ctx.Err() == nil {
+		merr.VMs, _ = h.blockingVMs(ctx, opts.EvacuatePoweredOffVms)
+	}
+
+	return merr
+}
@@ -37,6 +37,22 @@ func NewOpaqueNetwork(c *vim25.Client, ref types.ManagedObjectReference) *Opaque
 
 // EthernetCardBackingInfo returns the VirtualDeviceBackingInfo for this Network
 func (n OpaqueNetwork) EthernetCardBackingInfo(ctx context.Context) (types.BaseVirtualDeviceBackingInfo, error) {
+	summary, err := n.Summary(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	backing := &types.VirtualEthernetCardOpaqueNetworkBackingInfo{
+		OpaqueNetworkId:   summary.OpaqueNetworkId,
+		OpaqueNetworkType: summary.OpaqueNetworkType,
+	}
+
+	return backing, nil
+}
+
+// Summary returns the mo.OpaqueNetwork.Summary property, which includes the
+// OpaqueNetworkId (for example an NSX-T logical switch UUID) and OpaqueNetworkType.
+func (n OpaqueNetwork) Summary(ctx context.Context) (*types.OpaqueNetworkSummary, error) {
 	var net mo.OpaqueNetwork
 
 	if err := n.Properties(ctx, n.Reference(), []string{"summary"}, &net); err != nil {
@@ -45,13 +61,8 @@ func (n OpaqueNetwork) EthernetCardBackingInfo(ctx context.Context) (types.BaseV
 
 	summary, ok := net.Summary.(*types.OpaqueNetworkSummary)
 	if !ok {
-		return nil, fmt.Errorf("%s unsupported network type: %T", n, net.Summary)
-	}
-
-	backing := &types.VirtualEthernetCardOpaqueNetworkBackingInfo{
-		OpaqueNetworkId:   summary.OpaqueNetworkId,
-		OpaqueNetworkType: summary.OpaqueNetworkType,
+		return nil, fmt.Errorf("%s unsupported network summary type: %T", n, net.Summary)
 	}
 
-	return backing, nil
+	return summary, nil
 }
@@ -0,0 +1,247 @@
+/*
+Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// DiskPlacement specifies the target of a single disk when relocating a VM.
+type DiskPlacement struct {
+	// Datastore defaults to the RelocateSpec Datastore if set, otherwise the disk's current datastore.
+	// The disk's current datastore is not used for cross vCenter migration, see RelocateSpecBuilder.Service.
+	Datastore types.ManagedObjectReference
+	// Profile is the storage policy to apply to the disk.
+	Profile []types.BaseVirtualMachineProfileSpec
+	// Format converts the disk backing to thin, thick (preallocated) or eagerZeroedThick, unchanged if empty.
+	Format types.VirtualDiskType
+	// DiskMoveType overrides the RelocateSpec DiskMoveType for this disk.
+	DiskMoveType types.VirtualMachineRelocateDiskMoveOptions
+}
+
+type diskPlacement struct {
+	name string // device name or label, used if key is 0
+	key  int32
+	DiskPlacement
+}
+
+// RelocateSpecBuilder builds a VirtualMachineRelocateSpec, mapping the VM's disks to target datastores and policies.
+// Disks are resolved and the spec validated by Build.
+type RelocateSpecBuilder struct {
+	vm    VirtualMachine
+	spec  types.VirtualMachineRelocateSpec
+	disks []diskPlacement
+}
+
+// RelocateSpecBuilder returns a RelocateSpecBuilder for this VM.
+func (v VirtualMachine) RelocateSpecBuilder() *RelocateSpecBuilder {
+	return &RelocateSpecBuilder{vm: v}
+}
+
+// Datastore sets the target datastore of the VM home files, and the default target of its disks.
+func (b *RelocateSpecBuilder) Datastore(ds mo.Reference) *RelocateSpecBuilder {
+	b.spec.Datastore = types.NewReference(ds.Reference())
+	return b
+}
+
+// Pool sets the target resource pool.
+func (b *RelocateSpecBuilder) Pool(pool mo.Reference) *RelocateSpecBuilder {
+	b.spec.Pool = types.NewReference(pool.Reference())
+	return b
+}
+
+// Host sets the target host.
+func (b *RelocateSpecBuilder) Host(host mo.Reference) *RelocateSpecBuilder {
+	b.spec.Host = types.NewReference(host.Reference())
+	return b
+}
+
+// Folder sets the target folder.
+func (b *RelocateSpecBuilder) Folder(folder mo.Reference) *RelocateSpecBuilder {
+	b.spec.Folder = types.NewReference(folder.Reference())
+	return b
+}
+
+// Service sets the target vCenter, for cross vCenter migration.
+// Note that Datastore, Pool, Host and Folder references are then resolved by the target vCenter,
+// such that Build requires a Datastore for each placed disk.
+func (b *RelocateSpecBuilder) Service(service *types.ServiceLocator) *RelocateSpecBuilder {
+	b.spec.Service = service
+	return b
+}
+
+// Profile sets the storage policy of the VM home files.
+func (b *RelocateSpecBuilder) Profile(profile ...types.BaseVirtualMachineProfileSpec) *RelocateSpecBuilder {
+	b.spec.Profile = profile
+	return b
+}
+
+// DiskMoveType sets the default DiskMoveType of the VM's disks.
+func (b *RelocateSpecBuilder) DiskMoveType(t types.VirtualMachineRelocateDiskMoveOptions) *RelocateSpecBuilder {
+	b.spec.DiskMoveType = string(t)
+	return b
+}
+
+// Disk places the disk with the given device name (such as "disk-1000-0") or label (such as "Hard disk 1").
+func (b *RelocateSpecBuilder) Disk(name string, placement DiskPlacement) *RelocateSpecBuilder {
+	b.disks = append(b.disks, diskPlacement{name: name, DiskPlacement: placement})
+	return b
+}
+
+// DiskKey places the disk with the given device key.
+func (b *RelocateSpecBuilder) DiskKey(key int32, placement DiskPlacement) *RelocateSpecBuilder {
+	b.disks = append(b.disks, diskPlacement{key: key, DiskPlacement: placement})
+	return b
+}
+
+func (p *diskPlacement) String() string {
+	if p.key != 0 {
+		return fmt.Sprintf("%d", p.key)
+	}
+	return p.name
+}
+
+// find returns the disk matching the placement
+func (p *diskPlacement) find(devices VirtualDeviceList) (*types.VirtualDisk, error) {
+	var device types.BaseVirtualDevice
+
+	if p.key != 0 {
+		device = devices.FindByKey(p.key)
+	} else {
+		device = devices.Find(p.name)
+		if device == nil {
+			for _, d := range devices {
+				if info := d.GetVirtualDevice().DeviceInfo; info != nil && info.GetDescription().Label == p.name {
+					device = d
+					break
+				}
+			}
+		}
+	}
+
+	if device == nil {
+		return nil, fmt.Errorf("disk %q not found", p)
+	}
+
+	disk, ok := device.(*types.VirtualDisk)
+	if !ok {
+		return nil, fmt.Errorf("device %q is not a disk", p)
+	}
+
+	return disk, nil
+}
+
+// backing returns a backing to convert the disk to the placement Format, or nil if the Format is not set
+func (p *diskPlacement) backing(disk *types.VirtualDisk) (types.BaseVirtualDeviceBackingInfo, error) {
+	if p.Format == "" {
+		return nil, nil
+	}
+
+	current, ok := disk.Backing.(*types.VirtualDiskFlatVer2BackingInfo)
+	if !ok {
+		return nil, fmt.Errorf("disk %q backing %T cannot be converted", p, disk.Backing)
+	}
+
+	backing := &types.VirtualDiskFlatVer2BackingInfo{
+		DiskMode:        current.DiskMode,
+		ThinProvisioned: types.NewBool(false),
+		EagerlyScrub:    types.NewBool(false),
+	}
+
+	switch p.Format {
+	case types.VirtualDiskTypeThin:
+		backing.ThinProvisioned = types.NewBool(true)
+	case types.VirtualDiskTypeThick, types.VirtualDiskTypePreallocated:
+	case types.VirtualDiskTypeEagerZeroedThick:
+		backing.EagerlyScrub = types.NewBool(true)
+	default:
+		return nil, fmt.Errorf("disk %q format %q is not supported", p, p.Format)
+	}
+
+	return backing, nil
+}
+
+// Build resolves each disk placement to a VirtualMachineRelocateSpecDiskLocator and returns the validated spec.
+func (b *RelocateSpecBuilder) Build(ctx context.Context) (types.VirtualMachineRelocateSpec, error) {
+	spec := b.spec
+
+	if spec.Datastore != nil && spec.Datastore.Type != "Datastore" {
+		return spec, fmt.Errorf("%s is not a Datastore", spec.Datastore)
+	}
+
+	if len(b.disks) == 0 {
+		return spec, nil
+	}
+
+	devices, err := b.vm.Device(ctx)
+	if err != nil {
+		return spec, err
+	}
+
+	seen := make(map[int32]bool)
+
+	for i := range b.disks {
+		p := &b.disks[i]
+
+		disk, err := p.find(devices)
+		if err != nil {
+			return spec, err
+		}
+
+		if seen[disk.Key] {
+			return spec, fmt.Errorf("disk %q is placed more than once", p)
+		}
+		seen[disk.Key] = true
+
+		locator := types.VirtualMachineRelocateSpecDiskLocator{
+			DiskId:       disk.Key,
+			Datastore:    p.Datastore,
+			DiskMoveType: string(p.DiskMoveType),
+			Profile:      p.Profile,
+		}
+
+		if locator.Datastore.Value == "" {
+			if spec.Datastore != nil {
+				locator.Datastore = *spec.Datastore
+			} else if spec.Service != nil {
+				// the source datastore reference is not valid in the target vCenter
+				return spec, fmt.Errorf("disk %q has no target datastore for cross vCenter migration", p)
+			} else if backing, ok := disk.Backing.(types.BaseVirtualDeviceFileBackingInfo); ok && backing.GetVirtualDeviceFileBackingInfo().Datastore != nil {
+				locator.Datastore = *backing.GetVirtualDeviceFileBackingInfo().Datastore
+			} else {
+				return spec, fmt.Errorf("disk %q has no target datastore", p)
+			}
+		}
+
+		if locator.Datastore.Type != "Datastore" {
+			return spec, fmt.Errorf("disk %q target %s is not a Datastore", p, locator.Datastore)
+		}
+
+		locator.DiskBackingInfo, err = p.backing(disk)
+		if err != nil {
+			return spec, err
+		}
+
+		spec.Disk = append(spec.Disk, locator)
+	}
+
+	return spec, nil
+}
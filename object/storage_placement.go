@@ -0,0 +1,143 @@
+/*
+Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"context"
+	"errors"
+
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// NewStoragePlacementSpecCreate returns a StoragePlacementSpec to place a new VM in the given datastore cluster.
+// Disks to be created by the given spec are included in the pod selection spec, such that each can be placed.
+// The host param is optional.
+func NewStoragePlacementSpecCreate(pod *StoragePod, pool *ResourcePool, folder *Folder, host *HostSystem, spec types.VirtualMachineConfigSpec) types.StoragePlacementSpec {
+	ref := pod.Reference()
+
+	sps := types.StoragePlacementSpec{
+		Type:         string(types.StoragePlacementSpecPlacementTypeCreate),
+		ResourcePool: types.NewReference(pool.Reference()),
+		ConfigSpec:   &spec,
+		PodSelectionSpec: types.StorageDrsPodSelectionSpec{
+			StoragePod: &ref,
+		},
+	}
+
+	if folder != nil {
+		sps.Folder = types.NewReference(folder.Reference())
+	}
+
+	if host != nil {
+		sps.Host = types.NewReference(host.Reference())
+	}
+
+	for _, change := range spec.DeviceChange {
+		s := change.GetVirtualDeviceConfigSpec()
+		if s.Operation != types.VirtualDeviceConfigSpecOperationAdd {
+			continue
+		}
+
+		if s.FileOperation != types.VirtualDeviceConfigSpecFileOperationCreate {
+			continue
+		}
+
+		disk, ok := s.Device.(*types.VirtualDisk)
+		if !ok {
+			continue
+		}
+
+		sps.PodSelectionSpec.InitialVmConfig = append(sps.PodSelectionSpec.InitialVmConfig, types.VmPodConfigForPlacement{
+			StoragePod: ref,
+			Disk: []types.PodDiskLocator{
+				{
+					DiskId:          disk.Key,
+					DiskBackingInfo: disk.Backing,
+				},
+			},
+		})
+	}
+
+	return sps
+}
+
+// NewStoragePlacementSpecClone returns a StoragePlacementSpec to place a clone of the given VM in the given datastore cluster.
+func NewStoragePlacementSpecClone(pod *StoragePod, vm *VirtualMachine, folder *Folder, name string, spec types.VirtualMachineCloneSpec) types.StoragePlacementSpec {
+	return types.StoragePlacementSpec{
+		Type:      string(types.StoragePlacementSpecPlacementTypeClone),
+		Vm:        types.NewReference(vm.Reference()),
+		Folder:    types.NewReference(folder.Reference()),
+		CloneName: name,
+		CloneSpec: &spec,
+		PodSelectionSpec: types.StorageDrsPodSelectionSpec{
+			StoragePod: types.NewReference(pod.Reference()),
+		},
+	}
+}
+
+// NewStoragePlacementSpecRelocate returns a StoragePlacementSpec to relocate the given VM to the given datastore cluster.
+func NewStoragePlacementSpecRelocate(pod *StoragePod, vm *VirtualMachine, spec types.VirtualMachineRelocateSpec) types.StoragePlacementSpec {
+	return types.StoragePlacementSpec{
+		Type:         string(types.StoragePlacementSpecPlacementTypeRelocate),
+		Vm:           types.NewReference(vm.Reference()),
+		RelocateSpec: &spec,
+		PodSelectionSpec: types.StorageDrsPodSelectionSpec{
+			StoragePod: types.NewReference(pod.Reference()),
+		},
+	}
+}
+
+// ErrNoStoragePlacement is returned by RecommendDatastore when Storage DRS made no recommendations.
+var ErrNoStoragePlacement = errors.New("no datastore-cluster recommendations")
+
+// RecommendDatastore returns the first recommendation for the given spec,
+// which can be applied using ApplyStorageDrsRecommendation with the recommendation Key.
+func (sr StorageResourceManager) RecommendDatastore(ctx context.Context, spec types.StoragePlacementSpec) (*types.ClusterRecommendation, error) {
+	res, err := sr.RecommendDatastores(ctx, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(res.Recommendations) == 0 {
+		return nil, ErrNoStoragePlacement
+	}
+
+	return &res.Recommendations[0], nil
+}
+
+// StoragePlacementDestination returns the datastore of the recommendation's first StoragePlacementAction,
+// or nil if the recommendation has no such action.
+func StoragePlacementDestination(rec types.ClusterRecommendation) *types.ManagedObjectReference {
+	for _, action := range rec.Action {
+		if a, ok := action.(*types.StoragePlacementAction); ok {
+			return &a.Destination
+		}
+	}
+
+	return nil
+}
+
+// NewStorageDrsConfigSpec returns a spec to enable or disable Storage DRS for a datastore cluster,
+// with the given default automation behavior. The behavior is left unchanged if empty.
+func NewStorageDrsConfigSpec(enabled bool, behavior types.StorageDrsPodConfigInfoBehavior) types.StorageDrsConfigSpec {
+	return types.StorageDrsConfigSpec{
+		PodConfigSpec: &types.StorageDrsPodConfigSpec{
+			Enabled:           types.NewBool(enabled),
+			DefaultVmBehavior: string(behavior),
+		},
+	}
+}
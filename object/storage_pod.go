@@ -17,7 +17,10 @@ limitations under the License.
 package object
 
 import (
+	"context"
+
 	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/mo"
 	"github.com/vmware/govmomi/vim25/types"
 )
 
@@ -32,3 +35,29 @@ func NewStoragePod(c *vim25.Client, ref types.ManagedObjectReference) *StoragePo
 		},
 	}
 }
+
+// StorageDrsConfig returns the Storage DRS configuration of the datastore cluster.
+func (p StoragePod) StorageDrsConfig(ctx context.Context) (*types.StorageDrsConfigInfo, error) {
+	var pod mo.StoragePod
+
+	err := p.Properties(ctx, p.Reference(), []string{"podStorageDrsEntry"}, &pod)
+	if err != nil {
+		return nil, err
+	}
+
+	if pod.PodStorageDrsEntry == nil {
+		return nil, ErrNotSupported
+	}
+
+	return &pod.PodStorageDrsEntry.StorageDrsConfig, nil
+}
+
+// AddDatastores moves the given datastores into the datastore cluster.
+func (p StoragePod) AddDatastores(ctx context.Context, datastores ...*Datastore) (*Task, error) {
+	refs := make([]types.ManagedObjectReference, len(datastores))
+	for i, ds := range datastores {
+		refs[i] = ds.Reference()
+	}
+
+	return p.MoveInto(ctx, refs)
+}
@@ -18,9 +18,13 @@ package object
 
 import (
 	"context"
+	"fmt"
+	"sort"
 
+	"github.com/vmware/govmomi/nfc"
 	"github.com/vmware/govmomi/vim25"
 	"github.com/vmware/govmomi/vim25/methods"
+	"github.com/vmware/govmomi/vim25/mo"
 	"github.com/vmware/govmomi/vim25/types"
 )
 
@@ -103,3 +107,176 @@ func (p VirtualApp) Suspend(ctx context.Context) (*Task, error) {
 
 	return NewTask(p.c, res.Returnval), nil
 }
+
+// Clone creates a copy of the vApp and its child entities, named name, in the target ResourcePool or VirtualApp.
+// The spec.Location datastore is required.
+func (p VirtualApp) Clone(ctx context.Context, name string, target Reference, spec types.VAppCloneSpec) (*Task, error) {
+	req := types.CloneVApp_Task{
+		This:   p.Reference(),
+		Name:   name,
+		Target: target.Reference(),
+		Spec:   spec,
+	}
+
+	res, err := methods.CloneVApp_Task(ctx, p.c, &req)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewTask(p.c, res.Returnval), nil
+}
+
+func (p VirtualApp) Export(ctx context.Context) (*nfc.Lease, error) {
+	req := types.ExportVApp{
+		This: p.Reference(),
+	}
+
+	res, err := methods.ExportVApp(ctx, p.c, &req)
+	if err != nil {
+		return nil, err
+	}
+
+	return nfc.NewLease(p.c, res.Returnval), nil
+}
+
+func (p VirtualApp) config(ctx context.Context) (*types.VAppConfigInfo, error) {
+	var o mo.VirtualApp
+
+	err := p.Properties(ctx, p.Reference(), []string{"vAppConfig"}, &o)
+	if err != nil {
+		return nil, err
+	}
+
+	if o.VAppConfig == nil {
+		return new(types.VAppConfigInfo), nil
+	}
+
+	return o.VAppConfig, nil
+}
+
+// StartOrder returns the vApp's child entities grouped by start order.
+// PowerOn starts each group in turn, PowerOff and Suspend stop the groups in reverse order.
+func (p VirtualApp) StartOrder(ctx context.Context) ([][]types.ManagedObjectReference, error) {
+	config, err := p.config(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	entities := config.EntityConfig
+	sort.SliceStable(entities, func(i, j int) bool {
+		return entities[i].StartOrder < entities[j].StartOrder
+	})
+
+	var groups [][]types.ManagedObjectReference
+	order := int32(-1)
+
+	for _, e := range entities {
+		if e.Key == nil {
+			continue
+		}
+
+		if e.StartOrder != order || len(groups) == 0 {
+			groups = append(groups, nil)
+			order = e.StartOrder
+		}
+
+		groups[len(groups)-1] = append(groups[len(groups)-1], *e.Key)
+	}
+
+	return groups, nil
+}
+
+// SetStartOrder updates the start order of the given child entities, the first group is started first.
+// Entities not included in any group keep their current start order.
+func (p VirtualApp) SetStartOrder(ctx context.Context, groups ...[]types.ManagedObjectReference) error {
+	var spec types.VAppConfigSpec
+
+	for i, group := range groups {
+		for j := range group {
+			spec.EntityConfig = append(spec.EntityConfig, types.VAppEntityConfigInfo{
+				Key:        &group[j],
+				StartOrder: int32(i + 1),
+			})
+		}
+	}
+
+	return p.UpdateConfig(ctx, spec)
+}
+
+// VAppProperties returns the vApp property values, keyed by property ID.
+// The property DefaultValue is used for properties without a Value.
+func (p VirtualApp) VAppProperties(ctx context.Context) (map[string]string, error) {
+	config, err := p.config(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string, len(config.Property))
+
+	for _, prop := range config.Property {
+		value := prop.Value
+		if value == "" {
+			value = prop.DefaultValue
+		}
+		values[prop.Id] = value
+	}
+
+	return values, nil
+}
+
+// SetVAppProperties updates the values of the given vApp properties, keyed by property ID.
+func (p VirtualApp) SetVAppProperties(ctx context.Context, values map[string]string) error {
+	config, err := p.config(ctx)
+	if err != nil {
+		return err
+	}
+
+	props, err := NewVAppPropertySpec(config.Property, values)
+	if err != nil {
+		return err
+	}
+
+	spec := types.VAppConfigSpec{
+		VmConfigSpec: types.VmConfigSpec{
+			Property: props,
+		},
+	}
+
+	return p.UpdateConfig(ctx, spec)
+}
+
+// NewVAppPropertySpec returns the edit operations to set the given values, keyed by property ID,
+// for use with a VirtualApp or VirtualMachine vApp config spec.
+// An error is returned if a value does not match an existing property.
+func NewVAppPropertySpec(props []types.VAppPropertyInfo, values map[string]string) ([]types.VAppPropertySpec, error) {
+	ids := make(map[string]types.VAppPropertyInfo, len(props))
+	for _, prop := range props {
+		ids[prop.Id] = prop
+	}
+
+	keys := make([]string, 0, len(values))
+	for id := range values {
+		keys = append(keys, id)
+	}
+	sort.Strings(keys)
+
+	var spec []types.VAppPropertySpec
+
+	for _, id := range keys {
+		prop, ok := ids[id]
+		if !ok {
+			return nil, fmt.Errorf("vApp property %q not found", id)
+		}
+
+		prop.Value = values[id]
+
+		spec = append(spec, types.VAppPropertySpec{
+			ArrayUpdateSpec: types.ArrayUpdateSpec{
+				Operation: types.ArrayUpdateOperationEdit,
+			},
+			Info: &prop,
+		})
+	}
+
+	return spec, nil
+}
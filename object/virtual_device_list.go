@@ -134,6 +134,9 @@ func (l VirtualDeviceList) SelectByBackingInfo(backing types.BaseVirtualDeviceBa
 			b := backing.(*types.VirtualEthernetCardDistributedVirtualPortBackingInfo)
 			return a.Port.SwitchUuid == b.Port.SwitchUuid &&
 				a.Port.PortgroupKey == b.Port.PortgroupKey
+		case *types.VirtualEthernetCardOpaqueNetworkBackingInfo:
+			b := backing.(*types.VirtualEthernetCardOpaqueNetworkBackingInfo)
+			return a.OpaqueNetworkId == b.OpaqueNetworkId
 		case *types.VirtualDiskFlatVer2BackingInfo:
 			b := backing.(*types.VirtualDiskFlatVer2BackingInfo)
 			if a.Parent != nil && b.Parent != nil {
@@ -303,10 +306,14 @@ func (l VirtualDeviceList) FindNVMEController(name string) (*types.VirtualNVMECo
 	return c.(*types.VirtualNVMEController), nil
 }
 
-// CreateNVMEController creates a new NVMWE controller.
+// CreateNVMEController creates a new NVME controller.
+// An error is returned if the maximum number of NVME controllers are already in use.
 func (l VirtualDeviceList) CreateNVMEController() (types.BaseVirtualDevice, error) {
 	nvme := &types.VirtualNVMEController{}
 	nvme.BusNumber = l.newNVMEBusNumber()
+	if nvme.BusNumber < 0 {
+		return nil, errors.New("no available NVME bus number")
+	}
 	nvme.Key = l.NewKey()
 
 	return nvme, nil
@@ -314,6 +321,9 @@ func (l VirtualDeviceList) CreateNVMEController() (types.BaseVirtualDevice, erro
 
 var nvmeBusNumbers = []int{0, 1, 2, 3}
 
+// nvmeUnitNumbers is the number of disks (namespaces) that can be attached to an NVME controller.
+const nvmeUnitNumbers = 15
+
 // newNVMEBusNumber returns the bus number to use for adding a new NVME bus device.
 // -1 is returned if there are no bus numbers available.
 func (l VirtualDeviceList) newNVMEBusNumber() int32 {
@@ -337,7 +347,7 @@ func (l VirtualDeviceList) newNVMEBusNumber() int32 {
 	return -1
 }
 
-// FindDiskController will find an existing ide or scsi disk controller.
+// FindDiskController will find an existing ide, scsi or nvme disk controller.
 func (l VirtualDeviceList) FindDiskController(name string) (types.BaseVirtualController, error) {
 	switch {
 	case name == "ide":
@@ -366,7 +376,7 @@ func (l VirtualDeviceList) PickController(kind types.BaseVirtualController) type
 		case *types.VirtualIDEController:
 			return num < 2
 		case *types.VirtualNVMEController:
-			return num < 8
+			return num < nvmeUnitNumbers
 		default:
 			return true
 		}
@@ -387,6 +397,8 @@ func (l VirtualDeviceList) newUnitNumber(c types.BaseVirtualController) int32 {
 	case types.BaseVirtualSCSIController:
 		//  The SCSI controller sits on its own bus
 		units[sc.GetVirtualSCSIController().ScsiCtlrUnitNumber] = true
+	case *types.VirtualNVMEController:
+		units = make([]bool, nvmeUnitNumbers)
 	}
 
 	key := c.GetVirtualController().Key
@@ -394,7 +406,7 @@ func (l VirtualDeviceList) newUnitNumber(c types.BaseVirtualController) int32 {
 	for _, device := range l {
 		d := device.GetVirtualDevice()
 
-		if d.ControllerKey == key && d.UnitNumber != nil {
+		if d.ControllerKey == key && d.UnitNumber != nil && int(*d.UnitNumber) < len(units) {
 			units[int(*d.UnitNumber)] = true
 		}
 	}
@@ -681,6 +681,64 @@ func TestCreateSCSIController(t *testing.T) {
 	}
 }
 
+func TestCreateNVMEController(t *testing.T) {
+	var l VirtualDeviceList
+
+	for i := 0; i < len(nvmeBusNumbers); i++ {
+		d, err := l.CreateNVMEController()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		c := d.(*types.VirtualNVMEController)
+		if c.BusNumber != int32(i) {
+			t.Errorf("expected bus number: %d, got: %d", i, c.BusNumber)
+		}
+
+		l = append(l, d)
+	}
+
+	if _, err := l.CreateNVMEController(); err == nil {
+		t.Error("should fail")
+	}
+
+	c, err := l.FindNVMEController("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = l.FindNVMEController(l.Name(c)); err != nil {
+		t.Error(err)
+	}
+
+	if _, err = devices.FindNVMEController("ide-200"); err == nil {
+		t.Error("should fail")
+	}
+
+	if dc, err := l.FindDiskController("nvme"); err != nil || dc != c {
+		t.Errorf("FindDiskController(nvme)=%v, %v", dc, err)
+	}
+
+	for i := 0; i < nvmeUnitNumbers; i++ {
+		disk := l.CreateDisk(c, types.ManagedObjectReference{}, "")
+		if *disk.UnitNumber != int32(i) {
+			t.Errorf("expected unit number: %d, got: %d", i, *disk.UnitNumber)
+		}
+		disk.Key = l.NewKey()
+
+		l = append(l, disk)
+		c.Device = append(c.Device, disk.Key)
+	}
+
+	if l.newUnitNumber(c) != -1 {
+		t.Error("expected no available unit number")
+	}
+
+	if p := l.PickController((*types.VirtualNVMEController)(nil)); p == nil || p.GetVirtualController().Key == c.Key {
+		t.Errorf("expected a different controller, got: %v", p)
+	}
+}
+
 func TestCreateEthernetCard(t *testing.T) {
 	_, err := EthernetCardTypes().CreateEthernetCard("enoent", nil)
 	if err == nil {
@@ -580,7 +580,7 @@ func (v VirtualMachine) FindSnapshot(ctx context.Context, name string) (*types.M
 	}
 
 	if o.Snapshot == nil || len(o.Snapshot.RootSnapshotList) == 0 {
-		return nil, errors.New("no snapshots for this VM")
+		return nil, ErrNoSnapshots
 	}
 
 	m := make(snapshotMap)
@@ -589,11 +589,11 @@ func (v VirtualMachine) FindSnapshot(ctx context.Context, name string) (*types.M
 	s := m[name]
 	switch len(s) {
 	case 0:
-		return nil, fmt.Errorf("snapshot %q not found", name)
+		return nil, &SnapshotNotFoundError{Name: name}
 	case 1:
 		return &s[0], nil
 	default:
-		return nil, fmt.Errorf("%q resolves to %d snapshots", name, len(s))
+		return nil, &SnapshotAmbiguousError{Name: name, Snapshots: s}
 	}
 }
 
@@ -720,6 +720,22 @@ func (v VirtualMachine) MarkAsVirtualMachine(ctx context.Context, pool ResourceP
 	return nil
 }
 
+// InstantClone creates a powered on clone of the VirtualMachine, sharing the memory and disk state of the running source VM.
+// The source VM must be powered on. Guest customization of the clone can be done via spec.Config, see GuestInfo.
+func (v VirtualMachine) InstantClone(ctx context.Context, spec types.VirtualMachineInstantCloneSpec) (*Task, error) {
+	req := types.InstantClone_Task{
+		This: v.Reference(),
+		Spec: spec,
+	}
+
+	res, err := methods.InstantClone_Task(ctx, v.c, &req)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewTask(v.c, res.Returnval), nil
+}
+
 func (v VirtualMachine) Migrate(ctx context.Context, pool *ResourcePool, host *HostSystem, priority types.VirtualMachineMovePriority, state types.VirtualMachinePowerState) (*Task, error) {
 	req := types.MigrateVM_Task{
 		This:     v.Reference(),
@@ -910,3 +926,44 @@ func (v VirtualMachine) QueryChangedDiskAreas(ctx context.Context, baseSnapshot,
 
 	return res.Returnval, nil
 }
+
+// AttachDisk attaches the First Class Disk with the given id to the VirtualMachine.
+// If controllerKey is 0, the disk is attached to the VM's first available controller.
+// If unitNumber is nil, the next available unit number of the controller is used.
+// The datastore containing the disk is required.
+func (v VirtualMachine) AttachDisk(ctx context.Context, id string, datastore *Datastore, controllerKey int32, unitNumber *int32) (*Task, error) {
+	if datastore == nil {
+		return nil, errors.New("datastore is required to attach a disk")
+	}
+
+	req := types.AttachDisk_Task{
+		This:          v.Reference(),
+		DiskId:        types.ID{Id: id},
+		Datastore:     datastore.Reference(),
+		ControllerKey: controllerKey,
+		UnitNumber:    unitNumber,
+	}
+
+	res, err := methods.AttachDisk_Task(ctx, v.Client(), &req)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewTask(v.c, res.Returnval), nil
+}
+
+// DetachDisk detaches the First Class Disk with the given id from the VirtualMachine.
+// The disk itself is not deleted.
+func (v VirtualMachine) DetachDisk(ctx context.Context, id string) (*Task, error) {
+	req := types.DetachDisk_Task{
+		This:   v.Reference(),
+		DiskId: types.ID{Id: id},
+	}
+
+	res, err := methods.DetachDisk_Task(ctx, v.Client(), &req)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewTask(v.c, res.Returnval), nil
+}
@@ -0,0 +1,239 @@
+/*
+Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+
+	"github.com/vmware/govmomi/vim25/methods"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// ErrNoSnapshots is returned by FindSnapshot when the VM has no snapshots.
+var ErrNoSnapshots = errors.New("no snapshots for this VM")
+
+// SnapshotNotFoundError is returned by FindSnapshot when no snapshot matches Name.
+type SnapshotNotFoundError struct {
+	Name string
+}
+
+func (e *SnapshotNotFoundError) Error() string {
+	return fmt.Sprintf("snapshot %q not found", e.Name)
+}
+
+// SnapshotAmbiguousError is returned by FindSnapshot when Name matches more than one snapshot.
+// The full tree path or the snapshot moref value can be used to resolve the ambiguity.
+type SnapshotAmbiguousError struct {
+	Name      string
+	Snapshots []types.ManagedObjectReference
+}
+
+func (e *SnapshotAmbiguousError) Error() string {
+	return fmt.Sprintf("%q resolves to %d snapshots", e.Name, len(e.Snapshots))
+}
+
+// SkipSnapshotChildren is used as a return value from a WalkSnapshotTree callback,
+// to skip the children of the snapshot it was called with. It is not returned as an error by any function.
+var SkipSnapshotChildren = errors.New("skip snapshot children")
+
+// WalkSnapshotTree walks the given snapshot tree depth-first, calling fn for each snapshot along with its tree path.
+// If fn returns an error other than SkipSnapshotChildren, the walk stops and that error is returned.
+func WalkSnapshotTree(tree []types.VirtualMachineSnapshotTree, fn func(string, *types.VirtualMachineSnapshotTree) error) error {
+	return walkSnapshotTree("", tree, fn)
+}
+
+func walkSnapshotTree(parent string, tree []types.VirtualMachineSnapshotTree, fn func(string, *types.VirtualMachineSnapshotTree) error) error {
+	for i := range tree {
+		node := &tree[i]
+		name := path.Join(parent, node.Name)
+
+		err := fn(name, node)
+		if err == SkipSnapshotChildren {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if err = walkSnapshotTree(name, node.ChildSnapshotList, fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WalkSnapshots calls WalkSnapshotTree with the VM's snapshot tree.
+// If the VM has no snapshots, fn is not called.
+func (v VirtualMachine) WalkSnapshots(ctx context.Context, fn func(string, *types.VirtualMachineSnapshotTree) error) error {
+	var o mo.VirtualMachine
+
+	err := v.Properties(ctx, v.Reference(), []string{"snapshot"}, &o)
+	if err != nil {
+		return err
+	}
+
+	if o.Snapshot == nil {
+		return nil
+	}
+
+	return WalkSnapshotTree(o.Snapshot.RootSnapshotList, fn)
+}
+
+// snapshotChain returns the chain of snapshots from a root to the given snapshot, inclusive.
+func snapshotChain(tree []types.VirtualMachineSnapshotTree, snapshot types.ManagedObjectReference) []types.ManagedObjectReference {
+	for _, node := range tree {
+		if node.Snapshot == snapshot {
+			return []types.ManagedObjectReference{snapshot}
+		}
+
+		if chain := snapshotChain(node.ChildSnapshotList, snapshot); chain != nil {
+			return append([]types.ManagedObjectReference{node.Snapshot}, chain...)
+		}
+	}
+
+	return nil
+}
+
+// snapshotFiles returns the file keys of the given snapshots: data, memory and disk chain files.
+func snapshotFiles(layout *types.VirtualMachineFileLayoutEx, snapshots ...types.ManagedObjectReference) map[int32]bool {
+	files := make(map[int32]bool)
+	include := make(map[types.ManagedObjectReference]bool)
+	for _, s := range snapshots {
+		include[s] = true
+	}
+
+	for _, s := range layout.Snapshot {
+		if !include[s.Key] {
+			continue
+		}
+
+		files[s.DataKey] = true
+		if s.MemoryKey >= 0 {
+			files[s.MemoryKey] = true
+		}
+
+		for _, disk := range s.Disk {
+			for _, unit := range disk.Chain {
+				for _, key := range unit.FileKey {
+					files[key] = true
+				}
+			}
+		}
+	}
+
+	return files
+}
+
+func fileSize(layout *types.VirtualMachineFileLayoutEx, files map[int32]bool) int64 {
+	var size int64
+
+	for _, file := range layout.File {
+		if files[file.Key] {
+			size += file.Size
+		}
+	}
+
+	return size
+}
+
+// SnapshotSize returns the size in bytes of the files added by the given snapshot,
+// excluding files shared with its parent, which may be nil for a root snapshot.
+func SnapshotSize(layout *types.VirtualMachineFileLayoutEx, snapshot types.ManagedObjectReference, parent *types.ManagedObjectReference) int64 {
+	files := snapshotFiles(layout, snapshot)
+
+	if parent != nil {
+		for key := range snapshotFiles(layout, *parent) {
+			delete(files, key)
+		}
+	}
+
+	return fileSize(layout, files)
+}
+
+// SnapshotChainSize returns the size in bytes of the files of the named snapshot and all of its ancestors.
+// See FindSnapshot for the supported name formats.
+func (v VirtualMachine) SnapshotChainSize(ctx context.Context, name string) (int64, error) {
+	snapshot, err := v.FindSnapshot(ctx, name)
+	if err != nil {
+		return 0, err
+	}
+
+	var o mo.VirtualMachine
+
+	err = v.Properties(ctx, v.Reference(), []string{"snapshot", "layoutEx"}, &o)
+	if err != nil {
+		return 0, err
+	}
+
+	if o.Snapshot == nil || o.LayoutEx == nil {
+		return 0, nil
+	}
+
+	chain := snapshotChain(o.Snapshot.RootSnapshotList, *snapshot)
+
+	return fileSize(o.LayoutEx, snapshotFiles(o.LayoutEx, chain...)), nil
+}
+
+// ConsolidateDisks consolidates the VM's disks, merging redundant delta disks left behind by snapshot operations.
+func (v VirtualMachine) ConsolidateDisks(ctx context.Context) (*Task, error) {
+	req := types.ConsolidateVMDisks_Task{
+		This: v.Reference(),
+	}
+
+	res, err := methods.ConsolidateVMDisks_Task(ctx, v.c, &req)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewTask(v.c, res.Returnval), nil
+}
+
+// RemoveSnapshotTree removes the named snapshot along with all of its children and waits for the task to complete.
+// If the VM reports that disk consolidation is needed afterwards, ConsolidateDisks is also called and waited for.
+func (v VirtualMachine) RemoveSnapshotTree(ctx context.Context, name string) error {
+	task, err := v.RemoveSnapshot(ctx, name, true, types.NewBool(true))
+	if err != nil {
+		return err
+	}
+
+	if err = task.Wait(ctx); err != nil {
+		return err
+	}
+
+	var o mo.VirtualMachine
+
+	err = v.Properties(ctx, v.Reference(), []string{"runtime.consolidationNeeded"}, &o)
+	if err != nil {
+		return err
+	}
+
+	if o.Runtime.ConsolidationNeeded == nil || !*o.Runtime.ConsolidationNeeded {
+		return nil
+	}
+
+	task, err = v.ConsolidateDisks(ctx)
+	if err != nil {
+		return err
+	}
+
+	return task.Wait(ctx)
+}
@@ -0,0 +1,139 @@
+/*
+Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+func TestWalkSnapshotTree(t *testing.T) {
+	var paths []string
+
+	err := WalkSnapshotTree(snapshot.RootSnapshotList, func(name string, node *types.VirtualMachineSnapshotTree) error {
+		paths = append(paths, name)
+		if name == "root/child" {
+			return SkipSnapshotChildren
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := []string{
+		"root",
+		"root/child",
+		"root/child",
+		"root/voodoo",
+		"root/voodoo/child",
+		"root/better",
+		"root/better/best",
+		"root/better/best/betterer",
+	}
+
+	if !reflect.DeepEqual(paths, expect) {
+		t.Errorf("%v != %v", paths, expect)
+	}
+
+	stop := errors.New("stop")
+	n := 0
+
+	err = WalkSnapshotTree(snapshot.RootSnapshotList, func(string, *types.VirtualMachineSnapshotTree) error {
+		n++
+		if n == 3 {
+			return stop
+		}
+		return nil
+	})
+	if err != stop {
+		t.Errorf("err=%v", err)
+	}
+	if n != 3 {
+		t.Errorf("n=%d", n)
+	}
+}
+
+func TestSnapshotSize(t *testing.T) {
+	ref := func(id string) types.ManagedObjectReference {
+		return types.ManagedObjectReference{Type: "VirtualMachineSnapshot", Value: id}
+	}
+
+	chain := snapshotChain(snapshot.RootSnapshotList, ref("2-snapshot-10"))
+	expect := []types.ManagedObjectReference{ref("2-snapshot-1"), ref("2-snapshot-3"), ref("2-snapshot-9"), ref("2-snapshot-10")}
+	if !reflect.DeepEqual(chain, expect) {
+		t.Errorf("%v != %v", chain, expect)
+	}
+
+	if chain = snapshotChain(snapshot.RootSnapshotList, ref("enoent")); chain != nil {
+		t.Errorf("chain=%v", chain)
+	}
+
+	layout := &types.VirtualMachineFileLayoutEx{
+		File: []types.VirtualMachineFileLayoutExFileInfo{
+			{Key: 1, Size: 1},
+			{Key: 2, Size: 2},
+			{Key: 3, Size: 4},
+			{Key: 10, Size: 100},
+			{Key: 11, Size: 200},
+		},
+		Snapshot: []types.VirtualMachineFileLayoutExSnapshotLayout{
+			{
+				Key:       ref("2-snapshot-1"),
+				DataKey:   1,
+				MemoryKey: -1,
+				Disk: []types.VirtualMachineFileLayoutExDiskLayout{
+					{Key: 2000, Chain: []types.VirtualMachineFileLayoutExDiskUnit{{FileKey: []int32{10}}}},
+				},
+			},
+			{
+				Key:       ref("2-snapshot-3"),
+				DataKey:   2,
+				MemoryKey: 3,
+				Disk: []types.VirtualMachineFileLayoutExDiskLayout{
+					{Key: 2000, Chain: []types.VirtualMachineFileLayoutExDiskUnit{{FileKey: []int32{10}}, {FileKey: []int32{11}}}},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		snapshot types.ManagedObjectReference
+		parent   *types.ManagedObjectReference
+		expect   int64
+	}{
+		{ref("2-snapshot-1"), nil, 101},
+		{ref("2-snapshot-3"), nil, 306},
+		{ref("2-snapshot-3"), &expect[0], 206},
+		{ref("enoent"), nil, 0},
+	}
+
+	for _, test := range tests {
+		size := SnapshotSize(layout, test.snapshot, test.parent)
+		if size != test.expect {
+			t.Errorf("%s: %d != %d", test.snapshot, size, test.expect)
+		}
+	}
+
+	size := fileSize(layout, snapshotFiles(layout, expect...))
+	if size != 307 {
+		t.Errorf("chain size=%d", size)
+	}
+}
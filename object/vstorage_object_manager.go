@@ -0,0 +1,175 @@
+/*
+Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"context"
+
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/methods"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// VStorageObjectManager wraps the datastore scoped First Class Disk (FCD) operations of VStorageObjectManagerBase.
+// For the global catalog, which does not require a datastore, see vslm.GlobalObjectManager.
+type VStorageObjectManager struct {
+	Common
+
+	isVC bool
+}
+
+// GetVStorageObjectManager wraps NewVStorageObjectManager, returning ErrNotSupported
+// when the client is not connected to an endpoint with a VStorageObjectManager.
+func GetVStorageObjectManager(c *vim25.Client) (*VStorageObjectManager, error) {
+	if c.ServiceContent.VStorageObjectManager == nil {
+		return nil, ErrNotSupported
+	}
+	return NewVStorageObjectManager(c), nil
+}
+
+// NewVStorageObjectManager returns a VStorageObjectManager referencing the VcenterVStorageObjectManager singleton
+// when connected to vCenter or the HostVStorageObjectManager singleton when connected to an ESX host.
+// The optional ref param can be used to specify an ESX host instead, when connected to vCenter.
+// The reference is empty if the endpoint has no VStorageObjectManager, see GetVStorageObjectManager.
+func NewVStorageObjectManager(c *vim25.Client, ref ...types.ManagedObjectReference) *VStorageObjectManager {
+	var mref types.ManagedObjectReference
+	if c.ServiceContent.VStorageObjectManager != nil {
+		mref = *c.ServiceContent.VStorageObjectManager
+	}
+
+	if len(ref) == 1 {
+		mref = ref[0]
+	}
+
+	m := VStorageObjectManager{
+		Common: NewCommon(c, mref),
+		isVC:   mref.Type == "VcenterVStorageObjectManager",
+	}
+
+	return &m
+}
+
+// CreateDisk creates a First Class Disk on the datastore specified by the spec backing.
+// The Task result is the created types.VStorageObject.
+func (m VStorageObjectManager) CreateDisk(ctx context.Context, spec types.VslmCreateSpec) (*Task, error) {
+	req := types.CreateDisk_Task{
+		This: m.Reference(),
+		Spec: spec,
+	}
+
+	if m.isVC {
+		res, err := methods.CreateDisk_Task(ctx, m.c, &req)
+		if err != nil {
+			return nil, err
+		}
+
+		return NewTask(m.c, res.Returnval), nil
+	}
+
+	res, err := methods.HostCreateDisk_Task(ctx, m.c, (*types.HostCreateDisk_Task)(&req))
+	if err != nil {
+		return nil, err
+	}
+
+	return NewTask(m.c, res.Returnval), nil
+}
+
+// Delete deletes the First Class Disk with the given id from datastore ds.
+func (m VStorageObjectManager) Delete(ctx context.Context, ds Reference, id string) (*Task, error) {
+	req := types.DeleteVStorageObject_Task{
+		This:      m.Reference(),
+		Datastore: ds.Reference(),
+		Id:        types.ID{Id: id},
+	}
+
+	if m.isVC {
+		res, err := methods.DeleteVStorageObject_Task(ctx, m.c, &req)
+		if err != nil {
+			return nil, err
+		}
+
+		return NewTask(m.c, res.Returnval), nil
+	}
+
+	res, err := methods.HostDeleteVStorageObject_Task(ctx, m.c, (*types.HostDeleteVStorageObject_Task)(&req))
+	if err != nil {
+		return nil, err
+	}
+
+	return NewTask(m.c, res.Returnval), nil
+}
+
+// Retrieve returns the First Class Disk with the given id on datastore ds.
+func (m VStorageObjectManager) Retrieve(ctx context.Context, ds Reference, id string) (*types.VStorageObject, error) {
+	req := types.RetrieveVStorageObject{
+		This:      m.Reference(),
+		Datastore: ds.Reference(),
+		Id:        types.ID{Id: id},
+	}
+
+	if m.isVC {
+		res, err := methods.RetrieveVStorageObject(ctx, m.c, &req)
+		if err != nil {
+			return nil, err
+		}
+
+		return &res.Returnval, nil
+	}
+
+	res, err := methods.HostRetrieveVStorageObject(ctx, m.c, (*types.HostRetrieveVStorageObject)(&req))
+	if err != nil {
+		return nil, err
+	}
+
+	return &res.Returnval, nil
+}
+
+// List returns the IDs of the First Class Disks on datastore ds.
+func (m VStorageObjectManager) List(ctx context.Context, ds Reference) ([]types.ID, error) {
+	req := types.ListVStorageObject{
+		This:      m.Reference(),
+		Datastore: ds.Reference(),
+	}
+
+	if m.isVC {
+		res, err := methods.ListVStorageObject(ctx, m.c, &req)
+		if err != nil {
+			return nil, err
+		}
+
+		return res.Returnval, nil
+	}
+
+	res, err := methods.HostListVStorageObject(ctx, m.c, (*types.HostListVStorageObject)(&req))
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Returnval, nil
+}
+
+// AttachDisk attaches the First Class Disk with the given id on datastore ds to vm,
+// see VirtualMachine.AttachDisk.
+func (m VStorageObjectManager) AttachDisk(ctx context.Context, vm Reference, ds Reference, id string, controllerKey int32, unitNumber *int32) (*Task, error) {
+	datastore := NewDatastore(m.c, ds.Reference())
+	return NewVirtualMachine(m.c, vm.Reference()).AttachDisk(ctx, id, datastore, controllerKey, unitNumber)
+}
+
+// DetachDisk detaches the First Class Disk with the given id from vm, see VirtualMachine.DetachDisk.
+func (m VStorageObjectManager) DetachDisk(ctx context.Context, vm Reference, id string) (*Task, error) {
+	return NewVirtualMachine(m.c, vm.Reference()).DetachDisk(ctx, id)
+}
@@ -0,0 +1,382 @@
+/*
+Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package property
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/vmware/govmomi/vim25/methods"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// Cache is an opt-in, in-memory cache of managed object properties.
+// The cache is kept consistent by a WaitForUpdates loop, using a dedicated property collector
+// and the given WaitFilter, which runs in the background once started.
+// Retrieve and RetrieveOne calls are served from the cache when the requested objects and properties
+// are covered by the filter, otherwise the calls are forwarded to the Collector.
+// Values returned from the cache are shared and must not be modified.
+type Cache struct {
+	collector *Collector
+	filter    *WaitFilter
+	specs     map[string]types.PropertySpec
+
+	mu      sync.RWMutex
+	objects map[types.ManagedObjectReference]*cacheEntry
+	running bool
+	err     error
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+type cacheEntry struct {
+	props   map[string]types.AnyType
+	missing map[string]types.MissingProperty
+}
+
+// NewCache creates a Cache for the objects and properties specified by the given filter,
+// which must not be modified once the Cache is started.
+func NewCache(c *Collector, filter *WaitFilter) *Cache {
+	specs := make(map[string]types.PropertySpec)
+	for _, spec := range filter.Spec.PropSet {
+		specs[spec.Type] = spec
+	}
+
+	return &Cache{
+		collector: c,
+		filter:    filter,
+		specs:     specs,
+		objects:   make(map[types.ManagedObjectReference]*cacheEntry),
+	}
+}
+
+// Start creates the property collector and filter used to keep the cache consistent and
+// waits for the initial set of updates to be applied, then runs the WaitForUpdates loop
+// in the background until Stop is called or an error occurs.
+func (c *Cache) Start(ctx context.Context) error {
+	if c.cancel != nil {
+		return errors.New("cache already started")
+	}
+
+	p, err := c.collector.Create(ctx)
+	if err != nil {
+		return err
+	}
+
+	var version string
+	err = p.CreateFilter(ctx, c.filter.CreateFilter)
+	if err == nil {
+		version, err = c.sync(ctx, p)
+	}
+	if err != nil {
+		_ = p.Destroy(context.Background())
+		return err
+	}
+
+	wctx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+	c.done = make(chan struct{})
+
+	c.mu.Lock()
+	c.running = true
+	c.err = nil
+	c.mu.Unlock()
+
+	go c.run(wctx, p, version)
+
+	return nil
+}
+
+// Stop cancels the WaitForUpdates loop and destroys its property collector.
+// Once stopped, all calls are forwarded to the Collector.
+func (c *Cache) Stop() {
+	if c.cancel == nil {
+		return
+	}
+
+	c.cancel()
+	<-c.done
+	c.cancel = nil
+}
+
+// Err returns the error that caused the WaitForUpdates loop to stop, if any.
+func (c *Cache) Err() error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.err
+}
+
+// sync applies the initial set of updates, returning the version to wait for next.
+func (c *Cache) sync(ctx context.Context, p *Collector) (string, error) {
+	req := types.WaitForUpdatesEx{
+		This:    p.Reference(),
+		Options: &types.WaitOptions{MaxWaitSeconds: types.NewInt32(0)},
+	}
+
+	if c.filter.Options != nil {
+		req.Options.MaxObjectUpdates = c.filter.Options.MaxObjectUpdates
+	}
+
+	for {
+		res, err := methods.WaitForUpdatesEx(ctx, p.roundTripper, &req)
+		if err != nil {
+			return "", err
+		}
+
+		set := res.Returnval
+		if set == nil {
+			return req.Version, nil
+		}
+
+		req.Version = set.Version
+
+		if err = c.apply(ctx, p, set); err != nil {
+			return "", err
+		}
+
+		if set.Truncated == nil || !*set.Truncated {
+			return req.Version, nil
+		}
+	}
+}
+
+func (c *Cache) run(ctx context.Context, p *Collector, version string) {
+	defer close(c.done)
+
+	// Destroy the collector using the background context, as ctx has been canceled.
+	defer func() {
+		_ = p.Destroy(context.Background())
+	}()
+
+	req := types.WaitForUpdatesEx{
+		This:    p.Reference(),
+		Version: version,
+		Options: c.filter.Options,
+	}
+
+	for {
+		res, err := methods.WaitForUpdatesEx(ctx, p.roundTripper, &req)
+		if err == nil && res.Returnval != nil {
+			req.Version = res.Returnval.Version
+			err = c.apply(ctx, p, res.Returnval)
+		}
+
+		if err != nil {
+			if ctx.Err() != nil {
+				err = p.CancelWaitForUpdates(context.Background())
+			}
+
+			c.mu.Lock()
+			c.running = false
+			c.err = err
+			c.objects = make(map[types.ManagedObjectReference]*cacheEntry)
+			c.mu.Unlock()
+
+			return
+		}
+	}
+}
+
+// property returns the name of the cached property that contains the given property change name.
+func (c *Cache) property(kind string, name string) string {
+	for _, path := range c.specs[kind].PathSet {
+		if name == path || strings.HasPrefix(name, path+".") || strings.HasPrefix(name, path+"[") {
+			return path
+		}
+	}
+
+	if i := strings.IndexAny(name, ".["); i > 0 {
+		return name[:i]
+	}
+
+	return name
+}
+
+// apply the given updates to the cache. Changes that apply to part of a cached property,
+// such as an element of an array property, are applied by retrieving the entire property.
+func (c *Cache) apply(ctx context.Context, p *Collector, set *types.UpdateSet) error {
+	refresh := make(map[types.ManagedObjectReference]map[string]bool)
+
+	c.mu.Lock()
+	for _, fs := range set.FilterSet {
+		for _, update := range fs.ObjectSet {
+			if update.Kind == types.ObjectUpdateKindLeave {
+				delete(c.objects, update.Obj)
+				continue
+			}
+
+			e, ok := c.objects[update.Obj]
+			if !ok || update.Kind == types.ObjectUpdateKindEnter {
+				e = &cacheEntry{
+					props:   make(map[string]types.AnyType),
+					missing: make(map[string]types.MissingProperty),
+				}
+				c.objects[update.Obj] = e
+			}
+
+			for _, change := range update.ChangeSet {
+				name := c.property(update.Obj.Type, change.Name)
+				if name != change.Name {
+					if refresh[update.Obj] == nil {
+						refresh[update.Obj] = make(map[string]bool)
+					}
+					refresh[update.Obj][name] = true
+					continue
+				}
+
+				delete(e.missing, name)
+
+				switch change.Op {
+				case types.PropertyChangeOpAssign, types.PropertyChangeOpAdd:
+					e.props[name] = change.Val
+				default:
+					delete(e.props, name)
+				}
+			}
+
+			for _, m := range update.MissingSet {
+				delete(e.props, m.Path)
+				e.missing[m.Path] = m
+			}
+		}
+	}
+	c.mu.Unlock()
+
+	for obj, names := range refresh {
+		var ps []string
+		for name := range names {
+			ps = append(ps, name)
+		}
+
+		var content []types.ObjectContent
+		if err := p.Retrieve(ctx, []types.ManagedObjectReference{obj}, ps, &content); err != nil {
+			return err
+		}
+
+		c.mu.Lock()
+		if e, ok := c.objects[obj]; ok {
+			for _, name := range ps {
+				delete(e.props, name)
+				delete(e.missing, name)
+			}
+			for _, o := range content {
+				for _, prop := range o.PropSet {
+					e.props[prop.Name] = prop.Val
+				}
+				for _, m := range o.MissingSet {
+					e.missing[m.Path] = m
+				}
+			}
+		}
+		c.mu.Unlock()
+	}
+
+	return nil
+}
+
+// content returns the cached ObjectContent for the given objects and properties,
+// or false if any of the objects or properties are not covered by the cache.
+func (c *Cache) content(objs []types.ManagedObjectReference, ps []string) ([]types.ObjectContent, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if !c.running {
+		return nil, false
+	}
+
+	content := make([]types.ObjectContent, 0, len(objs))
+
+	for _, obj := range objs {
+		e, ok := c.objects[obj]
+		if !ok {
+			return nil, false
+		}
+
+		spec := c.specs[obj.Type]
+		all := spec.All != nil && *spec.All
+
+		names := ps
+		if names == nil {
+			if !all {
+				return nil, false
+			}
+			for name := range e.props {
+				names = append(names, name)
+			}
+			for name := range e.missing {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+		} else {
+			for _, name := range names {
+				if c.property(obj.Type, name) != name {
+					return nil, false // nested property of a cached property
+				}
+				if !all && !contains(spec.PathSet, name) {
+					return nil, false
+				}
+			}
+		}
+
+		o := types.ObjectContent{Obj: obj}
+		for _, name := range names {
+			if val, ok := e.props[name]; ok {
+				o.PropSet = append(o.PropSet, types.DynamicProperty{Name: name, Val: val})
+			}
+			if m, ok := e.missing[name]; ok {
+				o.MissingSet = append(o.MissingSet, m)
+			}
+		}
+		content = append(content, o)
+	}
+
+	return content, true
+}
+
+func contains(s []string, name string) bool {
+	for i := range s {
+		if s[i] == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Retrieve is the same as Collector.Retrieve, served from the cache if possible.
+func (c *Cache) Retrieve(ctx context.Context, objs []types.ManagedObjectReference, ps []string, dst interface{}) error {
+	content, ok := c.content(objs, ps)
+	if !ok || len(objs) == 0 {
+		return c.collector.Retrieve(ctx, objs, ps, dst)
+	}
+
+	if d, ok := dst.(*[]types.ObjectContent); ok {
+		*d = content
+		return nil
+	}
+
+	return mo.LoadObjectContent(content, dst)
+}
+
+// RetrieveOne is the same as Collector.RetrieveOne, served from the cache if possible.
+func (c *Cache) RetrieveOne(ctx context.Context, obj types.ManagedObjectReference, ps []string, dst interface{}) error {
+	return c.Retrieve(ctx, []types.ManagedObjectReference{obj}, ps, dst)
+}
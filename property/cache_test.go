@@ -0,0 +1,128 @@
+/*
+Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package property_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/view"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+func TestCache(t *testing.T) {
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		vm, err := find.NewFinder(c).VirtualMachine(ctx, "DC0_H0_VM0")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var retrieved int32
+		c.Client.RoundTripHook = func(_ context.Context, info soap.RoundTripInfo) {
+			if info.Method == "RetrieveProperties" {
+				atomic.AddInt32(&retrieved, 1)
+			}
+		}
+
+		m := view.NewManager(c)
+		v, err := m.CreateContainerView(ctx, c.ServiceContent.RootFolder, []string{"VirtualMachine"}, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ps := []string{"name", "runtime.powerState"}
+		filter := new(property.WaitFilter).Add(v.Reference(), "VirtualMachine", ps, &types.TraversalSpec{
+			Type: "ContainerView",
+			Path: "view",
+		})
+		filter.Spec.ObjectSet[0].Skip = types.NewBool(true)
+
+		pc := property.DefaultCollector(c)
+		cache := property.NewCache(pc, filter)
+		if err = cache.Start(ctx); err != nil {
+			t.Fatal(err)
+		}
+		defer cache.Stop()
+
+		var o mo.VirtualMachine
+		if err = cache.RetrieveOne(ctx, vm.Reference(), ps, &o); err != nil {
+			t.Fatal(err)
+		}
+		if o.Name != vm.Name() || o.Runtime.PowerState != types.VirtualMachinePowerStatePoweredOn {
+			t.Errorf("name=%s state=%s", o.Name, o.Runtime.PowerState)
+		}
+		if n := atomic.LoadInt32(&retrieved); n != 0 {
+			t.Errorf("retrieved=%d", n)
+		}
+
+		var vms []mo.VirtualMachine
+		if err = cache.Retrieve(ctx, []types.ManagedObjectReference{vm.Reference()}, []string{"config.guestId"}, &vms); err != nil {
+			t.Fatal(err)
+		}
+		if len(vms) != 1 || vms[0].Config.GuestId == "" {
+			t.Errorf("vms=%#v", vms)
+		}
+		if n := atomic.LoadInt32(&retrieved); n != 1 {
+			t.Errorf("retrieved=%d", n) // not covered by the cache
+		}
+
+		task, err := vm.PowerOff(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = task.Wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		for i := 0; ; i++ {
+			if err = cache.RetrieveOne(ctx, vm.Reference(), []string{"runtime.powerState"}, &o); err != nil {
+				t.Fatal(err)
+			}
+			if o.Runtime.PowerState == types.VirtualMachinePowerStatePoweredOff {
+				break
+			}
+			if i == 100 {
+				t.Fatalf("state=%s", o.Runtime.PowerState)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+
+		if n := atomic.LoadInt32(&retrieved); n != 1 {
+			t.Errorf("retrieved=%d", n)
+		}
+
+		cache.Stop()
+		if err = cache.Err(); err != nil {
+			t.Error(err)
+		}
+
+		if err = cache.RetrieveOne(ctx, vm.Reference(), ps, &o); err != nil {
+			t.Fatal(err)
+		}
+		if n := atomic.LoadInt32(&retrieved); n != 2 {
+			t.Errorf("retrieved=%d", n) // stopped cache forwards to the collector
+		}
+	})
+}
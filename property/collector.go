@@ -122,17 +122,8 @@ func (p *Collector) RetrieveProperties(ctx context.Context, req types.RetrievePr
 	return methods.RetrieveProperties(ctx, p.roundTripper, &req)
 }
 
-// Retrieve loads properties for a slice of managed objects. The dst argument
-// must be a pointer to a []interface{}, which is populated with the instances
-// of the specified managed objects, with the relevant properties filled in. If
-// the properties slice is nil, all properties are loaded.
-// Note that pointer types are optional fields that may be left as a nil value.
-// The caller should check such fields for a nil value before dereferencing.
-func (p *Collector) Retrieve(ctx context.Context, objs []types.ManagedObjectReference, ps []string, dst interface{}) error {
-	if len(objs) == 0 {
-		return errors.New("object references is empty")
-	}
-
+// retrieveSpec returns a PropertyFilterSpec for the properties ps of objs, or all properties if ps is nil.
+func retrieveSpec(objs []types.ManagedObjectReference, ps []string) types.PropertyFilterSpec {
 	kinds := make(map[string]bool)
 
 	var propSet []types.PropertySpec
@@ -160,13 +151,25 @@ func (p *Collector) Retrieve(ctx context.Context, objs []types.ManagedObjectRefe
 		objectSet = append(objectSet, objectSpec)
 	}
 
+	return types.PropertyFilterSpec{
+		ObjectSet: objectSet,
+		PropSet:   propSet,
+	}
+}
+
+// Retrieve loads properties for a slice of managed objects. The dst argument
+// must be a pointer to a []interface{}, which is populated with the instances
+// of the specified managed objects, with the relevant properties filled in. If
+// the properties slice is nil, all properties are loaded.
+// Note that pointer types are optional fields that may be left as a nil value.
+// The caller should check such fields for a nil value before dereferencing.
+func (p *Collector) Retrieve(ctx context.Context, objs []types.ManagedObjectReference, ps []string, dst interface{}) error {
+	if len(objs) == 0 {
+		return errors.New("object references is empty")
+	}
+
 	req := types.RetrieveProperties{
-		SpecSet: []types.PropertyFilterSpec{
-			{
-				ObjectSet: objectSet,
-				PropSet:   propSet,
-			},
-		},
+		SpecSet: []types.PropertyFilterSpec{retrieveSpec(objs, ps)},
 	}
 
 	res, err := p.RetrieveProperties(ctx, req)
@@ -23,8 +23,10 @@ import (
 	"github.com/vmware/govmomi/find"
 	"github.com/vmware/govmomi/property"
 	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/view"
 	"github.com/vmware/govmomi/vim25"
 	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
 )
 
 // Example to retrieve properties from a single object
@@ -48,3 +50,86 @@ func ExampleCollector_RetrieveOne() {
 	})
 	// Output: hardware version vmx-13
 }
+
+// Example to retrieve properties of all VMs, one page at a time
+func ExampleCollector_RetrievePages() {
+	simulator.Run(func(ctx context.Context, c *vim25.Client) error {
+		pc := property.DefaultCollector(c)
+
+		v, err := view.NewManager(c).CreateContainerView(ctx, c.ServiceContent.RootFolder, []string{"VirtualMachine"}, true)
+		if err != nil {
+			return err
+		}
+		defer v.Destroy(ctx)
+
+		req := types.RetrievePropertiesEx{
+			SpecSet: []types.PropertyFilterSpec{{
+				ObjectSet: []types.ObjectSpec{{
+					Obj:  v.Reference(),
+					Skip: types.NewBool(true),
+					SelectSet: []types.BaseSelectionSpec{&types.TraversalSpec{
+						Type: "ContainerView",
+						Path: "view",
+					}},
+				}},
+				PropSet: []types.PropertySpec{{Type: "VirtualMachine", PathSet: []string{"name"}}},
+			}},
+			Options: types.RetrieveOptions{MaxObjects: 3},
+		}
+
+		pages := pc.RetrievePages(req)
+		defer pages.Close(ctx)
+
+		for pages.Next(ctx) {
+			var vms []mo.VirtualMachine
+			if err = pages.Load(&vms); err != nil {
+				return err
+			}
+			fmt.Printf("page of %d VMs\n", len(vms))
+		}
+
+		return pages.Err()
+	})
+	// Output:
+	// page of 3 VMs
+	// page of 1 VMs
+}
+
+// Example to build a PropertyFilterSpec that traverses from a cluster to the VMs on its hosts
+func ExampleSpecBuilder() {
+	simulator.Run(func(ctx context.Context, c *vim25.Client) error {
+		pc := property.DefaultCollector(c)
+
+		cluster, err := find.NewFinder(c).ClusterComputeResource(ctx, "DC0_C0")
+		if err != nil {
+			return err
+		}
+
+		spec, err := property.NewSpecBuilder(cluster.Reference()).
+			Skip().
+			Traverse("ClusterComputeResource.host", "HostSystem.vm").
+			Properties("VirtualMachine", "name").
+			Build()
+		if err != nil {
+			return err
+		}
+
+		res, err := pc.RetrieveProperties(ctx, types.RetrieveProperties{SpecSet: []types.PropertyFilterSpec{spec}})
+		if err != nil {
+			return err
+		}
+
+		var vms []mo.VirtualMachine
+		if err = mo.LoadObjectContent(res.Returnval, &vms); err != nil {
+			return err
+		}
+
+		for _, vm := range vms {
+			fmt.Println(vm.Name)
+		}
+		return nil
+	})
+	// Unordered output:
+	// DC0_C0_RP0_VM0
+	// DC0_C0_RP0_VM1
+}
@@ -0,0 +1,96 @@
+/*
+Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package property
+
+import (
+	"context"
+	"reflect"
+	"sync"
+
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// ObjectMap maintains a map of typed managed objects, such as mo.VirtualMachine,
+// applying the enter, modify and leave changes of each ObjectUpdate to them.
+// Changes to indexed properties, such as "config.hardware.device[4000]", are ignored,
+// as such changes cannot be applied to the typed objects.
+type ObjectMap struct {
+	mu      sync.Mutex
+	objects map[types.ManagedObjectReference]reflect.Value
+}
+
+// NewObjectMap creates an empty ObjectMap.
+func NewObjectMap() *ObjectMap {
+	return &ObjectMap{
+		objects: make(map[types.ManagedObjectReference]reflect.Value),
+	}
+}
+
+// Apply applies the given update and returns the updated object.
+// For ObjectUpdateKindLeave, the object is removed from the map and its last known state is returned.
+// The returned object is a shallow copy: it must not be modified and its pointer fields
+// may be updated by subsequent calls to Apply.
+func (m *ObjectMap) Apply(update types.ObjectUpdate) mo.Reference {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	obj, ok := m.objects[update.Obj]
+	if !ok {
+		val, _ := mo.ObjectContentToType(types.ObjectContent{Obj: update.Obj})
+		obj = reflect.New(reflect.TypeOf(val))
+		obj.Elem().Set(reflect.ValueOf(val))
+	}
+
+	if update.Kind == types.ObjectUpdateKindLeave {
+		delete(m.objects, update.Obj)
+	} else {
+		mo.ApplyPropertyChange(obj.Interface().(mo.Reference), update.ChangeSet)
+		m.objects[update.Obj] = obj
+	}
+
+	return obj.Elem().Interface().(mo.Reference)
+}
+
+// Get returns the object with the given reference, or nil if it is not in the map.
+// The same restrictions apply to the returned object as those returned by Apply.
+func (m *ObjectMap) Get(ref types.ManagedObjectReference) mo.Reference {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	obj, ok := m.objects[ref]
+	if !ok {
+		return nil
+	}
+
+	return obj.Elem().Interface().(mo.Reference)
+}
+
+// WaitForUpdates is a variant of the package level WaitForUpdates function, that applies each ObjectUpdate
+// to the map and calls f with the update kind and the updated object, such as mo.VirtualMachine.
+// If f returns true, it stops waiting and returns.
+func (m *ObjectMap) WaitForUpdates(ctx context.Context, c *Collector, filter *WaitFilter, f func(types.ObjectUpdateKind, mo.Reference) bool) error {
+	return WaitForUpdates(ctx, c, filter, func(updates []types.ObjectUpdate) bool {
+		for _, update := range updates {
+			if f(update.Kind, m.Apply(update)) {
+				return true
+			}
+		}
+
+		return false
+	})
+}
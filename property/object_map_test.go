@@ -0,0 +1,89 @@
+/*
+Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package property_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+func TestObjectMap(t *testing.T) {
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		vm, err := find.NewFinder(c).VirtualMachine(ctx, "DC0_H0_VM0")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		m := property.NewObjectMap()
+		pc := property.DefaultCollector(c)
+		filter := new(property.WaitFilter).Add(vm.Reference(), vm.Reference().Type, []string{"name", "runtime.powerState"})
+
+		var states []types.VirtualMachinePowerState
+
+		err = m.WaitForUpdates(ctx, pc, filter, func(kind types.ObjectUpdateKind, obj mo.Reference) bool {
+			o, ok := obj.(mo.VirtualMachine)
+			if !ok {
+				t.Fatalf("unexpected type %T", obj)
+			}
+			if o.Name != vm.Name() {
+				t.Errorf("name=%q", o.Name)
+			}
+
+			states = append(states, o.Runtime.PowerState)
+
+			if kind == types.ObjectUpdateKindEnter {
+				task, err := vm.PowerOff(ctx)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err = task.Wait(ctx); err != nil {
+					t.Fatal(err)
+				}
+				return false
+			}
+
+			return true
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(states) != 2 || states[0] != types.VirtualMachinePowerStatePoweredOn || states[1] != types.VirtualMachinePowerStatePoweredOff {
+			t.Errorf("states=%v", states)
+		}
+
+		obj := m.Get(vm.Reference()).(mo.VirtualMachine)
+		if obj.Runtime.PowerState != types.VirtualMachinePowerStatePoweredOff {
+			t.Errorf("state=%s", obj.Runtime.PowerState)
+		}
+
+		obj = m.Apply(types.ObjectUpdate{Kind: types.ObjectUpdateKindLeave, Obj: vm.Reference()}).(mo.VirtualMachine)
+		if obj.Name != vm.Name() {
+			t.Errorf("name=%q", obj.Name)
+		}
+		if m.Get(vm.Reference()) != nil {
+			t.Error("object should have been removed")
+		}
+	})
+}
@@ -0,0 +1,138 @@
+/*
+Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package property
+
+import (
+	"context"
+
+	"github.com/vmware/govmomi/vim25/methods"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// Pages iterates over the results of a RetrievePropertiesEx request one page at a time,
+// following continuation tokens via ContinueRetrievePropertiesEx.
+// The size of each page is bounded by the request's RetrieveOptions.MaxObjects,
+// allowing callers to process large inventories with bounded memory.
+type Pages struct {
+	collector *Collector
+	req       types.RetrievePropertiesEx
+
+	started bool
+	token   string
+	page    []types.ObjectContent
+	err     error
+}
+
+// RetrievePages returns a Pages iterator for the given request, no request is sent until Pages.Next is called.
+func (p *Collector) RetrievePages(req types.RetrievePropertiesEx) *Pages {
+	req.This = p.Reference()
+
+	return &Pages{
+		collector: p,
+		req:       req,
+	}
+}
+
+// Next retrieves the next page of results, returning false when there are no more results or an error occurs.
+func (p *Pages) Next(ctx context.Context) bool {
+	p.page = nil
+
+	for p.err == nil {
+		var res *types.RetrieveResult
+
+		if !p.started {
+			p.started = true
+
+			r, err := methods.RetrievePropertiesEx(ctx, p.collector.roundTripper, &p.req)
+			if err != nil {
+				p.err = err
+				return false
+			}
+			res = r.Returnval
+		} else {
+			if p.token == "" {
+				return false
+			}
+
+			req := types.ContinueRetrievePropertiesEx{
+				This:  p.req.This,
+				Token: p.token,
+			}
+
+			r, err := methods.ContinueRetrievePropertiesEx(ctx, p.collector.roundTripper, &req)
+			if err != nil {
+				p.err = err
+				return false
+			}
+			res = &r.Returnval
+		}
+
+		if res == nil {
+			p.token = ""
+			return false
+		}
+
+		p.token = res.Token
+
+		if len(res.Objects) != 0 {
+			p.page = res.Objects
+			return true
+		}
+	}
+
+	return false
+}
+
+// Page returns the current page of results, as retrieved by the most recent call to Next.
+func (p *Pages) Page() []types.ObjectContent {
+	return p.page
+}
+
+// Load populates dst with the current page of results, as Collector.Retrieve does.
+func (p *Pages) Load(dst interface{}) error {
+	if d, ok := dst.(*[]types.ObjectContent); ok {
+		*d = p.page
+		return nil
+	}
+
+	return mo.LoadObjectContent(p.page, dst)
+}
+
+// Err returns the first error encountered by Next, if any.
+func (p *Pages) Err() error {
+	return p.err
+}
+
+// Close cancels retrieval of any remaining pages, releasing the server side resources.
+// Close must be called if the iterator is abandoned before Next returns false.
+func (p *Pages) Close(ctx context.Context) error {
+	if p.token == "" {
+		return nil
+	}
+
+	req := types.CancelRetrievePropertiesEx{
+		This:  p.req.This,
+		Token: p.token,
+	}
+
+	p.token = ""
+	p.page = nil
+
+	_, err := methods.CancelRetrievePropertiesEx(ctx, p.collector.roundTripper, &req)
+	return err
+}
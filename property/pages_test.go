@@ -0,0 +1,95 @@
+/*
+Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package property_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/methods"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+func TestRetrievePages(t *testing.T) {
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		pc := property.DefaultCollector(c)
+		vms := simulator.Map.All("VirtualMachine")
+
+		req := types.RetrievePropertiesEx{
+			SpecSet: []types.PropertyFilterSpec{{
+				PropSet: []types.PropertySpec{{Type: "VirtualMachine", PathSet: []string{"name"}}},
+			}},
+			Options: types.RetrieveOptions{MaxObjects: 1},
+		}
+		for _, vm := range vms {
+			req.SpecSet[0].ObjectSet = append(req.SpecSet[0].ObjectSet, types.ObjectSpec{Obj: vm.Reference()})
+		}
+
+		pages := pc.RetrievePages(req)
+		n := 0
+		for pages.Next(ctx) {
+			if len(pages.Page()) != 1 {
+				t.Errorf("page size=%d", len(pages.Page()))
+			}
+			n++
+		}
+		if err := pages.Err(); err != nil {
+			t.Fatal(err)
+		}
+		if n != len(vms) {
+			t.Errorf("pages=%d, vms=%d", n, len(vms))
+		}
+		if err := pages.Close(ctx); err != nil {
+			t.Error(err)
+		}
+
+		// Cancel releases the remaining pages, such that the token is no longer valid
+		res, err := methods.RetrievePropertiesEx(ctx, c, &types.RetrievePropertiesEx{
+			This:    pc.Reference(),
+			SpecSet: req.SpecSet,
+			Options: req.Options,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		token := res.Returnval.Token
+
+		_, err = methods.CancelRetrievePropertiesEx(ctx, c, &types.CancelRetrievePropertiesEx{This: pc.Reference(), Token: token})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = methods.ContinueRetrievePropertiesEx(ctx, c, &types.ContinueRetrievePropertiesEx{This: pc.Reference(), Token: token})
+		if err == nil {
+			t.Error("expected error")
+		}
+
+		pages = pc.RetrievePages(req)
+		if !pages.Next(ctx) {
+			t.Fatal(pages.Err())
+		}
+		if err = pages.Close(ctx); err != nil {
+			t.Error(err)
+		}
+		if pages.Next(ctx) {
+			t.Error("expected no more pages after Close")
+		}
+	})
+}
@@ -0,0 +1,211 @@
+/*
+Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package property
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/methods"
+	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// ResumePolicy configures error recovery for WaitForUpdatesResume.
+type ResumePolicy struct {
+	// MaxAttempts is the number of consecutive failed attempts before giving up.
+	// Values less than 1 retry until the Context is canceled.
+	MaxAttempts int
+
+	// Retryable reports whether WaitForUpdatesResume should recover from err.
+	// Defaults to ResumableError when nil.
+	Retryable func(err error) bool
+
+	// Backoff returns the delay before the given retry attempt (starting at 1).
+	// Defaults to no delay when nil.
+	Backoff func(attempt int) time.Duration
+}
+
+// ResumableError returns true for the errors vim25.TransientError returns true for, as well as
+// network errors such as connection refused, ManagedObjectNotFound faults, which are returned once the
+// session that owned the property collector is gone, and InvalidCollectorVersion faults.
+func ResumableError(err error) bool {
+	if vim25.TransientError(err) {
+		return true
+	}
+
+	if soap.IsSoapFault(err) {
+		switch soap.ToSoapFault(err).VimFault().(type) {
+		case types.ManagedObjectNotFound, *types.ManagedObjectNotFound,
+			types.InvalidCollectorVersion, *types.InvalidCollectorVersion:
+			return true
+		}
+		return false
+	}
+
+	if soap.IsRegularError(err) {
+		err = soap.ToRegularError(err)
+	}
+
+	_, ok := err.(net.Error)
+	return ok
+}
+
+// resumeAction returns whether the filter and/or version must be discarded to recover from err.
+func resumeAction(err error) (recreate bool, resync bool) {
+	if soap.IsSoapFault(err) {
+		switch soap.ToSoapFault(err).VimFault().(type) {
+		case types.InvalidCollectorVersion, *types.InvalidCollectorVersion:
+			return false, true
+		case types.ManagedObjectNotFound, *types.ManagedObjectNotFound,
+			types.NotAuthenticated, *types.NotAuthenticated:
+			return true, true
+		}
+	}
+
+	return false, false
+}
+
+// WaitForUpdatesResume is a variant of WaitForUpdates that recovers from connection failures and
+// session re-establishment, as configured by the given ResumePolicy.
+// After a connection failure, waiting resumes from the last UpdateSet version received.
+// If the property collector or its filter no longer exist, for example when the session has expired and
+// was re-established by a session.KeepAliveHandler, both are created again. If the collector version is no
+// longer valid, waiting starts over from an empty version.
+// In both cases the server sends the full state of all objects matching the filter, without notice of
+// objects that may have been removed in the meantime. The resync param of f is true for the first
+// update after such a full resync occurred, such that the caller can discard any state it has accumulated.
+func WaitForUpdatesResume(ctx context.Context, c *Collector, filter *WaitFilter, policy ResumePolicy, f func(updates []types.ObjectUpdate, resync bool) bool) error {
+	var p *Collector
+
+	// Attempt to destroy the collector using the background context, as the
+	// specified context may have timed out or have been canceled.
+	destroy := func() {
+		if p != nil {
+			_ = p.Destroy(context.Background())
+			p = nil
+		}
+	}
+	defer destroy()
+
+	retryable := policy.Retryable
+	if retryable == nil {
+		retryable = ResumableError
+	}
+
+	var version string
+	created, resync := false, false
+
+	for attempt := 0; ; {
+		var err error
+
+		if p == nil {
+			p, err = c.Create(ctx)
+			if err == nil {
+				err = p.CreateFilter(ctx, filter.CreateFilter)
+				if err != nil {
+					destroy()
+				}
+			}
+			if err == nil {
+				resync = created
+				created = true
+				version = ""
+			}
+		}
+
+		var set *types.UpdateSet
+
+		if err == nil {
+			var res *types.WaitForUpdatesExResponse
+			res, err = methods.WaitForUpdatesEx(ctx, p.roundTripper, &types.WaitForUpdatesEx{
+				This:    p.Reference(),
+				Version: version,
+				Options: filter.Options,
+			})
+			if err == nil {
+				set = res.Returnval
+			}
+		}
+
+		if err != nil {
+			if ctx.Err() == context.Canceled && p != nil {
+				return p.CancelWaitForUpdates(context.Background())
+			}
+			if ctx.Err() != nil {
+				return err
+			}
+
+			attempt++
+			if !retryable(err) || (policy.MaxAttempts > 0 && attempt >= policy.MaxAttempts) {
+				return err
+			}
+
+			recreate, reset := resumeAction(err)
+			if recreate {
+				destroy()
+			}
+			if reset {
+				version = ""
+				resync = true
+			}
+
+			var delay time.Duration
+			if policy.Backoff != nil {
+				delay = policy.Backoff(attempt)
+			}
+
+			select {
+			case <-ctx.Done():
+				return err
+			case <-time.After(delay):
+			}
+
+			continue
+		}
+
+		attempt = 0
+
+		if set == nil {
+			if filter.Options != nil && filter.Options.MaxWaitSeconds != nil {
+				return nil // WaitOptions.MaxWaitSeconds exceeded
+			}
+			// Retry if the result came back empty
+			continue
+		}
+
+		version = set.Version
+
+		for _, fs := range set.FilterSet {
+			if filter.PropagateMissing {
+				for i := range fs.ObjectSet {
+					for _, p := range fs.ObjectSet[i].MissingSet {
+						// Same behavior as mo.ObjectContentToType()
+						return soap.WrapVimFault(p.Fault.Fault)
+					}
+				}
+			}
+
+			if f(fs.ObjectSet, resync) {
+				return nil
+			}
+			resync = false
+		}
+	}
+}
@@ -0,0 +1,134 @@
+/*
+Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package property_test
+
+import (
+	"context"
+	"net"
+	"syscall"
+	"testing"
+
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/session"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// dropOnce fails the next WaitForUpdatesEx call with a network error when drop is set
+type dropOnce struct {
+	soap.RoundTripper
+	drop bool
+}
+
+func (d *dropOnce) RoundTrip(ctx context.Context, req, res soap.HasFault) error {
+	if d.drop && soap.MethodName(req) == "WaitForUpdatesEx" {
+		d.drop = false
+		return &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}
+	}
+	return d.RoundTripper.RoundTrip(ctx, req, res)
+}
+
+func TestWaitForUpdatesResume(t *testing.T) {
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		vm, err := find.NewFinder(c).VirtualMachine(ctx, "DC0_H0_VM0")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rt := &dropOnce{RoundTripper: c.RoundTripper}
+		c.RoundTripper = rt
+
+		filter := new(property.WaitFilter).Add(vm.Reference(), "VirtualMachine", []string{"runtime.powerState"})
+		sm := session.NewManager(c)
+
+		var kinds []types.ObjectUpdateKind
+		var resyncs []bool
+
+		err = property.WaitForUpdatesResume(ctx, property.DefaultCollector(c), filter, property.ResumePolicy{MaxAttempts: 3},
+			func(updates []types.ObjectUpdate, resync bool) bool {
+				for _, update := range updates {
+					kinds = append(kinds, update.Kind)
+					resyncs = append(resyncs, resync)
+				}
+
+				switch len(kinds) {
+				case 1:
+					// connection drop: resume from the current version
+					rt.drop = true
+					if _, err := vm.PowerOff(ctx); err != nil {
+						t.Fatal(err)
+					}
+				case 2:
+					// session loss: the collector and filter must be re-created
+					if err := sm.Logout(ctx); err != nil {
+						t.Fatal(err)
+					}
+					if err := sm.Login(ctx, simulator.DefaultLogin); err != nil {
+						t.Fatal(err)
+					}
+				default:
+					return true
+				}
+
+				return false
+			})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expect := []types.ObjectUpdateKind{types.ObjectUpdateKindEnter, types.ObjectUpdateKindModify, types.ObjectUpdateKindEnter}
+		for i := range expect {
+			if i >= len(kinds) || kinds[i] != expect[i] {
+				t.Fatalf("kinds=%v", kinds)
+			}
+		}
+		if resyncs[0] || resyncs[1] || !resyncs[2] {
+			t.Errorf("resyncs=%v", resyncs)
+		}
+	})
+}
+
+func TestWaitForUpdatesResumeMaxAttempts(t *testing.T) {
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		filter := new(property.WaitFilter).Add(c.ServiceContent.RootFolder, "Folder", []string{"name"})
+
+		calls := 0
+		policy := property.ResumePolicy{
+			MaxAttempts: 2,
+			Retryable: func(err error) bool {
+				calls++
+				return true
+			},
+		}
+
+		if err := session.NewManager(c).Logout(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		err := property.WaitForUpdatesResume(ctx, property.DefaultCollector(c), filter, policy,
+			func([]types.ObjectUpdate, bool) bool { return true })
+		if err == nil {
+			t.Fatal("expected error")
+		}
+		if calls != 2 {
+			t.Errorf("calls=%d", calls)
+		}
+	})
+}
@@ -0,0 +1,111 @@
+/*
+Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package property_test
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/methods"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+func TestRetrieveSharded(t *testing.T) {
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		pc := property.DefaultCollector(c)
+
+		var objs []types.ManagedObjectReference
+		for _, kind := range []string{"VirtualMachine", "HostSystem", "Datastore"} {
+			for _, obj := range simulator.Map.All(kind) {
+				objs = append(objs, obj.Reference())
+			}
+		}
+
+		var expect []mo.ManagedEntity
+		if err := pc.Retrieve(ctx, objs, []string{"name"}, &expect); err != nil {
+			t.Fatal(err)
+		}
+
+		for _, opts := range []property.ShardOptions{{}, {Concurrency: 1}, {Concurrency: 3, Size: 2}, {Size: len(objs) * 2}} {
+			var entities []mo.ManagedEntity
+			if err := pc.RetrieveSharded(ctx, objs, []string{"name"}, &entities, opts); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(entities, expect) {
+				t.Errorf("%#v: %d entities, expected %d", opts, len(entities), len(expect))
+			}
+		}
+
+		// any shard failure fails the entire retrieval
+		objs = append(objs, types.ManagedObjectReference{Type: "VirtualMachine", Value: "enoent"})
+		var entities []mo.ManagedEntity
+		err := pc.RetrieveSharded(ctx, objs, []string{"name"}, &entities, property.ShardOptions{Size: 1})
+		if err == nil {
+			t.Error("expected error")
+		}
+	})
+}
+
+// serverLatency models the server side cost of RetrievePropertiesEx calls, which is proportional to the
+// number of objects in the request and serialized per call.
+type serverLatency struct {
+	soap.RoundTripper
+	perObject time.Duration
+}
+
+func (l *serverLatency) RoundTrip(ctx context.Context, req, res soap.HasFault) error {
+	if r, ok := req.(*methods.RetrievePropertiesExBody); ok {
+		time.Sleep(time.Duration(len(r.Req.SpecSet[0].ObjectSet)) * l.perObject)
+	}
+	return l.RoundTripper.RoundTrip(ctx, req, res)
+}
+
+func BenchmarkRetrieveSharded(b *testing.B) {
+	model := simulator.VPX()
+	model.Machine = 100 // 200 VMs
+
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		c.RoundTripper = &serverLatency{RoundTripper: c.RoundTripper, perObject: time.Millisecond}
+		pc := property.DefaultCollector(c)
+
+		var objs []types.ManagedObjectReference
+		for _, vm := range simulator.Map.All("VirtualMachine") {
+			objs = append(objs, vm.Reference())
+		}
+
+		for _, concurrency := range []int{1, 2, 4, 8} {
+			b.Run(fmt.Sprintf("concurrency=%d", concurrency), func(b *testing.B) {
+				opts := property.ShardOptions{Concurrency: concurrency}
+
+				for i := 0; i < b.N; i++ {
+					var content []types.ObjectContent
+					if err := pc.RetrieveSharded(ctx, objs, []string{"name", "runtime.powerState"}, &content, opts); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}, model)
+}
@@ -0,0 +1,187 @@
+/*
+Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package property
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// SpecBuilder is a fluent builder for types.PropertyFilterSpec, for use with RetrieveProperties or CreateFilter.
+// The managed object types and properties given to the builder are validated when Build is called.
+type SpecBuilder struct {
+	obj       types.ManagedObjectReference
+	skip      bool
+	selectSet []types.BaseSelectionSpec
+	propSet   []types.PropertySpec
+	err       error
+}
+
+// NewSpecBuilder returns a SpecBuilder that starts collection from the given object.
+func NewSpecBuilder(obj types.ManagedObjectReference) *SpecBuilder {
+	return &SpecBuilder{obj: obj}
+}
+
+func (b *SpecBuilder) fail(format string, args ...interface{}) *SpecBuilder {
+	if b.err == nil {
+		b.err = fmt.Errorf(format, args...)
+	}
+	return b
+}
+
+// Skip excludes the starting object from the results, such that only objects found via traversal are collected.
+func (b *SpecBuilder) Skip() *SpecBuilder {
+	b.skip = true
+	return b
+}
+
+// Traverse adds a traversal path from the starting object, where each element of path is in the form "Type.property",
+// and each Type is that of the objects referenced by the property of the previous element.
+// For example, to traverse from a cluster to the VMs of its hosts: Traverse("ClusterComputeResource.host", "HostSystem.vm")
+// Objects found at each step of the path are collected if Properties are specified for their type.
+func (b *SpecBuilder) Traverse(path ...string) *SpecBuilder {
+	if len(path) == 0 {
+		return b.fail("empty traversal path")
+	}
+
+	var specs []*types.TraversalSpec
+
+	for i, elem := range path {
+		parts := strings.SplitN(elem, ".", 2)
+		if len(parts) != 2 || !mo.IsProperty(parts[0], parts[1]) {
+			return b.fail("invalid traversal %q: expected a Type.property reference", elem)
+		}
+		if i == 0 && b.obj.Type != "" && !mo.IsProperty(b.obj.Type, parts[1]) {
+			return b.fail("invalid traversal %q: %s has no %q property", elem, b.obj.Type, parts[1])
+		}
+
+		specs = append(specs, &types.TraversalSpec{
+			Type: parts[0],
+			Path: parts[1],
+			Skip: types.NewBool(false),
+		})
+	}
+
+	for i := len(specs) - 1; i > 0; i-- {
+		specs[i-1].SelectSet = []types.BaseSelectionSpec{specs[i]}
+	}
+
+	b.selectSet = append(b.selectSet, specs[0])
+
+	return b
+}
+
+// TraverseInventory adds traversal of the entire inventory tree below the starting object, such as the root Folder,
+// a Datacenter, ComputeResource or ResourcePool. All folders, datacenters, compute resources, hosts, resource pools,
+// VMs, datastores and networks are found, collected if Properties are specified for their type.
+func (b *SpecBuilder) TraverseInventory() *SpecBuilder {
+	const (
+		folder = "inventoryFolder"
+		pool   = "inventoryResourcePool"
+		poolVM = "inventoryResourcePoolVM"
+	)
+
+	sel := func(names ...string) []types.BaseSelectionSpec {
+		var specs []types.BaseSelectionSpec
+		for _, name := range names {
+			specs = append(specs, &types.SelectionSpec{Name: name})
+		}
+		return specs
+	}
+
+	ts := func(name, kind, path string, set []types.BaseSelectionSpec) *types.TraversalSpec {
+		return &types.TraversalSpec{
+			SelectionSpec: types.SelectionSpec{Name: name},
+			Type:          kind,
+			Path:          path,
+			Skip:          types.NewBool(false),
+			SelectSet:     set,
+		}
+	}
+
+	datacenter := []string{"vmFolder", "hostFolder", "datastoreFolder", "networkFolder"}
+	all := []string{folder, pool, poolVM, "inventoryComputeResourceHost", "inventoryComputeResourcePool"}
+	for _, path := range datacenter {
+		all = append(all, "inventoryDatacenter"+path)
+	}
+
+	specs := []types.BaseSelectionSpec{
+		ts(folder, "Folder", "childEntity", sel(all...)),
+		ts(pool, "ResourcePool", "resourcePool", sel(pool, poolVM)),
+		ts(poolVM, "ResourcePool", "vm", nil),
+		ts("inventoryComputeResourceHost", "ComputeResource", "host", nil),
+		ts("inventoryComputeResourcePool", "ComputeResource", "resourcePool", sel(pool, poolVM)),
+	}
+	for _, path := range datacenter {
+		specs = append(specs, ts("inventoryDatacenter"+path, "Datacenter", path, sel(folder)))
+	}
+
+	b.selectSet = append(b.selectSet, specs...)
+
+	return b
+}
+
+// Properties adds the properties ps to collect for objects of type kind, all properties are collected if ps is empty.
+func (b *SpecBuilder) Properties(kind string, ps ...string) *SpecBuilder {
+	spec := types.PropertySpec{Type: kind}
+
+	if len(ps) == 0 {
+		spec.All = types.NewBool(true)
+	}
+
+	for _, p := range ps {
+		if !mo.IsProperty(kind, p) {
+			return b.fail("invalid property %q for type %q", p, kind)
+		}
+		spec.PathSet = append(spec.PathSet, p)
+	}
+
+	b.propSet = append(b.propSet, spec)
+
+	return b
+}
+
+// Build returns the PropertyFilterSpec, or the first error encountered by the builder.
+func (b *SpecBuilder) Build() (types.PropertyFilterSpec, error) {
+	var spec types.PropertyFilterSpec
+
+	if b.err != nil {
+		return spec, b.err
+	}
+	if b.obj.Type == "" || b.obj.Value == "" {
+		return spec, errors.New("starting object reference is empty")
+	}
+	if len(b.propSet) == 0 {
+		return spec, errors.New("no properties specified")
+	}
+	if b.skip && len(b.selectSet) == 0 {
+		return spec, errors.New("starting object is skipped without any traversal")
+	}
+
+	spec.ObjectSet = []types.ObjectSpec{{
+		Obj:       b.obj,
+		Skip:      types.NewBool(b.skip),
+		SelectSet: b.selectSet,
+	}}
+	spec.PropSet = b.propSet
+
+	return spec, nil
+}
@@ -0,0 +1,88 @@
+/*
+Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package property_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+func TestSpecBuilderInventory(t *testing.T) {
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		pc := property.DefaultCollector(c)
+
+		spec, err := property.NewSpecBuilder(c.ServiceContent.RootFolder).
+			TraverseInventory().
+			Properties("HostSystem", "name").
+			Properties("VirtualMachine", "name").
+			Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		res, err := pc.RetrieveProperties(ctx, types.RetrieveProperties{SpecSet: []types.PropertyFilterSpec{spec}})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		count := make(map[string]int)
+		for _, content := range res.Returnval {
+			count[content.Obj.Type]++
+		}
+
+		for _, kind := range []string{"HostSystem", "VirtualMachine"} {
+			n := len(simulator.Map.All(kind))
+			if count[kind] != n {
+				t.Errorf("%s: %d, expected %d", kind, count[kind], n)
+			}
+		}
+	})
+}
+
+func TestSpecBuilderValidation(t *testing.T) {
+	host := types.ManagedObjectReference{Type: "HostSystem", Value: "host-1"}
+
+	tests := []struct {
+		name string
+		b    *property.SpecBuilder
+	}{
+		{"unknown type", property.NewSpecBuilder(host).Properties("NoSuchType", "name")},
+		{"unknown property", property.NewSpecBuilder(host).Properties("HostSystem", "nope")},
+		{"invalid traversal", property.NewSpecBuilder(host).Traverse("HostSystem").Properties("HostSystem")},
+		{"unknown traversal property", property.NewSpecBuilder(host).Traverse("HostSystem.nope").Properties("HostSystem")},
+		{"start type mismatch", property.NewSpecBuilder(host).Traverse("ClusterComputeResource.host").Properties("HostSystem")},
+		{"no properties", property.NewSpecBuilder(host)},
+		{"skip without traversal", property.NewSpecBuilder(host).Skip().Properties("HostSystem")},
+		{"empty reference", property.NewSpecBuilder(types.ManagedObjectReference{}).Properties("HostSystem")},
+	}
+
+	for _, test := range tests {
+		if _, err := test.b.Build(); err == nil {
+			t.Errorf("%s: expected error", test.name)
+		}
+	}
+
+	_, err := property.NewSpecBuilder(host).Traverse("HostSystem.vm").Properties("VirtualMachine", "runtime.powerState").Build()
+	if err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,200 @@
+/*
+Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package property
+
+import (
+	"context"
+
+	"github.com/vmware/govmomi/vim25/methods"
+	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/govmomi/vim25/types"
+	"github.com/vmware/govmomi/vim25/xml"
+)
+
+// decodeChildren invokes fn for each child element of the current element,
+// until the current element's end tag is consumed.
+// fn must consume the entire child element.
+func decodeChildren(d *xml.Decoder, fn func(xml.StartElement) error) error {
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if err = fn(t); err != nil {
+				return err
+			}
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+// retrieveResultStream decodes a RetrieveResult, passing each ObjectContent to fn as it is decoded.
+type retrieveResultStream struct {
+	fn    func(types.ObjectContent) error
+	token string
+}
+
+func (s *retrieveResultStream) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	return decodeChildren(d, func(xml.StartElement) error { // returnval
+		return decodeChildren(d, func(e xml.StartElement) error {
+			switch e.Name.Local {
+			case "token":
+				return d.DecodeElement(&s.token, &e)
+			case "objects":
+				var content types.ObjectContent
+				if err := d.DecodeElement(&content, &e); err != nil {
+					return err
+				}
+				return s.fn(content)
+			default:
+				return d.Skip()
+			}
+		})
+	})
+}
+
+type retrievePropertiesExStreamBody struct {
+	Req    *types.RetrievePropertiesEx `xml:"urn:vim25 RetrievePropertiesEx,omitempty"`
+	Res    *retrieveResultStream       `xml:"RetrievePropertiesExResponse,omitempty"`
+	Fault_ *soap.Fault                 `xml:"http://schemas.xmlsoap.org/soap/envelope/ Fault,omitempty"`
+}
+
+func (b *retrievePropertiesExStreamBody) Fault() *soap.Fault { return b.Fault_ }
+
+type continueRetrievePropertiesExStreamBody struct {
+	Req    *types.ContinueRetrievePropertiesEx `xml:"urn:vim25 ContinueRetrievePropertiesEx,omitempty"`
+	Res    *retrieveResultStream               `xml:"ContinueRetrievePropertiesExResponse,omitempty"`
+	Fault_ *soap.Fault                         `xml:"http://schemas.xmlsoap.org/soap/envelope/ Fault,omitempty"`
+}
+
+func (b *continueRetrievePropertiesExStreamBody) Fault() *soap.Fault { return b.Fault_ }
+
+// RetrievePropertiesStream is a variant of RetrievePropertiesEx that passes each ObjectContent to fn
+// as it is decoded from the response, rather than decoding the entire response into memory first.
+// Continuation tokens are followed until all results have been retrieved, req.Options.MaxObjects
+// can be used to bound the size of each response. If fn returns an error, the retrieval is canceled
+// and the error is returned. Note that fn may be called again for the same objects if a failed
+// request is retried by a vim25.RetryPolicy.
+func (p *Collector) RetrievePropertiesStream(ctx context.Context, req types.RetrievePropertiesEx, fn func(types.ObjectContent) error) error {
+	req.This = p.Reference()
+
+	res := retrieveResultStream{fn: fn}
+	body := retrievePropertiesExStreamBody{Req: &req, Res: &res}
+
+	err := p.roundTripper.RoundTrip(ctx, &body, &body)
+
+	for err == nil && res.token != "" {
+		body := continueRetrievePropertiesExStreamBody{
+			Req: &types.ContinueRetrievePropertiesEx{This: req.This, Token: res.token},
+			Res: &res,
+		}
+
+		res.token = ""
+		err = p.roundTripper.RoundTrip(ctx, &body, &body)
+	}
+
+	if err != nil && res.token != "" {
+		_, _ = methods.CancelRetrievePropertiesEx(ctx, p.roundTripper, &types.CancelRetrievePropertiesEx{
+			This:  req.This,
+			Token: res.token,
+		})
+	}
+
+	return err
+}
+
+// updateSetStream decodes an UpdateSet, passing each ObjectUpdate and MissingObject to fn as it is decoded.
+type updateSetStream struct {
+	fn  func(types.PropertyFilterUpdate) error
+	set *types.UpdateSet
+}
+
+func (s *updateSetStream) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	return decodeChildren(d, func(xml.StartElement) error { // returnval
+		s.set = new(types.UpdateSet)
+
+		return decodeChildren(d, func(e xml.StartElement) error {
+			switch e.Name.Local {
+			case "version":
+				return d.DecodeElement(&s.set.Version, &e)
+			case "truncated":
+				return d.DecodeElement(&s.set.Truncated, &e)
+			case "filterSet":
+				return s.decodeFilterUpdate(d)
+			default:
+				return d.Skip()
+			}
+		})
+	})
+}
+
+func (s *updateSetStream) decodeFilterUpdate(d *xml.Decoder) error {
+	var filter types.ManagedObjectReference
+
+	return decodeChildren(d, func(e xml.StartElement) error {
+		update := types.PropertyFilterUpdate{Filter: filter}
+
+		switch e.Name.Local {
+		case "filter":
+			return d.DecodeElement(&filter, &e)
+		case "objectSet":
+			update.ObjectSet = make([]types.ObjectUpdate, 1)
+			if err := d.DecodeElement(&update.ObjectSet[0], &e); err != nil {
+				return err
+			}
+		case "missingSet":
+			update.MissingSet = make([]types.MissingObject, 1)
+			if err := d.DecodeElement(&update.MissingSet[0], &e); err != nil {
+				return err
+			}
+		default:
+			return d.Skip()
+		}
+
+		return s.fn(update)
+	})
+}
+
+type waitForUpdatesExStreamBody struct {
+	Req    *types.WaitForUpdatesEx `xml:"urn:vim25 WaitForUpdatesEx,omitempty"`
+	Res    *updateSetStream        `xml:"WaitForUpdatesExResponse,omitempty"`
+	Fault_ *soap.Fault             `xml:"http://schemas.xmlsoap.org/soap/envelope/ Fault,omitempty"`
+}
+
+func (b *waitForUpdatesExStreamBody) Fault() *soap.Fault { return b.Fault_ }
+
+// WaitForUpdatesStream is a variant of WaitForUpdatesEx that passes updates to fn as they are decoded
+// from the response, rather than decoding the entire response into memory first.
+// Each PropertyFilterUpdate passed to fn contains a single ObjectSet or MissingSet element.
+// The returned UpdateSet contains the Version and Truncated fields, with an empty FilterSet,
+// or is nil if the wait timed out.
+func (p *Collector) WaitForUpdatesStream(ctx context.Context, req types.WaitForUpdatesEx, fn func(types.PropertyFilterUpdate) error) (*types.UpdateSet, error) {
+	req.This = p.Reference()
+
+	res := updateSetStream{fn: fn}
+	body := waitForUpdatesExStreamBody{Req: &req, Res: &res}
+
+	if err := p.roundTripper.RoundTrip(ctx, &body, &body); err != nil {
+		return nil, err
+	}
+
+	return res.set, nil
+}
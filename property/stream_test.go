@@ -0,0 +1,132 @@
+/*
+Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package property_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/view"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+func TestRetrievePropertiesStream(t *testing.T) {
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		m := view.NewManager(c)
+		v, err := m.CreateContainerView(ctx, c.ServiceContent.RootFolder, []string{"VirtualMachine"}, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req := types.RetrievePropertiesEx{
+			SpecSet: []types.PropertyFilterSpec{{
+				ObjectSet: []types.ObjectSpec{{
+					Obj:  v.Reference(),
+					Skip: types.NewBool(true),
+					SelectSet: []types.BaseSelectionSpec{&types.TraversalSpec{
+						Type: "ContainerView",
+						Path: "view",
+					}},
+				}},
+				PropSet: []types.PropertySpec{{Type: "VirtualMachine", PathSet: []string{"name"}}},
+			}},
+		}
+
+		pc := property.DefaultCollector(c)
+
+		var objects []types.ObjectContent
+		err = pc.RetrievePropertiesStream(ctx, req, func(content types.ObjectContent) error {
+			objects = append(objects, content)
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		vms := simulator.Map.All("VirtualMachine")
+		if len(objects) != len(vms) || len(vms) == 0 {
+			t.Fatalf("objects=%d, vms=%d", len(objects), len(vms))
+		}
+
+		for _, content := range objects {
+			if content.Obj.Type != "VirtualMachine" || len(content.PropSet) != 1 || content.PropSet[0].Name != "name" {
+				t.Errorf("unexpected content: %#v", content)
+			}
+		}
+
+		stop := errors.New("stop")
+		n := 0
+		err = pc.RetrievePropertiesStream(ctx, req, func(types.ObjectContent) error {
+			n++
+			return stop
+		})
+		if err != stop || n != 1 {
+			t.Errorf("err=%v, n=%d", err, n)
+		}
+	})
+}
+
+func TestWaitForUpdatesStream(t *testing.T) {
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		pc, err := property.DefaultCollector(c).Create(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer pc.Destroy(ctx)
+
+		obj := simulator.Map.Any("VirtualMachine").Reference()
+
+		err = pc.CreateFilter(ctx, types.CreateFilter{
+			Spec: types.PropertyFilterSpec{
+				ObjectSet: []types.ObjectSpec{{Obj: obj}},
+				PropSet:   []types.PropertySpec{{Type: obj.Type, PathSet: []string{"name", "runtime.powerState"}}},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var updates []types.PropertyFilterUpdate
+		set, err := pc.WaitForUpdatesStream(ctx, types.WaitForUpdatesEx{}, func(update types.PropertyFilterUpdate) error {
+			updates = append(updates, update)
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if set == nil || set.Version == "" || len(set.FilterSet) != 0 {
+			t.Fatalf("set=%#v", set)
+		}
+
+		if len(updates) != 1 {
+			t.Fatalf("updates=%d", len(updates))
+		}
+
+		update := updates[0]
+		if update.Filter.Value == "" || len(update.ObjectSet) != 1 || update.ObjectSet[0].Obj != obj {
+			t.Errorf("update=%#v", update)
+		}
+		if len(update.ObjectSet[0].ChangeSet) != 2 {
+			t.Errorf("changeSet=%#v", update.ObjectSet[0].ChangeSet)
+		}
+	})
+}
@@ -0,0 +1,51 @@
+//+build !linux,!darwin,!freebsd,!netbsd,!openbsd,!dragonfly
+
+/*
+Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"os"
+	"time"
+)
+
+// staleLock is the age after which a lock file is assumed to be left behind by a process that exited without unlocking.
+const staleLock = time.Minute
+
+// lockFile acquires an exclusive lock on the given file, blocking until the lock is available.
+// The syscall package does not provide flock(2) on this platform (nor LockFileEx on windows),
+// so the lock is held by exclusively creating the file.
+func lockFile(name string) (func(), error) {
+	for {
+		f, err := os.OpenFile(name, os.O_CREATE|os.O_EXCL|os.O_RDWR, 0600)
+		if err == nil {
+			_ = f.Close()
+			return func() { _ = os.Remove(name) }, nil
+		}
+
+		if !os.IsExist(err) {
+			return nil, err
+		}
+
+		if info, serr := os.Stat(name); serr == nil && time.Since(info.ModTime()) > staleLock {
+			_ = os.Remove(name)
+			continue
+		}
+
+		time.Sleep(50 * time.Millisecond)
+	}
+}
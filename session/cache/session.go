@@ -0,0 +1,205 @@
+/*
+Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package cache provides a file based cache of vim25.Client sessions, which can be shared by
+concurrent processes. Access to each session file is serialized with a file lock, such that
+only one process logs in when there is no valid session, and session files are written atomically.
+*/
+package cache
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/vmware/govmomi/session"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// ConfigureFunc is used to configure a soap.Client before it is used, for example to set
+// TLS certificates. The returned RoundTripper, typically the given soap.Client or a wrapper of it,
+// is used as the vim25.Client.RoundTripper.
+type ConfigureFunc func(*soap.Client) (soap.RoundTripper, error)
+
+// Session is a cached session for a single service URL.
+type Session struct {
+	URL      *url.URL // Service URL, URL.User is used by Login but is not part of the cache key
+	Insecure bool     // Skip TLS verification, this setting is part of the cache key
+	Dir      string   // Directory for session files, defaults to $GOVMOMI_HOME/sessions
+}
+
+// DefaultDir returns the default session file directory, $GOVMOMI_HOME/sessions,
+// where GOVMOMI_HOME defaults to $HOME/.govmomi.
+func DefaultDir() string {
+	home := os.Getenv("GOVMOMI_HOME")
+	if home == "" {
+		home = filepath.Join(os.Getenv("HOME"), ".govmomi")
+	}
+	return filepath.Join(home, "sessions")
+}
+
+// Path returns the session file path.
+func (s *Session) Path() string {
+	u := *s.URL
+	u.User = nil
+	if s.URL.User != nil {
+		u.User = url.User(s.URL.User.Username())
+	}
+
+	// Key session file off of full URI and insecure setting.
+	// Hash key to get a predictable, canonical format.
+	key := fmt.Sprintf("%s#insecure=%t", u.String(), s.Insecure)
+	name := fmt.Sprintf("%040x", sha1.Sum([]byte(key)))
+
+	dir := s.Dir
+	if dir == "" {
+		dir = DefaultDir()
+	}
+	return filepath.Join(dir, name)
+}
+
+// Lock acquires an exclusive lock on the session file, blocking until the lock is held by this process.
+// The returned func releases the lock.
+func (s *Session) Lock() (func(), error) {
+	p := s.Path()
+	if err := os.MkdirAll(filepath.Dir(p), 0700); err != nil {
+		return nil, err
+	}
+	return lockFile(p + ".lock")
+}
+
+// Load restores the cached client and validates its session. The configure func, if any,
+// is applied to the restored soap.Client before validation.
+// A nil client is returned if there is no cached session or the session is no longer valid.
+func (s *Session) Load(ctx context.Context, configure ConfigureFunc) (*vim25.Client, error) {
+	f, err := os.Open(s.Path())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	c := new(vim25.Client)
+	err = json.NewDecoder(f).Decode(c)
+	_ = f.Close()
+	if err != nil || !c.Valid() {
+		return nil, nil // discard corrupt or incomplete session files
+	}
+
+	if configure != nil {
+		c.RoundTripper, err = configure(c.Client)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	u, err := session.NewManager(c).UserSession(ctx)
+	if err != nil {
+		if soap.IsSoapFault(err) {
+			fault := soap.ToSoapFault(err).VimFault()
+			// If the PropertyCollector is not found, the saved session for this URL is not valid
+			if _, ok := fault.(types.ManagedObjectNotFound); ok {
+				return nil, nil
+			}
+		}
+
+		return nil, err
+	}
+
+	// If the session is nil, the client is not authenticated
+	if u == nil {
+		return nil, nil
+	}
+
+	return c, nil
+}
+
+// Save writes the client session to the session file. The file is replaced atomically,
+// such that concurrent readers never see a partially written file.
+func (s *Session) Save(c *vim25.Client) error {
+	p := s.Path()
+	dir := filepath.Dir(p)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+
+	f, err := ioutil.TempFile(dir, filepath.Base(p)+".tmp")
+	if err != nil {
+		return err
+	}
+
+	err = json.NewEncoder(f).Encode(c)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), p)
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+	}
+
+	return err
+}
+
+// Login returns the cached client if its session is still valid. Otherwise, a new client is
+// created and logged in using URL.User, then saved to the session file.
+// The session file is locked for the duration, such that concurrent callers wait for a single Login.
+func (s *Session) Login(ctx context.Context, configure ConfigureFunc) (*vim25.Client, error) {
+	unlock, err := s.Lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	c, err := s.Load(ctx, configure)
+	if err != nil || c != nil {
+		return c, err
+	}
+
+	sc := soap.NewClient(s.URL, s.Insecure)
+
+	var rt soap.RoundTripper = sc
+	if configure != nil {
+		rt, err = configure(sc)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	c, err = vim25.NewClient(ctx, rt)
+	if err != nil {
+		return nil, err
+	}
+
+	// Set client, in case configure wrapped the soap.Client
+	c.Client = sc
+
+	if err = session.NewManager(c).Login(ctx, s.URL.User); err != nil {
+		return nil, err
+	}
+
+	return c, s.Save(c)
+}
@@ -0,0 +1,103 @@
+/*
+Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/vmware/govmomi/session/cache"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25/soap"
+)
+
+func TestSessionLogin(t *testing.T) {
+	ctx := context.Background()
+
+	model := simulator.VPX()
+	defer model.Remove()
+	if err := model.Create(); err != nil {
+		t.Fatal(err)
+	}
+
+	s := model.Service.NewServer()
+	defer s.Close()
+
+	dir, err := ioutil.TempDir("", "govmomi-sessions")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var logins int32
+	configure := func(c *soap.Client) (soap.RoundTripper, error) {
+		c.RoundTripHook = func(_ context.Context, info soap.RoundTripInfo) {
+			if info.Method == "Login" {
+				atomic.AddInt32(&logins, 1)
+			}
+		}
+		return c, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			session := &cache.Session{URL: s.URL, Insecure: true, Dir: dir}
+			c, err := session.Login(ctx, configure)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if !c.Valid() {
+				t.Error("invalid client")
+			}
+		}()
+	}
+	wg.Wait()
+
+	if logins != 1 {
+		t.Errorf("logins=%d", logins)
+	}
+
+	// A session for a different URL is not shared
+	u := *s.URL
+	u.Path = "/sdk/"
+	session := &cache.Session{URL: &u, Insecure: true, Dir: dir}
+	if session.Path() == (&cache.Session{URL: s.URL, Insecure: true, Dir: dir}).Path() {
+		t.Error("expected different session file")
+	}
+
+	// A corrupt session file results in a new login
+	session = &cache.Session{URL: s.URL, Insecure: true, Dir: dir}
+	if err = ioutil.WriteFile(session.Path(), []byte("{"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = session.Login(ctx, configure); err != nil {
+		t.Fatal(err)
+	}
+
+	if logins != 2 {
+		t.Errorf("logins=%d", logins)
+	}
+}
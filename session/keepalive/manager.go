@@ -0,0 +1,176 @@
+/*
+Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package keepalive manages a vim25 SOAP session and a vAPI REST session as a unit,
+logging in, keeping alive and re-establishing both sessions together.
+*/
+package keepalive
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/vmware/govmomi/session"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vim25"
+)
+
+// Manager keeps a vim25.Client session and a rest.Client session alive together.
+// Unlike session.KeepAlive and rest.Client.KeepAlive, which each track the idle time of a single client,
+// Manager checks both sessions at the same interval, regardless of client activity,
+// and logs in again if either session has been lost.
+type Manager struct {
+	// OnLoss, if set, is called when a session could not be re-established,
+	// after which the keep alive go routine is stopped.
+	OnLoss func(error)
+
+	vim      *vim25.Client
+	rest     *rest.Client
+	user     *url.Userinfo
+	interval time.Duration
+
+	mu   sync.Mutex
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewManager creates a Manager for the given clients, using user to login and checking
+// the sessions at the given interval once logged in. The interval must be positive, see Login.
+func NewManager(c *vim25.Client, rc *rest.Client, user *url.Userinfo, interval time.Duration) *Manager {
+	return &Manager{
+		vim:      c,
+		rest:     rc,
+		user:     user,
+		interval: interval,
+	}
+}
+
+// Login creates both sessions and starts the keep alive go routine.
+// If the REST login fails, the SOAP session is logged out, such that neither session is left behind.
+// An error is returned, without logging in, if the Manager's interval is not positive.
+func (m *Manager) Login(ctx context.Context) error {
+	if m.interval <= 0 {
+		return fmt.Errorf("invalid keep alive interval: %s", m.interval)
+	}
+
+	sm := session.NewManager(m.vim)
+
+	if err := sm.Login(ctx, m.user); err != nil {
+		return err
+	}
+
+	if err := m.rest.Login(ctx, m.user); err != nil {
+		_ = sm.Logout(ctx)
+		return err
+	}
+
+	m.start()
+	return nil
+}
+
+// Logout stops the keep alive go routine and deletes both sessions.
+func (m *Manager) Logout(ctx context.Context) error {
+	m.stopKeepAlive()
+
+	rerr := m.rest.Logout(ctx)
+	err := session.NewManager(m.vim).Logout(ctx)
+	if err == nil {
+		err = rerr
+	}
+	return err
+}
+
+// keepAlive checks both sessions, logging in again if either is no longer authenticated.
+func (m *Manager) keepAlive(ctx context.Context) error {
+	sm := session.NewManager(m.vim)
+
+	u, err := sm.UserSession(ctx)
+	if err != nil {
+		return err
+	}
+	if u == nil {
+		if err = sm.Login(ctx, m.user); err != nil {
+			return err
+		}
+	}
+
+	s, err := m.rest.Session(ctx)
+	if err != nil {
+		return err
+	}
+	if s == nil {
+		return m.rest.Login(ctx, m.user)
+	}
+
+	return nil
+}
+
+func (m *Manager) start() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.stop != nil {
+		return
+	}
+
+	// This channel must be closed to terminate the ticker.
+	stop := make(chan struct{})
+	m.stop = stop
+	m.wg.Add(1)
+
+	go func() {
+		defer m.wg.Done()
+
+		t := time.NewTicker(m.interval)
+		defer t.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-t.C:
+				if err := m.keepAlive(context.Background()); err != nil {
+					m.mu.Lock()
+					if m.stop == stop {
+						m.stop = nil
+					}
+					m.mu.Unlock()
+
+					if m.OnLoss != nil {
+						m.OnLoss(err)
+					}
+					return
+				}
+			}
+		}
+	}()
+}
+
+func (m *Manager) stopKeepAlive() {
+	m.mu.Lock()
+	stop := m.stop
+	m.stop = nil
+	m.mu.Unlock()
+
+	if stop != nil {
+		close(stop)
+		m.wg.Wait()
+	}
+}
@@ -0,0 +1,156 @@
+/*
+Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keepalive_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/vmware/govmomi/session"
+	"github.com/vmware/govmomi/session/keepalive"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/rest"
+	_ "github.com/vmware/govmomi/vapi/simulator"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/soap"
+)
+
+// waitFor polls fn until it returns true or the timeout expires.
+func waitFor(t *testing.T, fn func() bool) {
+	for start := time.Now(); time.Since(start) < 5*time.Second; time.Sleep(10 * time.Millisecond) {
+		if fn() {
+			return
+		}
+	}
+	t.Fatal("timeout")
+}
+
+func TestManager(t *testing.T) {
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		// simulator.Test logs in, start with a fresh SOAP session
+		if err := session.NewManager(c).Logout(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		rc := rest.NewClient(c)
+		m := keepalive.NewManager(c, rc, simulator.DefaultLogin, 10*time.Millisecond)
+
+		lost := make(chan error, 1)
+		m.OnLoss = func(err error) { lost <- err }
+
+		if err := m.Login(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		// Destroy both sessions behind the Manager's back
+		vim := session.NewManager(c)
+		u, err := vim.UserSession(ctx)
+		if err != nil || u == nil {
+			t.Fatalf("session=%v, err=%v", u, err)
+		}
+
+		admin, err := vim25.NewClient(ctx, soap.NewClient(c.URL(), true))
+		if err != nil {
+			t.Fatal(err)
+		}
+		sm := session.NewManager(admin)
+		if err = sm.Login(ctx, simulator.DefaultLogin); err != nil {
+			t.Fatal(err)
+		}
+		if err = sm.TerminateSession(ctx, []string{u.Key}); err != nil {
+			t.Fatal(err)
+		}
+
+		clone, err := rc.Clone(ctx, rc.SessionID())
+		if err != nil {
+			t.Fatal(err)
+		}
+		id := rc.SessionID()
+		if err = clone.Logout(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		// Both sessions are re-established
+		waitFor(t, func() bool {
+			s, err := vim.UserSession(ctx)
+			return err == nil && s != nil && s.Key != u.Key
+		})
+		waitFor(t, func() bool {
+			s, err := rc.Session(ctx)
+			return err == nil && s != nil && rc.SessionID() != id
+		})
+
+		if err = m.Logout(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		select {
+		case err = <-lost:
+			t.Errorf("unexpected loss: %s", err)
+		default:
+		}
+	})
+}
+
+func TestManagerLoss(t *testing.T) {
+	ctx := context.Background()
+
+	model := simulator.VPX()
+	defer model.Remove()
+	if err := model.Create(); err != nil {
+		t.Fatal(err)
+	}
+
+	model.Service.RegisterEndpoints = true
+	s := model.Service.NewServer()
+
+	c, err := vim25.NewClient(ctx, soap.NewClient(s.URL, true))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := keepalive.NewManager(c, rest.NewClient(c), simulator.DefaultLogin, 10*time.Millisecond)
+	lost := make(chan error, 1)
+	m.OnLoss = func(err error) { lost <- err }
+
+	if err = m.Login(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	s.Close()
+
+	select {
+	case err = <-lost:
+		if err == nil {
+			t.Error("expected error")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("OnLoss was not called")
+	}
+}
+
+func TestManagerInvalidInterval(t *testing.T) {
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		for _, interval := range []time.Duration{0, -time.Second} {
+			m := keepalive.NewManager(c, rest.NewClient(c), simulator.DefaultLogin, interval)
+			if err := m.Login(ctx); err == nil {
+				t.Errorf("interval=%s: expected error", interval)
+			}
+		}
+	})
+}
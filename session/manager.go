@@ -19,7 +19,6 @@ package session
 import (
 	"context"
 	"io/ioutil"
-	"net/http"
 	"net/url"
 	"os"
 	"strings"
@@ -120,11 +119,6 @@ func (sm *Manager) LoginExtensionByCertificate(ctx context.Context, key string)
 			RoundTripper:   sc,
 			ServiceContent: c.ServiceContent,
 		}
-		// When http.Transport.Proxy is used, our thumbprint checker is bypassed, resulting in:
-		// "Post https://sdkTunnel:8089/sdk: x509: certificate is valid for $vcenter_hostname, not sdkTunnel"
-		// The only easy way around this is to disable verification for the call to LoginExtensionByCertificate().
-		// TODO: find a way to avoid disabling InsecureSkipVerify.
-		c.Transport.(*http.Transport).TLSClientConfig.InsecureSkipVerify = true
 	}
 
 	req := types.LoginExtensionByCertificate{
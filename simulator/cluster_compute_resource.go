@@ -19,6 +19,7 @@ package simulator
 import (
 	"log"
 	"math/rand"
+	"strconv"
 	"sync/atomic"
 	"time"
 
@@ -34,6 +35,7 @@ type ClusterComputeResource struct {
 	mo.ClusterComputeResource
 
 	ruleKey int32
+	recKey  int32
 }
 
 func (c *ClusterComputeResource) RenameTask(req *types.Rename_Task) soap.HasFault {
@@ -59,6 +61,7 @@ func (add *addHost) Run(task *Task) (types.AnyType, types.BaseMethodFault) {
 	cr := add.ClusterComputeResource
 	Map.PutEntity(cr, Map.NewEntity(host))
 	host.Summary.Host = &host.Self
+	host.putCertificateManager()
 
 	cr.Host = append(cr.Host, host.Reference())
 	addComputeResource(cr.Summary.GetComputeResourceSummary(), host)
@@ -349,6 +352,164 @@ func (c *ClusterComputeResource) PlaceVm(ctx *Context, req *types.PlaceVm) soap.
 	return body
 }
 
+// drsBehavior returns the cluster's default DRS behavior, or false if DRS is disabled.
+func (c *ClusterComputeResource) drsBehavior() (types.DrsBehavior, bool) {
+	drs := c.ConfigurationEx.(*types.ClusterConfigInfoEx).DrsConfig
+	if drs.Enabled == nil || !*drs.Enabled {
+		return "", false
+	}
+
+	if drs.DefaultVmBehavior == "" {
+		return types.DrsBehaviorFullyAutomated, true
+	}
+
+	return drs.DefaultVmBehavior, true
+}
+
+// migrate moves vm from host src to host dst.
+func (c *ClusterComputeResource) migrate(ctx *Context, vm *VirtualMachine, src, dst *HostSystem) {
+	ctx.WithLock(src, func() { RemoveReference(&src.Vm, vm.Self) })
+	ctx.WithLock(dst, func() { dst.Vm = append(dst.Vm, vm.Self) })
+
+	ref := dst.Reference()
+	ctx.WithLock(vm, func() {
+		Map.Update(vm, []types.PropertyChange{
+			{Name: "runtime.host", Val: &ref},
+			{Name: "summary.runtime.host", Val: &ref},
+		})
+	})
+}
+
+// evacuate moves VMs off the given host when DRS is fully automated, otherwise recommendations are generated.
+// Returns true if the host has no VMs left to evacuate.
+func (c *ClusterComputeResource) evacuate(ctx *Context, host *HostSystem, poweredOff bool) bool {
+	vms := host.evacuees(poweredOff)
+	if len(vms) == 0 {
+		return true
+	}
+
+	behavior, enabled := c.drsBehavior()
+	if !enabled {
+		return true // VMs are not evacuated without DRS
+	}
+
+	var targets []*HostSystem
+	for _, ref := range c.Host {
+		h := Map.Get(ref).(*HostSystem)
+		if h != host && !h.Runtime.InMaintenanceMode && h.Runtime.ConnectionState == types.HostSystemConnectionStateConnected {
+			targets = append(targets, h)
+		}
+	}
+
+	if len(targets) == 0 {
+		return false
+	}
+
+	if behavior == types.DrsBehaviorFullyAutomated {
+		for i, vm := range vms {
+			c.migrate(ctx, vm, host, targets[i%len(targets)])
+		}
+		return true
+	}
+
+	now := time.Now()
+	recs := c.Recommendation
+
+	for i, vm := range vms {
+		key := strconv.Itoa(int(atomic.AddInt32(&c.recKey, 1)))
+
+		recs = append(recs, types.ClusterRecommendation{
+			Key:        key,
+			Type:       "V1",
+			Time:       now,
+			Rating:     5,
+			Reason:     string(types.RecommendationReasonCodeHostMaint),
+			ReasonText: string(types.RecommendationReasonCodeHostMaint),
+			Target:     &c.Self,
+			Action: []types.BaseClusterAction{
+				&types.ClusterMigrationAction{
+					ClusterAction: types.ClusterAction{
+						Type:   string(types.ActionTypeMigrationV1),
+						Target: &vm.Self,
+					},
+					DrsMigration: &types.ClusterDrsMigration{
+						Key:         key,
+						Time:        now,
+						Vm:          vm.Self,
+						Source:      host.Self,
+						Destination: targets[i%len(targets)].Self,
+					},
+				},
+			},
+		})
+	}
+
+	Map.Update(c, []types.PropertyChange{{Name: "recommendation", Val: recs}})
+
+	return false
+}
+
+// cancelRecommendations removes any pending recommendations to migrate VMs off the given host.
+func (c *ClusterComputeResource) cancelRecommendations(host *HostSystem) {
+	Map.WithLock(c, func() {
+		var recs []types.ClusterRecommendation
+
+		for _, rec := range c.Recommendation {
+			source := false
+			for _, action := range rec.Action {
+				if m, ok := action.(*types.ClusterMigrationAction); ok && m.DrsMigration != nil {
+					source = source || m.DrsMigration.Source == host.Self
+				}
+			}
+			if !source {
+				recs = append(recs, rec)
+			}
+		}
+
+		Map.Update(c, []types.PropertyChange{{Name: "recommendation", Val: recs}})
+	})
+}
+
+func (c *ClusterComputeResource) ApplyRecommendation(ctx *Context, req *types.ApplyRecommendation) soap.HasFault {
+	body := new(methods.ApplyRecommendationBody)
+
+	var recs []types.ClusterRecommendation
+	var rec *types.ClusterRecommendation
+
+	for i := range c.Recommendation {
+		if c.Recommendation[i].Key == req.Key {
+			rec = &c.Recommendation[i]
+			continue
+		}
+		recs = append(recs, c.Recommendation[i])
+	}
+
+	if rec == nil {
+		body.Fault_ = Fault("", &types.InvalidArgument{InvalidProperty: "key"})
+		return body
+	}
+
+	Map.Update(c, []types.PropertyChange{{Name: "recommendation", Val: recs}})
+
+	for _, action := range rec.Action {
+		m, ok := action.(*types.ClusterMigrationAction)
+		if !ok || m.DrsMigration == nil {
+			continue
+		}
+
+		vm := Map.Get(m.DrsMigration.Vm).(*VirtualMachine)
+		src := Map.Get(*vm.Runtime.Host).(*HostSystem)
+		dst := Map.Get(m.DrsMigration.Destination).(*HostSystem)
+
+		c.migrate(ctx, vm, src, dst)
+		src.evacuated(ctx)
+	}
+
+	body.Res = new(types.ApplyRecommendationResponse)
+
+	return body
+}
+
 func CreateClusterComputeResource(f *Folder, name string, spec types.ClusterConfigSpecEx) (*ClusterComputeResource, types.BaseMethodFault) {
 	if e := Map.FindByName(name, f.ChildEntity); e != nil {
 		return nil, &types.DuplicateName{
@@ -0,0 +1,321 @@
+/*
+Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"github.com/google/uuid"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/methods"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// kmipCerts holds the certificates used to establish trust between vCenter and a KMS cluster
+type kmipCerts struct {
+	server, client, key string
+}
+
+type CryptoManagerKmip struct {
+	mo.CryptoManagerKmip
+
+	certs map[string]*kmipCerts
+	keys  []types.CryptoKeyId
+}
+
+func NewCryptoManagerKmip(ref types.ManagedObjectReference) object.Reference {
+	m := &CryptoManagerKmip{certs: make(map[string]*kmipCerts)}
+	m.Self = ref
+	m.Enabled = true
+	return m
+}
+
+func (m *CryptoManagerKmip) cluster(id types.KeyProviderId) *types.KmipClusterInfo {
+	for i := range m.KmipServers {
+		if m.KmipServers[i].ClusterId.Id == id.Id {
+			return &m.KmipServers[i]
+		}
+	}
+	return nil
+}
+
+func (m *CryptoManagerKmip) server(id types.KeyProviderId, name string) (*types.KmipClusterInfo, int) {
+	cluster := m.cluster(id)
+	if cluster != nil {
+		for i := range cluster.Servers {
+			if cluster.Servers[i].Name == name {
+				return cluster, i
+			}
+		}
+	}
+	return cluster, -1
+}
+
+func (m *CryptoManagerKmip) RegisterKmipServer(ctx *Context, req *types.RegisterKmipServer) soap.HasFault {
+	body := new(methods.RegisterKmipServerBody)
+
+	cluster, i := m.server(req.Server.ClusterId, req.Server.Info.Name)
+	if i >= 0 {
+		body.Fault_ = Fault("", &types.AlreadyExists{Name: req.Server.Info.Name})
+		return body
+	}
+
+	if cluster == nil {
+		m.KmipServers = append(m.KmipServers, types.KmipClusterInfo{
+			ClusterId:    req.Server.ClusterId,
+			UseAsDefault: len(m.KmipServers) == 0,
+		})
+		m.certs[req.Server.ClusterId.Id] = new(kmipCerts)
+		cluster = m.cluster(req.Server.ClusterId)
+	}
+
+	cluster.Servers = append(cluster.Servers, req.Server.Info)
+
+	body.Res = new(types.RegisterKmipServerResponse)
+	return body
+}
+
+func (m *CryptoManagerKmip) UpdateKmipServer(ctx *Context, req *types.UpdateKmipServer) soap.HasFault {
+	body := new(methods.UpdateKmipServerBody)
+
+	cluster, i := m.server(req.Server.ClusterId, req.Server.Info.Name)
+	if i < 0 {
+		body.Fault_ = Fault("", &types.NotFound{})
+		return body
+	}
+
+	cluster.Servers[i] = req.Server.Info
+
+	body.Res = new(types.UpdateKmipServerResponse)
+	return body
+}
+
+func (m *CryptoManagerKmip) RemoveKmipServer(ctx *Context, req *types.RemoveKmipServer) soap.HasFault {
+	body := new(methods.RemoveKmipServerBody)
+
+	cluster, i := m.server(req.ClusterId, req.ServerName)
+	if i < 0 {
+		body.Fault_ = Fault("", &types.NotFound{})
+		return body
+	}
+
+	cluster.Servers = append(cluster.Servers[:i], cluster.Servers[i+1:]...)
+
+	if len(cluster.Servers) == 0 {
+		for j := range m.KmipServers {
+			if m.KmipServers[j].ClusterId.Id == req.ClusterId.Id {
+				m.KmipServers = append(m.KmipServers[:j], m.KmipServers[j+1:]...)
+				break
+			}
+		}
+		delete(m.certs, req.ClusterId.Id)
+	}
+
+	body.Res = new(types.RemoveKmipServerResponse)
+	return body
+}
+
+func (m *CryptoManagerKmip) ListKmipServers(ctx *Context, req *types.ListKmipServers) soap.HasFault {
+	clusters := m.KmipServers
+	if req.Limit != nil && int(*req.Limit) < len(clusters) {
+		clusters = clusters[:*req.Limit]
+	}
+
+	return &methods.ListKmipServersBody{
+		Res: &types.ListKmipServersResponse{
+			Returnval: clusters,
+		},
+	}
+}
+
+func (m *CryptoManagerKmip) MarkDefault(ctx *Context, req *types.MarkDefault) soap.HasFault {
+	body := new(methods.MarkDefaultBody)
+
+	if m.cluster(req.ClusterId) == nil {
+		body.Fault_ = Fault("", &types.NotFound{})
+		return body
+	}
+
+	for i := range m.KmipServers {
+		m.KmipServers[i].UseAsDefault = m.KmipServers[i].ClusterId.Id == req.ClusterId.Id
+	}
+
+	body.Res = new(types.MarkDefaultResponse)
+	return body
+}
+
+func (m *CryptoManagerKmip) RetrieveKmipServerCert(ctx *Context, req *types.RetrieveKmipServerCert) soap.HasFault {
+	body := new(methods.RetrieveKmipServerCertBody)
+
+	certs, ok := m.certs[req.KeyProvider.Id]
+	if !ok {
+		body.Fault_ = Fault("", &types.NotFound{})
+		return body
+	}
+
+	body.Res = &types.RetrieveKmipServerCertResponse{
+		Returnval: types.CryptoManagerKmipServerCertInfo{
+			Certificate:       certs.server,
+			ClientTrustServer: types.NewBool(certs.server != ""),
+		},
+	}
+	return body
+}
+
+func (m *CryptoManagerKmip) UploadKmipServerCert(ctx *Context, req *types.UploadKmipServerCert) soap.HasFault {
+	body := new(methods.UploadKmipServerCertBody)
+
+	certs, ok := m.certs[req.Cluster.Id]
+	if !ok {
+		body.Fault_ = Fault("", &types.NotFound{})
+		return body
+	}
+
+	certs.server = req.Certificate
+
+	body.Res = new(types.UploadKmipServerCertResponse)
+	return body
+}
+
+func (m *CryptoManagerKmip) UpdateSelfSignedClientCert(ctx *Context, req *types.UpdateSelfSignedClientCert) soap.HasFault {
+	body := new(methods.UpdateSelfSignedClientCertBody)
+
+	certs, ok := m.certs[req.Cluster.Id]
+	if !ok {
+		body.Fault_ = Fault("", &types.NotFound{})
+		return body
+	}
+
+	certs.client = req.Certificate
+	certs.key = ""
+
+	body.Res = new(types.UpdateSelfSignedClientCertResponse)
+	return body
+}
+
+func (m *CryptoManagerKmip) RetrieveSelfSignedClientCert(ctx *Context, req *types.RetrieveSelfSignedClientCert) soap.HasFault {
+	body := new(methods.RetrieveSelfSignedClientCertBody)
+
+	certs, ok := m.certs[req.Cluster.Id]
+	if !ok {
+		body.Fault_ = Fault("", &types.NotFound{})
+		return body
+	}
+
+	body.Res = &types.RetrieveSelfSignedClientCertResponse{Returnval: certs.client}
+	return body
+}
+
+func (m *CryptoManagerKmip) UploadClientCert(ctx *Context, req *types.UploadClientCert) soap.HasFault {
+	body := new(methods.UploadClientCertBody)
+
+	certs, ok := m.certs[req.Cluster.Id]
+	if !ok {
+		body.Fault_ = Fault("", &types.NotFound{})
+		return body
+	}
+
+	certs.client = req.Certificate
+	certs.key = req.PrivateKey
+
+	body.Res = new(types.UploadClientCertResponse)
+	return body
+}
+
+func (m *CryptoManagerKmip) RetrieveClientCert(ctx *Context, req *types.RetrieveClientCert) soap.HasFault {
+	body := new(methods.RetrieveClientCertBody)
+
+	certs, ok := m.certs[req.Cluster.Id]
+	if !ok {
+		body.Fault_ = Fault("", &types.NotFound{})
+		return body
+	}
+
+	body.Res = &types.RetrieveClientCertResponse{Returnval: certs.client}
+	return body
+}
+
+func (m *CryptoManagerKmip) GenerateKey(ctx *Context, req *types.GenerateKey) soap.HasFault {
+	var result types.CryptoKeyResult
+
+	var cluster *types.KmipClusterInfo
+	if req.KeyProvider == nil {
+		for i := range m.KmipServers {
+			if m.KmipServers[i].UseAsDefault {
+				cluster = &m.KmipServers[i]
+			}
+		}
+	} else {
+		cluster = m.cluster(*req.KeyProvider)
+	}
+
+	if cluster == nil {
+		result.Reason = "KMS cluster not found"
+	} else {
+		provider := cluster.ClusterId
+		result.KeyId = types.CryptoKeyId{
+			KeyId:      uuid.New().String(),
+			ProviderId: &provider,
+		}
+		result.Success = true
+		m.keys = append(m.keys, result.KeyId)
+	}
+
+	return &methods.GenerateKeyBody{
+		Res: &types.GenerateKeyResponse{
+			Returnval: result,
+		},
+	}
+}
+
+func (m *CryptoManagerKmip) ListKeys(ctx *Context, req *types.ListKeys) soap.HasFault {
+	keys := m.keys
+	if req.Limit != nil && int(*req.Limit) < len(keys) {
+		keys = keys[:*req.Limit]
+	}
+
+	return &methods.ListKeysBody{
+		Res: &types.ListKeysResponse{
+			Returnval: keys,
+		},
+	}
+}
+
+func (m *CryptoManagerKmip) RemoveKeys(ctx *Context, req *types.RemoveKeys) soap.HasFault {
+	var results []types.CryptoKeyResult
+
+	for _, key := range req.Keys {
+		result := types.CryptoKeyResult{KeyId: key, Reason: "key not found"}
+
+		for i := range m.keys {
+			if m.keys[i].KeyId == key.KeyId {
+				m.keys = append(m.keys[:i], m.keys[i+1:]...)
+				result.Success = true
+				result.Reason = ""
+				break
+			}
+		}
+
+		results = append(results, result)
+	}
+
+	return &methods.RemoveKeysBody{
+		Res: &types.RemoveKeysResponse{
+			Returnval: results,
+		},
+	}
+}
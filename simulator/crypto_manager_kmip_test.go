@@ -0,0 +1,146 @@
+/*
+Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+func TestCryptoManagerKmip(t *testing.T) {
+	Test(func(ctx context.Context, c *vim25.Client) {
+		m, err := object.GetCryptoManagerKmip(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// no KMS cluster registered yet
+		if _, err = m.GenerateKey(ctx, ""); err == nil {
+			t.Error("expected error")
+		}
+
+		for _, cluster := range []string{"kms1", "kms2"} {
+			err = m.RegisterKmipServer(ctx, types.KmipServerSpec{
+				ClusterId: types.KeyProviderId{Id: cluster},
+				Info: types.KmipServerInfo{
+					Name:    cluster + "-server",
+					Address: cluster + ".example.com",
+					Port:    5696,
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		clusters, err := m.ListKmipServers(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(clusters) != 2 {
+			t.Fatalf("clusters=%d", len(clusters))
+		}
+
+		// the first registered cluster is the default
+		id, err := m.DefaultClusterID(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id != "kms1" {
+			t.Errorf("default=%s", id)
+		}
+
+		if err = m.MarkDefault(ctx, "kms2"); err != nil {
+			t.Fatal(err)
+		}
+		if id, _ = m.DefaultClusterID(ctx); id != "kms2" {
+			t.Errorf("default=%s", id)
+		}
+		if err = m.MarkDefault(ctx, "enoent"); err == nil {
+			t.Error("expected error")
+		}
+
+		// trust establishment
+		if err = m.UploadKmipServerCert(ctx, "kms2", "server-cert"); err != nil {
+			t.Fatal(err)
+		}
+		info, err := m.RetrieveKmipServerCert(ctx, "kms2", clusters[1].Servers[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Certificate != "server-cert" || !*info.ClientTrustServer {
+			t.Errorf("info=%#v", info)
+		}
+		if err = m.UploadClientCert(ctx, "kms2", "client-cert", "client-key"); err != nil {
+			t.Fatal(err)
+		}
+		cert, err := m.RetrieveClientCert(ctx, "kms2")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cert != "client-cert" {
+			t.Errorf("cert=%s", cert)
+		}
+
+		// keys
+		key, err := m.GenerateKey(ctx, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if key.ProviderId.Id != "kms2" {
+			t.Errorf("provider=%s", key.ProviderId.Id)
+		}
+		if _, err = m.GenerateKey(ctx, "kms1"); err != nil {
+			t.Fatal(err)
+		}
+
+		keys, err := m.ListKeys(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(keys) != 2 {
+			t.Errorf("keys=%d", len(keys))
+		}
+
+		res, err := m.RemoveKeys(ctx, []types.CryptoKeyId{*key}, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(res) != 1 || !res[0].Success {
+			t.Errorf("res=%#v", res)
+		}
+
+		if err = m.RemoveKmipServer(ctx, "kms1", "kms1-server"); err != nil {
+			t.Fatal(err)
+		}
+		if clusters, _ = m.ListKmipServers(ctx); len(clusters) != 1 {
+			t.Errorf("clusters=%d", len(clusters))
+		}
+	})
+}
+
+func TestCryptoManagerKmipESX(t *testing.T) {
+	Test(func(ctx context.Context, c *vim25.Client) {
+		if _, err := object.GetCryptoManagerKmip(c); err != object.ErrNotSupported {
+			t.Errorf("err=%v", err)
+		}
+	}, ESX())
+}
@@ -17,6 +17,9 @@ limitations under the License.
 package simulator
 
 import (
+	"strconv"
+
+	"github.com/vmware/govmomi/simulator/internal"
 	"github.com/vmware/govmomi/vim25/methods"
 	"github.com/vmware/govmomi/vim25/mo"
 	"github.com/vmware/govmomi/vim25/soap"
@@ -25,6 +28,9 @@ import (
 
 type DistributedVirtualSwitch struct {
 	mo.DistributedVirtualSwitch
+
+	nport int
+	ports map[string]types.DVPortConfigInfo
 }
 
 func (s *DistributedVirtualSwitch) AddDVPortgroupTask(c *types.AddDVPortgroup_Task) soap.HasFault {
@@ -65,6 +71,7 @@ func (s *DistributedVirtualSwitch) AddDVPortgroupTask(c *types.AddDVPortgroup_Ta
 				AutoExpand:                   spec.AutoExpand,
 				VmVnicNetworkResourcePoolKey: spec.VmVnicNetworkResourcePoolKey,
 			}
+			pg.nsx.BackingType = internal.DistributedVirtualPortgroupBackingTypeStandard
 
 			if pg.Config.DefaultPortConfig == nil {
 				pg.Config.DefaultPortConfig = &types.VMwareDVSPortSetting{
@@ -218,11 +225,78 @@ func (s *DistributedVirtualSwitch) ReconfigureDvsTask(req *types.ReconfigureDvs_
 func (s *DistributedVirtualSwitch) FetchDVPorts(req *types.FetchDVPorts) soap.HasFault {
 	body := &methods.FetchDVPortsBody{}
 	body.Res = &types.FetchDVPortsResponse{
-		Returnval: s.dvPortgroups(req.Criteria),
+		Returnval: s.dvPorts(req.Criteria),
+	}
+	return body
+}
+
+func (s *DistributedVirtualSwitch) RefreshDVPortState(req *types.RefreshDVPortState) soap.HasFault {
+	body := &methods.RefreshDVPortStateBody{}
+
+	for _, key := range req.PortKeys {
+		if s.dvPort(key) == nil {
+			body.Fault_ = Fault("", &types.NotFound{})
+			return body
+		}
 	}
+
+	body.Res = new(types.RefreshDVPortStateResponse)
 	return body
 }
 
+func (s *DistributedVirtualSwitch) ReconfigureDVPortTask(req *types.ReconfigureDVPort_Task) soap.HasFault {
+	task := CreateTask(s, "reconfigureDVPort", func(t *Task) (types.AnyType, types.BaseMethodFault) {
+		for _, spec := range req.Port {
+			if types.ConfigSpecOperation(spec.Operation) != types.ConfigSpecOperationEdit {
+				return nil, &types.NotSupported{}
+			}
+
+			port := s.dvPort(spec.Key)
+			if port == nil {
+				return nil, &types.NotFound{}
+			}
+
+			if spec.ConfigVersion != "" && spec.ConfigVersion != port.Config.ConfigVersion {
+				return nil, &types.ConcurrentAccess{}
+			}
+		}
+
+		if s.ports == nil {
+			s.ports = make(map[string]types.DVPortConfigInfo)
+		}
+
+		for _, spec := range req.Port {
+			config := s.dvPort(spec.Key).Config
+
+			if spec.Name != "" {
+				config.Name = spec.Name
+			}
+			if spec.Description != "" {
+				config.Description = spec.Description
+			}
+			if spec.Scope != nil {
+				config.Scope = spec.Scope
+			}
+			if spec.Setting != nil {
+				config.Setting = spec.Setting
+			}
+
+			version, _ := strconv.Atoi(config.ConfigVersion)
+			config.ConfigVersion = strconv.Itoa(version + 1)
+
+			s.ports[spec.Key] = config
+		}
+
+		return nil, nil
+	})
+
+	return &methods.ReconfigureDVPort_TaskBody{
+		Res: &types.ReconfigureDVPort_TaskResponse{
+			Returnval: task.Run(),
+		},
+	}
+}
+
 func (s *DistributedVirtualSwitch) DestroyTask(req *types.Destroy_Task) soap.HasFault {
 	task := CreateTask(s, "destroy", func(t *Task) (types.AnyType, types.BaseMethodFault) {
 		f := Map.getEntityParent(s, "Folder").(*Folder)
@@ -237,28 +311,184 @@ func (s *DistributedVirtualSwitch) DestroyTask(req *types.Destroy_Task) soap.Has
 	}
 }
 
-func (s *DistributedVirtualSwitch) dvPortgroups(_ *types.DistributedVirtualSwitchPortCriteria) []types.DistributedVirtualPort {
-	// TODO(agui): Filter is not implemented yet
+// dvPortConnectee is a virtual machine nic connected to a port
+type dvPortConnectee struct {
+	vm  *VirtualMachine
+	nic *types.VirtualEthernetCard
+}
+
+// connectees returns the virtual machine nics connected to this switch, keyed by port
+func (s *DistributedVirtualSwitch) connectees() map[string]dvPortConnectee {
+	ports := make(map[string]dvPortConnectee)
+
+	for _, obj := range Map.All("VirtualMachine") {
+		vm := obj.(*VirtualMachine)
+		if vm.Config == nil {
+			continue
+		}
+
+		for _, device := range vm.Config.Hardware.Device {
+			card, ok := device.(types.BaseVirtualEthernetCard)
+			if !ok {
+				continue
+			}
+
+			nic := card.GetVirtualEthernetCard()
+			b, ok := nic.Backing.(*types.VirtualEthernetCardDistributedVirtualPortBackingInfo)
+			if ok && b.Port.SwitchUuid == s.Uuid && b.Port.PortKey != "" {
+				ports[b.Port.PortKey] = dvPortConnectee{vm, nic}
+			}
+		}
+	}
+
+	return ports
+}
+
+// allocatePort adds a new port to the given portgroup, returning the port key
+func (s *DistributedVirtualSwitch) allocatePort(pg *DistributedVirtualPortgroup) string {
+	key := strconv.Itoa(s.nport)
+	s.nport++
+
+	pg.PortKeys = append(pg.PortKeys, key)
+
+	return key
+}
+
+// connectPort returns a copy of the given backing, connected to a new port unless the backing's port is free.
+func (s *DistributedVirtualSwitch) connectPort(vm *VirtualMachine, pg *DistributedVirtualPortgroup, b *types.VirtualEthernetCardDistributedVirtualPortBackingInfo) *types.VirtualEthernetCardDistributedVirtualPortBackingInfo {
+	backing := *b
+
+	if backing.Port.PortKey != "" {
+		c, ok := s.connectees()[backing.Port.PortKey]
+		if !ok || c.vm == vm {
+			return &backing
+		}
+	}
+
+	backing.Port.PortKey = s.allocatePort(pg)
+
+	return &backing
+}
+
+func (s *DistributedVirtualSwitch) newDVPort(pg *DistributedVirtualPortgroup, key string, connectees map[string]dvPortConnectee) types.DistributedVirtualPort {
+	port := types.DistributedVirtualPort{
+		DvsUuid:      s.Uuid,
+		Key:          key,
+		PortgroupKey: pg.Key,
+		Config: types.DVPortConfigInfo{
+			Setting:       pg.Config.DefaultPortConfig,
+			ConfigVersion: "0",
+		},
+		State: &types.DVPortState{
+			RuntimeInfo: new(types.DVPortStatus),
+		},
+	}
+
+	if config, ok := s.ports[key]; ok {
+		port.Config = config
+	}
+
+	if setting, ok := port.Config.Setting.(*types.VMwareDVSPortSetting); ok && setting.Blocked != nil {
+		port.State.RuntimeInfo.Blocked = setting.Blocked.Value != nil && *setting.Blocked.Value
+	}
+
+	if c, ok := connectees[key]; ok {
+		port.Connectee = &types.DistributedVirtualSwitchPortConnectee{
+			ConnectedEntity: &c.vm.Self,
+			NicKey:          strconv.Itoa(int(c.nic.Key)),
+			Type:            string(types.DistributedVirtualSwitchPortConnecteeConnecteeTypeVmVnic),
+		}
+		port.ProxyHost = c.vm.Runtime.Host
+
+		status := port.State.RuntimeInfo
+		status.MacAddress = c.nic.MacAddress
+		status.LinkUp = c.vm.Runtime.PowerState == types.VirtualMachinePowerStatePoweredOn && !status.Blocked
+		if status.LinkUp {
+			status.Mtu = 1500
+		}
+	}
+
+	return port
+}
+
+// dvPort returns the port with the given key or nil if not found
+func (s *DistributedVirtualSwitch) dvPort(key string) *types.DistributedVirtualPort {
+	ports := s.dvPorts(&types.DistributedVirtualSwitchPortCriteria{PortKey: []string{key}})
+	if len(ports) == 0 {
+		return nil
+	}
+	return &ports[0]
+}
+
+func (s *DistributedVirtualSwitch) dvPorts(criteria *types.DistributedVirtualSwitchPortCriteria) []types.DistributedVirtualPort {
+	if criteria == nil {
+		criteria = new(types.DistributedVirtualSwitchPortCriteria)
+	}
+
+	connectees := s.connectees()
+
 	var res []types.DistributedVirtualPort
 	for _, ref := range s.Portgroup {
 		pg := Map.Get(ref).(*DistributedVirtualPortgroup)
-		res = append(res, types.DistributedVirtualPort{
-			DvsUuid: s.Uuid,
-			Key:     pg.Key,
-			Config: types.DVPortConfigInfo{
-				Setting: pg.Config.DefaultPortConfig,
-			},
-		})
 
-		for _, key := range pg.PortKeys {
-			res = append(res, types.DistributedVirtualPort{
-				DvsUuid: s.Uuid,
-				Key:     key,
-				Config: types.DVPortConfigInfo{
-					Setting: pg.Config.DefaultPortConfig,
-				},
-			})
+		if criteria.Inside != nil && len(criteria.PortgroupKey) != 0 {
+			inside := false
+			for _, key := range criteria.PortgroupKey {
+				if key == pg.Key {
+					inside = true
+				}
+			}
+			if inside != *criteria.Inside {
+				continue
+			}
+		} else if criteria.Inside != nil && !*criteria.Inside {
+			continue // all ports are inside a portgroup
+		}
+
+		keys := append([]string{pg.Key}, pg.PortKeys...)
+
+		for _, key := range keys {
+			port := s.newDVPort(pg, key, connectees)
+
+			if s.matchDVPort(criteria, &port) {
+				res = append(res, port)
+			}
 		}
 	}
+
 	return res
 }
+
+func (s *DistributedVirtualSwitch) matchDVPort(criteria *types.DistributedVirtualSwitchPortCriteria, port *types.DistributedVirtualPort) bool {
+	if criteria.Connected != nil && *criteria.Connected != (port.Connectee != nil) {
+		return false
+	}
+
+	if criteria.Active != nil && *criteria.Active != port.State.RuntimeInfo.LinkUp {
+		return false
+	}
+
+	if criteria.UplinkPort != nil && *criteria.UplinkPort {
+		return false // uplink ports are not simulated
+	}
+
+	if len(criteria.PortKey) != 0 {
+		found := false
+		for _, key := range criteria.PortKey {
+			if key == port.Key {
+				found = true
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if len(criteria.Host) != 0 {
+		if port.ProxyHost == nil || FindReference(criteria.Host, *port.ProxyHost) == nil {
+			return false
+		}
+	}
+
+	return true
+}
@@ -24,6 +24,8 @@ import (
 	"github.com/vmware/govmomi/find"
 	"github.com/vmware/govmomi/object"
 	"github.com/vmware/govmomi/task"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/mo"
 	"github.com/vmware/govmomi/vim25/types"
 )
 
@@ -168,3 +170,160 @@ func TestDVS(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestDVPorts(t *testing.T) {
+	Test(func(ctx context.Context, c *vim25.Client) {
+		finder := find.NewFinder(c)
+
+		net, err := finder.Network(ctx, "DVS0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		dvs := net.(*object.DistributedVirtualSwitch)
+
+		vm, err := finder.VirtualMachine(ctx, "DC0_H0_VM0")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ports, err := dvs.FetchDVPortsByVirtualMachine(ctx, vm.Reference())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(ports) != 1 {
+			t.Fatalf("ports=%d", len(ports))
+		}
+		port := ports[0]
+
+		if !port.State.RuntimeInfo.LinkUp {
+			t.Error("expected link up")
+		}
+
+		devices, err := vm.Device(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		nic := devices.SelectByType((*types.VirtualEthernetCard)(nil))[0].(types.BaseVirtualEthernetCard).GetVirtualEthernetCard()
+		backing := nic.Backing.(*types.VirtualEthernetCardDistributedVirtualPortBackingInfo)
+		if backing.Port.PortKey != port.Key {
+			t.Errorf("port key=%s, expected %s", backing.Port.PortKey, port.Key)
+		}
+
+		byMAC, err := dvs.FetchDVPortByMAC(ctx, nic.MacAddress)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if byMAC.Key != port.Key {
+			t.Errorf("port key=%s, expected %s", byMAC.Key, port.Key)
+		}
+		if _, err = dvs.FetchDVPortByMAC(ctx, "00:00:00:00:00:00"); err == nil {
+			t.Error("expected error")
+		}
+
+		var props mo.VirtualMachine
+		if err = vm.Properties(ctx, vm.Reference(), []string{"runtime.host"}, &props); err != nil {
+			t.Fatal(err)
+		}
+
+		criteria := []struct {
+			criteria *object.DVPortCriteria
+			expect   bool
+		}{
+			{object.NewDVPortCriteria(), true},
+			{object.NewDVPortCriteria().Connected(true).Active(true), true},
+			{object.NewDVPortCriteria().Connected(false), false},
+			{object.NewDVPortCriteria().Inside(port.PortgroupKey).PortKey(port.Key), true},
+			{object.NewDVPortCriteria().Outside(port.PortgroupKey), false},
+			{object.NewDVPortCriteria().Uplink(true), false},
+			{object.NewDVPortCriteria().Host(*props.Runtime.Host), true},
+		}
+
+		for i, test := range criteria {
+			res, err := dvs.FetchDVPorts(ctx, test.criteria.Criteria())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			found := false
+			for _, p := range res {
+				if p.Key == port.Key {
+					found = true
+				}
+			}
+			if found != test.expect {
+				t.Errorf("%d: found=%t", i, found)
+			}
+		}
+
+		stats, err := dvs.DVPortStatistics(ctx, port.Key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := stats[port.Key]; !ok || len(stats) != 1 {
+			t.Errorf("stats=%#v", stats)
+		}
+
+		if err = dvs.RefreshDVPortState(ctx, port.Key); err != nil {
+			t.Fatal(err)
+		}
+
+		setting := &types.VMwareDVSPortSetting{
+			DVPortSetting: types.DVPortSetting{
+				Blocked: &types.BoolPolicy{Value: types.NewBool(true)},
+			},
+		}
+
+		task, err := dvs.ReconfigureDVPort(ctx, []types.DVPortConfigSpec{object.NewDVPortConfigSpec(port, setting)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = task.Wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		// stale ConfigVersion
+		task, err = dvs.ReconfigureDVPort(ctx, []types.DVPortConfigSpec{object.NewDVPortConfigSpec(port, setting)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = task.Wait(ctx); err == nil {
+			t.Error("expected error")
+		}
+
+		ports, err = dvs.FetchDVPorts(ctx, object.NewDVPortCriteria().PortKey(port.Key).Criteria())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ports[0].State.RuntimeInfo.Blocked || ports[0].State.RuntimeInfo.LinkUp {
+			t.Errorf("runtime=%#v", ports[0].State.RuntimeInfo)
+		}
+		if ports[0].Config.ConfigVersion == port.Config.ConfigVersion {
+			t.Error("expected ConfigVersion change")
+		}
+
+		// a clone is connected to its own port
+		folder, err := finder.DefaultFolder(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		task, err = vm.Clone(ctx, folder, "clone", types.VirtualMachineCloneSpec{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		info, err := task.WaitForResult(ctx, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		clone, err := dvs.FetchDVPortsByVirtualMachine(ctx, info.Result.(types.ManagedObjectReference))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(clone) != 1 || clone[0].Key == port.Key {
+			t.Errorf("clone ports=%#v", clone)
+		}
+		if ports, _ = dvs.FetchDVPortsByVirtualMachine(ctx, vm.Reference()); len(ports) != 1 || ports[0].Key != port.Key {
+			t.Errorf("source ports=%#v", ports)
+		}
+	})
+}
@@ -26,6 +26,8 @@ import (
 	"github.com/vmware/govmomi"
 	"github.com/vmware/govmomi/find"
 	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/simulator/internal"
+	"github.com/vmware/govmomi/vim25"
 	"github.com/vmware/govmomi/vim25/types"
 )
 
@@ -273,3 +275,106 @@ func TestFinderDefaultHostVPX(t *testing.T) {
 		t.Errorf("unexpected error type=%T", err)
 	}
 }
+
+func TestFinderNSX(t *testing.T) {
+	m := VPX()
+	m.PortgroupNSX = 1
+	m.OpaqueNetwork = 1
+
+	Test(func(ctx context.Context, c *vim25.Client) {
+		finder := find.NewFinder(c)
+		dc, err := finder.DefaultDatacenter(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		finder.SetDatacenter(dc)
+
+		net, err := finder.Network(ctx, "DC0_NSX0")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		opaque, ok := net.(*object.OpaqueNetwork)
+		if !ok {
+			t.Fatalf("network type=%T", net)
+		}
+
+		summary, err := opaque.Summary(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		backing, err := net.EthernetCardBackingInfo(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if backing.(*types.VirtualEthernetCardOpaqueNetworkBackingInfo).OpaqueNetworkId != summary.OpaqueNetworkId {
+			t.Errorf("backing=%#v", backing)
+		}
+
+		// lookup by NSX ID
+		net, err = finder.Network(ctx, summary.OpaqueNetworkId)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if net.Reference() != opaque.Reference() {
+			t.Errorf("network=%s", net.Reference())
+		}
+
+		pg := Map.Any("DistributedVirtualPortgroup").(*DistributedVirtualPortgroup)
+		for _, ref := range Map.getEntityParent(pg, "Folder").(*Folder).ChildEntity {
+			if p, ok := Map.Get(ref).(*DistributedVirtualPortgroup); ok && p.nsx.BackingType == internal.DistributedVirtualPortgroupBackingTypeNsx {
+				pg = p
+			}
+		}
+
+		for _, id := range []string{pg.nsx.LogicalSwitchUuid, pg.nsx.SegmentId} {
+			net, err = finder.Network(ctx, id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if net.Reference() != pg.Reference() {
+				t.Errorf("network=%s", net.Reference())
+			}
+		}
+
+		_, err = finder.Network(ctx, "enoent")
+		if _, ok := err.(*find.NotFoundError); !ok {
+			t.Errorf("err=%v", err)
+		}
+
+		// attach a VM nic to the opaque network
+		vm, err := finder.VirtualMachine(ctx, "DC0_H0_VM0")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		devices, err := vm.Device(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		nic, err := devices.CreateEthernetCard("", backing)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err = vm.AddDevice(ctx, nic); err != nil {
+			t.Fatal(err)
+		}
+
+		devices, err = vm.Device(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if n := len(devices.SelectByBackingInfo(backing)); n != 1 {
+			t.Errorf("%d nics with backing %s", n, summary.OpaqueNetworkId)
+		}
+
+		if FindReference(Map.Get(vm.Reference()).(*VirtualMachine).Network, opaque.Reference()) == nil {
+			t.Error("vm.network does not include the opaque network")
+		}
+	}, m)
+}
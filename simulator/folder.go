@@ -22,6 +22,7 @@ import (
 	"path"
 	"strings"
 
+	"github.com/google/uuid"
 	"github.com/vmware/govmomi/object"
 	"github.com/vmware/govmomi/vim25/methods"
 	"github.com/vmware/govmomi/vim25/mo"
@@ -56,7 +57,7 @@ func (f *Folder) update(o mo.Reference, u func(mo.Reference, *[]types.ManagedObj
 	dc := Map.getEntityDatacenter(f)
 
 	switch ref.Type {
-	case "Network", "DistributedVirtualSwitch", "DistributedVirtualPortgroup":
+	case "Network", "OpaqueNetwork", "DistributedVirtualSwitch", "DistributedVirtualPortgroup":
 		u(dc, &dc.Network, ref)
 	case "Datastore":
 		u(dc, &dc.Datastore, ref)
@@ -82,7 +83,15 @@ func (f *Folder) putChild(o mo.Entity) {
 	case *mo.Network:
 		e.Summary = networkSummary(e)
 	case *mo.OpaqueNetwork:
-		e.Summary = networkSummary(&e.Network)
+		summary, ok := e.Summary.(*types.OpaqueNetworkSummary)
+		if !ok {
+			summary = &types.OpaqueNetworkSummary{
+				OpaqueNetworkId:   uuid.New().String(),
+				OpaqueNetworkType: "nsx.LogicalSwitch",
+			}
+		}
+		summary.NetworkSummary = *networkSummary(&e.Network)
+		e.Summary = summary
 	case *DistributedVirtualPortgroup:
 		e.Summary = networkSummary(&e.Network)
 	}
@@ -334,6 +343,7 @@ func (c *createVM) Run(task *Task) (types.AnyType, types.BaseMethodFault) {
 			rp.Vm = append(rp.Vm, vm.Self)
 		case *VirtualApp:
 			rp.Vm = append(rp.Vm, vm.Self)
+			rp.addEntity(vm.Self, vm.Name)
 		}
 	})
 
@@ -0,0 +1,210 @@
+/*
+Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"net"
+
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/simulator/internal"
+	"github.com/vmware/govmomi/vim25/methods"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+type HostCertificateManager struct {
+	mo.HostCertificateManager
+
+	Host *mo.HostSystem
+
+	caCert []string
+	caCrl  []string
+}
+
+func NewHostCertificateManager(h *mo.HostSystem) *HostCertificateManager {
+	m := &HostCertificateManager{Host: h}
+
+	if cert, err := parseCertificate(string(internal.LocalhostCert)); err == nil {
+		m.setCertificate(cert)
+	}
+
+	return m
+}
+
+// parseCertificate decodes the first PEM block of s as an x509.Certificate
+func parseCertificate(s string) (*x509.Certificate, error) {
+	block, _ := pem.Decode([]byte(s))
+	if block == nil {
+		return nil, errors.New("failed to decode PEM certificate")
+	}
+
+	return x509.ParseCertificate(block.Bytes)
+}
+
+func (m *HostCertificateManager) setCertificate(cert *x509.Certificate) {
+	info := new(object.HostCertificateInfo).FromCertificate(cert)
+	info.Status = string(types.HostCertificateManagerCertificateInfoCertificateStatusGood)
+
+	m.CertificateInfo = info.HostCertificateManagerCertificateInfo
+}
+
+// ipAddress returns the address of the host's first vmkernel nic, falling back to the host name
+func (m *HostCertificateManager) ipAddress() string {
+	if m.Host.Config != nil && m.Host.Config.Network != nil {
+		for _, nic := range m.Host.Config.Network.Vnic {
+			if nic.Spec.Ip != nil && net.ParseIP(nic.Spec.Ip.IpAddress) != nil {
+				return nic.Spec.Ip.IpAddress
+			}
+		}
+	}
+
+	return m.Host.Name
+}
+
+func (m *HostCertificateManager) csr(info *object.HostCertificateInfo) (string, *soap.Fault) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return "", Fault(err.Error(), &types.RuntimeFault{})
+	}
+
+	req := x509.CertificateRequest{Subject: *info.SubjectName()}
+	if ip := net.ParseIP(req.Subject.CommonName); ip != nil {
+		req.IPAddresses = []net.IP{ip}
+	} else if req.Subject.CommonName != "" {
+		req.DNSNames = []string{req.Subject.CommonName}
+	}
+
+	der, err := x509.CreateCertificateRequest(rand.Reader, &req, key)
+	if err != nil {
+		return "", Fault(err.Error(), &types.InvalidArgument{})
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der})), nil
+}
+
+func (m *HostCertificateManager) GenerateCertificateSigningRequest(req *types.GenerateCertificateSigningRequest) soap.HasFault {
+	body := new(methods.GenerateCertificateSigningRequestBody)
+
+	name := m.Host.Name
+	if req.UseIpAddressAsCommonName {
+		name = m.ipAddress()
+	}
+
+	info := &object.HostCertificateInfo{
+		HostCertificateManagerCertificateInfo: types.HostCertificateManagerCertificateInfo{
+			Subject: "CN=" + name,
+		},
+	}
+
+	csr, fault := m.csr(info)
+	if fault != nil {
+		body.Fault_ = fault
+		return body
+	}
+
+	body.Res = &types.GenerateCertificateSigningRequestResponse{Returnval: csr}
+
+	return body
+}
+
+func (m *HostCertificateManager) GenerateCertificateSigningRequestByDn(req *types.GenerateCertificateSigningRequestByDn) soap.HasFault {
+	body := new(methods.GenerateCertificateSigningRequestByDnBody)
+
+	info := &object.HostCertificateInfo{
+		HostCertificateManagerCertificateInfo: types.HostCertificateManagerCertificateInfo{
+			Subject: req.DistinguishedName,
+		},
+	}
+
+	if info.SubjectName().CommonName == "" {
+		body.Fault_ = Fault("", &types.InvalidArgument{InvalidProperty: "distinguishedName"})
+		return body
+	}
+
+	csr, fault := m.csr(info)
+	if fault != nil {
+		body.Fault_ = fault
+		return body
+	}
+
+	body.Res = &types.GenerateCertificateSigningRequestByDnResponse{Returnval: csr}
+
+	return body
+}
+
+func (m *HostCertificateManager) InstallServerCertificate(req *types.InstallServerCertificate) soap.HasFault {
+	body := new(methods.InstallServerCertificateBody)
+
+	cert, err := parseCertificate(req.Cert)
+	if err != nil {
+		body.Fault_ = Fault("", &types.InvalidArgument{InvalidProperty: "cert"})
+		return body
+	}
+
+	m.setCertificate(cert)
+
+	body.Res = new(types.InstallServerCertificateResponse)
+
+	return body
+}
+
+func (m *HostCertificateManager) NotifyAffectedServices(*internal.NotifyAffectedServices) soap.HasFault {
+	return &internal.NotifyAffectedServicesBody{
+		Res: new(internal.NotifyAffectedServicesResponse),
+	}
+}
+
+func (m *HostCertificateManager) ListCACertificates(*types.ListCACertificates) soap.HasFault {
+	return &methods.ListCACertificatesBody{
+		Res: &types.ListCACertificatesResponse{
+			Returnval: m.caCert,
+		},
+	}
+}
+
+func (m *HostCertificateManager) ListCACertificateRevocationLists(*types.ListCACertificateRevocationLists) soap.HasFault {
+	return &methods.ListCACertificateRevocationListsBody{
+		Res: &types.ListCACertificateRevocationListsResponse{
+			Returnval: m.caCrl,
+		},
+	}
+}
+
+func (m *HostCertificateManager) ReplaceCACertificatesAndCRLs(req *types.ReplaceCACertificatesAndCRLs) soap.HasFault {
+	body := new(methods.ReplaceCACertificatesAndCRLsBody)
+
+	for _, cert := range req.CaCert {
+		if _, err := parseCertificate(cert); err != nil {
+			body.Fault_ = Fault("", &types.InvalidArgument{InvalidProperty: "caCert"})
+			return body
+		}
+	}
+
+	m.caCert = req.CaCert
+	m.caCrl = req.CaCrl
+
+	body.Res = new(types.ReplaceCACertificatesAndCRLsResponse)
+
+	return body
+}
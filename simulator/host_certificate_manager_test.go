@@ -0,0 +1,143 @@
+/*
+Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25"
+)
+
+func TestHostCertificateManager(t *testing.T) {
+	Test(func(ctx context.Context, c *vim25.Client) {
+		host := object.NewHostSystem(c, Map.Any("HostSystem").Reference())
+
+		m, err := host.ConfigManager().CertificateManager(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		info, err := m.CertificateInfo(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Status != "good" {
+			t.Errorf("status=%s", info.Status)
+		}
+
+		// CA signs a certificate for the host's CSR
+		caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		now := time.Now()
+		ca := &x509.Certificate{
+			SerialNumber:          big.NewInt(1),
+			Subject:               pkix.Name{CommonName: "govmomi CA"},
+			NotBefore:             now,
+			NotAfter:              now.Add(time.Hour),
+			IsCA:                  true,
+			BasicConstraintsValid: true,
+			KeyUsage:              x509.KeyUsageCertSign,
+		}
+		der, err := x509.CreateCertificate(rand.Reader, ca, ca, caKey.Public(), caKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		caPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+
+		s, err := m.GenerateCertificateSigningRequestByDn(ctx, "CN=esx.example.com,O=VMware")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		block, _ := pem.Decode([]byte(s))
+		csr, err := x509.ParseCertificateRequest(block.Bytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if csr.Subject.CommonName != "esx.example.com" {
+			t.Errorf("subject=%s", csr.Subject)
+		}
+
+		if _, err = m.GenerateCertificateSigningRequest(ctx, true); err != nil {
+			t.Fatal(err)
+		}
+
+		cert := &x509.Certificate{
+			SerialNumber: big.NewInt(2),
+			Subject:      csr.Subject,
+			NotBefore:    now,
+			NotAfter:     now.Add(time.Hour),
+		}
+		der, err = x509.CreateCertificate(rand.Reader, cert, ca, csr.PublicKey, caKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = m.InstallServerCertificate(ctx, string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = m.InstallServerCertificate(ctx, "invalid"); err == nil {
+			t.Error("expected error")
+		}
+
+		info, err = m.CertificateInfo(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.IssuerName().CommonName != "govmomi CA" || info.SubjectName().CommonName != "esx.example.com" {
+			t.Errorf("issuer=%s subject=%s", info.Issuer, info.Subject)
+		}
+
+		// CA trust
+		if err = m.ReplaceCACertificatesAndCRLs(ctx, []string{caPEM}, nil); err != nil {
+			t.Fatal(err)
+		}
+		if err = m.ReplaceCACertificatesAndCRLs(ctx, []string{"invalid"}, nil); err == nil {
+			t.Error("expected error")
+		}
+
+		certs, err := m.ListCACertificates(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(certs) != 1 || certs[0] != caPEM {
+			t.Errorf("certs=%v", certs)
+		}
+
+		crls, err := m.ListCACertificateRevocationLists(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(crls) != 0 {
+			t.Errorf("crls=%v", crls)
+		}
+	})
+}
@@ -33,6 +33,14 @@ var (
 
 type HostSystem struct {
 	mo.HostSystem
+
+	maintenance *maintenance
+}
+
+// maintenance tracks an EnterMaintenanceMode task waiting for VMs to be evacuated from the host.
+type maintenance struct {
+	task       *Task
+	poweredOff bool
 }
 
 func NewHostSystem(host mo.HostSystem) *HostSystem {
@@ -77,6 +85,19 @@ func NewHostSystem(host mo.HostSystem) *HostSystem {
 	return hs
 }
 
+// putCertificateManager registers the HostCertificateManager of a registered host.
+// Its reference is derived from that of the host, rather than allocated by the Registry,
+// such that adding the manager does not shift the references of objects created later.
+func (h *HostSystem) putCertificateManager() {
+	m := NewHostCertificateManager(&h.HostSystem)
+	m.Self = *esx.HostSystem.ConfigManager.CertificateManager
+	if Map.IsVPX() {
+		m.Self.Value = "certificatemanager-" + h.Self.Value
+	}
+	h.ConfigManager.CertificateManager = &m.Self
+	Map.Put(m)
+}
+
 func (h *HostSystem) configure(spec types.HostConnectSpec, connected bool) {
 	h.Runtime.ConnectionState = types.HostSystemConnectionStateDisconnected
 	if connected {
@@ -159,6 +180,7 @@ func CreateDefaultESX(f *Folder) {
 	cr.Host = append(cr.Host, host.Reference())
 	host.Network = cr.Network
 	Map.PutEntity(cr, host)
+	host.putCertificateManager()
 
 	pool := NewResourcePool()
 	cr.ResourcePool = &pool.Self
@@ -192,6 +214,7 @@ func CreateStandaloneHost(f *Folder, spec types.HostConnectSpec) (*HostSystem, t
 
 	Map.PutEntity(cr, Map.NewEntity(host))
 	host.Summary.Host = &host.Self
+	host.putCertificateManager()
 
 	Map.PutEntity(cr, Map.NewEntity(pool))
 
@@ -228,17 +251,85 @@ func (h *HostSystem) DestroyTask(ctx *Context, req *types.Destroy_Task) soap.Has
 	}
 }
 
-func (h *HostSystem) EnterMaintenanceModeTask(spec *types.EnterMaintenanceMode_Task) soap.HasFault {
+// evacuees returns the VMs that must be moved off the host before it can enter maintenance mode.
+func (h *HostSystem) evacuees(poweredOff bool) []*VirtualMachine {
+	var vms []*VirtualMachine
+
+	for _, ref := range h.Vm {
+		vm, ok := Map.Get(ref).(*VirtualMachine)
+		if !ok {
+			continue
+		}
+
+		if poweredOff || vm.Runtime.PowerState != types.VirtualMachinePowerStatePoweredOff {
+			vms = append(vms, vm)
+		}
+	}
+
+	return vms
+}
+
+// evacuated completes the pending EnterMaintenanceMode task, if any, once the host has been evacuated.
+func (h *HostSystem) evacuated(ctx *Context) {
+	ctx.WithLock(h, func() {
+		m := h.maintenance
+		if m == nil || len(h.evacuees(m.poweredOff)) != 0 {
+			return
+		}
+
+		h.maintenance = nil
+		m.task.finish(m.task.Execute(m.task))
+	})
+}
+
+func (h *HostSystem) EnterMaintenanceModeTask(ctx *Context, spec *types.EnterMaintenanceMode_Task) soap.HasFault {
+	ctx.Caller = &h.Self
+
 	task := CreateTask(h, "enterMaintenanceMode", func(t *Task) (types.AnyType, types.BaseMethodFault) {
 		h.Runtime.InMaintenanceMode = true
 		return nil, nil
 	})
 
-	return &methods.EnterMaintenanceMode_TaskBody{
+	body := &methods.EnterMaintenanceMode_TaskBody{
 		Res: &types.EnterMaintenanceMode_TaskResponse{
-			Returnval: task.Run(),
+			Returnval: task.Self,
 		},
 	}
+
+	m := &maintenance{
+		task:       task,
+		poweredOff: spec.EvacuatePoweredOffVms != nil && *spec.EvacuatePoweredOffVms,
+	}
+
+	cluster, ok := Map.Get(*h.Parent).(*ClusterComputeResource)
+	if !ok || cluster.evacuate(ctx, h, m.poweredOff) {
+		task.Run()
+		return body
+	}
+
+	// VMs remain on the host until the cluster's recommendations are applied
+	task.start()
+	h.maintenance = m
+
+	if spec.Timeout > 0 {
+		time.AfterFunc(time.Duration(spec.Timeout)*time.Second, func() {
+			timeout := false
+
+			Map.WithLock(h, func() {
+				if h.maintenance == m {
+					h.maintenance = nil
+					timeout = true
+				}
+			})
+
+			if timeout {
+				task.finish(nil, new(types.Timedout))
+				cluster.cancelRecommendations(h)
+			}
+		})
+	}
+
+	return body
 }
 
 func (h *HostSystem) ExitMaintenanceModeTask(spec *types.ExitMaintenanceMode_Task) soap.HasFault {
@@ -19,11 +19,15 @@ package simulator
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/vmware/govmomi"
 	"github.com/vmware/govmomi/find"
 	"github.com/vmware/govmomi/object"
 	"github.com/vmware/govmomi/simulator/esx"
+	"github.com/vmware/govmomi/task"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/types"
 )
 
 func TestDefaultESX(t *testing.T) {
@@ -115,6 +119,73 @@ func TestMaintenanceMode(t *testing.T) {
 	}
 }
 
+func TestMaintenanceModeDrs(t *testing.T) {
+	Test(func(ctx context.Context, c *vim25.Client) {
+		cluster := Map.Any("ClusterComputeResource").(*ClusterComputeResource)
+		drs := &cluster.ConfigurationEx.(*types.ClusterConfigInfoEx).DrsConfig
+		drs.DefaultVmBehavior = types.DrsBehaviorManual
+
+		// VMs are placed on random cluster hosts
+		evacuee := func() *HostSystem {
+			for _, ref := range cluster.Host {
+				h := Map.Get(ref).(*HostSystem)
+				if len(h.Vm) != 0 && !h.Runtime.InMaintenanceMode {
+					return h
+				}
+			}
+			t.Fatal("expected VMs on host")
+			return nil
+		}
+
+		hs := evacuee()
+		host := object.NewHostSystem(c, hs.Self)
+		vms := len(hs.Vm)
+
+		err := host.EnterMaintenanceModeAndWait(ctx, object.MaintenanceModeOptions{Timeout: time.Second})
+		merr, ok := err.(*object.MaintenanceModeError)
+		if !ok {
+			t.Fatalf("err=%#v", err)
+		}
+		if len(merr.VMs) != vms || len(merr.Recommendations) != vms {
+			t.Errorf("vms=%d, recommendations=%d", len(merr.VMs), len(merr.Recommendations))
+		}
+		if _, ok = merr.Err.(task.Error).Fault().(*types.Timedout); !ok {
+			t.Errorf("err=%#v", merr.Err)
+		}
+		if hs.Runtime.InMaintenanceMode || len(cluster.Recommendation) != 0 {
+			t.Error("expected maintenance mode timeout")
+		}
+
+		err = host.EnterMaintenanceModeAndWait(ctx, object.MaintenanceModeOptions{ApplyRecommendations: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !hs.Runtime.InMaintenanceMode || len(hs.Vm) != 0 || len(cluster.Recommendation) != 0 {
+			t.Error("expected host to be evacuated")
+		}
+
+		drs.DefaultVmBehavior = types.DrsBehaviorFullyAutomated
+		hs = evacuee()
+		host = object.NewHostSystem(c, hs.Self)
+
+		err = host.EnterMaintenanceModeAndWait(ctx, object.MaintenanceModeOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !hs.Runtime.InMaintenanceMode || len(hs.Vm) != 0 {
+			t.Error("expected host to be evacuated")
+		}
+
+		for _, ref := range cluster.Host {
+			for _, vm := range Map.Get(ref).(*HostSystem).Vm {
+				if host := *Map.Get(vm).(*VirtualMachine).Runtime.Host; host != ref {
+					t.Errorf("%s runtime.host=%s", vm, host)
+				}
+			}
+		}
+	})
+}
+
 func TestNewHostSystem(t *testing.T) {
 	m := ESX()
 
@@ -24,9 +24,11 @@ import (
 	"os"
 	"path"
 
+	"github.com/google/uuid"
 	"github.com/vmware/govmomi"
 	"github.com/vmware/govmomi/object"
 	"github.com/vmware/govmomi/simulator/esx"
+	"github.com/vmware/govmomi/simulator/internal"
 	"github.com/vmware/govmomi/simulator/vpx"
 	"github.com/vmware/govmomi/vim25"
 	"github.com/vmware/govmomi/vim25/mo"
@@ -66,6 +68,12 @@ type Model struct {
 	// Portgroup specifies the number of DistributedVirtualPortgroup entities to create per Datacenter
 	Portgroup int
 
+	// PortgroupNSX specifies the number of NSX backed DistributedVirtualPortgroup entities to create per Datacenter
+	PortgroupNSX int `json:",omitempty"`
+
+	// OpaqueNetwork specifies the number of OpaqueNetwork entities to create per Datacenter
+	OpaqueNetwork int `json:",omitempty"`
+
 	// Host specifies the number of standalone HostSystems entities to create per Datacenter
 	Host int `json:",omitempty"`
 
@@ -158,7 +166,13 @@ func (m *Model) Count() Model {
 		case "Datacenter":
 			count.Datacenter++
 		case "DistributedVirtualPortgroup":
-			count.Portgroup++
+			if obj.(*DistributedVirtualPortgroup).nsx.BackingType == internal.DistributedVirtualPortgroupBackingTypeNsx {
+				count.PortgroupNSX++
+			} else {
+				count.Portgroup++
+			}
+		case "OpaqueNetwork":
+			count.OpaqueNetwork++
 		case "ClusterComputeResource":
 			count.Cluster++
 		case "Datastore":
@@ -268,7 +282,8 @@ func (m *Model) Create() error {
 					config.Files.VmPathName+" "+path.Join(name, "disk1.vmdk"))
 				disk.CapacityInKB = 1024
 
-				devices = append(devices, scsi, cdrom, disk, &nic)
+				card := nic // each VM needs its own nic, as vcsim assigns the DVPort key
+				devices = append(devices, scsi, cdrom, disk, &card)
 
 				config.DeviceChange, _ = devices.ConfigSpec(types.VirtualDeviceConfigSpecOperationAdd)
 
@@ -343,7 +358,7 @@ func (m *Model) Create() error {
 			nfolder++
 		}
 
-		if m.Portgroup > 0 {
+		if m.Portgroup > 0 || m.PortgroupNSX > 0 {
 			var spec types.DVSCreateSpec
 			spec.ConfigSpec = &types.VMwareDVSConfigSpec{}
 			spec.ConfigSpec.GetDVSConfigSpec().Name = m.fmtName("DVS", 0)
@@ -382,6 +397,43 @@ func (m *Model) Create() error {
 					vmnet, _ = object.NewDistributedVirtualPortgroup(client, pg.Reference()).EthernetCardBackingInfo(ctx)
 				}
 			}
+
+			for npg := 0; npg < m.PortgroupNSX; npg++ {
+				name := m.fmtName(dcName+"_NSXPG", npg)
+
+				task, err = dvs.AddPortgroup(ctx, []types.DVPortgroupConfigSpec{{Name: name}})
+				if err != nil {
+					return err
+				}
+
+				err = task.Wait(ctx)
+				if err != nil {
+					return err
+				}
+
+				// The NSX backing is normally configured by NSX and is not part of the generated (6.7) DVPortgroupConfigSpec
+				net := Map.Get(folders.NetworkFolder.Reference()).(*Folder)
+				pg := Map.FindByName(name, net.ChildEntity).(*DistributedVirtualPortgroup)
+				id := uuid.New().String()
+				pg.nsx = internal.DVPortgroupConfigInfo{
+					LogicalSwitchUuid: id,
+					SegmentId:         "/infra/segments/vnet_" + id,
+					BackingType:       internal.DistributedVirtualPortgroupBackingTypeNsx,
+				}
+			}
+		}
+
+		if m.OpaqueNetwork > 0 {
+			// There is no API to create an OpaqueNetwork, these are normally created by NSX
+			net := Map.Get(folders.NetworkFolder.Reference()).(*Folder)
+
+			for nnet := 0; nnet < m.OpaqueNetwork; nnet++ {
+				network := &mo.OpaqueNetwork{}
+				network.Name = m.fmtName(dcName+"_NSX", nnet)
+				network.Entity().Name = network.Name
+
+				Map.WithLock(net, func() { net.putChild(network) })
+			}
 		}
 
 		for nhost := 0; nhost < m.Host; nhost++ {
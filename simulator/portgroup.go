@@ -17,6 +17,10 @@ limitations under the License.
 package simulator
 
 import (
+	"reflect"
+	"strings"
+
+	"github.com/vmware/govmomi/simulator/internal"
 	"github.com/vmware/govmomi/vim25/methods"
 	"github.com/vmware/govmomi/vim25/mo"
 	"github.com/vmware/govmomi/vim25/soap"
@@ -25,6 +29,19 @@ import (
 
 type DistributedVirtualPortgroup struct {
 	mo.DistributedVirtualPortgroup
+
+	nsx internal.DVPortgroupConfigInfo
+}
+
+// propertyValue returns the value of the config properties that are not included in mo.DistributedVirtualPortgroup
+func (s *DistributedVirtualPortgroup) propertyValue(path string) (interface{}, bool) {
+	name := strings.TrimPrefix(path, "config.")
+	if name == path {
+		return nil, false
+	}
+
+	val, err := fieldValue(reflect.ValueOf(&s.nsx).Elem(), name)
+	return val, err != errMissingField
 }
 
 func (s *DistributedVirtualPortgroup) ReconfigureDVPortgroupTask(req *types.ReconfigureDVPortgroup_Task) soap.HasFault {
@@ -26,6 +26,8 @@ import (
 	"sync"
 	"time"
 
+	"github.com/google/uuid"
+
 	"github.com/vmware/govmomi/object"
 	"github.com/vmware/govmomi/simulator/internal"
 	"github.com/vmware/govmomi/vim25"
@@ -42,6 +44,13 @@ type PropertyCollector struct {
 	updates []types.ObjectUpdate
 	mu      sync.Mutex
 	cancel  context.CancelFunc
+	pages   map[string]retrievePage
+}
+
+// retrievePage holds the remaining objects of a paged RetrievePropertiesEx request.
+type retrievePage struct {
+	objects []types.ObjectContent
+	max     int32
 }
 
 func NewPropertyCollector(ref types.ManagedObjectReference) object.Reference {
@@ -50,6 +59,12 @@ func NewPropertyCollector(ref types.ManagedObjectReference) object.Reference {
 	return s
 }
 
+// propertyValuer is implemented by objects with properties that are not defined by the generated mo types,
+// such as those added in a newer API version.
+type propertyValuer interface {
+	propertyValue(path string) (interface{}, bool)
+}
+
 var errMissingField = errors.New("missing field")
 var errEmptyField = errors.New("empty field")
 
@@ -180,8 +195,7 @@ func fieldValue(rval reflect.Value, p string) (interface{}, error) {
 			rval = rval.Elem()
 		}
 
-		x := ucFirst(name)
-		val := rval.FieldByName(x)
+		ftype, val := fieldByName(rval, ucFirst(name))
 		if !val.IsValid() {
 			return nil, errMissingField
 		}
@@ -191,7 +205,6 @@ func fieldValue(rval reflect.Value, p string) (interface{}, error) {
 		}
 
 		if i == len(fields)-1 {
-			ftype, _ := rval.Type().FieldByName(x)
 			value = fieldValueInterface(ftype, val)
 			break
 		}
@@ -202,6 +215,24 @@ func fieldValue(rval reflect.Value, p string) (interface{}, error) {
 	return value, nil
 }
 
+// fieldByName is like reflect.Value.FieldByName, but descends into an embedded struct of the same name,
+// such as the mo.ResourcePool embedded in mo.VirtualApp, which has its own ResourcePool field.
+func fieldByName(rval reflect.Value, name string) (reflect.StructField, reflect.Value) {
+	for {
+		field, ok := rval.Type().FieldByName(name)
+		if !ok {
+			return field, reflect.Value{}
+		}
+
+		val := rval.FieldByIndex(field.Index)
+		if !field.Anonymous || val.Kind() != reflect.Struct {
+			return field, val
+		}
+
+		rval = val
+	}
+}
+
 func fieldRefs(f interface{}) []types.ManagedObjectReference {
 	switch fv := f.(type) {
 	case types.ManagedObjectReference:
@@ -310,6 +341,12 @@ func (rr *retrieveResult) collectFields(ctx *Context, rval reflect.Value, fields
 		case nil, errEmptyField:
 			rr.add(ctx, name, val, content)
 		case errMissingField:
+			if obj, ok := Map.Get(content.Obj).(propertyValuer); ok {
+				if val, ok = obj.propertyValue(name); ok {
+					rr.add(ctx, name, val, content)
+					continue
+				}
+			}
 			content.MissingSet = append(content.MissingSet, types.MissingProperty{
 				Path: name,
 				Fault: types.LocalizedMethodFault{Fault: &types.InvalidProperty{
@@ -519,6 +556,7 @@ func (pc *PropertyCollector) RetrievePropertiesEx(ctx *Context, r *types.Retriev
 			objects = append(objects, o)
 		}
 		res.Objects = objects
+		pc.page(res, r.Options.MaxObjects)
 		body.Res = &types.RetrievePropertiesExResponse{
 			Returnval: res,
 		}
@@ -527,6 +565,65 @@ func (pc *PropertyCollector) RetrievePropertiesEx(ctx *Context, r *types.Retriev
 	return body
 }
 
+// page limits res to max objects, saving the remaining objects for ContinueRetrievePropertiesEx.
+func (pc *PropertyCollector) page(res *types.RetrieveResult, max int32) {
+	if max <= 0 || len(res.Objects) <= int(max) {
+		return
+	}
+
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+
+	if pc.pages == nil {
+		pc.pages = make(map[string]retrievePage)
+	}
+
+	res.Token = uuid.New().String()
+	pc.pages[res.Token] = retrievePage{objects: res.Objects[max:], max: max}
+	res.Objects = res.Objects[:max]
+}
+
+func (pc *PropertyCollector) ContinueRetrievePropertiesEx(ctx *Context, r *types.ContinueRetrievePropertiesEx) soap.HasFault {
+	body := &methods.ContinueRetrievePropertiesExBody{}
+
+	pc.mu.Lock()
+	page, ok := pc.pages[r.Token]
+	delete(pc.pages, r.Token)
+	pc.mu.Unlock()
+
+	if !ok {
+		body.Fault_ = Fault("", &types.InvalidArgument{InvalidProperty: "token"})
+		return body
+	}
+
+	res := types.RetrieveResult{Objects: page.objects}
+	pc.page(&res, page.max)
+
+	body.Res = &types.ContinueRetrievePropertiesExResponse{
+		Returnval: res,
+	}
+
+	return body
+}
+
+func (pc *PropertyCollector) CancelRetrievePropertiesEx(ctx *Context, r *types.CancelRetrievePropertiesEx) soap.HasFault {
+	body := &methods.CancelRetrievePropertiesExBody{}
+
+	pc.mu.Lock()
+	_, ok := pc.pages[r.Token]
+	delete(pc.pages, r.Token)
+	pc.mu.Unlock()
+
+	if !ok {
+		body.Fault_ = Fault("", &types.InvalidArgument{InvalidProperty: "token"})
+		return body
+	}
+
+	body.Res = new(types.CancelRetrievePropertiesExResponse)
+
+	return body
+}
+
 // RetrieveProperties is deprecated, but govmomi is still using it at the moment.
 func (pc *PropertyCollector) RetrieveProperties(ctx *Context, r *types.RetrieveProperties) soap.HasFault {
 	body := &methods.RetrievePropertiesBody{}
@@ -603,6 +700,23 @@ func (pc *PropertyCollector) apply(ctx *Context, update *types.UpdateSet) types.
 			Filter: ref,
 		}
 
+		collected := make(map[types.ManagedObjectReference]bool, len(res.Objects))
+		for _, o := range res.Objects {
+			collected[o.Obj] = true
+		}
+
+		// Objects no longer collected by the filter, such as those removed from a ListView
+		for obj := range filter.refs {
+			if collected[obj] {
+				continue
+			}
+			delete(filter.refs, obj)
+			fu.ObjectSet = append(fu.ObjectSet, types.ObjectUpdate{
+				Obj:  obj,
+				Kind: types.ObjectUpdateKindLeave,
+			})
+		}
+
 		for _, o := range res.Objects {
 			if _, ok := filter.refs[o.Obj]; ok {
 				continue
@@ -310,6 +310,13 @@ func (p *ResourcePool) CreateVApp(req *types.CreateVApp) soap.HasFault {
 		child.ParentFolder = &folder
 	}
 
+	if summary, ok := pool.Summary.(*types.ResourcePoolSummary); ok {
+		child.Summary = &types.VirtualAppSummary{
+			ResourcePoolSummary: *summary,
+			VAppState:           types.VirtualAppVAppStateStopped,
+		}
+	}
+
 	child.VAppConfig = &types.VAppConfigInfo{
 		VmConfigInfo: types.VmConfigInfo{},
 		Annotation:   req.ConfigSpec.Annotation,
@@ -323,6 +330,12 @@ func (p *ResourcePool) CreateVApp(req *types.CreateVApp) soap.HasFault {
 
 	p.ResourcePool.ResourcePool = append(p.ResourcePool.ResourcePool, child.Reference())
 
+	if p.Self.Type != "VirtualApp" {
+		// Nested vApps are listed by their parent vApp, others are also children of the vm folder
+		folder := Map.Get(*child.ParentFolder).(*Folder)
+		Map.AddReference(folder, &folder.ChildEntity, child.Self)
+	}
+
 	body.Res = &types.CreateVAppResponse{
 		Returnval: child.Reference(),
 	}
@@ -351,7 +364,16 @@ func (a *VirtualApp) CreateChildVMTask(ctx *Context, req *types.CreateChildVM_Ta
 }
 
 func (a *VirtualApp) DestroyTask(req *types.Destroy_Task) soap.HasFault {
-	return (&ResourcePool{ResourcePool: a.ResourcePool}).DestroyTask(req)
+	body := (&ResourcePool{ResourcePool: a.ResourcePool}).DestroyTask(req).(*methods.Destroy_TaskBody)
+
+	task := Map.Get(body.Res.Returnval).(*Task)
+	if task.Info.Error == nil && a.ParentFolder != nil {
+		if folder, ok := Map.Get(*a.ParentFolder).(*Folder); ok {
+			Map.RemoveReference(folder, &folder.ChildEntity, a.Self)
+		}
+	}
+
+	return body
 }
 
 func (p *ResourcePool) DestroyTask(req *types.Destroy_Task) soap.HasFault {
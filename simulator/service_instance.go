@@ -81,6 +81,10 @@ func NewServiceInstance(content types.ServiceContent, folder mo.Folder) *Service
 		objects = append(objects, NewVcenterVStorageObjectManager(*content.VStorageObjectManager))
 	}
 
+	if s.Content.CryptoManager != nil && s.Content.CryptoManager.Type == "CryptoManagerKmip" {
+		objects = append(objects, NewCryptoManagerKmip(*s.Content.CryptoManager))
+	}
+
 	if s.Content.CustomFieldsManager != nil {
 		objects = append(objects, NewCustomFieldsManager(*s.Content.CustomFieldsManager))
 	}
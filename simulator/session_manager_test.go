@@ -20,6 +20,7 @@ import (
 	"context"
 	"crypto/tls"
 	"log"
+	"net/http"
 	"strings"
 	"testing"
 
@@ -28,6 +29,7 @@ import (
 	"github.com/vmware/govmomi/property"
 	"github.com/vmware/govmomi/session"
 	"github.com/vmware/govmomi/simulator/vpx"
+	"github.com/vmware/govmomi/vim25"
 	"github.com/vmware/govmomi/vim25/methods"
 	"github.com/vmware/govmomi/vim25/mo"
 	"github.com/vmware/govmomi/vim25/soap"
@@ -268,6 +270,54 @@ func TestSessionManagerLoginExtension(t *testing.T) {
 	if err != nil {
 		t.Error(err)
 	}
+
+	// The tunnel verifies vCenter's certificate using the vCenter host name or thumbprint, rather than sdkTunnel
+	caFile, err := ts.CertificateFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	info := ts.CertificateInfo()
+
+	tests := []struct {
+		configure func(*soap.Client) error
+		fail      bool
+	}{
+		{func(sc *soap.Client) error { return sc.SetRootCAs(caFile) }, false},
+		{func(sc *soap.Client) error { sc.SetThumbprint(ts.URL.Host, info.ThumbprintSHA256); return nil }, false},
+		{func(sc *soap.Client) error { sc.SetThumbprint(ts.URL.Host, info.ThumbprintSHA1); return nil }, false},
+		{func(sc *soap.Client) error { return nil }, true},
+	}
+
+	for i, test := range tests {
+		sc := soap.NewClient(ts.URL, false)
+		if err = test.configure(sc); err != nil {
+			t.Fatal(err)
+		}
+		sc.SetCertificate(ts.TLS.Certificates[0])
+
+		vc := &vim25.Client{Client: sc, RoundTripper: sc}
+		vc.ServiceContent = c.ServiceContent
+
+		err = session.NewManager(vc).LoginExtensionByCertificate(ctx, u.Username())
+		if test.fail {
+			if err == nil {
+				t.Errorf("%d: expected error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%d: %s", i, err)
+		}
+
+		// The Client's own TLS config must not be modified by the tunnel
+		if sc.Transport.(*http.Transport).TLSClientConfig.InsecureSkipVerify {
+			t.Errorf("%d: InsecureSkipVerify=true", i)
+		}
+
+		if _, err = methods.GetCurrentTime(ctx, vc); err != nil {
+			t.Errorf("%d: %s", i, err)
+		}
+	}
 }
 
 // Test WaitForUpdates against the PropertyCollector singleton.
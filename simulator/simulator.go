@@ -18,6 +18,7 @@ package simulator
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
 	"crypto/tls"
 	"crypto/x509"
@@ -402,7 +403,18 @@ func (s *Service) ServeSDK(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	body, err := s.readAll(r.Body)
+	var req io.Reader = r.Body
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		gz, err := gzip.NewReader(r.Body)
+		if err != nil {
+			log.Printf("error reading gzip body: %s", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		req = gz
+	}
+
+	body, err := s.readAll(req)
 	_ = r.Body.Close()
 	if err != nil {
 		log.Printf("error reading body: %s", err)
@@ -414,6 +426,11 @@ func (s *Service) ServeSDK(w http.ResponseWriter, r *http.Request) {
 		fmt.Fprintf(os.Stderr, "Request: %s\n", string(body))
 	}
 
+	gzipResponse := strings.Contains(r.Header.Get("Accept-Encoding"), "gzip")
+	if gzipResponse {
+		w.Header().Set("Content-Encoding", "gzip")
+	}
+
 	ctx := &Context{
 		req: r,
 		res: w,
@@ -485,6 +502,13 @@ func (s *Service) ServeSDK(w http.ResponseWriter, r *http.Request) {
 		fmt.Fprintf(os.Stderr, "Response: %s\n", out.String())
 	}
 
+	if gzipResponse {
+		gz := gzip.NewWriter(w)
+		_, _ = gz.Write(out.Bytes())
+		_ = gz.Close()
+		return
+	}
+
 	_, _ = w.Write(out.Bytes())
 }
 
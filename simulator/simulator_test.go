@@ -301,6 +301,89 @@ func TestServeAbout(t *testing.T) {
 	_ = r.Body.Close()
 }
 
+// encodingRecorder records the Content-Encoding of requests and of responses before they are decompressed
+type encodingRecorder struct {
+	http.RoundTripper
+	req, res []string
+}
+
+func (r *encodingRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	r.req = append(r.req, req.Header.Get("Content-Encoding"))
+	res, err := r.RoundTripper.RoundTrip(req)
+	if err == nil {
+		r.res = append(r.res, res.Header.Get("Content-Encoding"))
+	}
+	return res, err
+}
+
+func TestServeGzip(t *testing.T) {
+	Test(func(ctx context.Context, c *vim25.Client) {
+		rec := &encodingRecorder{RoundTripper: c.Client.Transport}
+		c.Client.Transport = rec
+		c.AcceptGzip = true
+		c.GzipRequest = true
+
+		now, err := methods.GetCurrentTime(ctx, c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if now.After(time.Now()) {
+			t.Errorf("now=%s", now)
+		}
+
+		// the fault response body is also compressed
+		_, err = methods.QueryVMotionCompatibility(ctx, c, &types.QueryVMotionCompatibility{})
+		if !soap.IsSoapFault(err) {
+			t.Errorf("unexpected error: %s", err)
+		}
+
+		expect := []string{"gzip", "gzip"}
+		if !reflect.DeepEqual(rec.req, expect) || !reflect.DeepEqual(rec.res, expect) {
+			t.Errorf("req=%v, res=%v", rec.req, rec.res)
+		}
+	})
+}
+
+func TestRoundTripHook(t *testing.T) {
+	Test(func(ctx context.Context, c *vim25.Client) {
+		var infos []soap.RoundTripInfo
+		c.RoundTripHook = func(_ context.Context, info soap.RoundTripInfo) {
+			infos = append(infos, info)
+		}
+
+		_, err := methods.GetCurrentTime(ctx, c)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = methods.QueryVMotionCompatibility(ctx, c, &types.QueryVMotionCompatibility{})
+		if !soap.IsSoapFault(err) {
+			t.Errorf("unexpected error: %s", err)
+		}
+
+		if len(infos) != 2 {
+			t.Fatalf("infos=%d", len(infos))
+		}
+
+		for i, method := range []string{"CurrentTime", "QueryVMotionCompatibility"} {
+			info := infos[i]
+			if info.Method != method {
+				t.Errorf("method=%s", info.Method)
+			}
+			if info.RequestSize == 0 || info.ResponseSize == 0 || info.Duration == 0 {
+				t.Errorf("%s: %#v", method, info)
+			}
+		}
+
+		if infos[0].StatusCode != http.StatusOK || infos[0].Fault != "" || infos[0].Err != nil {
+			t.Errorf("%#v", infos[0])
+		}
+		if infos[1].StatusCode != http.StatusInternalServerError || infos[1].Fault != "ManagedObjectNotFound" || infos[1].Err != err {
+			t.Errorf("%#v", infos[1])
+		}
+	})
+}
+
 func TestServeHTTPS(t *testing.T) {
 	s := New(NewServiceInstance(esx.ServiceContent, esx.RootFolder))
 	s.TLS = new(tls.Config)
@@ -328,8 +411,8 @@ func TestServeHTTPS(t *testing.T) {
 		t.Fatalf("err type=%T", err)
 	}
 
-	_, ok = uerr.Err.(x509.UnknownAuthorityError)
-	if !ok {
+	var authErr x509.UnknownAuthorityError
+	if !errors.As(uerr.Err, &authErr) {
 		t.Fatalf("err type=%T", uerr.Err)
 	}
 
@@ -344,10 +427,13 @@ func TestServeHTTPS(t *testing.T) {
 		t.Error("expected error")
 	}
 	// Add host with thumbprint match should pass
-	sc.SetThumbprint(ts.URL.Host, sinfo.ThumbprintSHA1)
-	_, err = vim25.NewClient(ctx, sc)
-	if err != nil {
-		t.Fatal(err)
+	for _, thumbprint := range []string{sinfo.ThumbprintSHA1, sinfo.ThumbprintSHA256} {
+		sc = soap.NewClient(ts.URL, false)
+		sc.SetThumbprint(ts.URL.Host, thumbprint)
+		_, err = vim25.NewClient(ctx, sc)
+		if err != nil {
+			t.Fatal(err)
+		}
 	}
 
 	var pinfo object.HostCertificateInfo
@@ -114,8 +114,13 @@ func (v *VirtualMachineSnapshot) RemoveSnapshotTask(ctx *Context, req *types.Rem
 		var changes []types.PropertyChange
 
 		vm := Map.Get(v.Vm).(*VirtualMachine)
+		refs := []types.ManagedObjectReference{req.This}
+		if req.RemoveChildren {
+			refs = append(refs, allSnapshotsInTree(findSnapshotInTree(vm.Snapshot.RootSnapshotList, req.This).ChildSnapshotList)...)
+		}
+
 		Map.WithLock(vm, func() {
-			if vm.Snapshot.CurrentSnapshot != nil && *vm.Snapshot.CurrentSnapshot == req.This {
+			if vm.Snapshot.CurrentSnapshot != nil && FindReference(refs, *vm.Snapshot.CurrentSnapshot) != nil {
 				parent := findParentSnapshotInTree(vm.Snapshot.RootSnapshotList, req.This)
 				changes = append(changes, types.PropertyChange{Name: "snapshot.currentSnapshot", Val: parent})
 			}
@@ -129,12 +134,16 @@ func (v *VirtualMachineSnapshot) RemoveSnapshotTask(ctx *Context, req *types.Rem
 				}
 			}
 
-			Map.Get(req.This).(*VirtualMachineSnapshot).removeSnapshotFiles(ctx)
+			for _, ref := range refs {
+				Map.Get(ref).(*VirtualMachineSnapshot).removeSnapshotFiles(ctx)
+			}
 
 			Map.Update(vm, changes)
 		})
 
-		Map.Remove(req.This)
+		for _, ref := range refs {
+			Map.Remove(ref)
+		}
 
 		return nil, nil
 	})
@@ -17,6 +17,7 @@ limitations under the License.
 package simulator
 
 import (
+	"fmt"
 	"strconv"
 	"time"
 
@@ -29,10 +30,19 @@ import (
 
 type StorageResourceManager struct {
 	mo.StorageResourceManager
+
+	nrec int
+	recs map[string]storagePlacement
+}
+
+// storagePlacement is a recommendation made by RecommendDatastores, pending apply or cancel
+type storagePlacement struct {
+	spec types.StoragePlacementSpec
+	ds   types.ManagedObjectReference
 }
 
 func NewStorageResourceManager(ref types.ManagedObjectReference) object.Reference {
-	m := &StorageResourceManager{}
+	m := &StorageResourceManager{recs: make(map[string]storagePlacement)}
 	m.Self = ref
 
 	return m
@@ -85,7 +95,6 @@ func (m *StorageResourceManager) RecommendDatastores(req *types.RecommendDatasto
 	spec := req.StorageSpec.PodSelectionSpec
 	body := new(methods.RecommendDatastoresBody)
 	res := new(types.RecommendDatastoresResponse)
-	key := 0
 	invalid := func(prop string) soap.HasFault {
 		body.Fault_ = Fault("", &types.InvalidArgument{
 			InvalidProperty: prop,
@@ -93,9 +102,11 @@ func (m *StorageResourceManager) RecommendDatastores(req *types.RecommendDatasto
 		return body
 	}
 	add := func(cluster *StoragePod, ds types.ManagedObjectReference) {
-		key++
+		m.nrec++
+		key := strconv.Itoa(m.nrec)
+		m.recs[key] = storagePlacement{req.StorageSpec, ds}
 		res.Returnval.Recommendations = append(res.Returnval.Recommendations, types.ClusterRecommendation{
-			Key:            strconv.Itoa(key),
+			Key:            key,
 			Type:           "V1",
 			Time:           time.Now(),
 			Rating:         1,
@@ -167,6 +178,21 @@ func (m *StorageResourceManager) RecommendDatastores(req *types.RecommendDatasto
 		if req.StorageSpec.CloneSpec == nil {
 			return invalid("cloneSpec")
 		}
+	case types.StoragePlacementSpecPlacementTypeRelocate:
+		if req.StorageSpec.Vm == nil {
+			return invalid("vm")
+		}
+	}
+
+	if len(spec.InitialVmConfig) == 0 && spec.StoragePod != nil {
+		cluster := m.pod(spec.StoragePod)
+		if cluster == nil {
+			return invalid("podSelectionSpec.storagePod")
+		}
+
+		for _, ds := range cluster.ChildEntity {
+			add(cluster, ds)
+		}
 	}
 
 	for _, placement := range spec.InitialVmConfig {
@@ -189,3 +215,143 @@ func (m *StorageResourceManager) RecommendDatastores(req *types.RecommendDatasto
 	body.Res = res
 	return body
 }
+
+// apply performs the operation of the given placement, returning the VM created, cloned or relocated
+func (m *StorageResourceManager) apply(ctx *Context, p storagePlacement) (*types.ManagedObjectReference, types.BaseMethodFault) {
+	ds := Map.Get(p.ds).(*Datastore)
+	spec := p.spec
+
+	var ref types.ManagedObjectReference
+
+	switch types.StoragePlacementSpecPlacementType(spec.Type) {
+	case types.StoragePlacementSpecPlacementTypeCreate:
+		config := *spec.ConfigSpec
+		config.Files = &types.VirtualMachineFileInfo{VmPathName: fmt.Sprintf("[%s]", ds.Name)}
+
+		for _, placement := range spec.PodSelectionSpec.InitialVmConfig {
+			for _, disk := range placement.Disk {
+				if b, ok := disk.DiskBackingInfo.(*types.VirtualDiskFlatVer2BackingInfo); ok {
+					b.Datastore = &p.ds
+				}
+			}
+		}
+
+		var folder *Folder
+		if spec.Folder != nil {
+			folder = Map.Get(*spec.Folder).(*Folder)
+		} else {
+			pool := Map.Get(*spec.ResourcePool).(mo.Entity)
+			folder = Map.Get(Map.getEntityDatacenter(pool).VmFolder).(*Folder)
+		}
+
+		res := folder.CreateVMTask(ctx, &types.CreateVM_Task{
+			This:   folder.Self,
+			Config: config,
+			Pool:   *spec.ResourcePool,
+			Host:   spec.Host,
+		})
+		ref = res.(*methods.CreateVM_TaskBody).Res.Returnval
+	case types.StoragePlacementSpecPlacementTypeClone:
+		vm := Map.Get(*spec.Vm).(*VirtualMachine)
+		clone := *spec.CloneSpec
+		clone.Location.Datastore = &p.ds
+
+		res := vm.CloneVMTask(ctx, &types.CloneVM_Task{
+			This:   vm.Self,
+			Folder: *spec.Folder,
+			Name:   spec.CloneName,
+			Spec:   clone,
+		})
+		ref = res.(*methods.CloneVM_TaskBody).Res.Returnval
+	case types.StoragePlacementSpecPlacementTypeRelocate:
+		vm := Map.Get(*spec.Vm).(*VirtualMachine)
+		var relocate types.VirtualMachineRelocateSpec
+		if spec.RelocateSpec != nil {
+			relocate = *spec.RelocateSpec
+		}
+		relocate.Datastore = &p.ds
+
+		res := vm.RelocateVMTask(&types.RelocateVM_Task{
+			This: vm.Self,
+			Spec: relocate,
+		})
+		ref = res.(*methods.RelocateVM_TaskBody).Res.Returnval
+	default:
+		return nil, &types.NotSupported{}
+	}
+
+	task := Map.Get(ref).(*Task)
+	if task.Info.Error != nil {
+		return nil, task.Info.Error.Fault
+	}
+
+	if vm, ok := task.Info.Result.(types.ManagedObjectReference); ok {
+		return &vm, nil
+	}
+
+	return spec.Vm, nil
+}
+
+func (m *StorageResourceManager) applyRecommendations(ctx *Context, keys []string) (types.AnyType, types.BaseMethodFault) {
+	for _, key := range keys {
+		if _, ok := m.recs[key]; !ok {
+			return nil, &types.InvalidArgument{InvalidProperty: "key"}
+		}
+	}
+
+	var result types.ApplyStorageRecommendationResult
+
+	for _, key := range keys {
+		p := m.recs[key]
+		delete(m.recs, key)
+
+		vm, err := m.apply(ctx, p)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Vm = vm
+	}
+
+	return result, nil
+}
+
+func (m *StorageResourceManager) ApplyStorageDrsRecommendationTask(ctx *Context, req *types.ApplyStorageDrsRecommendation_Task) soap.HasFault {
+	task := CreateTask(m, "applyStorageDrsRecommendation", func(*Task) (types.AnyType, types.BaseMethodFault) {
+		return m.applyRecommendations(ctx, req.Key)
+	})
+
+	return &methods.ApplyStorageDrsRecommendation_TaskBody{
+		Res: &types.ApplyStorageDrsRecommendation_TaskResponse{
+			Returnval: task.Run(),
+		},
+	}
+}
+
+func (m *StorageResourceManager) ApplyStorageDrsRecommendationToPodTask(ctx *Context, req *types.ApplyStorageDrsRecommendationToPod_Task) soap.HasFault {
+	task := CreateTask(m, "applyStorageDrsRecommendationToPod", func(*Task) (types.AnyType, types.BaseMethodFault) {
+		if p, ok := m.recs[req.Key]; ok {
+			if ds := Map.Get(p.ds).(*Datastore); *ds.Parent != req.Pod {
+				return nil, &types.InvalidArgument{InvalidProperty: "pod"}
+			}
+		}
+
+		return m.applyRecommendations(ctx, []string{req.Key})
+	})
+
+	return &methods.ApplyStorageDrsRecommendationToPod_TaskBody{
+		Res: &types.ApplyStorageDrsRecommendationToPod_TaskResponse{
+			Returnval: task.Run(),
+		},
+	}
+}
+
+func (m *StorageResourceManager) CancelStorageDrsRecommendation(req *types.CancelStorageDrsRecommendation) soap.HasFault {
+	for _, key := range req.Key {
+		delete(m.recs, key)
+	}
+
+	return &methods.CancelStorageDrsRecommendationBody{
+		Res: new(types.CancelStorageDrsRecommendationResponse),
+	}
+}
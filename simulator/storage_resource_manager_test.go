@@ -0,0 +1,168 @@
+/*
+Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+func TestStorageResourceManager(t *testing.T) {
+	m := VPX()
+	m.Datastore = 2
+
+	Test(func(ctx context.Context, c *vim25.Client) {
+		finder := find.NewFinder(c)
+
+		dc, err := finder.DefaultDatacenter(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		finder.SetDatacenter(dc)
+
+		folders, err := dc.Folders(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		pod, err := folders.DatastoreFolder.CreateStoragePod(ctx, "pod")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		datastores, err := finder.DatastoreList(ctx, "*")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		task, err := pod.AddDatastores(ctx, datastores...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = task.Wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		srm := object.NewStorageResourceManager(c)
+
+		task, err = srm.ConfigureStorageDrsForPod(ctx, pod, object.NewStorageDrsConfigSpec(true, types.StorageDrsPodConfigInfoBehaviorAutomated), true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = task.Wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		config, err := pod.StorageDrsConfig(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !config.PodConfig.Enabled || config.PodConfig.DefaultVmBehavior != string(types.StorageDrsPodConfigInfoBehaviorAutomated) {
+			t.Errorf("config=%#v", config.PodConfig)
+		}
+
+		pool, err := finder.ResourcePool(ctx, "DC0_H0/Resources")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		vm, err := finder.VirtualMachine(ctx, "DC0_H0_VM0")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// create
+		var devices object.VirtualDeviceList
+		scsi, _ := devices.CreateSCSIController("pvscsi")
+		devices = append(devices, scsi)
+		disk := devices.CreateDisk(scsi.(types.BaseVirtualController), types.ManagedObjectReference{}, "")
+		disk.CapacityInKB = 1024
+		devices = append(devices, disk)
+
+		spec := types.VirtualMachineConfigSpec{
+			Name:    "sdrs-create",
+			GuestId: string(types.VirtualMachineGuestOsIdentifierOtherGuest),
+		}
+		spec.DeviceChange, _ = devices.ConfigSpec(types.VirtualDeviceConfigSpecOperationAdd)
+		spec.DeviceChange[1].GetVirtualDeviceConfigSpec().FileOperation = types.VirtualDeviceConfigSpecFileOperationCreate
+
+		placements := []types.StoragePlacementSpec{
+			object.NewStoragePlacementSpecCreate(pod, pool, folders.VmFolder, nil, spec),
+			object.NewStoragePlacementSpecClone(pod, vm, folders.VmFolder, "sdrs-clone", types.VirtualMachineCloneSpec{}),
+			object.NewStoragePlacementSpecRelocate(pod, vm, types.VirtualMachineRelocateSpec{}),
+		}
+
+		if len(placements[0].PodSelectionSpec.InitialVmConfig) != 1 {
+			t.Fatalf("InitialVmConfig=%d", len(placements[0].PodSelectionSpec.InitialVmConfig))
+		}
+
+		for i, placement := range placements {
+			rec, err := srm.RecommendDatastore(ctx, placement)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ds := object.StoragePlacementDestination(*rec)
+			if ds == nil {
+				t.Fatalf("%d: no destination", i)
+			}
+
+			task, err = srm.ApplyStorageDrsRecommendation(ctx, []string{rec.Key})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			info, err := task.WaitForResult(ctx, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ref := info.Result.(types.ApplyStorageRecommendationResult).Vm
+			if ref == nil {
+				t.Fatalf("%d: no vm", i)
+			}
+
+			obj := Map.Get(*ref).(*VirtualMachine)
+			if FindReference(obj.Datastore, *ds) == nil {
+				t.Errorf("%d: %s datastore=%s, expected %s", i, obj.Name, obj.Datastore, ds)
+			}
+		}
+
+		// canceled recommendations can't be applied
+		rec, err := srm.RecommendDatastore(ctx, placements[2])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err = srm.CancelStorageDrsRecommendation(ctx, []string{rec.Key}); err != nil {
+			t.Fatal(err)
+		}
+
+		task, err = srm.ApplyStorageDrsRecommendation(ctx, []string{rec.Key})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = task.Wait(ctx); err == nil {
+			t.Error("expected error")
+		}
+	}, m)
+}
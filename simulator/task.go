@@ -78,14 +78,22 @@ type TaskRunner interface {
 }
 
 func (t *Task) Run() types.ManagedObjectReference {
-	now := time.Now()
+	t.start()
+	t.finish(t.Execute(t))
 
+	return t.Self
+}
+
+// start transitions the task to the running state.
+func (t *Task) start() {
 	Map.Update(t, []types.PropertyChange{
-		{Name: "info.startTime", Val: now},
+		{Name: "info.startTime", Val: time.Now()},
 		{Name: "info.state", Val: types.TaskInfoStateRunning},
 	})
+}
 
-	res, err := t.Execute(t)
+// finish transitions the task to the success or error state, depending on err.
+func (t *Task) finish(res types.AnyType, err types.BaseMethodFault) {
 	state := types.TaskInfoStateSuccess
 	var fault interface{}
 	if err != nil {
@@ -96,14 +104,10 @@ func (t *Task) Run() types.ManagedObjectReference {
 		}
 	}
 
-	now = time.Now()
-
 	Map.Update(t, []types.PropertyChange{
-		{Name: "info.completeTime", Val: now},
+		{Name: "info.completeTime", Val: time.Now()},
 		{Name: "info.state", Val: state},
 		{Name: "info.result", Val: res},
 		{Name: "info.error", Val: fault},
 	})
-
-	return t.Self
 }
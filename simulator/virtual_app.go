@@ -0,0 +1,446 @@
+/*
+Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"path"
+	"sort"
+
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/methods"
+	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// newVAppEntityConfig returns the default config for an entity added to a vApp.
+func newVAppEntityConfig(ref types.ManagedObjectReference, name string) types.VAppEntityConfigInfo {
+	return types.VAppEntityConfigInfo{
+		Key:               &ref,
+		Tag:               name,
+		StartOrder:        1,
+		WaitingForGuest:   types.NewBool(false),
+		StartAction:       string(types.VAppAutoStartActionPowerOn),
+		StopAction:        string(types.VAppAutoStartActionPowerOff),
+		DestroyWithParent: types.NewBool(true),
+	}
+}
+
+func (a *VirtualApp) addEntity(ref types.ManagedObjectReference, name string) {
+	if a.VAppConfig == nil {
+		a.VAppConfig = new(types.VAppConfigInfo)
+	}
+
+	a.VAppConfig.EntityConfig = append(a.VAppConfig.EntityConfig, newVAppEntityConfig(ref, name))
+}
+
+// entities returns the config of each child VM and vApp, sorted by start order.
+func (a *VirtualApp) entities() []types.VAppEntityConfigInfo {
+	var entities []types.VAppEntityConfigInfo
+
+	children := append([]types.ManagedObjectReference{}, a.Vm...)
+	for _, ref := range a.ResourcePool.ResourcePool {
+		if ref.Type == "VirtualApp" {
+			children = append(children, ref)
+		}
+	}
+
+	for i := range children {
+		config := newVAppEntityConfig(children[i], "")
+
+		if a.VAppConfig != nil {
+			for _, e := range a.VAppConfig.EntityConfig {
+				if e.Key != nil && *e.Key == children[i] {
+					config = e
+					break
+				}
+			}
+		}
+
+		entities = append(entities, config)
+	}
+
+	sort.SliceStable(entities, func(i, j int) bool {
+		return entities[i].StartOrder < entities[j].StartOrder
+	})
+
+	return entities
+}
+
+func (a *VirtualApp) setState(state types.VirtualAppVAppState) {
+	if s, ok := a.Summary.(*types.VirtualAppSummary); ok {
+		s.VAppState = state
+	}
+}
+
+// power changes the power state of the vApp's children, following the entity start order.
+// Children are powered off and suspended in reverse order.
+func (a *VirtualApp) power(ctx *Context, state types.VirtualMachinePowerState) types.BaseMethodFault {
+	entities := a.entities()
+
+	if state != types.VirtualMachinePowerStatePoweredOn {
+		for i, j := 0, len(entities)-1; i < j; i, j = i+1, j-1 {
+			entities[i], entities[j] = entities[j], entities[i]
+		}
+	}
+
+	for _, e := range entities {
+		action := types.VAppAutoStartAction(e.StartAction)
+		if state != types.VirtualMachinePowerStatePoweredOn {
+			action = types.VAppAutoStartAction(e.StopAction)
+		}
+
+		if action == types.VAppAutoStartActionNone {
+			continue
+		}
+
+		target := state
+		if state == types.VirtualMachinePowerStatePoweredOff && action == types.VAppAutoStartActionSuspend {
+			target = types.VirtualMachinePowerStateSuspended
+		}
+
+		var fault types.BaseMethodFault
+
+		switch child := Map.Get(*e.Key).(type) {
+		case *VirtualMachine:
+			current := child.Runtime.PowerState
+			if current == target || (target == types.VirtualMachinePowerStateSuspended && current != types.VirtualMachinePowerStatePoweredOn) {
+				continue
+			}
+
+			runner := &powerVMTask{child, target, ctx}
+			ctx.WithLock(child, func() { _, fault = runner.Run(nil) })
+		case *VirtualApp:
+			ctx.WithLock(child, func() { fault = child.power(ctx, state) })
+		}
+
+		if fault != nil {
+			return fault
+		}
+	}
+
+	switch state {
+	case types.VirtualMachinePowerStatePoweredOn:
+		a.setState(types.VirtualAppVAppStateStarted)
+	default:
+		a.setState(types.VirtualAppVAppStateStopped)
+	}
+
+	return nil
+}
+
+func (a *VirtualApp) PowerOnVAppTask(ctx *Context, req *types.PowerOnVApp_Task) soap.HasFault {
+	ctx.Caller = &a.Self
+
+	task := CreateTask(a, "powerOn", func(*Task) (types.AnyType, types.BaseMethodFault) {
+		return nil, a.power(ctx, types.VirtualMachinePowerStatePoweredOn)
+	})
+
+	return &methods.PowerOnVApp_TaskBody{
+		Res: &types.PowerOnVApp_TaskResponse{
+			Returnval: task.Run(),
+		},
+	}
+}
+
+func (a *VirtualApp) PowerOffVAppTask(ctx *Context, req *types.PowerOffVApp_Task) soap.HasFault {
+	ctx.Caller = &a.Self
+
+	task := CreateTask(a, "powerOff", func(*Task) (types.AnyType, types.BaseMethodFault) {
+		return nil, a.power(ctx, types.VirtualMachinePowerStatePoweredOff)
+	})
+
+	return &methods.PowerOffVApp_TaskBody{
+		Res: &types.PowerOffVApp_TaskResponse{
+			Returnval: task.Run(),
+		},
+	}
+}
+
+func (a *VirtualApp) SuspendVAppTask(ctx *Context, req *types.SuspendVApp_Task) soap.HasFault {
+	ctx.Caller = &a.Self
+
+	task := CreateTask(a, "suspend", func(*Task) (types.AnyType, types.BaseMethodFault) {
+		return nil, a.power(ctx, types.VirtualMachinePowerStateSuspended)
+	})
+
+	return &methods.SuspendVApp_TaskBody{
+		Res: &types.SuspendVApp_TaskResponse{
+			Returnval: task.Run(),
+		},
+	}
+}
+
+func (a *VirtualApp) CreateVApp(req *types.CreateVApp) soap.HasFault {
+	pool := &ResourcePool{ResourcePool: a.ResourcePool}
+
+	body := pool.CreateVApp(req).(*methods.CreateVAppBody)
+	a.ResourcePool.ResourcePool = pool.ResourcePool.ResourcePool
+
+	if body.Res != nil {
+		a.addEntity(body.Res.Returnval, req.Name)
+	}
+
+	return body
+}
+
+func (a *VirtualApp) UpdateVAppConfig(req *types.UpdateVAppConfig) soap.HasFault {
+	body := new(methods.UpdateVAppConfigBody)
+
+	if a.VAppConfig == nil {
+		a.VAppConfig = new(types.VAppConfigInfo)
+	}
+	config := a.VAppConfig
+	spec := req.Spec
+
+	if spec.Annotation != "" {
+		config.Annotation = spec.Annotation
+	}
+
+	entities := a.entities()
+
+	for _, e := range spec.EntityConfig {
+		var entity *types.VAppEntityConfigInfo
+		if e.Key != nil {
+			for i := range entities {
+				if *entities[i].Key == *e.Key {
+					entity = &entities[i]
+					break
+				}
+			}
+		}
+
+		if entity == nil {
+			body.Fault_ = Fault("", &types.InvalidArgument{InvalidProperty: "spec.entityConfig.key"})
+			return body
+		}
+
+		if e.Tag != "" {
+			entity.Tag = e.Tag
+		}
+		if e.StartOrder != 0 {
+			entity.StartOrder = e.StartOrder
+		}
+		if e.StartDelay != 0 {
+			entity.StartDelay = e.StartDelay
+		}
+		if e.WaitingForGuest != nil {
+			entity.WaitingForGuest = e.WaitingForGuest
+		}
+		if e.StartAction != "" {
+			entity.StartAction = e.StartAction
+		}
+		if e.StopDelay != 0 {
+			entity.StopDelay = e.StopDelay
+		}
+		if e.StopAction != "" {
+			entity.StopAction = e.StopAction
+		}
+		if e.DestroyWithParent != nil {
+			entity.DestroyWithParent = e.DestroyWithParent
+		}
+	}
+
+	for _, p := range spec.Property {
+		index := -1
+		if p.Info != nil {
+			for i := range config.Property {
+				if config.Property[i].Key == p.Info.Key {
+					index = i
+					break
+				}
+			}
+		}
+
+		switch p.Operation {
+		case types.ArrayUpdateOperationAdd:
+			if p.Info == nil || index != -1 {
+				body.Fault_ = Fault("", &types.InvalidArgument{InvalidProperty: "spec.property.info.key"})
+				return body
+			}
+			config.Property = append(config.Property, *p.Info)
+		case types.ArrayUpdateOperationEdit:
+			if index == -1 {
+				body.Fault_ = Fault("", &types.InvalidArgument{InvalidProperty: "spec.property.info.key"})
+				return body
+			}
+			config.Property[index] = *p.Info
+		case types.ArrayUpdateOperationRemove:
+			key, _ := p.RemoveKey.(int32)
+			for i := range config.Property {
+				if config.Property[i].Key == key {
+					config.Property = append(config.Property[:i], config.Property[i+1:]...)
+					break
+				}
+			}
+		}
+	}
+
+	config.EntityConfig = entities
+
+	body.Res = new(types.UpdateVAppConfigResponse)
+
+	return body
+}
+
+// clone copies the vApp and its children into target, with VM files placed in dir on the spec.Location datastore.
+func (a *VirtualApp) clone(ctx *Context, name string, dir string, target types.ManagedObjectReference, spec *types.VAppCloneSpec) (*VirtualApp, types.BaseMethodFault) {
+	ds, ok := Map.Get(spec.Location).(*Datastore)
+	if !ok {
+		return nil, &types.ManagedObjectNotFound{Obj: spec.Location}
+	}
+
+	rspec := types.DefaultResourceConfigSpec()
+	if spec.ResourceSpec != nil {
+		rspec = *spec.ResourceSpec
+	}
+
+	req := &types.CreateVApp{
+		Name:     name,
+		ResSpec:  rspec,
+		VmFolder: spec.VmFolder,
+	}
+
+	var res soap.HasFault
+
+	switch pool := Map.Get(target).(type) {
+	case *ResourcePool:
+		ctx.WithLock(pool, func() { res = pool.CreateVApp(req) })
+	case *VirtualApp:
+		ctx.WithLock(pool, func() { res = pool.CreateVApp(req) })
+	default:
+		return nil, &types.ManagedObjectNotFound{Obj: target}
+	}
+
+	body := res.(*methods.CreateVAppBody)
+	if body.Fault_ != nil {
+		return nil, body.Fault_.VimFault().(types.BaseMethodFault)
+	}
+
+	clone := Map.Get(body.Res.Returnval).(*VirtualApp)
+
+	if a.VAppConfig != nil {
+		clone.VAppConfig.Annotation = a.VAppConfig.Annotation
+		clone.VAppConfig.Product = append([]types.VAppProductInfo(nil), a.VAppConfig.Product...)
+		clone.VAppConfig.Property = append([]types.VAppPropertyInfo(nil), a.VAppConfig.Property...)
+	}
+
+	for _, kv := range spec.Property {
+		for i := range clone.VAppConfig.Property {
+			if clone.VAppConfig.Property[i].Id == kv.Key {
+				clone.VAppConfig.Property[i].Value = kv.Value
+			}
+		}
+	}
+
+	keys := make(map[types.ManagedObjectReference]types.ManagedObjectReference)
+
+	for _, ref := range a.Vm {
+		vm := Map.Get(ref).(*VirtualMachine)
+		vmx := object.DatastorePath{
+			Datastore: ds.Name,
+			Path:      path.Join(dir, vm.Name, vm.Name+".vmx"),
+		}
+
+		res := vm.CloneVMTask(ctx, &types.CloneVM_Task{
+			This:   ref,
+			Folder: *clone.ParentFolder,
+			Name:   vm.Name,
+			Spec: types.VirtualMachineCloneSpec{
+				Location: types.VirtualMachineRelocateSpec{
+					Pool:      &clone.Self,
+					Host:      spec.Host,
+					Datastore: &spec.Location,
+				},
+				Config: &types.VirtualMachineConfigSpec{
+					Files: &types.VirtualMachineFileInfo{VmPathName: vmx.String()},
+				},
+			},
+		})
+		ctx.Caller = &a.Self
+
+		ctask := Map.Get(res.(*methods.CloneVM_TaskBody).Res.Returnval).(*Task)
+		if ctask.Info.Error != nil {
+			return nil, ctask.Info.Error.Fault
+		}
+
+		keys[ref] = ctask.Info.Result.(types.ManagedObjectReference)
+	}
+
+	for _, ref := range a.ResourcePool.ResourcePool {
+		child, ok := Map.Get(ref).(*VirtualApp)
+		if !ok {
+			continue
+		}
+
+		cspec := types.VAppCloneSpec{
+			Location: spec.Location,
+			Host:     spec.Host,
+			VmFolder: clone.ParentFolder,
+		}
+
+		var c *VirtualApp
+		var fault types.BaseMethodFault
+
+		ctx.WithLock(child, func() {
+			c, fault = child.clone(ctx, child.Name, path.Join(dir, child.Name), clone.Self, &cspec)
+		})
+
+		if fault != nil {
+			return nil, fault
+		}
+
+		keys[ref] = c.Self
+	}
+
+	// Preserve the start order and actions of the source entities
+	for _, e := range a.entities() {
+		ref, ok := keys[*e.Key]
+		if !ok {
+			continue
+		}
+
+		for i := range clone.VAppConfig.EntityConfig {
+			if *clone.VAppConfig.EntityConfig[i].Key == ref {
+				tag := clone.VAppConfig.EntityConfig[i].Tag
+				clone.VAppConfig.EntityConfig[i] = e
+				clone.VAppConfig.EntityConfig[i].Key = &ref
+				clone.VAppConfig.EntityConfig[i].Tag = tag
+			}
+		}
+	}
+
+	return clone, nil
+}
+
+func (a *VirtualApp) CloneVAppTask(ctx *Context, req *types.CloneVApp_Task) soap.HasFault {
+	ctx.Caller = &a.Self
+
+	task := CreateTask(a, "cloneVApp", func(*Task) (types.AnyType, types.BaseMethodFault) {
+		clone, err := a.clone(ctx, req.Name, req.Name, req.Target, &req.Spec)
+		if err != nil {
+			return nil, err
+		}
+
+		return clone.Reference(), nil
+	})
+
+	return &methods.CloneVApp_TaskBody{
+		Res: &types.CloneVApp_TaskResponse{
+			Returnval: task.Run(),
+		},
+	}
+}
@@ -0,0 +1,223 @@
+/*
+Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+func TestVirtualApp(t *testing.T) {
+	m := VPX()
+	m.App = 1
+
+	Test(func(ctx context.Context, c *vim25.Client) {
+		finder := find.NewFinder(c)
+
+		dc, err := finder.DefaultDatacenter(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		finder.SetDatacenter(dc)
+
+		vapp, err := finder.VirtualApp(ctx, "DC0_C0_APP0")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		child, err := vapp.CreateVApp(ctx, "child", types.DefaultResourceConfigSpec(), NewVAppConfigSpec(), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		spec := types.VirtualMachineConfigSpec{
+			Name:    "child_VM0",
+			GuestId: string(types.VirtualMachineGuestOsIdentifierOtherGuest),
+			Files: &types.VirtualMachineFileInfo{
+				VmPathName: "[LocalDS_0]",
+			},
+		}
+
+		task, err := child.CreateChildVM(ctx, spec, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = task.Wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		nested, err := finder.VirtualApp(ctx, "/DC0/vm/DC0_C0_APP0/child")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if nested.Reference() != child.Reference() {
+			t.Errorf("nested=%s", nested.Reference())
+		}
+
+		vms, err := finder.VirtualMachineList(ctx, "/DC0/vm/DC0_C0_APP0/*")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		powerState := func(ref types.ManagedObjectReference) types.VirtualMachinePowerState {
+			return Map.Get(ref).(*VirtualMachine).Runtime.PowerState
+		}
+
+		// start the child vApp first, then the VMs
+		order := [][]types.ManagedObjectReference{{child.Reference()}, {vms[0].Reference(), vms[1].Reference()}}
+		if err = vapp.SetStartOrder(ctx, order...); err != nil {
+			t.Fatal(err)
+		}
+
+		groups, err := vapp.StartOrder(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(groups, order) {
+			t.Errorf("groups=%v", groups)
+		}
+
+		err = vapp.SetStartOrder(ctx, []types.ManagedObjectReference{vms[0].Reference()}, []types.ManagedObjectReference{{Type: "VirtualMachine", Value: "enoent"}})
+		if err == nil {
+			t.Error("expected error")
+		}
+
+		for _, op := range []struct {
+			fn    func(context.Context) (*object.Task, error)
+			state types.VirtualMachinePowerState
+			vapp  types.VirtualAppVAppState
+		}{
+			{func(ctx context.Context) (*object.Task, error) { return vapp.PowerOff(ctx, false) }, types.VirtualMachinePowerStatePoweredOff, types.VirtualAppVAppStateStopped},
+			{vapp.PowerOn, types.VirtualMachinePowerStatePoweredOn, types.VirtualAppVAppStateStarted},
+			{vapp.Suspend, types.VirtualMachinePowerStateSuspended, types.VirtualAppVAppStateStopped},
+		} {
+			task, err := op.fn(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err = task.Wait(ctx); err != nil {
+				t.Fatal(err)
+			}
+
+			for _, vm := range append(vms, object.NewVirtualMachine(c, Map.Get(child.Reference()).(*VirtualApp).Vm[0])) {
+				if state := powerState(vm.Reference()); state != op.state {
+					t.Errorf("%s state=%s", vm.Reference(), state)
+				}
+			}
+
+			var o mo.VirtualApp
+			if err = vapp.Properties(ctx, vapp.Reference(), []string{"summary"}, &o); err != nil {
+				t.Fatal(err)
+			}
+			if state := o.Summary.(*types.VirtualAppSummary).VAppState; state != op.vapp {
+				t.Errorf("vApp state=%s", state)
+			}
+		}
+
+		err = vapp.UpdateConfig(ctx, types.VAppConfigSpec{
+			VmConfigSpec: types.VmConfigSpec{
+				Property: []types.VAppPropertySpec{
+					{
+						ArrayUpdateSpec: types.ArrayUpdateSpec{Operation: types.ArrayUpdateOperationAdd},
+						Info:            &types.VAppPropertyInfo{Key: 1, Id: "ip", DefaultValue: "0.0.0.0", Type: "string"},
+					},
+				},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		props, err := vapp.VAppProperties(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if props["ip"] != "0.0.0.0" {
+			t.Errorf("props=%v", props)
+		}
+
+		if err = vapp.SetVAppProperties(ctx, map[string]string{"enoent": "x"}); err == nil {
+			t.Error("expected error")
+		}
+
+		if err = vapp.SetVAppProperties(ctx, map[string]string{"ip": "10.0.0.1"}); err != nil {
+			t.Fatal(err)
+		}
+
+		props, _ = vapp.VAppProperties(ctx)
+		if props["ip"] != "10.0.0.1" {
+			t.Errorf("props=%v", props)
+		}
+
+		pool, err := finder.ResourcePool(ctx, "DC0_C0/Resources")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ds, err := finder.Datastore(ctx, "LocalDS_0")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		task, err = vapp.Clone(ctx, "clone", pool, types.VAppCloneSpec{
+			Location: ds.Reference(),
+			Property: []types.KeyValue{{Key: "ip", Value: "10.0.0.2"}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		info, err := task.WaitForResult(ctx, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		clone := object.NewVirtualApp(c, info.Result.(types.ManagedObjectReference))
+
+		props, _ = clone.VAppProperties(ctx)
+		if props["ip"] != "10.0.0.2" {
+			t.Errorf("props=%v", props)
+		}
+
+		groups, err = clone.StartOrder(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(groups) != 2 || len(groups[0]) != 1 || len(groups[1]) != 2 || groups[0][0].Type != "VirtualApp" {
+			t.Errorf("groups=%v", groups)
+		}
+
+		vms, err = finder.VirtualMachineList(ctx, "/DC0/vm/clone/*")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(vms) != 2 {
+			t.Errorf("clone vms=%d", len(vms))
+		}
+
+		if _, err = finder.VirtualMachine(ctx, "/DC0/vm/clone/child/child_VM0"); err != nil {
+			t.Error(err)
+		}
+	}, m)
+}
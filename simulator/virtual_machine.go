@@ -937,6 +937,16 @@ func (vm *VirtualMachine) configureDevice(devices object.VirtualDeviceList, spec
 			summary = fmt.Sprintf("DVSwitch: %s", b.Port.SwitchUuid)
 			net.Type = "DistributedVirtualPortgroup"
 			net.Value = b.Port.PortgroupKey
+			if pg, ok := Map.Get(net).(*DistributedVirtualPortgroup); ok {
+				dvs := Map.Get(*pg.Config.DistributedVirtualSwitch).(*DistributedVirtualSwitch)
+				d.Backing = dvs.connectPort(vm, pg, b)
+			}
+		case *types.VirtualEthernetCardOpaqueNetworkBackingInfo:
+			summary = fmt.Sprintf("%s: %s", b.OpaqueNetworkType, b.OpaqueNetworkId)
+			if on := findOpaqueNetwork(dc.Network, b.OpaqueNetworkId); on != nil {
+				name = on.Name
+				net = on.Self
+			}
 		}
 
 		Map.Update(vm, []types.PropertyChange{
@@ -1075,6 +1085,11 @@ func (vm *VirtualMachine) removeDevice(devices object.VirtualDeviceList, spec *t
 			case *types.VirtualEthernetCardDistributedVirtualPortBackingInfo:
 				net.Type = "DistributedVirtualPortgroup"
 				net.Value = b.Port.PortgroupKey
+			case *types.VirtualEthernetCardOpaqueNetworkBackingInfo:
+				dc := Map.getEntityDatacenter(Map.Get(*vm.Parent).(mo.Entity))
+				if on := findOpaqueNetwork(dc.Network, b.OpaqueNetworkId); on != nil {
+					net = on.Self
+				}
 			}
 
 			networks := vm.Network
@@ -1091,6 +1106,19 @@ func (vm *VirtualMachine) removeDevice(devices object.VirtualDeviceList, spec *t
 	return devices
 }
 
+// findOpaqueNetwork returns the OpaqueNetwork with the given opaqueNetworkId, or nil if not found.
+func findOpaqueNetwork(networks []types.ManagedObjectReference, id string) *mo.OpaqueNetwork {
+	for _, ref := range networks {
+		if net, ok := Map.Get(ref).(*mo.OpaqueNetwork); ok {
+			if s, ok := net.Summary.(*types.OpaqueNetworkSummary); ok && s.OpaqueNetworkId == id {
+				return net
+			}
+		}
+	}
+
+	return nil
+}
+
 func (vm *VirtualMachine) genVmdkPath(p object.DatastorePath) (string, types.BaseMethodFault) {
 	if p.Datastore == "" {
 		p.FromString(vm.Config.Files.VmPathName)
@@ -1344,6 +1372,110 @@ func (vm *VirtualMachine) ReconfigVMTask(ctx *Context, req *types.ReconfigVM_Tas
 	}
 }
 
+// attachedDisk returns the VirtualDisk device backed by the given First Class Disk id, if any.
+func (vm *VirtualMachine) attachedDisk(id types.ID) *types.VirtualDisk {
+	for _, device := range vm.Config.Hardware.Device {
+		if disk, ok := device.(*types.VirtualDisk); ok && disk.VDiskId != nil && disk.VDiskId.Id == id.Id {
+			return disk
+		}
+	}
+	return nil
+}
+
+func (vm *VirtualMachine) AttachDiskTask(ctx *Context, req *types.AttachDisk_Task) soap.HasFault {
+	task := CreateTask(vm, "attachDisk", func(t *Task) (types.AnyType, types.BaseMethodFault) {
+		m, ok := ctx.Map.Get(*ctx.Map.content().VStorageObjectManager).(*VcenterVStorageObjectManager)
+		if !ok {
+			return nil, new(types.NotSupported)
+		}
+
+		obj := m.object(req.Datastore, req.DiskId)
+		if obj == nil || vm.attachedDisk(req.DiskId) != nil {
+			return nil, &types.InvalidArgument{InvalidProperty: "diskId"}
+		}
+
+		backing := obj.Config.Backing.(*types.BaseConfigInfoDiskFileBackingInfo)
+		disk := &types.VirtualDisk{
+			VirtualDevice: types.VirtualDevice{
+				Backing: &types.VirtualDiskFlatVer2BackingInfo{
+					VirtualDeviceFileBackingInfo: types.VirtualDeviceFileBackingInfo{
+						FileName:  backing.FilePath,
+						Datastore: &req.Datastore,
+					},
+					DiskMode:        string(types.VirtualDiskModePersistent),
+					ThinProvisioned: types.NewBool(backing.ProvisioningType == string(types.BaseConfigInfoDiskFileBackingInfoProvisioningTypeThin)),
+				},
+				ControllerKey: req.ControllerKey,
+				UnitNumber:    req.UnitNumber,
+			},
+			CapacityInKB: obj.Config.CapacityInMB * 1024,
+			VDiskId:      &req.DiskId,
+		}
+
+		devices := object.VirtualDeviceList(vm.Config.Hardware.Device)
+
+		var controller types.BaseVirtualController
+		if req.ControllerKey == 0 {
+			c, err := devices.FindSCSIController("")
+			if err != nil {
+				return nil, &types.MissingController{}
+			}
+			controller = c
+		} else {
+			c, ok := devices.FindByKey(req.ControllerKey).(types.BaseVirtualController)
+			if !ok {
+				return nil, &types.InvalidArgument{InvalidProperty: "controllerKey"}
+			}
+			controller = c
+		}
+
+		if req.UnitNumber == nil {
+			devices.AssignController(disk, controller)
+		} else {
+			disk.ControllerKey = controller.GetVirtualController().Key
+		}
+
+		return nil, vm.configure(&types.VirtualMachineConfigSpec{
+			DeviceChange: []types.BaseVirtualDeviceConfigSpec{
+				&types.VirtualDeviceConfigSpec{
+					Operation: types.VirtualDeviceConfigSpecOperationAdd,
+					Device:    disk,
+				},
+			},
+		})
+	})
+
+	return &methods.AttachDisk_TaskBody{
+		Res: &types.AttachDisk_TaskResponse{
+			Returnval: task.Run(),
+		},
+	}
+}
+
+func (vm *VirtualMachine) DetachDiskTask(ctx *Context, req *types.DetachDisk_Task) soap.HasFault {
+	task := CreateTask(vm, "detachDisk", func(t *Task) (types.AnyType, types.BaseMethodFault) {
+		disk := vm.attachedDisk(req.DiskId)
+		if disk == nil {
+			return nil, &types.InvalidArgument{InvalidProperty: "diskId"}
+		}
+
+		return nil, vm.configure(&types.VirtualMachineConfigSpec{
+			DeviceChange: []types.BaseVirtualDeviceConfigSpec{
+				&types.VirtualDeviceConfigSpec{
+					Operation: types.VirtualDeviceConfigSpecOperationRemove,
+					Device:    disk,
+				},
+			},
+		})
+	})
+
+	return &methods.DetachDisk_TaskBody{
+		Res: &types.DetachDisk_TaskResponse{
+			Returnval: task.Run(),
+		},
+	}
+}
+
 func (vm *VirtualMachine) UpgradeVMTask(req *types.UpgradeVM_Task) soap.HasFault {
 	body := &methods.UpgradeVM_TaskBody{}
 
@@ -1481,6 +1613,17 @@ func (vm *VirtualMachine) CloneVMTask(ctx *Context, req *types.CloneVM_Task) soa
 				// Leave FileName empty so CreateVM will just create a new one under VmPathName
 				disk.Backing.(*types.VirtualDiskFlatVer2BackingInfo).FileName = ""
 				disk.Backing.(*types.VirtualDiskFlatVer2BackingInfo).Parent = nil
+			case types.BaseVirtualEthernetCard:
+				if b, ok := disk.GetVirtualEthernetCard().Backing.(*types.VirtualEthernetCardDistributedVirtualPortBackingInfo); ok {
+					// Connect the clone to a new port, leaving the source nic as-is
+					nic := reflect.New(reflect.TypeOf(device).Elem())
+					nic.Elem().Set(reflect.ValueOf(device).Elem())
+					device = nic.Interface().(types.BaseVirtualDevice)
+
+					backing := *b
+					backing.Port.PortKey = ""
+					device.GetVirtualDevice().Backing = &backing
+				}
 			}
 
 			config.DeviceChange = append(config.DeviceChange, &types.VirtualDeviceConfigSpec{
@@ -1490,10 +1633,19 @@ func (vm *VirtualMachine) CloneVMTask(ctx *Context, req *types.CloneVM_Task) soa
 			})
 		}
 
+		if req.Spec.Config != nil && req.Spec.Config.Files != nil && req.Spec.Config.Files.VmPathName != "" {
+			config.Files.VmPathName = req.Spec.Config.Files.VmPathName
+		}
+
+		pool := *vm.ResourcePool
+		if req.Spec.Location.Pool != nil {
+			pool = *req.Spec.Location.Pool
+		}
+
 		res := folder.CreateVMTask(ctx, &types.CreateVM_Task{
 			This:   folder.Self,
 			Config: config,
-			Pool:   *vm.ResourcePool,
+			Pool:   pool,
 			Host:   vm.Runtime.Host,
 		})
 
@@ -1524,10 +1676,69 @@ func (vm *VirtualMachine) CloneVMTask(ctx *Context, req *types.CloneVM_Task) soa
 	}
 }
 
+func (vm *VirtualMachine) InstantCloneTask(ctx *Context, req *types.InstantClone_Task) soap.HasFault {
+	task := CreateTask(vm, "instantClone", func(t *Task) (types.AnyType, types.BaseMethodFault) {
+		if vm.Runtime.PowerState != types.VirtualMachinePowerStatePoweredOn {
+			return nil, &types.InvalidPowerState{
+				RequestedState: types.VirtualMachinePowerStatePoweredOn,
+				ExistingState:  vm.Runtime.PowerState,
+			}
+		}
+
+		folder := *vm.Parent
+		if req.Spec.Location.Folder != nil {
+			folder = *req.Spec.Location.Folder
+		}
+
+		res := vm.CloneVMTask(ctx, &types.CloneVM_Task{
+			This:   vm.Self,
+			Folder: folder,
+			Name:   req.Spec.Name,
+			Spec:   types.VirtualMachineCloneSpec{Location: req.Spec.Location},
+		})
+
+		ctask := Map.Get(res.(*methods.CloneVM_TaskBody).Res.Returnval).(*Task)
+		if ctask.Info.Error != nil {
+			return nil, ctask.Info.Error.Fault
+		}
+
+		ref := ctask.Info.Result.(types.ManagedObjectReference)
+		clone := Map.Get(ref).(*VirtualMachine)
+
+		if err := clone.configure(&types.VirtualMachineConfigSpec{ExtraConfig: req.Spec.Config, Uuid: req.Spec.BiosUuid}); err != nil {
+			return nil, err
+		}
+
+		// An instant clone starts out powered on, in the same running state as its source VM
+		ptask := Map.Get(clone.PowerOnVMTask(ctx, &types.PowerOnVM_Task{This: ref}).(*methods.PowerOnVM_TaskBody).Res.Returnval).(*Task)
+		if ptask.Info.Error != nil {
+			return nil, ptask.Info.Error.Fault
+		}
+
+		return ref, nil
+	})
+
+	return &methods.InstantClone_TaskBody{
+		Res: &types.InstantClone_TaskResponse{
+			Returnval: task.Run(),
+		},
+	}
+}
+
 func (vm *VirtualMachine) RelocateVMTask(req *types.RelocateVM_Task) soap.HasFault {
 	task := CreateTask(vm, "relocateVm", func(t *Task) (types.AnyType, types.BaseMethodFault) {
 		var changes []types.PropertyChange
 
+		devices := object.VirtualDeviceList(vm.Config.Hardware.Device)
+		for _, locator := range req.Spec.Disk {
+			if _, ok := devices.FindByKey(locator.DiskId).(*types.VirtualDisk); !ok {
+				return nil, &types.InvalidArgument{InvalidProperty: "spec.disk.diskId"}
+			}
+			if _, ok := Map.Get(locator.Datastore).(*Datastore); !ok {
+				return nil, &types.ManagedObjectNotFound{Obj: locator.Datastore}
+			}
+		}
+
 		if ref := req.Spec.Datastore; ref != nil {
 			ds := Map.Get(*ref).(*Datastore)
 			Map.RemoveReference(ds, &ds.Vm, *ref)
@@ -1537,6 +1748,43 @@ func (vm *VirtualMachine) RelocateVMTask(req *types.RelocateVM_Task) soap.HasFau
 			changes = append(changes, types.PropertyChange{Name: "datastore", Val: []types.ManagedObjectReference{*ref}})
 		}
 
+		if len(req.Spec.Disk) != 0 {
+			var datastores []types.ManagedObjectReference
+			if ref := req.Spec.Datastore; ref != nil {
+				datastores = append(datastores, *ref)
+			} else if len(vm.Datastore) != 0 {
+				datastores = append(datastores, vm.Datastore[0])
+			}
+
+			for _, locator := range req.Spec.Disk {
+				disk := devices.FindByKey(locator.DiskId).(*types.VirtualDisk)
+				backing, ok := disk.Backing.(*types.VirtualDiskFlatVer2BackingInfo)
+				if !ok {
+					continue
+				}
+
+				ds := locator.Datastore
+				backing.Datastore = &ds
+
+				if b, ok := locator.DiskBackingInfo.(*types.VirtualDiskFlatVer2BackingInfo); ok {
+					backing.ThinProvisioned = b.ThinProvisioned
+					backing.EagerlyScrub = b.EagerlyScrub
+				}
+			}
+
+			for _, device := range devices.SelectByType((*types.VirtualDisk)(nil)) {
+				backing, ok := device.GetVirtualDevice().Backing.(types.BaseVirtualDeviceFileBackingInfo)
+				if !ok {
+					continue
+				}
+				if ds := backing.GetVirtualDeviceFileBackingInfo().Datastore; ds != nil && FindReference(datastores, *ds) == nil {
+					datastores = append(datastores, *ds)
+				}
+			}
+
+			changes = append(changes, types.PropertyChange{Name: "datastore", Val: datastores})
+		}
+
 		if ref := req.Spec.Pool; ref != nil {
 			pool := Map.Get(*ref).(*ResourcePool)
 			Map.RemoveReference(pool, &pool.Vm, *ref)
@@ -1669,6 +1917,22 @@ func (vm *VirtualMachine) RemoveAllSnapshotsTask(ctx *Context, req *types.Remove
 	}
 }
 
+func (vm *VirtualMachine) ConsolidateVMDisksTask(req *types.ConsolidateVMDisks_Task) soap.HasFault {
+	task := CreateTask(vm, "consolidateVMDisks", func(t *Task) (types.AnyType, types.BaseMethodFault) {
+		Map.Update(vm, []types.PropertyChange{
+			{Name: "runtime.consolidationNeeded", Val: types.NewBool(false)},
+		})
+
+		return nil, nil
+	})
+
+	return &methods.ConsolidateVMDisks_TaskBody{
+		Res: &types.ConsolidateVMDisks_TaskResponse{
+			Returnval: task.Run(),
+		},
+	}
+}
+
 func (vm *VirtualMachine) ShutdownGuest(ctx *Context, c *types.ShutdownGuest) soap.HasFault {
 	r := &methods.ShutdownGuestBody{}
 	// should be poweron
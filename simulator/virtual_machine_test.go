@@ -30,6 +30,8 @@ import (
 	"github.com/vmware/govmomi/property"
 	"github.com/vmware/govmomi/simulator/esx"
 	"github.com/vmware/govmomi/task"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/mo"
 	"github.com/vmware/govmomi/vim25/types"
 )
 
@@ -835,6 +837,122 @@ func TestVmSnapshot(t *testing.T) {
 	}
 }
 
+func TestVmSnapshotTree(t *testing.T) {
+	Test(func(ctx context.Context, c *vim25.Client) {
+		simVm := Map.Any("VirtualMachine").(*VirtualMachine)
+		vm := object.NewVirtualMachine(c, simVm.Reference())
+
+		snapshot := func(name string) {
+			task, err := vm.CreateSnapshot(ctx, name, "", false, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err = task.Wait(ctx); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		revert := func(name string) {
+			task, err := vm.RevertToSnapshot(ctx, name, true)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err = task.Wait(ctx); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		_, err := vm.FindSnapshot(ctx, "root")
+		if err != object.ErrNoSnapshots {
+			t.Errorf("err=%v", err)
+		}
+
+		snapshot("root")
+		snapshot("child")
+		snapshot("grandkid")
+		revert("root")
+		snapshot("child")
+
+		_, err = vm.FindSnapshot(ctx, "child")
+		if e, ok := err.(*object.SnapshotAmbiguousError); !ok || len(e.Snapshots) != 2 {
+			t.Errorf("err=%#v", err)
+		}
+
+		_, err = vm.FindSnapshot(ctx, "enoent")
+		if _, ok := err.(*object.SnapshotNotFoundError); !ok {
+			t.Errorf("err=%#v", err)
+		}
+
+		var paths []string
+		var child types.ManagedObjectReference
+		err = vm.WalkSnapshots(ctx, func(name string, node *types.VirtualMachineSnapshotTree) error {
+			paths = append(paths, name)
+			if len(node.ChildSnapshotList) != 0 && node.Name == "child" {
+				child = node.Snapshot
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(paths) != 4 {
+			t.Errorf("paths=%v", paths)
+		}
+
+		for i := range simVm.LayoutEx.File {
+			simVm.LayoutEx.File[i].Size = 1 // snapshot files are created empty
+		}
+
+		root, err := vm.SnapshotChainSize(ctx, "root")
+		if err != nil {
+			t.Fatal(err)
+		}
+		chain, err := vm.SnapshotChainSize(ctx, "grandkid")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if root == 0 || chain != root+2 { // grandkid and child data files
+			t.Errorf("root=%d, chain=%d", root, chain)
+		}
+
+		revert("grandkid")
+
+		err = vm.RemoveSnapshotTree(ctx, child.Value)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		paths = nil
+		_ = vm.WalkSnapshots(ctx, func(name string, _ *types.VirtualMachineSnapshotTree) error {
+			paths = append(paths, name)
+			return nil
+		})
+		if len(paths) != 2 {
+			t.Errorf("paths=%v", paths)
+		}
+
+		for _, ref := range []string{child.Value, "grandkid"} {
+			if _, err = vm.FindSnapshot(ctx, ref); err == nil {
+				t.Errorf("%s should be removed", ref)
+			}
+		}
+
+		current, err := vm.FindSnapshot(ctx, "root")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var o mo.VirtualMachine
+		err = vm.Properties(ctx, vm.Reference(), []string{"snapshot.currentSnapshot"}, &o)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if *o.Snapshot.CurrentSnapshot != *current {
+			t.Errorf("current=%s", o.Snapshot.CurrentSnapshot)
+		}
+	})
+}
+
 func TestVmMarkAsTemplate(t *testing.T) {
 	ctx := context.Background()
 
@@ -1121,3 +1239,161 @@ func TestVmRefreshStorageInfo(t *testing.T) {
 		t.Errorf("expected %d, got %d", fileLayoutExCount, len(vmm.LayoutEx.File))
 	}
 }
+
+func TestInstantClone(t *testing.T) {
+	Test(func(ctx context.Context, c *vim25.Client) {
+		finder := find.NewFinder(c)
+
+		vm, err := finder.VirtualMachine(ctx, "DC0_H0_VM0")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		spec := object.NewInstantCloneSpec("DC0_H0_VM0_IC", types.VirtualMachineRelocateSpec{}, object.GuestInfo{
+			"hostname": "ic",
+		})
+
+		task, err := vm.InstantClone(ctx, spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := task.WaitForResult(ctx, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var clone mo.VirtualMachine
+		err = vm.Properties(ctx, res.Result.(types.ManagedObjectReference), []string{"name", "runtime.powerState", "config.extraConfig"}, &clone)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if clone.Name != spec.Name || clone.Runtime.PowerState != types.VirtualMachinePowerStatePoweredOn {
+			t.Errorf("name=%s state=%s", clone.Name, clone.Runtime.PowerState)
+		}
+
+		found := false
+		for _, opt := range clone.Config.ExtraConfig {
+			if o := opt.GetOptionValue(); o.Key == "guestinfo.hostname" && o.Value == "ic" {
+				found = true
+			}
+		}
+		if !found {
+			t.Error("guestinfo.hostname not set")
+		}
+
+		// the source VM must be powered on
+		task, err = vm.PowerOff(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = task.Wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		spec.Name += "2"
+		task, err = vm.InstantClone(ctx, spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = task.Wait(ctx); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
+
+func TestRelocateSpecBuilder(t *testing.T) {
+	m := VPX()
+	m.Datastore = 2
+
+	Test(func(ctx context.Context, c *vim25.Client) {
+		finder := find.NewFinder(c)
+
+		vm, err := finder.VirtualMachine(ctx, "DC0_H0_VM0")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ds, err := finder.Datastore(ctx, "LocalDS_1")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		devices, err := vm.Device(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		disk := devices.SelectByType((*types.VirtualDisk)(nil))[0].(*types.VirtualDisk)
+		name := devices.Name(disk)
+		cdrom := devices.Name(devices.SelectByType((*types.VirtualCdrom)(nil))[0])
+
+		invalid := []*object.RelocateSpecBuilder{
+			vm.RelocateSpecBuilder().Disk("enoent", object.DiskPlacement{}),
+			vm.RelocateSpecBuilder().Disk(cdrom, object.DiskPlacement{}),
+			vm.RelocateSpecBuilder().DiskKey(disk.Key, object.DiskPlacement{}).Disk(name, object.DiskPlacement{}),
+			vm.RelocateSpecBuilder().Disk(name, object.DiskPlacement{Format: types.VirtualDiskTypeSeSparse}),
+			vm.RelocateSpecBuilder().Disk(name, object.DiskPlacement{Datastore: vm.Reference()}),
+			vm.RelocateSpecBuilder().Service(&types.ServiceLocator{}).Disk(name, object.DiskPlacement{}),
+		}
+
+		for i, b := range invalid {
+			if _, err = b.Build(ctx); err == nil {
+				t.Errorf("%d: expected error", i)
+			}
+		}
+
+		spec, err := vm.RelocateSpecBuilder().
+			Disk(name, object.DiskPlacement{Datastore: ds.Reference(), Format: types.VirtualDiskTypeThin}).
+			Build(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(spec.Disk) != 1 || spec.Disk[0].DiskId != disk.Key || spec.Disk[0].Datastore != ds.Reference() {
+			t.Fatalf("spec=%#v", spec.Disk)
+		}
+
+		task, err := vm.Relocate(ctx, spec, types.VirtualMachineMovePriorityDefaultPriority)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = task.Wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		devices, err = vm.Device(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		backing := devices.FindByKey(disk.Key).GetVirtualDevice().Backing.(*types.VirtualDiskFlatVer2BackingInfo)
+		if *backing.Datastore != ds.Reference() || !*backing.ThinProvisioned {
+			t.Errorf("backing=%#v", backing)
+		}
+
+		var props mo.VirtualMachine
+		if err = vm.Properties(ctx, vm.Reference(), []string{"datastore"}, &props); err != nil {
+			t.Fatal(err)
+		}
+		if len(props.Datastore) != 2 {
+			t.Errorf("datastore=%v", props.Datastore)
+		}
+
+		// disk defaults to the VM's target datastore
+		spec, err = vm.RelocateSpecBuilder().Datastore(ds).DiskKey(disk.Key, object.DiskPlacement{}).Build(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if spec.Disk[0].Datastore != ds.Reference() || spec.Disk[0].DiskBackingInfo != nil {
+			t.Errorf("spec=%#v", spec.Disk)
+		}
+
+		// cross vCenter migration requires an explicit datastore
+		spec, err = vm.RelocateSpecBuilder().Service(&types.ServiceLocator{}).Datastore(ds).DiskKey(disk.Key, object.DiskPlacement{}).Build(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if spec.Disk[0].Datastore != ds.Reference() {
+			t.Errorf("spec=%#v", spec.Disk)
+		}
+	}, m)
+}
@@ -0,0 +1,171 @@
+/*
+Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+func TestVStorageObjectAttachDisk(t *testing.T) {
+	Test(func(ctx context.Context, c *vim25.Client) {
+		finder := find.NewFinder(c)
+
+		ds, err := finder.DefaultDatastore(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		vm, err := finder.VirtualMachine(ctx, "DC0_H0_VM0")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		m := object.NewVStorageObjectManager(c)
+
+		task, err := m.CreateDisk(ctx, types.VslmCreateSpec{
+			Name:         "fcd",
+			CapacityInMB: 10,
+			BackingSpec: &types.VslmCreateSpecDiskFileBackingSpec{
+				VslmCreateSpecBackingSpec: types.VslmCreateSpecBackingSpec{
+					Datastore: ds.Reference(),
+				},
+				ProvisioningType: string(types.BaseConfigInfoDiskFileBackingInfoProvisioningTypeThin),
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		res, err := task.WaitForResult(ctx, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		id := res.Result.(types.VStorageObject).Config.Id.Id
+
+		attached := func() *types.VirtualDisk {
+			var o mo.VirtualMachine
+			if err := vm.Properties(ctx, vm.Reference(), []string{"config.hardware.device"}, &o); err != nil {
+				t.Fatal(err)
+			}
+			for _, disk := range object.VirtualDeviceList(o.Config.Hardware.Device).SelectByType((*types.VirtualDisk)(nil)) {
+				if d := disk.(*types.VirtualDisk); d.VDiskId != nil && d.VDiskId.Id == id {
+					return d
+				}
+			}
+			return nil
+		}
+
+		task, err = m.AttachDisk(ctx, vm, ds, id, 0, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = task.Wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		disk := attached()
+		if disk == nil {
+			t.Fatal("disk not attached")
+		}
+		if disk.CapacityInKB != 10*1024 || disk.UnitNumber == nil {
+			t.Errorf("disk=%#v", disk)
+		}
+
+		// attaching the same disk again fails
+		task, err = vm.AttachDisk(ctx, id, ds, 0, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = task.Wait(ctx); err == nil {
+			t.Error("expected error")
+		}
+
+		task, err = m.DetachDisk(ctx, vm, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = task.Wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		if attached() != nil {
+			t.Error("disk still attached")
+		}
+
+		// the disk itself still exists
+		if _, err = m.Retrieve(ctx, ds, id); err != nil {
+			t.Error(err)
+		}
+		ids, err := m.List(ctx, ds)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(ids) != 1 || ids[0].Id != id {
+			t.Errorf("ids=%v", ids)
+		}
+
+		if _, err = vm.AttachDisk(ctx, id, nil, 0, nil); err == nil {
+			t.Error("expected error")
+		}
+
+		task, err = vm.DetachDisk(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = task.Wait(ctx); err == nil {
+			t.Error("expected error")
+		}
+
+		task, err = m.Delete(ctx, ds, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = task.Wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err = m.Retrieve(ctx, ds, id); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
+
+func TestVStorageObjectManagerNotSupported(t *testing.T) {
+	Test(func(ctx context.Context, c *vim25.Client) {
+		if _, err := object.GetVStorageObjectManager(c); err != nil {
+			t.Fatal(err)
+		}
+
+		c.ServiceContent.VStorageObjectManager = nil
+
+		if _, err := object.GetVStorageObjectManager(c); err != object.ErrNotSupported {
+			t.Errorf("err=%v", err)
+		}
+
+		m := object.NewVStorageObjectManager(c)
+		if ref := m.Reference(); ref.Value != "" {
+			t.Errorf("ref=%s", ref)
+		}
+	})
+}
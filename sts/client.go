@@ -25,6 +25,7 @@ import (
 
 	"github.com/vmware/govmomi/lookup"
 	"github.com/vmware/govmomi/lookup/types"
+	"github.com/vmware/govmomi/session"
 	"github.com/vmware/govmomi/sts/internal"
 	"github.com/vmware/govmomi/vim25"
 	"github.com/vmware/govmomi/vim25/soap"
@@ -207,3 +208,33 @@ func (c *Client) Renew(ctx context.Context, req TokenRequest) (*Signer, error) {
 
 	return s, s.setLifetime(res.Lifetime)
 }
+
+// LoginByCertificate issues a Holder-of-Key token for the given client certificate, or that of the vim25.Client if
+// req.Certificate is nil, then uses the token to login via the SessionManager LoginByToken method.
+// This allows solution users to authenticate using only a certificate and private key, without a password.
+// The returned Signer can be used with NewRenewer to keep the token and session valid.
+func LoginByCertificate(ctx context.Context, c *vim25.Client, req TokenRequest) (*Signer, error) {
+	if req.Certificate == nil {
+		req.Certificate = c.Certificate()
+	}
+	if req.Certificate == nil {
+		return nil, errors.New("client certificate is required")
+	}
+
+	tokens, err := NewClient(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := tokens.Issue(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	header := soap.Header{Security: s}
+	if err = session.NewManager(c).LoginByToken(c.WithHeader(ctx, header)); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
@@ -28,6 +28,7 @@ import (
 	"time"
 
 	"github.com/vmware/govmomi/session"
+	"github.com/vmware/govmomi/simulator"
 	"github.com/vmware/govmomi/ssoadmin"
 	"github.com/vmware/govmomi/ssoadmin/types"
 	"github.com/vmware/govmomi/vim25"
@@ -301,3 +302,30 @@ func TestIssueActAs(t *testing.T) {
 
 	t.Logf("expires in %s", s.Lifetime.Expires.Sub(s.Lifetime.Created))
 }
+
+func TestLoginByCertificate(t *testing.T) {
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		sm := session.NewManager(c)
+		if err := sm.Logout(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := LoginByCertificate(ctx, c, TokenRequest{}); err == nil {
+			t.Error("expected error") // client certificate not set
+		}
+
+		c.SetCertificate(*solutionUserCert())
+
+		s, err := LoginByCertificate(ctx, c, TokenRequest{Renewable: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s.Certificate == nil {
+			t.Error("expected HoK token")
+		}
+
+		if _, err = methods.GetCurrentTime(ctx, c); err != nil {
+			t.Error(err)
+		}
+	})
+}
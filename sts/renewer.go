@@ -0,0 +1,154 @@
+/*
+Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sts
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/vmware/govmomi/session"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/methods"
+	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// Renewer keeps a renewable token valid, by renewing it via the STS Renew operation ahead of its expiry.
+type Renewer struct {
+	// Before is how long before the token expires that it is renewed. Defaults to a fifth of
+	// the token's lifetime, which is also used if Before is not less than the token's lifetime.
+	Before time.Duration
+
+	client *Client
+	req    TokenRequest
+
+	mu     sync.Mutex
+	signer *Signer
+}
+
+// NewRenewer creates a Renewer for the given Signer, as returned by Issue with TokenRequest.Renewable set.
+// The req parameter is used for Renew requests, where req.Certificate must be set to sign
+// holder-of-key tokens and req.Lifetime specifies the lifetime of renewed tokens.
+func NewRenewer(c *Client, s *Signer, req TokenRequest) *Renewer {
+	return &Renewer{
+		client: c,
+		req:    req,
+		signer: s,
+	}
+}
+
+// Signer returns the current Signer, which is replaced each time the token is renewed.
+func (r *Renewer) Signer() *Signer {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.signer
+}
+
+// renewAt returns the time at which the current token should be renewed.
+func (r *Renewer) renewAt() time.Time {
+	s := r.Signer()
+
+	lifetime := s.Lifetime.Expires.Sub(s.Lifetime.Created)
+	before := r.Before
+	if before <= 0 || before >= lifetime {
+		before = lifetime / 5
+	}
+
+	return s.Lifetime.Expires.Add(-before)
+}
+
+// Due returns true if the token should be renewed.
+func (r *Renewer) Due() bool {
+	return !time.Now().Before(r.renewAt())
+}
+
+// Renew renews the token, regardless of whether it is due.
+func (r *Renewer) Renew(ctx context.Context) (*Signer, error) {
+	req := r.req
+	req.Token = r.Signer().Token
+
+	s, err := r.client.Renew(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	r.signer = s
+	r.mu.Unlock()
+
+	return s, nil
+}
+
+// Run renews the token each time it is due, until the context is canceled or a renewal fails.
+func (r *Renewer) Run(ctx context.Context) error {
+	for {
+		t := time.NewTimer(time.Until(r.renewAt()))
+
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-t.C:
+			if _, err := r.Renew(ctx); err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				return err
+			}
+		}
+	}
+}
+
+// KeepAliveHandler returns a handler for use with session.KeepAliveHandler, for a session
+// created with LoginByToken using the Renewer's Signer. The handler renews the token when it is due,
+// then sends the keep alive request, calling LoginByToken with the current token if the session
+// is no longer authenticated. If renewal or login fails, the handler returns an error,
+// stopping the keep alive.
+func (r *Renewer) KeepAliveHandler(c *vim25.Client) func(soap.RoundTripper) error {
+	return func(rt soap.RoundTripper) error {
+		ctx := context.Background()
+
+		if r.Due() {
+			if _, err := r.Renew(ctx); err != nil {
+				return err
+			}
+		}
+
+		_, err := methods.GetCurrentTime(ctx, rt)
+		if err == nil {
+			return nil
+		}
+
+		if !isNotAuthenticated(err) {
+			return err
+		}
+
+		header := soap.Header{Security: r.Signer()}
+		return session.NewManager(c).LoginByToken(c.WithHeader(ctx, header))
+	}
+}
+
+func isNotAuthenticated(err error) bool {
+	if soap.IsSoapFault(err) {
+		switch soap.ToSoapFault(err).VimFault().(type) {
+		case types.NotAuthenticated, *types.NotAuthenticated:
+			return true
+		}
+	}
+	return false
+}
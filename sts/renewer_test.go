@@ -0,0 +1,108 @@
+/*
+Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sts
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	_ "github.com/vmware/govmomi/lookup/simulator"
+	"github.com/vmware/govmomi/session"
+	"github.com/vmware/govmomi/simulator"
+	_ "github.com/vmware/govmomi/sts/simulator"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/soap"
+)
+
+func TestRenewer(t *testing.T) {
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		sts, err := NewClient(ctx, c)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req := TokenRequest{
+			Userinfo:  simulator.DefaultLogin,
+			Renewable: true,
+		}
+
+		s, err := sts.Issue(ctx, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		r := NewRenewer(sts, s, req)
+		if r.Due() {
+			t.Error("token should not be due for renewal")
+		}
+
+		// Run returns once the context is canceled
+		rctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+		if err = r.Run(rctx); err != context.DeadlineExceeded {
+			t.Errorf("Run: %v", err)
+		}
+
+		r.Before = s.Lifetime.Expires.Sub(s.Lifetime.Created) - time.Millisecond
+		time.Sleep(time.Millisecond)
+		if !r.Due() {
+			t.Error("token should be due for renewal")
+		}
+
+		if _, err = r.Renew(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if r.Signer() == s {
+			t.Error("token was not renewed")
+		}
+		r.Before = 0
+
+		sm := session.NewManager(c)
+		if err = sm.Logout(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		header := soap.Header{Security: r.Signer()}
+		if err = sm.LoginByToken(c.WithHeader(ctx, header)); err != nil {
+			t.Fatal(err)
+		}
+
+		handler := r.KeepAliveHandler(c)
+
+		if err = handler(c); err != nil {
+			t.Fatal(err)
+		}
+
+		// The handler logs in again once the session is gone
+		if err = sm.Logout(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		if err = handler(c); err != nil {
+			t.Fatal(err)
+		}
+
+		u, err := sm.UserSession(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if u == nil {
+			t.Error("expected session")
+		}
+	})
+}
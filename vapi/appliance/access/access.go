@@ -0,0 +1,108 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package access
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/vmware/govmomi/vapi/internal"
+	"github.com/vmware/govmomi/vapi/rest"
+)
+
+// MaxShellTimeout is the maximum number of seconds the BASH shell can be enabled for.
+const MaxShellTimeout = 86400
+
+// Manager extends rest.Client, adding appliance access related methods.
+type Manager struct {
+	*rest.Client
+}
+
+// NewManager creates a new Manager instance with the given client.
+func NewManager(client *rest.Client) *Manager {
+	return &Manager{
+		Client: client,
+	}
+}
+
+// Shell is the BASH shell access configuration.
+// Timeout is the number of seconds the shell remains enabled, after which it is disabled.
+type Shell struct {
+	Enabled bool `json:"enabled"`
+	Timeout int  `json:"timeout"`
+}
+
+func (c *Manager) enabled(ctx context.Context, method string) (bool, error) {
+	url := internal.URL(c, internal.ApplianceAccessPath+"/"+method)
+	var res bool
+	return res, c.Do(ctx, url.Request(ctx, http.MethodGet), &res)
+}
+
+func (c *Manager) enable(ctx context.Context, method string, enabled bool) error {
+	url := internal.URL(c, internal.ApplianceAccessPath+"/"+method)
+	spec := struct {
+		Enabled bool `json:"enabled"`
+	}{enabled}
+	return c.Do(ctx, url.Request(ctx, http.MethodPut, spec), nil)
+}
+
+// GetSSH returns true if SSH based login is enabled.
+func (c *Manager) GetSSH(ctx context.Context) (bool, error) {
+	return c.enabled(ctx, "ssh")
+}
+
+// SetSSH enables or disables SSH based login.
+func (c *Manager) SetSSH(ctx context.Context, enabled bool) error {
+	return c.enable(ctx, "ssh", enabled)
+}
+
+// GetDCUI returns true if Direct Console User Interface login is enabled.
+func (c *Manager) GetDCUI(ctx context.Context) (bool, error) {
+	return c.enabled(ctx, "dcui")
+}
+
+// SetDCUI enables or disables Direct Console User Interface login.
+func (c *Manager) SetDCUI(ctx context.Context, enabled bool) error {
+	return c.enable(ctx, "dcui", enabled)
+}
+
+// GetConsoleCLI returns true if console based login is enabled.
+func (c *Manager) GetConsoleCLI(ctx context.Context) (bool, error) {
+	return c.enabled(ctx, "consolecli")
+}
+
+// SetConsoleCLI enables or disables console based login.
+func (c *Manager) SetConsoleCLI(ctx context.Context, enabled bool) error {
+	return c.enable(ctx, "consolecli", enabled)
+}
+
+// GetShell returns the BASH shell access configuration, where Timeout is the number of seconds remaining.
+func (c *Manager) GetShell(ctx context.Context) (*Shell, error) {
+	url := internal.URL(c, internal.ApplianceAccessPath+"/shell")
+	var res Shell
+	return &res, c.Do(ctx, url.Request(ctx, http.MethodGet), &res)
+}
+
+// SetShell enables or disables BASH shell access.
+// When enabling, the Timeout must be between 1 and MaxShellTimeout seconds.
+func (c *Manager) SetShell(ctx context.Context, config Shell) error {
+	url := internal.URL(c, internal.ApplianceAccessPath+"/shell")
+	spec := struct {
+		Config Shell `json:"config"`
+	}{config}
+	return c.Do(ctx, url.Request(ctx, http.MethodPut, spec), nil)
+}
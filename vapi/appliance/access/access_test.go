@@ -0,0 +1,112 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package access_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/appliance/access"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vim25"
+
+	_ "github.com/vmware/govmomi/vapi/simulator"
+)
+
+func TestAccess(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+		if err := c.Login(ctx, simulator.DefaultLogin); err != nil {
+			t.Fatal(err)
+		}
+
+		m := access.NewManager(c)
+
+		tests := []struct {
+			name string
+			get  func(context.Context) (bool, error)
+			set  func(context.Context, bool) error
+		}{
+			{"ssh", m.GetSSH, m.SetSSH},
+			{"dcui", m.GetDCUI, m.SetDCUI},
+			{"consolecli", m.GetConsoleCLI, m.SetConsoleCLI},
+		}
+
+		for _, test := range tests {
+			enabled, err := test.get(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err = test.set(ctx, !enabled); err != nil {
+				t.Fatal(err)
+			}
+
+			toggled, err := test.get(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if toggled == enabled {
+				t.Errorf("%s: enabled=%t", test.name, toggled)
+			}
+		}
+	})
+}
+
+func TestShell(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+		if err := c.Login(ctx, simulator.DefaultLogin); err != nil {
+			t.Fatal(err)
+		}
+
+		m := access.NewManager(c)
+
+		shell, err := m.GetShell(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if shell.Enabled {
+			t.Errorf("shell=%#v", shell)
+		}
+
+		if err = m.SetShell(ctx, access.Shell{Enabled: true, Timeout: access.MaxShellTimeout + 1}); err == nil {
+			t.Error("expected error")
+		}
+
+		if err = m.SetShell(ctx, access.Shell{Enabled: true, Timeout: 3600}); err != nil {
+			t.Fatal(err)
+		}
+		if shell, err = m.GetShell(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if !shell.Enabled || shell.Timeout <= 0 || shell.Timeout > 3600 {
+			t.Errorf("shell=%#v", shell)
+		}
+
+		if err = m.SetShell(ctx, access.Shell{Enabled: false}); err != nil {
+			t.Fatal(err)
+		}
+		if shell, err = m.GetShell(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if shell.Enabled || shell.Timeout != 0 {
+			t.Errorf("shell=%#v", shell)
+		}
+	})
+}
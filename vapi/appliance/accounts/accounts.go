@@ -0,0 +1,152 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package accounts
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/vmware/govmomi/vapi/internal"
+	"github.com/vmware/govmomi/vapi/rest"
+)
+
+// Local account roles
+const (
+	RoleSuperAdmin = "superAdmin"
+	RoleAdmin      = "admin"
+	RoleOperator   = "operator"
+)
+
+// Manager extends rest.Client, adding appliance local account related methods.
+type Manager struct {
+	*rest.Client
+}
+
+// NewManager creates a new Manager instance with the given client.
+func NewManager(client *rest.Client) *Manager {
+	return &Manager{
+		Client: client,
+	}
+}
+
+// Info describes a local account.
+type Info struct {
+	FullName                         string     `json:"fullname,omitempty"`
+	Email                            string     `json:"email,omitempty"`
+	Roles                            []string   `json:"roles"`
+	Enabled                          bool       `json:"enabled"`
+	HasPassword                      bool       `json:"has_password"`
+	LastPasswordChange               *time.Time `json:"last_password_change,omitempty"`
+	PasswordExpiresAt                *time.Time `json:"password_expires_at,omitempty"`
+	InactiveAt                       *time.Time `json:"inactive_at,omitempty"`
+	MinDaysBetweenPasswordChange     *int       `json:"min_days_between_password_change,omitempty"`
+	MaxDaysWithoutPasswordChange     *int       `json:"max_days_without_password_change,omitempty"`
+	WarnDaysBeforePasswordExpiration *int       `json:"warn_days_before_password_expiration,omitempty"`
+}
+
+// Config specifies the configuration of a local account.
+// When used with Update, only the fields that are set are changed.
+// OldPassword is required when users change their own Password.
+type Config struct {
+	Password                         string     `json:"password,omitempty"`
+	OldPassword                      string     `json:"old_password,omitempty"`
+	FullName                         string     `json:"full_name,omitempty"`
+	Email                            string     `json:"email,omitempty"`
+	Roles                            []string   `json:"roles,omitempty"`
+	Enabled                          *bool      `json:"enabled,omitempty"`
+	PasswordExpires                  *bool      `json:"password_expires,omitempty"`
+	PasswordExpiresAt                *time.Time `json:"password_expires_at,omitempty"`
+	InactiveAfterPasswordExpiration  *bool      `json:"inactive_after_password_expiration,omitempty"`
+	DaysAfterPasswordExpiration      *int       `json:"days_after_password_expiration,omitempty"`
+	MinDaysBetweenPasswordChange     *int       `json:"min_days_between_password_change,omitempty"`
+	MaxDaysWithoutPasswordChange     *int       `json:"max_days_without_password_change,omitempty"`
+	WarnDaysBeforePasswordExpiration *int       `json:"warn_days_before_password_expiration,omitempty"`
+}
+
+// Policy is the global password policy, applied to new local accounts.
+type Policy struct {
+	MaxDays  int `json:"max_days"`
+	MinDays  int `json:"min_days"`
+	WarnDays int `json:"warn_days"`
+}
+
+func (c *Manager) resource(username string) *internal.Resource {
+	return internal.URL(c, internal.ApplianceLocalAccountsPath).WithSubpath(username)
+}
+
+func (c *Manager) config(ctx context.Context, method string, username string, config Config) error {
+	spec := struct {
+		Config Config `json:"config"`
+	}{config}
+	return c.Do(ctx, c.resource(username).Request(ctx, method, spec), nil)
+}
+
+// List returns the usernames of all local accounts.
+func (c *Manager) List(ctx context.Context) ([]string, error) {
+	url := internal.URL(c, internal.ApplianceLocalAccountsPath)
+	var res []string
+	return res, c.Do(ctx, url.Request(ctx, http.MethodGet), &res)
+}
+
+// Get returns the given local account.
+func (c *Manager) Get(ctx context.Context, username string) (*Info, error) {
+	var res Info
+	return &res, c.Do(ctx, c.resource(username).Request(ctx, http.MethodGet), &res)
+}
+
+// Create creates a local account, the Password and Roles are required.
+func (c *Manager) Create(ctx context.Context, username string, config Config) error {
+	return c.config(ctx, http.MethodPost, username, config)
+}
+
+// Set replaces the configuration of the given local account.
+func (c *Manager) Set(ctx context.Context, username string, config Config) error {
+	return c.config(ctx, http.MethodPut, username, config)
+}
+
+// Update changes the fields of the given local account that are set in config.
+func (c *Manager) Update(ctx context.Context, username string, config Config) error {
+	return c.config(ctx, http.MethodPatch, username, config)
+}
+
+// Delete deletes the given local account.
+func (c *Manager) Delete(ctx context.Context, username string) error {
+	return c.Do(ctx, c.resource(username).Request(ctx, http.MethodDelete), nil)
+}
+
+// Unlock enables the given local account, such as one that was disabled after its password expired.
+func (c *Manager) Unlock(ctx context.Context, username string) error {
+	enabled := true
+	return c.Update(ctx, username, Config{Enabled: &enabled})
+}
+
+// GetGlobalPolicy returns the global password policy.
+func (c *Manager) GetGlobalPolicy(ctx context.Context) (*Policy, error) {
+	url := internal.URL(c, internal.ApplianceLocalAccountsPath+"/global-policy")
+	var res Policy
+	return &res, c.Do(ctx, url.Request(ctx, http.MethodGet), &res)
+}
+
+// SetGlobalPolicy sets the global password policy.
+func (c *Manager) SetGlobalPolicy(ctx context.Context, policy Policy) error {
+	url := internal.URL(c, internal.ApplianceLocalAccountsPath+"/global-policy")
+	spec := struct {
+		Policy Policy `json:"policy"`
+	}{policy}
+	return c.Do(ctx, url.Request(ctx, http.MethodPut, spec), nil)
+}
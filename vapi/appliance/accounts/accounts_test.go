@@ -0,0 +1,181 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package accounts_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/appliance/accounts"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vim25"
+
+	_ "github.com/vmware/govmomi/vapi/simulator"
+)
+
+// jsonContentType fails the test if a PUT request is sent without the JSON Content-Type.
+func jsonContentType(t *testing.T) rest.Middleware {
+	return func(next rest.Handler) rest.Handler {
+		return func(ctx context.Context, req *http.Request, resBody interface{}) error {
+			if req.Method == http.MethodPut && req.Header.Get("Content-Type") != "application/json" {
+				t.Errorf("PUT %s: Content-Type=%q", req.URL.Path, req.Header.Get("Content-Type"))
+			}
+			return next(ctx, req, resBody)
+		}
+	}
+}
+
+func TestLocalAccounts(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc, rest.WithMiddleware(jsonContentType(t)))
+		if err := c.Login(ctx, simulator.DefaultLogin); err != nil {
+			t.Fatal(err)
+		}
+
+		m := accounts.NewManager(c)
+
+		names, err := m.List(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(names) != 1 || names[0] != "root" {
+			t.Errorf("names=%v", names)
+		}
+
+		config := accounts.Config{
+			Password: "Passw0rd!",
+			FullName: "Backup Operator",
+			Roles:    []string{accounts.RoleOperator},
+		}
+		if err = m.Create(ctx, "backup", config); err != nil {
+			t.Fatal(err)
+		}
+
+		var ae *rest.AlreadyExists
+		if err = m.Create(ctx, "backup", config); !errors.As(err, &ae) {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		info, err := m.Get(ctx, "backup")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !info.Enabled || !info.HasPassword || info.FullName != config.FullName || info.PasswordExpiresAt == nil {
+			t.Errorf("info=%#v", info)
+		}
+		if info.WarnDaysBeforePasswordExpiration == nil || *info.WarnDaysBeforePasswordExpiration != 7 {
+			t.Errorf("warn days=%v", info.WarnDaysBeforePasswordExpiration)
+		}
+
+		// rotate the password
+		err = m.Update(ctx, "backup", accounts.Config{Password: "n3wPassw0rd!", OldPassword: "invalid"})
+		if err == nil {
+			t.Error("expected error")
+		}
+		if err = m.Update(ctx, "backup", accounts.Config{Password: "n3wPassw0rd!", OldPassword: config.Password}); err != nil {
+			t.Fatal(err)
+		}
+
+		warn := 14
+		disabled := false
+		if err = m.Update(ctx, "backup", accounts.Config{WarnDaysBeforePasswordExpiration: &warn, Enabled: &disabled}); err != nil {
+			t.Fatal(err)
+		}
+		if info, err = m.Get(ctx, "backup"); err != nil {
+			t.Fatal(err)
+		}
+		if info.Enabled || *info.WarnDaysBeforePasswordExpiration != warn || info.FullName != config.FullName {
+			t.Errorf("info=%#v", info)
+		}
+
+		if err = m.Unlock(ctx, "backup"); err != nil {
+			t.Fatal(err)
+		}
+		if info, err = m.Get(ctx, "backup"); err != nil {
+			t.Fatal(err)
+		}
+		if !info.Enabled {
+			t.Errorf("info=%#v", info)
+		}
+
+		expires := false
+		if err = m.Set(ctx, "backup", accounts.Config{Roles: []string{accounts.RoleAdmin}, PasswordExpires: &expires}); err != nil {
+			t.Fatal(err)
+		}
+		if info, err = m.Get(ctx, "backup"); err != nil {
+			t.Fatal(err)
+		}
+		if info.PasswordExpiresAt != nil || info.FullName != "" || info.Roles[0] != accounts.RoleAdmin || !info.HasPassword {
+			t.Errorf("info=%#v", info)
+		}
+
+		if err = m.Delete(ctx, "backup"); err != nil {
+			t.Fatal(err)
+		}
+		var nf *rest.NotFound
+		if _, err = m.Get(ctx, "backup"); !errors.As(err, &nf) {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
+
+func TestGlobalPolicy(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc, rest.WithMiddleware(jsonContentType(t)))
+		if err := c.Login(ctx, simulator.DefaultLogin); err != nil {
+			t.Fatal(err)
+		}
+
+		m := accounts.NewManager(c)
+
+		policy := accounts.Policy{MaxDays: 60, MinDays: 1, WarnDays: 10}
+		if err := m.SetGlobalPolicy(ctx, policy); err != nil {
+			t.Fatal(err)
+		}
+		if err := m.SetGlobalPolicy(ctx, accounts.Policy{MaxDays: 1, MinDays: 2}); err == nil {
+			t.Error("expected error")
+		}
+
+		res, err := m.GetGlobalPolicy(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if *res != policy {
+			t.Errorf("policy=%#v", res)
+		}
+
+		// new accounts use the global policy
+		err = m.Create(ctx, "ops", accounts.Config{Password: "Passw0rd!", Roles: []string{accounts.RoleOperator}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		info, err := m.Get(ctx, "ops")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if *info.MaxDaysWithoutPasswordChange != policy.MaxDays || *info.WarnDaysBeforePasswordExpiration != policy.WarnDays {
+			t.Errorf("info=%#v", info)
+		}
+		if d := time.Until(*info.PasswordExpiresAt); d < 59*24*time.Hour || d > 60*24*time.Hour {
+			t.Errorf("password expires in %s", d)
+		}
+	})
+}
@@ -0,0 +1,107 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/vmware/govmomi/vapi/internal"
+	"github.com/vmware/govmomi/vapi/rest"
+)
+
+// Health levels reported by the appliance health checks
+const (
+	// Green indicates the component is healthy
+	Green = "green"
+	// Yellow indicates the component is healthy, but may have some problems
+	Yellow = "yellow"
+	// Orange indicates the component is degraded and may have serious problems
+	Orange = "orange"
+	// Red indicates the component is unavailable or will stop functioning soon
+	Red = "red"
+	// Gray indicates no health data is available
+	Gray = "gray"
+)
+
+// Manager extends rest.Client, adding appliance health related methods.
+type Manager struct {
+	*rest.Client
+}
+
+// NewManager creates a new Manager instance with the given client.
+func NewManager(client *rest.Client) *Manager {
+	return &Manager{
+		Client: client,
+	}
+}
+
+// level returns the health level of the given component.
+func (c *Manager) level(ctx context.Context, component string) (string, error) {
+	url := internal.URL(c, internal.ApplianceHealthPath+"/"+component)
+	var res string
+	return res, c.Do(ctx, url.Request(ctx, http.MethodGet), &res)
+}
+
+// System returns the overall health of the appliance, the worst level of all its components.
+func (c *Manager) System(ctx context.Context) (string, error) {
+	return c.level(ctx, "system")
+}
+
+// SystemLastCheck returns the time the appliance health was last checked.
+func (c *Manager) SystemLastCheck(ctx context.Context) (time.Time, error) {
+	url := internal.URL(c, internal.ApplianceHealthPath+"/system/lastcheck")
+	var res time.Time
+	return res, c.Do(ctx, url.Request(ctx, http.MethodGet), &res)
+}
+
+// DatabaseStorage returns the health of the database storage.
+func (c *Manager) DatabaseStorage(ctx context.Context) (string, error) {
+	return c.level(ctx, "database-storage")
+}
+
+// Load returns the health of the CPU load.
+func (c *Manager) Load(ctx context.Context) (string, error) {
+	return c.level(ctx, "load")
+}
+
+// Memory returns the health of the memory usage.
+func (c *Manager) Memory(ctx context.Context) (string, error) {
+	return c.level(ctx, "mem")
+}
+
+// Swap returns the health of the swap usage.
+func (c *Manager) Swap(ctx context.Context) (string, error) {
+	return c.level(ctx, "swap")
+}
+
+// Storage returns the health of the storage usage.
+func (c *Manager) Storage(ctx context.Context) (string, error) {
+	return c.level(ctx, "storage")
+}
+
+// SoftwarePackages returns the health of the installed software packages,
+// which is degraded when security updates are available.
+func (c *Manager) SoftwarePackages(ctx context.Context) (string, error) {
+	return c.level(ctx, "software-packages")
+}
+
+// ApplMgmt returns the health of the appliance management service.
+func (c *Manager) ApplMgmt(ctx context.Context) (string, error) {
+	return c.level(ctx, "applmgmt")
+}
@@ -0,0 +1,70 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/appliance/health"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vim25"
+
+	_ "github.com/vmware/govmomi/vapi/simulator"
+)
+
+func TestHealth(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+		if err := c.Login(ctx, simulator.DefaultLogin); err != nil {
+			t.Fatal(err)
+		}
+
+		m := health.NewManager(c)
+
+		checks := map[string]func(context.Context) (string, error){
+			"system":            m.System,
+			"database-storage":  m.DatabaseStorage,
+			"load":              m.Load,
+			"mem":               m.Memory,
+			"swap":              m.Swap,
+			"storage":           m.Storage,
+			"software-packages": m.SoftwarePackages,
+			"applmgmt":          m.ApplMgmt,
+		}
+
+		for name, check := range checks {
+			level, err := check(ctx)
+			if err != nil {
+				t.Fatalf("%s: %s", name, err)
+			}
+			if level != health.Green {
+				t.Errorf("%s: level=%s", name, level)
+			}
+		}
+
+		last, err := m.SystemLastCheck(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if last.IsZero() || last.After(time.Now()) {
+			t.Errorf("lastcheck=%s", last)
+		}
+	})
+}
@@ -0,0 +1,113 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package monitoring
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/vmware/govmomi/vapi/internal"
+	"github.com/vmware/govmomi/vapi/rest"
+)
+
+// Interval of the returned data points
+const (
+	IntervalMinutes5  = "MINUTES5"
+	IntervalMinutes30 = "MINUTES30"
+	IntervalHours2    = "HOURS2"
+	IntervalHours6    = "HOURS6"
+	IntervalDay1      = "DAY1"
+)
+
+// Aggregation function applied to the data points within each interval
+const (
+	FunctionCount = "COUNT"
+	FunctionMax   = "MAX"
+	FunctionAvg   = "AVG"
+	FunctionMin   = "MIN"
+)
+
+// Manager extends rest.Client, adding appliance monitoring related methods.
+type Manager struct {
+	*rest.Client
+}
+
+// NewManager creates a new Manager instance with the given client.
+func NewManager(client *rest.Client) *Manager {
+	return &Manager{
+		Client: client,
+	}
+}
+
+// Item describes a monitored metric, such as "cpu.util" or "mem.usage".
+type Item struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Units       string `json:"units"`
+	Category    string `json:"category"`
+	Instance    string `json:"instance"`
+	Description string `json:"description"`
+}
+
+// Query specifies the metrics and time range of a monitoring data query.
+type Query struct {
+	Names     []string
+	Interval  string
+	Function  string
+	StartTime time.Time
+	EndTime   time.Time
+}
+
+// Data contains the data points of a single metric, one per Interval between StartTime and EndTime.
+// Data points for which no data was collected are empty strings.
+type Data struct {
+	Name      string    `json:"name"`
+	Interval  string    `json:"interval"`
+	Function  string    `json:"function"`
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+	Data      []string  `json:"data"`
+}
+
+// List returns all monitored items.
+func (c *Manager) List(ctx context.Context) ([]Item, error) {
+	url := internal.URL(c, internal.ApplianceMonitoringPath)
+	var res []Item
+	return res, c.Do(ctx, url.Request(ctx, http.MethodGet), &res)
+}
+
+// Get returns the monitored item with the given ID.
+func (c *Manager) Get(ctx context.Context, id string) (*Item, error) {
+	url := internal.URL(c, internal.ApplianceMonitoringPath).WithSubpath(id)
+	var res Item
+	return &res, c.Do(ctx, url.Request(ctx, http.MethodGet), &res)
+}
+
+// Query returns the data points of the given metrics.
+func (c *Manager) Query(ctx context.Context, q Query) ([]Data, error) {
+	url := internal.URL(c, internal.ApplianceMonitoringPath+"/query")
+	for _, name := range q.Names {
+		url = url.WithParameter("item.names", name)
+	}
+	url = url.WithParameter("item.interval", q.Interval).
+		WithParameter("item.function", q.Function).
+		WithParameter("item.start_time", q.StartTime.UTC().Format(time.RFC3339)).
+		WithParameter("item.end_time", q.EndTime.UTC().Format(time.RFC3339))
+	var res []Data
+	return res, c.Do(ctx, url.Request(ctx, http.MethodGet), &res)
+}
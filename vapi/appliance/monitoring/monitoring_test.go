@@ -0,0 +1,94 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package monitoring_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/appliance/monitoring"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vim25"
+
+	_ "github.com/vmware/govmomi/vapi/simulator"
+)
+
+func TestMonitoring(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+		if err := c.Login(ctx, simulator.DefaultLogin); err != nil {
+			t.Fatal(err)
+		}
+
+		m := monitoring.NewManager(c)
+
+		items, err := m.List(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(items) == 0 {
+			t.Fatal("no monitored items")
+		}
+
+		item, err := m.Get(ctx, "mem.usage")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if item.ID != "mem.usage" {
+			t.Errorf("item=%#v", item)
+		}
+
+		var nf *rest.NotFound
+		if _, err = m.Get(ctx, "enoent"); !errors.As(err, &nf) {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		end := time.Now().Truncate(time.Hour)
+		q := monitoring.Query{
+			Names:     []string{"cpu.util", "mem.util"},
+			Interval:  monitoring.IntervalMinutes30,
+			Function:  monitoring.FunctionAvg,
+			StartTime: end.Add(-2 * time.Hour),
+			EndTime:   end,
+		}
+
+		data, err := m.Query(ctx, q)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(data) != len(q.Names) {
+			t.Fatalf("data=%#v", data)
+		}
+		for i, d := range data {
+			if d.Name != q.Names[i] || d.Function != q.Function || len(d.Data) != 4 {
+				t.Errorf("data=%#v", d)
+			}
+			if !d.StartTime.Equal(q.StartTime) || !d.EndTime.Equal(q.EndTime) {
+				t.Errorf("start=%s, end=%s", d.StartTime, d.EndTime)
+			}
+		}
+
+		q.Names = []string{"enoent"}
+		var ia *rest.InvalidArgument
+		if _, err = m.Query(ctx, q); !errors.As(err, &ia) {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
@@ -0,0 +1,70 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networking
+
+import (
+	"context"
+	"net/http"
+)
+
+// DNS server modes
+const (
+	DNSModeDHCP     = "DHCP"
+	DNSModeIsStatic = "IS_STATIC"
+)
+
+// DNSServers is the DNS server configuration of the appliance.
+type DNSServers struct {
+	Mode    string   `json:"mode"`
+	Servers []string `json:"servers"`
+}
+
+// GetDNSServers returns the DNS server configuration.
+func (c *Manager) GetDNSServers(ctx context.Context) (*DNSServers, error) {
+	var res DNSServers
+	return &res, c.Do(ctx, c.resource("/dns/servers").Request(ctx, http.MethodGet), &res)
+}
+
+// SetDNSServers sets the DNS server configuration.
+func (c *Manager) SetDNSServers(ctx context.Context, config DNSServers) error {
+	spec := struct {
+		Config DNSServers `json:"config"`
+	}{config}
+	return c.Do(ctx, c.resource("/dns/servers").Request(ctx, http.MethodPut, spec), nil)
+}
+
+// AddDNSServer adds a DNS server to the static configuration.
+func (c *Manager) AddDNSServer(ctx context.Context, server string) error {
+	spec := struct {
+		Server string `json:"server"`
+	}{server}
+	return c.Do(ctx, c.resource("/dns/servers").Request(ctx, http.MethodPost, spec), nil)
+}
+
+// GetHostname returns the fully qualified domain name of the appliance.
+func (c *Manager) GetHostname(ctx context.Context) (string, error) {
+	var res string
+	return res, c.Do(ctx, c.resource("/dns/hostname").Request(ctx, http.MethodGet), &res)
+}
+
+// SetHostname sets the fully qualified domain name of the appliance.
+func (c *Manager) SetHostname(ctx context.Context, name string) error {
+	spec := struct {
+		Name string `json:"name"`
+	}{name}
+	return c.Do(ctx, c.resource("/dns/hostname").Request(ctx, http.MethodPut, spec), nil)
+}
@@ -0,0 +1,57 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networking
+
+import (
+	"context"
+	"net/http"
+)
+
+// Firewall rule policies
+const (
+	PolicyIgnore = "IGNORE"
+	PolicyAccept = "ACCEPT"
+	PolicyReject = "REJECT"
+	PolicyReturn = "RETURN"
+)
+
+// FirewallRule matches inbound traffic from the given source address and prefix.
+// Rules are evaluated in order, the first matching rule's Policy is applied.
+// If InterfaceName is empty, or "*", the rule applies to all interfaces.
+type FirewallRule struct {
+	Address       string `json:"address"`
+	Prefix        int    `json:"prefix"`
+	Policy        string `json:"policy"`
+	InterfaceName string `json:"interface_name,omitempty"`
+}
+
+// GetFirewallInbound returns the ordered list of inbound firewall rules.
+func (c *Manager) GetFirewallInbound(ctx context.Context) ([]FirewallRule, error) {
+	var res []FirewallRule
+	return res, c.Do(ctx, c.resource("/firewall/inbound").Request(ctx, http.MethodGet), &res)
+}
+
+// SetFirewallInbound replaces the inbound firewall rules.
+func (c *Manager) SetFirewallInbound(ctx context.Context, rules []FirewallRule) error {
+	if rules == nil {
+		rules = []FirewallRule{}
+	}
+	spec := struct {
+		Rules []FirewallRule `json:"rules"`
+	}{rules}
+	return c.Do(ctx, c.resource("/firewall/inbound").Request(ctx, http.MethodPut, spec), nil)
+}
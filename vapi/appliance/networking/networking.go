@@ -0,0 +1,102 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networking
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/vmware/govmomi/vapi/internal"
+	"github.com/vmware/govmomi/vapi/rest"
+)
+
+// Network interface status
+const (
+	StatusDown = "DOWN"
+	StatusUp   = "UP"
+)
+
+// IPv4 address modes
+const (
+	ModeDHCP         = "DHCP"
+	ModeStatic       = "STATIC"
+	ModeUnconfigured = "UNCONFIGURED"
+)
+
+// Manager extends rest.Client, adding appliance networking related methods.
+type Manager struct {
+	*rest.Client
+}
+
+// NewManager creates a new Manager instance with the given client.
+func NewManager(client *rest.Client) *Manager {
+	return &Manager{
+		Client: client,
+	}
+}
+
+// IPv4 is the IPv4 configuration of a network interface.
+type IPv4 struct {
+	Configurable   bool   `json:"configurable,omitempty"`
+	Mode           string `json:"mode"`
+	Address        string `json:"address,omitempty"`
+	Prefix         int    `json:"prefix,omitempty"`
+	DefaultGateway string `json:"default_gateway,omitempty"`
+}
+
+// Interface describes a network interface of the appliance.
+type Interface struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	MAC    string `json:"mac"`
+	IPv4   *IPv4  `json:"ipv4,omitempty"`
+}
+
+func (c *Manager) resource(path string) *internal.Resource {
+	return internal.URL(c, internal.ApplianceNetworkingPath+path)
+}
+
+// ListInterfaces returns the network interfaces of the appliance.
+func (c *Manager) ListInterfaces(ctx context.Context) ([]Interface, error) {
+	var res []Interface
+	return res, c.Do(ctx, c.resource("/interfaces").Request(ctx, http.MethodGet), &res)
+}
+
+// GetInterface returns the given network interface.
+func (c *Manager) GetInterface(ctx context.Context, name string) (*Interface, error) {
+	url := c.resource("/interfaces").WithSubpath(name)
+	var res Interface
+	return &res, c.Do(ctx, url.Request(ctx, http.MethodGet), &res)
+}
+
+// GetIPv4 returns the IPv4 configuration of the given network interface.
+func (c *Manager) GetIPv4(ctx context.Context, name string) (*IPv4, error) {
+	url := c.resource("/interfaces").WithSubpath(name).WithSubpath("ipv4")
+	var res IPv4
+	return &res, c.Do(ctx, url.Request(ctx, http.MethodGet), &res)
+}
+
+// SetIPv4 sets the IPv4 configuration of the given network interface.
+// The Configurable field is ignored.
+func (c *Manager) SetIPv4(ctx context.Context, name string, config IPv4) error {
+	url := c.resource("/interfaces").WithSubpath(name).WithSubpath("ipv4")
+	config.Configurable = false
+	spec := struct {
+		Config IPv4 `json:"config"`
+	}{config}
+	return c.Do(ctx, url.Request(ctx, http.MethodPut, spec), nil)
+}
@@ -0,0 +1,219 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networking_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/appliance/networking"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vim25"
+
+	_ "github.com/vmware/govmomi/vapi/simulator"
+)
+
+// jsonContentType fails the test if a PUT request is sent without the JSON Content-Type.
+func jsonContentType(t *testing.T) rest.Middleware {
+	return func(next rest.Handler) rest.Handler {
+		return func(ctx context.Context, req *http.Request, resBody interface{}) error {
+			if req.Method == http.MethodPut && req.Header.Get("Content-Type") != "application/json" {
+				t.Errorf("PUT %s: Content-Type=%q", req.URL.Path, req.Header.Get("Content-Type"))
+			}
+			return next(ctx, req, resBody)
+		}
+	}
+}
+
+func TestInterfaces(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc, rest.WithMiddleware(jsonContentType(t)))
+		if err := c.Login(ctx, simulator.DefaultLogin); err != nil {
+			t.Fatal(err)
+		}
+
+		m := networking.NewManager(c)
+
+		nics, err := m.ListInterfaces(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(nics) == 0 {
+			t.Fatal("no interfaces")
+		}
+		name := nics[0].Name
+
+		nic, err := m.GetInterface(ctx, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if nic.Status != networking.StatusUp || nic.IPv4 == nil {
+			t.Errorf("nic=%#v", nic)
+		}
+
+		config := networking.IPv4{Mode: networking.ModeStatic, Address: "10.0.1.20", Prefix: 23, DefaultGateway: "10.0.0.1"}
+		if err = m.SetIPv4(ctx, name, config); err != nil {
+			t.Fatal(err)
+		}
+		ipv4, err := m.GetIPv4(ctx, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ipv4.Address != config.Address || ipv4.Prefix != config.Prefix || !ipv4.Configurable {
+			t.Errorf("ipv4=%#v", ipv4)
+		}
+
+		var ia *rest.InvalidArgument
+		err = m.SetIPv4(ctx, name, networking.IPv4{Mode: networking.ModeStatic, Address: "10.0.1"})
+		if !errors.As(err, &ia) {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		var nf *rest.NotFound
+		if _, err = m.GetIPv4(ctx, "enoent"); !errors.As(err, &nf) {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
+
+func TestDNS(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc, rest.WithMiddleware(jsonContentType(t)))
+		if err := c.Login(ctx, simulator.DefaultLogin); err != nil {
+			t.Fatal(err)
+		}
+
+		m := networking.NewManager(c)
+
+		config := networking.DNSServers{Mode: networking.DNSModeIsStatic, Servers: []string{"10.0.0.53"}}
+		if err := m.SetDNSServers(ctx, config); err != nil {
+			t.Fatal(err)
+		}
+		if err := m.AddDNSServer(ctx, "10.0.1.53"); err != nil {
+			t.Fatal(err)
+		}
+		if err := m.AddDNSServer(ctx, "dns.example.com"); err == nil {
+			t.Error("expected error")
+		}
+
+		dns, err := m.GetDNSServers(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if dns.Mode != networking.DNSModeIsStatic || len(dns.Servers) != 2 || dns.Servers[1] != "10.0.1.53" {
+			t.Errorf("dns=%#v", dns)
+		}
+
+		if err = m.SetHostname(ctx, "vcenter.example.com"); err != nil {
+			t.Fatal(err)
+		}
+		name, err := m.GetHostname(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if name != "vcenter.example.com" {
+			t.Errorf("hostname=%s", name)
+		}
+	})
+}
+
+func TestProxy(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc, rest.WithMiddleware(jsonContentType(t)))
+		if err := c.Login(ctx, simulator.DefaultLogin); err != nil {
+			t.Fatal(err)
+		}
+
+		m := networking.NewManager(c)
+
+		config := networking.Proxy{Server: "http://proxy.example.com", Port: 3128, Username: "user", Password: "pass", Enabled: true}
+		if err := m.SetProxy(ctx, networking.ProxyHTTPS, config); err != nil {
+			t.Fatal(err)
+		}
+
+		proxy, err := m.GetProxy(ctx, networking.ProxyHTTPS)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if proxy.Server != config.Server || proxy.Port != config.Port || !proxy.Enabled || proxy.Password != "" {
+			t.Errorf("proxy=%#v", proxy)
+		}
+
+		proxies, err := m.ListProxies(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(proxies) != 3 || !proxies[networking.ProxyHTTPS].Enabled || proxies[networking.ProxyHTTP].Enabled {
+			t.Errorf("proxies=%#v", proxies)
+		}
+
+		if err = m.DeleteProxy(ctx, networking.ProxyHTTPS); err != nil {
+			t.Fatal(err)
+		}
+		if proxy, err = m.GetProxy(ctx, networking.ProxyHTTPS); err != nil {
+			t.Fatal(err)
+		}
+		if proxy.Enabled {
+			t.Errorf("proxy=%#v", proxy)
+		}
+	})
+}
+
+func TestFirewallInbound(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc, rest.WithMiddleware(jsonContentType(t)))
+		if err := c.Login(ctx, simulator.DefaultLogin); err != nil {
+			t.Fatal(err)
+		}
+
+		m := networking.NewManager(c)
+
+		rules := []networking.FirewallRule{
+			{Address: "10.0.0.0", Prefix: 8, Policy: networking.PolicyAccept},
+			{Address: "0.0.0.0", Prefix: 0, Policy: networking.PolicyReject, InterfaceName: "nic0"},
+		}
+		if err := m.SetFirewallInbound(ctx, rules); err != nil {
+			t.Fatal(err)
+		}
+
+		res, err := m.GetFirewallInbound(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(res) != len(rules) || res[0] != rules[0] || res[1] != rules[1] {
+			t.Errorf("rules=%#v", res)
+		}
+
+		err = m.SetFirewallInbound(ctx, []networking.FirewallRule{{Address: "10.0.0.0", Prefix: 8, Policy: "DROP"}})
+		if err == nil {
+			t.Error("expected error")
+		}
+
+		if err = m.SetFirewallInbound(ctx, nil); err != nil {
+			t.Fatal(err)
+		}
+		if res, err = m.GetFirewallInbound(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if len(res) != 0 {
+			t.Errorf("rules=%#v", res)
+		}
+	})
+}
@@ -0,0 +1,74 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networking
+
+import (
+	"context"
+	"net/http"
+)
+
+// Proxy protocols
+const (
+	ProxyHTTP  = "http"
+	ProxyHTTPS = "https"
+	ProxyFTP   = "ftp"
+)
+
+// Proxy is the proxy server configuration for a protocol.
+type Proxy struct {
+	Server   string `json:"server"`
+	Port     int    `json:"port"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	Enabled  bool   `json:"enabled"`
+}
+
+// ListProxies returns the proxy configuration of all protocols, keyed by protocol.
+func (c *Manager) ListProxies(ctx context.Context) (map[string]Proxy, error) {
+	var res []struct {
+		Key   string `json:"key"`
+		Value Proxy  `json:"value"`
+	}
+	if err := c.Do(ctx, c.resource("/proxy").Request(ctx, http.MethodGet), &res); err != nil {
+		return nil, err
+	}
+
+	proxies := make(map[string]Proxy, len(res))
+	for _, p := range res {
+		proxies[p.Key] = p.Value
+	}
+	return proxies, nil
+}
+
+// GetProxy returns the proxy configuration of the given protocol.
+func (c *Manager) GetProxy(ctx context.Context, protocol string) (*Proxy, error) {
+	var res Proxy
+	return &res, c.Do(ctx, c.resource("/proxy").WithSubpath(protocol).Request(ctx, http.MethodGet), &res)
+}
+
+// SetProxy sets the proxy configuration of the given protocol.
+func (c *Manager) SetProxy(ctx context.Context, protocol string, config Proxy) error {
+	spec := struct {
+		Config Proxy `json:"config"`
+	}{config}
+	return c.Do(ctx, c.resource("/proxy").WithSubpath(protocol).Request(ctx, http.MethodPut, spec), nil)
+}
+
+// DeleteProxy deletes the proxy configuration of the given protocol.
+func (c *Manager) DeleteProxy(ctx context.Context, protocol string) error {
+	return c.Do(ctx, c.resource("/proxy").WithSubpath(protocol).Request(ctx, http.MethodDelete), nil)
+}
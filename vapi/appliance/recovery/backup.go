@@ -0,0 +1,99 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recovery
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/vmware/govmomi/vapi/internal"
+	"github.com/vmware/govmomi/vapi/rest"
+)
+
+// BackupPart is an optional set of data that can be included in a backup, in addition to the common configuration.
+type BackupPart struct {
+	ID                string                  `json:"id"`
+	Name              rest.LocalizableMessage `json:"name"`
+	Description       rest.LocalizableMessage `json:"description"`
+	SelectedByDefault bool                    `json:"selected_by_default"`
+}
+
+// BackupRequest specifies the parts to back up and the backup location.
+type BackupRequest struct {
+	Parts            []string `json:"parts"`
+	BackupPassword   string   `json:"backup_password,omitempty"`
+	LocationType     string   `json:"location_type"`
+	Location         string   `json:"location"`
+	LocationUser     string   `json:"location_user,omitempty"`
+	LocationPassword string   `json:"location_password,omitempty"`
+	Comment          string   `json:"comment,omitempty"`
+}
+
+// BackupJobStatus is the status of a backup job.
+type BackupJobStatus struct {
+	ID        string                    `json:"id"`
+	State     string                    `json:"state"`
+	Messages  []rest.LocalizableMessage `json:"messages"`
+	Progress  int                       `json:"progress"`
+	StartTime time.Time                 `json:"start_time"`
+	EndTime   *time.Time                `json:"end_time,omitempty"`
+}
+
+// ListBackupParts returns the parts that can be included in a backup.
+func (c *Manager) ListBackupParts(ctx context.Context) ([]BackupPart, error) {
+	url := internal.URL(c, internal.ApplianceBackupPath+"/parts")
+	var res []BackupPart
+	return res, c.Do(ctx, url.Request(ctx, http.MethodGet), &res)
+}
+
+// BackupPartSize returns the estimated size in MB of the given backup part.
+func (c *Manager) BackupPartSize(ctx context.Context, id string) (int64, error) {
+	url := internal.URL(c, internal.ApplianceBackupPath+"/parts").WithSubpath(id)
+	var res int64
+	return res, c.Do(ctx, url.Request(ctx, http.MethodGet), &res)
+}
+
+// CreateBackupJob starts a backup job, returning its initial status.
+func (c *Manager) CreateBackupJob(ctx context.Context, req BackupRequest) (*BackupJobStatus, error) {
+	url := internal.URL(c, internal.ApplianceBackupPath+"/job")
+	spec := struct {
+		Piece BackupRequest `json:"piece"`
+	}{req}
+	var res BackupJobStatus
+	return &res, c.Do(ctx, url.Request(ctx, http.MethodPost, spec), &res)
+}
+
+// ListBackupJobs returns the IDs of all backup jobs.
+func (c *Manager) ListBackupJobs(ctx context.Context) ([]string, error) {
+	url := internal.URL(c, internal.ApplianceBackupPath+"/job")
+	var res []string
+	return res, c.Do(ctx, url.Request(ctx, http.MethodGet), &res)
+}
+
+// GetBackupJob returns the status of the given backup job.
+func (c *Manager) GetBackupJob(ctx context.Context, id string) (*BackupJobStatus, error) {
+	url := internal.URL(c, internal.ApplianceBackupPath+"/job").WithSubpath(id)
+	var res BackupJobStatus
+	return &res, c.Do(ctx, url.Request(ctx, http.MethodGet), &res)
+}
+
+// CancelBackupJob cancels the given backup job, which must be in the StateInProgress state.
+func (c *Manager) CancelBackupJob(ctx context.Context, id string) error {
+	url := internal.URL(c, internal.ApplianceBackupPath+"/job").WithSubpath(id).WithSubpath("cancel")
+	return c.Do(ctx, url.Request(ctx, http.MethodPost), nil)
+}
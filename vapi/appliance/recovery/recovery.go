@@ -0,0 +1,53 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recovery
+
+import (
+	"github.com/vmware/govmomi/vapi/rest"
+)
+
+// Backup location types
+const (
+	LocationFTP   = "FTP"
+	LocationFTPS  = "FTPS"
+	LocationHTTP  = "HTTP"
+	LocationHTTPS = "HTTPS"
+	LocationSCP   = "SCP"
+	LocationSFTP  = "SFTP"
+	LocationNFS   = "NFS"
+	LocationSMB   = "SMB"
+)
+
+// Backup and restore job states
+const (
+	StateNone       = "NONE"
+	StateInProgress = "INPROGRESS"
+	StateSucceeded  = "SUCCEEDED"
+	StateFailed     = "FAILED"
+)
+
+// Manager extends rest.Client, adding appliance backup and restore related methods.
+type Manager struct {
+	*rest.Client
+}
+
+// NewManager creates a new Manager instance with the given client.
+func NewManager(client *rest.Client) *Manager {
+	return &Manager{
+		Client: client,
+	}
+}
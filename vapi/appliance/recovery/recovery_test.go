@@ -0,0 +1,158 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recovery_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/appliance/recovery"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vim25"
+
+	_ "github.com/vmware/govmomi/vapi/simulator"
+)
+
+func TestBackupJob(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+		if err := c.Login(ctx, simulator.DefaultLogin); err != nil {
+			t.Fatal(err)
+		}
+
+		m := recovery.NewManager(c)
+
+		parts, err := m.ListBackupParts(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var names []string
+		for _, part := range parts {
+			size, err := m.BackupPartSize(ctx, part.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if size <= 0 {
+				t.Errorf("%s size=%d", part.ID, size)
+			}
+			names = append(names, part.ID)
+		}
+
+		req := recovery.BackupRequest{
+			Parts:            names,
+			LocationType:     recovery.LocationSFTP,
+			Location:         "sftp://backup.example.com/vcsa",
+			LocationUser:     "backup",
+			LocationPassword: "secret",
+		}
+
+		var ia *rest.InvalidArgument
+		_, err = m.CreateBackupJob(ctx, recovery.BackupRequest{LocationType: "TAPE", Location: req.Location})
+		if !errors.As(err, &ia) {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		job, err := m.CreateBackupJob(ctx, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if job.State != recovery.StateInProgress {
+			t.Errorf("state=%s", job.State)
+		}
+
+		if _, err = m.CreateBackupJob(ctx, req); err == nil {
+			t.Error("expected error creating a job while another is in progress")
+		}
+
+		for job.State == recovery.StateInProgress {
+			if job, err = m.GetBackupJob(ctx, job.ID); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if job.State != recovery.StateSucceeded || job.Progress != 100 || job.EndTime == nil {
+			t.Errorf("job=%#v", job)
+		}
+
+		if err = m.CancelBackupJob(ctx, job.ID); err == nil {
+			t.Error("expected error canceling a completed job")
+		}
+
+		job, err = m.CreateBackupJob(ctx, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = m.CancelBackupJob(ctx, job.ID); err != nil {
+			t.Fatal(err)
+		}
+		if job, err = m.GetBackupJob(ctx, job.ID); err != nil {
+			t.Fatal(err)
+		}
+		if job.State != recovery.StateFailed {
+			t.Errorf("state=%s", job.State)
+		}
+
+		ids, err := m.ListBackupJobs(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(ids) != 2 {
+			t.Errorf("ids=%v", ids)
+		}
+	})
+}
+
+func TestRestoreJob(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+		if err := c.Login(ctx, simulator.DefaultLogin); err != nil {
+			t.Fatal(err)
+		}
+
+		m := recovery.NewManager(c)
+
+		job, err := m.GetRestoreJob(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if job.State != recovery.StateNone {
+			t.Errorf("state=%s", job.State)
+		}
+
+		job, err = m.CreateRestoreJob(ctx, recovery.RestoreRequest{
+			LocationType: recovery.LocationHTTPS,
+			Location:     "https://backup.example.com/vcsa/20190412-153005",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for job.State == recovery.StateInProgress {
+			if job, err = m.GetRestoreJob(ctx); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if job.State != recovery.StateSucceeded {
+			t.Errorf("job=%#v", job)
+		}
+
+		if err = m.CancelRestoreJob(ctx); err == nil {
+			t.Error("expected error canceling a completed job")
+		}
+	})
+}
@@ -0,0 +1,68 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recovery
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/vmware/govmomi/vapi/internal"
+	"github.com/vmware/govmomi/vapi/rest"
+)
+
+// RestoreRequest specifies the location of the backup to restore.
+type RestoreRequest struct {
+	BackupPassword       string `json:"backup_password,omitempty"`
+	LocationType         string `json:"location_type"`
+	Location             string `json:"location"`
+	LocationUser         string `json:"location_user,omitempty"`
+	LocationPassword     string `json:"location_password,omitempty"`
+	SSOAdminUserName     string `json:"sso_admin_user_name,omitempty"`
+	SSOAdminUserPassword string `json:"sso_admin_user_password,omitempty"`
+	IgnoreWarnings       bool   `json:"ignore_warnings,omitempty"`
+}
+
+// RestoreJobStatus is the status of the restore job.
+type RestoreJobStatus struct {
+	State    string                    `json:"state"`
+	Messages []rest.LocalizableMessage `json:"messages"`
+	Progress int                       `json:"progress"`
+}
+
+// CreateRestoreJob starts restoring the appliance from a backup, returning the initial job status.
+// A restore is only possible on a newly deployed appliance that has not yet been configured.
+func (c *Manager) CreateRestoreJob(ctx context.Context, req RestoreRequest) (*RestoreJobStatus, error) {
+	url := internal.URL(c, internal.ApplianceRestorePath+"/job")
+	spec := struct {
+		Piece RestoreRequest `json:"piece"`
+	}{req}
+	var res RestoreJobStatus
+	return &res, c.Do(ctx, url.Request(ctx, http.MethodPost, spec), &res)
+}
+
+// GetRestoreJob returns the status of the restore job.
+func (c *Manager) GetRestoreJob(ctx context.Context) (*RestoreJobStatus, error) {
+	url := internal.URL(c, internal.ApplianceRestorePath+"/job")
+	var res RestoreJobStatus
+	return &res, c.Do(ctx, url.Request(ctx, http.MethodGet), &res)
+}
+
+// CancelRestoreJob cancels the restore job.
+func (c *Manager) CancelRestoreJob(ctx context.Context) error {
+	url := internal.URL(c, internal.ApplianceRestorePath+"/job/cancel")
+	return c.Do(ctx, url.Request(ctx, http.MethodPost), nil)
+}
@@ -0,0 +1,94 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/vmware/govmomi/vapi/internal"
+	"github.com/vmware/govmomi/vapi/rest"
+)
+
+// Service states
+const (
+	StateStarting = "STARTING"
+	StateStopping = "STOPPING"
+	StateStarted  = "STARTED"
+	StateStopped  = "STOPPED"
+)
+
+// Manager extends rest.Client, adding appliance service related methods.
+type Manager struct {
+	*rest.Client
+}
+
+// NewManager creates a new Manager instance with the given client.
+func NewManager(client *rest.Client) *Manager {
+	return &Manager{
+		Client: client,
+	}
+}
+
+// Info describes the state of an appliance service.
+type Info struct {
+	Description string `json:"description"`
+	State       string `json:"state"`
+}
+
+func (c *Manager) resource(name string) *internal.Resource {
+	return internal.URL(c, internal.ApplianceServicesPath).WithSubpath(name)
+}
+
+// List returns the state of all appliance services, keyed by service name.
+func (c *Manager) List(ctx context.Context) (map[string]Info, error) {
+	url := internal.URL(c, internal.ApplianceServicesPath)
+	var res []struct {
+		Key   string `json:"key"`
+		Value Info   `json:"value"`
+	}
+	if err := c.Do(ctx, url.Request(ctx, http.MethodGet), &res); err != nil {
+		return nil, err
+	}
+
+	services := make(map[string]Info, len(res))
+	for _, s := range res {
+		services[s.Key] = s.Value
+	}
+	return services, nil
+}
+
+// Get returns the state of the given service.
+func (c *Manager) Get(ctx context.Context, name string) (*Info, error) {
+	var res Info
+	return &res, c.Do(ctx, c.resource(name).Request(ctx, http.MethodGet), &res)
+}
+
+// Start starts the given service.
+func (c *Manager) Start(ctx context.Context, name string) error {
+	return c.Do(ctx, c.resource(name).WithSubpath("start").Request(ctx, http.MethodPost), nil)
+}
+
+// Stop stops the given service.
+func (c *Manager) Stop(ctx context.Context, name string) error {
+	return c.Do(ctx, c.resource(name).WithSubpath("stop").Request(ctx, http.MethodPost), nil)
+}
+
+// Restart restarts the given service.
+func (c *Manager) Restart(ctx context.Context, name string) error {
+	return c.Do(ctx, c.resource(name).WithSubpath("restart").Request(ctx, http.MethodPost), nil)
+}
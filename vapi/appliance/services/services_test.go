@@ -0,0 +1,80 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/appliance/services"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vim25"
+
+	_ "github.com/vmware/govmomi/vapi/simulator"
+)
+
+func TestServices(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+		if err := c.Login(ctx, simulator.DefaultLogin); err != nil {
+			t.Fatal(err)
+		}
+
+		m := services.NewManager(c)
+
+		list, err := m.List(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if list["vpxd"].State != services.StateStarted {
+			t.Errorf("services=%#v", list)
+		}
+
+		state := func(name string) string {
+			info, err := m.Get(ctx, name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return info.State
+		}
+
+		if err = m.Stop(ctx, "content-library"); err != nil {
+			t.Fatal(err)
+		}
+		if s := state("content-library"); s != services.StateStopped {
+			t.Errorf("state=%s", s)
+		}
+
+		if err = m.Start(ctx, "content-library"); err != nil {
+			t.Fatal(err)
+		}
+		if s := state("content-library"); s != services.StateStarted {
+			t.Errorf("state=%s", s)
+		}
+
+		if err = m.Restart(ctx, "vpxd"); err != nil {
+			t.Fatal(err)
+		}
+
+		var nf *rest.NotFound
+		if err = m.Restart(ctx, "enoent"); !errors.As(err, &nf) {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
@@ -0,0 +1,85 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shutdown
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/vmware/govmomi/vapi/internal"
+	"github.com/vmware/govmomi/vapi/rest"
+)
+
+// Shutdown actions
+const (
+	ActionPowerOff = "poweroff"
+	ActionReboot   = "reboot"
+)
+
+// Manager extends rest.Client, adding appliance shutdown related methods.
+type Manager struct {
+	*rest.Client
+}
+
+// NewManager creates a new Manager instance with the given client.
+func NewManager(client *rest.Client) *Manager {
+	return &Manager{
+		Client: client,
+	}
+}
+
+// Config describes the pending shutdown action, the Action is empty if no shutdown is pending.
+type Config struct {
+	ShutdownTime *time.Time `json:"shutdown_time,omitempty"`
+	Action       string     `json:"action"`
+	Reason       string     `json:"reason"`
+}
+
+// Get returns the pending shutdown action.
+func (c *Manager) Get(ctx context.Context) (*Config, error) {
+	url := internal.URL(c, internal.ApplianceShutdownPath)
+	var res Config
+	return &res, c.Do(ctx, url.Request(ctx, http.MethodGet), &res)
+}
+
+func (c *Manager) shutdown(ctx context.Context, action string, reason string, delay time.Duration) error {
+	url := internal.URL(c, internal.ApplianceShutdownPath).WithSubpath(action)
+	spec := struct {
+		Delay  int    `json:"delay"`
+		Reason string `json:"reason"`
+	}{int(delay / time.Minute), reason}
+	return c.Do(ctx, url.Request(ctx, http.MethodPost, spec), nil)
+}
+
+// PowerOff powers off the appliance after the given delay, which is rounded down to whole minutes.
+// The reason is required and is logged and shown to users until the appliance powers off.
+func (c *Manager) PowerOff(ctx context.Context, reason string, delay time.Duration) error {
+	return c.shutdown(ctx, ActionPowerOff, reason, delay)
+}
+
+// Reboot reboots the appliance after the given delay, which is rounded down to whole minutes.
+// The reason is required and is logged and shown to users until the appliance reboots.
+func (c *Manager) Reboot(ctx context.Context, reason string, delay time.Duration) error {
+	return c.shutdown(ctx, ActionReboot, reason, delay)
+}
+
+// Cancel cancels the pending shutdown action.
+func (c *Manager) Cancel(ctx context.Context) error {
+	url := internal.URL(c, internal.ApplianceShutdownPath).WithSubpath("cancel")
+	return c.Do(ctx, url.Request(ctx, http.MethodPost), nil)
+}
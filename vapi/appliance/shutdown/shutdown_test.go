@@ -0,0 +1,86 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shutdown_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/appliance/shutdown"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vim25"
+
+	_ "github.com/vmware/govmomi/vapi/simulator"
+)
+
+func TestShutdown(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+		if err := c.Login(ctx, simulator.DefaultLogin); err != nil {
+			t.Fatal(err)
+		}
+
+		m := shutdown.NewManager(c)
+
+		pending, err := m.Get(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pending.Action != "" {
+			t.Errorf("pending=%#v", pending)
+		}
+
+		if err = m.Reboot(ctx, "", time.Minute); err == nil {
+			t.Error("expected error without a reason")
+		}
+
+		if err = m.Reboot(ctx, "apply patch", 10*time.Minute); err != nil {
+			t.Fatal(err)
+		}
+		if pending, err = m.Get(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if pending.Action != shutdown.ActionReboot || pending.Reason != "apply patch" || pending.ShutdownTime == nil {
+			t.Fatalf("pending=%#v", pending)
+		}
+		if d := time.Until(*pending.ShutdownTime); d <= 9*time.Minute || d > 10*time.Minute {
+			t.Errorf("shutdown in %s", d)
+		}
+
+		if err = m.Cancel(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if pending, err = m.Get(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if pending.Action != "" {
+			t.Errorf("pending=%#v", pending)
+		}
+
+		if err = m.PowerOff(ctx, "maintenance", time.Hour); err != nil {
+			t.Fatal(err)
+		}
+		if pending, err = m.Get(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if pending.Action != shutdown.ActionPowerOff {
+			t.Errorf("pending=%#v", pending)
+		}
+	})
+}
@@ -0,0 +1,69 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package update
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/vmware/govmomi/vapi/internal"
+)
+
+// Time is the day and time of a scheduled check for updates.
+// Day is one of MONDAY through SUNDAY or EVERYDAY.
+type Time struct {
+	Day    string `json:"day"`
+	Hour   int    `json:"hour"`
+	Minute int    `json:"minute"`
+}
+
+// Policy is the appliance update policy.
+type Policy struct {
+	CustomURL     string `json:"custom_URL,omitempty"`
+	DefaultURL    string `json:"default_URL"`
+	Username      string `json:"username,omitempty"`
+	CheckSchedule []Time `json:"check_schedule"`
+	AutoStage     bool   `json:"auto_stage"`
+	AutoUpdate    bool   `json:"auto_update"`
+	ManualControl bool   `json:"manual_control"`
+}
+
+// PolicyConfig specifies the appliance update policy.
+// Updates are retrieved from the DefaultURL when CustomURL is empty.
+type PolicyConfig struct {
+	CustomURL     string `json:"custom_URL,omitempty"`
+	Username      string `json:"username,omitempty"`
+	Password      string `json:"password,omitempty"`
+	CheckSchedule []Time `json:"check_schedule"`
+	AutoStage     bool   `json:"auto_stage"`
+}
+
+// GetPolicy returns the appliance update policy.
+func (c *Manager) GetPolicy(ctx context.Context) (*Policy, error) {
+	url := internal.URL(c, internal.ApplianceUpdatePath+"/policy")
+	var res Policy
+	return &res, c.Do(ctx, url.Request(ctx, http.MethodGet), &res)
+}
+
+// SetPolicy sets the appliance update policy.
+func (c *Manager) SetPolicy(ctx context.Context, config PolicyConfig) error {
+	url := internal.URL(c, internal.ApplianceUpdatePath+"/policy")
+	spec := struct {
+		Config PolicyConfig `json:"config"`
+	}{config}
+	return c.Do(ctx, url.Request(ctx, http.MethodPut, spec), nil)
+}
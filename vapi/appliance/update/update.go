@@ -0,0 +1,240 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package update
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/vmware/govmomi/vapi/internal"
+	"github.com/vmware/govmomi/vapi/rest"
+)
+
+// Appliance update states
+const (
+	StateUpToDate           = "UP_TO_DATE"
+	StateUpdatesPending     = "UPDATES_PENDING"
+	StateStageInProgress    = "STAGE_IN_PROGRESS"
+	StateInstallInProgress  = "INSTALL_IN_PROGRESS"
+	StateInstallFailed      = "INSTALL_FAILED"
+	StateRollbackInProgress = "ROLLBACK_IN_PROGRESS"
+)
+
+// Pending update sources
+const (
+	SourceLatest         = "LATEST"
+	SourceLocal          = "LOCAL"
+	SourceLocalAndOnline = "LOCAL_AND_ONLINE"
+)
+
+// Question types
+const (
+	QuestionPlainText = "PLAIN_TEXT"
+	QuestionBoolean   = "BOOLEAN"
+	QuestionPassword  = "PASSWORD"
+)
+
+// Manager extends rest.Client, adding appliance update related methods.
+type Manager struct {
+	*rest.Client
+}
+
+// NewManager creates a new Manager instance with the given client.
+func NewManager(client *rest.Client) *Manager {
+	return &Manager{
+		Client: client,
+	}
+}
+
+// Progress of an update task
+type Progress struct {
+	Total     int64                   `json:"total"`
+	Completed int64                   `json:"completed"`
+	Message   rest.LocalizableMessage `json:"message"`
+}
+
+// Task is the status of the current or last update operation.
+type Task struct {
+	Description rest.LocalizableMessage `json:"description"`
+	Operation   string                  `json:"operation"`
+	Status      string                  `json:"status"`
+	Progress    *Progress               `json:"progress,omitempty"`
+	StartTime   *time.Time              `json:"start_time,omitempty"`
+	EndTime     *time.Time              `json:"end_time,omitempty"`
+}
+
+// Info is the update state of the appliance.
+type Info struct {
+	State           string     `json:"state"`
+	Task            *Task      `json:"task,omitempty"`
+	Version         string     `json:"version"`
+	LatestQueryTime *time.Time `json:"latest_query_time,omitempty"`
+}
+
+// Summary describes a pending update.
+type Summary struct {
+	Version        string                  `json:"version"`
+	Name           rest.LocalizableMessage `json:"name"`
+	Description    rest.LocalizableMessage `json:"description"`
+	Priority       string                  `json:"priority"`
+	Severity       string                  `json:"severity"`
+	UpdateType     string                  `json:"update_type"`
+	ReleaseDate    time.Time               `json:"release_date"`
+	RebootRequired bool                    `json:"reboot_required"`
+	Size           int64                   `json:"size"`
+}
+
+// PendingInfo describes a pending update in detail.
+type PendingInfo struct {
+	Summary
+	ServicesWillBeStopped []string                  `json:"services_will_be_stopped"`
+	EULAs                 []rest.LocalizableMessage `json:"eulas"`
+}
+
+// Question is user input required to install an update.
+// The answer is passed to Install, Validate or StageAndInstall in the user data, keyed by DataItem.
+type Question struct {
+	DataItem      string                  `json:"data_item"`
+	Text          rest.LocalizableMessage `json:"text"`
+	Description   rest.LocalizableMessage `json:"description"`
+	Type          string                  `json:"type"`
+	AllowedValues []string                `json:"allowed_values,omitempty"`
+	Regexp        string                  `json:"regexp,omitempty"`
+	DefaultAnswer string                  `json:"default_answer,omitempty"`
+}
+
+// Notification is an issue found by a precheck or validation.
+type Notification struct {
+	ID         string                   `json:"id"`
+	Message    rest.LocalizableMessage  `json:"message"`
+	Resolution *rest.LocalizableMessage `json:"resolution,omitempty"`
+}
+
+// Notifications groups issues by severity. Installation is blocked by any Errors.
+type Notifications struct {
+	Info     []Notification `json:"info,omitempty"`
+	Warnings []Notification `json:"warnings,omitempty"`
+	Errors   []Notification `json:"errors,omitempty"`
+}
+
+// PrecheckResult is the result of an update precheck.
+type PrecheckResult struct {
+	CheckTime               time.Time      `json:"check_time"`
+	EstimatedTimeToInstall  int64          `json:"estimated_time_to_install,omitempty"`
+	EstimatedTimeToRollback int64          `json:"estimated_time_to_rollback,omitempty"`
+	RebootRequired          bool           `json:"reboot_required"`
+	Issues                  *Notifications `json:"issues,omitempty"`
+	Questions               []Question     `json:"questions"`
+}
+
+// StagedInfo describes the staged update.
+type StagedInfo struct {
+	Summary
+	StagingComplete bool `json:"staging_complete"`
+}
+
+// userData is the /rest wire format of the update user data map
+type userData struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+func userDataSpec(data map[string]string) interface{} {
+	spec := struct {
+		UserData []userData `json:"user_data"`
+	}{[]userData{}}
+	for k, v := range data {
+		spec.UserData = append(spec.UserData, userData{k, v})
+	}
+	return spec
+}
+
+func (c *Manager) pending(version, action string) *internal.Resource {
+	return internal.URL(c, internal.ApplianceUpdatePath+"/pending").WithSubpath(version).WithParameter("action", action)
+}
+
+// Get returns the update state of the appliance.
+func (c *Manager) Get(ctx context.Context) (*Info, error) {
+	url := internal.URL(c, internal.ApplianceUpdatePath)
+	var res Info
+	return &res, c.Do(ctx, url.Request(ctx, http.MethodGet), &res)
+}
+
+// Cancel cancels the update operation in progress.
+func (c *Manager) Cancel(ctx context.Context) error {
+	url := internal.URL(c, internal.ApplianceUpdatePath).WithParameter("action", "cancel")
+	return c.Do(ctx, url.Request(ctx, http.MethodPost), nil)
+}
+
+// ListPending checks the given source for updates that can be applied to the appliance.
+// The url is optional, defaulting to the update policy's URL.
+func (c *Manager) ListPending(ctx context.Context, source string, url string) ([]Summary, error) {
+	u := internal.URL(c, internal.ApplianceUpdatePath+"/pending").WithParameter("source_type", source)
+	if url != "" {
+		u = u.WithParameter("url", url)
+	}
+	var res []Summary
+	return res, c.Do(ctx, u.Request(ctx, http.MethodGet), &res)
+}
+
+// GetPending returns the details of the given pending update.
+func (c *Manager) GetPending(ctx context.Context, version string) (*PendingInfo, error) {
+	url := internal.URL(c, internal.ApplianceUpdatePath+"/pending").WithSubpath(version)
+	var res PendingInfo
+	return &res, c.Do(ctx, url.Request(ctx, http.MethodGet), &res)
+}
+
+// Precheck runs the update prechecks, returning any issues and the Questions that must be answered to install the update.
+func (c *Manager) Precheck(ctx context.Context, version string) (*PrecheckResult, error) {
+	var res PrecheckResult
+	return &res, c.Do(ctx, c.pending(version, "precheck").Request(ctx, http.MethodPost), &res)
+}
+
+// Validate checks the given user data answers the Questions required to install the update.
+func (c *Manager) Validate(ctx context.Context, version string, data map[string]string) (*Notifications, error) {
+	var res Notifications
+	return &res, c.Do(ctx, c.pending(version, "validate").Request(ctx, http.MethodPost, userDataSpec(data)), &res)
+}
+
+// Stage downloads the update, such that it can be installed later.
+func (c *Manager) Stage(ctx context.Context, version string) error {
+	return c.Do(ctx, c.pending(version, "stage").Request(ctx, http.MethodPost), nil)
+}
+
+// Install installs the staged update, using the given user data to answer the precheck Questions.
+func (c *Manager) Install(ctx context.Context, version string, data map[string]string) error {
+	return c.Do(ctx, c.pending(version, "install").Request(ctx, http.MethodPost, userDataSpec(data)), nil)
+}
+
+// StageAndInstall stages and then installs the update, using the given user data to answer the precheck Questions.
+func (c *Manager) StageAndInstall(ctx context.Context, version string, data map[string]string) error {
+	return c.Do(ctx, c.pending(version, "stage-and-install").Request(ctx, http.MethodPost, userDataSpec(data)), nil)
+}
+
+// GetStaged returns the staged update.
+func (c *Manager) GetStaged(ctx context.Context) (*StagedInfo, error) {
+	url := internal.URL(c, internal.ApplianceUpdatePath+"/staged")
+	var res StagedInfo
+	return &res, c.Do(ctx, url.Request(ctx, http.MethodGet), &res)
+}
+
+// DeleteStaged deletes the staged update.
+func (c *Manager) DeleteStaged(ctx context.Context) error {
+	url := internal.URL(c, internal.ApplianceUpdatePath+"/staged")
+	return c.Do(ctx, url.Request(ctx, http.MethodDelete), nil)
+}
@@ -0,0 +1,174 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package update_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/appliance/update"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vim25"
+
+	_ "github.com/vmware/govmomi/vapi/simulator"
+)
+
+// jsonContentType fails the test if a PUT request is sent without the JSON Content-Type.
+func jsonContentType(t *testing.T) rest.Middleware {
+	return func(next rest.Handler) rest.Handler {
+		return func(ctx context.Context, req *http.Request, resBody interface{}) error {
+			if req.Method == http.MethodPut && req.Header.Get("Content-Type") != "application/json" {
+				t.Errorf("PUT %s: Content-Type=%q", req.URL.Path, req.Header.Get("Content-Type"))
+			}
+			return next(ctx, req, resBody)
+		}
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc, rest.WithMiddleware(jsonContentType(t)))
+		if err := c.Login(ctx, simulator.DefaultLogin); err != nil {
+			t.Fatal(err)
+		}
+
+		m := update.NewManager(c)
+
+		pending, err := m.ListPending(ctx, update.SourceLocalAndOnline, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(pending) != 1 {
+			t.Fatalf("pending=%#v", pending)
+		}
+		version := pending[0].Version
+
+		info, err := m.Get(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.State != update.StateUpdatesPending || info.LatestQueryTime == nil {
+			t.Errorf("info=%#v", info)
+		}
+
+		details, err := m.GetPending(ctx, version)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(details.ServicesWillBeStopped) == 0 {
+			t.Errorf("details=%#v", details)
+		}
+
+		check, err := m.Precheck(ctx, version)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(check.Questions) == 0 {
+			t.Fatal("no questions")
+		}
+
+		// installing requires staging and answering the precheck questions
+		if err = m.Install(ctx, version, nil); err == nil {
+			t.Error("expected error")
+		}
+
+		answers := make(map[string]string)
+		for _, q := range check.Questions {
+			answers[q.DataItem] = "secret"
+		}
+
+		issues, err := m.Validate(ctx, version, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(issues.Errors) != len(check.Questions) {
+			t.Errorf("issues=%#v", issues)
+		}
+		if issues, err = m.Validate(ctx, version, answers); err != nil {
+			t.Fatal(err)
+		}
+		if len(issues.Errors) != 0 {
+			t.Errorf("issues=%#v", issues)
+		}
+
+		if err = m.Install(ctx, version, answers); err == nil {
+			t.Error("expected error installing an update that is not staged")
+		}
+
+		if err = m.Stage(ctx, version); err != nil {
+			t.Fatal(err)
+		}
+		staged, err := m.GetStaged(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if staged.Version != version || !staged.StagingComplete {
+			t.Errorf("staged=%#v", staged)
+		}
+
+		if err = m.Install(ctx, version, answers); err != nil {
+			t.Fatal(err)
+		}
+
+		info, err = m.Get(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.State != update.StateUpToDate || info.Version != version || info.Task == nil {
+			t.Errorf("info=%#v", info)
+		}
+
+		var nf *rest.NotFound
+		if _, err = m.GetStaged(ctx); !errors.As(err, &nf) {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
+
+func TestUpdatePolicy(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc, rest.WithMiddleware(jsonContentType(t)))
+		if err := c.Login(ctx, simulator.DefaultLogin); err != nil {
+			t.Fatal(err)
+		}
+
+		m := update.NewManager(c)
+
+		config := update.PolicyConfig{
+			CustomURL:     "https://updates.example.com/vcsa/",
+			CheckSchedule: []update.Time{{Day: "EVERYDAY", Hour: 2, Minute: 30}},
+			AutoStage:     true,
+		}
+		if err := m.SetPolicy(ctx, config); err != nil {
+			t.Fatal(err)
+		}
+
+		policy, err := m.GetPolicy(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if policy.DefaultURL == "" || policy.CustomURL != config.CustomURL || !policy.AutoStage {
+			t.Errorf("policy=%#v", policy)
+		}
+		if len(policy.CheckSchedule) != 1 || policy.CheckSchedule[0] != config.CheckSchedule[0] {
+			t.Errorf("schedule=%#v", policy.CheckSchedule)
+		}
+	})
+}
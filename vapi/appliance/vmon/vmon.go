@@ -0,0 +1,125 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vmon
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/vmware/govmomi/vapi/internal"
+	"github.com/vmware/govmomi/vapi/rest"
+)
+
+// Service states
+const (
+	StateStarting = "STARTING"
+	StateStopping = "STOPPING"
+	StateStarted  = "STARTED"
+	StateStopped  = "STOPPED"
+)
+
+// Service startup types
+const (
+	StartupTypeManual    = "MANUAL"
+	StartupTypeAutomatic = "AUTOMATIC"
+	StartupTypeDisabled  = "DISABLED"
+)
+
+// Service health, only reported for services in the StateStarted state
+const (
+	HealthDegraded            = "DEGRADED"
+	HealthHealthy             = "HEALTHY"
+	HealthHealthyWithWarnings = "HEALTHY_WITH_WARNINGS"
+)
+
+// Manager extends rest.Client, adding vMon (VMware Service Lifecycle Manager) related methods.
+type Manager struct {
+	*rest.Client
+}
+
+// NewManager creates a new Manager instance with the given client.
+func NewManager(client *rest.Client) *Manager {
+	return &Manager{
+		Client: client,
+	}
+}
+
+// Info describes a service managed by vMon.
+type Info struct {
+	NameKey        string                    `json:"name_key"`
+	DescriptionKey string                    `json:"description_key"`
+	StartupType    string                    `json:"startup_type"`
+	State          string                    `json:"state"`
+	Health         string                    `json:"health,omitempty"`
+	HealthMessages []rest.LocalizableMessage `json:"health_messages,omitempty"`
+}
+
+// Update specifies the service configuration to update.
+type Update struct {
+	StartupType string `json:"startup_type"`
+}
+
+func (c *Manager) resource(name string) *internal.Resource {
+	return internal.URL(c, internal.ApplianceVMonServicePath).WithSubpath(name)
+}
+
+// List returns all services managed by vMon, keyed by service name.
+func (c *Manager) List(ctx context.Context) (map[string]Info, error) {
+	url := internal.URL(c, internal.ApplianceVMonServicePath)
+	var res []struct {
+		Key   string `json:"key"`
+		Value Info   `json:"value"`
+	}
+	if err := c.Do(ctx, url.Request(ctx, http.MethodGet), &res); err != nil {
+		return nil, err
+	}
+
+	services := make(map[string]Info, len(res))
+	for _, s := range res {
+		services[s.Key] = s.Value
+	}
+	return services, nil
+}
+
+// Get returns the given service.
+func (c *Manager) Get(ctx context.Context, name string) (*Info, error) {
+	var res Info
+	return &res, c.Do(ctx, c.resource(name).Request(ctx, http.MethodGet), &res)
+}
+
+// Update updates the configuration of the given service.
+func (c *Manager) Update(ctx context.Context, name string, spec Update) error {
+	body := struct {
+		Spec Update `json:"spec"`
+	}{spec}
+	return c.Do(ctx, c.resource(name).Request(ctx, http.MethodPatch, body), nil)
+}
+
+// Start starts the given service. The service must not have the StartupTypeDisabled startup type.
+func (c *Manager) Start(ctx context.Context, name string) error {
+	return c.Do(ctx, c.resource(name).WithSubpath("start").Request(ctx, http.MethodPost), nil)
+}
+
+// Stop stops the given service.
+func (c *Manager) Stop(ctx context.Context, name string) error {
+	return c.Do(ctx, c.resource(name).WithSubpath("stop").Request(ctx, http.MethodPost), nil)
+}
+
+// Restart restarts the given service.
+func (c *Manager) Restart(ctx context.Context, name string) error {
+	return c.Do(ctx, c.resource(name).WithSubpath("restart").Request(ctx, http.MethodPost), nil)
+}
@@ -0,0 +1,80 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vmon_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/appliance/vmon"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vim25"
+
+	_ "github.com/vmware/govmomi/vapi/simulator"
+)
+
+func TestVMon(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+		if err := c.Login(ctx, simulator.DefaultLogin); err != nil {
+			t.Fatal(err)
+		}
+
+		m := vmon.NewManager(c)
+
+		list, err := m.List(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if list["vpxd"].Health != vmon.HealthHealthy {
+			t.Errorf("services=%#v", list)
+		}
+
+		if err = m.Stop(ctx, "vsphere-ui"); err != nil {
+			t.Fatal(err)
+		}
+		info, err := m.Get(ctx, "vsphere-ui")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.State != vmon.StateStopped || info.Health != "" {
+			t.Errorf("info=%#v", info)
+		}
+
+		if err = m.Update(ctx, "vsphere-ui", vmon.Update{StartupType: vmon.StartupTypeDisabled}); err != nil {
+			t.Fatal(err)
+		}
+		if err = m.Start(ctx, "vsphere-ui"); err == nil {
+			t.Error("expected error starting a disabled service")
+		}
+
+		if err = m.Update(ctx, "vsphere-ui", vmon.Update{StartupType: vmon.StartupTypeManual}); err != nil {
+			t.Fatal(err)
+		}
+		if err = m.Start(ctx, "vsphere-ui"); err != nil {
+			t.Fatal(err)
+		}
+		info, err = m.Get(ctx, "vsphere-ui")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.State != vmon.StateStarted || info.StartupType != vmon.StartupTypeManual {
+			t.Errorf("info=%#v", info)
+		}
+	})
+}
@@ -0,0 +1,117 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/vmware/govmomi/vapi/internal"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// Manager extends rest.Client, adding cluster module related methods.
+// A cluster module is a group of VMs in a cluster, used by solutions such as Tanzu to keep
+// members of the group on separate hosts via vm-vm anti-affinity rules.
+type Manager struct {
+	*rest.Client
+}
+
+// NewManager creates a new Manager instance with the given client.
+func NewManager(client *rest.Client) *Manager {
+	return &Manager{
+		Client: client,
+	}
+}
+
+// ModuleSummary describes a cluster module.
+type ModuleSummary struct {
+	Cluster string `json:"cluster"`
+	Module  string `json:"module"`
+}
+
+// CreateModule creates a cluster module in the given cluster, returning the module ID.
+func (c *Manager) CreateModule(ctx context.Context, cluster mo.Reference) (string, error) {
+	url := internal.URL(c, internal.ClusterModulesPath)
+	spec := struct {
+		Spec struct {
+			Cluster string `json:"cluster"`
+		} `json:"spec"`
+	}{}
+	spec.Spec.Cluster = cluster.Reference().Value
+	var res string
+	return res, c.Do(ctx, url.Request(ctx, http.MethodPost, spec), &res)
+}
+
+// ListModules returns the cluster modules of all clusters.
+func (c *Manager) ListModules(ctx context.Context) ([]ModuleSummary, error) {
+	url := internal.URL(c, internal.ClusterModulesPath)
+	var res struct {
+		Summaries []ModuleSummary `json:"summaries"`
+	}
+	return res.Summaries, c.Do(ctx, url.Request(ctx, http.MethodGet), &res)
+}
+
+// DeleteModule deletes the given cluster module, removing the rules that apply to its members.
+func (c *Manager) DeleteModule(ctx context.Context, id string) error {
+	url := internal.URL(c, internal.ClusterModulesPath).WithSubpath(id)
+	return c.Do(ctx, url.Request(ctx, http.MethodDelete), nil)
+}
+
+// ListModuleMembers returns the VMs that are members of the given cluster module.
+func (c *Manager) ListModuleMembers(ctx context.Context, id string) ([]types.ManagedObjectReference, error) {
+	url := internal.URL(c, internal.ClusterModulesVMPath).WithSubpath(id)
+	var res struct {
+		VMs []string `json:"vms"`
+	}
+	if err := c.Do(ctx, url.Request(ctx, http.MethodGet), &res); err != nil {
+		return nil, err
+	}
+	refs := make([]types.ManagedObjectReference, len(res.VMs))
+	for i, vm := range res.VMs {
+		refs[i] = types.ManagedObjectReference{Type: "VirtualMachine", Value: vm}
+	}
+	return refs, nil
+}
+
+func (c *Manager) moduleMembers(ctx context.Context, action string, id string, vms ...mo.Reference) (bool, error) {
+	url := internal.URL(c, internal.ClusterModulesVMPath).WithSubpath(id).WithAction(action)
+	spec := struct {
+		VMs []string `json:"vms"`
+	}{}
+	for _, vm := range vms {
+		spec.VMs = append(spec.VMs, vm.Reference().Value)
+	}
+	var res struct {
+		Success bool `json:"success"`
+	}
+	return res.Success, c.Do(ctx, url.Request(ctx, http.MethodPost, spec), &res)
+}
+
+// AddModuleMembers adds the given VMs, which must be in the module's cluster, to the cluster module.
+// Returns false if any of the VMs is already a member, in which case the other VMs are still added.
+func (c *Manager) AddModuleMembers(ctx context.Context, id string, vms ...mo.Reference) (bool, error) {
+	return c.moduleMembers(ctx, "add", id, vms...)
+}
+
+// RemoveModuleMembers removes the given VMs from the cluster module.
+// Returns false if any of the VMs is not a member, in which case the other VMs are still removed.
+func (c *Manager) RemoveModuleMembers(ctx context.Context, id string, vms ...mo.Reference) (bool, error) {
+	return c.moduleMembers(ctx, "remove", id, vms...)
+}
@@ -0,0 +1,111 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/cluster"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+
+	_ "github.com/vmware/govmomi/vapi/simulator"
+)
+
+func TestClusterModules(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+		if err := c.Login(ctx, simulator.DefaultLogin); err != nil {
+			t.Fatal(err)
+		}
+
+		m := cluster.NewManager(c)
+
+		ccr := simulator.Map.Any("ClusterComputeResource").(*simulator.ClusterComputeResource)
+		vms := simulator.Map.Get(*ccr.ResourcePool).(*simulator.ResourcePool).Vm
+		if len(vms) < 2 {
+			t.Fatalf("cluster vms=%v", vms)
+		}
+
+		var nf *rest.NotFound
+		if _, err := m.CreateModule(ctx, types.ManagedObjectReference{Type: "ClusterComputeResource", Value: "domain-c0"}); !errors.As(err, &nf) {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		id, err := m.CreateModule(ctx, ccr)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		modules, err := m.ListModules(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(modules) != 1 || modules[0].Module != id || modules[0].Cluster != ccr.Reference().Value {
+			t.Errorf("modules=%#v", modules)
+		}
+
+		ok, err := m.AddModuleMembers(ctx, id, vms[0], vms[1])
+		if err != nil || !ok {
+			t.Fatalf("add=%t: %v", ok, err)
+		}
+		ok, err = m.AddModuleMembers(ctx, id, vms[0])
+		if err != nil || ok {
+			t.Errorf("add=%t: %v", ok, err)
+		}
+
+		// a VM on a standalone host cannot be a member
+		cr := simulator.Map.Any("ComputeResource").(*mo.ComputeResource)
+		standalone := simulator.Map.Get(*cr.ResourcePool).(*simulator.ResourcePool).Vm[0]
+		var ia *rest.InvalidArgument
+		if _, err = m.AddModuleMembers(ctx, id, standalone); !errors.As(err, &ia) {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		members, err := m.ListModuleMembers(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(members) != 2 {
+			t.Errorf("members=%v", members)
+		}
+
+		ok, err = m.RemoveModuleMembers(ctx, id, vms[0])
+		if err != nil || !ok {
+			t.Errorf("remove=%t: %v", ok, err)
+		}
+		members, err = m.ListModuleMembers(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(members) != 1 || members[0] != vms[1] {
+			t.Errorf("members=%v", members)
+		}
+
+		if err = m.DeleteModule(ctx, id); err != nil {
+			t.Fatal(err)
+		}
+		if _, err = m.ListModuleMembers(ctx, id); !errors.As(err, &nf) {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
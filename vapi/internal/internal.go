@@ -18,10 +18,12 @@ package internal
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"io"
 	"net/http"
 	"net/url"
+	"strings"
 
 	"github.com/vmware/govmomi/vim25/mo"
 	"github.com/vmware/govmomi/vim25/types"
@@ -30,6 +32,8 @@ import (
 // VAPI REST Paths
 const (
 	Path                           = "/rest"
+	APIPath                        = "/api"
+	APISessionPath                 = APIPath + "/session"
 	SessionPath                    = "/com/vmware/cis/session"
 	CategoryPath                   = "/com/vmware/cis/tagging/category"
 	TagPath                        = "/com/vmware/cis/tagging/tag"
@@ -38,13 +42,50 @@ const (
 	LibraryItemFileData            = "/com/vmware/cis/data"
 	LibraryItemPath                = "/com/vmware/content/library/item"
 	LibraryItemFilePath            = "/com/vmware/content/library/item/file"
+	LibraryItemStoragePath         = "/com/vmware/content/library/item/storage"
 	LibraryItemUpdateSession       = "/com/vmware/content/library/item/update-session"
 	LibraryItemUpdateSessionFile   = "/com/vmware/content/library/item/updatesession/file"
 	LibraryItemDownloadSession     = "/com/vmware/content/library/item/download-session"
 	LibraryItemDownloadSessionFile = "/com/vmware/content/library/item/downloadsession/file"
 	LocalLibraryPath               = "/com/vmware/content/local-library"
 	SubscribedLibraryPath          = "/com/vmware/content/subscribed-library"
+	SubscribedLibraryItem          = "/com/vmware/content/library/subscribed-item"
+	Subscriptions                  = "/com/vmware/content/library/subscriptions"
+	SecurityPoliciesPath           = APIPath + "/content/security-policies"
 	VCenterOVFLibraryItem          = "/com/vmware/vcenter/ovf/library-item"
+	VCenterISOImage                = "/com/vmware/vcenter/iso/image"
+	VCenterVMTXLibraryItem         = "/vcenter/vm-template/library-items"
+	ApplianceHealthPath            = "/appliance/health"
+	ApplianceMonitoringPath        = "/appliance/monitoring"
+	ApplianceServicesPath          = "/appliance/services"
+	ApplianceVMonServicePath       = "/appliance/vmon/service"
+	ApplianceBackupPath            = "/appliance/recovery/backup"
+	ApplianceRestorePath           = "/appliance/recovery/restore"
+	ApplianceUpdatePath            = "/appliance/update"
+	ApplianceNetworkingPath        = "/appliance/networking"
+	ApplianceAccessPath            = "/appliance/access"
+	ApplianceShutdownPath          = "/appliance/shutdown"
+	ApplianceLocalAccountsPath     = "/appliance/local-accounts"
+	NamespacesInstancesPath        = APIPath + "/vcenter/namespaces/instances"
+	NamespaceVMClassesPath         = APIPath + "/vcenter/namespace-management/virtual-machine-classes"
+	SupervisorServicesPath         = APIPath + "/vcenter/namespace-management/supervisor-services"
+	NamespaceClustersPath          = APIPath + "/vcenter/namespace-management/clusters"
+	ClusterCompatibilityPath       = APIPath + "/vcenter/namespace-management/cluster-compatibility"
+	VCenterVMPath                  = APIPath + "/vcenter/vm"
+	CustomizationSpecsPath         = APIPath + "/vcenter/guest/customization-specs"
+	ClusterModulesPath             = "/vcenter/cluster/modules"
+	ClusterModulesVMPath           = "/vcenter/cluster/modules/vm"
+	DeploymentPath                 = APIPath + "/vcenter/deployment"
+	TrustedInfrastructurePath      = APIPath + "/vcenter/trusted-infrastructure"
+	IdentityProvidersPath          = APIPath + "/vcenter/identity/providers"
+	ComputePoliciesPath            = APIPath + "/vcenter/compute/policies"
+	VCenterResourcePoolPath        = APIPath + "/vcenter/resource-pool"
+	VCenterFolderPath              = APIPath + "/vcenter/folder"
+	VCenterDatacenterPath          = APIPath + "/vcenter/datacenter"
+	VCenterClusterPath             = APIPath + "/vcenter/cluster"
+	VCenterHostPath                = APIPath + "/vcenter/host"
+	VCenterDatastorePath           = APIPath + "/vcenter/datastore"
+	VCenterNetworkPath             = APIPath + "/vcenter/network"
 	SessionCookieName              = "vmware-api-session-id"
 )
 
@@ -81,13 +122,27 @@ type CloneURL interface {
 
 // Resource wraps url.URL with helpers
 type Resource struct {
-	u *url.URL
+	u      *url.URL
+	api    bool
+	header http.Header
 }
 
-// URL creates a URL resource
+// IsAPI returns true if the given URL path uses the /api JSON protocol,
+// rather than the /rest protocol.
+func IsAPI(path string) bool {
+	return path == APIPath || strings.HasPrefix(path, APIPath+"/")
+}
+
+// URL creates a URL resource.
+// A path with the APIPath prefix targets the /api JSON protocol (vSphere 7.0U2+),
+// any other path is relative to the /rest protocol Path.
 func URL(c CloneURL, path string) *Resource {
-	r := &Resource{u: c.URL()}
-	r.u.Path = Path + path
+	r := &Resource{u: c.URL(), api: IsAPI(path)}
+	if r.api {
+		r.u.Path = path
+	} else {
+		r.u.Path = Path + path
+	}
 	return r
 }
 
@@ -95,39 +150,71 @@ func (r *Resource) String() string {
 	return r.u.String()
 }
 
-// WithID appends id to the URL.Path
+// WithID appends id to the URL.Path, as a single escaped path segment.
 func (r *Resource) WithID(id string) *Resource {
-	r.u.Path += "/id:" + id
+	if !r.api {
+		id = "id:" + id
+	}
+	return r.withPath(id, url.PathEscape(id))
+}
+
+// WithSubpath appends the given path segment(s) to the URL.Path, as-is.
+// Unlike WithID, no "id:" prefix is added when using the /rest protocol.
+func (r *Resource) WithSubpath(subpath string) *Resource {
+	return r.withPath(subpath, (&url.URL{Path: subpath}).EscapedPath())
+}
+
+// withPath appends path to the URL.Path, keeping URL.RawPath in sync with the given escaped form,
+// such that a "/" within an ID is sent as "%2F" rather than as a path separator.
+func (r *Resource) withPath(path string, escaped string) *Resource {
+	r.u.RawPath = r.u.EscapedPath() + "/" + escaped
+	r.u.Path += "/" + path
 	return r
 }
 
 // WithAction sets adds action to the URL.RawQuery
 func (r *Resource) WithAction(action string) *Resource {
+	name := "~action"
+	if r.api {
+		name = "action"
+	}
 	r.u.RawQuery = url.Values{
-		"~action": []string{action},
+		name: []string{action},
 	}.Encode()
 	return r
 }
 
-// WithParameter sets adds a parameter to the URL.RawQuery
+// WithParameter adds a parameter to the URL.RawQuery
 func (r *Resource) WithParameter(name string, value string) *Resource {
-	parameter := url.Values{}
-	parameter.Set(name, value)
+	parameter, _ := url.ParseQuery(r.u.RawQuery)
+	parameter.Add(name, value)
 	r.u.RawQuery = parameter.Encode()
 	return r
 }
 
-// Request returns a new http.Request for the given method.
+// WithHeader sets a header to be sent with the request
+func (r *Resource) WithHeader(name string, value string) *Resource {
+	if r.header == nil {
+		r.header = make(http.Header)
+	}
+	r.header.Set(name, value)
+	return r
+}
+
+// Request returns a new http.Request for the given method, with the given context.
 // An optional body can be provided for POST and PATCH methods.
-func (r *Resource) Request(method string, body ...interface{}) *http.Request {
+func (r *Resource) Request(ctx context.Context, method string, body ...interface{}) *http.Request {
 	rdr := io.MultiReader() // empty body by default
 	if len(body) != 0 {
 		rdr = encode(body[0])
 	}
-	req, err := http.NewRequest(method, r.u.String(), rdr)
+	req, err := http.NewRequestWithContext(ctx, method, r.u.String(), rdr)
 	if err != nil {
 		panic(err)
 	}
+	for name, values := range r.header {
+		req.Header[name] = values
+	}
 	return req
 }
 
@@ -0,0 +1,63 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestResourceWithParameter(t *testing.T) {
+	u, _ := url.Parse("https://127.0.0.1")
+	c := baseURL{*u}
+
+	tests := []struct {
+		r     *Resource
+		query string
+	}{
+		{URL(c, LibraryItemPath).WithParameter("library_id", "lib"), "library_id=lib"},
+		{URL(c, "/appliance/monitoring/query").WithParameter("item.names", "a").WithParameter("item.names", "b").WithParameter("item.interval", "DAY1"), "item.interval=DAY1&item.names=a&item.names=b"},
+		{URL(c, SessionPath).WithAction("get").WithParameter("name", "x"), "name=x&~action=get"},
+		{URL(c, APIPath+"/vcenter/vm").WithAction("clone").WithParameter("name", "x"), "action=clone&name=x"},
+	}
+
+	for _, test := range tests {
+		if query := test.r.u.RawQuery; query != test.query {
+			t.Errorf("%s: query=%s, expected=%s", test.r, query, test.query)
+		}
+	}
+}
+
+func TestResourceWithID(t *testing.T) {
+	u, _ := url.Parse("https://127.0.0.1")
+	c := baseURL{*u}
+
+	tests := []struct {
+		r    *Resource
+		path string
+	}{
+		{URL(c, LibraryPath).WithID("lib"), "/rest/com/vmware/content/library/id:lib"},
+		{URL(c, APIPath+"/vcenter/datastore").WithID("/tmp/ds@folder-5"), "/api/vcenter/datastore/%2Ftmp%2Fds@folder-5"},
+		{URL(c, APIPath+"/vcenter/datastore").WithID("/tmp/ds").WithSubpath("a b/c"), "/api/vcenter/datastore/%2Ftmp%2Fds/a%20b/c"},
+	}
+
+	for _, test := range tests {
+		if path := test.r.u.EscapedPath(); path != test.path {
+			t.Errorf("path=%s, expected=%s", path, test.path)
+		}
+	}
+}
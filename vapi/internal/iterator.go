@@ -0,0 +1,162 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+)
+
+// Paging query parameters
+const (
+	MarkerParameter = "marker"
+	LimitParameter  = "limit"
+)
+
+// WithPage adds the marker and limit paging parameters to the URL.RawQuery,
+// preserving any existing parameters. An empty marker or zero limit is omitted.
+func (r *Resource) WithPage(marker string, limit int) *Resource {
+	query := r.u.Query()
+	if marker != "" {
+		query.Set(MarkerParameter, marker)
+	}
+	if limit > 0 {
+		query.Set(LimitParameter, strconv.Itoa(limit))
+	}
+	r.u.RawQuery = query.Encode()
+	return r
+}
+
+// PageFunc fetches a page of up to limit items, starting after the given marker.
+// It returns the page items and the marker for the next page, where an empty marker indicates the last page.
+type PageFunc func(ctx context.Context, marker string, limit int) ([]json.RawMessage, string, error)
+
+// DoFunc sends the http.Request, decoding resBody if provided, as rest.Client.Do does.
+type DoFunc func(ctx context.Context, req *http.Request, resBody interface{}) error
+
+// Iterator streams the items of a paged list endpoint, fetching the next page as needed.
+// Typical usage:
+//
+//	for it.Next(ctx) {
+//		var tag tags.Tag
+//		if err := it.Decode(&tag); err != nil {
+//			return err
+//		}
+//	}
+//	return it.Err()
+type Iterator struct {
+	fetch  PageFunc
+	limit  int
+	marker string
+	page   []json.RawMessage
+	index  int
+	done   bool
+	err    error
+}
+
+// NewIterator returns an Iterator that uses fetch to retrieve pages of up to limit items.
+func NewIterator(fetch PageFunc, limit int) *Iterator {
+	return &Iterator{fetch: fetch, limit: limit, index: -1}
+}
+
+// NewResourceIterator returns an Iterator for a list endpoint that supports the marker and limit query parameters,
+// where the marker is the last item of the previous page, or its "id" field if the item is an object.
+// The resource func must return a new Resource for each call, as WithPage is applied to it.
+// Endpoints that ignore the paging parameters are listed in full: a page larger than limit is the last page
+// and items up to and including the marker are skipped, should the server return them again.
+func NewResourceIterator(do DoFunc, resource func() *Resource, limit int) *Iterator {
+	return NewIterator(func(ctx context.Context, marker string, limit int) ([]json.RawMessage, string, error) {
+		var items []json.RawMessage
+		req := resource().WithPage(marker, limit).Request(ctx, http.MethodGet)
+		if err := do(ctx, req, &items); err != nil {
+			return nil, "", err
+		}
+		if marker != "" {
+			for i := range items {
+				if id, _ := itemID(items[i]); id == marker {
+					items = items[i+1:]
+					break
+				}
+			}
+		}
+		if len(items) == 0 || limit <= 0 || len(items) != limit {
+			return items, "", nil
+		}
+		next, err := itemID(items[len(items)-1])
+		return items, next, err
+	}, limit)
+}
+
+// itemID returns the given item if it is a string, otherwise the item's "id" field.
+func itemID(item json.RawMessage) (string, error) {
+	var id string
+	if err := json.Unmarshal(item, &id); err == nil {
+		return id, nil
+	}
+	var obj struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(item, &obj); err != nil {
+		return "", err
+	}
+	if obj.ID == "" {
+		return "", errors.New("paged item has no id")
+	}
+	return obj.ID, nil
+}
+
+// Next advances the Iterator to the next item, fetching the next page if needed.
+// It returns false when there are no more items or an error occurred, see Err.
+func (it *Iterator) Next(ctx context.Context) bool {
+	if it.err != nil {
+		return false
+	}
+
+	it.index++
+	for it.index >= len(it.page) {
+		if it.done {
+			return false
+		}
+		page, next, err := it.fetch(ctx, it.marker, it.limit)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.page, it.index, it.marker = page, 0, next
+		if next == "" {
+			it.done = true
+		}
+	}
+
+	return true
+}
+
+// Decode unmarshals the current item into val.
+func (it *Iterator) Decode(val interface{}) error {
+	if it.index < 0 || it.index >= len(it.page) {
+		return errors.New("iterator has no current item")
+	}
+	return json.Unmarshal(it.page[it.index], val)
+}
+
+// Err returns the error, if any, that was encountered during iteration.
+func (it *Iterator) Err() error {
+	return it.err
+}
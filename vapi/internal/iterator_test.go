@@ -0,0 +1,133 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"testing"
+)
+
+type baseURL struct {
+	u url.URL
+}
+
+func (b baseURL) URL() *url.URL {
+	u := b.u
+	return &u
+}
+
+func TestResourceIterator(t *testing.T) {
+	var items []string
+	for i := 0; i < 7; i++ {
+		items = append(items, fmt.Sprintf("id-%d", i))
+	}
+
+	requests := 0
+	do := func(ctx context.Context, req *http.Request, resBody interface{}) error {
+		requests++
+		q := req.URL.Query()
+		if q.Get("library_id") != "lib" {
+			t.Errorf("query=%s", req.URL.RawQuery)
+		}
+		limit, _ := strconv.Atoi(q.Get(LimitParameter))
+		start := 0
+		if marker := q.Get(MarkerParameter); marker != "" {
+			for i := range items {
+				if items[i] == marker {
+					start = i + 1
+				}
+			}
+		}
+		end := start + limit
+		if end > len(items) {
+			end = len(items)
+		}
+		b, _ := json.Marshal(items[start:end])
+		return json.Unmarshal(b, resBody)
+	}
+
+	u := baseURL{url.URL{Scheme: "https", Host: "vc"}}
+	resource := func() *Resource {
+		return URL(u, LibraryItemPath).WithParameter("library_id", "lib")
+	}
+
+	for _, limit := range []int{1, 3, 7, 10} {
+		requests = 0
+		it := NewResourceIterator(do, resource, limit)
+		var ids []string
+		for it.Next(context.Background()) {
+			var id string
+			if err := it.Decode(&id); err != nil {
+				t.Fatal(err)
+			}
+			ids = append(ids, id)
+		}
+		if err := it.Err(); err != nil {
+			t.Fatal(err)
+		}
+		if len(ids) != len(items) {
+			t.Errorf("limit=%d: ids=%v", limit, ids)
+		}
+		expect := len(items)/limit + 1
+		if requests != expect {
+			t.Errorf("limit=%d: requests=%d, expected %d", limit, requests, expect)
+		}
+	}
+}
+
+func TestResourceIteratorUnpaged(t *testing.T) {
+	items := []string{"id-0", "id-1", "id-2"}
+
+	requests := 0
+	do := func(ctx context.Context, req *http.Request, resBody interface{}) error {
+		requests++
+		b, _ := json.Marshal(items)
+		return json.Unmarshal(b, resBody)
+	}
+
+	u := baseURL{url.URL{Scheme: "https", Host: "vc"}}
+	resource := func() *Resource {
+		return URL(u, TagPath)
+	}
+
+	for _, limit := range []int{0, 2, 3} {
+		requests = 0
+		it := NewResourceIterator(do, resource, limit)
+		var ids []string
+		for it.Next(context.Background()) {
+			var id string
+			if err := it.Decode(&id); err != nil {
+				t.Fatal(err)
+			}
+			ids = append(ids, id)
+		}
+		if err := it.Err(); err != nil {
+			t.Fatal(err)
+		}
+		if len(ids) != len(items) {
+			t.Errorf("limit=%d: ids=%v", limit, ids)
+		}
+		if requests > 2 {
+			t.Errorf("limit=%d: requests=%d", limit, requests)
+		}
+	}
+}
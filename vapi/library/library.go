@@ -38,11 +38,16 @@ type Library struct {
 	Description      string            `json:"description,omitempty"`
 	ID               string            `json:"id,omitempty"`
 	LastModifiedTime *time.Time        `json:"last_modified_time,omitempty"`
+	LastSyncTime     *time.Time        `json:"last_sync_time,omitempty"`
 	Name             string            `json:"name,omitempty"`
 	Storage          []StorageBackings `json:"storage_backings,omitempty"`
 	Type             string            `json:"type,omitempty"`
 	Version          string            `json:"version,omitempty"`
 	Subscription     *Subscription     `json:"subscription_info,omitempty"`
+	Publish          *Publish          `json:"publish_info,omitempty"`
+	SecurityPolicyID string            `json:"security_policy_id,omitempty"`
+	// UnsetSecurityPolicyID removes the security policy of a library when updated, see UpdateLibrary.
+	UnsetSecurityPolicyID bool `json:"unset_security_policy_id,omitempty"`
 }
 
 // Subscription info
@@ -56,6 +61,19 @@ type Subscription struct {
 	UserName             string `json:"user_name,omitempty"`
 }
 
+// Publish info of a local library.
+// The Password and CurrentPassword fields are not returned by the server.
+// To change the password of a library using BASIC authentication, CurrentPassword must be set to the existing password.
+type Publish struct {
+	AuthenticationMethod string `json:"authentication_method,omitempty"`
+	CurrentPassword      string `json:"current_password,omitempty"`
+	Password             string `json:"password,omitempty"`
+	PersistJSON          *bool  `json:"persist_json_enabled,omitempty"`
+	PublishURL           string `json:"publish_url,omitempty"`
+	Published            *bool  `json:"published,omitempty"`
+	UserName             string `json:"user_name,omitempty"`
+}
+
 // Patch merges updates from the given src.
 func (l *Library) Patch(src *Library) {
 	if src.Name != "" {
@@ -67,6 +85,12 @@ func (l *Library) Patch(src *Library) {
 	if src.Version != "" {
 		l.Version = src.Version
 	}
+	if src.SecurityPolicyID != "" {
+		l.SecurityPolicyID = src.SecurityPolicyID
+	}
+	if src.UnsetSecurityPolicyID {
+		l.SecurityPolicyID = ""
+	}
 }
 
 // Manager extends rest.Client, adding content library related methods.
@@ -101,7 +125,7 @@ func (c *Manager) FindLibrary(ctx context.Context, search Find) ([]string, error
 		Spec Find `json:"spec"`
 	}{search}
 	var res []string
-	return res, c.Do(ctx, url.Request(http.MethodPost, spec), &res)
+	return res, c.Do(ctx, url.Request(ctx, http.MethodPost, spec), &res)
 }
 
 // CreateLibrary creates a new library with the given Type, Name,
@@ -116,27 +140,57 @@ func (c *Manager) CreateLibrary(ctx context.Context, library Library) (string, e
 	}
 	url := internal.URL(c, path)
 	var res string
-	return res, c.Do(ctx, url.Request(http.MethodPost, spec), &res)
+	return res, c.Do(ctx, url.Request(ctx, http.MethodPost, spec), &res)
+}
+
+// UpdateLibrary updates an existing library, only the non-empty fields of the given library are changed.
+// For example, to publish a local library:
+//
+//	published := true
+//	err := m.UpdateLibrary(ctx, &library.Library{ID: id, Publish: &library.Publish{Published: &published}})
+func (c *Manager) UpdateLibrary(ctx context.Context, library *Library) error {
+	spec := struct {
+		Library *Library `json:"update_spec"`
+	}{library}
+	path := internal.LocalLibraryPath
+	if library.Type == "SUBSCRIBED" {
+		path = internal.SubscribedLibraryPath
+	}
+	url := internal.URL(c, path).WithID(library.ID)
+	return c.Do(ctx, url.Request(ctx, http.MethodPatch, spec), nil)
+}
+
+// GetLibraryPublishInfo returns the publish info of a published local library, including the subscription URL
+// and authentication settings to be used when creating a subscribed library.
+func (c *Manager) GetLibraryPublishInfo(ctx context.Context, id string) (*Publish, error) {
+	l, err := c.GetLibraryByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if l.Publish == nil || l.Publish.Published == nil || !*l.Publish.Published {
+		return nil, fmt.Errorf("library %q is not published", l.Name)
+	}
+	return l.Publish, nil
 }
 
 // DeleteLibrary deletes an existing library.
 func (c *Manager) DeleteLibrary(ctx context.Context, library *Library) error {
 	url := internal.URL(c, internal.LocalLibraryPath).WithID(library.ID)
-	return c.Do(ctx, url.Request(http.MethodDelete), nil)
+	return c.Do(ctx, url.Request(ctx, http.MethodDelete), nil)
 }
 
 // ListLibraries returns a list of all content library IDs in the system.
 func (c *Manager) ListLibraries(ctx context.Context) ([]string, error) {
 	url := internal.URL(c, internal.LibraryPath)
 	var res []string
-	return res, c.Do(ctx, url.Request(http.MethodGet), &res)
+	return res, c.Do(ctx, url.Request(ctx, http.MethodGet), &res)
 }
 
 // GetLibraryByID returns information on a library for the given ID.
 func (c *Manager) GetLibraryByID(ctx context.Context, id string) (*Library, error) {
 	url := internal.URL(c, internal.LibraryPath).WithID(id)
 	var res Library
-	return &res, c.Do(ctx, url.Request(http.MethodGet), &res)
+	return &res, c.Do(ctx, url.Request(ctx, http.MethodGet), &res)
 }
 
 // GetLibraryByName returns information on a library for the given name.
@@ -173,3 +227,36 @@ func (c *Manager) GetLibraries(ctx context.Context) ([]Library, error) {
 	}
 	return libraries, nil
 }
+
+// SyncLibrary requests synchronization of a subscribed library with its publisher.
+// The sync is asynchronous, see WaitForLibrarySync to observe its completion.
+func (c *Manager) SyncLibrary(ctx context.Context, library *Library) error {
+	url := internal.URL(c, internal.SubscribedLibraryPath).WithID(library.ID).WithAction("sync")
+	return c.Do(ctx, url.Request(ctx, http.MethodPost), nil)
+}
+
+// EvictSubscribedLibrary evicts the cached content of an on-demand subscribed library.
+func (c *Manager) EvictSubscribedLibrary(ctx context.Context, library *Library) error {
+	url := internal.URL(c, internal.SubscribedLibraryPath).WithID(library.ID).WithAction("evict")
+	return c.Do(ctx, url.Request(ctx, http.MethodPost), nil)
+}
+
+// WaitForLibrarySync polls the given library every interval, until its LastSyncTime differs from library.LastSyncTime,
+// returning the updated library. The library argument would normally be retrieved before calling SyncLibrary.
+func (c *Manager) WaitForLibrarySync(ctx context.Context, library *Library, interval time.Duration) (*Library, error) {
+	for {
+		l, err := c.GetLibraryByID(ctx, library.ID)
+		if err != nil {
+			return nil, err
+		}
+		if l.LastSyncTime != nil && (library.LastSyncTime == nil || !l.LastSyncTime.Equal(*library.LastSyncTime)) {
+			return l, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
@@ -18,17 +18,73 @@ package library
 
 import (
 	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
 	"net/http"
+	"strings"
 
 	"github.com/vmware/govmomi/vapi/internal"
 )
 
+// Checksum algorithms
+const (
+	ChecksumMD5    = "MD5"
+	ChecksumSHA1   = "SHA1"
+	ChecksumSHA256 = "SHA256"
+	ChecksumSHA512 = "SHA512"
+)
+
 // Checksum provides checksum information on library item files.
 type Checksum struct {
 	Algorithm string `json:"algorithm,omitempty"`
 	Checksum  string `json:"checksum"`
 }
 
+// hash returns a new hash.Hash for the checksum Algorithm, which defaults to SHA1.
+func (c *Checksum) hash() (hash.Hash, error) {
+	switch strings.ToUpper(c.Algorithm) {
+	case ChecksumMD5:
+		return md5.New(), nil
+	case ChecksumSHA1, "":
+		return sha1.New(), nil
+	case ChecksumSHA256:
+		return sha256.New(), nil
+	case ChecksumSHA512:
+		return sha512.New(), nil
+	}
+	return nil, fmt.Errorf("unsupported checksum algorithm: %q", c.Algorithm)
+}
+
+// verify returns a *ChecksumError if the sum of h does not match the checksum of the named file.
+func (c *Checksum) verify(name string, h hash.Hash) error {
+	actual := hex.EncodeToString(h.Sum(nil))
+	if strings.EqualFold(actual, c.Checksum) {
+		return nil
+	}
+	return &ChecksumError{
+		Name:     name,
+		Expected: *c,
+		Actual:   Checksum{Algorithm: c.Algorithm, Checksum: actual},
+	}
+}
+
+// ChecksumError is returned when the checksum of transferred file content does not match the expected checksum.
+type ChecksumError struct {
+	Name     string
+	Expected Checksum
+	Actual   Checksum
+}
+
+func (e *ChecksumError) Error() string {
+	return fmt.Sprintf("file %s: %s checksum mismatch: expected %s, got %s",
+		e.Name, e.Expected.Algorithm, e.Expected.Checksum, e.Actual.Checksum)
+}
+
 // File provides methods to get information on library item files.
 type File struct {
 	Cached   *bool     `json:"cached,omitempty"`
@@ -42,7 +98,7 @@ type File struct {
 func (c *Manager) ListLibraryItemFiles(ctx context.Context, id string) ([]File, error) {
 	url := internal.URL(c, internal.LibraryItemFilePath).WithParameter("library_item_id", id)
 	var res []File
-	return res, c.Do(ctx, url.Request(http.MethodGet), &res)
+	return res, c.Do(ctx, url.Request(ctx, http.MethodGet), &res)
 }
 
 // GetLibraryItemFile returns a file with the provided name for a library item.
@@ -52,5 +108,5 @@ func (c *Manager) GetLibraryItemFile(ctx context.Context, id, fileName string) (
 		Name string `json:"name"`
 	}{fileName}
 	var res File
-	return &res, c.Do(ctx, url.Request(http.MethodPost, spec), &res)
+	return &res, c.Do(ctx, url.Request(ctx, http.MethodPost, spec), &res)
 }
@@ -41,6 +41,9 @@ type Item struct {
 	SourceID         string     `json:"source_id,omitempty"`
 	Type             string     `json:"type,omitempty"`
 	Version          string     `json:"version,omitempty"`
+
+	SecurityCompliance      *bool                    `json:"security_compliance,omitempty"`
+	CertificateVerification *CertificateVerification `json:"certificate_verification_info,omitempty"`
 }
 
 // Patch merges updates from the given src.
@@ -79,27 +82,27 @@ func (c *Manager) CreateLibraryItem(ctx context.Context, item Item) (string, err
 	}
 	url := internal.URL(c, internal.LibraryItemPath)
 	var res string
-	return res, c.Do(ctx, url.Request(http.MethodPost, spec), &res)
+	return res, c.Do(ctx, url.Request(ctx, http.MethodPost, spec), &res)
 }
 
 // DeleteLibraryItem deletes an existing library item.
 func (c *Manager) DeleteLibraryItem(ctx context.Context, item *Item) error {
 	url := internal.URL(c, internal.LibraryItemPath).WithID(item.ID)
-	return c.Do(ctx, url.Request(http.MethodDelete), nil)
+	return c.Do(ctx, url.Request(ctx, http.MethodDelete), nil)
 }
 
 // ListLibraryItems returns a list of all items in a content library.
 func (c *Manager) ListLibraryItems(ctx context.Context, id string) ([]string, error) {
 	url := internal.URL(c, internal.LibraryItemPath).WithParameter("library_id", id)
 	var res []string
-	return res, c.Do(ctx, url.Request(http.MethodGet), &res)
+	return res, c.Do(ctx, url.Request(ctx, http.MethodGet), &res)
 }
 
 // GetLibraryItem returns information on a library item for the given ID.
 func (c *Manager) GetLibraryItem(ctx context.Context, id string) (*Item, error) {
 	url := internal.URL(c, internal.LibraryItemPath).WithID(id)
 	var res Item
-	return &res, c.Do(ctx, url.Request(http.MethodGet), &res)
+	return &res, c.Do(ctx, url.Request(ctx, http.MethodGet), &res)
 }
 
 // GetLibraryItems returns a list of all the library items for the specified library.
@@ -120,16 +123,17 @@ func (c *Manager) GetLibraryItems(ctx context.Context, libraryID string) ([]Item
 }
 
 // FindItem is the search criteria for finding library items.
+// Fields left empty match any item.
 type FindItem struct {
-	Cached    *bool  `json:"cached,omitempty"`
-	LibraryID string `json:"library_id,omitempty"`
-	Name      string `json:"name,omitempty"`
-	SourceID  string `json:"source_id,omitempty"`
-	Type      string `json:"type,omitempty"`
+	Cached    *bool  `json:"cached,omitempty"`     // Cached matches items with or without content cached locally
+	LibraryID string `json:"library_id,omitempty"` // LibraryID matches items in the given library
+	Name      string `json:"name,omitempty"`       // Name matches items by name, case-insensitive
+	SourceID  string `json:"source_id,omitempty"`  // SourceID matches subscribed items by their published item ID
+	Type      string `json:"type,omitempty"`       // Type matches items by type, such as "ovf" or "iso"
 }
 
 // FindLibraryItems returns the IDs of all the library items that match the
-// search criteria.
+// search criteria, in a single request.
 func (c *Manager) FindLibraryItems(
 	ctx context.Context, search FindItem) ([]string, error) {
 
@@ -138,5 +142,22 @@ func (c *Manager) FindLibraryItems(
 		Spec FindItem `json:"spec"`
 	}{search}
 	var res []string
-	return res, c.Do(ctx, url.Request(http.MethodPost, spec), &res)
+	return res, c.Do(ctx, url.Request(ctx, http.MethodPost, spec), &res)
+}
+
+// SyncLibraryItem requests synchronization of a subscribed library item.
+// If force is true, the item content is synchronized even if the library is configured to download content on demand.
+func (c *Manager) SyncLibraryItem(ctx context.Context, item *Item, force bool) error {
+	url := internal.URL(c, internal.SubscribedLibraryItem).WithID(item.ID).WithAction("sync")
+	spec := struct {
+		Force bool `json:"force_sync_content"`
+	}{force}
+	return c.Do(ctx, url.Request(ctx, http.MethodPost, spec), nil)
+}
+
+// EvictLibraryItem evicts the cached content of a library item in an on-demand subscribed library.
+// The item metadata is retained, its content can be fetched again with SyncLibraryItem.
+func (c *Manager) EvictLibraryItem(ctx context.Context, item *Item) error {
+	url := internal.URL(c, internal.SubscribedLibraryItem).WithID(item.ID).WithAction("evict")
+	return c.Do(ctx, url.Request(ctx, http.MethodPost), nil)
 }
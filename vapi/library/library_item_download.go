@@ -0,0 +1,260 @@
+/*
+Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package library
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vim25/progress"
+	"github.com/vmware/govmomi/vim25/soap"
+)
+
+// DefaultDownloadKeepAlive is the default interval between download session keep-alive requests.
+// vCenter expires a download session after 5 minutes of inactivity.
+const DefaultDownloadKeepAlive = time.Minute
+
+// Download specifies how a DownloadSession transfers files.
+type Download struct {
+	// KeepAlive is the interval between keep-alive requests while the session is open,
+	// defaults to DefaultDownloadKeepAlive.
+	KeepAlive time.Duration
+	// Retries is the number of consecutive failed attempts that are retried before giving up.
+	// If the session has expired it is re-created, a retry resumes the transfer using a ranged request.
+	Retries int
+	// SkipChecksum disables verification of the downloaded content against the checksum reported for the file.
+	// Otherwise a mismatch is returned as a *ChecksumError.
+	SkipChecksum bool
+}
+
+// DownloadSession is a library item download session, kept alive while open.
+type DownloadSession struct {
+	m      *Manager
+	itemID string
+	param  Download
+
+	mu sync.Mutex
+	id string
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewDownloadSession creates a download session for the given library item.
+// The session must be closed with Close once all files have been downloaded.
+func (c *Manager) NewDownloadSession(ctx context.Context, itemID string, param *Download) (*DownloadSession, error) {
+	s := &DownloadSession{m: c, itemID: itemID, stop: make(chan struct{})}
+	if param != nil {
+		s.param = *param
+	}
+	if s.param.KeepAlive <= 0 {
+		s.param.KeepAlive = DefaultDownloadKeepAlive
+	}
+
+	id, err := c.CreateLibraryItemDownloadSession(ctx, Session{LibraryItemID: itemID})
+	if err != nil {
+		return nil, err
+	}
+	s.id = id
+
+	s.wg.Add(1)
+	go s.keepAlive()
+
+	return s, nil
+}
+
+// ID returns the current session ID, which changes if the session is re-created.
+func (s *DownloadSession) ID() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.id
+}
+
+func (s *DownloadSession) keepAlive() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.param.KeepAlive)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			// errors are ignored here, an expired session is re-created by Download
+			_ = s.m.KeepAliveLibraryItemDownloadSession(context.Background(), s.ID())
+		}
+	}
+}
+
+// Close stops the session keep-alive and deletes the download session.
+func (s *DownloadSession) Close(ctx context.Context) error {
+	close(s.stop)
+	s.wg.Wait()
+	return s.m.DeleteLibraryItemDownloadSession(ctx, s.ID())
+}
+
+// recreate replaces the session with a new one, if the current session no longer exists.
+func (s *DownloadSession) recreate(ctx context.Context) error {
+	id := s.ID()
+	_, err := s.m.GetLibraryItemDownloadSession(ctx, id)
+	if err == nil {
+		return nil
+	}
+	if !rest.IsStatusError(err, http.StatusNotFound) {
+		return err
+	}
+
+	id, err = s.m.CreateLibraryItemDownloadSession(ctx, Session{LibraryItemID: s.itemID})
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.id = id
+	s.mu.Unlock()
+	return nil
+}
+
+// Download prepares the file with the given name and writes its content to w.
+// If sink is not nil, it receives progress reports for the entire file transfer.
+// The checksum of the content is verified against the checksum reported by the session, unless SkipChecksum is set.
+func (s *DownloadSession) Download(ctx context.Context, name string, w io.Writer, sink progress.Sinker) error {
+	t := &transfer{ctx: ctx}
+	if sink != nil {
+		t.ch = sink.Sink()
+	}
+
+	var err error
+	retries := s.param.Retries
+
+	for {
+		pos := t.pos
+		err = s.download(ctx, name, w, t)
+		if err == nil || retries == 0 || ctx.Err() != nil {
+			break
+		}
+		if t.pos > pos {
+			retries = s.param.Retries // progress was made, reset the retry count
+		}
+		retries--
+
+		if err = s.recreate(ctx); err != nil {
+			break
+		}
+	}
+
+	if err == nil {
+		err = t.check(name)
+	}
+	t.done(err)
+	return err
+}
+
+// prepareError returns an error for a file that failed to prepare, including the server's message if any.
+func prepareError(name string, info *DownloadFile) error {
+	if info.ErrorMessage == nil {
+		return fmt.Errorf("prepare %s failed", name)
+	}
+	return fmt.Errorf("prepare %s failed: %s", name, info.ErrorMessage.DefaultMessage)
+}
+
+// download writes the given file to w, starting at the current transfer position.
+func (s *DownloadSession) download(ctx context.Context, name string, w io.Writer, t *transfer) error {
+	id := s.ID()
+
+	info, err := s.m.GetLibraryItemDownloadSessionFile(ctx, id, name)
+	if err != nil {
+		return err
+	}
+
+	// poll with a backoff, files are usually prepared quickly
+	delay := 100 * time.Millisecond
+	for info.Status != "PREPARED" {
+		switch info.Status {
+		case "ERROR":
+			return prepareError(name, info)
+		case "UNPREPARED":
+			// not yet prepared, or the server reset the preparation
+			if info, err = s.m.PrepareLibraryItemDownloadSessionFile(ctx, id, name); err != nil {
+				return err
+			}
+			if info.Status == "PREPARED" || info.Status == "ERROR" {
+				continue
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		if delay < time.Second {
+			delay *= 2
+		}
+
+		if info, err = s.m.GetLibraryItemDownloadSessionFile(ctx, id, name); err != nil {
+			return err
+		}
+	}
+
+	if info.Size != 0 {
+		t.size = info.Size
+	}
+	if t.pos == 0 && info.Checksum != nil && !s.param.SkipChecksum {
+		if err = t.setChecksum(info.Checksum); err != nil {
+			return err
+		}
+	}
+
+	u, err := url.Parse(info.DownloadEndpoint.URI)
+	if err != nil {
+		return err
+	}
+
+	p := soap.DefaultDownload
+	if t.pos != 0 {
+		p.Headers = map[string]string{"Range": fmt.Sprintf("bytes=%d-", t.pos)}
+	}
+
+	res, err := s.m.DownloadRequest(ctx, u, &p)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	switch res.StatusCode {
+	case http.StatusOK:
+		// the range was not applied, skip the bytes already transferred
+		if _, err = io.CopyN(ioutil.Discard, res.Body, t.pos); err != nil {
+			return err
+		}
+	case http.StatusPartialContent:
+	default:
+		return fmt.Errorf("download(%s): %s", u, res.Status)
+	}
+
+	_, err = io.Copy(w, &transferReader{res.Body, t})
+	return err
+}
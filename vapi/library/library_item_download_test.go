@@ -0,0 +1,302 @@
+/*
+Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package library_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/library"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/soap"
+)
+
+// expiringTransport truncates the body of the first file download, invoking expire before returning the response.
+type expiringTransport struct {
+	http.RoundTripper
+	sync.Mutex
+	expire     func()
+	gets       int
+	keepAlives int
+	ranges     []string
+}
+
+type truncatedBody struct {
+	io.ReadCloser
+	n int
+}
+
+func (b *truncatedBody) Read(p []byte) (int, error) {
+	if b.n <= 0 {
+		return 0, errors.New("connection reset")
+	}
+	if len(p) > b.n {
+		p = p[:b.n]
+	}
+	n, err := b.ReadCloser.Read(p)
+	b.n -= n
+	return n, err
+}
+
+func (t *expiringTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.Lock()
+	if strings.Contains(req.URL.RawQuery, "keep-alive") {
+		t.keepAlives++
+	}
+	data := req.Method == http.MethodGet && strings.Contains(req.URL.Path, "/com/vmware/cis/data/")
+	if data {
+		t.gets++
+		t.ranges = append(t.ranges, req.Header.Get("Range"))
+	}
+	first := data && t.gets == 1
+	t.Unlock()
+
+	res, err := t.RoundTripper.RoundTrip(req)
+	if err != nil || !first {
+		return res, err
+	}
+	t.expire()
+	res.Body = &truncatedBody{res.Body, 300}
+	return res, nil
+}
+
+func TestDownloadSession(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		expiring := &expiringTransport{RoundTripper: vc.Client.Transport}
+		c := rest.NewClient(vc, rest.WithTransport(expiring))
+		if err := c.Login(ctx, simulator.DefaultLogin); err != nil {
+			t.Fatal(err)
+		}
+		m := library.NewManager(c)
+
+		content := strings.Repeat("0123456789", 100)
+		item := newTestItem(ctx, t, c, map[string]string{"disk.vmdk": content})
+
+		session, err := m.NewDownloadSession(ctx, item, &library.Download{KeepAlive: 10 * time.Millisecond, Retries: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		id := session.ID()
+		expiring.expire = func() {
+			_ = m.DeleteLibraryItemDownloadSession(ctx, id)
+		}
+
+		time.Sleep(50 * time.Millisecond)
+
+		sink := new(progressSink)
+		var buf bytes.Buffer
+		if err = session.Download(ctx, "disk.vmdk", &buf, sink); err != nil {
+			t.Fatal(err)
+		}
+		<-sink.done
+
+		if buf.String() != content {
+			t.Errorf("content=%q", buf.String())
+		}
+		if session.ID() == id {
+			t.Error("expected session to be re-created")
+		}
+		if len(expiring.ranges) != 2 || expiring.ranges[0] != "" || expiring.ranges[1] != "bytes=300-" {
+			t.Errorf("ranges=%q", expiring.ranges)
+		}
+		last := sink.reports[len(sink.reports)-1]
+		if last.Percentage() != 100 || last.Error() != nil {
+			t.Errorf("progress=%f (%s) %v", last.Percentage(), last.Detail(), last.Error())
+		}
+
+		if err = session.Close(ctx); err != nil {
+			t.Fatal(err)
+		}
+		expiring.Lock()
+		if expiring.keepAlives == 0 {
+			t.Error("expected keep-alive requests")
+		}
+		expiring.Unlock()
+
+		ids, err := m.ListLibraryItemDownloadSession(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(ids) != 0 {
+			t.Errorf("sessions=%v", ids)
+		}
+
+		// Without retries the download fails
+		session, err = m.NewDownloadSession(ctx, item, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer session.Close(ctx)
+		expiring.Lock()
+		expiring.gets = 0
+		expiring.ranges = nil
+		expiring.Unlock()
+		buf.Reset()
+		if err = session.Download(ctx, "disk.vmdk", &buf, nil); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
+
+func TestDownloadSessionState(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+		if err := c.Login(ctx, simulator.DefaultLogin); err != nil {
+			t.Fatal(err)
+		}
+		m := library.NewManager(c)
+
+		item := newTestItem(ctx, t, c, map[string]string{"test.ovf": testOVF})
+		session, err := m.CreateLibraryItemDownloadSession(ctx, library.Session{LibraryItemID: item})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err = m.PrepareLibraryItemDownloadSessionFile(ctx, session, "invalid.ovf"); err == nil {
+			t.Error("expected error preparing an unknown file")
+		}
+
+		info, err := m.PrepareLibraryItemDownloadSessionFile(ctx, session, "test.ovf")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Status != "PREPARING" || info.DownloadEndpoint != nil {
+			t.Errorf("info=%#v", info)
+		}
+		if info, err = m.GetLibraryItemDownloadSessionFile(ctx, session, "test.ovf"); err != nil {
+			t.Fatal(err)
+		}
+		if info.Status != "PREPARED" || info.DownloadEndpoint == nil || info.Size != int64(len(testOVF)) {
+			t.Fatalf("info=%#v", info)
+		}
+
+		u, err := url.Parse(info.DownloadEndpoint.URI)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r, _, err := c.Download(ctx, u, &soap.DefaultDownload)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _ = io.Copy(ioutil.Discard, r)
+		_ = r.Close()
+		if info, err = m.GetLibraryItemDownloadSessionFile(ctx, session, "test.ovf"); err != nil {
+			t.Fatal(err)
+		}
+		if info.BytesTransferred != info.Size {
+			t.Errorf("transferred=%d", info.BytesTransferred)
+		}
+
+		if err = m.CancelLibraryItemDownloadSession(ctx, session); err != nil {
+			t.Fatal(err)
+		}
+		s, err := m.GetLibraryItemDownloadSession(ctx, session)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s.State != "CANCELED" {
+			t.Errorf("state=%s", s.State)
+		}
+		if _, err = m.PrepareLibraryItemDownloadSessionFile(ctx, session, "test.ovf"); err == nil {
+			t.Error("expected error preparing a file of a canceled session")
+		}
+		if _, _, err = c.Download(ctx, u, &soap.DefaultDownload); err == nil {
+			t.Error("expected error downloading a file of a canceled session")
+		}
+		if err = m.KeepAliveLibraryItemDownloadSession(ctx, session); err == nil {
+			t.Error("expected error keeping a canceled session alive")
+		}
+		if err = m.DeleteLibraryItemDownloadSession(ctx, session); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func TestDownloadSessionPrepare(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		var resets, prepares int
+		var fail bool
+		status := func(next rest.Handler) rest.Handler {
+			return func(ctx context.Context, req *http.Request, resBody interface{}) error {
+				err := next(ctx, req, resBody)
+				info, ok := resBody.(*library.DownloadFile)
+				if err != nil || !ok {
+					return err
+				}
+				switch {
+				case strings.Contains(req.URL.RawQuery, "action=prepare"):
+					prepares++
+					if fail {
+						info.Status = "ERROR"
+						info.ErrorMessage = &rest.LocalizableMessage{DefaultMessage: "no space left"}
+					}
+				case info.Status == "PREPARED" && resets == 0:
+					resets++
+					info.Status = "UNPREPARED"
+				}
+				return nil
+			}
+		}
+
+		c := rest.NewClient(vc, rest.WithMiddleware(status))
+		if err := c.Login(ctx, simulator.DefaultLogin); err != nil {
+			t.Fatal(err)
+		}
+		m := library.NewManager(c)
+
+		item := newTestItem(ctx, t, c, map[string]string{"test.ovf": testOVF})
+
+		// a reset preparation is prepared again
+		session, err := m.NewDownloadSession(ctx, item, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		if err = session.Download(ctx, "test.ovf", &buf, nil); err != nil {
+			t.Fatal(err)
+		}
+		if buf.String() != testOVF || resets != 1 || prepares != 2 {
+			t.Errorf("resets=%d, prepares=%d, content=%q", resets, prepares, buf.String())
+		}
+		_ = session.Close(ctx)
+
+		// a failed preparation is returned rather than polled
+		fail = true
+		session, err = m.NewDownloadSession(ctx, item, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer session.Close(ctx)
+		ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+		err = session.Download(ctx, "test.ovf", ioutil.Discard, nil)
+		if err == nil || !strings.Contains(err.Error(), "no space left") {
+			t.Errorf("err=%v", err)
+		}
+	})
+}
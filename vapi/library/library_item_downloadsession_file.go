@@ -43,7 +43,7 @@ func (c *Manager) GetLibraryItemDownloadSessionFile(ctx context.Context, session
 		Name string `json:"file_name"`
 	}{name}
 	var res DownloadFile
-	err := c.Do(ctx, url.Request(http.MethodPost, spec), &res)
+	err := c.Do(ctx, url.Request(ctx, http.MethodPost, spec), &res)
 	if err != nil {
 		return nil, err
 	}
@@ -57,7 +57,7 @@ func (c *Manager) GetLibraryItemDownloadSessionFile(ctx context.Context, session
 func (c *Manager) ListLibraryItemDownloadSessionFile(ctx context.Context, sessionID string) ([]DownloadFile, error) {
 	url := internal.URL(c, internal.LibraryItemDownloadSessionFile).WithParameter("download_session_id", sessionID)
 	var res []DownloadFile
-	return res, c.Do(ctx, url.Request(http.MethodGet), &res)
+	return res, c.Do(ctx, url.Request(ctx, http.MethodGet), &res)
 }
 
 // PrepareLibraryItemDownloadSessionFile retrieves information about a specific file that is a part of an download session.
@@ -67,5 +67,5 @@ func (c *Manager) PrepareLibraryItemDownloadSessionFile(ctx context.Context, ses
 		Name string `json:"file_name"`
 	}{name}
 	var res DownloadFile
-	return &res, c.Do(ctx, url.Request(http.MethodPost, spec), &res)
+	return &res, c.Do(ctx, url.Request(ctx, http.MethodPost, spec), &res)
 }
@@ -0,0 +1,289 @@
+/*
+Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package library
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/vmware/govmomi/ovf"
+	"github.com/vmware/govmomi/vim25/progress"
+)
+
+// Export specifies how ExportOVA and ExportOVF write an OVF library item.
+type Export struct {
+	// Download configures the download session used to transfer the item's files.
+	// Unless SkipChecksum is set, files are also verified against the item's manifest, if it has one.
+	Download
+	// Manifest, if true, replaces the item's manifest with a SHA256 manifest generated from the exported files.
+	// Otherwise the item's manifest is exported as-is, or generated if the item has none.
+	Manifest bool
+	// Progress, if set, is called before each file download and returns the progress.Sinker for its transfer.
+	Progress func(name string) progress.Sinker
+}
+
+// exportWriter writes the files of an exported library item.
+type exportWriter interface {
+	// Create starts a new file of the given size, returning the io.Writer for its content.
+	Create(name string, size int64) (io.Writer, error)
+	// Close completes the export.
+	Close() error
+}
+
+// ovaWriter writes files to a tar archive.
+type ovaWriter struct {
+	tw  *tar.Writer
+	now time.Time
+}
+
+func (w *ovaWriter) Create(name string, size int64) (io.Writer, error) {
+	return w.tw, w.tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Size:     size,
+		Mode:     0644,
+		ModTime:  w.now,
+		Format:   tar.FormatUSTAR,
+	})
+}
+
+func (w *ovaWriter) Close() error {
+	return w.tw.Close()
+}
+
+// ovfWriter writes files to a directory.
+type ovfWriter struct {
+	dir string
+	f   *os.File
+}
+
+// Create rejects any name that is not a single path element, as names are provided by the library item.
+func (w *ovfWriter) Create(name string, _ int64) (io.Writer, error) {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return nil, fmt.Errorf("invalid library item file name %q", name)
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	f, err := os.Create(filepath.Join(w.dir, name))
+	if err != nil {
+		return nil, err
+	}
+	w.f = f
+	return f, nil
+}
+
+func (w *ovfWriter) Close() error {
+	if w.f == nil {
+		return nil
+	}
+	err := w.f.Close()
+	w.f = nil
+	return err
+}
+
+// ExportOVA writes the files of the given OVF library item to w as an OVA (tar) archive.
+// The OVF descriptor is written first, followed by the item's manifest if it has one,
+// then the files in the order they are referenced by the descriptor.
+// If the item has no manifest, or param.Manifest is set, a SHA256 manifest is generated
+// and written at the end of the archive.
+func (c *Manager) ExportOVA(ctx context.Context, itemID string, w io.Writer, param *Export) error {
+	return c.export(ctx, itemID, &ovaWriter{tw: tar.NewWriter(w), now: time.Now()}, param)
+}
+
+// ExportOVF writes the files of the given OVF library item to the directory dir, creating it if needed.
+// The manifest is handled the same as ExportOVA.
+func (c *Manager) ExportOVF(ctx context.Context, itemID string, dir string, param *Export) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	w := &ovfWriter{dir: dir}
+	defer w.Close()
+	return c.export(ctx, itemID, w, param)
+}
+
+func (c *Manager) export(ctx context.Context, itemID string, out exportWriter, param *Export) error {
+	if param == nil {
+		param = new(Export)
+	}
+
+	files, err := c.ListLibraryItemFiles(ctx, itemID)
+	if err != nil {
+		return err
+	}
+
+	var descriptor, manifest string
+	info := make(map[string]File, len(files))
+	for _, f := range files {
+		info[f.Name] = f
+		switch path.Ext(f.Name) {
+		case ".ovf":
+			descriptor = f.Name
+		case ".mf":
+			manifest = f.Name
+		}
+		if f.Size == nil {
+			return fmt.Errorf("library item %s file %s: size unknown", itemID, f.Name)
+		}
+	}
+	if descriptor == "" {
+		return fmt.Errorf("library item %s has no OVF descriptor", itemID)
+	}
+
+	session, err := c.NewDownloadSession(ctx, itemID, &param.Download)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = session.Close(ctx)
+	}()
+
+	for _, f := range files {
+		if _, err = c.PrepareLibraryItemDownloadSessionFile(ctx, session.ID(), f.Name); err != nil {
+			return err
+		}
+	}
+
+	download := func(name string, w io.Writer) error {
+		var sink progress.Sinker
+		if param.Progress != nil {
+			sink = param.Progress(name)
+		}
+		return session.Download(ctx, name, w, sink)
+	}
+
+	var buf bytes.Buffer
+	if err = download(descriptor, &buf); err != nil {
+		return err
+	}
+	env, err := ovf.Unmarshal(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		return fmt.Errorf("library item %s: %s: %s", itemID, descriptor, err)
+	}
+
+	var mf bytes.Buffer
+	expected := map[string]*Checksum{}
+	if manifest != "" {
+		if err = download(manifest, &mf); err != nil {
+			return err
+		}
+		if !param.SkipChecksum {
+			if expected, err = ReadManifest(bytes.NewReader(mf.Bytes())); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Files referenced by the descriptor are written in order, followed by any others in name order.
+	var names, others []string
+	seen := map[string]bool{descriptor: true, manifest: true}
+	for _, ref := range env.References {
+		if _, ok := info[ref.Href]; ok && !seen[ref.Href] {
+			seen[ref.Href] = true
+			names = append(names, ref.Href)
+		}
+	}
+	for _, f := range files {
+		if !seen[f.Name] {
+			others = append(others, f.Name)
+		}
+	}
+	sort.Strings(others)
+	names = append(names, others...)
+
+	sums := make(map[string][]byte, len(files))
+
+	// write writes the content of a file, as written to w by src, verifying its manifest checksum if any.
+	write := func(name string, size int64, src func(w io.Writer) error) error {
+		w, err := out.Create(name, size)
+		if err != nil {
+			return err
+		}
+		h := sha256.New()
+		w = io.MultiWriter(w, h)
+
+		var check hash.Hash
+		if sum, ok := expected[name]; ok {
+			if check, err = sum.hash(); err != nil {
+				return err
+			}
+			w = io.MultiWriter(w, check)
+		}
+
+		if err = src(w); err != nil {
+			return err
+		}
+		sums[name] = h.Sum(nil)
+
+		if check != nil {
+			return expected[name].verify(name, check)
+		}
+		return nil
+	}
+
+	content := func(b []byte) func(io.Writer) error {
+		return func(w io.Writer) error {
+			_, err := w.Write(b)
+			return err
+		}
+	}
+
+	if err = write(descriptor, int64(buf.Len()), content(buf.Bytes())); err != nil {
+		return err
+	}
+
+	if manifest != "" && !param.Manifest {
+		if err = write(manifest, int64(mf.Len()), content(mf.Bytes())); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range names {
+		err = write(name, *info[name].Size, func(w io.Writer) error {
+			return download(name, w)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if manifest == "" || param.Manifest {
+		buf.Reset()
+		for _, name := range append([]string{descriptor}, names...) {
+			fmt.Fprintf(&buf, "SHA256(%s)= %x\n", name, sums[name])
+		}
+		if manifest == "" {
+			manifest = descriptor[:len(descriptor)-len(path.Ext(descriptor))] + ".mf"
+		}
+		if err = write(manifest, int64(buf.Len()), content(buf.Bytes())); err != nil {
+			return err
+		}
+	}
+
+	return out.Close()
+}
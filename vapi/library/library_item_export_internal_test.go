@@ -0,0 +1,59 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package library
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOVFWriterCreate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "govmomi-export")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	w := &ovfWriter{dir: filepath.Join(dir, "ovf")}
+	if err = os.Mkdir(w.dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	for _, name := range []string{"", ".", "..", "../escape.vmdk", "sub/disk.vmdk", `..\escape.vmdk`, "/tmp/escape.vmdk"} {
+		if _, err = w.Create(name, 0); err == nil {
+			t.Errorf("%q: expected error", name)
+		}
+	}
+
+	if _, err = w.Create("disk-0.vmdk", 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = os.Stat(filepath.Join(w.dir, "disk-0.vmdk")); err != nil {
+		t.Error(err)
+	}
+
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 {
+		t.Errorf("files=%d", len(files))
+	}
+}
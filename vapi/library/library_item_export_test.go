@@ -0,0 +1,231 @@
+/*
+Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package library_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/library"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/soap"
+
+	_ "github.com/vmware/govmomi/vapi/simulator"
+)
+
+const testOVF = `<?xml version="1.0" encoding="UTF-8"?>
+<Envelope xmlns="http://schemas.dmtf.org/ovf/envelope/1">
+  <References>
+    <File ovf:href="disk-1.vmdk" ovf:id="file2" xmlns:ovf="http://schemas.dmtf.org/ovf/envelope/1"/>
+    <File ovf:href="disk-0.vmdk" ovf:id="file1" xmlns:ovf="http://schemas.dmtf.org/ovf/envelope/1"/>
+  </References>
+</Envelope>
+`
+
+// newTestItem creates a library item with the given files, returning the item ID.
+func newTestItem(ctx context.Context, t *testing.T, c *rest.Client, files map[string]string) string {
+	m := library.NewManager(c)
+
+	id, err := m.CreateLibrary(ctx, library.Library{
+		Name: "test-library",
+		Type: "LOCAL",
+		Storage: []library.StorageBackings{{
+			DatastoreID: simulator.Map.Any("Datastore").Reference().Value,
+			Type:        "DATASTORE",
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	item, err := m.CreateLibraryItem(ctx, library.Item{Name: "test-item", Type: "ovf", LibraryID: id})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	session, err := m.CreateLibraryItemUpdateSession(ctx, library.Session{LibraryItemID: item})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, content := range files {
+		info, err := m.AddLibraryItemFile(ctx, session, library.UpdateFile{
+			Name:       name,
+			SourceType: "PUSH",
+			Size:       int64(len(content)),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		u, err := url.Parse(info.UploadEndpoint.URI)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p := soap.DefaultUpload
+		p.ContentLength = int64(len(content))
+		if err = c.Upload(ctx, strings.NewReader(content), u, &p); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err = m.CompleteLibraryItemUpdateSession(ctx, session); err != nil {
+		t.Fatal(err)
+	}
+
+	return item
+}
+
+func TestExportOVA(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+		if err := c.Login(ctx, simulator.DefaultLogin); err != nil {
+			t.Fatal(err)
+		}
+
+		files := map[string]string{
+			"test.ovf":    testOVF,
+			"disk-0.vmdk": "disk-0 content",
+			"disk-1.vmdk": "disk-1 content",
+		}
+		item := newTestItem(ctx, t, c, files)
+
+		var buf bytes.Buffer
+		if err := library.NewManager(c).ExportOVA(ctx, item, &buf, nil); err != nil {
+			t.Fatal(err)
+		}
+
+		var names []string
+		var manifest string
+		r := tar.NewReader(&buf)
+		for {
+			h, err := r.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			names = append(names, h.Name)
+
+			var content bytes.Buffer
+			if _, err = io.Copy(&content, r); err != nil {
+				t.Fatal(err)
+			}
+
+			if h.Name == "test.mf" {
+				manifest = content.String()
+				continue
+			}
+			if content.String() != files[h.Name] {
+				t.Errorf("%s: %q", h.Name, content.String())
+			}
+		}
+
+		expect := "test.ovf disk-1.vmdk disk-0.vmdk test.mf"
+		if strings.Join(names, " ") != expect {
+			t.Errorf("names=%s", names)
+		}
+
+		for name, content := range files {
+			line := fmt.Sprintf("SHA256(%s)= %x\n", name, sha256.Sum256([]byte(content)))
+			if !strings.Contains(manifest, line) {
+				t.Errorf("manifest missing %q", line)
+			}
+		}
+	})
+}
+
+func TestExportManifest(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+		if err := c.Login(ctx, simulator.DefaultLogin); err != nil {
+			t.Fatal(err)
+		}
+		m := library.NewManager(c)
+
+		mf := fmt.Sprintf("SHA1(test.ovf)= %x\nSHA1(disk-0.vmdk)= %x\n",
+			sha1.Sum([]byte(testOVF)), sha1.Sum([]byte("other content")))
+		files := map[string]string{
+			"test.ovf":    testOVF,
+			"test.mf":     mf,
+			"disk-0.vmdk": "disk-0 content",
+			"disk-1.vmdk": "disk-1 content",
+		}
+		item := newTestItem(ctx, t, c, files)
+
+		err := m.ExportOVA(ctx, item, ioutil.Discard, nil)
+		var e *library.ChecksumError
+		if !errors.As(err, &e) {
+			t.Fatalf("unexpected error %T: %s", err, err)
+		}
+		if e.Name != "disk-0.vmdk" || e.Expected.Algorithm != "SHA1" {
+			t.Errorf("error=%#v", e)
+		}
+
+		dir, err := ioutil.TempDir("", "govmomi-export")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+
+		// The item's manifest is exported as-is
+		param := &library.Export{Download: library.Download{SkipChecksum: true}}
+		if err = m.ExportOVF(ctx, item, dir, param); err != nil {
+			t.Fatal(err)
+		}
+		for name, content := range files {
+			b, err := ioutil.ReadFile(filepath.Join(dir, name))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != content {
+				t.Errorf("%s: %q", name, b)
+			}
+		}
+
+		// The manifest is regenerated from the exported files
+		param.Manifest = true
+		if err = m.ExportOVF(ctx, item, dir, param); err != nil {
+			t.Fatal(err)
+		}
+		b, err := ioutil.ReadFile(filepath.Join(dir, "test.mf"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		expect := fmt.Sprintf("SHA256(test.ovf)= %x\nSHA256(disk-1.vmdk)= %x\nSHA256(disk-0.vmdk)= %x\n",
+			sha256.Sum256([]byte(testOVF)),
+			sha256.Sum256([]byte(files["disk-1.vmdk"])),
+			sha256.Sum256([]byte(files["disk-0.vmdk"])))
+		if string(b) != expect {
+			t.Errorf("manifest=%s", b)
+		}
+	})
+}
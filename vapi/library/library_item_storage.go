@@ -0,0 +1,105 @@
+/*
+Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package library
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/vapi/internal"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// Storage is the storage information of a library item file.
+// A file can be stored in more than one storage backing.
+type Storage struct {
+	Cached         bool            `json:"cached"`
+	Checksum       *Checksum       `json:"checksum_info,omitempty"`
+	Name           string          `json:"name"`
+	Size           int64           `json:"size"`
+	StorageBacking StorageBackings `json:"storage_backing"`
+	StorageURIs    []string        `json:"storage_uris"`
+	Version        string          `json:"version"`
+}
+
+// ListLibraryItemStorage returns the storage information of all the files for a library item.
+func (c *Manager) ListLibraryItemStorage(ctx context.Context, id string) ([]Storage, error) {
+	url := internal.URL(c, internal.LibraryItemStoragePath).WithParameter("library_item_id", id)
+	var res []Storage
+	return res, c.Do(ctx, url.Request(ctx, http.MethodGet), &res)
+}
+
+// GetLibraryItemStorage returns the storage information of the file with the provided name for a library item.
+func (c *Manager) GetLibraryItemStorage(ctx context.Context, id, fileName string) ([]Storage, error) {
+	url := internal.URL(c, internal.LibraryItemStoragePath).WithID(id).WithAction("get")
+	spec := struct {
+		Name string `json:"file_name"`
+	}{fileName}
+	var res []Storage
+	return res, c.Do(ctx, url.Request(ctx, http.MethodPost, spec), &res)
+}
+
+// ResolveLibraryItemStorage converts the StorageURIs of the given storage with a datastore backing
+// from the "ds://" URL form to a datastore path, for example: "[datastore1] contentlib-$id/$item/disk-0.vmdk".
+// URIs which do not refer to a backing datastore are left as-is.
+func ResolveLibraryItemStorage(ctx context.Context, vc *vim25.Client, storage []Storage) error {
+	var refs []types.ManagedObjectReference
+	seen := make(map[string]bool)
+	for _, s := range storage {
+		id := s.StorageBacking.DatastoreID
+		if s.StorageBacking.Type != "DATASTORE" || id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		refs = append(refs, types.ManagedObjectReference{Type: "Datastore", Value: id})
+	}
+	if len(refs) == 0 {
+		return nil
+	}
+
+	var datastores []mo.Datastore
+	pc := property.DefaultCollector(vc)
+	if err := pc.Retrieve(ctx, refs, []string{"summary"}, &datastores); err != nil {
+		return err
+	}
+
+	prefix := make(map[string]mo.Datastore, len(datastores))
+	for _, ds := range datastores {
+		prefix[ds.Self.Value] = ds
+	}
+
+	for i := range storage {
+		ds, ok := prefix[storage[i].StorageBacking.DatastoreID]
+		if !ok {
+			continue
+		}
+		base := strings.TrimSuffix(strings.TrimPrefix(ds.Summary.Url, "ds://"), "/") + "/"
+		for j, uri := range storage[i].StorageURIs {
+			p := strings.TrimPrefix(uri, "ds://")
+			if strings.HasPrefix(p, base) {
+				storage[i].StorageURIs[j] = (&object.DatastorePath{Datastore: ds.Summary.Name, Path: strings.TrimPrefix(p, base)}).String()
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+/*
+Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package library_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/library"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vim25"
+
+	_ "github.com/vmware/govmomi/vapi/simulator"
+)
+
+func TestLibraryItemStorage(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+		if err := c.Login(ctx, simulator.DefaultLogin); err != nil {
+			t.Fatal(err)
+		}
+		m := library.NewManager(c)
+
+		id := newTestItem(ctx, t, c, map[string]string{
+			"test.ovf":    testOVF,
+			"disk-0.vmdk": "disk-0",
+		})
+
+		storage, err := m.ListLibraryItemStorage(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(storage) != 2 {
+			t.Fatalf("storage=%#v", storage)
+		}
+
+		storage, err = m.GetLibraryItemStorage(ctx, id, "disk-0.vmdk")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(storage) != 1 || storage[0].Size != 6 || len(storage[0].StorageURIs) != 1 {
+			t.Fatalf("storage=%#v", storage)
+		}
+
+		if err = library.ResolveLibraryItemStorage(ctx, vc, storage); err != nil {
+			t.Fatal(err)
+		}
+
+		item, err := m.GetLibraryItem(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ds := simulator.Map.Any("Datastore").(*simulator.Datastore)
+		expect := (&object.DatastorePath{
+			Datastore: ds.Name,
+			Path:      fmt.Sprintf("contentlib-%s/%s/disk-0.vmdk", item.LibraryID, id),
+		}).String()
+		if uri := storage[0].StorageURIs[0]; uri != expect {
+			t.Errorf("uri=%s, expected %s", uri, expect)
+		}
+	})
+}
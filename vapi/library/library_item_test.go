@@ -0,0 +1,89 @@
+/*
+Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package library_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/library"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vim25"
+
+	_ "github.com/vmware/govmomi/vapi/simulator"
+)
+
+func TestFindLibraryItems(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+		if err := c.Login(ctx, simulator.DefaultLogin); err != nil {
+			t.Fatal(err)
+		}
+		m := library.NewManager(c)
+
+		var libs []string
+		for _, name := range []string{"lib1", "lib2"} {
+			id, err := m.CreateLibrary(ctx, library.Library{
+				Name: name,
+				Type: "LOCAL",
+				Storage: []library.StorageBackings{{
+					DatastoreID: simulator.Map.Any("Datastore").Reference().Value,
+					Type:        "DATASTORE",
+				}},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			libs = append(libs, id)
+		}
+
+		items := []library.Item{
+			{Name: "foo", Type: "ovf", LibraryID: libs[0]},
+			{Name: "bar", Type: "iso", LibraryID: libs[0]},
+			{Name: "foo", Type: "ovf", LibraryID: libs[1]},
+		}
+		for _, item := range items {
+			if _, err := m.CreateLibraryItem(ctx, item); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		cached := true
+		tests := []struct {
+			find  library.FindItem
+			count int
+		}{
+			{library.FindItem{}, 3},
+			{library.FindItem{Name: "FOO"}, 2},
+			{library.FindItem{Name: "foo", LibraryID: libs[0]}, 1},
+			{library.FindItem{Type: "iso"}, 1},
+			{library.FindItem{LibraryID: libs[1], Type: "iso"}, 0},
+			{library.FindItem{Cached: &cached}, 0},
+		}
+
+		for i, test := range tests {
+			ids, err := m.FindLibraryItems(ctx, test.find)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(ids) != test.count {
+				t.Errorf("%d: found %d items, expected %d", i, len(ids), test.count)
+			}
+		}
+	})
+}
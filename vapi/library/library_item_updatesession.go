@@ -18,7 +18,9 @@ package library
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/vmware/govmomi/vapi/internal"
@@ -34,6 +36,66 @@ type Session struct {
 	LibraryItemContentVersion string                   `json:"library_item_content_version,omitempty"`
 	LibraryItemID             string                   `json:"library_item_id,omitempty"`
 	State                     string                   `json:"state,omitempty"`
+	PreviewInfo               *PreviewInfo             `json:"preview_info,omitempty"`
+	WarningBehavior           []WarningBehavior        `json:"warning_behavior,omitempty"`
+}
+
+// Update session preview warning types, raised when files are pulled from an HTTPS source.
+const (
+	WarningSelfSignedCertificate  = "SELF_SIGNED_CERTIFICATE"
+	WarningExpiredCertificate     = "EXPIRED_CERTIFICATE"
+	WarningNotYetValidCertificate = "NOT_YET_VALID_CERTIFICATE"
+	WarningUntrustedServer        = "UNTRUSTED_SERVER"
+)
+
+// CertificateWarnings are all of the preview warning types related to the source server certificate.
+var CertificateWarnings = []string{
+	WarningSelfSignedCertificate,
+	WarningExpiredCertificate,
+	WarningNotYetValidCertificate,
+	WarningUntrustedServer,
+}
+
+// CertificateInfo of the source server of an update session file.
+type CertificateInfo struct {
+	Issuer     string `json:"issuer,omitempty"`
+	Subject    string `json:"subject,omitempty"`
+	SelfSigned bool   `json:"self_signed,omitempty"`
+	X509       string `json:"x509,omitempty"`
+}
+
+// PreviewWarning is a warning raised while validating the source of an update session.
+type PreviewWarning struct {
+	Type    string                  `json:"type"`
+	Message rest.LocalizableMessage `json:"message"`
+	Ignored bool                    `json:"ignored"`
+}
+
+// PreviewInfo of an update session.
+// While any Warnings are not Ignored, files pulled from the source are not transferred.
+type PreviewInfo struct {
+	State           string           `json:"state,omitempty"`
+	CertificateInfo *CertificateInfo `json:"certificate_info,omitempty"`
+	Warnings        []PreviewWarning `json:"warnings,omitempty"`
+}
+
+// Pending returns the warnings which have not been ignored.
+func (p *PreviewInfo) Pending() []PreviewWarning {
+	var warnings []PreviewWarning
+	if p != nil {
+		for _, w := range p.Warnings {
+			if !w.Ignored {
+				warnings = append(warnings, w)
+			}
+		}
+	}
+	return warnings
+}
+
+// WarningBehavior specifies if a preview warning type should be ignored.
+type WarningBehavior struct {
+	Type    string `json:"type"`
+	Ignored bool   `json:"ignored"`
 }
 
 // CreateLibraryItemUpdateSession creates a new library item
@@ -43,55 +105,110 @@ func (c *Manager) CreateLibraryItemUpdateSession(ctx context.Context, session Se
 		CreateSpec Session `json:"create_spec"`
 	}{session}
 	var res string
-	return res, c.Do(ctx, url.Request(http.MethodPost, spec), &res)
+	return res, c.Do(ctx, url.Request(ctx, http.MethodPost, spec), &res)
 }
 
 // GetLibraryItemUpdateSession gets the update session information with status
 func (c *Manager) GetLibraryItemUpdateSession(ctx context.Context, id string) (*Session, error) {
 	url := internal.URL(c, internal.LibraryItemUpdateSession).WithID(id)
 	var res Session
-	return &res, c.Do(ctx, url.Request(http.MethodGet), &res)
+	return &res, c.Do(ctx, url.Request(ctx, http.MethodGet), &res)
+}
+
+// UpdateLibraryItemUpdateSession updates the WarningBehavior of an update session.
+func (c *Manager) UpdateLibraryItemUpdateSession(ctx context.Context, id string, behavior []WarningBehavior) error {
+	url := internal.URL(c, internal.LibraryItemUpdateSession).WithID(id)
+	spec := struct {
+		UpdateSpec struct {
+			WarningBehavior []WarningBehavior `json:"warning_behavior"`
+		} `json:"update_spec"`
+	}{}
+	spec.UpdateSpec.WarningBehavior = behavior
+	return c.Do(ctx, url.Request(ctx, http.MethodPatch, spec), nil)
+}
+
+// IgnoreLibraryItemUpdateSessionWarnings acknowledges the given preview warning types of an update session,
+// such that the transfer of files pulled from the source can proceed.
+// If no types are given, the session's pending warnings are ignored.
+// For example, to accept a source server certificate regardless of its validity:
+//
+//	err := m.IgnoreLibraryItemUpdateSessionWarnings(ctx, id, library.CertificateWarnings...)
+func (c *Manager) IgnoreLibraryItemUpdateSessionWarnings(ctx context.Context, id string, types ...string) error {
+	if len(types) == 0 {
+		session, err := c.GetLibraryItemUpdateSession(ctx, id)
+		if err != nil {
+			return err
+		}
+		for _, w := range session.PreviewInfo.Pending() {
+			types = append(types, w.Type)
+		}
+		if len(types) == 0 {
+			return nil
+		}
+	}
+
+	behavior := make([]WarningBehavior, len(types))
+	for i := range types {
+		behavior[i] = WarningBehavior{Type: types[i], Ignored: true}
+	}
+	return c.UpdateLibraryItemUpdateSession(ctx, id, behavior)
 }
 
 // ListLibraryItemUpdateSession gets the list of update sessions
 func (c *Manager) ListLibraryItemUpdateSession(ctx context.Context) ([]string, error) {
 	url := internal.URL(c, internal.LibraryItemUpdateSession)
 	var res []string
-	return res, c.Do(ctx, url.Request(http.MethodGet), &res)
+	return res, c.Do(ctx, url.Request(ctx, http.MethodGet), &res)
 }
 
 // CancelLibraryItemUpdateSession cancels an update session
 func (c *Manager) CancelLibraryItemUpdateSession(ctx context.Context, id string) error {
 	url := internal.URL(c, internal.LibraryItemUpdateSession).WithID(id).WithAction("cancel")
-	return c.Do(ctx, url.Request(http.MethodPost), nil)
+	return c.Do(ctx, url.Request(ctx, http.MethodPost), nil)
 }
 
 // CompleteLibraryItemUpdateSession completes an update session
 func (c *Manager) CompleteLibraryItemUpdateSession(ctx context.Context, id string) error {
 	url := internal.URL(c, internal.LibraryItemUpdateSession).WithID(id).WithAction("complete")
-	return c.Do(ctx, url.Request(http.MethodPost), nil)
+	return c.Do(ctx, url.Request(ctx, http.MethodPost), nil)
 }
 
 // DeleteLibraryItemUpdateSession deletes an update session
 func (c *Manager) DeleteLibraryItemUpdateSession(ctx context.Context, id string) error {
 	url := internal.URL(c, internal.LibraryItemUpdateSession).WithID(id)
-	return c.Do(ctx, url.Request(http.MethodDelete), nil)
+	return c.Do(ctx, url.Request(ctx, http.MethodDelete), nil)
 }
 
 // FailLibraryItemUpdateSession fails an update session
 func (c *Manager) FailLibraryItemUpdateSession(ctx context.Context, id string) error {
 	url := internal.URL(c, internal.LibraryItemUpdateSession).WithID(id).WithAction("fail")
-	return c.Do(ctx, url.Request(http.MethodPost), nil)
+	return c.Do(ctx, url.Request(ctx, http.MethodPost), nil)
 }
 
 // KeepAliveLibraryItemUpdateSession keeps an inactive update session alive.
 func (c *Manager) KeepAliveLibraryItemUpdateSession(ctx context.Context, id string) error {
 	url := internal.URL(c, internal.LibraryItemUpdateSession).WithID(id).WithAction("keep-alive")
-	return c.Do(ctx, url.Request(http.MethodPost), nil)
+	return c.Do(ctx, url.Request(ctx, http.MethodPost), nil)
+}
+
+// PreviewWarningsError is returned by WaitOnLibraryItemUpdateSession when the transfer of an update session
+// is blocked by preview warnings, see IgnoreLibraryItemUpdateSessionWarnings.
+type PreviewWarningsError struct {
+	SessionID string
+	Warnings  []PreviewWarning
+}
+
+func (e *PreviewWarningsError) Error() string {
+	types := make([]string, len(e.Warnings))
+	for i := range e.Warnings {
+		types[i] = e.Warnings[i].Type
+	}
+	return fmt.Sprintf("update session %s has pending preview warnings: %s", e.SessionID, strings.Join(types, ", "))
 }
 
 // WaitOnLibraryItemUpdateSession blocks until the update session is no longer
 // in the ACTIVE state.
+// A *PreviewWarningsError is returned if the session has preview warnings which must be ignored to proceed.
 func (c *Manager) WaitOnLibraryItemUpdateSession(
 	ctx context.Context, sessionID string,
 	interval time.Duration, intervalCallback func()) error {
@@ -103,6 +220,9 @@ func (c *Manager) WaitOnLibraryItemUpdateSession(
 			return err
 		}
 
+		if warnings := session.PreviewInfo.Pending(); len(warnings) != 0 {
+			return &PreviewWarningsError{SessionID: sessionID, Warnings: warnings}
+		}
 		if session.State != "ACTIVE" {
 			if session.State == "ERROR" {
 				return session.ErrorMessage
@@ -123,43 +243,43 @@ func (c *Manager) CreateLibraryItemDownloadSession(ctx context.Context, session
 		CreateSpec Session `json:"create_spec"`
 	}{session}
 	var res string
-	return res, c.Do(ctx, url.Request(http.MethodPost, spec), &res)
+	return res, c.Do(ctx, url.Request(ctx, http.MethodPost, spec), &res)
 }
 
 // GetLibraryItemDownloadSession gets the download session information with status
 func (c *Manager) GetLibraryItemDownloadSession(ctx context.Context, id string) (*Session, error) {
 	url := internal.URL(c, internal.LibraryItemDownloadSession).WithID(id)
 	var res Session
-	return &res, c.Do(ctx, url.Request(http.MethodGet), &res)
+	return &res, c.Do(ctx, url.Request(ctx, http.MethodGet), &res)
 }
 
 // ListLibraryItemDownloadSession gets the list of download sessions
 func (c *Manager) ListLibraryItemDownloadSession(ctx context.Context) ([]string, error) {
 	url := internal.URL(c, internal.LibraryItemDownloadSession)
 	var res []string
-	return res, c.Do(ctx, url.Request(http.MethodGet), &res)
+	return res, c.Do(ctx, url.Request(ctx, http.MethodGet), &res)
 }
 
 // CancelLibraryItemDownloadSession cancels an download session
 func (c *Manager) CancelLibraryItemDownloadSession(ctx context.Context, id string) error {
 	url := internal.URL(c, internal.LibraryItemDownloadSession).WithID(id).WithAction("cancel")
-	return c.Do(ctx, url.Request(http.MethodPost), nil)
+	return c.Do(ctx, url.Request(ctx, http.MethodPost), nil)
 }
 
 // DeleteLibraryItemDownloadSession deletes an download session
 func (c *Manager) DeleteLibraryItemDownloadSession(ctx context.Context, id string) error {
 	url := internal.URL(c, internal.LibraryItemDownloadSession).WithID(id)
-	return c.Do(ctx, url.Request(http.MethodDelete), nil)
+	return c.Do(ctx, url.Request(ctx, http.MethodDelete), nil)
 }
 
 // FailLibraryItemDownloadSession fails an download session
 func (c *Manager) FailLibraryItemDownloadSession(ctx context.Context, id string) error {
 	url := internal.URL(c, internal.LibraryItemDownloadSession).WithID(id).WithAction("fail")
-	return c.Do(ctx, url.Request(http.MethodPost), nil)
+	return c.Do(ctx, url.Request(ctx, http.MethodPost), nil)
 }
 
 // KeepAliveLibraryItemDownloadSession keeps an inactive download session alive.
 func (c *Manager) KeepAliveLibraryItemDownloadSession(ctx context.Context, id string) error {
 	url := internal.URL(c, internal.LibraryItemDownloadSession).WithID(id).WithAction("keep-alive")
-	return c.Do(ctx, url.Request(http.MethodPost), nil)
+	return c.Do(ctx, url.Request(ctx, http.MethodPost), nil)
 }
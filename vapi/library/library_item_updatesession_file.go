@@ -55,7 +55,7 @@ func (c *Manager) AddLibraryItemFile(ctx context.Context, sessionID string, upda
 		FileSpec UpdateFile `json:"file_spec"`
 	}{updateFile}
 	var res UpdateFile
-	err := c.Do(ctx, url.Request(http.MethodPost, spec), &res)
+	err := c.Do(ctx, url.Request(ctx, http.MethodPost, spec), &res)
 	if err != nil {
 		return nil, err
 	}
@@ -99,7 +99,7 @@ func (c *Manager) GetLibraryItemUpdateSessionFile(ctx context.Context, sessionID
 		Name string `json:"file_name"`
 	}{fileName}
 	var res UpdateFile
-	return &res, c.Do(ctx, url.Request(http.MethodPost, spec), &res)
+	return &res, c.Do(ctx, url.Request(ctx, http.MethodPost, spec), &res)
 }
 
 // getContentLengthAndFingerprint gets the number of bytes returned
@@ -114,7 +114,7 @@ func (c *Manager) getContentLengthAndFingerprint(
 	if resp.TLS == nil || len(resp.TLS.PeerCertificates) == 0 {
 		return resp.ContentLength, "", nil
 	}
-	fingerprint := c.Thumbprint(resp.Request.URL.Host)
+	fingerprint := c.ThumbprintSHA1(resp.Request.URL.Host, resp.TLS.PeerCertificates[0])
 	if fingerprint == "" {
 		if c.Transport.(*http.Transport).TLSClientConfig.InsecureSkipVerify {
 			fingerprint = soap.ThumbprintSHA1(resp.TLS.PeerCertificates[0])
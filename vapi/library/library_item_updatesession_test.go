@@ -0,0 +1,198 @@
+/*
+Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package library_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/library"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vim25"
+
+	_ "github.com/vmware/govmomi/vapi/simulator"
+)
+
+func TestUpdateSessionPreviewWarnings(t *testing.T) {
+	source := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(testOVF))
+	}))
+	defer source.Close()
+
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+		if err := c.Login(ctx, simulator.DefaultLogin); err != nil {
+			t.Fatal(err)
+		}
+		m := library.NewManager(c)
+
+		item := newTestItem(ctx, t, c, nil)
+		session, err := m.CreateLibraryItemUpdateSession(ctx, library.Session{LibraryItemID: item})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = m.AddLibraryItemFile(ctx, session, library.UpdateFile{
+			Name:           "pull.ovf",
+			SourceType:     "PULL",
+			SourceEndpoint: &library.TransferEndpoint{URI: source.URL + "/pull.ovf"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = m.WaitOnLibraryItemUpdateSession(ctx, session, 10*time.Millisecond, nil)
+		var e *library.PreviewWarningsError
+		if !errors.As(err, &e) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(e.Warnings) != 1 || e.Warnings[0].Type != library.WarningSelfSignedCertificate {
+			t.Errorf("warnings=%#v", e.Warnings)
+		}
+
+		info, err := m.GetLibraryItemUpdateSession(ctx, session)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.PreviewInfo.CertificateInfo == nil || !info.PreviewInfo.CertificateInfo.SelfSigned {
+			t.Errorf("preview=%#v", info.PreviewInfo)
+		}
+
+		if err = m.IgnoreLibraryItemUpdateSessionWarnings(ctx, session); err != nil {
+			t.Fatal(err)
+		}
+
+		for {
+			file, err := m.GetLibraryItemUpdateSessionFile(ctx, session, "pull.ovf")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if file.Status == "READY" {
+				break
+			}
+			if file.Status == "ERROR" {
+				t.Fatal(file.ErrorMessage)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+
+		info, err = m.GetLibraryItemUpdateSession(ctx, session)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(info.PreviewInfo.Pending()) != 0 {
+			t.Errorf("pending=%#v", info.PreviewInfo.Pending())
+		}
+	})
+}
+
+func TestUpdateSessionState(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+		if err := c.Login(ctx, simulator.DefaultLogin); err != nil {
+			t.Fatal(err)
+		}
+		m := library.NewManager(c)
+
+		id := newTestItem(ctx, t, c, map[string]string{"test.ovf": testOVF})
+		item, err := m.GetLibraryItem(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if item.ContentVersion != "1" || item.Size != int64(len(testOVF)) {
+			t.Errorf("version=%s, size=%d", item.ContentVersion, item.Size)
+		}
+
+		content := "disk content"
+		upload := func(session string) {
+			file := library.UpdateFile{Name: "disk.vmdk", Size: int64(len(content))}
+			if err := m.UploadLibraryItemFile(ctx, session, file, strings.NewReader(content), nil); err != nil {
+				t.Fatal(err)
+			}
+		}
+		state := func(session, expect string) {
+			s, err := m.GetLibraryItemUpdateSession(ctx, session)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s.State != expect {
+				t.Errorf("state=%s, expected %s", s.State, expect)
+			}
+		}
+		files := func() int {
+			f, err := m.ListLibraryItemFiles(ctx, id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return len(f)
+		}
+
+		// Canceling a session removes the files it transferred
+		session, err := m.CreateLibraryItemUpdateSession(ctx, library.Session{LibraryItemID: id})
+		if err != nil {
+			t.Fatal(err)
+		}
+		upload(session)
+		if n := files(); n != 2 {
+			t.Errorf("files=%d", n)
+		}
+		if err = m.CancelLibraryItemUpdateSession(ctx, session); err != nil {
+			t.Fatal(err)
+		}
+		state(session, "CANCELED")
+		if n := files(); n != 1 {
+			t.Errorf("files=%d", n)
+		}
+		if err = m.CompleteLibraryItemUpdateSession(ctx, session); err == nil {
+			t.Error("expected error completing a canceled session")
+		}
+		if _, err = m.AddLibraryItemFile(ctx, session, library.UpdateFile{Name: "x", SourceType: "PUSH"}); err == nil {
+			t.Error("expected error adding a file to a canceled session")
+		}
+
+		// A session cannot be completed until its files are transferred
+		session, err = m.CreateLibraryItemUpdateSession(ctx, library.Session{LibraryItemID: id})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = m.AddLibraryItemFile(ctx, session, library.UpdateFile{Name: "disk.vmdk", SourceType: "PUSH"}); err != nil {
+			t.Fatal(err)
+		}
+		if err = m.CompleteLibraryItemUpdateSession(ctx, session); err == nil {
+			t.Error("expected error completing a session with pending files")
+		}
+		state(session, "ACTIVE")
+		upload(session)
+		if err = m.CompleteLibraryItemUpdateSession(ctx, session); err != nil {
+			t.Fatal(err)
+		}
+		state(session, "DONE")
+
+		if item, err = m.GetLibraryItem(ctx, id); err != nil {
+			t.Fatal(err)
+		}
+		if item.ContentVersion != "2" || item.Size != int64(len(testOVF)+len(content)) {
+			t.Errorf("version=%s, size=%d", item.ContentVersion, item.Size)
+		}
+	})
+}
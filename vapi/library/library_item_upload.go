@@ -0,0 +1,214 @@
+/*
+Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package library
+
+import (
+	"context"
+	"fmt"
+	"hash"
+	"io"
+	"net/url"
+
+	"github.com/vmware/govmomi/units"
+	"github.com/vmware/govmomi/vim25/progress"
+	"github.com/vmware/govmomi/vim25/soap"
+)
+
+// Upload specifies how UploadLibraryItemFile transfers a file.
+type Upload struct {
+	// ChunkSize is the maximum number of bytes sent per request, using the Content-Range header.
+	// If ChunkSize is 0, the file is sent with a single request.
+	ChunkSize int64
+	// Retries is the number of consecutive failed requests that are retried before giving up.
+	// A retry resumes the transfer from the number of bytes the update session reports as transferred.
+	Retries int
+	// Progress, if set, receives progress reports for the entire file transfer.
+	Progress progress.Sinker
+	// SkipChecksum disables verification of the content of r against the file Checksum, if any.
+	// Otherwise a mismatch is returned as a *ChecksumError.
+	SkipChecksum bool
+}
+
+// UploadLibraryItemFile adds the given file to the update session and uploads the content of r.
+// The file Size must be set, the SourceType is set to PUSH.
+// If the file Checksum is set, the checksum of the content is computed during the transfer and verified once complete.
+func (c *Manager) UploadLibraryItemFile(ctx context.Context, sessionID string, file UpdateFile, r io.ReaderAt, param *Upload) error {
+	if param == nil {
+		param = new(Upload)
+	}
+
+	t := &transfer{ctx: ctx, size: file.Size}
+	if file.Checksum != nil && !param.SkipChecksum {
+		if err := t.setChecksum(file.Checksum); err != nil {
+			return err
+		}
+	}
+
+	file.SourceType = "PUSH"
+	info, err := c.AddLibraryItemFile(ctx, sessionID, file)
+	if err != nil {
+		return err
+	}
+
+	u, err := url.Parse(info.UploadEndpoint.URI)
+	if err != nil {
+		return err
+	}
+	if param.Progress != nil {
+		t.ch = param.Progress.Sink()
+	}
+
+	var offset int64
+	retries := param.Retries
+
+	for {
+		n := file.Size - offset
+		if param.ChunkSize > 0 && n > param.ChunkSize {
+			n = param.ChunkSize
+		}
+
+		err = c.uploadChunk(ctx, u, io.NewSectionReader(r, offset, n), offset, n, t)
+		if err == nil {
+			offset += n
+			retries = param.Retries
+			if offset >= file.Size {
+				break
+			}
+			continue
+		}
+
+		if retries == 0 || ctx.Err() != nil {
+			break
+		}
+		retries--
+
+		status, serr := c.GetLibraryItemUpdateSessionFile(ctx, sessionID, file.Name)
+		if serr != nil {
+			break
+		}
+		if status.BytesTransferred <= file.Size {
+			offset = status.BytesTransferred
+		}
+	}
+
+	if err == nil {
+		err = t.check(file.Name)
+	}
+	t.done(err)
+	return err
+}
+
+func (c *Manager) uploadChunk(ctx context.Context, u *url.URL, r io.Reader, offset, n int64, t *transfer) error {
+	p := soap.DefaultUpload
+	p.ContentLength = n
+	if n != t.size {
+		p.Headers = map[string]string{
+			"Content-Range": fmt.Sprintf("bytes %d-%d/%d", offset, offset+n-1, t.size),
+		}
+	}
+
+	t.pos = offset
+	return c.Upload(ctx, &transferReader{r, t}, u, &p)
+}
+
+// transfer tracks the progress of a file transfer that may span multiple requests.
+type transfer struct {
+	ctx  context.Context
+	ch   chan<- progress.Report
+	pos  int64
+	size int64
+
+	checksum *Checksum
+	sum      hash.Hash
+	summed   int64 // number of bytes written to sum
+}
+
+// setChecksum enables computing the checksum of the transferred content, to be verified by check.
+func (t *transfer) setChecksum(c *Checksum) error {
+	sum, err := c.hash()
+	if err != nil {
+		return err
+	}
+	t.checksum, t.sum = c, sum
+	return nil
+}
+
+// check returns a *ChecksumError if the checksum of the transferred content does not match the expected checksum.
+func (t *transfer) check(name string) error {
+	if t.sum == nil {
+		return nil
+	}
+	return t.checksum.verify(name, t.sum)
+}
+
+func (t *transfer) report(err error) {
+	if t.ch == nil {
+		return
+	}
+	select {
+	case t.ch <- transferReport{t.pos, t.size, err}:
+	case <-t.ctx.Done():
+	}
+}
+
+func (t *transfer) done(err error) {
+	if t.ch == nil {
+		return
+	}
+	t.report(err)
+	close(t.ch)
+}
+
+type transferReader struct {
+	r io.Reader
+	t *transfer
+}
+
+func (r *transferReader) Read(b []byte) (int, error) {
+	n, err := r.r.Read(b)
+	start, end := r.t.pos, r.t.pos+int64(n)
+	// A retry may resend content, only content past what was already summed is written to the hash.
+	if r.t.sum != nil && start <= r.t.summed && end > r.t.summed {
+		_, _ = r.t.sum.Write(b[r.t.summed-start : n])
+		r.t.summed = end
+	}
+	r.t.pos = end
+	r.t.report(nil)
+	return n, err
+}
+
+// transferReport implements progress.Report
+type transferReport struct {
+	pos  int64
+	size int64
+	err  error
+}
+
+func (r transferReport) Percentage() float32 {
+	if r.size == 0 {
+		return 100
+	}
+	return 100 * float32(r.pos) / float32(r.size)
+}
+
+func (r transferReport) Detail() string {
+	return fmt.Sprintf("%s/%s", units.ByteSize(r.pos), units.ByteSize(r.size))
+}
+
+func (r transferReport) Error() error {
+	return r.err
+}
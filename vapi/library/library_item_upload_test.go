@@ -0,0 +1,221 @@
+/*
+Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package library_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/library"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/progress"
+)
+
+// flakyTransport fails the nth PUT request.
+type flakyTransport struct {
+	http.RoundTripper
+	sync.Mutex
+	n    int
+	puts int
+}
+
+func (t *flakyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method == http.MethodPut {
+		t.Lock()
+		t.puts++
+		fail := t.puts == t.n
+		t.Unlock()
+		if fail {
+			return nil, errors.New("connection reset")
+		}
+	}
+	return t.RoundTripper.RoundTrip(req)
+}
+
+type progressSink struct {
+	reports []progress.Report
+	done    chan struct{}
+}
+
+func (s *progressSink) Sink() chan<- progress.Report {
+	ch := make(chan progress.Report)
+	s.done = make(chan struct{})
+	go func() {
+		for r := range ch {
+			s.reports = append(s.reports, r)
+		}
+		close(s.done)
+	}()
+	return ch
+}
+
+func TestUploadLibraryItemFile(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		flaky := &flakyTransport{RoundTripper: vc.Client.Transport, n: 2}
+		c := rest.NewClient(vc, rest.WithTransport(flaky))
+		if err := c.Login(ctx, simulator.DefaultLogin); err != nil {
+			t.Fatal(err)
+		}
+		m := library.NewManager(c)
+
+		item := newTestItem(ctx, t, c, nil)
+		session, err := m.CreateLibraryItemUpdateSession(ctx, library.Session{LibraryItemID: item})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		content := strings.Repeat("0123456789", 100)
+		sink := new(progressSink)
+		param := &library.Upload{ChunkSize: 256, Retries: 1, Progress: sink}
+		file := library.UpdateFile{Name: "disk.vmdk", Size: int64(len(content))}
+
+		err = m.UploadLibraryItemFile(ctx, session, file, strings.NewReader(content), param)
+		if err != nil {
+			t.Fatal(err)
+		}
+		<-sink.done
+
+		if flaky.puts != 5 { // 4 chunks + 1 retry
+			t.Errorf("puts=%d", flaky.puts)
+		}
+		last := sink.reports[len(sink.reports)-1]
+		if last.Percentage() != 100 || last.Error() != nil {
+			t.Errorf("progress=%f (%s) %v", last.Percentage(), last.Detail(), last.Error())
+		}
+
+		if err = m.CompleteLibraryItemUpdateSession(ctx, session); err != nil {
+			t.Fatal(err)
+		}
+
+		files, err := m.ListLibraryItemFiles(ctx, item)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(files) != 1 || *files[0].Size != file.Size {
+			t.Fatalf("files=%#v", files)
+		}
+
+		var buf bytes.Buffer
+		dl, err := m.NewDownloadSession(ctx, item, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = dl.Download(ctx, file.Name, &buf, nil)
+		_ = dl.Close(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if buf.String() != content {
+			t.Errorf("content=%q", buf.String())
+		}
+
+		// Without retries the transfer fails
+		flaky.puts, flaky.n = 0, 1
+		session, err = m.CreateLibraryItemUpdateSession(ctx, library.Session{LibraryItemID: item})
+		if err != nil {
+			t.Fatal(err)
+		}
+		param.Retries = 0
+		file.Name = "disk2.vmdk"
+		err = m.UploadLibraryItemFile(ctx, session, file, strings.NewReader(content), param)
+		if err == nil {
+			t.Fatal("expected error")
+		}
+		<-sink.done
+		if last := sink.reports[len(sink.reports)-1]; last.Error() == nil {
+			t.Error("expected progress error")
+		}
+	})
+}
+
+func TestUploadLibraryItemFileChecksum(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		flaky := &flakyTransport{RoundTripper: vc.Client.Transport, n: 2}
+		c := rest.NewClient(vc, rest.WithTransport(flaky))
+		if err := c.Login(ctx, simulator.DefaultLogin); err != nil {
+			t.Fatal(err)
+		}
+		m := library.NewManager(c)
+
+		item := newTestItem(ctx, t, c, nil)
+		session, err := m.CreateLibraryItemUpdateSession(ctx, library.Session{LibraryItemID: item})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		content := strings.Repeat("0123456789", 100)
+		sum := sha256.Sum256([]byte(content))
+		checksum := &library.Checksum{Algorithm: library.ChecksumSHA256, Checksum: hex.EncodeToString(sum[:])}
+		param := &library.Upload{ChunkSize: 256, Retries: 1}
+
+		// The checksum of content resent by a retry is computed once
+		file := library.UpdateFile{Name: "disk.vmdk", Size: int64(len(content)), Checksum: checksum}
+		err = m.UploadLibraryItemFile(ctx, session, file, strings.NewReader(content), param)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		file.Name = "disk2.vmdk"
+		file.Checksum = &library.Checksum{Algorithm: library.ChecksumSHA256, Checksum: "invalid"}
+		err = m.UploadLibraryItemFile(ctx, session, file, strings.NewReader(content), param)
+		var e *library.ChecksumError
+		if !errors.As(err, &e) {
+			t.Fatalf("unexpected error %T: %s", err, err)
+		}
+		if e.Name != file.Name || e.Actual.Checksum != checksum.Checksum {
+			t.Errorf("error=%#v", e)
+		}
+
+		file.Name = "disk3.vmdk"
+		param.SkipChecksum = true
+		err = m.UploadLibraryItemFile(ctx, session, file, strings.NewReader(content), param)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		file.Name = "disk4.vmdk"
+		file.Checksum = &library.Checksum{Algorithm: "CRC32", Checksum: "invalid"}
+		param.SkipChecksum = false
+		err = m.UploadLibraryItemFile(ctx, session, file, strings.NewReader(content), param)
+		if err == nil {
+			t.Error("expected error")
+		}
+
+		if err = m.CompleteLibraryItemUpdateSession(ctx, session); err != nil {
+			t.Fatal(err)
+		}
+
+		files, err := m.ListLibraryItemFiles(ctx, item)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, f := range files {
+			if f.Checksum == nil || f.Checksum.Algorithm != library.ChecksumSHA1 {
+				t.Errorf("file %s checksum=%#v", f.Name, f.Checksum)
+			}
+		}
+	})
+}
@@ -0,0 +1,118 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package library
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/vmware/govmomi/vapi/rest"
+)
+
+// WatchItemSpec configures the behavior of WatchItem.
+type WatchItemSpec struct {
+	// Interval is the delay between polls of the library item.
+	Interval time.Duration
+	// MaxBackoff limits the delay between polls after consecutive errors.
+	// Defaults to 10 times Interval.
+	MaxBackoff time.Duration
+	// Sync triggers a SyncLibraryItem before each poll, such that the ContentVersion
+	// of an on-demand subscribed item reflects that of the publisher.
+	Sync bool
+	// ForceSyncContent is passed to SyncLibraryItem when Sync is true.
+	ForceSyncContent bool
+}
+
+// WatchItemVersion calls WatchItem with the given interval and the default WatchItemSpec options.
+func (c *Manager) WatchItemVersion(ctx context.Context, itemID string, interval time.Duration, fn func(old, new Item)) error {
+	return c.WatchItem(ctx, itemID, WatchItemSpec{Interval: interval}, fn)
+}
+
+// WatchItem polls the library item with the given ID, invoking fn each time the item's ContentVersion changes.
+// Errors are treated as transient, the delay between polls is doubled after each consecutive error,
+// up to spec.MaxBackoff. WatchItem returns nil when ctx is canceled.
+// The error is returned if the item is not found or access to the item is denied.
+// An error is returned if spec.Interval is not positive.
+func (c *Manager) WatchItem(ctx context.Context, itemID string, spec WatchItemSpec, fn func(old, new Item)) error {
+	if spec.Interval <= 0 {
+		return fmt.Errorf("invalid WatchItemSpec.Interval: %s", spec.Interval)
+	}
+
+	maxBackoff := spec.MaxBackoff
+	if maxBackoff == 0 {
+		maxBackoff = spec.Interval * 10
+	}
+
+	var current *Item
+	delay := time.Duration(0) // first poll is immediate
+
+	for {
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+
+		item, err := c.pollItem(ctx, itemID, spec)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			if !isTransientError(err) {
+				return err
+			}
+			if delay < spec.Interval {
+				delay = spec.Interval
+			}
+			delay *= 2
+			if delay > maxBackoff {
+				delay = maxBackoff
+			}
+			continue
+		}
+
+		delay = spec.Interval
+
+		if current != nil && current.ContentVersion != item.ContentVersion {
+			fn(*current, *item)
+		}
+		current = item
+	}
+}
+
+func (c *Manager) pollItem(ctx context.Context, id string, spec WatchItemSpec) (*Item, error) {
+	if spec.Sync {
+		if err := c.SyncLibraryItem(ctx, &Item{ID: id}, spec.ForceSyncContent); err != nil {
+			return nil, err
+		}
+	}
+	return c.GetLibraryItem(ctx, id)
+}
+
+// isTransientError returns false for errors that will not go away by polling again.
+func isTransientError(err error) bool {
+	for _, code := range []int{http.StatusNotFound, http.StatusUnauthorized, http.StatusForbidden} {
+		if rest.IsStatusError(err, code) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,166 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package library
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vmware/govmomi/vapi/internal"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/soap"
+)
+
+// itemServer is a stub vAPI endpoint that advances an item's content version every few polls.
+type itemServer struct {
+	sync.Mutex
+	polls int
+	syncs int
+	fails int
+	// status, if non-zero, is returned for all polls after the first
+	status int
+}
+
+func (s *itemServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.Lock()
+	defer s.Unlock()
+
+	switch {
+	case strings.HasPrefix(r.URL.Path, internal.Path+internal.SubscribedLibraryItem):
+		s.syncs++
+		w.WriteHeader(http.StatusOK)
+	case strings.HasPrefix(r.URL.Path, internal.Path+internal.LibraryItemPath):
+		if s.fails > 0 {
+			s.fails--
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if s.status != 0 && s.polls != 0 {
+			w.WriteHeader(s.status)
+			return
+		}
+		s.polls++
+		item := Item{ID: "item1", ContentVersion: strconv.Itoa(1 + s.polls/3)}
+		_ = json.NewEncoder(w).Encode(struct {
+			Value Item `json:"value"`
+		}{item})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func newTestManager(t *testing.T, h http.Handler) (*Manager, func()) {
+	s := httptest.NewServer(h)
+	u, err := url.Parse(s.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vc := &vim25.Client{Client: soap.NewClient(u, true)}
+	return NewManager(rest.NewClient(vc)), s.Close
+}
+
+func TestWatchItemVersion(t *testing.T) {
+	h := &itemServer{fails: 2}
+	m, done := newTestManager(t, h)
+	defer done()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var versions []string
+	err := m.WatchItem(ctx, "item1", WatchItemSpec{Interval: time.Millisecond, Sync: true}, func(old, new Item) {
+		if old.ContentVersion == new.ContentVersion {
+			t.Errorf("unchanged version: %s", old.ContentVersion)
+		}
+		versions = append(versions, new.ContentVersion)
+		if len(versions) == 2 {
+			cancel()
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(versions) != 2 || versions[0] != "2" || versions[1] != "3" {
+		t.Errorf("versions=%v", versions)
+	}
+
+	h.Lock()
+	defer h.Unlock()
+	if h.syncs < h.polls {
+		t.Errorf("syncs=%d, polls=%d", h.syncs, h.polls)
+	}
+}
+
+func TestWatchItemVersionCancel(t *testing.T) {
+	m, done := newTestManager(t, &itemServer{})
+	defer done()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := m.WatchItemVersion(ctx, "item1", time.Hour, func(Item, Item) {
+		t.Error("unexpected callback")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWatchItemInvalidInterval(t *testing.T) {
+	m, done := newTestManager(t, &itemServer{})
+	defer done()
+
+	for _, interval := range []time.Duration{0, -time.Second} {
+		err := m.WatchItemVersion(context.Background(), "item1", interval, func(Item, Item) {
+			t.Error("unexpected callback")
+		})
+		if err == nil {
+			t.Errorf("interval=%s: expected error", interval)
+		}
+	}
+}
+
+func TestWatchItemDeleted(t *testing.T) {
+	for _, status := range []int{http.StatusNotFound, http.StatusUnauthorized, http.StatusForbidden} {
+		m, done := newTestManager(t, &itemServer{fails: 1, status: status})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+
+		err := m.WatchItemVersion(ctx, "item1", time.Millisecond, func(Item, Item) {
+			t.Error("unexpected callback")
+		})
+		if !rest.IsStatusError(err, status) {
+			t.Errorf("status=%d: err=%v", status, err)
+		}
+		if ctx.Err() != nil {
+			t.Errorf("status=%d: %s", status, ctx.Err())
+		}
+
+		cancel()
+		done()
+	}
+}
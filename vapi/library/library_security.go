@@ -0,0 +1,63 @@
+/*
+Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package library
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/vmware/govmomi/vapi/internal"
+)
+
+// Library item certificate verification status
+const (
+	CertificateNotAvailable           = "NOT_AVAILABLE"
+	CertificateVerified               = "VERIFIED"
+	CertificateInternal               = "INTERNAL"
+	CertificateVerificationFailure    = "VERIFICATION_FAILURE"
+	CertificateVerificationInProgress = "VERIFICATION_IN_PROGRESS"
+	CertificateUntrusted              = "UNTRUSTED"
+)
+
+// CertificateVerification is the signing certificate verification info of a library item,
+// which is only verified for items in a library with a security policy.
+type CertificateVerification struct {
+	Status    string   `json:"status"`
+	CertChain []string `json:"cert_chain,omitempty"`
+}
+
+// SecurityPolicy is a content library security policy, which can be applied to a library using its SecurityPolicyID.
+// ItemTypeRules maps a library item type to the rule applied to items of that type, for example: "ovf" is "OVF_STRICT_VERIFICATION".
+type SecurityPolicy struct {
+	Policy        string            `json:"policy"`
+	Name          string            `json:"name"`
+	ItemTypeRules map[string]string `json:"item_type_rules"`
+}
+
+// ListSecurityPolicies returns the content library security policies (vSphere 7.0U3+).
+func (c *Manager) ListSecurityPolicies(ctx context.Context) ([]SecurityPolicy, error) {
+	url := internal.URL(c, internal.SecurityPoliciesPath)
+	var res []SecurityPolicy
+	return res, c.Do(ctx, url.Request(ctx, http.MethodGet), &res)
+}
+
+// VerifyLibraryItem requests verification of the signing certificate of a library item in a library with a security policy.
+// Verification is asynchronous, the result is reported by the item's CertificateVerification and SecurityCompliance.
+func (c *Manager) VerifyLibraryItem(ctx context.Context, id string) error {
+	url := internal.URL(c, internal.LibraryItemPath).WithID(id).WithAction("verify")
+	return c.Do(ctx, url.Request(ctx, http.MethodPost), nil)
+}
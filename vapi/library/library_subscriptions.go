@@ -0,0 +1,155 @@
+/*
+Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package library
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/vmware/govmomi/vapi/internal"
+)
+
+// Subscription locations
+const (
+	SubscriptionLocationLocal  = "LOCAL"  // The subscribed library is on the same vCenter as the published library
+	SubscriptionLocationRemote = "REMOTE" // The subscribed library is on another vCenter
+)
+
+// Subscription targets
+const (
+	SubscriptionTargetCreateNew   = "CREATE_NEW"   // Create a new subscribed library
+	SubscriptionTargetUseExisting = "USE_EXISTING" // Use an existing subscribed library
+)
+
+// Placement specifies where virtual machine templates of a subscribed library are placed.
+type Placement struct {
+	Folder       string `json:"folder,omitempty"`
+	Cluster      string `json:"cluster,omitempty"`
+	ResourcePool string `json:"resource_pool,omitempty"`
+	Host         string `json:"host,omitempty"`
+	Network      string `json:"network,omitempty"`
+}
+
+// Vcenter identifies the vCenter where a subscribed library exists.
+type Vcenter struct {
+	Hostname   string `json:"hostname"`
+	Port       int    `json:"https_port,omitempty"`
+	ServerGUID string `json:"server_guid,omitempty"`
+}
+
+// NewSubscribedLibrary is the specification of a subscribed library created by CreateSubscriber.
+type NewSubscribedLibrary struct {
+	Name                 string            `json:"name"`
+	Description          string            `json:"description,omitempty"`
+	StorageBackings      []StorageBackings `json:"storage_backings"`
+	AutomaticSyncEnabled bool              `json:"automatic_sync_enabled"`
+	OnDemand             bool              `json:"on_demand"`
+}
+
+// SubscriberLibrary is the specification of the subscribed library associated with a subscription.
+type SubscriberLibrary struct {
+	Target               string                `json:"target"`
+	NewSubscribedLibrary *NewSubscribedLibrary `json:"new_subscribed_library,omitempty"` // Required if Target is CREATE_NEW
+	LibraryID            string                `json:"subscribed_library,omitempty"`     // Required if Target is USE_EXISTING
+	Location             string                `json:"location"`
+	Vcenter              *Vcenter              `json:"vcenter,omitempty"` // Required if Location is REMOTE
+	Placement            *Placement            `json:"placement,omitempty"`
+}
+
+// SubscriberSummary is a subscription of a published library, as returned by ListSubscribers.
+type SubscriberSummary struct {
+	SubscriptionID  string `json:"subscription"`
+	LibraryID       string `json:"subscribed_library"`
+	LibraryName     string `json:"subscribed_library_name"`
+	VcenterHostname string `json:"subscribed_library_vcenter_hostname,omitempty"`
+}
+
+// Subscriber is the subscribed library of a subscription, as returned by GetSubscriber.
+type Subscriber struct {
+	LibraryID   string     `json:"subscribed_library"`
+	LibraryName string     `json:"subscribed_library_name"`
+	Location    string     `json:"subscribed_library_location"`
+	Vcenter     *Vcenter   `json:"subscribed_library_vcenter,omitempty"`
+	Placement   *Placement `json:"subscribed_library_placement,omitempty"`
+}
+
+type subscriptionID struct {
+	ID string `json:"subscription"`
+}
+
+func subscriptionIDs(ids []string) []subscriptionID {
+	res := make([]subscriptionID, len(ids))
+	for i := range ids {
+		res[i].ID = ids[i]
+	}
+	return res
+}
+
+// CreateSubscriber creates a subscription of the given published library, returning the subscription ID.
+func (c *Manager) CreateSubscriber(ctx context.Context, library *Library, s SubscriberLibrary) (string, error) {
+	var spec struct {
+		Spec struct {
+			Library SubscriberLibrary `json:"subscribed_library"`
+		} `json:"spec"`
+	}
+	spec.Spec.Library = s
+	url := internal.URL(c, internal.Subscriptions).WithID(library.ID)
+	var res string
+	return res, c.Do(ctx, url.Request(ctx, http.MethodPost, spec), &res)
+}
+
+// ListSubscribers returns the subscriptions of the given published library.
+func (c *Manager) ListSubscribers(ctx context.Context, library *Library) ([]SubscriberSummary, error) {
+	url := internal.URL(c, internal.Subscriptions).WithParameter("library", library.ID)
+	var res []SubscriberSummary
+	return res, c.Do(ctx, url.Request(ctx, http.MethodGet), &res)
+}
+
+// GetSubscriber returns the subscribed library of the given subscription.
+func (c *Manager) GetSubscriber(ctx context.Context, library *Library, subscription string) (*Subscriber, error) {
+	url := internal.URL(c, internal.Subscriptions).WithID(library.ID).WithAction("get")
+	var res Subscriber
+	return &res, c.Do(ctx, url.Request(ctx, http.MethodPost, subscriptionID{subscription}), &res)
+}
+
+// DeleteSubscriber deletes the given subscription, the subscribed library itself is not deleted.
+func (c *Manager) DeleteSubscriber(ctx context.Context, library *Library, subscription string) error {
+	url := internal.URL(c, internal.Subscriptions).WithID(library.ID).WithAction("delete")
+	return c.Do(ctx, url.Request(ctx, http.MethodPost, subscriptionID{subscription}), nil)
+}
+
+// PublishLibrary pushes the content of the given published library to its subscribers.
+// If no subscription IDs are given, the library is published to all of its subscribers.
+func (c *Manager) PublishLibrary(ctx context.Context, library *Library, subscriptions ...string) error {
+	spec := struct {
+		Subscriptions []subscriptionID `json:"subscriptions,omitempty"`
+	}{subscriptionIDs(subscriptions)}
+	url := internal.URL(c, internal.LocalLibraryPath).WithID(library.ID).WithAction("publish")
+	return c.Do(ctx, url.Request(ctx, http.MethodPost, spec), nil)
+}
+
+// PublishLibraryItem pushes the given item of a published library to its subscribers.
+// If force is true, the item's content is synchronized even if subscribers download content on demand.
+// If no subscription IDs are given, the item is published to all of its library's subscribers.
+func (c *Manager) PublishLibraryItem(ctx context.Context, item *Item, force bool, subscriptions ...string) error {
+	spec := struct {
+		Force         bool             `json:"force_sync_content"`
+		Subscriptions []subscriptionID `json:"subscriptions,omitempty"`
+	}{force, subscriptionIDs(subscriptions)}
+	url := internal.URL(c, internal.LibraryItemPath).WithID(item.ID).WithAction("publish")
+	return c.Do(ctx, url.Request(ctx, http.MethodPost, spec), nil)
+}
@@ -0,0 +1,183 @@
+/*
+Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package library_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/library"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vim25"
+
+	_ "github.com/vmware/govmomi/vapi/simulator"
+)
+
+func TestSubscriptions(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+		if err := c.Login(ctx, simulator.DefaultLogin); err != nil {
+			t.Fatal(err)
+		}
+		m := library.NewManager(c)
+
+		storage := []library.StorageBackings{{
+			DatastoreID: simulator.Map.Any("Datastore").Reference().Value,
+			Type:        "DATASTORE",
+		}}
+
+		id := newTestItem(ctx, t, c, map[string]string{"test.ovf": testOVF})
+		item, err := m.GetLibraryItem(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pub := &library.Library{ID: item.LibraryID}
+
+		spec := library.SubscriberLibrary{
+			Target:   library.SubscriptionTargetCreateNew,
+			Location: library.SubscriptionLocationLocal,
+			NewSubscribedLibrary: &library.NewSubscribedLibrary{
+				Name:                 "subscriber",
+				StorageBackings:      storage,
+				AutomaticSyncEnabled: true,
+			},
+		}
+
+		if _, err = m.CreateSubscriber(ctx, pub, spec); err == nil {
+			t.Fatal("expected error, library is not published")
+		}
+
+		published := true
+		if err = m.UpdateLibrary(ctx, &library.Library{ID: pub.ID, Publish: &library.Publish{Published: &published}}); err != nil {
+			t.Fatal(err)
+		}
+
+		local, err := m.CreateSubscriber(ctx, pub, spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		sub, err := m.GetSubscriber(ctx, pub, local)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sub.LibraryName != "subscriber" || sub.Location != library.SubscriptionLocationLocal {
+			t.Errorf("subscriber=%#v", sub)
+		}
+
+		// The published item content is pushed to the new subscribed library
+		items, err := m.FindLibraryItems(ctx, library.FindItem{LibraryID: sub.LibraryID, SourceID: item.ID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(items) != 1 {
+			t.Fatalf("items=%v", items)
+		}
+		session, err := m.NewDownloadSession(ctx, items[0], nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		err = session.Download(ctx, "test.ovf", &buf, nil)
+		_ = session.Close(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if buf.String() != testOVF {
+			t.Errorf("content=%q", buf.String())
+		}
+
+		// Content of an on-demand subscriber is only synchronized when forced
+		spec.NewSubscribedLibrary.Name = "on-demand"
+		spec.NewSubscribedLibrary.OnDemand = true
+		onDemand, err := m.CreateSubscriber(ctx, pub, spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sub, err = m.GetSubscriber(ctx, pub, onDemand); err != nil {
+			t.Fatal(err)
+		}
+		cached := func() bool {
+			ids, err := m.FindLibraryItems(ctx, library.FindItem{LibraryID: sub.LibraryID, SourceID: item.ID})
+			if err != nil || len(ids) != 1 {
+				t.Fatalf("items=%v: %v", ids, err)
+			}
+			i, err := m.GetLibraryItem(ctx, ids[0])
+			if err != nil {
+				t.Fatal(err)
+			}
+			return i.Cached
+		}
+		if err = m.PublishLibrary(ctx, pub, onDemand); err != nil {
+			t.Fatal(err)
+		}
+		if cached() {
+			t.Error("on-demand item is cached")
+		}
+		if err = m.PublishLibraryItem(ctx, item, true, onDemand); err != nil {
+			t.Fatal(err)
+		}
+		if !cached() {
+			t.Error("forced item is not cached")
+		}
+
+		// Remote subscribers require a vCenter
+		spec.Location = library.SubscriptionLocationRemote
+		_, err = m.CreateSubscriber(ctx, pub, spec)
+		var e *rest.InvalidArgument
+		if !errors.As(err, &e) {
+			t.Fatalf("unexpected error %T: %s", err, err)
+		}
+		spec.Vcenter = &library.Vcenter{Hostname: "vc2.example.com", Port: 443}
+		remote, err := m.CreateSubscriber(ctx, pub, spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		subs, err := m.ListSubscribers(ctx, pub)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(subs) != 3 {
+			t.Fatalf("subscribers=%#v", subs)
+		}
+		for _, s := range subs {
+			if s.SubscriptionID == remote && s.VcenterHostname != spec.Vcenter.Hostname {
+				t.Errorf("remote=%#v", s)
+			}
+		}
+
+		if err = m.PublishLibrary(ctx, pub); err != nil {
+			t.Fatal(err)
+		}
+		if err = m.PublishLibrary(ctx, pub, "invalid"); err == nil {
+			t.Error("expected error")
+		}
+
+		for _, id := range []string{local, onDemand, remote} {
+			if err = m.DeleteSubscriber(ctx, pub, id); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if subs, err = m.ListSubscribers(ctx, pub); err != nil || len(subs) != 0 {
+			t.Errorf("subscribers=%#v: %v", subs, err)
+		}
+	})
+}
@@ -0,0 +1,115 @@
+/*
+Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package library_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/library"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vim25"
+
+	_ "github.com/vmware/govmomi/vapi/simulator"
+)
+
+func TestSyncLibrary(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+		if err := c.Login(ctx, simulator.DefaultLogin); err != nil {
+			t.Fatal(err)
+		}
+		m := library.NewManager(c)
+
+		storage := []library.StorageBackings{{
+			DatastoreID: simulator.Map.Any("Datastore").Reference().Value,
+			Type:        "DATASTORE",
+		}}
+
+		local, err := m.CreateLibrary(ctx, library.Library{Name: "local", Type: "LOCAL", Storage: storage})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = m.SyncLibrary(ctx, &library.Library{ID: local}); err == nil {
+			t.Error("expected error syncing a local library")
+		}
+
+		onDemand := true
+		id, err := m.CreateLibrary(ctx, library.Library{
+			Name:    "subscribed",
+			Type:    "SUBSCRIBED",
+			Storage: storage,
+			Subscription: &library.Subscription{
+				AuthenticationMethod: "NONE",
+				OnDemand:             &onDemand,
+				SubscriptionURL:      "https://127.0.0.1/cls/vcsp/lib/0/lib.json",
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		itemID, err := m.CreateLibraryItem(ctx, library.Item{Name: "item", Type: "ovf", LibraryID: id})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		lib, err := m.GetLibraryByID(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if lib.LastSyncTime != nil {
+			t.Errorf("LastSyncTime=%s", lib.LastSyncTime)
+		}
+
+		if err = m.SyncLibrary(ctx, lib); err != nil {
+			t.Fatal(err)
+		}
+		synced, err := m.WaitForLibrarySync(ctx, lib, time.Millisecond)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if synced.LastSyncTime == nil {
+			t.Fatal("LastSyncTime not set")
+		}
+
+		tests := []struct {
+			op     func(*library.Item) error
+			cached bool
+		}{
+			{func(i *library.Item) error { return m.SyncLibraryItem(ctx, i, false) }, false},
+			{func(i *library.Item) error { return m.SyncLibraryItem(ctx, i, true) }, true},
+			{func(i *library.Item) error { return m.EvictLibraryItem(ctx, i) }, false},
+			{func(i *library.Item) error { return m.SyncLibraryItem(ctx, i, true) }, true},
+			{func(*library.Item) error { return m.EvictSubscribedLibrary(ctx, lib) }, false},
+		}
+
+		for i, test := range tests {
+			if err = test.op(&library.Item{ID: itemID}); err != nil {
+				t.Fatalf("%d: %s", i, err)
+			}
+			item, err := m.GetLibraryItem(ctx, itemID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if item.Cached != test.cached || item.LastSyncTime == nil {
+				t.Errorf("%d: cached=%t, last_sync_time=%v", i, item.Cached, item.LastSyncTime)
+			}
+		}
+	})
+}
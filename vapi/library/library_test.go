@@ -0,0 +1,165 @@
+/*
+Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package library_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/library"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vim25"
+
+	_ "github.com/vmware/govmomi/vapi/simulator"
+)
+
+func TestPublishLibrary(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+		if err := c.Login(ctx, simulator.DefaultLogin); err != nil {
+			t.Fatal(err)
+		}
+		m := library.NewManager(c)
+
+		id, err := m.CreateLibrary(ctx, library.Library{
+			Name: "published",
+			Type: "LOCAL",
+			Storage: []library.StorageBackings{{
+				DatastoreID: simulator.Map.Any("Datastore").Reference().Value,
+				Type:        "DATASTORE",
+			}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err = m.GetLibraryPublishInfo(ctx, id); err == nil {
+			t.Error("expected error")
+		}
+
+		published := true
+		err = m.UpdateLibrary(ctx, &library.Library{
+			ID: id,
+			Publish: &library.Publish{
+				AuthenticationMethod: "BASIC",
+				UserName:             "vcsp",
+				Password:             "secret",
+				Published:            &published,
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		info, err := m.GetLibraryPublishInfo(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasSuffix(info.PublishURL, "/"+id+"/lib.json") || info.AuthenticationMethod != "BASIC" || info.UserName != "vcsp" {
+			t.Errorf("info=%#v", info)
+		}
+		if info.Password != "" {
+			t.Error("password returned")
+		}
+
+		rotate := &library.Library{ID: id, Publish: &library.Publish{CurrentPassword: "invalid", Password: "new-secret"}}
+		err = m.UpdateLibrary(ctx, rotate)
+		var e *rest.Unauthorized
+		if !errors.As(err, &e) {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		rotate.Publish.CurrentPassword = "secret"
+		if err = m.UpdateLibrary(ctx, rotate); err != nil {
+			t.Fatal(err)
+		}
+
+		published = false
+		if err = m.UpdateLibrary(ctx, &library.Library{ID: id, Publish: &library.Publish{Published: &published}}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err = m.GetLibraryPublishInfo(ctx, id); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
+
+func TestSecurityPolicy(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+		if err := c.Login(ctx, simulator.DefaultLogin); err != nil {
+			t.Fatal(err)
+		}
+		m := library.NewManager(c)
+
+		policies, err := m.ListSecurityPolicies(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(policies) != 1 || policies[0].ItemTypeRules["ovf"] == "" {
+			t.Fatalf("policies=%#v", policies)
+		}
+		policy := policies[0].Policy
+
+		id := newTestItem(ctx, t, c, map[string]string{"test.ovf": testOVF})
+		if err = m.VerifyLibraryItem(ctx, id); err == nil {
+			t.Error("expected error, library has no security policy")
+		}
+
+		item, err := m.GetLibraryItem(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if item.CertificateVerification != nil || item.SecurityCompliance != nil {
+			t.Errorf("item=%#v", item)
+		}
+
+		lib := &library.Library{ID: item.LibraryID, SecurityPolicyID: "enoent"}
+		if err = m.UpdateLibrary(ctx, lib); err == nil {
+			t.Error("expected error, invalid policy")
+		}
+		lib.SecurityPolicyID = policy
+		if err = m.UpdateLibrary(ctx, lib); err != nil {
+			t.Fatal(err)
+		}
+
+		if err = m.VerifyLibraryItem(ctx, id); err != nil {
+			t.Fatal(err)
+		}
+		item, err = m.GetLibraryItem(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if item.CertificateVerification.Status != library.CertificateVerificationFailure || *item.SecurityCompliance {
+			t.Errorf("item=%#v", item)
+		}
+
+		if err = m.UpdateLibrary(ctx, &library.Library{ID: item.LibraryID, UnsetSecurityPolicyID: true}); err != nil {
+			t.Fatal(err)
+		}
+		l, err := m.GetLibraryByID(ctx, item.LibraryID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if l.SecurityPolicyID != "" {
+			t.Errorf("policy=%s", l.SecurityPolicyID)
+		}
+	})
+}
@@ -0,0 +1,87 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package namespace
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/vmware/govmomi/vapi/internal"
+)
+
+// Access subject types
+const (
+	SubjectTypeUser  = "USER"
+	SubjectTypeGroup = "GROUP"
+)
+
+// Namespace access roles
+const (
+	RoleOwner = "OWNER"
+	RoleEdit  = "EDIT"
+	RoleView  = "VIEW"
+)
+
+// Access grants a Role on a namespace to a user or group Subject of the given identity source Domain,
+// for example: "devops" of "vsphere.local".
+type Access struct {
+	SubjectType string `json:"subject_type"`
+	Subject     string `json:"subject"`
+	Domain      string `json:"domain"`
+	Role        string `json:"role"`
+}
+
+// AccessInfo is the role of a subject on a namespace.
+// Inherited is true if the role is granted via vCenter Single Sign-On group membership.
+type AccessInfo struct {
+	Role      string `json:"role"`
+	Inherited bool   `json:"inherited"`
+}
+
+func (c *Manager) access(name string, access Access) *internal.Resource {
+	return internal.URL(c, internal.NamespacesInstancesPath).
+		WithSubpath(name).WithSubpath("access").
+		WithSubpath(access.Domain).WithSubpath(access.Subject).
+		WithParameter("type", access.SubjectType)
+}
+
+// GetNamespaceAccess returns the role of the given subject on the namespace, the access Role field is ignored.
+func (c *Manager) GetNamespaceAccess(ctx context.Context, name string, access Access) (*AccessInfo, error) {
+	var res AccessInfo
+	return &res, c.Do(ctx, c.access(name, access).Request(ctx, http.MethodGet), &res)
+}
+
+// CreateNamespaceAccess grants the access Role on the namespace to the given subject.
+func (c *Manager) CreateNamespaceAccess(ctx context.Context, name string, access Access) error {
+	spec := struct {
+		Role string `json:"role"`
+	}{access.Role}
+	return c.Do(ctx, c.access(name, access).Request(ctx, http.MethodPost, spec), nil)
+}
+
+// SetNamespaceAccess changes the role of the given subject on the namespace to the access Role.
+func (c *Manager) SetNamespaceAccess(ctx context.Context, name string, access Access) error {
+	spec := struct {
+		Role string `json:"role"`
+	}{access.Role}
+	return c.Do(ctx, c.access(name, access).Request(ctx, http.MethodPut, spec), nil)
+}
+
+// DeleteNamespaceAccess revokes the role of the given subject on the namespace, the access Role field is ignored.
+func (c *Manager) DeleteNamespaceAccess(ctx context.Context, name string, access Access) error {
+	return c.Do(ctx, c.access(name, access).Request(ctx, http.MethodDelete), nil)
+}
@@ -0,0 +1,127 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package namespace_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/namespace"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vim25"
+
+	_ "github.com/vmware/govmomi/vapi/simulator"
+)
+
+// jsonContentType fails the test if a PUT request is sent without the JSON Content-Type.
+func jsonContentType(t *testing.T) rest.Middleware {
+	return func(next rest.Handler) rest.Handler {
+		return func(ctx context.Context, req *http.Request, resBody interface{}) error {
+			if req.Method == http.MethodPut && req.Header.Get("Content-Type") != "application/json" {
+				t.Errorf("PUT %s: Content-Type=%q", req.URL.Path, req.Header.Get("Content-Type"))
+			}
+			return next(ctx, req, resBody)
+		}
+	}
+}
+
+func TestNamespaceAccess(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc, rest.WithMiddleware(jsonContentType(t)))
+		if err := c.Login(ctx, simulator.DefaultLogin); err != nil {
+			t.Fatal(err)
+		}
+
+		m := namespace.NewManager(c)
+
+		owner := namespace.Access{
+			SubjectType: namespace.SubjectTypeUser,
+			Subject:     "Administrator",
+			Domain:      "vsphere.local",
+			Role:        namespace.RoleOwner,
+		}
+		spec := namespace.CreateSpec{
+			Cluster:    "domain-c8",
+			Namespace:  "prod",
+			AccessList: []namespace.Access{owner},
+		}
+		if err := m.CreateNamespace(ctx, spec); err != nil {
+			t.Fatal(err)
+		}
+
+		devops := namespace.Access{
+			SubjectType: namespace.SubjectTypeGroup,
+			Subject:     "devops",
+			Domain:      "vsphere.local",
+			Role:        namespace.RoleView,
+		}
+		if err := m.CreateNamespaceAccess(ctx, spec.Namespace, devops); err != nil {
+			t.Fatal(err)
+		}
+
+		var ae *rest.AlreadyExists
+		if err := m.CreateNamespaceAccess(ctx, spec.Namespace, devops); !errors.As(err, &ae) {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		var ia *rest.InvalidArgument
+		invalid := devops
+		invalid.Role = "ADMIN"
+		if err := m.SetNamespaceAccess(ctx, spec.Namespace, invalid); !errors.As(err, &ia) {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		devops.Role = namespace.RoleEdit
+		if err := m.SetNamespaceAccess(ctx, spec.Namespace, devops); err != nil {
+			t.Fatal(err)
+		}
+
+		access, err := m.GetNamespaceAccess(ctx, spec.Namespace, devops)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if access.Role != namespace.RoleEdit {
+			t.Errorf("access=%#v", access)
+		}
+
+		// the same subject name as a USER is a different subject
+		var nf *rest.NotFound
+		user := devops
+		user.SubjectType = namespace.SubjectTypeUser
+		if _, err = m.GetNamespaceAccess(ctx, spec.Namespace, user); !errors.As(err, &nf) {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		info, err := m.GetNamespace(ctx, spec.Namespace)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(info.AccessList) != 2 || info.AccessList[0] != owner || info.AccessList[1] != devops {
+			t.Errorf("access list=%#v", info.AccessList)
+		}
+
+		if err = m.DeleteNamespaceAccess(ctx, spec.Namespace, devops); err != nil {
+			t.Fatal(err)
+		}
+		if _, err = m.GetNamespaceAccess(ctx, spec.Namespace, devops); !errors.As(err, &nf) {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
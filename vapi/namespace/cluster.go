@@ -0,0 +1,169 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package namespace
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/vmware/govmomi/vapi/internal"
+	"github.com/vmware/govmomi/vapi/rest"
+)
+
+// Supervisor control plane size hints
+const (
+	SizeTiny   = "TINY"
+	SizeSmall  = "SMALL"
+	SizeMedium = "MEDIUM"
+	SizeLarge  = "LARGE"
+)
+
+// Supervisor network providers
+const (
+	NetworkProviderNSXT    = "NSXT_CONTAINER_PLUGIN"
+	NetworkProviderVSphere = "VSPHERE_NETWORK"
+)
+
+// Control plane management network address modes
+const (
+	NetworkModeDHCP        = "DHCP"
+	NetworkModeStaticRange = "STATICRANGE"
+)
+
+// Supervisor cluster configuration status
+const (
+	ClusterConfigConfiguring = "CONFIGURING"
+	ClusterConfigRemoving    = "REMOVING"
+	ClusterConfigRunning     = "RUNNING"
+	ClusterConfigError       = "ERROR"
+)
+
+// Supervisor cluster Kubernetes status
+const (
+	KubernetesReady   = "READY"
+	KubernetesWarning = "WARNING"
+	KubernetesError   = "ERROR"
+)
+
+// IPv4Cidr is an IPv4 address range in CIDR notation.
+type IPv4Cidr struct {
+	Address string `json:"address"`
+	Prefix  int    `json:"prefix"`
+}
+
+// IPRange is a range of consecutive IPv4 addresses, starting with StartingAddress.
+type IPRange struct {
+	StartingAddress string `json:"starting_address"`
+	AddressCount    int    `json:"address_count"`
+	SubnetMask      string `json:"subnet_mask"`
+	Gateway         string `json:"gateway"`
+}
+
+// MasterManagementNetwork specifies the network the Supervisor control plane VMs are connected to.
+// AddressRange is required when Mode is NetworkModeStaticRange.
+type MasterManagementNetwork struct {
+	Mode         string   `json:"mode"`
+	Network      string   `json:"network"`
+	AddressRange *IPRange `json:"address_range,omitempty"`
+	FloatingIP   string   `json:"floating_IP,omitempty"`
+}
+
+// ImageStorage specifies the storage policy used for container images.
+type ImageStorage struct {
+	StoragePolicy string `json:"storage_policy"`
+}
+
+// EnableClusterSpec specifies the configuration of a Supervisor cluster.
+type EnableClusterSpec struct {
+	SizeHint                               string                   `json:"size_hint"`
+	ServiceCidr                            *IPv4Cidr                `json:"service_cidr,omitempty"`
+	NetworkProvider                        string                   `json:"network_provider"`
+	MasterManagementNetwork                *MasterManagementNetwork `json:"master_management_network"`
+	MasterDNS                              []string                 `json:"master_DNS,omitempty"`
+	WorkerDNS                              []string                 `json:"worker_DNS,omitempty"`
+	MasterNTPServers                       []string                 `json:"master_NTP_servers,omitempty"`
+	MasterStoragePolicy                    string                   `json:"master_storage_policy"`
+	EphemeralStoragePolicy                 string                   `json:"ephemeral_storage_policy"`
+	ImageStorage                           ImageStorage             `json:"image_storage"`
+	DefaultKubernetesServiceContentLibrary string                   `json:"default_kubernetes_service_content_library,omitempty"`
+}
+
+// ClusterSummary describes a cluster with Workload Management enabled.
+type ClusterSummary struct {
+	Cluster          string `json:"cluster"`
+	ClusterName      string `json:"cluster_name"`
+	ConfigStatus     string `json:"config_status"`
+	KubernetesStatus string `json:"kubernetes_status"`
+}
+
+// ClusterInfo describes the Supervisor configuration and status of a cluster.
+type ClusterInfo struct {
+	SizeHint                    string                   `json:"size_hint"`
+	ConfigStatus                string                   `json:"config_status"`
+	KubernetesStatus            string                   `json:"kubernetes_status"`
+	APIServerManagementEndpoint string                   `json:"api_server_management_endpoint,omitempty"`
+	APIServers                  []string                 `json:"api_servers"`
+	NetworkProvider             string                   `json:"network_provider"`
+	ServiceCidr                 *IPv4Cidr                `json:"service_cidr,omitempty"`
+	MasterManagementNetwork     *MasterManagementNetwork `json:"master_management_network,omitempty"`
+	MasterStoragePolicy         string                   `json:"master_storage_policy"`
+	EphemeralStoragePolicy      string                   `json:"ephemeral_storage_policy"`
+	ImageStorage                ImageStorage             `json:"image_storage"`
+}
+
+// ClusterCompatibility describes whether Workload Management can be enabled on a cluster.
+type ClusterCompatibility struct {
+	Cluster                string                    `json:"cluster"`
+	Compatible             bool                      `json:"compatible"`
+	IncompatibilityReasons []rest.LocalizableMessage `json:"incompatibility_reasons"`
+}
+
+func (c *Manager) cluster(id string) *internal.Resource {
+	return internal.URL(c, internal.NamespaceClustersPath).WithSubpath(id)
+}
+
+// ListClusters returns the clusters with Workload Management enabled.
+func (c *Manager) ListClusters(ctx context.Context) ([]ClusterSummary, error) {
+	url := internal.URL(c, internal.NamespaceClustersPath)
+	var res []ClusterSummary
+	return res, c.Do(ctx, url.Request(ctx, http.MethodGet), &res)
+}
+
+// GetCluster returns the Supervisor configuration and status of the given cluster.
+func (c *Manager) GetCluster(ctx context.Context, id string) (*ClusterInfo, error) {
+	var res ClusterInfo
+	return &res, c.Do(ctx, c.cluster(id).Request(ctx, http.MethodGet), &res)
+}
+
+// EnableCluster enables Workload Management on the given cluster, making it a Supervisor cluster.
+// Use ListClusterCompatibility to check whether the cluster can be enabled.
+func (c *Manager) EnableCluster(ctx context.Context, id string, spec EnableClusterSpec) error {
+	return c.Do(ctx, c.cluster(id).WithAction("enable").Request(ctx, http.MethodPost, spec), nil)
+}
+
+// DisableCluster disables Workload Management on the given cluster,
+// removing its namespaces and supervisor services.
+func (c *Manager) DisableCluster(ctx context.Context, id string) error {
+	return c.Do(ctx, c.cluster(id).WithAction("disable").Request(ctx, http.MethodPost), nil)
+}
+
+// ListClusterCompatibility returns whether Workload Management can be enabled on each cluster in the inventory.
+func (c *Manager) ListClusterCompatibility(ctx context.Context) ([]ClusterCompatibility, error) {
+	url := internal.URL(c, internal.ClusterCompatibilityPath)
+	var res []ClusterCompatibility
+	return res, c.Do(ctx, url.Request(ctx, http.MethodGet), &res)
+}
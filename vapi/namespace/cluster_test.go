@@ -0,0 +1,131 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package namespace_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/namespace"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vim25"
+
+	_ "github.com/vmware/govmomi/vapi/simulator"
+)
+
+func TestEnableCluster(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+		if err := c.Login(ctx, simulator.DefaultLogin); err != nil {
+			t.Fatal(err)
+		}
+
+		m := namespace.NewManager(c)
+
+		compat, err := m.ListClusterCompatibility(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(compat) == 0 || !compat[0].Compatible {
+			t.Fatalf("compatibility=%#v", compat)
+		}
+		cluster := compat[0].Cluster
+
+		spec := namespace.EnableClusterSpec{
+			SizeHint:        namespace.SizeTiny,
+			NetworkProvider: namespace.NetworkProviderVSphere,
+			MasterManagementNetwork: &namespace.MasterManagementNetwork{
+				Mode:    namespace.NetworkModeStaticRange,
+				Network: "network-7",
+				AddressRange: &namespace.IPRange{
+					StartingAddress: "10.0.0.10",
+					AddressCount:    5,
+					SubnetMask:      "255.255.255.0",
+					Gateway:         "10.0.0.1",
+				},
+			},
+			MasterStoragePolicy:    "policy-1",
+			EphemeralStoragePolicy: "policy-1",
+			ImageStorage:           namespace.ImageStorage{StoragePolicy: "policy-1"},
+		}
+
+		var nf *rest.NotFound
+		if _, err = m.GetCluster(ctx, cluster); !errors.As(err, &nf) {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if err = m.EnableCluster(ctx, "domain-c0", spec); !errors.As(err, &nf) {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		var ia *rest.InvalidArgument
+		invalid := spec
+		invalid.MasterStoragePolicy = ""
+		if err = m.EnableCluster(ctx, cluster, invalid); !errors.As(err, &ia) {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		if err = m.EnableCluster(ctx, cluster, spec); err != nil {
+			t.Fatal(err)
+		}
+		if err = m.EnableCluster(ctx, cluster, spec); err == nil {
+			t.Error("expected error")
+		}
+
+		info, err := m.GetCluster(ctx, cluster)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.ConfigStatus != namespace.ClusterConfigRunning || info.APIServerManagementEndpoint != "10.0.0.10" {
+			t.Errorf("info=%#v", info)
+		}
+
+		clusters, err := m.ListClusters(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(clusters) != 1 || clusters[0].Cluster != cluster || clusters[0].ClusterName == "" {
+			t.Errorf("clusters=%#v", clusters)
+		}
+
+		compat, err = m.ListClusterCompatibility(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, c := range compat {
+			if c.Cluster == cluster && (c.Compatible || len(c.IncompatibilityReasons) == 0) {
+				t.Errorf("compatibility=%#v", c)
+			}
+		}
+
+		ns := namespace.CreateSpec{Cluster: cluster, Namespace: "dev"}
+		if err = m.CreateNamespace(ctx, ns); err != nil {
+			t.Fatal(err)
+		}
+
+		if err = m.DisableCluster(ctx, cluster); err != nil {
+			t.Fatal(err)
+		}
+		if _, err = m.GetCluster(ctx, cluster); !errors.As(err, &nf) {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if _, err = m.GetNamespace(ctx, ns.Namespace); !errors.As(err, &nf) {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
@@ -0,0 +1,96 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package namespace
+
+import (
+	"context"
+)
+
+// ContentLibraryAssociation is a content library used as a VM Service image source by a namespace.
+type ContentLibraryAssociation struct {
+	Namespace string `json:"namespace"`
+	Library   string `json:"library"`
+}
+
+// ListContentLibraryAssociations returns the content libraries associated with each namespace.
+func (c *Manager) ListContentLibraryAssociations(ctx context.Context) ([]ContentLibraryAssociation, error) {
+	namespaces, err := c.ListNamespaces(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []ContentLibraryAssociation
+	for _, ns := range namespaces {
+		info, err := c.GetNamespace(ctx, ns.Namespace)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range info.VMServiceSpec.ContentLibraries {
+			res = append(res, ContentLibraryAssociation{Namespace: ns.Namespace, Library: id})
+		}
+	}
+
+	return res, nil
+}
+
+// updateContentLibraries applies the change function to the namespace's content libraries,
+// preserving the rest of its VM Service configuration.
+func (c *Manager) updateContentLibraries(ctx context.Context, name string, change func([]string) []string) error {
+	info, err := c.GetNamespace(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	spec := info.VMServiceSpec
+	spec.ContentLibraries = change(spec.ContentLibraries)
+
+	return c.UpdateNamespace(ctx, name, UpdateSpec{VMServiceSpec: &spec})
+}
+
+// AssociateContentLibraries adds the given content libraries to the namespace's VM Service image sources.
+// Libraries that are already associated with the namespace are ignored.
+func (c *Manager) AssociateContentLibraries(ctx context.Context, name string, ids ...string) error {
+	return c.updateContentLibraries(ctx, name, func(libs []string) []string {
+		for _, id := range ids {
+			exists := false
+			for _, lib := range libs {
+				exists = exists || lib == id
+			}
+			if !exists {
+				libs = append(libs, id)
+			}
+		}
+		return libs
+	})
+}
+
+// DisassociateContentLibraries removes the given content libraries from the namespace's VM Service image sources.
+func (c *Manager) DisassociateContentLibraries(ctx context.Context, name string, ids ...string) error {
+	return c.updateContentLibraries(ctx, name, func(libs []string) []string {
+		var res []string
+		for _, lib := range libs {
+			remove := false
+			for _, id := range ids {
+				remove = remove || lib == id
+			}
+			if !remove {
+				res = append(res, lib)
+			}
+		}
+		return res
+	})
+}
@@ -0,0 +1,122 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package namespace_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/library"
+	"github.com/vmware/govmomi/vapi/namespace"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vim25"
+
+	_ "github.com/vmware/govmomi/vapi/simulator"
+)
+
+func TestContentLibraryAssociations(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+		if err := c.Login(ctx, simulator.DefaultLogin); err != nil {
+			t.Fatal(err)
+		}
+
+		m := namespace.NewManager(c)
+		lm := library.NewManager(c)
+
+		var libs []string
+		for _, name := range []string{"images", "templates"} {
+			id, err := lm.CreateLibrary(ctx, library.Library{
+				Name: name,
+				Type: "LOCAL",
+				Storage: []library.StorageBackings{{
+					DatastoreID: simulator.Map.Any("Datastore").Reference().Value,
+					Type:        "DATASTORE",
+				}},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			libs = append(libs, id)
+		}
+
+		spec := namespace.CreateSpec{
+			Cluster:       "domain-c8",
+			Namespace:     "vm-service",
+			VMServiceSpec: &namespace.VMServiceSpec{VMClasses: []string{"best-effort-small"}},
+		}
+		if err := m.CreateNamespace(ctx, spec); err != nil {
+			t.Fatal(err)
+		}
+
+		var ia *rest.InvalidArgument
+		if err := m.AssociateContentLibraries(ctx, spec.Namespace, "enoent"); !errors.As(err, &ia) {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		if err := m.AssociateContentLibraries(ctx, spec.Namespace, libs...); err != nil {
+			t.Fatal(err)
+		}
+		if err := m.AssociateContentLibraries(ctx, spec.Namespace, libs[0]); err != nil {
+			t.Fatal(err)
+		}
+
+		associations, err := m.ListContentLibraryAssociations(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(associations) != 2 || associations[0].Namespace != spec.Namespace || associations[1].Library != libs[1] {
+			t.Errorf("associations=%#v", associations)
+		}
+
+		if err = m.DisassociateContentLibraries(ctx, spec.Namespace, libs[0]); err != nil {
+			t.Fatal(err)
+		}
+
+		info, err := m.GetNamespace(ctx, spec.Namespace)
+		if err != nil {
+			t.Fatal(err)
+		}
+		vms := info.VMServiceSpec
+		if len(vms.ContentLibraries) != 1 || vms.ContentLibraries[0] != libs[1] {
+			t.Errorf("libraries=%v", vms.ContentLibraries)
+		}
+		if len(vms.VMClasses) != 1 {
+			t.Errorf("classes=%v", vms.VMClasses)
+		}
+
+		class, err := m.GetVMClass(ctx, "best-effort-small")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(class.Namespaces) != 1 || class.Namespaces[0] != spec.Namespace {
+			t.Errorf("namespaces=%v", class.Namespaces)
+		}
+
+		if err = m.DisassociateContentLibraries(ctx, spec.Namespace, libs[1]); err != nil {
+			t.Fatal(err)
+		}
+		if associations, err = m.ListContentLibraryAssociations(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if len(associations) != 0 {
+			t.Errorf("associations=%#v", associations)
+		}
+	})
+}
@@ -0,0 +1,149 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package namespace
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/vmware/govmomi/vapi/internal"
+	"github.com/vmware/govmomi/vapi/rest"
+)
+
+// Namespace configuration status
+const (
+	ConfigStatusConfiguring = "CONFIGURING"
+	ConfigStatusRemoving    = "REMOVING"
+	ConfigStatusRunning     = "RUNNING"
+	ConfigStatusError       = "ERROR"
+)
+
+// Manager extends rest.Client, adding vSphere with Tanzu namespace related methods.
+// The namespace APIs are only available via the /api protocol (vSphere 7.0+).
+type Manager struct {
+	*rest.Client
+}
+
+// NewManager creates a new Manager instance with the given client.
+func NewManager(client *rest.Client) *Manager {
+	return &Manager{
+		Client: client,
+	}
+}
+
+// ResourceSpec limits the resources that can be used by all workloads in a namespace.
+// CPU is in MHz, memory and storage in MiB. A nil limit is unlimited.
+type ResourceSpec struct {
+	CPULimit            *int64 `json:"cpu_limit,omitempty"`
+	MemoryLimit         *int64 `json:"memory_limit,omitempty"`
+	StorageRequestLimit *int64 `json:"storage_request_limit,omitempty"`
+}
+
+// StorageSpec assigns a storage policy to a namespace, with an optional limit in MiB on the storage that can be requested.
+type StorageSpec struct {
+	Policy string `json:"policy"`
+	Limit  *int64 `json:"limit,omitempty"`
+}
+
+// Stats is the resource usage of a namespace.
+type Stats struct {
+	CPUUsed     int64 `json:"cpu_used"`
+	MemoryUsed  int64 `json:"memory_used"`
+	StorageUsed int64 `json:"storage_used"`
+}
+
+// VMServiceSpec specifies the VM Service configuration of a namespace: the content libraries used as
+// the source of VM images and the VM classes that can be used by VMs in the namespace.
+type VMServiceSpec struct {
+	ContentLibraries []string `json:"content_libraries,omitempty"`
+	VMClasses        []string `json:"vm_classes,omitempty"`
+}
+
+// Summary describes a namespace, as returned by ListNamespaces.
+type Summary struct {
+	Namespace    string `json:"namespace"`
+	Description  string `json:"description,omitempty"`
+	Cluster      string `json:"cluster"`
+	ConfigStatus string `json:"config_status"`
+	Stats        Stats  `json:"stats"`
+}
+
+// Info describes a namespace in detail.
+type Info struct {
+	Cluster       string                    `json:"cluster"`
+	Description   string                    `json:"description,omitempty"`
+	ConfigStatus  string                    `json:"config_status"`
+	ResourceSpec  ResourceSpec              `json:"resource_spec"`
+	StorageSpecs  []StorageSpec             `json:"storage_specs"`
+	AccessList    []Access                  `json:"access_list"`
+	VMServiceSpec VMServiceSpec             `json:"vm_service_spec"`
+	Stats         Stats                     `json:"stats"`
+	Messages      []rest.LocalizableMessage `json:"messages"`
+}
+
+// CreateSpec specifies a namespace to create on the given Supervisor cluster.
+// The Namespace name must be a valid DNS label.
+type CreateSpec struct {
+	Cluster       string         `json:"cluster"`
+	Namespace     string         `json:"namespace"`
+	Description   string         `json:"description,omitempty"`
+	ResourceSpec  *ResourceSpec  `json:"resource_spec,omitempty"`
+	StorageSpecs  []StorageSpec  `json:"storage_specs,omitempty"`
+	AccessList    []Access       `json:"access_list,omitempty"`
+	VMServiceSpec *VMServiceSpec `json:"vm_service_spec,omitempty"`
+}
+
+// UpdateSpec specifies the namespace fields to update, fields that are not set are unchanged.
+// StorageSpecs replaces the assigned storage policies when non-nil, VMServiceSpec replaces the VM Service configuration.
+type UpdateSpec struct {
+	Description   string         `json:"description,omitempty"`
+	ResourceSpec  *ResourceSpec  `json:"resource_spec,omitempty"`
+	StorageSpecs  []StorageSpec  `json:"storage_specs,omitempty"`
+	VMServiceSpec *VMServiceSpec `json:"vm_service_spec,omitempty"`
+}
+
+// ListNamespaces returns all namespaces.
+func (c *Manager) ListNamespaces(ctx context.Context) ([]Summary, error) {
+	url := internal.URL(c, internal.NamespacesInstancesPath)
+	var res []Summary
+	return res, c.Do(ctx, url.Request(ctx, http.MethodGet), &res)
+}
+
+// GetNamespace returns the given namespace.
+func (c *Manager) GetNamespace(ctx context.Context, name string) (*Info, error) {
+	url := internal.URL(c, internal.NamespacesInstancesPath).WithSubpath(name)
+	var res Info
+	return &res, c.Do(ctx, url.Request(ctx, http.MethodGet), &res)
+}
+
+// CreateNamespace creates a namespace.
+func (c *Manager) CreateNamespace(ctx context.Context, spec CreateSpec) error {
+	url := internal.URL(c, internal.NamespacesInstancesPath)
+	return c.Do(ctx, url.Request(ctx, http.MethodPost, spec), nil)
+}
+
+// UpdateNamespace updates the given namespace.
+func (c *Manager) UpdateNamespace(ctx context.Context, name string, spec UpdateSpec) error {
+	url := internal.URL(c, internal.NamespacesInstancesPath).WithSubpath(name)
+	return c.Do(ctx, url.Request(ctx, http.MethodPatch, spec), nil)
+}
+
+// DeleteNamespace deletes the given namespace and all of its workloads.
+func (c *Manager) DeleteNamespace(ctx context.Context, name string) error {
+	url := internal.URL(c, internal.NamespacesInstancesPath).WithSubpath(name)
+	return c.Do(ctx, url.Request(ctx, http.MethodDelete), nil)
+}
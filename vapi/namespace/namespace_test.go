@@ -0,0 +1,108 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package namespace_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/namespace"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vim25"
+
+	_ "github.com/vmware/govmomi/vapi/simulator"
+)
+
+func TestNamespaces(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+		if err := c.Login(ctx, simulator.DefaultLogin); err != nil {
+			t.Fatal(err)
+		}
+
+		m := namespace.NewManager(c)
+
+		cpu := int64(10000)
+		storage := int64(100 * 1024)
+		spec := namespace.CreateSpec{
+			Cluster:      "domain-c8",
+			Namespace:    "dev-team",
+			Description:  "development",
+			ResourceSpec: &namespace.ResourceSpec{CPULimit: &cpu},
+			StorageSpecs: []namespace.StorageSpec{{Policy: "gold-policy", Limit: &storage}},
+		}
+		if err := m.CreateNamespace(ctx, spec); err != nil {
+			t.Fatal(err)
+		}
+
+		var ae *rest.AlreadyExists
+		if err := m.CreateNamespace(ctx, spec); !errors.As(err, &ae) {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		var ia *rest.InvalidArgument
+		invalid := namespace.CreateSpec{Cluster: spec.Cluster, Namespace: "Dev_Team"}
+		if err := m.CreateNamespace(ctx, invalid); !errors.As(err, &ia) {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		list, err := m.ListNamespaces(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(list) != 1 || list[0].Namespace != spec.Namespace || list[0].Cluster != spec.Cluster {
+			t.Errorf("list=%#v", list)
+		}
+
+		info, err := m.GetNamespace(ctx, spec.Namespace)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.ConfigStatus != namespace.ConfigStatusRunning || *info.ResourceSpec.CPULimit != cpu {
+			t.Errorf("info=%#v", info)
+		}
+		if len(info.StorageSpecs) != 1 || *info.StorageSpecs[0].Limit != storage {
+			t.Errorf("storage=%#v", info.StorageSpecs)
+		}
+
+		memory := int64(16 * 1024)
+		update := namespace.UpdateSpec{
+			ResourceSpec: &namespace.ResourceSpec{CPULimit: &cpu, MemoryLimit: &memory},
+			StorageSpecs: []namespace.StorageSpec{{Policy: "gold-policy"}, {Policy: "silver-policy"}},
+		}
+		if err = m.UpdateNamespace(ctx, spec.Namespace, update); err != nil {
+			t.Fatal(err)
+		}
+		if info, err = m.GetNamespace(ctx, spec.Namespace); err != nil {
+			t.Fatal(err)
+		}
+		if *info.ResourceSpec.MemoryLimit != memory || len(info.StorageSpecs) != 2 || info.Description != spec.Description {
+			t.Errorf("info=%#v", info)
+		}
+
+		if err = m.DeleteNamespace(ctx, spec.Namespace); err != nil {
+			t.Fatal(err)
+		}
+
+		var nf *rest.NotFound
+		if _, err = m.GetNamespace(ctx, spec.Namespace); !errors.As(err, &nf) {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
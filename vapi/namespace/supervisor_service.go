@@ -0,0 +1,168 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package namespace
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/vmware/govmomi/vapi/internal"
+)
+
+// Supervisor service and version states
+const (
+	SupervisorServiceActivated   = "ACTIVATED"
+	SupervisorServiceDeactivated = "DEACTIVATED"
+)
+
+// Supervisor service configuration status on a cluster
+const (
+	ClusterServiceConfiguring = "CONFIGURING"
+	ClusterServiceConfigured  = "CONFIGURED"
+	ClusterServiceError       = "ERROR"
+	ClusterServiceRemoving    = "REMOVING"
+)
+
+// CarvelSpec specifies a supervisor service version, defined by a Carvel Package.
+// Content is the Package YAML, which is base64 encoded on the wire.
+type CarvelSpec struct {
+	Content    []byte `json:"content"`
+	AcceptEULA bool   `json:"accept_EULA,omitempty"`
+}
+
+// SupervisorServiceSummary describes a supervisor service.
+type SupervisorServiceSummary struct {
+	SupervisorService string `json:"supervisor_service"`
+	DisplayName       string `json:"display_name"`
+	State             string `json:"state"`
+}
+
+// SupervisorServiceInfo describes a supervisor service in detail.
+type SupervisorServiceInfo struct {
+	DisplayName string `json:"display_name"`
+	Description string `json:"description,omitempty"`
+	State       string `json:"state"`
+}
+
+// SupervisorServiceVersionSummary describes a version of a supervisor service.
+type SupervisorServiceVersionSummary struct {
+	Version     string `json:"version"`
+	DisplayName string `json:"display_name"`
+	State       string `json:"state"`
+}
+
+// ClusterSupervisorServiceSpec specifies the supervisor service version to install on a Supervisor cluster.
+// YAMLServiceConfig is optional service specific configuration, which is base64 encoded on the wire.
+type ClusterSupervisorServiceSpec struct {
+	SupervisorService string `json:"supervisor_service"`
+	Version           string `json:"version"`
+	YAMLServiceConfig []byte `json:"yaml_service_config,omitempty"`
+}
+
+// ClusterSupervisorServiceSummary describes a supervisor service installed on a Supervisor cluster.
+type ClusterSupervisorServiceSummary struct {
+	SupervisorService string `json:"supervisor_service"`
+	DesiredVersion    string `json:"desired_version"`
+	CurrentVersion    string `json:"current_version,omitempty"`
+	ConfigStatus      string `json:"config_status"`
+}
+
+func (c *Manager) supervisorService(id string) *internal.Resource {
+	return internal.URL(c, internal.SupervisorServicesPath).WithSubpath(id)
+}
+
+func (c *Manager) clusterSupervisorServices(cluster string) *internal.Resource {
+	return c.cluster(cluster).WithSubpath("supervisor-services")
+}
+
+// ListSupervisorServices returns the supervisor services registered with vCenter.
+func (c *Manager) ListSupervisorServices(ctx context.Context) ([]SupervisorServiceSummary, error) {
+	url := internal.URL(c, internal.SupervisorServicesPath)
+	var res []SupervisorServiceSummary
+	return res, c.Do(ctx, url.Request(ctx, http.MethodGet), &res)
+}
+
+// GetSupervisorService returns the given supervisor service.
+func (c *Manager) GetSupervisorService(ctx context.Context, id string) (*SupervisorServiceInfo, error) {
+	var res SupervisorServiceInfo
+	return &res, c.Do(ctx, c.supervisorService(id).Request(ctx, http.MethodGet), &res)
+}
+
+// CreateSupervisorService registers a supervisor service and its first version, both of which are defined by the Carvel Package.
+func (c *Manager) CreateSupervisorService(ctx context.Context, spec CarvelSpec) error {
+	url := internal.URL(c, internal.SupervisorServicesPath)
+	body := struct {
+		CarvelSpec struct {
+			VersionSpec CarvelSpec `json:"version_spec"`
+		} `json:"carvel_spec"`
+	}{}
+	body.CarvelSpec.VersionSpec = spec
+	return c.Do(ctx, url.Request(ctx, http.MethodPost, body), nil)
+}
+
+// ActivateSupervisorService activates the given supervisor service, such that it can be installed on Supervisor clusters.
+func (c *Manager) ActivateSupervisorService(ctx context.Context, id string) error {
+	return c.Do(ctx, c.supervisorService(id).WithAction("activate").Request(ctx, http.MethodPatch), nil)
+}
+
+// DeactivateSupervisorService deactivates the given supervisor service, such that it can no longer be installed.
+// Existing installations on Supervisor clusters are not changed.
+func (c *Manager) DeactivateSupervisorService(ctx context.Context, id string) error {
+	return c.Do(ctx, c.supervisorService(id).WithAction("deactivate").Request(ctx, http.MethodPatch), nil)
+}
+
+// DeleteSupervisorService deletes the given supervisor service, which must be deactivated.
+func (c *Manager) DeleteSupervisorService(ctx context.Context, id string) error {
+	return c.Do(ctx, c.supervisorService(id).Request(ctx, http.MethodDelete), nil)
+}
+
+// ListSupervisorServiceVersions returns the versions of the given supervisor service.
+func (c *Manager) ListSupervisorServiceVersions(ctx context.Context, id string) ([]SupervisorServiceVersionSummary, error) {
+	var res []SupervisorServiceVersionSummary
+	return res, c.Do(ctx, c.supervisorService(id).WithSubpath("versions").Request(ctx, http.MethodGet), &res)
+}
+
+// CreateSupervisorServiceVersion registers a new version of the given supervisor service, defined by the Carvel Package.
+func (c *Manager) CreateSupervisorServiceVersion(ctx context.Context, id string, spec CarvelSpec) error {
+	body := struct {
+		CarvelSpec CarvelSpec `json:"carvel_spec"`
+	}{spec}
+	return c.Do(ctx, c.supervisorService(id).WithSubpath("versions").Request(ctx, http.MethodPost, body), nil)
+}
+
+// DeleteSupervisorServiceVersion deletes the given version of a supervisor service.
+func (c *Manager) DeleteSupervisorServiceVersion(ctx context.Context, id string, version string) error {
+	url := c.supervisorService(id).WithSubpath("versions").WithSubpath(version)
+	return c.Do(ctx, url.Request(ctx, http.MethodDelete), nil)
+}
+
+// ListClusterSupervisorServices returns the supervisor services installed on the given Supervisor cluster.
+func (c *Manager) ListClusterSupervisorServices(ctx context.Context, cluster string) ([]ClusterSupervisorServiceSummary, error) {
+	var res []ClusterSupervisorServiceSummary
+	return res, c.Do(ctx, c.clusterSupervisorServices(cluster).Request(ctx, http.MethodGet), &res)
+}
+
+// InstallClusterSupervisorService installs a version of an activated supervisor service on the given Supervisor cluster.
+func (c *Manager) InstallClusterSupervisorService(ctx context.Context, cluster string, spec ClusterSupervisorServiceSpec) error {
+	return c.Do(ctx, c.clusterSupervisorServices(cluster).Request(ctx, http.MethodPost, spec), nil)
+}
+
+// UninstallClusterSupervisorService removes the given supervisor service from the Supervisor cluster.
+func (c *Manager) UninstallClusterSupervisorService(ctx context.Context, cluster string, id string) error {
+	url := c.clusterSupervisorServices(cluster).WithSubpath(id)
+	return c.Do(ctx, url.Request(ctx, http.MethodDelete), nil)
+}
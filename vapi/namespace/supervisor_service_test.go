@@ -0,0 +1,144 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package namespace_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/namespace"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vim25"
+
+	_ "github.com/vmware/govmomi/vapi/simulator"
+)
+
+func carvelPackage(version string) namespace.CarvelSpec {
+	content := fmt.Sprintf(`apiVersion: data.packaging.carvel.dev/v1alpha1
+kind: Package
+metadata:
+  name: harbor.example.com.%[1]s
+spec:
+  refName: harbor.example.com
+  version: %[1]s
+  releasedAt: "2021-10-01T00:00:00Z"
+  template:
+    spec:
+      fetch:
+      - imgpkgBundle:
+          image: registry.example.com/harbor:%[1]s
+`, version)
+	return namespace.CarvelSpec{Content: []byte(content), AcceptEULA: true}
+}
+
+func TestSupervisorServices(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+		if err := c.Login(ctx, simulator.DefaultLogin); err != nil {
+			t.Fatal(err)
+		}
+
+		m := namespace.NewManager(c)
+		id := "harbor.example.com"
+		cluster := "domain-c8"
+
+		if err := m.CreateSupervisorService(ctx, carvelPackage("2.2.3")); err != nil {
+			t.Fatal(err)
+		}
+
+		var ae *rest.AlreadyExists
+		if err := m.CreateSupervisorService(ctx, carvelPackage("2.2.3")); !errors.As(err, &ae) {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		if err := m.CreateSupervisorServiceVersion(ctx, id, carvelPackage("2.3.0")); err != nil {
+			t.Fatal(err)
+		}
+
+		services, err := m.ListSupervisorServices(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(services) != 1 || services[0].SupervisorService != id || services[0].State != namespace.SupervisorServiceActivated {
+			t.Errorf("services=%#v", services)
+		}
+
+		versions, err := m.ListSupervisorServiceVersions(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(versions) != 2 {
+			t.Errorf("versions=%#v", versions)
+		}
+
+		if err = m.DeactivateSupervisorService(ctx, id); err != nil {
+			t.Fatal(err)
+		}
+		info, err := m.GetSupervisorService(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.State != namespace.SupervisorServiceDeactivated {
+			t.Errorf("info=%#v", info)
+		}
+
+		spec := namespace.ClusterSupervisorServiceSpec{SupervisorService: id, Version: "2.3.0"}
+		if err = m.InstallClusterSupervisorService(ctx, cluster, spec); err == nil {
+			t.Error("expected error installing a deactivated service")
+		}
+
+		if err = m.ActivateSupervisorService(ctx, id); err != nil {
+			t.Fatal(err)
+		}
+		if err = m.InstallClusterSupervisorService(ctx, cluster, spec); err != nil {
+			t.Fatal(err)
+		}
+
+		installed, err := m.ListClusterSupervisorServices(ctx, cluster)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(installed) != 1 || installed[0].CurrentVersion != spec.Version || installed[0].ConfigStatus != namespace.ClusterServiceConfigured {
+			t.Errorf("installed=%#v", installed)
+		}
+
+		if err = m.DeleteSupervisorServiceVersion(ctx, id, spec.Version); err == nil {
+			t.Error("expected error deleting an installed version")
+		}
+		if err = m.DeleteSupervisorServiceVersion(ctx, id, "2.2.3"); err != nil {
+			t.Fatal(err)
+		}
+
+		if err = m.UninstallClusterSupervisorService(ctx, cluster, id); err != nil {
+			t.Fatal(err)
+		}
+		if err = m.DeactivateSupervisorService(ctx, id); err != nil {
+			t.Fatal(err)
+		}
+		if err = m.DeleteSupervisorService(ctx, id); err != nil {
+			t.Fatal(err)
+		}
+
+		var nf *rest.NotFound
+		if _, err = m.GetSupervisorService(ctx, id); !errors.As(err, &nf) {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
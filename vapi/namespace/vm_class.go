@@ -0,0 +1,121 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package namespace
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/vmware/govmomi/vapi/internal"
+	"github.com/vmware/govmomi/vapi/rest"
+)
+
+// VM class configuration status
+const (
+	VMClassConfigStatusReady    = "READY"
+	VMClassConfigStatusCreating = "CREATING"
+	VMClassConfigStatusUpdating = "UPDATING"
+	VMClassConfigStatusRemoving = "REMOVING"
+	VMClassConfigStatusError    = "ERROR"
+)
+
+// VGPUDevice is a vGPU device, identified by the name of its vGPU profile.
+type VGPUDevice struct {
+	ProfileName string `json:"profile_name"`
+}
+
+// DirectPathIODevice is a Dynamic DirectPath I/O PCI passthrough device.
+type DirectPathIODevice struct {
+	VendorID    int64  `json:"vendor_id"`
+	DeviceID    int64  `json:"device_id"`
+	CustomLabel string `json:"custom_label,omitempty"`
+}
+
+// VirtualDevices are the PCI devices attached to VMs of a VM class.
+type VirtualDevices struct {
+	VGPUDevices                []VGPUDevice         `json:"vgpu_devices,omitempty"`
+	DynamicDirectPathIODevices []DirectPathIODevice `json:"dynamic_direct_path_io_devices,omitempty"`
+}
+
+// VMClassInfo describes a VM class.
+// CPUReservation and MemoryReservation are a percentage (0-100) of the CPU and memory that is reserved.
+type VMClassInfo struct {
+	ID                string                    `json:"id"`
+	CPUCount          int64                     `json:"cpu_count"`
+	CPUReservation    int64                     `json:"cpu_reservation,omitempty"`
+	MemoryMB          int64                     `json:"memory_mb"`
+	MemoryReservation int64                     `json:"memory_reservation,omitempty"`
+	Description       string                    `json:"description,omitempty"`
+	Namespaces        []string                  `json:"namespaces"`
+	VMs               []string                  `json:"vms"`
+	Devices           VirtualDevices            `json:"devices"`
+	ConfigStatus      string                    `json:"config_status"`
+	Messages          []rest.LocalizableMessage `json:"messages"`
+}
+
+// VMClassCreateSpec specifies a VM class to create, the ID must be a valid DNS label.
+type VMClassCreateSpec struct {
+	ID                string          `json:"id"`
+	CPUCount          int64           `json:"cpu_count"`
+	CPUReservation    int64           `json:"cpu_reservation,omitempty"`
+	MemoryMB          int64           `json:"memory_mb"`
+	MemoryReservation int64           `json:"memory_reservation,omitempty"`
+	Description       string          `json:"description,omitempty"`
+	Devices           *VirtualDevices `json:"devices,omitempty"`
+}
+
+// VMClassUpdateSpec specifies the VM class fields to update, fields that are not set are unchanged.
+type VMClassUpdateSpec struct {
+	CPUCount          int64           `json:"cpu_count,omitempty"`
+	CPUReservation    *int64          `json:"cpu_reservation,omitempty"`
+	MemoryMB          int64           `json:"memory_mb,omitempty"`
+	MemoryReservation *int64          `json:"memory_reservation,omitempty"`
+	Description       string          `json:"description,omitempty"`
+	Devices           *VirtualDevices `json:"devices,omitempty"`
+}
+
+// ListVMClasses returns all VM classes.
+func (c *Manager) ListVMClasses(ctx context.Context) ([]VMClassInfo, error) {
+	url := internal.URL(c, internal.NamespaceVMClassesPath)
+	var res []VMClassInfo
+	return res, c.Do(ctx, url.Request(ctx, http.MethodGet), &res)
+}
+
+// GetVMClass returns the given VM class.
+func (c *Manager) GetVMClass(ctx context.Context, id string) (*VMClassInfo, error) {
+	url := internal.URL(c, internal.NamespaceVMClassesPath).WithSubpath(id)
+	var res VMClassInfo
+	return &res, c.Do(ctx, url.Request(ctx, http.MethodGet), &res)
+}
+
+// CreateVMClass creates a VM class.
+func (c *Manager) CreateVMClass(ctx context.Context, spec VMClassCreateSpec) error {
+	url := internal.URL(c, internal.NamespaceVMClassesPath)
+	return c.Do(ctx, url.Request(ctx, http.MethodPost, spec), nil)
+}
+
+// UpdateVMClass updates the given VM class. Existing VMs are not changed, only those created after the update.
+func (c *Manager) UpdateVMClass(ctx context.Context, id string, spec VMClassUpdateSpec) error {
+	url := internal.URL(c, internal.NamespaceVMClassesPath).WithSubpath(id)
+	return c.Do(ctx, url.Request(ctx, http.MethodPatch, spec), nil)
+}
+
+// DeleteVMClass deletes the given VM class.
+func (c *Manager) DeleteVMClass(ctx context.Context, id string) error {
+	url := internal.URL(c, internal.NamespaceVMClassesPath).WithSubpath(id)
+	return c.Do(ctx, url.Request(ctx, http.MethodDelete), nil)
+}
@@ -0,0 +1,106 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package namespace_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/namespace"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vim25"
+
+	_ "github.com/vmware/govmomi/vapi/simulator"
+)
+
+func TestVMClasses(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+		if err := c.Login(ctx, simulator.DefaultLogin); err != nil {
+			t.Fatal(err)
+		}
+
+		m := namespace.NewManager(c)
+
+		classes, err := m.ListVMClasses(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(classes) == 0 {
+			t.Fatal("no default VM classes")
+		}
+
+		spec := namespace.VMClassCreateSpec{
+			ID:                "gpu-large",
+			CPUCount:          8,
+			CPUReservation:    50,
+			MemoryMB:          32768,
+			MemoryReservation: 100,
+			Devices: &namespace.VirtualDevices{
+				VGPUDevices: []namespace.VGPUDevice{{ProfileName: "grid_t4-16c"}},
+			},
+		}
+		if err = m.CreateVMClass(ctx, spec); err != nil {
+			t.Fatal(err)
+		}
+
+		var ae *rest.AlreadyExists
+		if err = m.CreateVMClass(ctx, spec); !errors.As(err, &ae) {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		var ia *rest.InvalidArgument
+		invalid := namespace.VMClassCreateSpec{ID: "invalid", CPUCount: 2, MemoryMB: 2048, CPUReservation: 200}
+		if err = m.CreateVMClass(ctx, invalid); !errors.As(err, &ia) {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		info, err := m.GetVMClass(ctx, spec.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.CPUCount != spec.CPUCount || info.ConfigStatus != namespace.VMClassConfigStatusReady {
+			t.Errorf("info=%#v", info)
+		}
+		if len(info.Devices.VGPUDevices) != 1 || info.Devices.VGPUDevices[0].ProfileName != "grid_t4-16c" {
+			t.Errorf("devices=%#v", info.Devices)
+		}
+
+		none := int64(0)
+		update := namespace.VMClassUpdateSpec{CPUCount: 16, CPUReservation: &none, Description: "GPU workloads"}
+		if err = m.UpdateVMClass(ctx, spec.ID, update); err != nil {
+			t.Fatal(err)
+		}
+		if info, err = m.GetVMClass(ctx, spec.ID); err != nil {
+			t.Fatal(err)
+		}
+		if info.CPUCount != 16 || info.CPUReservation != 0 || info.MemoryReservation != 100 || info.Description != update.Description {
+			t.Errorf("info=%#v", info)
+		}
+
+		if err = m.DeleteVMClass(ctx, spec.ID); err != nil {
+			t.Fatal(err)
+		}
+
+		var nf *rest.NotFound
+		if err = m.DeleteVMClass(ctx, spec.ID); !errors.As(err, &nf) {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
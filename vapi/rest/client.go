@@ -20,11 +20,13 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"sync"
 	"time"
 
 	"github.com/vmware/govmomi/vapi/internal"
@@ -33,8 +35,23 @@ import (
 )
 
 // Client extends soap.Client to support JSON encoding, while inheriting security features, debug tracing and session persistence.
+// When vim25/debug is enabled before NewClient is called, each request and response (headers and JSON body) is captured
+// to numbered files, with the timing and response status of each request written to the client log.
 type Client struct {
 	*soap.Client
+
+	api       bool
+	keepAlive *keepAlive
+	retry     RetryFunc
+	handler   Handler
+	opID      *opID
+	parent    *soap.Client
+	opts      []Option
+	reLogin   *reLogin
+	limiter   Limiter
+
+	mu        sync.Mutex
+	sessionID string
 }
 
 // Session information
@@ -55,19 +72,90 @@ func (m *LocalizableMessage) Error() string {
 	return m.DefaultMessage
 }
 
+// Option configures a Client created via NewClient.
+type Option func(*Client)
+
 // NewClient creates a new Client instance.
-func NewClient(c *vim25.Client) *Client {
-	sc := c.Client.NewServiceClient(internal.Path, "")
+func NewClient(c *vim25.Client, opts ...Option) *Client {
+	return newClient(c.Client, opts)
+}
+
+func newClient(c *soap.Client, opts []Option) *Client {
+	sc := c.NewServiceClient(internal.Path, "")
+
+	client := &Client{Client: sc, parent: c, opts: opts}
+	client.handler = client.do
+	for _, opt := range opts {
+		opt(client)
+	}
+	return client
+}
+
+// SessionID returns the current session ID, as sent via the vmware-api-session-id header.
+// An empty string is returned if the Client has not logged in.
+func (c *Client) SessionID() string {
+	return c.session()
+}
+
+// Clone returns a new Client, with the same options as this Client, that adopts the given session ID,
+// such as one returned by SessionID in another process. ErrSessionNotAuthenticated is returned if
+// the session is not valid. The keep alive settings of this Client are not inherited.
+func (c *Client) Clone(ctx context.Context, id string) (*Client, error) {
+	client := newClient(c.parent, c.opts)
+	client.api = c.api
+	client.setSession(id)
+
+	// Replace any session cookie copied from this Client
+	client.Jar.SetCookies(client.URL(), []*http.Cookie{{
+		Name:  internal.SessionCookieName,
+		Value: id,
+		Path:  internal.Path,
+	}})
+
+	s, err := client.Session(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if s == nil {
+		return nil, ErrSessionNotAuthenticated
+	}
+	return client, nil
+}
 
-	return &Client{sc}
+// UseAPI configures the Client to manage sessions via the /api JSON protocol, available since vSphere 7.0U2.
+// The session token is sent using the vmware-api-session-id header, which is accepted by both /api and /rest endpoints.
+// Request paths with the internal.APIPath prefix always use the /api protocol, regardless of this setting.
+func (c *Client) UseAPI() {
+	c.api = true
 }
 
+func (c *Client) session() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.sessionID
+}
+
+func (c *Client) setSession(id string) {
+	c.mu.Lock()
+	c.sessionID = id
+	c.mu.Unlock()
+}
+
+func (c *Client) sessionPath() string {
+	if c.api {
+		return internal.APISessionPath
+	}
+	return internal.SessionPath
+}
+
+// Signer is used to sign a request, such as a SAML token login request.
 type Signer interface {
 	SignRequest(*http.Request) error
 }
 
 type signerContext struct{}
 
+// WithSigner returns a context with the given Signer, used to sign the requests made with that context.
 func (c *Client) WithSigner(ctx context.Context, s Signer) context.Context {
 	return context.WithValue(ctx, signerContext{}, s)
 }
@@ -77,17 +165,86 @@ type statusError struct {
 }
 
 func (e *statusError) Error() string {
-	return fmt.Sprintf("%s %s: %s", e.res.Request.Method, e.res.Request.URL, e.res.Status)
+	return fmt.Sprintf("%s: %s", requestString(e.res.Request), e.res.Status)
+}
+
+// IsStatusError returns true if err is the response of a request that failed with the given HTTP status code.
+func IsStatusError(err error, code int) bool {
+	res := errorResponse(err)
+	return res != nil && res.StatusCode == code
 }
 
 // Do sends the http.Request, decoding resBody if provided.
 func (c *Client) Do(ctx context.Context, req *http.Request, resBody interface{}) error {
+	if c.keepAlive != nil {
+		c.keepAlive.request()
+	}
+
+	c.setHeader(ctx, req)
+
 	switch req.Method {
-	case http.MethodPost, http.MethodPatch:
-		req.Header.Set("Content-Type", "application/json")
+	case http.MethodPost, http.MethodPut, http.MethodPatch:
+		if req.Header.Get("Content-Type") == "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+	}
+
+	if req.Header.Get("Accept") == "" {
+		req.Header.Set("Accept", "application/json")
+	}
+
+	id := c.session()
+	if id != "" {
+		req.Header.Set(internal.SessionCookieName, id)
 	}
 
-	req.Header.Set("Accept", "application/json")
+	reLogin := true
+
+	for attempt := 1; ; attempt++ {
+		err := c.handler(ctx, req, resBody)
+		if err != nil && reLogin && c.shouldReLogin(req, err) {
+			reLogin = false
+			if err = c.reauthenticate(ctx, id); err != nil {
+				return err
+			}
+			if req.GetBody != nil {
+				if req.Body, err = req.GetBody(); err != nil {
+					return err
+				}
+			}
+			id = c.session()
+			req.Header.Set(internal.SessionCookieName, id)
+			err = c.handler(ctx, req, resBody)
+		}
+		if err == nil || c.retry == nil {
+			return err
+		}
+
+		retry, delay := c.retry(err, attempt)
+		if !retry {
+			return err
+		}
+
+		if req.GetBody != nil {
+			if req.Body, err = req.GetBody(); err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+func (c *Client) do(ctx context.Context, req *http.Request, resBody interface{}) error {
+	if c.limiter != nil {
+		if err := c.limiter.Wait(ctx); err != nil {
+			return err
+		}
+	}
 
 	if s, ok := ctx.Value(signerContext{}).(Signer); ok {
 		if err := s.SignRequest(req); err != nil {
@@ -97,15 +254,20 @@ func (c *Client) Do(ctx context.Context, req *http.Request, resBody interface{})
 
 	return c.Client.Do(ctx, req, func(res *http.Response) error {
 		switch res.StatusCode {
-		case http.StatusOK:
-		case http.StatusBadRequest:
-			// TODO: structured error types
+		case http.StatusOK, http.StatusCreated, http.StatusAccepted:
+		case http.StatusNoContent:
+			return nil
+		default:
 			detail, err := ioutil.ReadAll(res.Body)
 			if err != nil {
 				return err
 			}
-			return fmt.Errorf("%s: %s", res.Status, bytes.TrimSpace(detail))
-		default:
+			if err = decodeError(res, detail); err != nil {
+				return err
+			}
+			if res.StatusCode == http.StatusBadRequest {
+				return fmt.Errorf("%s: %s", res.Status, bytes.TrimSpace(detail))
+			}
 			return &statusError{res}
 		}
 
@@ -118,19 +280,24 @@ func (c *Client) Do(ctx context.Context, req *http.Request, resBody interface{})
 			_, err := io.Copy(b, res.Body)
 			return err
 		default:
+			dec := json.NewDecoder(res.Body)
+			if internal.IsAPI(req.URL.Path) {
+				// The /api protocol does not wrap response bodies
+				return dec.Decode(resBody)
+			}
 			val := struct {
 				Value interface{} `json:"value,omitempty"`
 			}{
 				resBody,
 			}
-			return json.NewDecoder(res.Body).Decode(&val)
+			return dec.Decode(&val)
 		}
 	})
 }
 
 // Login creates a new session via Basic Authentication with the given url.Userinfo.
 func (c *Client) Login(ctx context.Context, user *url.Userinfo) error {
-	req := internal.URL(c, internal.SessionPath).Request(http.MethodPost)
+	req := internal.URL(c, c.sessionPath()).Request(ctx, http.MethodPost)
 
 	if user != nil {
 		if password, ok := user.Password(); ok {
@@ -138,10 +305,24 @@ func (c *Client) Login(ctx context.Context, user *url.Userinfo) error {
 		}
 	}
 
-	return c.Do(ctx, req, nil)
+	var id string
+	if err := c.Do(ctx, req, &id); err != nil {
+		return err
+	}
+	c.setSession(id)
+	if c.keepAlive != nil {
+		c.keepAlive.start()
+	}
+	return nil
 }
 
+// LoginByToken creates a new session using the Signer from the given context, see WithSigner.
+// The Signer is typically an sts.Signer, with a holder-of-key SAML token issued by sts.Client.Issue
+// and the Certificate used to request it, such that the password need not be stored.
 func (c *Client) LoginByToken(ctx context.Context) error {
+	if _, ok := ctx.Value(signerContext{}).(Signer); !ok {
+		return errors.New("rest: LoginByToken requires a Signer, see WithSigner")
+	}
 	return c.Login(ctx, nil)
 }
 
@@ -149,13 +330,16 @@ func (c *Client) LoginByToken(ctx context.Context) error {
 // Nil is returned if the session is not authenticated.
 func (c *Client) Session(ctx context.Context) (*Session, error) {
 	var s Session
-	req := internal.URL(c, internal.SessionPath).WithAction("get").Request(http.MethodPost)
+	var req *http.Request
+	if c.api {
+		req = internal.URL(c, internal.APISessionPath).Request(ctx, http.MethodGet)
+	} else {
+		req = internal.URL(c, internal.SessionPath).WithAction("get").Request(ctx, http.MethodPost)
+	}
 	err := c.Do(ctx, req, &s)
 	if err != nil {
-		if e, ok := err.(*statusError); ok {
-			if e.res.StatusCode == http.StatusUnauthorized {
-				return nil, nil
-			}
+		if res := errorResponse(err); res != nil && res.StatusCode == http.StatusUnauthorized {
+			return nil, nil
 		}
 		return nil, err
 	}
@@ -164,6 +348,11 @@ func (c *Client) Session(ctx context.Context) (*Session, error) {
 
 // Logout deletes the current session.
 func (c *Client) Logout(ctx context.Context) error {
-	req := internal.URL(c, internal.SessionPath).Request(http.MethodDelete)
-	return c.Do(ctx, req, nil)
+	if c.keepAlive != nil {
+		c.keepAlive.stop()
+	}
+	req := internal.URL(c, c.sessionPath()).Request(ctx, http.MethodDelete)
+	err := c.Do(ctx, req, nil)
+	c.setSession("")
+	return err
 }
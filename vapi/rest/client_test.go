@@ -18,12 +18,26 @@ package rest_test
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net/http"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/sts"
+	"github.com/vmware/govmomi/vapi/internal"
 	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vapi/tags"
 	"github.com/vmware/govmomi/vim25"
 
+	_ "github.com/vmware/govmomi/lookup/simulator"
+	_ "github.com/vmware/govmomi/sts/simulator"
 	_ "github.com/vmware/govmomi/vapi/simulator"
 )
 
@@ -55,3 +69,181 @@ func TestSession(t *testing.T) {
 		}
 	})
 }
+
+// testCertificate returns a self-signed certificate for use with holder-of-key tokens.
+func testCertificate(t *testing.T) *tls.Certificate {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "govmomi-test"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+func TestLoginByToken(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+
+		if err := c.LoginByToken(ctx); err == nil {
+			t.Fatal("expected error without a Signer")
+		}
+
+		tokens, err := sts.NewClient(ctx, vc)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req := sts.TokenRequest{
+			Certificate: testCertificate(t),
+			Delegatable: true,
+		}
+
+		signer, err := tokens.Issue(ctx, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err = c.LoginByToken(c.WithSigner(ctx, signer)); err != nil {
+			t.Fatal(err)
+		}
+
+		session, err := c.Session(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if session == nil || session.User != "Administrator@VSPHERE.LOCAL" {
+			t.Errorf("session=%#v", session)
+		}
+	})
+}
+
+func TestClone(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+		if c.SessionID() != "" {
+			t.Fatal("expected empty session ID")
+		}
+
+		if err := c.Login(ctx, simulator.DefaultLogin); err != nil {
+			t.Fatal(err)
+		}
+
+		id := c.SessionID()
+		if id == "" {
+			t.Fatal("expected session ID")
+		}
+
+		// A Client without a session, as used by another process, can adopt the session
+		clone, err := rest.NewClient(vc).Clone(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if clone.SessionID() != id {
+			t.Errorf("clone session ID=%q", clone.SessionID())
+		}
+
+		if _, err = tags.NewManager(clone).ListCategories(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		if err = c.Logout(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = rest.NewClient(vc).Clone(ctx, id)
+		if err != rest.ErrSessionNotAuthenticated {
+			t.Errorf("expected ErrSessionNotAuthenticated, got %v", err)
+		}
+	})
+}
+
+func TestSessionAPI(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+		c.UseAPI()
+
+		session, err := c.Session(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if session != nil {
+			t.Fatal("expected nil session")
+		}
+
+		err = c.Login(ctx, simulator.DefaultLogin)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		session, err = c.Session(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if session == nil {
+			t.Fatal("expected non-nil session")
+		}
+
+		// /rest endpoints accept the session token header
+		m := tags.NewManager(c)
+		if _, err = m.ListCategories(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		err = c.Logout(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func TestMiddleware(t *testing.T) {
+	var trace []string
+
+	mw := func(name string) rest.Middleware {
+		return func(next rest.Handler) rest.Handler {
+			return func(ctx context.Context, req *http.Request, resBody interface{}) error {
+				req.Header.Set("X-Request-Name", name)
+				trace = append(trace, name)
+				err := next(ctx, req, resBody)
+				if id, ok := resBody.(*string); ok && err == nil {
+					trace = append(trace, name+":"+*id)
+				}
+				return err
+			}
+		}
+	}
+
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"value": "` + r.Header.Get("X-Request-Name") + `"}`))
+	})
+
+	c, done := newTestClient(t, h, rest.WithMiddleware(mw("a"), mw("b")))
+	defer done()
+
+	ctx := context.Background()
+	var res string
+	err := c.Do(ctx, internal.URL(c, internal.TagPath).Request(ctx, http.MethodGet), &res)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := []string{"a", "b", "b:b", "a:b"}
+	if !reflect.DeepEqual(trace, expect) {
+		t.Errorf("trace=%v", trace)
+	}
+}
@@ -0,0 +1,141 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CSPTokenURL is the VMware Cloud Services Platform endpoint used to exchange an API token for an access token.
+const CSPTokenURL = "https://console.cloud.vmware.com/csp/gateway/am/api/auth/api-tokens/authorize"
+
+// cspRefreshWindow is how long before the access token expires that a new token is requested.
+const cspRefreshWindow = time.Minute
+
+// CSPToken is the response of a CSP API token exchange.
+type CSPToken struct {
+	AccessToken  string `json:"access_token"`
+	ExpiresIn    int    `json:"expires_in"`
+	IDToken      string `json:"id_token,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	Scope        string `json:"scope,omitempty"`
+	TokenType    string `json:"token_type"`
+}
+
+// CSPTokenSource exchanges a CSP API token for an access token, such as for use with VMware Cloud vCenters.
+// The access token is cached and refreshed when it is about to expire.
+// CSPTokenSource implements the Signer interface, see Client.LoginByCSP.
+type CSPTokenSource struct {
+	APIToken string       // APIToken is the CSP API (refresh) token
+	URL      string       // URL of the token exchange endpoint, defaults to CSPTokenURL
+	Client   *http.Client // Client used for the token exchange, defaults to http.DefaultClient
+
+	mu      sync.Mutex
+	token   *CSPToken
+	expires time.Time
+}
+
+// Token returns a valid access token, exchanging the API token if there is no cached token or it is about to expire.
+func (s *CSPTokenSource) Token(ctx context.Context) (*CSPToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != nil && time.Now().Before(s.expires.Add(-cspRefreshWindow)) {
+		return s.token, nil
+	}
+
+	token, err := s.exchange(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.token = token
+	s.expires = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+
+	return token, nil
+}
+
+func (s *CSPTokenSource) exchange(ctx context.Context) (*CSPToken, error) {
+	endpoint := s.URL
+	if endpoint == "" {
+		endpoint = CSPTokenURL
+	}
+
+	form := url.Values{"refresh_token": {s.APIToken}}
+	req, err := http.NewRequest(http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		detail, _ := ioutil.ReadAll(res.Body)
+		return nil, fmt.Errorf("csp: %s: %s", res.Status, bytes.TrimSpace(detail))
+	}
+
+	var token CSPToken
+	if err = json.NewDecoder(res.Body).Decode(&token); err != nil {
+		return nil, err
+	}
+	if token.AccessToken == "" {
+		return nil, fmt.Errorf("csp: %s: no access_token in response", endpoint)
+	}
+
+	return &token, nil
+}
+
+// SignRequest is a Signer implementation that sets the access token as the request's Bearer Authorization.
+func (s *CSPTokenSource) SignRequest(req *http.Request) error {
+	token, err := s.Token(req.Context())
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token.AccessToken)
+	return nil
+}
+
+// LoginByCSP creates a new session using an access token from the given CSPTokenSource.
+// To login again when the session expires, use with KeepAliveHandler, for example:
+//
+//	c.KeepAliveHandler(idle, func(c *rest.Client) error {
+//		return c.LoginByCSP(context.Background(), tokens)
+//	})
+func (c *Client) LoginByCSP(ctx context.Context, s *CSPTokenSource) error {
+	return c.LoginByToken(c.WithSigner(ctx, s))
+}
@@ -0,0 +1,82 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rest_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/vmware/govmomi/vapi/rest"
+)
+
+func TestLoginByCSP(t *testing.T) {
+	exchanges := 0
+	expires := 0
+
+	csp := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("refresh_token") != "api-token" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		exchanges++
+		_ = json.NewEncoder(w).Encode(rest.CSPToken{
+			AccessToken: fmt.Sprintf("access-%d", exchanges),
+			ExpiresIn:   expires,
+			TokenType:   "bearer",
+		})
+	}))
+	defer csp.Close()
+
+	var auth string
+	c, done := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		_, _ = w.Write([]byte(`{"value": "session-id"}`))
+	}))
+	defer done()
+
+	ctx := context.Background()
+	tokens := &rest.CSPTokenSource{APIToken: "api-token", URL: csp.URL}
+
+	tests := []struct {
+		expires   int
+		exchanges int
+		auth      string
+	}{
+		{30, 1, "Bearer access-1"},
+		{1800, 2, "Bearer access-2"}, // previous token expires within the refresh window
+		{1800, 2, "Bearer access-2"}, // cached
+	}
+
+	for i, test := range tests {
+		expires = test.expires
+		if err := c.LoginByCSP(ctx, tokens); err != nil {
+			t.Fatal(err)
+		}
+		if exchanges != test.exchanges || auth != test.auth {
+			t.Errorf("%d: exchanges=%d, auth=%q", i, exchanges, auth)
+		}
+	}
+
+	tokens = &rest.CSPTokenSource{APIToken: "invalid", URL: csp.URL}
+	if err := c.LoginByCSP(ctx, tokens); err == nil {
+		t.Error("expected error")
+	}
+}
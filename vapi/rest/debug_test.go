@@ -0,0 +1,134 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rest_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/internal"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/debug"
+)
+
+type debugBuffer struct {
+	bytes.Buffer
+}
+
+func (*debugBuffer) Close() error {
+	return nil
+}
+
+// debugProvider is a debug.Provider that captures files in memory.
+type debugProvider struct {
+	sync.Mutex
+	files map[string]*debugBuffer
+}
+
+func (p *debugProvider) NewFile(name string) io.WriteCloser {
+	p.Lock()
+	defer p.Unlock()
+	b := new(debugBuffer)
+	p.files[name] = b
+	return b
+}
+
+func (p *debugProvider) Flush() {}
+
+// content returns the combined content of files with the given suffix.
+func (p *debugProvider) content(suffix string) string {
+	p.Lock()
+	defer p.Unlock()
+	var s string
+	for name, b := range p.files {
+		if strings.HasSuffix(name, suffix) {
+			s += b.String()
+		}
+	}
+	return s
+}
+
+func TestDebug(t *testing.T) {
+	p := &debugProvider{files: make(map[string]*debugBuffer)}
+	debug.SetProvider(p)
+	defer debug.SetProvider(nil)
+
+	c, done := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"type":"com.vmware.vapi.std.errors.not_found"}`))
+	}))
+	defer done()
+
+	ctx := context.Background()
+	req := internal.URL(c, internal.TagPath).Request(ctx, http.MethodPost, map[string]string{"name": "foo"})
+	if err := c.Do(ctx, req, nil); err == nil {
+		t.Fatal("expected error")
+	}
+
+	tests := []struct {
+		suffix string
+		expect string
+	}{
+		{"-client.log", "(POST " + req.URL.String() + ") 404 Not Found"},
+		{"-0001.req.headers", "POST " + internal.Path + internal.TagPath},
+		{"-0001.req.json", `{"name":"foo"}`},
+		{"-0001.res.headers", "404 Not Found"},
+		{"-0001.res.json", "errors.not_found"},
+	}
+
+	for _, test := range tests {
+		if f := p.content(test.suffix); !strings.Contains(f, test.expect) {
+			t.Errorf("%s: %q does not contain %q", test.suffix, f, test.expect)
+		}
+	}
+}
+
+func TestDebugLogin(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		for _, api := range []bool{false, true} {
+			p := &debugProvider{files: make(map[string]*debugBuffer)}
+			debug.SetProvider(p)
+
+			c := rest.NewClient(vc)
+			if api {
+				c.UseAPI()
+			}
+			if err := c.Login(ctx, simulator.DefaultLogin); err != nil {
+				t.Fatal(err)
+			}
+			debug.SetProvider(nil)
+
+			id := c.SessionID()
+			if id == "" {
+				t.Fatal("no session ID")
+			}
+			if f := p.content(""); strings.Contains(f, id) {
+				t.Errorf("api=%t: session ID %q in %q", api, id, f)
+			}
+			if f := p.content("-0001.res.json"); !strings.Contains(f, `"********"`) {
+				t.Errorf("api=%t: %q", api, f)
+			}
+		}
+	})
+}
@@ -0,0 +1,136 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// StdError is a standard VAPI error (com.vmware.vapi.std.errors), as decoded from an error response body.
+// Errors of the following types are returned as NotFound, AlreadyExists, Unauthorized, InvalidArgument
+// and ResourceBusy respectively, which callers can match using errors.As. Other types are returned as a *StdError.
+type StdError struct {
+	StatusCode int                  `json:"-"`
+	Type       string               `json:"type"`
+	Messages   []LocalizableMessage `json:"messages,omitempty"`
+	Data       json.RawMessage      `json:"data,omitempty"`
+
+	res *http.Response
+}
+
+func (e *StdError) Error() string {
+	msg := fmt.Sprintf("%s: %s", requestString(e.res.Request), e.res.Status)
+
+	var details []string
+	for _, m := range e.Messages {
+		if m.DefaultMessage != "" {
+			details = append(details, m.DefaultMessage)
+		}
+	}
+	if len(details) == 0 {
+		details = append(details, e.Type)
+	}
+
+	return msg + ": " + strings.Join(details, "; ")
+}
+
+// Kind returns the Type without the namespace prefix, in lower case. For example: "not_found".
+func (e *StdError) Kind() string {
+	kind := e.Type
+	if i := strings.LastIndex(kind, "."); i >= 0 {
+		kind = kind[i+1:]
+	}
+	return strings.ToLower(kind)
+}
+
+func (e *StdError) stdError() *StdError {
+	return e
+}
+
+// NotFound indicates the specified resource does not exist.
+type NotFound struct{ *StdError }
+
+// AlreadyExists indicates an attempt was made to create a resource that already exists.
+type AlreadyExists struct{ *StdError }
+
+// Unauthorized indicates the user is not authenticated or is not authorized to perform the operation.
+type Unauthorized struct{ *StdError }
+
+// InvalidArgument indicates the values received for one or more parameters are not acceptable.
+type InvalidArgument struct{ *StdError }
+
+// ResourceBusy indicates the operation could not be completed because the resource is in use.
+type ResourceBusy struct{ *StdError }
+
+// decodeError decodes a VAPI error from the given response body, returning nil if the body is not a VAPI error.
+func decodeError(res *http.Response, body []byte) error {
+	var val struct {
+		StdError
+		// The /rest protocol wraps the error value, where type is the fully qualified error type name.
+		Value *StdError `json:"value"`
+		// The /api protocol does not wrap the error value, where error_type is the constant name.
+		ErrorType string `json:"error_type"`
+	}
+
+	if err := json.NewDecoder(bytes.NewReader(body)).Decode(&val); err != nil {
+		return nil
+	}
+
+	e := &val.StdError
+	if val.Value != nil {
+		e.Messages = val.Value.Messages
+		e.Data = val.Value.Data
+	}
+	if val.ErrorType != "" {
+		e.Type = val.ErrorType
+	}
+	if e.Type == "" {
+		return nil
+	}
+	e.StatusCode = res.StatusCode
+	e.res = res
+
+	switch e.Kind() {
+	case "not_found":
+		return &NotFound{e}
+	case "already_exists":
+		return &AlreadyExists{e}
+	case "unauthenticated", "unauthorized":
+		return &Unauthorized{e}
+	case "invalid_argument":
+		return &InvalidArgument{e}
+	case "resource_busy":
+		return &ResourceBusy{e}
+	default:
+		return e
+	}
+}
+
+// errorResponse returns the http.Response of an error returned by Client.Do, nil if the request was not sent.
+func errorResponse(err error) *http.Response {
+	switch e := err.(type) {
+	case *statusError:
+		return e.res
+	case interface{ stdError() *StdError }:
+		return e.stdError().res
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rest_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/internal"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vapi/tags"
+	"github.com/vmware/govmomi/vim25"
+)
+
+func TestErrorDecode(t *testing.T) {
+	tests := []struct {
+		path   string
+		status int
+		body   string
+		match  func(error) bool
+		kind   string
+	}{
+		{
+			internal.TagPath, http.StatusNotFound,
+			`{"type":"com.vmware.vapi.std.errors.not_found","value":{"messages":[{"id":"tag","default_message":"Tag not found."}]}}`,
+			func(err error) bool { var e *rest.NotFound; return errors.As(err, &e) },
+			"not_found",
+		},
+		{
+			internal.TagPath, http.StatusBadRequest,
+			`{"type":"com.vmware.vapi.std.errors.already_exists","value":{}}`,
+			func(err error) bool { var e *rest.AlreadyExists; return errors.As(err, &e) },
+			"already_exists",
+		},
+		{
+			internal.APIPath + "/vcenter/vm", http.StatusUnauthorized,
+			`{"error_type":"UNAUTHENTICATED","messages":[]}`,
+			func(err error) bool { var e *rest.Unauthorized; return errors.As(err, &e) },
+			"unauthenticated",
+		},
+		{
+			internal.APIPath + "/vcenter/vm", http.StatusBadRequest,
+			`{"error_type":"INVALID_ARGUMENT","messages":[{"default_message":"Invalid name."}]}`,
+			func(err error) bool { var e *rest.InvalidArgument; return errors.As(err, &e) },
+			"invalid_argument",
+		},
+		{
+			internal.TagPath, http.StatusBadRequest,
+			`{"type":"com.vmware.vapi.std.errors.resource_busy"}`,
+			func(err error) bool { var e *rest.ResourceBusy; return errors.As(err, &e) },
+			"resource_busy",
+		},
+		{
+			internal.TagPath, http.StatusInternalServerError,
+			`{"type":"com.vmware.vapi.std.errors.internal_server_error"}`,
+			func(err error) bool { var e *rest.StdError; return errors.As(err, &e) },
+			"internal_server_error",
+		},
+	}
+
+	for _, test := range tests {
+		h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(test.status)
+			_, _ = w.Write([]byte(test.body))
+		})
+
+		c, done := newTestClient(t, h)
+		ctx := context.Background()
+		err := c.Do(ctx, internal.URL(c, test.path).Request(ctx, http.MethodGet), nil)
+		done()
+
+		if !test.match(err) {
+			t.Errorf("%s: unexpected error type %T: %s", test.kind, err, err)
+			continue
+		}
+
+		e, ok := err.(interface{ Kind() string })
+		if !ok || e.Kind() != test.kind {
+			t.Errorf("%s: unexpected error %#v", test.kind, err)
+		}
+	}
+}
+
+func TestErrorAlreadyExists(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+		if err := c.Login(ctx, simulator.DefaultLogin); err != nil {
+			t.Fatal(err)
+		}
+
+		m := tags.NewManager(c)
+		category := tags.Category{Name: "my-category", Cardinality: "SINGLE"}
+		if _, err := m.CreateCategory(ctx, &category); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err := m.CreateCategory(ctx, &category)
+		var e *rest.AlreadyExists
+		if !errors.As(err, &e) {
+			t.Fatalf("unexpected error %T: %s", err, err)
+		}
+		if e.StatusCode != http.StatusBadRequest || !strings.Contains(e.Error(), "already_exists") {
+			t.Errorf("error=%s", e)
+		}
+	})
+}
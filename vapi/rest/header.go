@@ -0,0 +1,94 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rest
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// OpIDHeader is the request header used to send an operation ID, for correlating requests with server side logs.
+const OpIDHeader = "X-Request-ID"
+
+type headerContext struct{}
+
+// WithHeader returns a context with the given headers, which are sent with each request made using that context.
+func (c *Client) WithHeader(ctx context.Context, header http.Header) context.Context {
+	return context.WithValue(ctx, headerContext{}, header)
+}
+
+// WithOpID configures the Client to generate an operation ID for each request.
+// The ID is sent using the OpIDHeader and can be retrieved from a request error using OpID.
+// A request that already has the OpIDHeader set, or a context with a types.ID value
+// (as used by soap.Client for the SOAP operationID), is not assigned a generated ID.
+func WithOpID() Option {
+	return func(c *Client) {
+		b := make([]byte, 4)
+		_, _ = rand.Read(b)
+		c.opID = &opID{prefix: hex.EncodeToString(b)}
+	}
+}
+
+type opID struct {
+	prefix string
+	n      uint64
+}
+
+func (o *opID) next() string {
+	return fmt.Sprintf("%s-%04d", o.prefix, atomic.AddUint64(&o.n, 1))
+}
+
+// OpID returns the operation ID of the request that failed with the given error, if any.
+func OpID(err error) string {
+	if res := errorResponse(err); res != nil {
+		return res.Request.Header.Get(OpIDHeader)
+	}
+	return ""
+}
+
+func (c *Client) setHeader(ctx context.Context, req *http.Request) {
+	if header, ok := ctx.Value(headerContext{}).(http.Header); ok {
+		for name, values := range header {
+			req.Header[name] = values
+		}
+	}
+
+	if req.Header.Get(OpIDHeader) != "" {
+		return
+	}
+
+	if id, ok := ctx.Value(types.ID{}).(string); ok {
+		req.Header.Set(OpIDHeader, id)
+	} else if c.opID != nil {
+		req.Header.Set(OpIDHeader, c.opID.next())
+	}
+}
+
+// requestString formats the request method and URL for use in error messages, including the opID if any.
+func requestString(req *http.Request) string {
+	s := req.Method + " " + req.URL.String()
+	if id := req.Header.Get(OpIDHeader); id != "" {
+		s += " (opID=" + id + ")"
+	}
+	return s
+}
@@ -0,0 +1,94 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rest_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/vmware/govmomi/vapi/internal"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+func TestHeader(t *testing.T) {
+	var header http.Header
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"value": "ok"}`))
+	})
+
+	c, done := newTestClient(t, h, rest.WithOpID())
+	defer done()
+
+	ctx := context.Background()
+	url := internal.URL(c, internal.TagPath)
+
+	if err := c.Do(ctx, url.Request(ctx, http.MethodGet), nil); err != nil {
+		t.Fatal(err)
+	}
+	first := header.Get(rest.OpIDHeader)
+	if first == "" {
+		t.Fatal("no opID")
+	}
+
+	err := c.Do(ctx, url.Request(ctx, http.MethodDelete), nil)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	id := rest.OpID(err)
+	if id == "" || id == first || id != header.Get(rest.OpIDHeader) {
+		t.Errorf("opID=%q, first=%q", id, first)
+	}
+	if !strings.Contains(err.Error(), "opID="+id) {
+		t.Errorf("error=%s", err)
+	}
+
+	ctx = context.WithValue(ctx, types.ID{}, "govc-op")
+	ctx = c.WithHeader(ctx, http.Header{"X-Context": []string{"ctx"}})
+	req := url.WithHeader("X-Resource", "resource").WithHeader("Accept", "text/plain").Request(ctx, http.MethodGet)
+	if err = c.Do(ctx, req, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	expect := map[string]string{
+		rest.OpIDHeader: "govc-op",
+		"X-Context":     "ctx",
+		"X-Resource":    "resource",
+		"Accept":        "text/plain",
+	}
+	for name, val := range expect {
+		if header.Get(name) != val {
+			t.Errorf("%s=%q", name, header.Get(name))
+		}
+	}
+
+	for _, method := range []string{http.MethodPost, http.MethodPut, http.MethodPatch} {
+		if err = c.Do(ctx, url.Request(ctx, method, map[string]bool{"enabled": true}), nil); err != nil {
+			t.Fatal(err)
+		}
+		if kind := header.Get("Content-Type"); kind != "application/json" {
+			t.Errorf("%s Content-Type=%q", method, kind)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rest
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrSessionNotAuthenticated is returned by the default keep alive handler when the session is no longer valid.
+var ErrSessionNotAuthenticated = errors.New("session is not authenticated")
+
+type keepAlive struct {
+	sync.Mutex
+
+	client          *Client
+	idleTime        time.Duration
+	notifyRequest   chan struct{}
+	notifyStop      chan struct{}
+	notifyWaitGroup sync.WaitGroup
+
+	// login is called when the keep alive request finds the session is no longer authenticated.
+	login func(*Client) error
+}
+
+// KeepAlive executes a session GET request in the background after the Client has been idle for the
+// specified amount of idle time, with the purpose of keeping the session active.
+// The keep alive process only starts once a user logs in and runs until the user logs out again.
+func (c *Client) KeepAlive(idleTime time.Duration) {
+	c.KeepAliveHandler(idleTime, nil)
+}
+
+// KeepAliveHandler works as KeepAlive() does, but the login func is called when the session is gone,
+// for example if connectivity to VC was down long enough for the session to expire.
+// If login is nil or returns non-nil, the keep alive go routine will be stopped.
+// The login func is called from the keep alive go routine and must not call Logout.
+func (c *Client) KeepAliveHandler(idleTime time.Duration, login func(*Client) error) {
+	if c.keepAlive != nil {
+		c.keepAlive.stop()
+	}
+
+	c.keepAlive = &keepAlive{
+		client:        c,
+		idleTime:      idleTime,
+		notifyRequest: make(chan struct{}),
+		login:         login,
+	}
+
+	if c.session() != "" {
+		c.keepAlive.start()
+	}
+}
+
+func (k *keepAlive) keepAlive() error {
+	s, err := k.client.Session(context.Background())
+	if err != nil {
+		return err
+	}
+	if s != nil {
+		return nil
+	}
+	if k.login == nil {
+		return ErrSessionNotAuthenticated
+	}
+	return k.login(k.client)
+}
+
+func (k *keepAlive) start() {
+	k.Lock()
+	defer k.Unlock()
+
+	if k.notifyStop != nil {
+		return
+	}
+
+	// This channel must be closed to terminate idle timer.
+	stop := make(chan struct{})
+	k.notifyStop = stop
+	k.notifyWaitGroup.Add(1)
+
+	go func() {
+		defer k.notifyWaitGroup.Done()
+
+		for t := time.NewTimer(k.idleTime); ; {
+			select {
+			case <-stop:
+				t.Stop()
+				return
+			case <-k.notifyRequest:
+				if !t.Stop() {
+					<-t.C
+				}
+				t.Reset(k.idleTime)
+			case <-t.C:
+				if err := k.keepAlive(); err != nil {
+					k.Lock()
+					if k.notifyStop == stop {
+						k.notifyStop = nil
+					}
+					k.Unlock()
+					return
+				}
+				t.Reset(k.idleTime)
+			}
+		}
+	}()
+}
+
+func (k *keepAlive) stop() {
+	k.Lock()
+	stop := k.notifyStop
+	k.notifyStop = nil
+	k.Unlock()
+
+	if stop != nil {
+		close(stop)
+		k.notifyWaitGroup.Wait()
+	}
+}
+
+// request resets the idle timer, unless the keep alive go routine is busy.
+func (k *keepAlive) request() {
+	select {
+	case k.notifyRequest <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,75 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rest_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/internal"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vim25"
+)
+
+func TestKeepAlive(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+
+		err := c.Login(ctx, simulator.DefaultLogin)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		relogin := make(chan error, 1)
+		c.KeepAliveHandler(time.Millisecond*10, func(c *rest.Client) error {
+			err := c.Login(ctx, simulator.DefaultLogin)
+			relogin <- err
+			return err
+		})
+
+		// Destroy the session behind the Client's back, the session cookie is sent via the soap.Client's Jar
+		req := internal.URL(c, internal.SessionPath).Request(ctx, http.MethodDelete)
+		err = c.Client.Do(ctx, req, func(*http.Response) error { return nil })
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		select {
+		case err = <-relogin:
+			if err != nil {
+				t.Fatal(err)
+			}
+		case <-time.After(time.Second * 5):
+			t.Fatal("keep alive did not call login")
+		}
+
+		session, err := c.Session(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if session == nil {
+			t.Fatal("expected non-nil session")
+		}
+
+		if err = c.Logout(ctx); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
@@ -0,0 +1,75 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rest
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Limiter is used to limit the rate of requests sent by a Client.
+// The golang.org/x/time/rate.Limiter type implements this interface.
+type Limiter interface {
+	// Wait blocks until a request is allowed to be sent, or returns an error if ctx is done first.
+	Wait(ctx context.Context) error
+}
+
+// WithLimiter configures the Client to call l.Wait before sending each request, including retries.
+func WithLimiter(l Limiter) Option {
+	return func(c *Client) {
+		c.limiter = l
+	}
+}
+
+type intervalLimiter struct {
+	sync.Mutex
+
+	interval time.Duration
+	next     time.Time
+}
+
+// NewIntervalLimiter returns a Limiter that allows one request per the given interval.
+func NewIntervalLimiter(interval time.Duration) Limiter {
+	return &intervalLimiter{interval: interval}
+}
+
+func (l *intervalLimiter) Wait(ctx context.Context) error {
+	l.Lock()
+	now := time.Now()
+	at := l.next
+	if at.Before(now) {
+		at = now
+	}
+	l.next = at.Add(l.interval)
+	l.Unlock()
+
+	delay := time.Until(at)
+	if delay <= 0 {
+		return ctx.Err()
+	}
+
+	t := time.NewTimer(delay)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
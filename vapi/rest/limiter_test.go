@@ -0,0 +1,64 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rest_test
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vmware/govmomi/vapi/internal"
+	"github.com/vmware/govmomi/vapi/rest"
+)
+
+func TestLimiter(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"value": "ok"}`))
+	})
+
+	interval := 20 * time.Millisecond
+	c, done := newTestClient(t, h, rest.WithLimiter(rest.NewIntervalLimiter(interval)))
+	defer done()
+
+	ctx := context.Background()
+	n := 5
+	start := time.Now()
+
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := c.Do(ctx, internal.URL(c, internal.TagPath).Request(ctx, http.MethodGet), nil); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if elapsed := time.Since(start); elapsed < time.Duration(n-1)*interval {
+		t.Errorf("%d requests in %s", n, elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := c.Do(ctx, internal.URL(c, internal.TagPath).Request(ctx, http.MethodGet), nil); err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
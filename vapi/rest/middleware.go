@@ -0,0 +1,42 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rest
+
+import (
+	"context"
+	"net/http"
+)
+
+// Handler sends the http.Request, decoding the response "value" into resBody if provided.
+type Handler func(ctx context.Context, req *http.Request, resBody interface{}) error
+
+// Middleware wraps a Handler, for example to add logging, metrics or request headers.
+// When the wrapped Handler returns a nil error, resBody has been decoded.
+type Middleware func(Handler) Handler
+
+// WithMiddleware configures the Client to pass each request through the given Middleware.
+// The first Middleware is the outermost, the last wraps the request round trip.
+// When WithMiddleware is given more than once, each wraps the Middleware of those before it.
+// Middleware is called for each request attempt, including those made via WithRetry,
+// after the session and content type headers have been set.
+func WithMiddleware(m ...Middleware) Option {
+	return func(c *Client) {
+		for i := len(m) - 1; i >= 0; i-- {
+			c.handler = m[i](c.handler)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rest
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/vmware/govmomi/vapi/internal"
+)
+
+type reLogin struct {
+	sync.Mutex
+
+	login func(context.Context, *Client) error
+}
+
+// WithReLogin configures the Client to call login when a request fails with 401 (Unauthorized),
+// for example when the session has expired, and then retry the request once with the new session.
+// When concurrent requests fail due to the same expired session, login is called only once,
+// with the other requests waiting for it to complete.
+// The login func should create a new session using one of the Client Login methods.
+func WithReLogin(login func(context.Context, *Client) error) Option {
+	return func(c *Client) {
+		c.reLogin = &reLogin{login: login}
+	}
+}
+
+// reauthenticate calls the reLogin func, unless another request already replaced the stale session.
+func (c *Client) reauthenticate(ctx context.Context, stale string) error {
+	c.reLogin.Lock()
+	defer c.reLogin.Unlock()
+
+	if c.session() != stale {
+		return nil
+	}
+
+	c.setSession("")
+	return c.reLogin.login(ctx, c)
+}
+
+// shouldReLogin returns true if the request failed with 401 and was not a session request.
+func (c *Client) shouldReLogin(req *http.Request, err error) bool {
+	if c.reLogin == nil {
+		return false
+	}
+
+	res := errorResponse(err)
+	if res == nil || res.StatusCode != http.StatusUnauthorized {
+		return false
+	}
+
+	path := req.URL.Path
+	return !strings.HasPrefix(path, internal.Path+internal.SessionPath) && !strings.HasPrefix(path, internal.APISessionPath)
+}
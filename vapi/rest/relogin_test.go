@@ -0,0 +1,113 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rest_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/vmware/govmomi/vapi/internal"
+	"github.com/vmware/govmomi/vapi/rest"
+)
+
+// sessionServer is a stub vAPI endpoint where the session can be expired.
+type sessionServer struct {
+	sync.Mutex
+	logins  int
+	current string
+}
+
+func (s *sessionServer) expire() {
+	s.Lock()
+	s.current = ""
+	s.Unlock()
+}
+
+func (s *sessionServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.Lock()
+	defer s.Unlock()
+
+	if r.URL.Path == internal.Path+internal.SessionPath {
+		s.logins++
+		s.current = fmt.Sprintf("session-%d", s.logins)
+		_, _ = fmt.Fprintf(w, `{"value": %q}`, s.current)
+		return
+	}
+
+	if s.current == "" || r.Header.Get(internal.SessionCookieName) != s.current {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	_, _ = w.Write([]byte(`{"value": "ok"}`))
+}
+
+func TestReLogin(t *testing.T) {
+	user := url.UserPassword("user", "pass")
+
+	for _, relogin := range []bool{false, true} {
+		var opts []rest.Option
+		if relogin {
+			opts = append(opts, rest.WithReLogin(func(ctx context.Context, c *rest.Client) error {
+				return c.Login(ctx, user)
+			}))
+		}
+
+		s := new(sessionServer)
+		c, done := newTestClient(t, s, opts...)
+
+		ctx := context.Background()
+		if err := c.Login(ctx, user); err != nil {
+			t.Fatal(err)
+		}
+		s.expire()
+
+		var wg sync.WaitGroup
+		errs := make(chan error, 10)
+		for i := 0; i < cap(errs); i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				var res string
+				errs <- c.Do(ctx, internal.URL(c, internal.TagPath).Request(ctx, http.MethodGet), &res)
+			}()
+		}
+		wg.Wait()
+		close(errs)
+		done()
+
+		for err := range errs {
+			if relogin && err != nil {
+				t.Error(err)
+			}
+			if !relogin && err == nil {
+				t.Error("expected error")
+			}
+		}
+
+		expect := 1
+		if relogin {
+			expect = 2
+		}
+		if s.logins != expect {
+			t.Errorf("relogin=%t: logins=%d", relogin, s.logins)
+		}
+	}
+}
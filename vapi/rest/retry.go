@@ -0,0 +1,103 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rest
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryFunc is called when Client.Do returns an error, where attempt is the number of requests sent so far.
+// It returns whether or not to retry the request, and if so, how long to delay before retrying.
+type RetryFunc func(err error, attempt int) (retry bool, delay time.Duration)
+
+// WithRetry configures the Client to call fn when a request fails, re-sending the request if fn returns true.
+func WithRetry(fn RetryFunc) Option {
+	return func(c *Client) {
+		c.retry = fn
+	}
+}
+
+// ThrottledRetry returns a RetryFunc that retries a request up to a maximum of n times,
+// only if the server responded with 429 (Too Many Requests) or 503 (Service Unavailable).
+// The delay before retrying is taken from the response Retry-After header if present,
+// otherwise the given delay is doubled after each attempt.
+// If idempotent is true, only requests with an idempotent method (GET, HEAD, PUT, DELETE, OPTIONS) are retried.
+func ThrottledRetry(n int, delay time.Duration, idempotent bool) RetryFunc {
+	return func(err error, attempt int) (bool, time.Duration) {
+		res := errorResponse(err)
+		if res == nil {
+			return false, 0
+		}
+
+		switch res.StatusCode {
+		case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		default:
+			return false, 0
+		}
+
+		if idempotent && !isIdempotent(res.Request.Method) {
+			return false, 0
+		}
+
+		// Don't retry if we're out of tries.
+		if attempt > n {
+			return false, 0
+		}
+
+		if d, ok := retryAfter(res.Header); ok {
+			return true, d
+		}
+
+		return true, delay << uint(attempt-1)
+	}
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// retryAfter parses the Retry-After header, which is either a number of seconds or an HTTP date.
+func retryAfter(h http.Header) (time.Duration, bool) {
+	val := h.Get("Retry-After")
+	if val == "" {
+		return 0, false
+	}
+
+	if n, err := strconv.Atoi(val); err == nil {
+		if n < 0 {
+			return 0, false
+		}
+		return time.Duration(n) * time.Second, true
+	}
+
+	t, err := http.ParseTime(val)
+	if err != nil {
+		return 0, false
+	}
+
+	d := time.Until(t)
+	if d < 0 {
+		d = 0
+	}
+	return d, true
+}
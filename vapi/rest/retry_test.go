@@ -0,0 +1,91 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rest_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/vmware/govmomi/vapi/internal"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/soap"
+)
+
+func newTestClient(t *testing.T, h http.Handler, opts ...rest.Option) (*rest.Client, func()) {
+	s := httptest.NewServer(h)
+	u, err := url.Parse(s.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vc := &vim25.Client{Client: soap.NewClient(u, true)}
+	return rest.NewClient(vc, opts...), s.Close
+}
+
+func TestThrottledRetry(t *testing.T) {
+	tests := []struct {
+		method     string
+		idempotent bool
+		attempts   int
+		fail       bool
+	}{
+		{http.MethodPost, false, 3, false},
+		{http.MethodPost, true, 1, true},
+		{http.MethodGet, true, 3, false},
+	}
+
+	for _, test := range tests {
+		attempts := 0
+		h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			if b, _ := ioutil.ReadAll(r.Body); r.Method == http.MethodPost && len(b) == 0 {
+				t.Error("empty request body")
+			}
+			if attempts < 3 {
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			_, _ = w.Write([]byte(`{"value": "ok"}`))
+		})
+
+		c, done := newTestClient(t, h, rest.WithRetry(rest.ThrottledRetry(5, time.Millisecond, test.idempotent)))
+
+		ctx := context.Background()
+		var res string
+		req := internal.URL(c, internal.TagPath).Request(ctx, test.method, struct{ Name string }{"foo"})
+		err := c.Do(ctx, req, &res)
+		done()
+
+		if test.fail {
+			if err == nil {
+				t.Errorf("%s: expected error", test.method)
+			}
+		} else if err != nil {
+			t.Errorf("%s: %s", test.method, err)
+		}
+
+		if attempts != test.attempts {
+			t.Errorf("%s: attempts=%d", test.method, attempts)
+		}
+	}
+}
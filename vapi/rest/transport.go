@@ -0,0 +1,54 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rest
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/url"
+)
+
+// WithTransport configures the Client to send requests using the given http.RoundTripper.
+// By default, the Client's transport is configured as the vim25.Client transport is, inheriting its TLS settings,
+// trusted thumbprints and proxy. This option, WithTLSConfig and WithProxy do not modify the vim25.Client transport.
+// Note that the Client's TLS settings (certificate and thumbprint verification) do not apply to a custom
+// RoundTripper, and that the Client methods which require an *http.Transport, such as SetCertificate, will panic
+// if rt is not an *http.Transport.
+func WithTransport(rt http.RoundTripper) Option {
+	return func(c *Client) {
+		c.Client.Transport = rt
+	}
+}
+
+// WithTLSConfig configures the Client to use the given tls.Config, rather than that of the vim25.Client.
+// Thumbprint verification, see soap.Client.SetThumbprint, still applies when certificate verification fails.
+func WithTLSConfig(config *tls.Config) Option {
+	return func(c *Client) {
+		if t, ok := c.Client.Transport.(*http.Transport); ok {
+			t.TLSClientConfig = config
+		}
+	}
+}
+
+// WithProxy configures the Client to use the given proxy func, such as http.ProxyURL, rather than that of the vim25.Client.
+func WithProxy(proxy func(*http.Request) (*url.URL, error)) Option {
+	return func(c *Client) {
+		if t, ok := c.Client.Transport.(*http.Transport); ok {
+			t.Proxy = proxy
+		}
+	}
+}
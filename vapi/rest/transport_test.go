@@ -0,0 +1,124 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rest_test
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/internal"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/soap"
+)
+
+func TestWithTLSConfig(t *testing.T) {
+	s := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"value": "ok"}`))
+	}))
+	s.Config.ErrorLog = log.New(ioutil.Discard, "", 0) // expected handshake error
+	s.StartTLS()
+	defer s.Close()
+
+	u, _ := url.Parse(s.URL)
+	vc := &vim25.Client{Client: soap.NewClient(u, false)}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(s.Certificate())
+	config := &tls.Config{RootCAs: pool}
+
+	ctx := context.Background()
+	for _, opts := range [][]rest.Option{nil, {rest.WithTLSConfig(config)}} {
+		c := rest.NewClient(vc, opts...)
+		err := c.Do(ctx, internal.URL(c, internal.TagPath).Request(ctx, http.MethodGet), nil)
+		if opts == nil && err == nil {
+			t.Error("expected certificate verification error")
+		}
+		if opts != nil && err != nil {
+			t.Error(err)
+		}
+	}
+
+	if vc.Transport.(*http.Transport).TLSClientConfig.RootCAs != nil {
+		t.Error("vim25.Client transport was modified")
+	}
+}
+
+func TestWithProxy(t *testing.T) {
+	var requests []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.String())
+		_, _ = w.Write([]byte(`{"value": "ok"}`))
+	}))
+	defer proxy.Close()
+
+	vc := &vim25.Client{Client: soap.NewClient(&url.URL{Scheme: "http", Host: "vcenter.invalid"}, true)}
+	p, _ := url.Parse(proxy.URL)
+
+	ctx := context.Background()
+	c := rest.NewClient(vc, rest.WithProxy(http.ProxyURL(p)))
+	req := internal.URL(c, internal.TagPath).Request(ctx, http.MethodGet)
+	if err := c.Do(ctx, req, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(requests) != 1 || requests[0] != req.URL.String() {
+		t.Errorf("requests=%v", requests)
+	}
+
+	if vc.Transport.(*http.Transport).Proxy == nil {
+		t.Error("vim25.Client transport was modified")
+	}
+}
+
+type roundTripper func(*http.Request) (*http.Response, error)
+
+func (f roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestWithTransport(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		trips := 0
+		rt := roundTripper(func(req *http.Request) (*http.Response, error) {
+			trips++
+			return vc.Transport.RoundTrip(req)
+		})
+
+		c := rest.NewClient(vc, rest.WithTransport(rt))
+		if err := c.Login(ctx, simulator.DefaultLogin); err != nil {
+			t.Fatal(err)
+		}
+
+		// Clone applies the same options to a copy of the vim25.Client transport
+		if _, err := c.Clone(ctx, c.SessionID()); err != nil {
+			t.Fatal(err)
+		}
+
+		if trips != 2 {
+			t.Errorf("trips=%d", trips)
+		}
+	})
+}
@@ -0,0 +1,1360 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/vmware/govmomi/vapi/appliance/access"
+	"github.com/vmware/govmomi/vapi/appliance/accounts"
+	"github.com/vmware/govmomi/vapi/appliance/health"
+	"github.com/vmware/govmomi/vapi/appliance/monitoring"
+	"github.com/vmware/govmomi/vapi/appliance/networking"
+	"github.com/vmware/govmomi/vapi/appliance/recovery"
+	"github.com/vmware/govmomi/vapi/appliance/services"
+	"github.com/vmware/govmomi/vapi/appliance/shutdown"
+	appupdate "github.com/vmware/govmomi/vapi/appliance/update"
+	"github.com/vmware/govmomi/vapi/appliance/vmon"
+	"github.com/vmware/govmomi/vapi/internal"
+	"github.com/vmware/govmomi/vapi/rest"
+)
+
+// appliance simulates the vCenter Server Appliance management API (/appliance).
+type appliance struct {
+	Health     map[string]string
+	LastCheck  time.Time
+	Monitoring []monitoring.Item
+	Service    map[string]*applianceService
+	BackupPart []recovery.BackupPart
+	BackupJob  map[string]*recovery.BackupJobStatus
+	RestoreJob *recovery.RestoreJobStatus
+	Update     applianceUpdate
+	Network    applianceNetwork
+	Access     map[string]bool
+	Shell      time.Time
+	Shutdown   shutdown.Config
+	Account    map[string]*applianceAccount
+	Policy     accounts.Policy
+}
+
+// applianceAccount is a local account of the appliance
+type applianceAccount struct {
+	accounts.Info
+	Password        string
+	PasswordExpires bool
+}
+
+// applianceNetwork is the state of the /appliance/networking API
+type applianceNetwork struct {
+	Interface []networking.Interface
+	DNS       networking.DNSServers
+	Hostname  string
+	Proxy     map[string]networking.Proxy
+	Firewall  []networking.FirewallRule
+}
+
+// applianceUpdate is the state of the /appliance/update API
+type applianceUpdate struct {
+	appupdate.Info
+	Pending   []appupdate.PendingInfo
+	Questions []appupdate.Question
+	Staged    *appupdate.StagedInfo
+	Policy    appupdate.Policy
+}
+
+// applianceService is exposed by both the /appliance/services and /appliance/vmon/service APIs
+type applianceService struct {
+	vmon.Info
+	Description string
+}
+
+func newAppliance() *appliance {
+	a := &appliance{
+		Health: map[string]string{
+			"applmgmt":          health.Green,
+			"database-storage":  health.Green,
+			"load":              health.Green,
+			"mem":               health.Green,
+			"software-packages": health.Green,
+			"storage":           health.Green,
+			"swap":              health.Green,
+			"system":            health.Green,
+		},
+		LastCheck: time.Now().UTC(),
+		Monitoring: []monitoring.Item{
+			{ID: "cpu.util", Name: "com.vmware.applmgmt.mon.name.cpu.util", Units: "com.vmware.applmgmt.mon.unit.percent",
+				Category: "com.vmware.applmgmt.mon.cat.cpu", Description: "com.vmware.applmgmt.mon.descr.cpu.util"},
+			{ID: "mem.util", Name: "com.vmware.applmgmt.mon.name.mem.util", Units: "com.vmware.applmgmt.mon.unit.percent",
+				Category: "com.vmware.applmgmt.mon.cat.memory", Description: "com.vmware.applmgmt.mon.descr.mem.util"},
+			{ID: "mem.usage", Name: "com.vmware.applmgmt.mon.name.mem.usage", Units: "com.vmware.applmgmt.mon.unit.kb",
+				Category: "com.vmware.applmgmt.mon.cat.memory", Description: "com.vmware.applmgmt.mon.descr.mem.usage"},
+			{ID: "swap.util", Name: "com.vmware.applmgmt.mon.name.swap.util", Units: "com.vmware.applmgmt.mon.unit.percent",
+				Category: "com.vmware.applmgmt.mon.cat.memory", Description: "com.vmware.applmgmt.mon.descr.swap.util"},
+			{ID: "storage.util.filesystem.root", Name: "com.vmware.applmgmt.mon.name.storage.util.filesystem.root",
+				Units: "com.vmware.applmgmt.mon.unit.percent", Category: "com.vmware.applmgmt.mon.cat.storage",
+				Instance: "root", Description: "com.vmware.applmgmt.mon.descr.storage.util.filesystem.root"},
+		},
+		Service: make(map[string]*applianceService),
+		BackupPart: []recovery.BackupPart{
+			{ID: "common", Name: rest.LocalizableMessage{DefaultMessage: "Inventory and configuration"},
+				Description: rest.LocalizableMessage{DefaultMessage: "Inventory and configuration"}, SelectedByDefault: true},
+			{ID: "seat", Name: rest.LocalizableMessage{DefaultMessage: "Stats, Events, and Tasks"},
+				Description: rest.LocalizableMessage{DefaultMessage: "Historical data"}},
+		},
+		BackupJob: make(map[string]*recovery.BackupJobStatus),
+		Update: applianceUpdate{
+			Info: appupdate.Info{
+				State:   appupdate.StateUpToDate,
+				Version: "6.7.0.40000",
+			},
+			Pending: []appupdate.PendingInfo{{
+				Summary: appupdate.Summary{
+					Version:        "6.7.0.41000",
+					Name:           rest.LocalizableMessage{DefaultMessage: "VMware vCenter Server Appliance 6.7 Update 3g"},
+					Description:    rest.LocalizableMessage{DefaultMessage: "Security and bug fixes"},
+					Priority:       "HIGH",
+					Severity:       "CRITICAL",
+					UpdateType:     "SECURITY",
+					ReleaseDate:    time.Date(2020, time.March, 26, 0, 0, 0, 0, time.UTC),
+					RebootRequired: true,
+					Size:           2147,
+				},
+				ServicesWillBeStopped: []string{"vpxd", "content-library", "vsphere-ui"},
+				EULAs:                 []rest.LocalizableMessage{},
+			}},
+			Questions: []appupdate.Question{{
+				DataItem:    "vmdir.password",
+				Text:        rest.LocalizableMessage{DefaultMessage: "Single Sign-On administrator password"},
+				Description: rest.LocalizableMessage{DefaultMessage: "For the first instance of the identity domain, this is the password given to the Administrator account."},
+				Type:        appupdate.QuestionPassword,
+			}},
+			Policy: appupdate.Policy{
+				DefaultURL:    "https://vapp-updates.vmware.com/vai-catalog/valm/vmw/8d167796-34d5-4899-be0a-6daade4005a3/6.7.0.40000.latest/",
+				CheckSchedule: []appupdate.Time{{Day: "SUNDAY", Hour: 0, Minute: 0}},
+				ManualControl: true,
+			},
+		},
+		Network: applianceNetwork{
+			Interface: []networking.Interface{{
+				Name:   "nic0",
+				Status: networking.StatusUp,
+				MAC:    "00:50:56:8a:2b:3c",
+				IPv4: &networking.IPv4{
+					Configurable:   true,
+					Mode:           networking.ModeStatic,
+					Address:        "10.0.0.10",
+					Prefix:         24,
+					DefaultGateway: "10.0.0.1",
+				},
+			}},
+			DNS:      networking.DNSServers{Mode: networking.DNSModeIsStatic, Servers: []string{"10.0.0.2"}},
+			Hostname: "vcsa.example.com",
+			Proxy: map[string]networking.Proxy{
+				networking.ProxyHTTP:  {},
+				networking.ProxyHTTPS: {},
+				networking.ProxyFTP:   {},
+			},
+			Firewall: []networking.FirewallRule{},
+		},
+		Access: map[string]bool{
+			"consolecli": true,
+			"dcui":       true,
+			"ssh":        false,
+		},
+		Account: make(map[string]*applianceAccount),
+		Policy:  accounts.Policy{MaxDays: 90, MinDays: 0, WarnDays: 7},
+	}
+
+	root := a.newAccount()
+	root.Roles = []string{accounts.RoleSuperAdmin}
+	root.FullName = "root"
+	root.setPassword("vmware")
+	a.Account["root"] = root
+
+	for name, description := range map[string]string{
+		"content-library": "VMware Content Library Service",
+		"eam":             "VMware ESX Agent Manager",
+		"sps":             "VMware vSphere Profile-Driven Storage Service",
+		"vapi-endpoint":   "VMware vAPI Endpoint",
+		"vpxd":            "VMware vCenter Server",
+		"vpxd-svcs":       "VMware vCenter-Services",
+		"vsphere-ui":      "VMware vSphere Client",
+	} {
+		a.Service[name] = &applianceService{
+			Info: vmon.Info{
+				NameKey:        "cis." + name + ".ServiceName",
+				DescriptionKey: "cis." + name + ".ServiceDescription",
+				StartupType:    vmon.StartupTypeAutomatic,
+				State:          vmon.StateStarted,
+				Health:         vmon.HealthHealthy,
+			},
+			Description: description,
+		}
+	}
+
+	return a
+}
+
+// monitoringIntervals maps monitoring.Query.Interval to its duration
+var monitoringIntervals = map[string]time.Duration{
+	monitoring.IntervalMinutes5:  5 * time.Minute,
+	monitoring.IntervalMinutes30: 30 * time.Minute,
+	monitoring.IntervalHours2:    2 * time.Hour,
+	monitoring.IntervalHours6:    6 * time.Hour,
+	monitoring.IntervalDay1:      24 * time.Hour,
+}
+
+func (s *handler) applianceHealth(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	component := strings.TrimPrefix(r.URL.Path, internal.Path+internal.ApplianceHealthPath+"/")
+	if component == "system/lastcheck" {
+		s.ok(w, s.Appliance.LastCheck)
+		return
+	}
+
+	if level, ok := s.Appliance.Health[component]; ok {
+		s.ok(w, level)
+		return
+	}
+
+	http.NotFound(w, r)
+}
+
+func (s *handler) applianceMonitoring(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	s.ok(w, s.Appliance.Monitoring)
+}
+
+func (s *handler) applianceMonitoringID(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	id := path.Base(r.URL.Path)
+	if id == "query" {
+		s.applianceMonitoringQuery(w, r)
+		return
+	}
+
+	for _, item := range s.Appliance.Monitoring {
+		if item.ID == id {
+			s.ok(w, item)
+			return
+		}
+	}
+
+	s.fail(w, "com.vmware.vapi.std.errors.not_found")
+}
+
+func (s *handler) applianceMonitoringQuery(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	interval, ok := monitoringIntervals[q.Get("item.interval")]
+	if !ok {
+		s.fail(w, "com.vmware.vapi.std.errors.invalid_argument")
+		return
+	}
+
+	switch q.Get("item.function") {
+	case monitoring.FunctionCount, monitoring.FunctionMax, monitoring.FunctionAvg, monitoring.FunctionMin:
+	default:
+		s.fail(w, "com.vmware.vapi.std.errors.invalid_argument")
+		return
+	}
+
+	start, err := time.Parse(time.RFC3339, q.Get("item.start_time"))
+	if err != nil {
+		s.fail(w, "com.vmware.vapi.std.errors.invalid_argument")
+		return
+	}
+	end, err := time.Parse(time.RFC3339, q.Get("item.end_time"))
+	if err != nil || end.Before(start) {
+		s.fail(w, "com.vmware.vapi.std.errors.invalid_argument")
+		return
+	}
+
+	known := make(map[string]bool)
+	for _, item := range s.Appliance.Monitoring {
+		known[item.ID] = true
+	}
+
+	var res []monitoring.Data
+	for _, name := range q["item.names"] {
+		if !known[name] {
+			s.fail(w, "com.vmware.vapi.std.errors.invalid_argument")
+			return
+		}
+
+		data := monitoring.Data{
+			Name:      name,
+			Interval:  q.Get("item.interval"),
+			Function:  q.Get("item.function"),
+			StartTime: start,
+			EndTime:   end,
+			Data:      []string{},
+		}
+		for t := start; t.Before(end); t = t.Add(interval) {
+			data.Data = append(data.Data, "0")
+		}
+		res = append(res, data)
+	}
+
+	s.ok(w, res)
+}
+
+// applianceServiceAction applies the start, stop or restart action to the given service.
+func (s *handler) applianceServiceAction(w http.ResponseWriter, r *http.Request, service *applianceService, action string) {
+	switch action {
+	case "start", "restart":
+		if service.StartupType == vmon.StartupTypeDisabled {
+			s.fail(w, "com.vmware.vapi.std.errors.not_allowed_in_current_state")
+			return
+		}
+		service.State = vmon.StateStarted
+		service.Health = vmon.HealthHealthy
+	case "stop":
+		service.State = vmon.StateStopped
+		service.Health = ""
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	s.ok(w)
+}
+
+// applianceServiceID returns the service and action of the given request path.
+func (s *handler) applianceServiceID(r *http.Request, prefix string) (*applianceService, string) {
+	p := strings.SplitN(strings.TrimPrefix(r.URL.Path, internal.Path+prefix+"/"), "/", 2)
+	action := ""
+	if len(p) == 2 {
+		action = p[1]
+	}
+	return s.Appliance.Service[p[0]], action
+}
+
+// applianceKeyValue is the /rest wire format of map entries
+type applianceKeyValue struct {
+	Key   string      `json:"key"`
+	Value interface{} `json:"value"`
+}
+
+func (s *handler) applianceServices(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	var res []applianceKeyValue
+	for name, service := range s.Appliance.Service {
+		res = append(res, applianceKeyValue{name, services.Info{Description: service.Description, State: service.State}})
+	}
+
+	s.ok(w, res)
+}
+
+func (s *handler) applianceServicesID(w http.ResponseWriter, r *http.Request) {
+	service, action := s.applianceServiceID(r, internal.ApplianceServicesPath)
+	if service == nil {
+		s.fail(w, "com.vmware.vapi.std.errors.not_found")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		s.ok(w, services.Info{Description: service.Description, State: service.State})
+	case http.MethodPost:
+		s.applianceServiceAction(w, r, service, action)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *handler) applianceVMonService(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	var res []applianceKeyValue
+	for name, service := range s.Appliance.Service {
+		res = append(res, applianceKeyValue{name, service.Info})
+	}
+
+	s.ok(w, res)
+}
+
+func (s *handler) applianceVMonServiceID(w http.ResponseWriter, r *http.Request) {
+	service, action := s.applianceServiceID(r, internal.ApplianceVMonServicePath)
+	if service == nil {
+		s.fail(w, "com.vmware.vapi.std.errors.not_found")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		s.ok(w, service.Info)
+	case http.MethodPatch:
+		var spec struct {
+			Spec vmon.Update `json:"spec"`
+		}
+		if !s.decode(r, w, &spec) {
+			return
+		}
+		switch spec.Spec.StartupType {
+		case vmon.StartupTypeManual, vmon.StartupTypeAutomatic, vmon.StartupTypeDisabled:
+			service.StartupType = spec.Spec.StartupType
+			s.ok(w)
+		default:
+			s.fail(w, "com.vmware.vapi.std.errors.invalid_argument")
+		}
+	case http.MethodPost:
+		s.applianceServiceAction(w, r, service, action)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// jobProgress is the progress a backup or restore job makes each time its status is queried
+const jobProgress = 50
+
+func (s *handler) applianceBackupParts(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	s.ok(w, s.Appliance.BackupPart)
+}
+
+func (s *handler) applianceBackupPartsID(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	id := path.Base(r.URL.Path)
+	for i, part := range s.Appliance.BackupPart {
+		if part.ID == id {
+			s.ok(w, (i+1)*1024)
+			return
+		}
+	}
+
+	s.fail(w, "com.vmware.vapi.std.errors.not_found")
+}
+
+// validLocation returns true if the backup location type and location are valid.
+func validLocation(kind, location string) bool {
+	switch kind {
+	case recovery.LocationFTP, recovery.LocationFTPS, recovery.LocationHTTP, recovery.LocationHTTPS,
+		recovery.LocationSCP, recovery.LocationSFTP, recovery.LocationNFS, recovery.LocationSMB:
+		return location != ""
+	}
+	return false
+}
+
+func (s *handler) applianceBackupJob(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		ids := []string{}
+		for id := range s.Appliance.BackupJob {
+			ids = append(ids, id)
+		}
+		s.ok(w, ids)
+	case http.MethodPost:
+		var spec struct {
+			Piece recovery.BackupRequest `json:"piece"`
+		}
+		if !s.decode(r, w, &spec) {
+			return
+		}
+
+		if !validLocation(spec.Piece.LocationType, spec.Piece.Location) {
+			s.fail(w, "com.vmware.vapi.std.errors.invalid_argument")
+			return
+		}
+		for _, name := range spec.Piece.Parts {
+			known := false
+			for _, part := range s.Appliance.BackupPart {
+				known = known || part.ID == name
+			}
+			if !known {
+				s.fail(w, "com.vmware.vapi.std.errors.invalid_argument")
+				return
+			}
+		}
+
+		for _, job := range s.Appliance.BackupJob {
+			if job.State == recovery.StateInProgress {
+				s.fail(w, "com.vmware.vapi.std.errors.not_allowed_in_current_state")
+				return
+			}
+		}
+
+		now := time.Now().UTC()
+		job := &recovery.BackupJobStatus{
+			ID:        fmt.Sprintf("%s-%d", now.Format("20060102-150405"), len(s.Appliance.BackupJob)),
+			State:     recovery.StateInProgress,
+			Messages:  []rest.LocalizableMessage{},
+			StartTime: now,
+		}
+		s.Appliance.BackupJob[job.ID] = job
+		s.ok(w, job)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *handler) applianceBackupJobID(w http.ResponseWriter, r *http.Request) {
+	p := strings.Split(strings.TrimPrefix(r.URL.Path, internal.Path+internal.ApplianceBackupPath+"/job/"), "/")
+	job, ok := s.Appliance.BackupJob[p[0]]
+	if !ok {
+		s.fail(w, "com.vmware.vapi.std.errors.not_found")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		if job.State == recovery.StateInProgress {
+			job.Progress += jobProgress
+			if job.Progress >= 100 {
+				now := time.Now().UTC()
+				job.Progress = 100
+				job.State = recovery.StateSucceeded
+				job.EndTime = &now
+			}
+		}
+		s.ok(w, job)
+	case http.MethodPost:
+		if len(p) != 2 || p[1] != "cancel" {
+			http.NotFound(w, r)
+			return
+		}
+		if job.State != recovery.StateInProgress {
+			s.fail(w, "com.vmware.vapi.std.errors.not_allowed_in_current_state")
+			return
+		}
+		now := time.Now().UTC()
+		job.State = recovery.StateFailed
+		job.EndTime = &now
+		job.Messages = append(job.Messages, rest.LocalizableMessage{
+			ID:             "com.vmware.applmgmt.backup.job.cancelled",
+			DefaultMessage: "Backup job canceled.",
+		})
+		s.ok(w)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *handler) applianceRestoreJob(w http.ResponseWriter, r *http.Request) {
+	job := s.Appliance.RestoreJob
+
+	switch r.Method {
+	case http.MethodGet:
+		if job == nil {
+			s.ok(w, recovery.RestoreJobStatus{State: recovery.StateNone, Messages: []rest.LocalizableMessage{}})
+			return
+		}
+		if job.State == recovery.StateInProgress {
+			job.Progress += jobProgress
+			if job.Progress >= 100 {
+				job.Progress = 100
+				job.State = recovery.StateSucceeded
+			}
+		}
+		s.ok(w, job)
+	case http.MethodPost:
+		var spec struct {
+			Piece recovery.RestoreRequest `json:"piece"`
+		}
+		if !s.decode(r, w, &spec) {
+			return
+		}
+		if !validLocation(spec.Piece.LocationType, spec.Piece.Location) {
+			s.fail(w, "com.vmware.vapi.std.errors.invalid_argument")
+			return
+		}
+		if job != nil && job.State == recovery.StateInProgress {
+			s.fail(w, "com.vmware.vapi.std.errors.not_allowed_in_current_state")
+			return
+		}
+		s.Appliance.RestoreJob = &recovery.RestoreJobStatus{
+			State:    recovery.StateInProgress,
+			Messages: []rest.LocalizableMessage{},
+		}
+		s.ok(w, s.Appliance.RestoreJob)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *handler) applianceRestoreJobCancel(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	job := s.Appliance.RestoreJob
+	if job == nil || job.State != recovery.StateInProgress {
+		s.fail(w, "com.vmware.vapi.std.errors.not_allowed_in_current_state")
+		return
+	}
+
+	job.State = recovery.StateFailed
+	job.Messages = append(job.Messages, rest.LocalizableMessage{
+		ID:             "com.vmware.applmgmt.restore.job.cancelled",
+		DefaultMessage: "Restore job canceled.",
+	})
+	s.ok(w)
+}
+
+func (s *handler) applianceUpdate(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.ok(w, s.Appliance.Update.Info)
+	case http.MethodPost:
+		if r.URL.Query().Get("action") != "cancel" {
+			http.NotFound(w, r)
+			return
+		}
+		// stage and install operations complete immediately, there is never an operation to cancel
+		s.fail(w, "com.vmware.vapi.std.errors.not_allowed_in_current_state")
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *handler) applianceUpdatePending(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	switch r.URL.Query().Get("source_type") {
+	case appupdate.SourceLatest, appupdate.SourceLocal, appupdate.SourceLocalAndOnline:
+	default:
+		s.fail(w, "com.vmware.vapi.std.errors.invalid_argument")
+		return
+	}
+
+	u := &s.Appliance.Update
+	now := time.Now().UTC()
+	u.LatestQueryTime = &now
+
+	res := []appupdate.Summary{}
+	for _, p := range u.Pending {
+		res = append(res, p.Summary)
+	}
+	if len(res) != 0 && u.State == appupdate.StateUpToDate {
+		u.State = appupdate.StateUpdatesPending
+	}
+
+	s.ok(w, res)
+}
+
+// validateUpdate returns Notifications for the update Questions not answered by the given user data.
+func (s *handler) validateUpdate(data map[string]string) appupdate.Notifications {
+	var res appupdate.Notifications
+	for _, q := range s.Appliance.Update.Questions {
+		if data[q.DataItem] == "" {
+			res.Errors = append(res.Errors, appupdate.Notification{
+				ID:      "com.vmware.appliance.update.missing_user_data",
+				Message: rest.LocalizableMessage{DefaultMessage: "Answer required: " + q.DataItem, Args: []string{q.DataItem}},
+			})
+		}
+	}
+	return res
+}
+
+func (s *handler) applianceUpdatePendingID(w http.ResponseWriter, r *http.Request) {
+	u := &s.Appliance.Update
+	version := path.Base(r.URL.Path)
+
+	var pending *appupdate.PendingInfo
+	for i := range u.Pending {
+		if u.Pending[i].Version == version {
+			pending = &u.Pending[i]
+		}
+	}
+	if pending == nil {
+		s.fail(w, "com.vmware.vapi.std.errors.not_found")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		s.ok(w, pending)
+		return
+	case http.MethodPost:
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	action := r.URL.Query().Get("action")
+	data := make(map[string]string)
+
+	switch action {
+	case "validate", "install", "stage-and-install":
+		var spec struct {
+			UserData []struct {
+				Key   string `json:"key"`
+				Value string `json:"value"`
+			} `json:"user_data"`
+		}
+		if !s.decode(r, w, &spec) {
+			return
+		}
+		for _, kv := range spec.UserData {
+			data[kv.Key] = kv.Value
+		}
+	}
+
+	stage := func() {
+		u.Staged = &appupdate.StagedInfo{Summary: pending.Summary, StagingComplete: true}
+		u.State = appupdate.StateUpdatesPending
+		u.Task = applianceUpdateTask("stage")
+	}
+
+	switch action {
+	case "precheck":
+		s.ok(w, appupdate.PrecheckResult{
+			CheckTime:               time.Now().UTC(),
+			EstimatedTimeToInstall:  20,
+			EstimatedTimeToRollback: 10,
+			RebootRequired:          pending.RebootRequired,
+			Issues:                  &appupdate.Notifications{},
+			Questions:               u.Questions,
+		})
+	case "validate":
+		s.ok(w, s.validateUpdate(data))
+	case "stage":
+		stage()
+		s.ok(w)
+	case "install", "stage-and-install":
+		if len(s.validateUpdate(data).Errors) != 0 {
+			s.fail(w, "com.vmware.vapi.std.errors.invalid_argument")
+			return
+		}
+		if action == "stage-and-install" {
+			stage()
+		}
+		if u.Staged == nil || u.Staged.Version != version {
+			s.fail(w, "com.vmware.vapi.std.errors.not_allowed_in_current_state")
+			return
+		}
+		u.Version = version
+		u.State = appupdate.StateUpToDate
+		u.Staged = nil
+		u.Task = applianceUpdateTask("install")
+		u.Pending = nil
+		s.ok(w)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func applianceUpdateTask(operation string) *appupdate.Task {
+	now := time.Now().UTC()
+	return &appupdate.Task{
+		Description: rest.LocalizableMessage{DefaultMessage: operation},
+		Operation:   "com.vmware.appliance.update." + operation,
+		Status:      "SUCCEEDED",
+		Progress:    &appupdate.Progress{Total: 100, Completed: 100},
+		StartTime:   &now,
+		EndTime:     &now,
+	}
+}
+
+func (s *handler) applianceUpdateStaged(w http.ResponseWriter, r *http.Request) {
+	u := &s.Appliance.Update
+	if u.Staged == nil {
+		s.fail(w, "com.vmware.vapi.std.errors.not_found")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		s.ok(w, u.Staged)
+	case http.MethodDelete:
+		u.Staged = nil
+		s.ok(w)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *handler) applianceUpdatePolicy(w http.ResponseWriter, r *http.Request) {
+	policy := &s.Appliance.Update.Policy
+
+	switch r.Method {
+	case http.MethodGet:
+		s.ok(w, policy)
+	case http.MethodPut:
+		var spec struct {
+			Config appupdate.PolicyConfig `json:"config"`
+		}
+		if !s.decode(r, w, &spec) {
+			return
+		}
+		policy.CustomURL = spec.Config.CustomURL
+		policy.Username = spec.Config.Username
+		policy.CheckSchedule = spec.Config.CheckSchedule
+		policy.AutoStage = spec.Config.AutoStage
+		s.ok(w)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *handler) applianceNetworkInterfaces(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	s.ok(w, s.Appliance.Network.Interface)
+}
+
+func (s *handler) applianceNetworkInterfacesID(w http.ResponseWriter, r *http.Request) {
+	p := strings.Split(strings.TrimPrefix(r.URL.Path, internal.Path+internal.ApplianceNetworkingPath+"/interfaces/"), "/")
+
+	var nic *networking.Interface
+	for i := range s.Appliance.Network.Interface {
+		if s.Appliance.Network.Interface[i].Name == p[0] {
+			nic = &s.Appliance.Network.Interface[i]
+		}
+	}
+	if nic == nil {
+		s.fail(w, "com.vmware.vapi.std.errors.not_found")
+		return
+	}
+
+	if len(p) == 1 {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		s.ok(w, nic)
+		return
+	}
+
+	if p[1] != "ipv4" {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		s.ok(w, nic.IPv4)
+	case http.MethodPut:
+		var spec struct {
+			Config networking.IPv4 `json:"config"`
+		}
+		if !s.decode(r, w, &spec) {
+			return
+		}
+		config := spec.Config
+		switch config.Mode {
+		case networking.ModeStatic:
+			if net.ParseIP(config.Address).To4() == nil || config.Prefix < 0 || config.Prefix > 32 {
+				s.fail(w, "com.vmware.vapi.std.errors.invalid_argument")
+				return
+			}
+			if config.DefaultGateway != "" && net.ParseIP(config.DefaultGateway).To4() == nil {
+				s.fail(w, "com.vmware.vapi.std.errors.invalid_argument")
+				return
+			}
+		case networking.ModeDHCP, networking.ModeUnconfigured:
+			config.Address, config.Prefix, config.DefaultGateway = "", 0, ""
+		default:
+			s.fail(w, "com.vmware.vapi.std.errors.invalid_argument")
+			return
+		}
+		config.Configurable = true
+		nic.IPv4 = &config
+		s.ok(w)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// validIPs returns true if all of the given addresses are valid IP addresses.
+func validIPs(addrs ...string) bool {
+	for _, addr := range addrs {
+		if net.ParseIP(addr) == nil {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *handler) applianceNetworkDNSServers(w http.ResponseWriter, r *http.Request) {
+	dns := &s.Appliance.Network.DNS
+
+	switch r.Method {
+	case http.MethodGet:
+		s.ok(w, dns)
+	case http.MethodPut:
+		var spec struct {
+			Config networking.DNSServers `json:"config"`
+		}
+		if !s.decode(r, w, &spec) {
+			return
+		}
+		switch spec.Config.Mode {
+		case networking.DNSModeDHCP:
+			spec.Config.Servers = []string{}
+		case networking.DNSModeIsStatic:
+			if !validIPs(spec.Config.Servers...) {
+				s.fail(w, "com.vmware.vapi.std.errors.invalid_argument")
+				return
+			}
+		default:
+			s.fail(w, "com.vmware.vapi.std.errors.invalid_argument")
+			return
+		}
+		*dns = spec.Config
+		s.ok(w)
+	case http.MethodPost:
+		var spec struct {
+			Server string `json:"server"`
+		}
+		if !s.decode(r, w, &spec) {
+			return
+		}
+		if dns.Mode != networking.DNSModeIsStatic {
+			s.fail(w, "com.vmware.vapi.std.errors.not_allowed_in_current_state")
+			return
+		}
+		if !validIPs(spec.Server) {
+			s.fail(w, "com.vmware.vapi.std.errors.invalid_argument")
+			return
+		}
+		dns.Servers = append(dns.Servers, spec.Server)
+		s.ok(w)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *handler) applianceNetworkHostname(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.ok(w, s.Appliance.Network.Hostname)
+	case http.MethodPut:
+		var spec struct {
+			Name string `json:"name"`
+		}
+		if !s.decode(r, w, &spec) {
+			return
+		}
+		if spec.Name == "" || strings.ContainsAny(spec.Name, " /_") {
+			s.fail(w, "com.vmware.vapi.std.errors.invalid_argument")
+			return
+		}
+		s.Appliance.Network.Hostname = spec.Name
+		s.ok(w)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *handler) applianceNetworkProxy(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	var res []applianceKeyValue
+	for protocol, proxy := range s.Appliance.Network.Proxy {
+		proxy.Password = ""
+		res = append(res, applianceKeyValue{protocol, proxy})
+	}
+
+	s.ok(w, res)
+}
+
+func (s *handler) applianceNetworkProxyID(w http.ResponseWriter, r *http.Request) {
+	protocol := path.Base(r.URL.Path)
+	proxy, ok := s.Appliance.Network.Proxy[protocol]
+	if !ok {
+		s.fail(w, "com.vmware.vapi.std.errors.not_found")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		proxy.Password = ""
+		s.ok(w, proxy)
+	case http.MethodPut:
+		var spec struct {
+			Config networking.Proxy `json:"config"`
+		}
+		if !s.decode(r, w, &spec) {
+			return
+		}
+		if spec.Config.Server == "" || spec.Config.Port < 0 || spec.Config.Port > 65535 {
+			s.fail(w, "com.vmware.vapi.std.errors.invalid_argument")
+			return
+		}
+		s.Appliance.Network.Proxy[protocol] = spec.Config
+		s.ok(w)
+	case http.MethodDelete:
+		s.Appliance.Network.Proxy[protocol] = networking.Proxy{}
+		s.ok(w)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *handler) applianceNetworkFirewall(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.ok(w, s.Appliance.Network.Firewall)
+	case http.MethodPut:
+		var spec struct {
+			Rules []networking.FirewallRule `json:"rules"`
+		}
+		if !s.decode(r, w, &spec) {
+			return
+		}
+		for _, rule := range spec.Rules {
+			switch rule.Policy {
+			case networking.PolicyIgnore, networking.PolicyAccept, networking.PolicyReject, networking.PolicyReturn:
+			default:
+				s.fail(w, "com.vmware.vapi.std.errors.invalid_argument")
+				return
+			}
+			if !validIPs(rule.Address) || rule.Prefix < 0 || rule.Prefix > 128 {
+				s.fail(w, "com.vmware.vapi.std.errors.invalid_argument")
+				return
+			}
+		}
+		if spec.Rules == nil {
+			spec.Rules = []networking.FirewallRule{}
+		}
+		s.Appliance.Network.Firewall = spec.Rules
+		s.ok(w)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *handler) applianceAccess(w http.ResponseWriter, r *http.Request) {
+	method := path.Base(r.URL.Path)
+	if _, ok := s.Appliance.Access[method]; !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		s.ok(w, s.Appliance.Access[method])
+	case http.MethodPut:
+		var spec struct {
+			Enabled bool `json:"enabled"`
+		}
+		if !s.decode(r, w, &spec) {
+			return
+		}
+		s.Appliance.Access[method] = spec.Enabled
+		s.ok(w)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *handler) applianceAccessShell(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		// the shell is disabled once the timeout, stored as the time it expires, has passed
+		timeout := int(time.Until(s.Appliance.Shell).Seconds())
+		if timeout <= 0 {
+			timeout = 0
+		}
+		s.ok(w, access.Shell{Enabled: timeout > 0, Timeout: timeout})
+	case http.MethodPut:
+		var spec struct {
+			Config access.Shell `json:"config"`
+		}
+		if !s.decode(r, w, &spec) {
+			return
+		}
+		if !spec.Config.Enabled {
+			s.Appliance.Shell = time.Time{}
+			s.ok(w)
+			return
+		}
+		if spec.Config.Timeout <= 0 || spec.Config.Timeout > access.MaxShellTimeout {
+			s.fail(w, "com.vmware.vapi.std.errors.invalid_argument")
+			return
+		}
+		s.Appliance.Shell = time.Now().Add(time.Duration(spec.Config.Timeout) * time.Second)
+		s.ok(w)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *handler) applianceShutdown(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	// the appliance is not actually shutdown, the action is no longer pending once its time has passed
+	config := s.Appliance.Shutdown
+	if config.ShutdownTime == nil || !config.ShutdownTime.After(time.Now()) {
+		config = shutdown.Config{}
+	}
+
+	s.ok(w, config)
+}
+
+func (s *handler) applianceShutdownAction(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	action := path.Base(r.URL.Path)
+
+	switch action {
+	case shutdown.ActionPowerOff, shutdown.ActionReboot:
+		var spec struct {
+			Delay  int    `json:"delay"`
+			Reason string `json:"reason"`
+		}
+		if !s.decode(r, w, &spec) {
+			return
+		}
+		if spec.Reason == "" || spec.Delay < 0 {
+			s.fail(w, "com.vmware.vapi.std.errors.invalid_argument")
+			return
+		}
+		when := time.Now().UTC().Add(time.Duration(spec.Delay) * time.Minute)
+		s.Appliance.Shutdown = shutdown.Config{ShutdownTime: &when, Action: action, Reason: spec.Reason}
+		s.ok(w)
+	case "cancel":
+		s.Appliance.Shutdown = shutdown.Config{}
+		s.ok(w)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// newAccount returns a local account using the global password policy.
+func (a *appliance) newAccount() *applianceAccount {
+	policy := a.Policy
+	return &applianceAccount{
+		Info: accounts.Info{
+			Enabled:                          true,
+			MinDaysBetweenPasswordChange:     &policy.MinDays,
+			MaxDaysWithoutPasswordChange:     &policy.MaxDays,
+			WarnDaysBeforePasswordExpiration: &policy.WarnDays,
+		},
+		PasswordExpires: true,
+	}
+}
+
+func (a *applianceAccount) setPassword(password string) {
+	now := time.Now().UTC()
+	a.Password = password
+	a.HasPassword = true
+	a.LastPasswordChange = &now
+	a.PasswordExpiresAt = nil
+}
+
+// apply updates the account fields that are set in the given config, returning false if the config is invalid.
+func (a *applianceAccount) apply(c accounts.Config) bool {
+	for _, role := range c.Roles {
+		switch role {
+		case accounts.RoleSuperAdmin, accounts.RoleAdmin, accounts.RoleOperator:
+		default:
+			return false
+		}
+	}
+
+	if c.Password != "" {
+		if c.OldPassword != "" && c.OldPassword != a.Password {
+			return false
+		}
+		a.setPassword(c.Password)
+	}
+	if c.FullName != "" {
+		a.FullName = c.FullName
+	}
+	if c.Email != "" {
+		a.Email = c.Email
+	}
+	if c.Roles != nil {
+		a.Roles = c.Roles
+	}
+	if c.Enabled != nil {
+		a.Enabled = *c.Enabled
+	}
+	if c.PasswordExpires != nil {
+		a.PasswordExpires = *c.PasswordExpires
+	}
+	if c.PasswordExpiresAt != nil {
+		a.PasswordExpiresAt = c.PasswordExpiresAt
+	}
+	if c.MinDaysBetweenPasswordChange != nil {
+		a.MinDaysBetweenPasswordChange = c.MinDaysBetweenPasswordChange
+	}
+	if c.MaxDaysWithoutPasswordChange != nil {
+		a.MaxDaysWithoutPasswordChange = c.MaxDaysWithoutPasswordChange
+	}
+	if c.WarnDaysBeforePasswordExpiration != nil {
+		a.WarnDaysBeforePasswordExpiration = c.WarnDaysBeforePasswordExpiration
+	}
+
+	return true
+}
+
+// info returns the account Info, including when the password expires.
+func (a *applianceAccount) info() accounts.Info {
+	info := a.Info
+	if !a.PasswordExpires {
+		info.PasswordExpiresAt = nil
+		info.MaxDaysWithoutPasswordChange = nil
+		info.WarnDaysBeforePasswordExpiration = nil
+	} else if info.PasswordExpiresAt == nil && info.LastPasswordChange != nil && info.MaxDaysWithoutPasswordChange != nil {
+		expires := info.LastPasswordChange.AddDate(0, 0, *info.MaxDaysWithoutPasswordChange)
+		info.PasswordExpiresAt = &expires
+	}
+	return info
+}
+
+func (s *handler) applianceAccounts(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	names := []string{}
+	for name := range s.Appliance.Account {
+		names = append(names, name)
+	}
+
+	s.ok(w, names)
+}
+
+func (s *handler) applianceAccountsID(w http.ResponseWriter, r *http.Request) {
+	name := path.Base(r.URL.Path)
+	account, exists := s.Appliance.Account[name]
+
+	var spec struct {
+		Config accounts.Config `json:"config"`
+	}
+
+	switch r.Method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch:
+		if !s.decode(r, w, &spec) {
+			return
+		}
+	}
+
+	if r.Method == http.MethodPost {
+		if exists {
+			s.fail(w, "com.vmware.vapi.std.errors.already_exists")
+			return
+		}
+		account = s.Appliance.newAccount()
+		if spec.Config.Password == "" || len(spec.Config.Roles) == 0 || !account.apply(spec.Config) {
+			s.fail(w, "com.vmware.vapi.std.errors.invalid_argument")
+			return
+		}
+		s.Appliance.Account[name] = account
+		s.ok(w)
+		return
+	}
+
+	if !exists {
+		s.fail(w, "com.vmware.vapi.std.errors.not_found")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		s.ok(w, account.info())
+	case http.MethodPut:
+		update := s.Appliance.newAccount()
+		update.Password = account.Password
+		update.HasPassword = account.HasPassword
+		update.LastPasswordChange = account.LastPasswordChange
+		if len(spec.Config.Roles) == 0 || !update.apply(spec.Config) {
+			s.fail(w, "com.vmware.vapi.std.errors.invalid_argument")
+			return
+		}
+		s.Appliance.Account[name] = update
+		s.ok(w)
+	case http.MethodPatch:
+		update := *account
+		if !update.apply(spec.Config) {
+			s.fail(w, "com.vmware.vapi.std.errors.invalid_argument")
+			return
+		}
+		*account = update
+		s.ok(w)
+	case http.MethodDelete:
+		delete(s.Appliance.Account, name)
+		s.ok(w)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *handler) applianceAccountsPolicy(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.ok(w, s.Appliance.Policy)
+	case http.MethodPut:
+		var spec struct {
+			Policy accounts.Policy `json:"policy"`
+		}
+		if !s.decode(r, w, &spec) {
+			return
+		}
+		p := spec.Policy
+		if p.MaxDays < 0 || p.MinDays < 0 || p.WarnDays < 0 || (p.MaxDays != 0 && p.MinDays > p.MaxDays) {
+			s.fail(w, "com.vmware.vapi.std.errors.invalid_argument")
+			return
+		}
+		s.Appliance.Policy = p
+		s.ok(w)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,188 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"context"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/vapi/cluster"
+	"github.com/vmware/govmomi/vapi/internal"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// clusterModule is a group of VMs in a cluster
+type clusterModule struct {
+	Cluster string
+	Members map[string]bool
+}
+
+// vmClusters returns the cluster ID of each of the given VMs, an empty string if the VM is on a standalone host.
+func (s *handler) vmClusters(ctx context.Context, ids []string) (map[string]string, error) {
+	res := make(map[string]string, len(ids))
+
+	err := s.withClient(ctx, func(c *govmomi.Client) error {
+		pc := property.DefaultCollector(c.Client)
+		for _, id := range ids {
+			var vm mo.VirtualMachine
+			ref := types.ManagedObjectReference{Type: "VirtualMachine", Value: id}
+			if err := pc.RetrieveOne(ctx, ref, []string{"runtime.host"}, &vm); err != nil {
+				return err
+			}
+			var host mo.HostSystem
+			if err := pc.RetrieveOne(ctx, *vm.Runtime.Host, []string{"parent"}, &host); err != nil {
+				return err
+			}
+			if host.Parent.Type == "ClusterComputeResource" {
+				res[id] = host.Parent.Value
+			} else {
+				res[id] = ""
+			}
+		}
+		return nil
+	})
+
+	return res, err
+}
+
+func (s *handler) clusterModules(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		var res struct {
+			Summaries []cluster.ModuleSummary `json:"summaries"`
+		}
+		res.Summaries = []cluster.ModuleSummary{}
+		for id, m := range s.Module {
+			res.Summaries = append(res.Summaries, cluster.ModuleSummary{Cluster: m.Cluster, Module: id})
+		}
+		sort.Slice(res.Summaries, func(i, j int) bool { return res.Summaries[i].Module < res.Summaries[j].Module })
+		s.ok(w, res)
+	case http.MethodPost:
+		var spec struct {
+			Spec struct {
+				Cluster string `json:"cluster"`
+			} `json:"spec"`
+		}
+		if !s.decode(r, w, &spec) {
+			return
+		}
+		names, err := s.computeClusters(r.Context())
+		if err != nil {
+			s.error(w, err)
+			return
+		}
+		if _, ok := names[spec.Spec.Cluster]; !ok {
+			s.fail(w, "com.vmware.vapi.std.errors.not_found")
+			return
+		}
+		id := uuid.New().String()
+		s.Module[id] = &clusterModule{Cluster: spec.Spec.Cluster, Members: make(map[string]bool)}
+		s.ok(w, id)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *handler) clusterModulesID(w http.ResponseWriter, r *http.Request) {
+	id := path.Base(r.URL.Path)
+	if _, ok := s.Module[id]; !ok {
+		s.fail(w, "com.vmware.vapi.std.errors.not_found")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodDelete:
+		delete(s.Module, id)
+		s.ok(w)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *handler) clusterModulesVM(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, internal.Path+internal.ClusterModulesVMPath+"/")
+	m, ok := s.Module[id]
+	if !ok {
+		s.fail(w, "com.vmware.vapi.std.errors.not_found")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		var res struct {
+			VMs []string `json:"vms"`
+		}
+		res.VMs = []string{}
+		for vm := range m.Members {
+			res.VMs = append(res.VMs, vm)
+		}
+		sort.Strings(res.VMs)
+		s.ok(w, res)
+	case http.MethodPost:
+		var spec struct {
+			VMs []string `json:"vms"`
+		}
+		if !s.decode(r, w, &spec) {
+			return
+		}
+		var res struct {
+			Success bool `json:"success"`
+		}
+		res.Success = true
+
+		switch s.action(r) {
+		case "add":
+			clusters, err := s.vmClusters(r.Context(), spec.VMs)
+			if err != nil {
+				s.fail(w, "com.vmware.vapi.std.errors.invalid_argument")
+				return
+			}
+			for _, vm := range spec.VMs {
+				if clusters[vm] != m.Cluster {
+					s.fail(w, "com.vmware.vapi.std.errors.invalid_argument")
+					return
+				}
+			}
+			for _, vm := range spec.VMs {
+				if m.Members[vm] {
+					res.Success = false
+				}
+				m.Members[vm] = true
+			}
+		case "remove":
+			for _, vm := range spec.VMs {
+				if !m.Members[vm] {
+					res.Success = false
+				}
+				delete(m.Members, vm)
+			}
+		default:
+			s.fail(w, "com.vmware.vapi.std.errors.invalid_argument")
+			return
+		}
+		s.ok(w, res)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,145 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/vmware/govmomi/vapi/internal"
+	"github.com/vmware/govmomi/vapi/vcenter/compute"
+)
+
+// computePolicyCapabilities are the supported compute policy capabilities, keyed by ID
+var computePolicyCapabilities = map[string]compute.Capability{
+	compute.CapabilityVMHostAffinity: {
+		Name:        "VM-Host affinity",
+		Description: "Tagged virtual machines run on the tagged hosts.",
+	},
+	compute.CapabilityVMHostAntiAffinity: {
+		Name:        "VM-Host anti-affinity",
+		Description: "Tagged virtual machines do not run on the tagged hosts.",
+	},
+	compute.CapabilityVMVMAffinity: {
+		Name:        "VM-VM affinity",
+		Description: "Tagged virtual machines run on the same host.",
+	},
+	compute.CapabilityVMVMAntiAffinity: {
+		Name:        "VM-VM anti-affinity",
+		Description: "Tagged virtual machines run on different hosts.",
+	},
+	compute.CapabilityDisableDRSVMotion: {
+		Name:        "Disable DRS vMotion",
+		Description: "DRS does not migrate the tagged virtual machines.",
+	},
+}
+
+// validComputePolicy returns true if the policy's capability is supported and its required tags exist
+func (s *handler) validComputePolicy(p *compute.Policy) bool {
+	if _, ok := computePolicyCapabilities[p.Capability]; !ok || p.Name == "" {
+		return false
+	}
+	if _, ok := s.Tag[p.VMTag]; !ok {
+		return false
+	}
+	switch p.Capability {
+	case compute.CapabilityVMHostAffinity, compute.CapabilityVMHostAntiAffinity:
+		_, ok := s.Tag[p.HostTag]
+		return ok
+	default:
+		return p.HostTag == ""
+	}
+}
+
+func (s *handler) computePolicies(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		res := []compute.Policy{}
+		for id, p := range s.Policy {
+			res = append(res, compute.Policy{
+				Policy:      id,
+				Name:        p.Name,
+				Description: p.Description,
+				Capability:  p.Capability,
+			})
+		}
+		sort.Slice(res, func(i, j int) bool { return res[i].Policy < res[j].Policy })
+		s.apiOK(w, res)
+	case http.MethodPost:
+		var spec compute.Policy
+		if !s.decode(r, w, &spec) {
+			return
+		}
+		spec.Policy = ""
+		if !s.validComputePolicy(&spec) {
+			s.apiFail(w, http.StatusBadRequest, "INVALID_ARGUMENT")
+			return
+		}
+		id := uuid.New().String()
+		s.Policy[id] = &spec
+		s.apiOK(w, id)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *handler) computePoliciesID(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, internal.ComputePoliciesPath+"/")
+	p, ok := s.Policy[id]
+	if !ok {
+		s.apiFail(w, http.StatusNotFound, "NOT_FOUND")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		s.apiOK(w, p)
+	case http.MethodDelete:
+		delete(s.Policy, id)
+		s.apiOK(w)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *handler) computePolicyCapabilities(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	if id := strings.TrimPrefix(r.URL.Path, internal.ComputePoliciesPath+"/capabilities/"); id != r.URL.Path {
+		c, ok := computePolicyCapabilities[id]
+		if !ok {
+			s.apiFail(w, http.StatusNotFound, "NOT_FOUND")
+			return
+		}
+		s.apiOK(w, c)
+		return
+	}
+
+	res := []compute.Capability{}
+	for id, c := range computePolicyCapabilities {
+		c.Capability = id
+		res = append(res, c)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Capability < res[j].Capability })
+	s.apiOK(w, res)
+}
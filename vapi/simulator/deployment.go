@@ -0,0 +1,318 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"net/http"
+	"path"
+
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vapi/vcenter/deployment"
+)
+
+// vcsaDeployment is the deployment state of the simulated appliance, ready to be installed or upgraded
+type vcsaDeployment struct {
+	Info     deployment.Info
+	Install  *deployment.InstallSpec
+	Upgrade  *deployment.UpgradeSpec
+	Question []deployment.Question
+	History  *deployment.Info
+}
+
+func newDeployment() *vcsaDeployment {
+	return &vcsaDeployment{
+		Info: deployment.Info{
+			State:       deployment.StateInitialized,
+			Description: rest.LocalizableMessage{DefaultMessage: "Appliance is ready to be configured."},
+		},
+	}
+}
+
+// deploymentQuestion is raised by an install or upgrade started without auto_answer
+var deploymentQuestion = deployment.Question{
+	ID:              "com.vmware.vcenter.deployment.continue",
+	Question:        rest.LocalizableMessage{DefaultMessage: "Do you want to continue?"},
+	Type:            "YES_NO",
+	DefaultAnswer:   "yes",
+	Text:            rest.LocalizableMessage{DefaultMessage: "The appliance will be configured, this operation cannot be undone."},
+	PossibleAnswers: []string{"yes", "no"},
+}
+
+func deploymentNotification(id, message string) *deployment.Notifications {
+	return &deployment.Notifications{
+		Errors: []deployment.Notification{{ID: id, Message: rest.LocalizableMessage{ID: id, DefaultMessage: message}}},
+	}
+}
+
+// checkInstall returns nil if the given spec is valid, otherwise the errors found.
+func checkInstall(spec *deployment.InstallSpec) *deployment.Notifications {
+	vcsa := spec.VCSAEmbedded
+	switch {
+	case vcsa == nil:
+		return deploymentNotification("install.vcsa_embedded", "Embedded vCenter Server spec is required.")
+	case (vcsa.Standalone == nil) == (vcsa.Replicated == nil):
+		return deploymentNotification("install.domain", "One of standalone or replicated SSO domain is required.")
+	case vcsa.Standalone != nil && vcsa.Standalone.SSOAdminPassword == "",
+		vcsa.Replicated != nil && (vcsa.Replicated.SSOAdminPassword == "" || vcsa.Replicated.PartnerHostname == ""):
+		return deploymentNotification("install.sso", "SSO administrator password and partner hostname are required.")
+	}
+	return nil
+}
+
+// checkUpgrade returns nil if the given spec is valid, otherwise the errors found.
+func checkUpgrade(spec *deployment.UpgradeSpec) *deployment.Notifications {
+	for _, l := range []deployment.LocationSpec{spec.SourceVC, spec.SourceLocation} {
+		if l.Hostname == "" || l.Username == "" || l.Password == "" {
+			return deploymentNotification("upgrade.source", "Source hostname and credentials are required.")
+		}
+	}
+	if h := spec.History; h != nil && h.DataSet != deployment.HistoryEventsTasks && h.DataSet != deployment.HistoryAll {
+		return deploymentNotification("upgrade.history", "Invalid history data set.")
+	}
+	return nil
+}
+
+// start starts the given operation, raising a question unless auto answered.
+func (d *vcsaDeployment) start(operation string, auto bool) {
+	d.Info = deployment.Info{
+		State:       deployment.StateConfigInProgress,
+		Operation:   operation,
+		Status:      deployment.StatusRunning,
+		Description: rest.LocalizableMessage{DefaultMessage: "Configuring the appliance."},
+		Progress:    &deployment.Progress{Total: 100},
+	}
+	if !auto {
+		d.Info.State = deployment.StateQuestionRaised
+		d.Info.Status = deployment.StatusBlocked
+		d.Question = []deployment.Question{deploymentQuestion}
+	}
+}
+
+// fail ends the current operation with the given error.
+func (d *vcsaDeployment) fail(id, message string) {
+	d.Info.State = deployment.StateFailed
+	d.Info.Status = deployment.StatusFailed
+	d.Info.Result = deploymentNotification(id, message)
+	d.Question = nil
+}
+
+// progress advances the given task, returning true when the task completes.
+func progress(info *deployment.Info) bool {
+	if info.Status != deployment.StatusRunning {
+		return false
+	}
+	info.Progress.Completed += jobProgress
+	if info.Progress.Completed < info.Progress.Total {
+		return false
+	}
+	info.Progress.Completed = info.Progress.Total
+	info.Status = deployment.StatusSucceeded
+	return true
+}
+
+func (s *handler) deployment(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	d := s.Deployment
+	if d.Info.State == deployment.StateConfigInProgress && progress(&d.Info) {
+		d.Info.State = deployment.StateConfigured
+		d.Info.Description = rest.LocalizableMessage{DefaultMessage: "Appliance is configured."}
+		if d.Upgrade != nil && d.Upgrade.History != nil && d.Upgrade.History.DeferImport {
+			d.History = &deployment.Info{
+				State:    d.Info.State,
+				Status:   deployment.StatusPending,
+				Progress: &deployment.Progress{Total: 100},
+			}
+		}
+	}
+	s.apiOK(w, d.Info)
+}
+
+func (s *handler) deploymentQuestion(w http.ResponseWriter, r *http.Request) {
+	d := s.Deployment
+
+	switch r.Method {
+	case http.MethodGet:
+		res := struct {
+			Questions []deployment.Question `json:"questions"`
+		}{[]deployment.Question{}}
+		res.Questions = append(res.Questions, d.Question...)
+		s.apiOK(w, res)
+	case http.MethodPost:
+		var spec struct {
+			QuestionID string `json:"question_id"`
+			AnswerVal  string `json:"answer_val"`
+		}
+		if r.URL.Query().Get("action") != "answer" || !s.decode(r, w, &spec) {
+			s.apiFail(w, http.StatusBadRequest, "INVALID_ARGUMENT")
+			return
+		}
+		if d.Info.State != deployment.StateQuestionRaised {
+			s.apiFail(w, http.StatusBadRequest, "NOT_ALLOWED_IN_CURRENT_STATE")
+			return
+		}
+		if spec.QuestionID != deploymentQuestion.ID {
+			s.apiFail(w, http.StatusNotFound, "NOT_FOUND")
+			return
+		}
+		switch spec.AnswerVal {
+		case "yes":
+			d.Info.State = deployment.StateConfigInProgress
+			d.Info.Status = deployment.StatusRunning
+			d.Question = nil
+		case "no":
+			d.fail("deployment.declined", "The operation was declined.")
+		default:
+			s.apiFail(w, http.StatusBadRequest, "INVALID_ARGUMENT")
+			return
+		}
+		s.apiOK(w)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// deploymentOperation handles the install and upgrade operations
+func (s *handler) deploymentOperation(w http.ResponseWriter, r *http.Request) {
+	d := s.Deployment
+	operation := deployment.OperationInstall
+	if path.Base(r.URL.Path) == "upgrade" {
+		operation = deployment.OperationUpgrade
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		if d.Info.Operation != operation {
+			s.apiFail(w, http.StatusNotFound, "NOT_FOUND")
+			return
+		}
+		if operation == deployment.OperationInstall {
+			spec := *d.Install
+			vcsa := *spec.VCSAEmbedded
+			if vcsa.Standalone != nil {
+				vcsa.Standalone = &deployment.StandaloneSpec{SSODomainName: vcsa.Standalone.SSODomainName}
+			}
+			if vcsa.Replicated != nil {
+				replicated := *vcsa.Replicated
+				replicated.SSOAdminPassword = ""
+				vcsa.Replicated = &replicated
+			}
+			spec.VCSAEmbedded = &vcsa
+			s.apiOK(w, spec)
+		} else {
+			spec := *d.Upgrade
+			spec.SourceVC.Password = ""
+			spec.SourceLocation.Password = ""
+			s.apiOK(w, spec)
+		}
+	case http.MethodPost:
+		action := r.URL.Query().Get("action")
+		if action == "cancel" {
+			if d.Info.Operation != operation || d.Info.Status != deployment.StatusRunning && d.Info.Status != deployment.StatusBlocked {
+				s.apiFail(w, http.StatusBadRequest, "NOT_ALLOWED_IN_CURRENT_STATE")
+				return
+			}
+			d.fail("deployment.canceled", "The operation was canceled.")
+			s.apiOK(w)
+			return
+		}
+
+		var install deployment.InstallSpec
+		var upgrade deployment.UpgradeSpec
+		var result *deployment.Notifications
+		var auto bool
+		if operation == deployment.OperationInstall {
+			if !s.decode(r, w, &install) {
+				return
+			}
+			result, auto = checkInstall(&install), install.AutoAnswer
+		} else {
+			if !s.decode(r, w, &upgrade) {
+				return
+			}
+			result, auto = checkUpgrade(&upgrade), upgrade.AutoAnswer
+		}
+
+		switch action {
+		case "check":
+			check := deployment.CheckInfo{Status: deployment.CheckSuccess, Result: result}
+			if result != nil {
+				check.Status = deployment.CheckFailed
+			}
+			s.apiOK(w, check)
+		case "start":
+			if d.Info.State != deployment.StateInitialized {
+				s.apiFail(w, http.StatusBadRequest, "NOT_ALLOWED_IN_CURRENT_STATE")
+				return
+			}
+			if result != nil {
+				s.apiFail(w, http.StatusBadRequest, "INVALID_ARGUMENT")
+				return
+			}
+			if operation == deployment.OperationInstall {
+				d.Install = &install
+			} else {
+				d.Upgrade = &upgrade
+			}
+			d.start(operation, auto)
+			s.apiOK(w)
+		default:
+			s.apiFail(w, http.StatusBadRequest, "INVALID_ARGUMENT")
+		}
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *handler) deploymentImportHistory(w http.ResponseWriter, r *http.Request) {
+	h := s.Deployment.History
+	if h == nil {
+		s.apiFail(w, http.StatusNotFound, "NOT_FOUND")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		progress(h)
+		s.apiOK(w, h)
+	case http.MethodPost:
+		transitions := map[string]struct{ from, to string }{
+			"start":  {deployment.StatusPending, deployment.StatusRunning},
+			"pause":  {deployment.StatusRunning, deployment.StatusPaused},
+			"resume": {deployment.StatusPaused, deployment.StatusRunning},
+		}
+		action := r.URL.Query().Get("action")
+		switch t, ok := transitions[action]; {
+		case ok && h.Status == t.from:
+			h.Status = t.to
+		case action == "cancel" && (h.Status == deployment.StatusRunning || h.Status == deployment.StatusPaused):
+			h.Status = deployment.StatusFailed
+		case ok || action == "cancel":
+			s.apiFail(w, http.StatusBadRequest, "NOT_ALLOWED_IN_CURRENT_STATE")
+			return
+		default:
+			s.apiFail(w, http.StatusBadRequest, "INVALID_ARGUMENT")
+			return
+		}
+		s.apiOK(w)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,183 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vmware/govmomi/vapi/internal"
+	"github.com/vmware/govmomi/vapi/vcenter/guest"
+)
+
+// validCustomizationSpec returns true if the given spec has a name, one guest OS configuration and valid IPv4 settings.
+func validCustomizationSpec(spec *guest.CustomizationSpec) bool {
+	config := spec.Spec.ConfigurationSpec
+	if spec.Name == "" || (config.LinuxConfig == nil) == (config.WindowsConfig == nil) {
+		return false
+	}
+	for _, nic := range spec.Spec.Interfaces {
+		ip := nic.Adapter.IPv4
+		if ip == nil {
+			continue
+		}
+		switch ip.Type {
+		case guest.IPv4DHCP, guest.IPv4UserInputRequired:
+		case guest.IPv4Static:
+			if ip.IPAddress == "" || ip.Prefix < 1 || ip.Prefix > 32 {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func customizationSpecSummary(spec *guest.CustomizationSpec) guest.CustomizationSpecSummary {
+	summary := guest.CustomizationSpecSummary{
+		Name:         spec.Name,
+		Description:  spec.Description,
+		OSType:       guest.OSTypeLinux,
+		LastModified: *spec.LastModified,
+	}
+	if spec.Spec.ConfigurationSpec.WindowsConfig != nil {
+		summary.OSType = guest.OSTypeWindows
+	}
+	return summary
+}
+
+// putCustomizationSpec stores the given spec, generating a new fingerprint.
+func (s *handler) putCustomizationSpec(spec guest.CustomizationSpec) {
+	now := time.Now()
+	spec.Fingerprint = uuid.New().String()
+	spec.LastModified = &now
+	if spec.Spec.Interfaces == nil {
+		spec.Spec.Interfaces = []guest.InterfaceMapping{}
+	}
+	s.GuestSpec[spec.Name] = &spec
+}
+
+func (s *handler) customizationSpecs(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		res := []guest.CustomizationSpecSummary{}
+		for _, spec := range s.GuestSpec {
+			res = append(res, customizationSpecSummary(spec))
+		}
+		s.apiOK(w, res)
+	case http.MethodPost:
+		if r.URL.Query().Get("action") == "import" {
+			var req struct {
+				Spec string `json:"spec"`
+			}
+			if !s.decode(r, w, &req) {
+				return
+			}
+			// only the JSON export format is simulated
+			var spec guest.CustomizationSpec
+			if err := json.Unmarshal([]byte(req.Spec), &spec); err != nil || !validCustomizationSpec(&spec) {
+				s.apiFail(w, http.StatusBadRequest, "INVALID_ARGUMENT")
+				return
+			}
+			spec.Fingerprint = ""
+			spec.LastModified = nil
+			s.apiOK(w, spec)
+			return
+		}
+
+		var spec guest.CustomizationSpec
+		if !s.decode(r, w, &spec) {
+			return
+		}
+		if !validCustomizationSpec(&spec) {
+			s.apiFail(w, http.StatusBadRequest, "INVALID_ARGUMENT")
+			return
+		}
+		if _, ok := s.GuestSpec[spec.Name]; ok {
+			s.apiFail(w, http.StatusBadRequest, "ALREADY_EXISTS")
+			return
+		}
+		s.putCustomizationSpec(spec)
+		s.apiOK(w)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *handler) customizationSpecsID(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, internal.CustomizationSpecsPath+"/")
+	current, ok := s.GuestSpec[name]
+	if !ok {
+		s.apiFail(w, http.StatusNotFound, "NOT_FOUND")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		s.apiOK(w, current)
+	case http.MethodPut:
+		var spec guest.CustomizationSpec
+		if !s.decode(r, w, &spec) {
+			return
+		}
+		if !validCustomizationSpec(&spec) {
+			s.apiFail(w, http.StatusBadRequest, "INVALID_ARGUMENT")
+			return
+		}
+		if spec.Fingerprint != current.Fingerprint {
+			s.apiFail(w, http.StatusBadRequest, "CONCURRENT_CHANGE")
+			return
+		}
+		if _, ok = s.GuestSpec[spec.Name]; ok && spec.Name != name {
+			s.apiFail(w, http.StatusBadRequest, "ALREADY_EXISTS")
+			return
+		}
+		delete(s.GuestSpec, name)
+		s.putCustomizationSpec(spec)
+		s.apiOK(w)
+	case http.MethodDelete:
+		delete(s.GuestSpec, name)
+		s.apiOK(w)
+	case http.MethodPost:
+		var req struct {
+			Format string `json:"format"`
+		}
+		if r.URL.Query().Get("action") != "export" || !s.decode(r, w, &req) {
+			s.apiFail(w, http.StatusBadRequest, "INVALID_ARGUMENT")
+			return
+		}
+		if req.Format != guest.FormatJSON {
+			s.apiFail(w, http.StatusBadRequest, "UNSUPPORTED")
+			return
+		}
+		spec := *current
+		spec.Fingerprint = ""
+		spec.LastModified = nil
+		b, err := json.Marshal(spec)
+		if err != nil {
+			s.error(w, err)
+			return
+		}
+		s.apiOK(w, string(b))
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,176 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/vmware/govmomi/vapi/internal"
+	"github.com/vmware/govmomi/vapi/vcenter/identity"
+)
+
+// validIdentityProvider returns true if the config of the given ConfigTag is set with its required fields
+func validIdentityProvider(p *identity.Provider) bool {
+	switch p.ConfigTag {
+	case identity.ConfigTypeOAuth2:
+		return p.OIDC == nil && p.OAuth2 != nil &&
+			p.OAuth2.AuthEndpoint != "" && p.OAuth2.TokenEndpoint != "" && p.OAuth2.ClientID != ""
+	case identity.ConfigTypeOIDC:
+		return p.OAuth2 == nil && p.OIDC != nil &&
+			p.OIDC.DiscoveryEndpoint != "" && p.OIDC.ClientID != ""
+	default:
+		return false
+	}
+}
+
+// identityProvider returns a copy of the given provider, without the client secret
+func identityProvider(id string, p *identity.Provider) identity.Provider {
+	info := *p
+	info.Provider = id
+	if p.OAuth2 != nil {
+		config := *p.OAuth2
+		config.ClientSecret = ""
+		info.OAuth2 = &config
+	}
+	if p.OIDC != nil {
+		config := *p.OIDC
+		config.ClientSecret = ""
+		info.OIDC = &config
+	}
+	return info
+}
+
+// setDefaultIdentityProvider makes the given provider the only default provider
+func (s *handler) setDefaultIdentityProvider(id string) {
+	for pid, p := range s.Identity {
+		p.IsDefault = pid == id
+	}
+}
+
+func (s *handler) identityProviders(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		res := []identity.Provider{}
+		for id, p := range s.Identity {
+			res = append(res, identityProvider(id, p))
+		}
+		sort.Slice(res, func(i, j int) bool { return res[i].Provider < res[j].Provider })
+		s.apiOK(w, res)
+	case http.MethodPost:
+		var spec identity.Provider
+		if !s.decode(r, w, &spec) {
+			return
+		}
+		if !validIdentityProvider(&spec) {
+			s.apiFail(w, http.StatusBadRequest, "INVALID_ARGUMENT")
+			return
+		}
+		for _, p := range s.Identity {
+			if spec.Name != "" && p.Name == spec.Name {
+				s.apiFail(w, http.StatusBadRequest, "ALREADY_EXISTS")
+				return
+			}
+		}
+		id := uuid.New().String()
+		spec.Provider = ""
+		s.Identity[id] = &spec
+		if spec.IsDefault {
+			s.setDefaultIdentityProvider(id)
+		}
+		s.apiOK(w, id)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *handler) identityProvidersID(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, internal.IdentityProvidersPath+"/")
+	p, ok := s.Identity[id]
+	if !ok {
+		s.apiFail(w, http.StatusNotFound, "NOT_FOUND")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		s.apiOK(w, identityProvider(id, p))
+	case http.MethodPatch:
+		var spec identity.UpdateSpec
+		if !s.decode(r, w, &spec) {
+			return
+		}
+		update := *p
+		update.ConfigTag = spec.ConfigTag
+		switch spec.ConfigTag {
+		case identity.ConfigTypeOAuth2:
+			if spec.OAuth2 != nil {
+				update.OAuth2 = spec.OAuth2
+			}
+			update.OIDC = nil
+		case identity.ConfigTypeOIDC:
+			if spec.OIDC != nil {
+				update.OIDC = spec.OIDC
+			}
+			update.OAuth2 = nil
+		}
+		if !validIdentityProvider(&update) {
+			s.apiFail(w, http.StatusBadRequest, "INVALID_ARGUMENT")
+			return
+		}
+		if spec.Name != "" {
+			update.Name = spec.Name
+		}
+		if spec.AuthQueryParams != nil {
+			update.AuthQueryParams = spec.AuthQueryParams
+		}
+		if spec.IDMProtocol != "" {
+			update.IDMProtocol = spec.IDMProtocol
+		}
+		if spec.UPNClaim != "" || spec.ResetUPNClaim {
+			update.UPNClaim = spec.UPNClaim
+		}
+		if spec.GroupsClaim != "" || spec.ResetGroupsClaim {
+			update.GroupsClaim = spec.GroupsClaim
+		}
+		remove := make(map[string]bool)
+		for _, name := range spec.DomainNamesToRemove {
+			remove[name] = true
+		}
+		var domains []string
+		for _, name := range append(p.DomainNames, spec.DomainNamesToAdd...) {
+			if !remove[name] {
+				remove[name] = true // skip duplicates
+				domains = append(domains, name)
+			}
+		}
+		update.DomainNames = domains
+		*p = update
+		if spec.MakeDefault {
+			s.setDefaultIdentityProvider(id)
+		}
+		s.apiOK(w)
+	case http.MethodDelete:
+		delete(s.Identity, id)
+		s.apiOK(w)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,500 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/vapi/internal"
+	"github.com/vmware/govmomi/vapi/vcenter"
+	"github.com/vmware/govmomi/view"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// retrieveInventory retrieves the given properties of the objects of the given kind into dst, a pointer to a slice.
+// Objects are retrieved from the given datacenters, or from all datacenters if none are given.
+func retrieveInventory(ctx context.Context, c *govmomi.Client, datacenters []string, kind []string, props []string, dst interface{}) error {
+	roots := []types.ManagedObjectReference{c.ServiceContent.RootFolder}
+	if len(datacenters) != 0 {
+		roots = nil
+		for _, id := range datacenters {
+			roots = append(roots, types.ManagedObjectReference{Type: "Datacenter", Value: id})
+		}
+	}
+
+	res := reflect.ValueOf(dst).Elem()
+	for _, root := range roots {
+		v, err := view.NewManager(c.Client).CreateContainerView(ctx, root, kind, true)
+		if err != nil {
+			return err
+		}
+		content := reflect.New(res.Type())
+		err = v.Retrieve(ctx, kind, props, content.Interface())
+		_ = v.Destroy(ctx)
+		if err != nil {
+			return err
+		}
+		res.Set(reflect.AppendSlice(res, content.Elem()))
+	}
+	return nil
+}
+
+// retrieveParents returns the parent ID of each of the given objects
+func retrieveParents(ctx context.Context, c *govmomi.Client, refs []types.ManagedObjectReference) (map[string]string, error) {
+	parents := make(map[string]string)
+	if len(refs) == 0 {
+		return parents, nil
+	}
+	var entities []mo.ManagedEntity
+	if err := property.DefaultCollector(c.Client).Retrieve(ctx, refs, []string{"parent"}, &entities); err != nil {
+		return nil, err
+	}
+	for _, e := range entities {
+		if e.Parent != nil {
+			parents[e.Self.Value] = e.Parent.Value
+		}
+	}
+	return parents, nil
+}
+
+// vcenterInventoryGet writes the /api representation of the given object, as converted by f from the retrieved properties.
+func (s *handler) vcenterInventoryGet(w http.ResponseWriter, r *http.Request, ref types.ManagedObjectReference, props []string, dst interface{}, f func() interface{}) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	ctx := r.Context()
+	err := s.withClient(ctx, func(c *govmomi.Client) error {
+		return property.DefaultCollector(c.Client).RetrieveOne(ctx, ref, props, dst)
+	})
+	if err != nil {
+		s.apiFault(w, err)
+		return
+	}
+	s.apiOK(w, f())
+}
+
+// vcenterInventoryRef returns the reference of the object with the ID of the given request path.
+// The ID is a single escaped path segment, as vcsim Datastore IDs contain the "/" separated path of the datastore.
+func vcenterInventoryRef(r *http.Request, path string, kind string) types.ManagedObjectReference {
+	id := strings.TrimPrefix(r.URL.EscapedPath(), path+"/")
+	if s, err := url.PathUnescape(id); err == nil {
+		id = s
+	}
+	return types.ManagedObjectReference{Type: kind, Value: id}
+}
+
+func (s *handler) vcenterResourcePools(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	q := r.URL.Query()
+	res := []vcenter.ResourcePoolSummary{}
+
+	err := s.withClient(ctx, func(c *govmomi.Client) error {
+		var pools []mo.ResourcePool
+		if err := retrieveInventory(ctx, c, q["datacenters"], []string{"ResourcePool"}, []string{"name", "parent", "owner"}, &pools); err != nil {
+			return err
+		}
+
+		// the hosts of each pool's owner, used to filter by host
+		hosts := make(map[string][]string)
+		if len(q["hosts"]) != 0 {
+			var refs []types.ManagedObjectReference
+			for _, pool := range pools {
+				refs = append(refs, pool.Owner)
+			}
+			var owners []mo.ComputeResource
+			if err := property.DefaultCollector(c.Client).Retrieve(ctx, refs, []string{"host"}, &owners); err != nil {
+				return err
+			}
+			for _, owner := range owners {
+				for _, host := range owner.Host {
+					hosts[owner.Self.Value] = append(hosts[owner.Self.Value], host.Value)
+				}
+			}
+		}
+
+		for _, pool := range pools {
+			match := contains(q["resource_pools"], pool.Self.Value) && contains(q["names"], pool.Name) &&
+				contains(q["parent_resource_pools"], pool.Parent.Value) && contains(q["clusters"], pool.Owner.Value)
+			if len(q["hosts"]) != 0 {
+				found := false
+				for _, host := range hosts[pool.Owner.Value] {
+					found = found || contains(q["hosts"], host)
+				}
+				match = match && found
+			}
+			if match {
+				res = append(res, vcenter.ResourcePoolSummary{ResourcePool: pool.Self.Value, Name: pool.Name})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		s.apiFault(w, err)
+		return
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].ResourcePool < res[j].ResourcePool })
+	s.apiOK(w, res)
+}
+
+// vcenterResourceAllocation converts the given vim25 allocation to its /api representation
+func vcenterResourceAllocation(a types.ResourceAllocationInfo) *vcenter.ResourceAllocation {
+	alloc := &vcenter.ResourceAllocation{ExpandableReservation: isTrue(a.ExpandableReservation), Limit: -1}
+	if a.Reservation != nil {
+		alloc.Reservation = *a.Reservation
+	}
+	if a.Limit != nil {
+		alloc.Limit = *a.Limit
+	}
+	if a.Shares != nil {
+		alloc.Shares = vcenter.Shares{Level: strings.ToUpper(string(a.Shares.Level))}
+		if a.Shares.Level == types.SharesLevelCustom {
+			alloc.Shares.Shares = a.Shares.Shares
+		}
+	}
+	return alloc
+}
+
+func (s *handler) vcenterResourcePoolID(w http.ResponseWriter, r *http.Request) {
+	var pool mo.ResourcePool
+	ref := vcenterInventoryRef(r, internal.VCenterResourcePoolPath, "ResourcePool")
+	s.vcenterInventoryGet(w, r, ref, []string{"name", "resourcePool", "config"}, &pool, func() interface{} {
+		info := vcenter.ResourcePoolInfo{
+			Name:             pool.Name,
+			ResourcePools:    []string{},
+			CPUAllocation:    vcenterResourceAllocation(pool.Config.CpuAllocation),
+			MemoryAllocation: vcenterResourceAllocation(pool.Config.MemoryAllocation),
+		}
+		for _, child := range pool.ResourcePool {
+			info.ResourcePools = append(info.ResourcePools, child.Value)
+		}
+		return info
+	})
+}
+
+// vcenterFolderType returns the /api type of a folder with the given child types
+func vcenterFolderType(childType []string) string {
+	for _, kind := range childType {
+		switch kind {
+		case "Datacenter":
+			return vcenter.FolderTypeDatacenter
+		case "Datastore":
+			return vcenter.FolderTypeDatastore
+		case "ComputeResource":
+			return vcenter.FolderTypeHost
+		case "Network":
+			return vcenter.FolderTypeNetwork
+		case "VirtualMachine":
+			return vcenter.FolderTypeVirtualMachine
+		}
+	}
+	return ""
+}
+
+func (s *handler) vcenterFolders(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	q := r.URL.Query()
+	res := []vcenter.FolderSummary{}
+
+	err := s.withClient(ctx, func(c *govmomi.Client) error {
+		var folders []mo.Folder
+		if err := retrieveInventory(ctx, c, q["datacenters"], []string{"Folder"}, []string{"name", "parent", "childType"}, &folders); err != nil {
+			return err
+		}
+		for _, f := range folders {
+			kind := vcenterFolderType(f.ChildType)
+			match := contains(q["folders"], f.Self.Value) && contains(q["names"], f.Name) &&
+				contains(q["type"], kind) && contains(q["parent_folders"], f.Parent.Value)
+			if match {
+				res = append(res, vcenter.FolderSummary{Folder: f.Self.Value, Name: f.Name, Type: kind})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		s.apiFault(w, err)
+		return
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].Folder < res[j].Folder })
+	s.apiOK(w, res)
+}
+
+func (s *handler) vcenterDatacenters(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	q := r.URL.Query()
+	res := []vcenter.DatacenterSummary{}
+
+	err := s.withClient(ctx, func(c *govmomi.Client) error {
+		var datacenters []mo.Datacenter
+		if err := retrieveInventory(ctx, c, nil, []string{"Datacenter"}, []string{"name", "parent"}, &datacenters); err != nil {
+			return err
+		}
+		for _, dc := range datacenters {
+			match := contains(q["datacenters"], dc.Self.Value) && contains(q["names"], dc.Name) &&
+				contains(q["folders"], dc.Parent.Value)
+			if match {
+				res = append(res, vcenter.DatacenterSummary{Datacenter: dc.Self.Value, Name: dc.Name})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		s.apiFault(w, err)
+		return
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].Datacenter < res[j].Datacenter })
+	s.apiOK(w, res)
+}
+
+func (s *handler) vcenterDatacenterID(w http.ResponseWriter, r *http.Request) {
+	var dc mo.Datacenter
+	props := []string{"name", "parent", "datastoreFolder", "hostFolder", "networkFolder", "vmFolder"}
+	ref := vcenterInventoryRef(r, internal.VCenterDatacenterPath, "Datacenter")
+	s.vcenterInventoryGet(w, r, ref, props, &dc, func() interface{} {
+		return vcenter.DatacenterInfo{
+			Name:            dc.Name,
+			DatastoreFolder: dc.DatastoreFolder.Value,
+			HostFolder:      dc.HostFolder.Value,
+			NetworkFolder:   dc.NetworkFolder.Value,
+			VMFolder:        dc.VmFolder.Value,
+			Parent:          dc.Parent.Value,
+		}
+	})
+}
+
+func (s *handler) vcenterClusters(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	q := r.URL.Query()
+	res := []vcenter.ClusterSummary{}
+
+	err := s.withClient(ctx, func(c *govmomi.Client) error {
+		var clusters []mo.ClusterComputeResource
+		kind := []string{"ClusterComputeResource"}
+		if err := retrieveInventory(ctx, c, q["datacenters"], kind, []string{"name", "parent", "configurationEx"}, &clusters); err != nil {
+			return err
+		}
+		for _, cluster := range clusters {
+			match := contains(q["clusters"], cluster.Self.Value) && contains(q["names"], cluster.Name) &&
+				contains(q["folders"], cluster.Parent.Value)
+			if !match {
+				continue
+			}
+			summary := vcenter.ClusterSummary{Cluster: cluster.Self.Value, Name: cluster.Name}
+			if config, ok := cluster.ConfigurationEx.(*types.ClusterConfigInfoEx); ok {
+				summary.HAEnabled = isTrue(config.DasConfig.Enabled)
+				summary.DRSEnabled = isTrue(config.DrsConfig.Enabled)
+			}
+			res = append(res, summary)
+		}
+		return nil
+	})
+	if err != nil {
+		s.apiFault(w, err)
+		return
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].Cluster < res[j].Cluster })
+	s.apiOK(w, res)
+}
+
+func (s *handler) vcenterClusterID(w http.ResponseWriter, r *http.Request) {
+	var cluster mo.ClusterComputeResource
+	ref := vcenterInventoryRef(r, internal.VCenterClusterPath, "ClusterComputeResource")
+	s.vcenterInventoryGet(w, r, ref, []string{"name", "resourcePool"}, &cluster, func() interface{} {
+		return vcenter.ClusterInfo{Name: cluster.Name, ResourcePool: cluster.ResourcePool.Value}
+	})
+}
+
+// vcenterHostSummary converts the given HostSystem to its /api representation
+func vcenterHostSummary(host *mo.HostSystem) vcenter.HostSummary {
+	summary := vcenter.HostSummary{Host: host.Self.Value, Name: host.Name}
+
+	switch host.Runtime.ConnectionState {
+	case types.HostSystemConnectionStateConnected:
+		summary.ConnectionState = vcenter.HostConnectionStateConnected
+	case types.HostSystemConnectionStateNotResponding:
+		summary.ConnectionState = vcenter.HostConnectionStateNotResponding
+	default:
+		summary.ConnectionState = vcenter.HostConnectionStateDisconnected
+	}
+
+	if summary.ConnectionState == vcenter.HostConnectionStateConnected {
+		switch host.Runtime.PowerState {
+		case types.HostSystemPowerStatePoweredOn:
+			summary.PowerState = vcenter.HostPowerStateOn
+		case types.HostSystemPowerStateStandBy:
+			summary.PowerState = vcenter.HostPowerStateStandby
+		default:
+			summary.PowerState = vcenter.HostPowerStateOff
+		}
+	}
+
+	return summary
+}
+
+func (s *handler) vcenterHosts(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	q := r.URL.Query()
+	res := []vcenter.HostSummary{}
+
+	err := s.withClient(ctx, func(c *govmomi.Client) error {
+		var hosts []mo.HostSystem
+		if err := retrieveInventory(ctx, c, q["datacenters"], []string{"HostSystem"}, []string{"name", "parent", "runtime"}, &hosts); err != nil {
+			return err
+		}
+
+		// the folder of each host's compute resource, used to filter by folder
+		var refs []types.ManagedObjectReference
+		for _, host := range hosts {
+			refs = append(refs, *host.Parent)
+		}
+		folders, err := retrieveParents(ctx, c, refs)
+		if err != nil {
+			return err
+		}
+
+		for _, host := range hosts {
+			summary := vcenterHostSummary(&host)
+			standalone := host.Parent.Type == "ComputeResource"
+			cluster := ""
+			if !standalone {
+				cluster = host.Parent.Value
+			}
+			match := contains(q["hosts"], host.Self.Value) && contains(q["names"], host.Name) &&
+				contains(q["folders"], folders[host.Parent.Value]) && contains(q["clusters"], cluster) &&
+				contains(q["connection_states"], summary.ConnectionState)
+			if val := q.Get("standalone"); val != "" {
+				match = match && val == strconv.FormatBool(standalone)
+			}
+			if match {
+				res = append(res, summary)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		s.apiFault(w, err)
+		return
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].Host < res[j].Host })
+	s.apiOK(w, res)
+}
+
+func (s *handler) vcenterDatastores(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	q := r.URL.Query()
+	res := []vcenter.DatastoreSummary{}
+
+	err := s.withClient(ctx, func(c *govmomi.Client) error {
+		var datastores []mo.Datastore
+		if err := retrieveInventory(ctx, c, q["datacenters"], []string{"Datastore"}, []string{"name", "parent", "summary"}, &datastores); err != nil {
+			return err
+		}
+		for _, ds := range datastores {
+			kind := strings.ToUpper(ds.Summary.Type)
+			match := contains(q["datastores"], ds.Self.Value) && contains(q["names"], ds.Name) &&
+				contains(q["types"], kind) && contains(q["folders"], ds.Parent.Value)
+			if match {
+				res = append(res, vcenter.DatastoreSummary{
+					Datastore: ds.Self.Value,
+					Name:      ds.Name,
+					Type:      kind,
+					FreeSpace: ds.Summary.FreeSpace,
+					Capacity:  ds.Summary.Capacity,
+				})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		s.apiFault(w, err)
+		return
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].Datastore < res[j].Datastore })
+	s.apiOK(w, res)
+}
+
+func (s *handler) vcenterDatastoreID(w http.ResponseWriter, r *http.Request) {
+	var ds mo.Datastore
+	ref := vcenterInventoryRef(r, internal.VCenterDatastorePath, "Datastore")
+	s.vcenterInventoryGet(w, r, ref, []string{"name", "summary", "capability"}, &ds, func() interface{} {
+		return vcenter.DatastoreInfo{
+			Name:                      ds.Name,
+			Type:                      strings.ToUpper(ds.Summary.Type),
+			Accessible:                ds.Summary.Accessible,
+			FreeSpace:                 ds.Summary.FreeSpace,
+			MultipleHostAccess:        isTrue(ds.Summary.MultipleHostAccess),
+			ThinProvisioningSupported: ds.Capability.PerFileThinProvisioningSupported,
+		}
+	})
+}
+
+// vcenterNetworkType returns the /api type of the given network
+func vcenterNetworkType(ref types.ManagedObjectReference) string {
+	switch ref.Type {
+	case "DistributedVirtualPortgroup":
+		return vcenter.NetworkTypeDistributedPortgroup
+	case "OpaqueNetwork":
+		return vcenter.NetworkTypeOpaqueNetwork
+	default:
+		return vcenter.NetworkTypeStandardPortgroup
+	}
+}
+
+func (s *handler) vcenterNetworks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	q := r.URL.Query()
+	res := []vcenter.NetworkSummary{}
+
+	err := s.withClient(ctx, func(c *govmomi.Client) error {
+		var networks []mo.Network
+		kind := []string{"Network", "DistributedVirtualPortgroup", "OpaqueNetwork"}
+		if err := retrieveInventory(ctx, c, q["datacenters"], kind, []string{"name", "parent"}, &networks); err != nil {
+			return err
+		}
+		for _, n := range networks {
+			kind := vcenterNetworkType(n.Self)
+			match := contains(q["networks"], n.Self.Value) && contains(q["names"], n.Name) &&
+				contains(q["types"], kind) && contains(q["folders"], n.Parent.Value)
+			if match {
+				res = append(res, vcenter.NetworkSummary{Network: n.Self.Value, Name: n.Name, Type: kind})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		s.apiFault(w, err)
+		return
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].Network < res[j].Network })
+	s.apiOK(w, res)
+}
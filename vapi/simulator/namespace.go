@@ -0,0 +1,836 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"net"
+	"net/http"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/vapi/internal"
+	"github.com/vmware/govmomi/vapi/namespace"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vim25/mo"
+)
+
+// supervisorService is a supervisor service registered with vCenter
+type supervisorService struct {
+	namespace.SupervisorServiceInfo
+	Version map[string]*namespace.SupervisorServiceVersionSummary
+}
+
+// supervisorCluster is the Workload Management state of a cluster, where Info is nil if not enabled
+type supervisorCluster struct {
+	Name    string
+	Info    *namespace.ClusterInfo
+	Service map[string]*namespace.ClusterSupervisorServiceSummary
+}
+
+// dnsLabel matches a valid namespace name, as per RFC 1123
+var dnsLabel = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]{0,61}[a-z0-9])?$`)
+
+// validVMServiceSpec returns true if the content libraries and VM classes of the given spec exist.
+func (s *handler) validVMServiceSpec(spec *namespace.VMServiceSpec) bool {
+	if spec == nil {
+		return true
+	}
+	for _, id := range spec.ContentLibraries {
+		if _, ok := s.Library[id]; !ok {
+			return false
+		}
+	}
+	for _, id := range spec.VMClasses {
+		if _, ok := s.VMClass[id]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// vmClassInfo returns the VM class info, including the namespaces it is associated with.
+func (s *handler) vmClassInfo(info *namespace.VMClassInfo) namespace.VMClassInfo {
+	res := *info
+	res.Namespaces = []string{}
+	for name, ns := range s.Namespace {
+		for _, id := range ns.VMServiceSpec.VMClasses {
+			if id == info.ID {
+				res.Namespaces = append(res.Namespaces, name)
+			}
+		}
+	}
+	return res
+}
+
+// validAccess returns true if the given subject type and role are valid.
+func validAccess(access namespace.Access) bool {
+	switch access.SubjectType {
+	case namespace.SubjectTypeUser, namespace.SubjectTypeGroup:
+	default:
+		return false
+	}
+	switch access.Role {
+	case namespace.RoleOwner, namespace.RoleEdit, namespace.RoleView:
+	default:
+		return false
+	}
+	return access.Subject != "" && access.Domain != ""
+}
+
+func validAccessList(list []namespace.Access) bool {
+	for _, access := range list {
+		if !validAccess(access) {
+			return false
+		}
+	}
+	return true
+}
+
+// validStorageSpecs returns true if all of the given specs have a storage policy.
+func validStorageSpecs(specs []namespace.StorageSpec) bool {
+	for _, spec := range specs {
+		if spec.Policy == "" {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *handler) namespaceInstances(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		res := []namespace.Summary{}
+		for name, info := range s.Namespace {
+			res = append(res, namespace.Summary{
+				Namespace:    name,
+				Description:  info.Description,
+				Cluster:      info.Cluster,
+				ConfigStatus: info.ConfigStatus,
+				Stats:        info.Stats,
+			})
+		}
+		s.apiOK(w, res)
+	case http.MethodPost:
+		var spec namespace.CreateSpec
+		if !s.decode(r, w, &spec) {
+			return
+		}
+		if !dnsLabel.MatchString(spec.Namespace) || spec.Cluster == "" || !validStorageSpecs(spec.StorageSpecs) ||
+			!validAccessList(spec.AccessList) || !s.validVMServiceSpec(spec.VMServiceSpec) {
+			s.apiFail(w, http.StatusBadRequest, "INVALID_ARGUMENT")
+			return
+		}
+		if _, ok := s.Namespace[spec.Namespace]; ok {
+			s.apiFail(w, http.StatusBadRequest, "ALREADY_EXISTS")
+			return
+		}
+
+		info := &namespace.Info{
+			Cluster:      spec.Cluster,
+			Description:  spec.Description,
+			ConfigStatus: namespace.ConfigStatusRunning,
+			StorageSpecs: spec.StorageSpecs,
+			AccessList:   spec.AccessList,
+			Messages:     []rest.LocalizableMessage{},
+		}
+		if spec.ResourceSpec != nil {
+			info.ResourceSpec = *spec.ResourceSpec
+		}
+		if spec.VMServiceSpec != nil {
+			info.VMServiceSpec = *spec.VMServiceSpec
+		}
+		if info.StorageSpecs == nil {
+			info.StorageSpecs = []namespace.StorageSpec{}
+		}
+		if info.AccessList == nil {
+			info.AccessList = []namespace.Access{}
+		}
+		s.Namespace[spec.Namespace] = info
+		s.apiOK(w)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *handler) namespaceInstancesID(w http.ResponseWriter, r *http.Request) {
+	p := strings.Split(strings.TrimPrefix(r.URL.Path, internal.NamespacesInstancesPath+"/"), "/")
+	info, ok := s.Namespace[p[0]]
+	if !ok {
+		s.apiFail(w, http.StatusNotFound, "NOT_FOUND")
+		return
+	}
+
+	switch {
+	case len(p) == 1:
+	case len(p) == 4 && p[1] == "access":
+		s.namespaceAccess(w, r, info, namespace.Access{
+			SubjectType: r.URL.Query().Get("type"),
+			Domain:      p[2],
+			Subject:     p[3],
+		})
+		return
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		s.apiOK(w, info)
+	case http.MethodPatch:
+		var spec namespace.UpdateSpec
+		if !s.decode(r, w, &spec) {
+			return
+		}
+		if !validStorageSpecs(spec.StorageSpecs) || !s.validVMServiceSpec(spec.VMServiceSpec) {
+			s.apiFail(w, http.StatusBadRequest, "INVALID_ARGUMENT")
+			return
+		}
+		if spec.Description != "" {
+			info.Description = spec.Description
+		}
+		if spec.ResourceSpec != nil {
+			info.ResourceSpec = *spec.ResourceSpec
+		}
+		if spec.StorageSpecs != nil {
+			info.StorageSpecs = spec.StorageSpecs
+		}
+		if spec.VMServiceSpec != nil {
+			info.VMServiceSpec = *spec.VMServiceSpec
+		}
+		s.apiOK(w)
+	case http.MethodDelete:
+		delete(s.Namespace, p[0])
+		s.apiOK(w)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// newVMClasses returns the default VM classes, a subset of those created when Workload Management is enabled.
+func newVMClasses() map[string]*namespace.VMClassInfo {
+	classes := make(map[string]*namespace.VMClassInfo)
+
+	for _, spec := range []namespace.VMClassCreateSpec{
+		{ID: "best-effort-xsmall", CPUCount: 2, MemoryMB: 2048},
+		{ID: "best-effort-small", CPUCount: 2, MemoryMB: 4096},
+		{ID: "best-effort-medium", CPUCount: 2, MemoryMB: 8192},
+		{ID: "guaranteed-small", CPUCount: 2, MemoryMB: 4096, CPUReservation: 100, MemoryReservation: 100},
+		{ID: "guaranteed-medium", CPUCount: 2, MemoryMB: 8192, CPUReservation: 100, MemoryReservation: 100},
+	} {
+		classes[spec.ID] = newVMClass(spec)
+	}
+
+	return classes
+}
+
+func newVMClass(spec namespace.VMClassCreateSpec) *namespace.VMClassInfo {
+	info := &namespace.VMClassInfo{
+		ID:                spec.ID,
+		CPUCount:          spec.CPUCount,
+		CPUReservation:    spec.CPUReservation,
+		MemoryMB:          spec.MemoryMB,
+		MemoryReservation: spec.MemoryReservation,
+		Description:       spec.Description,
+		Namespaces:        []string{},
+		VMs:               []string{},
+		ConfigStatus:      namespace.VMClassConfigStatusReady,
+		Messages:          []rest.LocalizableMessage{},
+	}
+	if spec.Devices != nil {
+		info.Devices = *spec.Devices
+	}
+	return info
+}
+
+// validVMClass returns true if the VM class sizing is valid.
+func validVMClass(info *namespace.VMClassInfo) bool {
+	valid := func(percent int64) bool { return percent >= 0 && percent <= 100 }
+
+	if info.CPUCount <= 0 || info.MemoryMB <= 0 || !valid(info.CPUReservation) || !valid(info.MemoryReservation) {
+		return false
+	}
+	for _, dev := range info.Devices.VGPUDevices {
+		if dev.ProfileName == "" {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *handler) namespaceVMClasses(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		res := []namespace.VMClassInfo{}
+		for _, info := range s.VMClass {
+			res = append(res, s.vmClassInfo(info))
+		}
+		s.apiOK(w, res)
+	case http.MethodPost:
+		var spec namespace.VMClassCreateSpec
+		if !s.decode(r, w, &spec) {
+			return
+		}
+		info := newVMClass(spec)
+		if !dnsLabel.MatchString(spec.ID) || !validVMClass(info) {
+			s.apiFail(w, http.StatusBadRequest, "INVALID_ARGUMENT")
+			return
+		}
+		if _, ok := s.VMClass[spec.ID]; ok {
+			s.apiFail(w, http.StatusBadRequest, "ALREADY_EXISTS")
+			return
+		}
+		s.VMClass[spec.ID] = info
+		s.apiOK(w)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *handler) namespaceVMClassesID(w http.ResponseWriter, r *http.Request) {
+	id := path.Base(r.URL.Path)
+	info, ok := s.VMClass[id]
+	if !ok {
+		s.apiFail(w, http.StatusNotFound, "NOT_FOUND")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		s.apiOK(w, s.vmClassInfo(info))
+	case http.MethodPatch:
+		var spec namespace.VMClassUpdateSpec
+		if !s.decode(r, w, &spec) {
+			return
+		}
+		update := *info
+		if spec.CPUCount != 0 {
+			update.CPUCount = spec.CPUCount
+		}
+		if spec.CPUReservation != nil {
+			update.CPUReservation = *spec.CPUReservation
+		}
+		if spec.MemoryMB != 0 {
+			update.MemoryMB = spec.MemoryMB
+		}
+		if spec.MemoryReservation != nil {
+			update.MemoryReservation = *spec.MemoryReservation
+		}
+		if spec.Description != "" {
+			update.Description = spec.Description
+		}
+		if spec.Devices != nil {
+			update.Devices = *spec.Devices
+		}
+		if !validVMClass(&update) {
+			s.apiFail(w, http.StatusBadRequest, "INVALID_ARGUMENT")
+			return
+		}
+		*info = update
+		s.apiOK(w)
+	case http.MethodDelete:
+		delete(s.VMClass, id)
+		s.apiOK(w)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// parseCarvel returns the name, version and display name of a Carvel Package.
+// The simulator does not depend on a YAML parser, the fields are found by scanning the content line by line.
+func parseCarvel(content []byte) (string, string, string) {
+	fields := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		kv := strings.SplitN(strings.TrimSpace(scanner.Text()), ":", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		key := kv[0]
+		if _, ok := fields[key]; !ok {
+			fields[key] = strings.Trim(strings.TrimSpace(kv[1]), `"'`)
+		}
+	}
+	display := fields["displayName"]
+	if display == "" {
+		display = fields["refName"]
+	}
+	return fields["refName"], fields["version"], display
+}
+
+func (s *handler) supervisorServices(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		res := []namespace.SupervisorServiceSummary{}
+		for id, svc := range s.Supervisor {
+			res = append(res, namespace.SupervisorServiceSummary{
+				SupervisorService: id,
+				DisplayName:       svc.DisplayName,
+				State:             svc.State,
+			})
+		}
+		s.apiOK(w, res)
+	case http.MethodPost:
+		var spec struct {
+			CarvelSpec struct {
+				VersionSpec namespace.CarvelSpec `json:"version_spec"`
+			} `json:"carvel_spec"`
+		}
+		if !s.decode(r, w, &spec) {
+			return
+		}
+		id, version, display := parseCarvel(spec.CarvelSpec.VersionSpec.Content)
+		if id == "" || version == "" {
+			s.apiFail(w, http.StatusBadRequest, "INVALID_ARGUMENT")
+			return
+		}
+		if _, ok := s.Supervisor[id]; ok {
+			s.apiFail(w, http.StatusBadRequest, "ALREADY_EXISTS")
+			return
+		}
+		s.Supervisor[id] = &supervisorService{
+			SupervisorServiceInfo: namespace.SupervisorServiceInfo{
+				DisplayName: display,
+				State:       namespace.SupervisorServiceActivated,
+			},
+			Version: map[string]*namespace.SupervisorServiceVersionSummary{
+				version: {Version: version, DisplayName: display, State: namespace.SupervisorServiceActivated},
+			},
+		}
+		s.apiOK(w)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// supervisorServiceInstalled returns true if the given supervisor service version is installed on any cluster.
+// If version is empty, returns true if any version of the service is installed.
+func (s *handler) supervisorServiceInstalled(id, version string) bool {
+	for _, cluster := range s.Cluster {
+		if svc, ok := cluster.Service[id]; ok && (version == "" || svc.DesiredVersion == version) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *handler) supervisorServicesID(w http.ResponseWriter, r *http.Request) {
+	p := strings.Split(strings.TrimPrefix(r.URL.Path, internal.SupervisorServicesPath+"/"), "/")
+	svc, ok := s.Supervisor[p[0]]
+	if !ok {
+		s.apiFail(w, http.StatusNotFound, "NOT_FOUND")
+		return
+	}
+
+	switch {
+	case len(p) == 1:
+		s.supervisorService(w, r, p[0], svc)
+	case p[1] == "versions":
+		s.supervisorServiceVersions(w, r, p[0], svc, p[2:])
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *handler) supervisorService(w http.ResponseWriter, r *http.Request, id string, svc *supervisorService) {
+	switch r.Method {
+	case http.MethodGet:
+		s.apiOK(w, svc.SupervisorServiceInfo)
+	case http.MethodPatch:
+		switch r.URL.Query().Get("action") {
+		case "activate":
+			svc.State = namespace.SupervisorServiceActivated
+		case "deactivate":
+			svc.State = namespace.SupervisorServiceDeactivated
+		default:
+			s.apiFail(w, http.StatusBadRequest, "INVALID_ARGUMENT")
+			return
+		}
+		s.apiOK(w)
+	case http.MethodDelete:
+		if svc.State != namespace.SupervisorServiceDeactivated || s.supervisorServiceInstalled(id, "") {
+			s.apiFail(w, http.StatusBadRequest, "NOT_ALLOWED_IN_CURRENT_STATE")
+			return
+		}
+		delete(s.Supervisor, id)
+		s.apiOK(w)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *handler) supervisorServiceVersions(w http.ResponseWriter, r *http.Request, id string, svc *supervisorService, p []string) {
+	if len(p) == 1 {
+		if _, ok := svc.Version[p[0]]; !ok {
+			s.apiFail(w, http.StatusNotFound, "NOT_FOUND")
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			s.apiOK(w, svc.Version[p[0]])
+		case http.MethodDelete:
+			if s.supervisorServiceInstalled(id, p[0]) {
+				s.apiFail(w, http.StatusBadRequest, "NOT_ALLOWED_IN_CURRENT_STATE")
+				return
+			}
+			delete(svc.Version, p[0])
+			s.apiOK(w)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		res := []namespace.SupervisorServiceVersionSummary{}
+		for _, v := range svc.Version {
+			res = append(res, *v)
+		}
+		s.apiOK(w, res)
+	case http.MethodPost:
+		var spec struct {
+			CarvelSpec namespace.CarvelSpec `json:"carvel_spec"`
+		}
+		if !s.decode(r, w, &spec) {
+			return
+		}
+		name, version, display := parseCarvel(spec.CarvelSpec.Content)
+		if name != id || version == "" {
+			s.apiFail(w, http.StatusBadRequest, "INVALID_ARGUMENT")
+			return
+		}
+		if _, ok := svc.Version[version]; ok {
+			s.apiFail(w, http.StatusBadRequest, "ALREADY_EXISTS")
+			return
+		}
+		svc.Version[version] = &namespace.SupervisorServiceVersionSummary{
+			Version:     version,
+			DisplayName: display,
+			State:       namespace.SupervisorServiceActivated,
+		}
+		s.apiOK(w)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *handler) namespaceClustersID(w http.ResponseWriter, r *http.Request) {
+	p := strings.Split(strings.TrimPrefix(r.URL.Path, internal.NamespaceClustersPath+"/"), "/")
+	cluster, ok := s.Cluster[p[0]]
+	if !ok {
+		// Supervisor services can be installed on any cluster ID, whether or not Workload Management is enabled
+		cluster = &supervisorCluster{Service: make(map[string]*namespace.ClusterSupervisorServiceSummary)}
+		if r.Method != http.MethodGet {
+			s.Cluster[p[0]] = cluster
+		}
+	}
+
+	switch {
+	case len(p) == 1:
+		s.namespaceCluster(w, r, p[0], cluster)
+	case p[1] == "supervisor-services":
+		s.clusterSupervisorServices(w, r, cluster, p[2:])
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// computeClusters returns the names of the inventory's clusters, keyed by ID.
+func (s *handler) computeClusters(ctx context.Context) (map[string]string, error) {
+	var clusters []mo.ClusterComputeResource
+
+	err := s.withClient(ctx, func(c *govmomi.Client) error {
+		return retrieveInventory(ctx, c, nil, []string{"ClusterComputeResource"}, []string{"name"}, &clusters)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]string, len(clusters))
+	for _, cluster := range clusters {
+		names[cluster.Self.Value] = cluster.Name
+	}
+	return names, nil
+}
+
+// validEnableClusterSpec returns true if the given spec has the required network and storage configuration.
+func (s *handler) validEnableClusterSpec(spec *namespace.EnableClusterSpec) bool {
+	switch spec.SizeHint {
+	case namespace.SizeTiny, namespace.SizeSmall, namespace.SizeMedium, namespace.SizeLarge:
+	default:
+		return false
+	}
+	switch spec.NetworkProvider {
+	case namespace.NetworkProviderNSXT, namespace.NetworkProviderVSphere:
+	default:
+		return false
+	}
+	network := spec.MasterManagementNetwork
+	if network == nil || network.Network == "" {
+		return false
+	}
+	switch network.Mode {
+	case namespace.NetworkModeDHCP:
+	case namespace.NetworkModeStaticRange:
+		if network.AddressRange == nil || net.ParseIP(network.AddressRange.StartingAddress) == nil ||
+			network.AddressRange.AddressCount < 1 {
+			return false
+		}
+	default:
+		return false
+	}
+	if id := spec.DefaultKubernetesServiceContentLibrary; id != "" {
+		if _, ok := s.Library[id]; !ok {
+			return false
+		}
+	}
+	return spec.MasterStoragePolicy != "" && spec.EphemeralStoragePolicy != "" && spec.ImageStorage.StoragePolicy != ""
+}
+
+func (s *handler) namespaceClusters(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		res := []namespace.ClusterSummary{}
+		for id, cluster := range s.Cluster {
+			if cluster.Info == nil {
+				continue
+			}
+			res = append(res, namespace.ClusterSummary{
+				Cluster:          id,
+				ClusterName:      cluster.Name,
+				ConfigStatus:     cluster.Info.ConfigStatus,
+				KubernetesStatus: cluster.Info.KubernetesStatus,
+			})
+		}
+		s.apiOK(w, res)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *handler) namespaceCluster(w http.ResponseWriter, r *http.Request, id string, cluster *supervisorCluster) {
+	switch r.Method {
+	case http.MethodGet:
+		if cluster.Info == nil {
+			s.apiFail(w, http.StatusNotFound, "NOT_FOUND")
+			return
+		}
+		s.apiOK(w, cluster.Info)
+	case http.MethodPost:
+		switch r.URL.Query().Get("action") {
+		case "enable":
+			var spec namespace.EnableClusterSpec
+			if !s.decode(r, w, &spec) {
+				return
+			}
+			names, err := s.computeClusters(r.Context())
+			if err != nil {
+				s.apiFail(w, http.StatusInternalServerError, "ERROR")
+				return
+			}
+			name, ok := names[id]
+			if !ok {
+				s.apiFail(w, http.StatusNotFound, "NOT_FOUND")
+				return
+			}
+			if cluster.Info != nil {
+				s.apiFail(w, http.StatusBadRequest, "NOT_ALLOWED_IN_CURRENT_STATE")
+				return
+			}
+			if !s.validEnableClusterSpec(&spec) {
+				s.apiFail(w, http.StatusBadRequest, "INVALID_ARGUMENT")
+				return
+			}
+			cluster.Name = name
+			cluster.Info = &namespace.ClusterInfo{
+				SizeHint:                spec.SizeHint,
+				ConfigStatus:            namespace.ClusterConfigRunning,
+				KubernetesStatus:        namespace.KubernetesReady,
+				APIServers:              []string{},
+				NetworkProvider:         spec.NetworkProvider,
+				ServiceCidr:             spec.ServiceCidr,
+				MasterManagementNetwork: spec.MasterManagementNetwork,
+				MasterStoragePolicy:     spec.MasterStoragePolicy,
+				EphemeralStoragePolicy:  spec.EphemeralStoragePolicy,
+				ImageStorage:            spec.ImageStorage,
+			}
+			if addr := spec.MasterManagementNetwork.AddressRange; addr != nil {
+				cluster.Info.APIServerManagementEndpoint = addr.StartingAddress
+				cluster.Info.APIServers = append(cluster.Info.APIServers, addr.StartingAddress)
+			}
+			s.Cluster[id] = cluster
+		case "disable":
+			if cluster.Info == nil {
+				s.apiFail(w, http.StatusBadRequest, "NOT_ALLOWED_IN_CURRENT_STATE")
+				return
+			}
+			for name, info := range s.Namespace {
+				if info.Cluster == id {
+					delete(s.Namespace, name)
+				}
+			}
+			delete(s.Cluster, id)
+		default:
+			s.apiFail(w, http.StatusBadRequest, "INVALID_ARGUMENT")
+			return
+		}
+		s.apiOK(w)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *handler) clusterCompatibility(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		names, err := s.computeClusters(r.Context())
+		if err != nil {
+			s.apiFail(w, http.StatusInternalServerError, "ERROR")
+			return
+		}
+		res := []namespace.ClusterCompatibility{}
+		for id := range names {
+			c := namespace.ClusterCompatibility{Cluster: id, Compatible: true, IncompatibilityReasons: []rest.LocalizableMessage{}}
+			if cluster, ok := s.Cluster[id]; ok && cluster.Info != nil {
+				c.Compatible = false
+				c.IncompatibilityReasons = append(c.IncompatibilityReasons, rest.LocalizableMessage{
+					ID:             "vcenter.wcp.cluster.enabled",
+					DefaultMessage: "Workload Management is already enabled on the cluster.",
+				})
+			}
+			res = append(res, c)
+		}
+		sort.Slice(res, func(i, j int) bool { return res[i].Cluster < res[j].Cluster })
+		s.apiOK(w, res)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *handler) clusterSupervisorServices(w http.ResponseWriter, r *http.Request, cluster *supervisorCluster, p []string) {
+	if len(p) == 1 {
+		svc, ok := cluster.Service[p[0]]
+		if !ok {
+			s.apiFail(w, http.StatusNotFound, "NOT_FOUND")
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			s.apiOK(w, svc)
+		case http.MethodDelete:
+			delete(cluster.Service, p[0])
+			s.apiOK(w)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		res := []namespace.ClusterSupervisorServiceSummary{}
+		for _, svc := range cluster.Service {
+			res = append(res, *svc)
+		}
+		s.apiOK(w, res)
+	case http.MethodPost:
+		var spec namespace.ClusterSupervisorServiceSpec
+		if !s.decode(r, w, &spec) {
+			return
+		}
+		svc, ok := s.Supervisor[spec.SupervisorService]
+		if !ok {
+			s.apiFail(w, http.StatusNotFound, "NOT_FOUND")
+			return
+		}
+		version, ok := svc.Version[spec.Version]
+		if !ok {
+			s.apiFail(w, http.StatusBadRequest, "INVALID_ARGUMENT")
+			return
+		}
+		if svc.State != namespace.SupervisorServiceActivated || version.State != namespace.SupervisorServiceActivated {
+			s.apiFail(w, http.StatusBadRequest, "NOT_ALLOWED_IN_CURRENT_STATE")
+			return
+		}
+		if _, ok = cluster.Service[spec.SupervisorService]; ok {
+			s.apiFail(w, http.StatusBadRequest, "ALREADY_EXISTS")
+			return
+		}
+		cluster.Service[spec.SupervisorService] = &namespace.ClusterSupervisorServiceSummary{
+			SupervisorService: spec.SupervisorService,
+			DesiredVersion:    spec.Version,
+			CurrentVersion:    spec.Version,
+			ConfigStatus:      namespace.ClusterServiceConfigured,
+		}
+		s.apiOK(w)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *handler) namespaceAccess(w http.ResponseWriter, r *http.Request, info *namespace.Info, subject namespace.Access) {
+	index := -1
+	for i, access := range info.AccessList {
+		if access.SubjectType == subject.SubjectType && access.Domain == subject.Domain && access.Subject == subject.Subject {
+			index = i
+		}
+	}
+
+	if r.Method != http.MethodPost && index == -1 {
+		s.apiFail(w, http.StatusNotFound, "NOT_FOUND")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		s.apiOK(w, namespace.AccessInfo{Role: info.AccessList[index].Role})
+	case http.MethodPost, http.MethodPut:
+		var spec struct {
+			Role string `json:"role"`
+		}
+		if !s.decode(r, w, &spec) {
+			return
+		}
+		subject.Role = spec.Role
+		if !validAccess(subject) {
+			s.apiFail(w, http.StatusBadRequest, "INVALID_ARGUMENT")
+			return
+		}
+		switch {
+		case r.Method == http.MethodPut:
+			info.AccessList[index] = subject
+		case index != -1:
+			s.apiFail(w, http.StatusBadRequest, "ALREADY_EXISTS")
+			return
+		default:
+			info.AccessList = append(info.AccessList, subject)
+		}
+		s.apiOK(w)
+	case http.MethodDelete:
+		info.AccessList = append(info.AccessList[:index], info.AccessList[index+1:]...)
+		s.apiOK(w)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
@@ -18,20 +18,31 @@ package simulator
 
 import (
 	"archive/tar"
+	"bytes"
+	"compress/gzip"
 	"context"
+	"crypto/sha1"
 	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
+	"encoding/pem"
+	"encoding/xml"
 	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"log"
+	"mime"
 	"net/http"
 	"net/url"
 	"os"
 	"path"
 	"path/filepath"
 	"reflect"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -39,15 +50,22 @@ import (
 	"github.com/google/uuid"
 	"github.com/vmware/govmomi"
 	"github.com/vmware/govmomi/nfc"
+	"github.com/vmware/govmomi/object"
 	"github.com/vmware/govmomi/ovf"
 	"github.com/vmware/govmomi/simulator"
 	"github.com/vmware/govmomi/vapi/internal"
 	"github.com/vmware/govmomi/vapi/library"
+	"github.com/vmware/govmomi/vapi/namespace"
 	"github.com/vmware/govmomi/vapi/rest"
 	"github.com/vmware/govmomi/vapi/tags"
 	"github.com/vmware/govmomi/vapi/vcenter"
+	"github.com/vmware/govmomi/vapi/vcenter/compute"
+	"github.com/vmware/govmomi/vapi/vcenter/guest"
+	"github.com/vmware/govmomi/vapi/vcenter/identity"
 	"github.com/vmware/govmomi/view"
+	"github.com/vmware/govmomi/vim25"
 	"github.com/vmware/govmomi/vim25/methods"
+	"github.com/vmware/govmomi/vim25/soap"
 	"github.com/vmware/govmomi/vim25/types"
 	vim "github.com/vmware/govmomi/vim25/types"
 )
@@ -59,7 +77,9 @@ type item struct {
 
 type content struct {
 	*library.Library
-	Item map[string]*item
+	Item       map[string]*item
+	Password   string
+	Subscriber map[string]*library.Subscriber
 }
 
 type update struct {
@@ -85,6 +105,18 @@ type handler struct {
 	Library     map[string]content
 	Update      map[string]update
 	Download    map[string]download
+	Appliance   *appliance
+	Namespace   map[string]*namespace.Info
+	VMClass     map[string]*namespace.VMClassInfo
+	Supervisor  map[string]*supervisorService
+	Cluster     map[string]*supervisorCluster
+	GuestSpec   map[string]*guest.CustomizationSpec
+	Module      map[string]*clusterModule
+	Deployment  *vcsaDeployment
+	Trust       *trustedInfra
+	Identity    map[string]*identity.Provider
+	Policy      map[string]*compute.Policy
+	VMTX        map[string]*vmtx
 }
 
 func init() {
@@ -92,6 +124,7 @@ func init() {
 		if r.IsVPX() {
 			path, handler := New(s.Listen, r.OptionManager().Setting)
 			s.Handle(path, handler)
+			s.Handle(internal.APIPath+"/", handler)
 		}
 	})
 }
@@ -108,6 +141,18 @@ func New(u *url.URL, settings []vim.BaseOptionValue) (string, http.Handler) {
 		Library:     make(map[string]content),
 		Update:      make(map[string]update),
 		Download:    make(map[string]download),
+		Appliance:   newAppliance(),
+		Namespace:   make(map[string]*namespace.Info),
+		VMClass:     newVMClasses(),
+		Supervisor:  make(map[string]*supervisorService),
+		Cluster:     make(map[string]*supervisorCluster),
+		GuestSpec:   make(map[string]*guest.CustomizationSpec),
+		Module:      make(map[string]*clusterModule),
+		Deployment:  newDeployment(),
+		Trust:       newTrustedInfra(),
+		Identity:    make(map[string]*identity.Provider),
+		Policy:      make(map[string]*compute.Policy),
+		VMTX:        make(map[string]*vmtx),
 	}
 
 	handlers := []struct {
@@ -126,6 +171,10 @@ func New(u *url.URL, settings []vim.BaseOptionValue) (string, http.Handler) {
 		{internal.SubscribedLibraryPath, s.library},
 		{internal.LibraryPath + "/", s.libraryID},
 		{internal.LocalLibraryPath + "/", s.libraryID},
+		{internal.SubscribedLibraryPath + "/", s.subscribedLibraryID},
+		{internal.Subscriptions, s.subscriptions},
+		{internal.Subscriptions + "/", s.subscriptionsID},
+		{internal.SubscribedLibraryItem + "/", s.subscribedLibraryItemID},
 		{internal.LibraryItemPath, s.libraryItem},
 		{internal.LibraryItemPath + "/", s.libraryItemID},
 		{internal.LibraryItemUpdateSession, s.libraryItemUpdateSession},
@@ -139,7 +188,47 @@ func New(u *url.URL, settings []vim.BaseOptionValue) (string, http.Handler) {
 		{internal.LibraryItemFileData + "/", s.libraryItemFileData},
 		{internal.LibraryItemFilePath, s.libraryItemFile},
 		{internal.LibraryItemFilePath + "/", s.libraryItemFileID},
+		{internal.LibraryItemStoragePath, s.libraryItemStorage},
+		{internal.LibraryItemStoragePath + "/", s.libraryItemStorageID},
 		{internal.VCenterOVFLibraryItem + "/", s.libraryItemDeployID},
+		{internal.VCenterVMTXLibraryItem, s.vmtxLibraryItem},
+		{internal.VCenterVMTXLibraryItem + "/", s.vmtxLibraryItemID},
+		{internal.VCenterISOImage + "/", s.isoImageID},
+		{internal.ClusterModulesPath, s.clusterModules},
+		{internal.ClusterModulesPath + "/", s.clusterModulesID},
+		{internal.ClusterModulesVMPath + "/", s.clusterModulesVM},
+		{internal.ApplianceHealthPath + "/", s.applianceHealth},
+		{internal.ApplianceMonitoringPath, s.applianceMonitoring},
+		{internal.ApplianceMonitoringPath + "/", s.applianceMonitoringID},
+		{internal.ApplianceServicesPath, s.applianceServices},
+		{internal.ApplianceServicesPath + "/", s.applianceServicesID},
+		{internal.ApplianceVMonServicePath, s.applianceVMonService},
+		{internal.ApplianceVMonServicePath + "/", s.applianceVMonServiceID},
+		{internal.ApplianceBackupPath + "/parts", s.applianceBackupParts},
+		{internal.ApplianceBackupPath + "/parts/", s.applianceBackupPartsID},
+		{internal.ApplianceBackupPath + "/job", s.applianceBackupJob},
+		{internal.ApplianceBackupPath + "/job/", s.applianceBackupJobID},
+		{internal.ApplianceRestorePath + "/job", s.applianceRestoreJob},
+		{internal.ApplianceRestorePath + "/job/cancel", s.applianceRestoreJobCancel},
+		{internal.ApplianceUpdatePath, s.applianceUpdate},
+		{internal.ApplianceUpdatePath + "/pending", s.applianceUpdatePending},
+		{internal.ApplianceUpdatePath + "/pending/", s.applianceUpdatePendingID},
+		{internal.ApplianceUpdatePath + "/staged", s.applianceUpdateStaged},
+		{internal.ApplianceUpdatePath + "/policy", s.applianceUpdatePolicy},
+		{internal.ApplianceNetworkingPath + "/interfaces", s.applianceNetworkInterfaces},
+		{internal.ApplianceNetworkingPath + "/interfaces/", s.applianceNetworkInterfacesID},
+		{internal.ApplianceNetworkingPath + "/dns/servers", s.applianceNetworkDNSServers},
+		{internal.ApplianceNetworkingPath + "/dns/hostname", s.applianceNetworkHostname},
+		{internal.ApplianceNetworkingPath + "/proxy", s.applianceNetworkProxy},
+		{internal.ApplianceNetworkingPath + "/proxy/", s.applianceNetworkProxyID},
+		{internal.ApplianceNetworkingPath + "/firewall/inbound", s.applianceNetworkFirewall},
+		{internal.ApplianceAccessPath + "/", s.applianceAccess},
+		{internal.ApplianceAccessPath + "/shell", s.applianceAccessShell},
+		{internal.ApplianceShutdownPath, s.applianceShutdown},
+		{internal.ApplianceShutdownPath + "/", s.applianceShutdownAction},
+		{internal.ApplianceLocalAccountsPath, s.applianceAccounts},
+		{internal.ApplianceLocalAccountsPath + "/", s.applianceAccountsID},
+		{internal.ApplianceLocalAccountsPath + "/global-policy", s.applianceAccountsPolicy},
 	}
 
 	for i := range handlers {
@@ -157,6 +246,70 @@ func New(u *url.URL, settings []vim.BaseOptionValue) (string, http.Handler) {
 		})
 	}
 
+	apiHandlers := []struct {
+		p string
+		m http.HandlerFunc
+	}{
+		{internal.APISessionPath, s.apiSession},
+		{internal.SecurityPoliciesPath, s.securityPolicies},
+		{internal.NamespacesInstancesPath, s.namespaceInstances},
+		{internal.NamespacesInstancesPath + "/", s.namespaceInstancesID},
+		{internal.NamespaceVMClassesPath, s.namespaceVMClasses},
+		{internal.NamespaceVMClassesPath + "/", s.namespaceVMClassesID},
+		{internal.SupervisorServicesPath, s.supervisorServices},
+		{internal.SupervisorServicesPath + "/", s.supervisorServicesID},
+		{internal.NamespaceClustersPath, s.namespaceClusters},
+		{internal.NamespaceClustersPath + "/", s.namespaceClustersID},
+		{internal.ClusterCompatibilityPath, s.clusterCompatibility},
+		{internal.VCenterVMPath, s.vcenterVM},
+		{internal.VCenterVMPath + "/", s.vcenterVMID},
+		{internal.CustomizationSpecsPath, s.customizationSpecs},
+		{internal.CustomizationSpecsPath + "/", s.customizationSpecsID},
+		{internal.DeploymentPath, s.deployment},
+		{internal.DeploymentPath + "/question", s.deploymentQuestion},
+		{internal.DeploymentPath + "/install", s.deploymentOperation},
+		{internal.DeploymentPath + "/upgrade", s.deploymentOperation},
+		{internal.DeploymentPath + "/import-history", s.deploymentImportHistory},
+		{internal.TrustedInfrastructurePath + "/trust-authority-clusters", s.trustAuthorityClusters},
+		{internal.TrustedInfrastructurePath + "/trust-authority-clusters/", s.trustAuthorityClustersID},
+		{internal.TrustedInfrastructurePath + "/attestation/services", s.attestationServices},
+		{internal.TrustedInfrastructurePath + "/attestation/services/", s.attestationServices},
+		{internal.TrustedInfrastructurePath + "/kms/services", s.kmsServices},
+		{internal.TrustedInfrastructurePath + "/kms/services/", s.kmsServices},
+		{internal.IdentityProvidersPath, s.identityProviders},
+		{internal.IdentityProvidersPath + "/", s.identityProvidersID},
+		{internal.ComputePoliciesPath, s.computePolicies},
+		{internal.ComputePoliciesPath + "/", s.computePoliciesID},
+		{internal.ComputePoliciesPath + "/capabilities", s.computePolicyCapabilities},
+		{internal.ComputePoliciesPath + "/capabilities/", s.computePolicyCapabilities},
+		{internal.VCenterResourcePoolPath, s.vcenterResourcePools},
+		{internal.VCenterResourcePoolPath + "/", s.vcenterResourcePoolID},
+		{internal.VCenterFolderPath, s.vcenterFolders},
+		{internal.VCenterDatacenterPath, s.vcenterDatacenters},
+		{internal.VCenterDatacenterPath + "/", s.vcenterDatacenterID},
+		{internal.VCenterClusterPath, s.vcenterClusters},
+		{internal.VCenterClusterPath + "/", s.vcenterClusterID},
+		{internal.VCenterHostPath, s.vcenterHosts},
+		{internal.VCenterDatastorePath, s.vcenterDatastores},
+		{internal.VCenterDatastorePath + "/", s.vcenterDatastoreID},
+		{internal.VCenterNetworkPath, s.vcenterNetworks},
+	}
+
+	for i := range apiHandlers {
+		h := apiHandlers[i]
+		s.HandleFunc(h.p, func(w http.ResponseWriter, r *http.Request) {
+			s.Lock()
+			defer s.Unlock()
+
+			if !s.isAuthorized(r) {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			h.m(w, r)
+		})
+	}
+
 	return internal.Path + "/", s
 }
 
@@ -164,6 +317,9 @@ func (s *handler) isAuthorized(r *http.Request) bool {
 	if r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, internal.SessionPath) && s.action(r) == "" {
 		return true
 	}
+	if r.Method == http.MethodPost && r.URL.Path == internal.APISessionPath {
+		return true
+	}
 	id := r.Header.Get(internal.SessionCookieName)
 	if id == "" {
 		if cookie, err := r.Cookie(internal.SessionCookieName); err == nil {
@@ -189,7 +345,54 @@ func (s *handler) hasAuthorization(r *http.Request) (string, bool) {
 		return u, true
 	}
 	auth := r.Header.Get("Authorization")
-	return "TODO", strings.HasPrefix(auth, "SIGN ") // token auth
+	if !strings.HasPrefix(auth, "SIGN ") {
+		return "", false
+	}
+	// token auth, see sts.Signer.SignRequest
+	user, err := tokenSubject(strings.TrimPrefix(auth, "SIGN "))
+	if err != nil {
+		log.Printf("token auth: %s", err)
+		return "", false
+	}
+	return user, true
+}
+
+// tokenSubject returns the NameID of the gzip'd and base64 encoded SAML token within the given SIGN parameters.
+// The token signature is not verified.
+func tokenSubject(params string) (string, error) {
+	var token string
+	for _, param := range strings.Split(params, ", ") {
+		kv := strings.SplitN(param, "=", 2)
+		if len(kv) == 2 && kv[0] == "token" {
+			token = strings.Trim(kv[1], `"`)
+		}
+	}
+	if token == "" {
+		return "", errors.New("missing token")
+	}
+
+	b, err := base64.StdEncoding.DecodeString(token)
+	if err != nil {
+		return "", err
+	}
+	gz, err := gzip.NewReader(bytes.NewReader(b))
+	if err != nil {
+		return "", err
+	}
+	defer gz.Close()
+
+	var assertion struct {
+		Subject struct {
+			NameID string `xml:"NameID"`
+		} `xml:"Subject"`
+	}
+	if err = xml.NewDecoder(gz).Decode(&assertion); err != nil {
+		return "", err
+	}
+	if assertion.Subject.NameID == "" {
+		return "", errors.New("missing token subject")
+	}
+	return assertion.Subject.NameID, nil
 }
 
 func (s *handler) findTag(e vim.VslmTagEntry) *tags.Tag {
@@ -291,6 +494,36 @@ func (s *handler) fail(w http.ResponseWriter, kind string) {
 	}
 }
 
+// apiOK responds with http.StatusOK and json encodes val if given, unwrapped as per the /api protocol.
+func (s *handler) apiOK(w http.ResponseWriter, val ...interface{}) {
+	w.WriteHeader(http.StatusOK)
+
+	if len(val) == 0 {
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(val[0]); err != nil {
+		log.Panic(err)
+	}
+}
+
+// apiFail responds with the given status and /api protocol error type, for example: "NOT_FOUND".
+func (s *handler) apiFail(w http.ResponseWriter, status int, kind string) {
+	w.WriteHeader(status)
+
+	err := json.NewEncoder(w).Encode(struct {
+		ErrorType string   `json:"error_type"`
+		Messages  []string `json:"messages"`
+	}{
+		ErrorType: kind,
+		Messages:  []string{},
+	})
+
+	if err != nil {
+		log.Panic(err)
+	}
+}
+
 func (*handler) error(w http.ResponseWriter, err error) {
 	http.Error(w, err.Error(), http.StatusInternalServerError)
 	log.Print(err)
@@ -311,6 +544,12 @@ func (s *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 func (s *handler) decode(r *http.Request, w http.ResponseWriter, val interface{}) bool {
 	defer r.Body.Close()
+	// vCenter rejects a JSON request body without the JSON media type
+	if kind, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); kind != "application/json" {
+		log.Printf("%s %s: Content-Type=%q", r.Method, r.RequestURI, r.Header.Get("Content-Type"))
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+		return false
+	}
 	err := json.NewDecoder(r.Body).Decode(val)
 	if err != nil {
 		log.Printf("%s %s: %s", r.Method, r.RequestURI, err)
@@ -338,9 +577,7 @@ func (s *handler) session(w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(http.StatusUnauthorized)
 			return
 		}
-		id = uuid.New().String()
-		now := time.Now()
-		s.Session[id] = &rest.Session{User: user, Created: now, LastAccessed: now}
+		id = s.newSession(user)
 		http.SetCookie(w, &http.Cookie{
 			Name:  internal.SessionCookieName,
 			Value: id,
@@ -355,6 +592,37 @@ func (s *handler) session(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// apiSession implements the /api protocol session endpoint,
+// where response bodies are not wrapped in a "value" field.
+func (s *handler) apiSession(w http.ResponseWriter, r *http.Request) {
+	id := r.Header.Get(internal.SessionCookieName)
+
+	switch r.Method {
+	case http.MethodPost:
+		user, ok := s.hasAuthorization(r)
+		if !ok {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(s.newSession(user))
+	case http.MethodDelete:
+		delete(s.Session, id)
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodGet:
+		_ = json.NewEncoder(w).Encode(s.Session[id])
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *handler) newSession(user string) string {
+	id := uuid.New().String()
+	now := time.Now()
+	s.Session[id] = &rest.Session{User: user, Created: now, LastAccessed: now}
+	return id
+}
+
 func (s *handler) action(r *http.Request) string {
 	return r.URL.Query().Get("~action")
 }
@@ -409,6 +677,27 @@ func (s *handler) categoryID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	switch s.action(r) {
+	case "add-to-used-by", "remove-from-used-by":
+		var spec struct {
+			Entity string `json:"used_by_entity"`
+		}
+		if s.decode(r, w, &spec) {
+			var used []string
+			for _, entity := range o.UsedBy {
+				if entity != spec.Entity {
+					used = append(used, entity)
+				}
+			}
+			if s.action(r) == "add-to-used-by" {
+				used = append(used, spec.Entity)
+			}
+			o.UsedBy = used
+			s.ok(w)
+		}
+		return
+	}
+
 	switch r.Method {
 	case http.MethodDelete:
 		delete(s.Category, id)
@@ -437,6 +726,11 @@ func (s *handler) categoryID(w http.ResponseWriter, r *http.Request) {
 					return
 				}
 			}
+			if spec.Category.Cardinality == tags.CardinalitySingle && o.Cardinality == tags.CardinalityMultiple {
+				// Cardinality can only be changed from SINGLE to MULTIPLE.
+				s.fail(w, "com.vmware.vapi.std.errors.invalid_argument")
+				return
+			}
 			o.Patch(&spec.Category)
 			s.ok(w)
 		}
@@ -453,7 +747,8 @@ func (s *handler) tag(w http.ResponseWriter, r *http.Request) {
 		}
 		if s.decode(r, w, &spec) {
 			for _, tag := range s.Tag {
-				if tag.Name == spec.Tag.Name {
+				// Tag names are unique within a category
+				if tag.Name == spec.Tag.Name && tag.CategoryID == spec.Tag.CategoryID {
 					s.fail(w, "com.vmware.vapi.std.errors.already_exists")
 					return
 				}
@@ -469,8 +764,25 @@ func (s *handler) tag(w http.ResponseWriter, r *http.Request) {
 		for id := range s.Tag {
 			ids = append(ids, id)
 		}
-		s.ok(w, ids)
+		s.ok(w, page(r, ids))
+	}
+}
+
+// page returns the page of ids selected by the marker and limit query parameters, if any.
+func page(r *http.Request, ids []string) []string {
+	sort.Strings(ids)
+
+	q := r.URL.Query()
+	if marker := q.Get(internal.MarkerParameter); marker != "" {
+		ids = ids[sort.SearchStrings(ids, marker):]
+		if len(ids) != 0 && ids[0] == marker {
+			ids = ids[1:]
+		}
+	}
+	if limit, err := strconv.Atoi(q.Get(internal.LimitParameter)); err == nil && limit > 0 && limit < len(ids) {
+		ids = ids[:limit]
 	}
+	return ids
 }
 
 func (s *handler) tagID(w http.ResponseWriter, r *http.Request) {
@@ -529,6 +841,22 @@ func (s *handler) association(w http.ResponseWriter, r *http.Request) {
 	}
 
 	switch s.action(r) {
+	case "list-attached-tags", "list-attachable-tags", "attach-multiple-tags-to-object", "detach-multiple-tags-from-object":
+		if spec.ObjectID == nil {
+			s.fail(w, "com.vmware.vapi.std.errors.invalid_argument")
+			return
+		}
+	}
+
+	switch s.action(r) {
+	case "list-attachable-tags":
+		var ids []string
+		for id, objs := range s.Association {
+			if !objs[*spec.ObjectID] && s.attachable(id, *spec.ObjectID) == "" {
+				ids = append(ids, id)
+			}
+		}
+		s.ok(w, ids)
 	case "list-attached-tags":
 		var ids []string
 		for id, objs := range s.Association {
@@ -540,13 +868,36 @@ func (s *handler) association(w http.ResponseWriter, r *http.Request) {
 	case "list-attached-objects-on-tags":
 		var res []tags.AttachedObjects
 		for _, id := range spec.TagIDs {
+			objs, exists := s.Association[id]
+			if !exists {
+				continue
+			}
 			o := tags.AttachedObjects{TagID: id}
-			for i := range s.Association[id] {
+			for i := range objs {
 				o.ObjectIDs = append(o.ObjectIDs, i)
 			}
 			res = append(res, o)
 		}
 		s.ok(w, res)
+	case "attach-multiple-tags-to-object", "detach-multiple-tags-from-object":
+		var res batchResponse
+		for _, id := range spec.TagIDs {
+			objs, exists := s.Association[id]
+			if !exists {
+				res.add("com.vmware.vapi.std.errors.not_found", fmt.Sprintf("Tag %s not found", id), id)
+				continue
+			}
+			if s.action(r) == "attach-multiple-tags-to-object" {
+				if kind := s.attachable(id, *spec.ObjectID); kind != "" {
+					res.add(kind, fmt.Sprintf("Tag %s cannot be attached to %s", id, spec.ObjectID.Reference()), id, spec.ObjectID.Value)
+					continue
+				}
+				objs[*spec.ObjectID] = true
+			} else {
+				delete(objs, *spec.ObjectID)
+			}
+		}
+		s.ok(w, res.done())
 	case "list-attached-tags-on-objects":
 		var res []tags.AttachedTags
 		for _, ref := range spec.ObjectIDs {
@@ -562,6 +913,56 @@ func (s *handler) association(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// batchResponse is the tag-association batch operation response
+type batchResponse struct {
+	Success bool             `json:"success"`
+	Errors  tags.BatchErrors `json:"error_messages,omitempty"`
+}
+
+func (r *batchResponse) add(kind, msg string, args ...string) {
+	r.Errors = append(r.Errors, tags.BatchError{
+		ID:      kind,
+		Message: msg,
+		Args:    args,
+	})
+}
+
+func (r *batchResponse) done() *batchResponse {
+	r.Success = len(r.Errors) == 0
+	return r
+}
+
+// attachable returns the error type if the given tag cannot be attached to the given object,
+// based on the tag category's AssociableTypes and Cardinality, or an empty string if it can be attached.
+func (s *handler) attachable(id string, ref internal.AssociatedObject) string {
+	category, ok := s.Category[s.Tag[id].CategoryID]
+	if !ok {
+		return ""
+	}
+
+	if len(category.AssociableTypes) != 0 {
+		ok = false
+		for _, kind := range category.AssociableTypes {
+			if kind == ref.Type {
+				ok = true
+			}
+		}
+		if !ok {
+			return "com.vmware.vapi.std.errors.invalid_argument"
+		}
+	}
+
+	if category.Cardinality == tags.CardinalitySingle {
+		for tid, objs := range s.Association {
+			if tid != id && objs[ref] && s.Tag[tid].CategoryID == category.ID {
+				return "com.vmware.vapi.std.errors.invalid_argument"
+			}
+		}
+	}
+
+	return ""
+}
+
 func (s *handler) associationID(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		w.WriteHeader(http.StatusMethodNotAllowed)
@@ -575,18 +976,48 @@ func (s *handler) associationID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var spec internal.Association
+	var spec struct {
+		internal.Association
+		ObjectIDs []internal.AssociatedObject `json:"object_ids,omitempty"`
+	}
 	if !s.decode(r, w, &spec) {
 		return
 	}
 
+	switch s.action(r) {
+	case "attach", "detach":
+		if spec.ObjectID == nil {
+			s.fail(w, "com.vmware.vapi.std.errors.invalid_argument")
+			return
+		}
+	}
+
 	switch s.action(r) {
 	case "attach":
+		if kind := s.attachable(id, *spec.ObjectID); kind != "" {
+			s.fail(w, kind)
+			return
+		}
 		s.Association[id][*spec.ObjectID] = true
 		s.ok(w)
 	case "detach":
 		delete(s.Association[id], *spec.ObjectID)
 		s.ok(w)
+	case "attach-tag-to-multiple-objects":
+		var res batchResponse
+		for _, ref := range spec.ObjectIDs {
+			if kind := s.attachable(id, ref); kind != "" {
+				res.add(kind, fmt.Sprintf("Tag %s cannot be attached to %s", id, ref.Reference()), id, ref.Value)
+				continue
+			}
+			s.Association[id][ref] = true
+		}
+		s.ok(w, res.done())
+	case "detach-tag-from-multiple-objects":
+		for _, ref := range spec.ObjectIDs {
+			delete(s.Association[id], ref)
+		}
+		s.ok(w, new(batchResponse).done())
 	case "list-attached-objects":
 		var ids []internal.AssociatedObject
 		for id := range s.Association[id] {
@@ -625,6 +1056,10 @@ func (s *handler) library(w http.ResponseWriter, r *http.Request) {
 			}
 			s.ok(w, ids)
 		case "":
+			if p := spec.Library.SecurityPolicyID; p != "" && p != securityPolicy.Policy {
+				s.fail(w, "com.vmware.vapi.std.errors.invalid_argument")
+				return
+			}
 			id := uuid.New().String()
 			spec.Library.ID = id
 			dir := libraryPath(&spec.Library, "")
@@ -632,10 +1067,19 @@ func (s *handler) library(w http.ResponseWriter, r *http.Request) {
 				s.error(w, err)
 				return
 			}
-			s.Library[id] = content{
-				Library: &spec.Library,
-				Item:    make(map[string]*item),
+			l := content{
+				Library:    &spec.Library,
+				Item:       make(map[string]*item),
+				Subscriber: make(map[string]*library.Subscriber),
+			}
+			if info := spec.Library.Publish; info != nil {
+				l.Publish = nil
+				if kind := s.publish(&l, info); kind != "" {
+					s.fail(w, kind)
+					return
+				}
 			}
+			s.Library[id] = l
 			s.ok(w, id)
 		}
 	case http.MethodGet:
@@ -670,102 +1114,525 @@ func (s *handler) libraryID(w http.ResponseWriter, r *http.Request) {
 			Library library.Library `json:"update_spec"`
 		}
 		if s.decode(r, w, &spec) {
+			if p := spec.Library.SecurityPolicyID; p != "" && p != securityPolicy.Policy {
+				s.fail(w, "com.vmware.vapi.std.errors.invalid_argument")
+				return
+			}
+			if info := spec.Library.Publish; info != nil {
+				if kind := s.publish(&l, info); kind != "" {
+					s.fail(w, kind)
+					return
+				}
+				s.Library[id] = l
+			}
 			l.Patch(&spec.Library)
 			s.ok(w)
 		}
-	case http.MethodGet:
-		s.ok(w, l)
-	}
-}
-
-func (s *handler) libraryItem(w http.ResponseWriter, r *http.Request) {
-	switch r.Method {
 	case http.MethodPost:
+		if s.action(r) != "publish" {
+			http.NotFound(w, r)
+			return
+		}
 		var spec struct {
-			Item library.Item     `json:"create_spec"`
-			Find library.FindItem `json:"spec"`
+			Subscriptions []subscriptionID `json:"subscriptions"`
 		}
 		if !s.decode(r, w, &spec) {
 			return
 		}
-
-		switch s.action(r) {
-		case "find":
-			var ids []string
-			for _, l := range s.Library {
-				if spec.Find.LibraryID != "" {
-					if spec.Find.LibraryID != l.ID {
-						continue
-					}
-				}
-				for _, i := range l.Item {
-					if spec.Find.Name != "" {
-						if spec.Find.Name != i.Name {
-							continue
-						}
-					}
-					if spec.Find.Type != "" {
-						if spec.Find.Type != i.Type {
-							continue
-						}
-					}
-					ids = append(ids, i.ID)
-				}
-			}
-			s.ok(w, ids)
-		case "create", "":
-			id := spec.Item.LibraryID
-			l, ok := s.Library[id]
-			if !ok {
-				log.Printf("library not found: %s", id)
-				http.NotFound(w, r)
-				return
-			}
-			for _, item := range l.Item {
-				if item.Name == spec.Item.Name {
-					s.fail(w, "com.vmware.vapi.std.errors.already_exists")
-					return
-				}
+		subs, kind := s.subscribers(&l, spec.Subscriptions)
+		if kind != "" {
+			s.fail(w, kind)
+			return
+		}
+		for _, sub := range subs {
+			for _, i := range l.Item {
+				s.publishItem(&l, sub, i, false)
 			}
-			id = uuid.New().String()
-			spec.Item.ID = id
-			l.Item[id] = &item{Item: &spec.Item}
-			s.ok(w, id)
 		}
+		s.ok(w)
 	case http.MethodGet:
-		id := r.URL.Query().Get("library_id")
-		l, ok := s.Library[id]
-		if !ok {
-			log.Printf("library not found: %s", id)
-			http.NotFound(w, r)
-			return
-		}
+		s.ok(w, l)
+	}
+}
 
-		var ids []string
-		for id := range l.Item {
-			ids = append(ids, id)
-		}
-		s.ok(w, ids)
+// onDemand returns true if the given library is subscribed and only downloads item content when requested.
+func onDemand(l *library.Library) bool {
+	return l.Subscription != nil && l.Subscription.OnDemand != nil && *l.Subscription.OnDemand
+}
+
+// syncItem simulates synchronization of a subscribed library item, caching its content
+// unless the library is on-demand and force is false.
+func syncItem(l *library.Library, i *item, force bool, now time.Time) {
+	i.LastSyncTime = &now
+	if force || !onDemand(l) {
+		i.Cached = true
 	}
 }
 
-func (s *handler) libraryItemID(w http.ResponseWriter, r *http.Request) {
-	id := s.id(r)
-	lid := r.URL.Query().Get("library_id")
-	if lid == "" {
-		if l := s.itemLibrary(id); l != nil {
-			lid = l.ID
-		}
+func (s *handler) subscribedLibraryID(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		s.libraryID(w, r)
+		return
 	}
-	l, ok := s.Library[lid]
+
+	id := s.id(r)
+	l, ok := s.Library[id]
 	if !ok {
-		log.Printf("library not found: %q", lid)
+		log.Printf("library not found: %s", id)
 		http.NotFound(w, r)
 		return
 	}
-	item, ok := l.Item[id]
-	if !ok {
-		log.Printf("library item not found: %q", id)
+	if l.Type != "SUBSCRIBED" {
+		s.fail(w, "com.vmware.vapi.std.errors.invalid_element_type")
+		return
+	}
+
+	switch s.action(r) {
+	case "sync":
+		now := time.Now()
+		l.LastSyncTime = &now
+		for _, i := range l.Item {
+			syncItem(l.Library, i, false, now)
+		}
+		s.ok(w)
+	case "evict":
+		if !onDemand(l.Library) {
+			s.fail(w, "com.vmware.vapi.std.errors.not_allowed_in_current_state")
+			return
+		}
+		for _, i := range l.Item {
+			i.Cached = false
+		}
+		s.ok(w)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *handler) subscribedLibraryItemID(w http.ResponseWriter, r *http.Request) {
+	id := s.id(r)
+	lib := s.itemLibrary(id)
+	if lib == nil {
+		log.Printf("library item not found: %q", id)
+		http.NotFound(w, r)
+		return
+	}
+	if lib.Type != "SUBSCRIBED" {
+		s.fail(w, "com.vmware.vapi.std.errors.invalid_element_type")
+		return
+	}
+	i := s.Library[lib.ID].Item[id]
+
+	switch s.action(r) {
+	case "sync":
+		var spec struct {
+			Force bool `json:"force_sync_content"`
+		}
+		if s.decode(r, w, &spec) {
+			syncItem(lib, i, spec.Force, time.Now())
+			s.ok(w)
+		}
+	case "evict":
+		if !onDemand(lib) {
+			s.fail(w, "com.vmware.vapi.std.errors.not_allowed_in_current_state")
+			return
+		}
+		i.Cached = false
+		s.ok(w)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+type subscriptionID struct {
+	ID string `json:"subscription"`
+}
+
+// published returns an error kind if the given library is not a published local library.
+func published(l *content) string {
+	if l.Type != "LOCAL" {
+		return "com.vmware.vapi.std.errors.invalid_element_type"
+	}
+	if l.Publish == nil || l.Publish.Published == nil || !*l.Publish.Published {
+		return "com.vmware.vapi.std.errors.not_allowed_in_current_state"
+	}
+	return ""
+}
+
+// subscribers returns the subscribers of the given published library with the given subscription IDs,
+// or all of its subscribers if ids is empty.
+func (s *handler) subscribers(l *content, ids []subscriptionID) ([]*library.Subscriber, string) {
+	if kind := published(l); kind != "" {
+		return nil, kind
+	}
+	var subs []*library.Subscriber
+	if len(ids) == 0 {
+		for _, sub := range l.Subscriber {
+			subs = append(subs, sub)
+		}
+		return subs, ""
+	}
+	for _, id := range ids {
+		sub, ok := l.Subscriber[id.ID]
+		if !ok {
+			return nil, "com.vmware.vapi.std.errors.not_found"
+		}
+		subs = append(subs, sub)
+	}
+	return subs, ""
+}
+
+func (s *handler) subscriptions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	id := r.URL.Query().Get("library")
+	l, ok := s.Library[id]
+	if !ok {
+		log.Printf("library not found: %s", id)
+		http.NotFound(w, r)
+		return
+	}
+
+	res := []library.SubscriberSummary{}
+	for sid, sub := range l.Subscriber {
+		summary := library.SubscriberSummary{
+			SubscriptionID: sid,
+			LibraryID:      sub.LibraryID,
+			LibraryName:    sub.LibraryName,
+		}
+		if sub.Vcenter != nil {
+			summary.VcenterHostname = sub.Vcenter.Hostname
+		}
+		res = append(res, summary)
+	}
+	s.ok(w, res)
+}
+
+func (s *handler) subscriptionsID(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	id := s.id(r)
+	l, ok := s.Library[id]
+	if !ok {
+		log.Printf("library not found: %s", id)
+		http.NotFound(w, r)
+		return
+	}
+
+	var spec struct {
+		Spec struct {
+			Library library.SubscriberLibrary `json:"subscribed_library"`
+		} `json:"spec"`
+		subscriptionID
+	}
+	if !s.decode(r, w, &spec) {
+		return
+	}
+
+	switch s.action(r) {
+	case "", "create":
+		if kind := published(&l); kind != "" {
+			s.fail(w, kind)
+			return
+		}
+		sub, kind := s.subscriber(&l, spec.Spec.Library)
+		if kind != "" {
+			s.fail(w, kind)
+			return
+		}
+		sid := uuid.New().String()
+		l.Subscriber[sid] = sub
+		for _, i := range l.Item {
+			s.publishItem(&l, sub, i, false)
+		}
+		s.ok(w, sid)
+	case "get":
+		sub, ok := l.Subscriber[spec.ID]
+		if !ok {
+			s.fail(w, "com.vmware.vapi.std.errors.not_found")
+			return
+		}
+		s.ok(w, sub)
+	case "delete":
+		delete(l.Subscriber, spec.ID)
+		s.ok(w)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// subscriber creates the subscriber of a subscription to the published library pub,
+// creating a subscribed library if spec.Target is CREATE_NEW and spec.Location is LOCAL.
+// Remote vCenters are not simulated, their libraries are recorded but not created.
+func (s *handler) subscriber(pub *content, spec library.SubscriberLibrary) (*library.Subscriber, string) {
+	invalid := "com.vmware.vapi.std.errors.invalid_argument"
+	sub := &library.Subscriber{
+		Location:  spec.Location,
+		Vcenter:   spec.Vcenter,
+		Placement: spec.Placement,
+	}
+
+	switch spec.Location {
+	case library.SubscriptionLocationLocal:
+	case library.SubscriptionLocationRemote:
+		if spec.Vcenter == nil || spec.Vcenter.Hostname == "" {
+			return nil, invalid
+		}
+	default:
+		return nil, invalid
+	}
+
+	switch spec.Target {
+	case library.SubscriptionTargetCreateNew:
+		n := spec.NewSubscribedLibrary
+		if n == nil || n.Name == "" {
+			return nil, invalid
+		}
+		sub.LibraryID = uuid.New().String()
+		sub.LibraryName = n.Name
+		if spec.Location == library.SubscriptionLocationRemote {
+			return sub, ""
+		}
+		if len(n.StorageBackings) == 0 {
+			return nil, invalid
+		}
+		lib := &library.Library{
+			ID:          sub.LibraryID,
+			Name:        n.Name,
+			Description: n.Description,
+			Type:        "SUBSCRIBED",
+			Storage:     n.StorageBackings,
+			Subscription: &library.Subscription{
+				AuthenticationMethod: pub.Publish.AuthenticationMethod,
+				AutomaticSyncEnabled: types.NewBool(n.AutomaticSyncEnabled),
+				OnDemand:             types.NewBool(n.OnDemand),
+				SubscriptionURL:      pub.Publish.PublishURL,
+			},
+		}
+		if err := os.Mkdir(libraryPath(lib, ""), 0750); err != nil {
+			log.Printf("subscribed library %s: %s", lib.ID, err)
+			return nil, "com.vmware.vapi.std.errors.internal_server_error"
+		}
+		s.Library[lib.ID] = content{
+			Library:    lib,
+			Item:       make(map[string]*item),
+			Subscriber: make(map[string]*library.Subscriber),
+		}
+	case library.SubscriptionTargetUseExisting:
+		if spec.LibraryID == "" {
+			return nil, invalid
+		}
+		sub.LibraryID = spec.LibraryID
+		if spec.Location == library.SubscriptionLocationLocal {
+			l, ok := s.Library[spec.LibraryID]
+			if !ok || l.Type != "SUBSCRIBED" {
+				return nil, invalid
+			}
+			sub.LibraryName = l.Name
+		}
+	default:
+		return nil, invalid
+	}
+
+	return sub, ""
+}
+
+// publishItem synchronizes the published item src to the local subscribed library of sub,
+// creating the subscribed item if needed. Items are not published to remote subscribers.
+func (s *handler) publishItem(pub *content, sub *library.Subscriber, src *item, force bool) {
+	if sub.Location != library.SubscriptionLocationLocal {
+		return
+	}
+	l, ok := s.Library[sub.LibraryID]
+	if !ok {
+		return
+	}
+
+	var dst *item
+	for _, i := range l.Item {
+		if i.SourceID == src.ID {
+			dst = i
+			break
+		}
+	}
+	if dst == nil {
+		info := *src.Item
+		info.ID = uuid.New().String()
+		info.LibraryID = l.ID
+		info.SourceID = src.ID
+		info.Cached = false
+		dst = &item{Item: &info}
+		l.Item[info.ID] = dst
+	}
+	dst.File = append([]library.File(nil), src.File...)
+
+	now := time.Now()
+	l.LastSyncTime = &now
+	syncItem(l.Library, dst, force, now)
+	if !dst.Cached {
+		return
+	}
+
+	dir := libraryPath(l.Library, dst.ID)
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		log.Printf("publish %s: %s", dst.ID, err)
+		return
+	}
+	for _, f := range src.File {
+		b, err := ioutil.ReadFile(path.Join(libraryPath(pub.Library, src.ID), f.Name))
+		if err == nil {
+			err = ioutil.WriteFile(path.Join(dir, f.Name), b, 0640)
+		}
+		if err != nil {
+			log.Printf("publish %s: %s", f.Name, err)
+		}
+	}
+}
+
+// publish merges the given publish info into the library, returning an error kind if the update is not allowed.
+// The password is retained by the handler rather than the library info, as it is never returned to the client.
+func (s *handler) publish(l *content, info *library.Publish) string {
+	if l.Type != "LOCAL" {
+		return "com.vmware.vapi.std.errors.invalid_argument"
+	}
+	if l.Publish == nil {
+		l.Publish = new(library.Publish)
+	}
+	pub := l.Publish
+
+	if info.Password != "" {
+		if l.Password != "" && info.CurrentPassword != l.Password {
+			return "com.vmware.vapi.std.errors.unauthorized"
+		}
+		l.Password = info.Password
+	}
+	if info.AuthenticationMethod != "" {
+		pub.AuthenticationMethod = info.AuthenticationMethod
+	}
+	if info.UserName != "" {
+		pub.UserName = info.UserName
+	}
+	if info.PersistJSON != nil {
+		pub.PersistJSON = info.PersistJSON
+	}
+	if info.Published != nil {
+		pub.Published = info.Published
+	}
+
+	pub.PublishURL = ""
+	if pub.Published != nil && *pub.Published {
+		u := s.URL
+		u.Path = path.Join("/cls/vcsp/lib", l.ID, "lib.json")
+		pub.PublishURL = u.String()
+	}
+
+	return ""
+}
+
+func (s *handler) libraryItem(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		var spec struct {
+			Item library.Item     `json:"create_spec"`
+			Find library.FindItem `json:"spec"`
+		}
+		if !s.decode(r, w, &spec) {
+			return
+		}
+
+		switch s.action(r) {
+		case "find":
+			var ids []string
+			for _, l := range s.Library {
+				if spec.Find.LibraryID != "" {
+					if spec.Find.LibraryID != l.ID {
+						continue
+					}
+				}
+				for _, i := range l.Item {
+					if spec.Find.Name != "" {
+						if !strings.EqualFold(spec.Find.Name, i.Name) {
+							continue
+						}
+					}
+					if spec.Find.Type != "" {
+						if spec.Find.Type != i.Type {
+							continue
+						}
+					}
+					if spec.Find.SourceID != "" {
+						if spec.Find.SourceID != i.SourceID {
+							continue
+						}
+					}
+					if spec.Find.Cached != nil {
+						if *spec.Find.Cached != i.Cached {
+							continue
+						}
+					}
+					ids = append(ids, i.ID)
+				}
+			}
+			s.ok(w, ids)
+		case "create", "":
+			id := spec.Item.LibraryID
+			l, ok := s.Library[id]
+			if !ok {
+				log.Printf("library not found: %s", id)
+				http.NotFound(w, r)
+				return
+			}
+			for _, item := range l.Item {
+				if item.Name == spec.Item.Name {
+					s.fail(w, "com.vmware.vapi.std.errors.already_exists")
+					return
+				}
+			}
+			id = uuid.New().String()
+			spec.Item.ID = id
+			l.Item[id] = &item{Item: &spec.Item}
+			s.ok(w, id)
+		}
+	case http.MethodGet:
+		id := r.URL.Query().Get("library_id")
+		l, ok := s.Library[id]
+		if !ok {
+			log.Printf("library not found: %s", id)
+			http.NotFound(w, r)
+			return
+		}
+
+		var ids []string
+		for id := range l.Item {
+			ids = append(ids, id)
+		}
+		s.ok(w, ids)
+	}
+}
+
+func (s *handler) libraryItemID(w http.ResponseWriter, r *http.Request) {
+	id := s.id(r)
+	lid := r.URL.Query().Get("library_id")
+	if lid == "" {
+		if l := s.itemLibrary(id); l != nil {
+			lid = l.ID
+		}
+	}
+	l, ok := s.Library[lid]
+	if !ok {
+		log.Printf("library not found: %q", lid)
+		http.NotFound(w, r)
+		return
+	}
+	item, ok := l.Item[id]
+	if !ok {
+		log.Printf("library item not found: %q", id)
 		http.NotFound(w, r)
 		return
 	}
@@ -787,11 +1654,77 @@ func (s *handler) libraryItemID(w http.ResponseWriter, r *http.Request) {
 			item.Patch(&spec.Item)
 			s.ok(w)
 		}
+	case http.MethodPost:
+		switch s.action(r) {
+		case "verify":
+			if l.SecurityPolicyID == "" {
+				s.fail(w, "com.vmware.vapi.std.errors.not_allowed_in_current_state")
+				return
+			}
+			item.verify()
+			s.ok(w)
+		case "publish":
+			var spec struct {
+				Force         bool             `json:"force_sync_content"`
+				Subscriptions []subscriptionID `json:"subscriptions"`
+			}
+			if !s.decode(r, w, &spec) {
+				return
+			}
+			subs, kind := s.subscribers(&l, spec.Subscriptions)
+			if kind != "" {
+				s.fail(w, kind)
+				return
+			}
+			for _, sub := range subs {
+				s.publishItem(&l, sub, item, spec.Force)
+			}
+			s.ok(w)
+		default:
+			http.NotFound(w, r)
+		}
 	case http.MethodGet:
+		if l.SecurityPolicyID == "" {
+			item.SecurityCompliance = nil
+			item.CertificateVerification = nil
+		} else if item.CertificateVerification == nil {
+			item.verify()
+		}
 		s.ok(w, item)
 	}
 }
 
+// securityPolicy is the only security policy supported by vcsim.
+var securityPolicy = library.SecurityPolicy{
+	Policy:        "a1f1e4a6-6c3b-4bfa-8f2e-7e7e4a0b0c01",
+	Name:          "OVF default policy",
+	ItemTypeRules: map[string]string{"ovf": "OVF_STRICT_VERIFICATION"},
+}
+
+func (s *handler) securityPolicies(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	_ = json.NewEncoder(w).Encode([]library.SecurityPolicy{securityPolicy})
+}
+
+// verify simulates signing certificate verification of an item in a library with a security policy.
+// An OVF item is considered signed if it includes a .cert file, other item types are not verified.
+func (i *item) verify() {
+	status := library.CertificateNotAvailable
+	if i.Type == "ovf" {
+		status = library.CertificateVerificationFailure
+		for _, f := range i.File {
+			if path.Ext(f.Name) == ".cert" {
+				status = library.CertificateVerified
+			}
+		}
+	}
+	i.CertificateVerification = &library.CertificateVerification{Status: status}
+	i.SecurityCompliance = types.NewBool(status == library.CertificateVerified)
+}
+
 func (s *handler) libraryItemUpdateSession(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
 	case http.MethodGet:
@@ -823,6 +1756,7 @@ func (s *handler) libraryItemUpdateSession(w http.ResponseWriter, r *http.Reques
 				ClientProgress:            0,
 				State:                     "ACTIVE",
 				ExpirationTime:            types.NewTime(time.Now().Add(time.Hour)),
+				WarningBehavior:           spec.Session.WarningBehavior,
 			}
 			s.Update[session.ID] = update{
 				Session: session,
@@ -857,10 +1791,23 @@ func (s *handler) libraryItemUpdateSessionID(w http.ResponseWriter, r *http.Requ
 	case http.MethodGet:
 		s.ok(w, session)
 	case http.MethodPost:
+		if session.State != "ACTIVE" {
+			s.fail(w, "com.vmware.vapi.std.errors.not_allowed_in_current_state")
+			return
+		}
 		switch s.action(r) {
 		case "cancel":
+			s.rollback(up)
 			done("CANCELED")
 		case "complete":
+			for _, info := range up.File {
+				if info.SourceType == "PUSH" && info.Status != "READY" {
+					log.Printf("update session %s: file %s is %s", id, info.Name, info.Status)
+					s.fail(w, "com.vmware.vapi.std.errors.not_allowed_in_current_state")
+					return
+				}
+			}
+			s.commit(up)
 			done("DONE")
 		case "fail":
 			done("ERROR")
@@ -868,12 +1815,168 @@ func (s *handler) libraryItemUpdateSessionID(w http.ResponseWriter, r *http.Requ
 			session.ExpirationTime = types.NewTime(time.Now().Add(time.Hour))
 		}
 		s.ok(w)
+	case http.MethodPatch:
+		var spec struct {
+			Session library.Session `json:"update_spec"`
+		}
+		if !s.decode(r, w, &spec) {
+			return
+		}
+		for _, b := range spec.Session.WarningBehavior {
+			session.WarningBehavior = setWarningBehavior(session.WarningBehavior, b)
+		}
+		if p := session.PreviewInfo; p != nil {
+			for i := range p.Warnings {
+				p.Warnings[i].Ignored = ignoreWarning(session.WarningBehavior, p.Warnings[i].Type)
+			}
+			if len(p.Pending()) == 0 {
+				// resume the transfer of any files blocked by the warnings
+				for _, info := range up.File {
+					if info.SourceType == "PULL" && info.Status == "WAITING_FOR_TRANSFER" {
+						go s.pullSource(up, info)
+					}
+				}
+			}
+		}
+		s.ok(w)
 	case http.MethodDelete:
 		delete(s.Update, id)
 		s.ok(w)
 	}
 }
 
+// commit updates the library item once the given update session is complete.
+func (s *handler) commit(up update) {
+	i, ok := s.Library[up.Library.ID].Item[up.Session.LibraryItemID]
+	if !ok {
+		return
+	}
+	version, _ := strconv.Atoi(i.ContentVersion)
+	i.ContentVersion = strconv.Itoa(version + 1)
+	now := time.Now()
+	i.LastModifiedTime = &now
+	i.Cached = true
+	i.Size = 0
+	for _, f := range i.File {
+		if f.Size != nil {
+			i.Size += *f.Size
+		}
+	}
+}
+
+// rollback removes the files transferred by the given update session from the library item.
+func (s *handler) rollback(up update) {
+	i, ok := s.Library[up.Library.ID].Item[up.Session.LibraryItemID]
+	if !ok {
+		return
+	}
+	added := make(map[string]bool)
+	for _, info := range up.File {
+		added[info.Name] = true
+	}
+	var files []library.File
+	for _, f := range i.File {
+		if !added[f.Name] {
+			files = append(files, f)
+			continue
+		}
+		p := path.Join(libraryPath(up.Library, i.ID), f.Name)
+		if err := os.Remove(p); err != nil {
+			log.Printf("rollback %s: %s", p, err)
+		}
+	}
+	i.File = files
+}
+
+func setWarningBehavior(behavior []library.WarningBehavior, b library.WarningBehavior) []library.WarningBehavior {
+	for i := range behavior {
+		if behavior[i].Type == b.Type {
+			behavior[i].Ignored = b.Ignored
+			return behavior
+		}
+	}
+	return append(behavior, b)
+}
+
+func ignoreWarning(behavior []library.WarningBehavior, kind string) bool {
+	for _, b := range behavior {
+		if b.Type == kind {
+			return b.Ignored
+		}
+	}
+	return false
+}
+
+// sourceCertificates returns the certificate chain of the given HTTPS source, nil for other schemes.
+func sourceCertificates(uri string) ([]*x509.Certificate, string, error) {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "https" {
+		return nil, "", err
+	}
+	host := u.Host
+	if u.Port() == "" {
+		host += ":443"
+	}
+	conn, err := tls.Dial("tcp", host, &tls.Config{InsecureSkipVerify: true})
+	if err != nil {
+		return nil, "", err
+	}
+	defer conn.Close()
+	return conn.ConnectionState().PeerCertificates, u.Hostname(), nil
+}
+
+// previewInfo validates the certificate of the given source, returning nil if the source is trusted.
+// A source is trusted if the certificate thumbprint was given or the certificate can be verified.
+func previewInfo(certs []*x509.Certificate, name, thumbprint string) *library.PreviewInfo {
+	if len(certs) == 0 {
+		return nil
+	}
+	cert := certs[0]
+	if thumbprint != "" && strings.EqualFold(soap.ThumbprintSHA1(cert), thumbprint) {
+		return nil
+	}
+
+	info := &library.CertificateInfo{
+		Issuer:     cert.Issuer.String(),
+		Subject:    cert.Subject.String(),
+		SelfSigned: bytes.Equal(cert.RawIssuer, cert.RawSubject) && cert.CheckSignatureFrom(cert) == nil,
+		X509:       string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})),
+	}
+
+	var warnings []library.PreviewWarning
+	warn := func(kind, msg string) {
+		warnings = append(warnings, library.PreviewWarning{
+			Type:    kind,
+			Message: rest.LocalizableMessage{DefaultMessage: msg},
+		})
+	}
+
+	now := time.Now()
+	if now.After(cert.NotAfter) {
+		warn(library.WarningExpiredCertificate, "The certificate has expired.")
+	}
+	if now.Before(cert.NotBefore) {
+		warn(library.WarningNotYetValidCertificate, "The certificate is not yet valid.")
+	}
+
+	pool := x509.NewCertPool()
+	for _, c := range certs[1:] {
+		pool.AddCert(c)
+	}
+	if _, err := cert.Verify(x509.VerifyOptions{DNSName: name, Intermediates: pool}); err != nil {
+		if info.SelfSigned {
+			warn(library.WarningSelfSignedCertificate, "The certificate is self-signed.")
+		} else {
+			warn(library.WarningUntrustedServer, "The server is not trusted: "+err.Error())
+		}
+	}
+
+	if len(warnings) == 0 {
+		return nil
+	}
+	return &library.PreviewInfo{State: "AVAILABLE", CertificateInfo: info, Warnings: warnings}
+}
+
 func (s *handler) libraryItemUpdateSessionFile(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		w.WriteHeader(http.StatusMethodNotAllowed)
@@ -908,6 +2011,27 @@ func (s *handler) pullSource(up update, info *library.UpdateFile) {
 		s.Unlock()
 	}
 
+	certs, name, err := sourceCertificates(info.SourceEndpoint.URI)
+	if err != nil {
+		done(err)
+		return
+	}
+
+	s.Lock()
+	preview := previewInfo(certs, name, info.SourceEndpoint.SSLCertificateThumbprint)
+	if preview != nil {
+		for i := range preview.Warnings {
+			preview.Warnings[i].Ignored = ignoreWarning(up.WarningBehavior, preview.Warnings[i].Type)
+		}
+		up.PreviewInfo = preview
+		if len(preview.Pending()) != 0 {
+			s.Unlock()
+			return // transfer resumes once the warnings are ignored
+		}
+	}
+	info.Status = "TRANSFERRING"
+	s.Unlock()
+
 	c := &http.Client{
 		Transport: &http.Transport{
 			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
@@ -938,6 +2062,14 @@ func (s *handler) libraryItemUpdateSessionFileID(w http.ResponseWriter, r *http.
 		return
 	}
 
+	switch s.action(r) {
+	case "add", "remove":
+		if up.State != "ACTIVE" {
+			s.fail(w, "com.vmware.vapi.std.errors.not_allowed_in_current_state")
+			return
+		}
+	}
+
 	switch s.action(r) {
 	case "add":
 		var spec struct {
@@ -963,20 +2095,45 @@ func (s *handler) libraryItemUpdateSessionFileID(w http.ResponseWriter, r *http.
 				info.SourceEndpoint = spec.File.SourceEndpoint
 				go s.pullSource(up, info)
 			}
+			for fid, f := range up.File {
+				if f.Name == info.Name {
+					delete(up.File, fid) // replaced
+				}
+			}
 			up.File[id] = info
 			s.ok(w, info)
 		}
 	case "get":
-		s.ok(w, up.Session)
+		var spec struct {
+			File string `json:"file_name"`
+		}
+		if s.decode(r, w, &spec) {
+			for _, info := range up.File {
+				if info.Name == spec.File {
+					s.ok(w, info)
+					return
+				}
+			}
+			http.NotFound(w, r)
+		}
 	case "list":
-		var ids []string
-		for id := range up.File {
-			ids = append(ids, id)
+		files := []*library.UpdateFile{}
+		for _, info := range up.File {
+			files = append(files, info)
 		}
-		s.ok(w, ids)
+		s.ok(w, files)
 	case "remove":
-		delete(s.Update, id)
-		s.ok(w)
+		var spec struct {
+			File string `json:"file_name"`
+		}
+		if s.decode(r, w, &spec) {
+			for fid, info := range up.File {
+				if info.Name == spec.File {
+					delete(up.File, fid)
+				}
+			}
+			s.ok(w)
+		}
 	case "validate":
 		// TODO
 	}
@@ -1048,13 +2205,30 @@ func (s *handler) libraryItemDownloadSessionID(w http.ResponseWriter, r *http.Re
 	}
 
 	session := up.Session
+	done := func(state string) {
+		session.State = state
+		go time.AfterFunc(session.ExpirationTime.Sub(time.Now()), func() {
+			s.Lock()
+			delete(s.Download, id)
+			s.Unlock()
+		})
+	}
+
 	switch r.Method {
 	case http.MethodGet:
 		s.ok(w, session)
 	case http.MethodPost:
+		if session.State != "ACTIVE" {
+			s.fail(w, "com.vmware.vapi.std.errors.not_allowed_in_current_state")
+			return
+		}
 		switch s.action(r) {
-		case "cancel", "complete", "fail":
-			delete(s.Download, id) // TODO: fully mock VC's behavior
+		case "cancel":
+			done("CANCELED")
+		case "complete":
+			delete(s.Download, id)
+		case "fail":
+			done("ERROR")
 		case "keep-alive":
 			session.ExpirationTime = types.NewTime(time.Now().Add(time.Hour))
 		}
@@ -1104,29 +2278,46 @@ func (s *handler) libraryItemDownloadSessionFileID(w http.ResponseWriter, r *htt
 		File string `json:"file_name"`
 	}
 
+	if !s.decode(r, w, &spec) {
+		return
+	}
+	info, ok := dl.File[spec.File]
+	if !ok {
+		s.fail(w, "com.vmware.vapi.std.errors.not_found")
+		return
+	}
+
 	switch s.action(r) {
 	case "prepare":
-		if s.decode(r, w, &spec) {
+		if dl.State != "ACTIVE" {
+			s.fail(w, "com.vmware.vapi.std.errors.not_allowed_in_current_state")
+			return
+		}
+		// The file is PREPARED once its status is next requested
+		info.Status = "PREPARING"
+		info.BytesTransferred = 0
+		if fi, err := os.Stat(path.Join(libraryPath(dl.Library, dl.Session.LibraryItemID), spec.File)); err == nil {
+			info.Size = fi.Size()
+		}
+		for _, f := range s.Library[dl.Library.ID].Item[dl.Session.LibraryItemID].File {
+			if f.Name == spec.File {
+				info.Checksum = f.Checksum
+			}
+		}
+		s.ok(w, info)
+	case "get":
+		if info.Status == "PREPARING" {
 			u := url.URL{
 				Scheme: s.URL.Scheme,
 				Host:   s.URL.Host,
 				Path:   path.Join(internal.Path, internal.LibraryItemFileData, id, spec.File),
 			}
-			info := &library.DownloadFile{
-				Name:             spec.File,
-				Status:           "PREPARED",
-				BytesTransferred: 0,
-				DownloadEndpoint: &library.TransferEndpoint{
-					URI: u.String(),
-				},
-			}
-			dl.File[spec.File] = info
-			s.ok(w, info)
-		}
-	case "get":
-		if s.decode(r, w, &spec) {
-			s.ok(w, dl.File[spec.File])
+			info.Status = "PREPARED"
+			info.DownloadEndpoint = &library.TransferEndpoint{URI: u.String()}
 		}
+		s.ok(w, info)
+	default:
+		http.NotFound(w, r)
 	}
 }
 
@@ -1199,15 +2390,28 @@ func (s *handler) libraryItemFileCreate(up *update, name string, body io.ReadClo
 
 	i := s.Library[up.Library.ID].Item[up.Session.LibraryItemID]
 	i.File = append(i.File, library.File{
-		Cached:  types.NewBool(true),
-		Name:    name,
-		Size:    types.NewInt64(n),
-		Version: "1",
+		Cached:   types.NewBool(true),
+		Checksum: fileChecksum(file.Name()),
+		Name:     name,
+		Size:     types.NewInt64(n),
+		Version:  "1",
 	})
 
 	return nil
 }
 
+// countWriter counts the bytes written to the http.ResponseWriter body.
+type countWriter struct {
+	http.ResponseWriter
+	n int64
+}
+
+func (w *countWriter) Write(b []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(b)
+	w.n += int64(n)
+	return n, err
+}
+
 func (s *handler) libraryItemFileData(w http.ResponseWriter, r *http.Request) {
 	p := strings.Split(r.URL.Path, "/")
 	id, name := p[len(p)-2], p[len(p)-1]
@@ -1219,17 +2423,26 @@ func (s *handler) libraryItemFileData(w http.ResponseWriter, r *http.Request) {
 			http.NotFound(w, r)
 			return
 		}
+		info, ok := dl.File[name]
+		if !ok || info.Status != "PREPARED" || dl.State != "ACTIVE" {
+			log.Printf("library download %s: file %q is not prepared", id, name)
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
 		p := path.Join(libraryPath(dl.Library, dl.Session.LibraryItemID), name)
 		f, err := os.Open(p)
 		if err != nil {
 			s.error(w, err)
 			return
 		}
-		_, err = io.Copy(w, f)
-		if err != nil {
-			log.Printf("copy %s: %s", p, err)
-		}
+		// ServeContent supports the Range requests used to resume a download
+		cw := &countWriter{ResponseWriter: w}
+		http.ServeContent(cw, r, name, time.Time{}, f)
 		_ = f.Close()
+		info.BytesTransferred += cw.n
+		if info.BytesTransferred > info.Size {
+			info.BytesTransferred = info.Size
+		}
 		return
 	}
 
@@ -1244,10 +2457,97 @@ func (s *handler) libraryItemFileData(w http.ResponseWriter, r *http.Request) {
 		http.NotFound(w, r)
 		return
 	}
+	if up.State != "ACTIVE" {
+		log.Printf("library update %s is %s", up.ID, up.State)
+		w.WriteHeader(http.StatusConflict)
+		return
+	}
+
+	info := up.File[id]
+
+	if cr := r.Header.Get("Content-Range"); cr != "" {
+		var start, end, size int64
+		if _, err := fmt.Sscanf(cr, "bytes %d-%d/%d", &start, &end, &size); err != nil || start > info.BytesTransferred {
+			w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+			return
+		}
+		if err := s.libraryItemFileWrite(up, info, start, size, r.Body); err != nil {
+			s.error(w, err)
+		}
+		return
+	}
 
 	err := s.libraryItemFileCreate(up, name, r.Body)
 	if err != nil {
 		s.error(w, err)
+		return
+	}
+	info.Status = "READY"
+}
+
+// libraryItemFileWrite writes a range of an uploaded file, starting at the given offset.
+// The file is added to the library item once all bytes of the given size have been transferred.
+func (s *handler) libraryItemFileWrite(up *update, info *library.UpdateFile, offset, size int64, body io.ReadCloser) error {
+	defer body.Close()
+
+	dir := libraryPath(up.Library, up.Session.LibraryItemID)
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(path.Join(dir, info.Name), os.O_CREATE|os.O_WRONLY, 0640)
+	if err != nil {
+		return err
+	}
+
+	if _, err = file.Seek(offset, io.SeekStart); err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	n, err := io.Copy(file, body)
+	info.BytesTransferred = offset + n
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err = file.Close(); err != nil {
+		return err
+	}
+
+	if info.BytesTransferred == size {
+		info.Status = "READY"
+		i := s.Library[up.Library.ID].Item[up.Session.LibraryItemID]
+		i.File = append(i.File, library.File{
+			Cached:   types.NewBool(true),
+			Checksum: fileChecksum(file.Name()),
+			Name:     info.Name,
+			Size:     types.NewInt64(size),
+			Version:  "1",
+		})
+	}
+
+	return nil
+}
+
+// fileChecksum returns the SHA1 checksum of the given file, as vCenter computes for library item files.
+func fileChecksum(name string) *library.Checksum {
+	f, err := os.Open(name)
+	if err != nil {
+		log.Printf("checksum: %s", err)
+		return nil
+	}
+	defer f.Close()
+
+	h := sha1.New()
+	if _, err = io.Copy(h, f); err != nil {
+		log.Printf("checksum %s: %s", name, err)
+		return nil
+	}
+
+	return &library.Checksum{
+		Algorithm: library.ChecksumSHA1,
+		Checksum:  hex.EncodeToString(h.Sum(nil)),
 	}
 }
 
@@ -1287,6 +2587,64 @@ func (s *handler) libraryItemFileID(w http.ResponseWriter, r *http.Request) {
 	http.NotFound(w, r)
 }
 
+// storage returns the storage info of the given library item file.
+func (l content) storage(i *item, f library.File) library.Storage {
+	info := library.Storage{
+		Checksum:       f.Checksum,
+		Name:           f.Name,
+		StorageBacking: l.Storage[0],
+		StorageURIs:    []string{"ds://" + path.Join(libraryPath(l.Library, i.ID), f.Name)},
+		Version:        f.Version,
+	}
+	if f.Cached != nil {
+		info.Cached = *f.Cached
+	}
+	if f.Size != nil {
+		info.Size = *f.Size
+	}
+	return info
+}
+
+func (s *handler) libraryItemStorage(w http.ResponseWriter, r *http.Request) {
+	id := r.URL.Query().Get("library_item_id")
+	for _, l := range s.Library {
+		if i, ok := l.Item[id]; ok {
+			res := []library.Storage{}
+			for _, f := range i.File {
+				res = append(res, l.storage(i, f))
+			}
+			s.ok(w, res)
+			return
+		}
+	}
+	http.NotFound(w, r)
+}
+
+func (s *handler) libraryItemStorageID(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	id := s.id(r)
+	var spec struct {
+		Name string `json:"file_name"`
+	}
+	if !s.decode(r, w, &spec) {
+		return
+	}
+	for _, l := range s.Library {
+		if i, ok := l.Item[id]; ok {
+			for _, f := range i.File {
+				if f.Name == spec.Name {
+					s.ok(w, []library.Storage{l.storage(i, f)})
+					return
+				}
+			}
+		}
+	}
+	http.NotFound(w, r)
+}
+
 func (i *item) ovf() string {
 	for _, f := range i.File {
 		if strings.HasSuffix(f.Name, ".ovf") {
@@ -1302,6 +2660,13 @@ func (s *handler) libraryDeploy(lib *library.Library, item *item, deploy vcenter
 	if err != nil {
 		return nil, err
 	}
+	filter, err := libraryFilter(lib, item)
+	if err != nil {
+		return nil, err
+	}
+	if len(filter.EULAs) != 0 && !deploy.DeploymentSpec.AcceptAllEULA {
+		return nil, errors.New("the EULAs of the OVF package must be accepted")
+	}
 	ds := types.ManagedObjectReference{Type: "Datastore", Value: deploy.DeploymentSpec.DefaultDatastoreID}
 	pool := types.ManagedObjectReference{Type: "ResourcePool", Value: deploy.Target.ResourcePoolID}
 	var folder, host *types.ManagedObjectReference
@@ -1402,6 +2767,87 @@ func (s *handler) libraryDeploy(lib *library.Library, item *item, deploy vcenter
 	return info, lease.Complete(ctx)
 }
 
+// libraryEnvelope reads the OVF descriptor of the given library item.
+func libraryEnvelope(lib *library.Library, item *item) (*ovf.Envelope, error) {
+	f, err := os.Open(filepath.Join(libraryPath(lib, item.ID), item.ovf()))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ovf.Unmarshal(f)
+}
+
+// libraryFilter returns the deployment requirements of the given library item's OVF descriptor.
+func libraryFilter(lib *library.Library, item *item) (*vcenter.FilterResponse, error) {
+	env, err := libraryEnvelope(lib, item)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &vcenter.FilterResponse{Name: item.Name}
+
+	if env.Annotation != nil {
+		res.Annotation = env.Annotation.Annotation
+	}
+	if env.Eula != nil {
+		res.EULAs = append(res.EULAs, env.Eula.License)
+	}
+	if env.Network != nil {
+		for _, net := range env.Network.Networks {
+			res.Networks = append(res.Networks, net.Name)
+		}
+	}
+
+	products := []ovf.ProductSection{}
+	if env.Product != nil {
+		products = append(products, *env.Product)
+	}
+	if vs := env.VirtualSystem; vs != nil {
+		products = append(products, vs.Product...)
+		for _, eula := range vs.Eula {
+			res.EULAs = append(res.EULAs, eula.License)
+		}
+		if res.Annotation == "" && len(vs.Annotation) != 0 {
+			res.Annotation = vs.Annotation[0].Annotation
+		}
+	}
+
+	props := vcenter.AdditionalParams{Class: vcenter.ClassOvfParams, Type: vcenter.TypePropertyParams}
+	for _, product := range products {
+		for _, p := range product.Property {
+			prop := vcenter.Property{ID: p.Key, Type: p.Type}
+			if p.Default != nil {
+				prop.Value = *p.Default
+			}
+			if p.Label != nil {
+				prop.Label = *p.Label
+			}
+			if p.Description != nil {
+				prop.Description = *p.Description
+			}
+			props.Properties = append(props.Properties, prop)
+		}
+	}
+	if len(props.Properties) != 0 {
+		res.AdditionalParams = append(res.AdditionalParams, props)
+	}
+
+	if env.DeploymentOption != nil {
+		opts := vcenter.AdditionalParams{Class: vcenter.ClassOvfParams, Type: vcenter.TypeDeploymentOptionParams}
+		for _, c := range env.DeploymentOption.Configuration {
+			opt := vcenter.DeploymentOption{Key: c.ID, Label: c.Label, Description: c.Description}
+			if c.Default != nil && *c.Default {
+				opt.DefaultChoice = true
+				opts.SelectedKey = c.ID
+			}
+			opts.DeploymentOptions = append(opts.DeploymentOptions, opt)
+		}
+		res.AdditionalParams = append(res.AdditionalParams, opts)
+	}
+
+	return res, nil
+}
+
 func (s *handler) libraryItemDeployID(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		w.WriteHeader(http.StatusMethodNotAllowed)
@@ -1457,11 +2903,152 @@ func (s *handler) libraryItemDeployID(w http.ResponseWriter, r *http.Request) {
 		}
 		s.ok(w, d)
 	case "filter":
-		res := vcenter.FilterResponse{
-			Name: item.Name,
+		res, err := libraryFilter(lib, item)
+		if err != nil {
+			s.error(w, err)
+			return
 		}
 		s.ok(w, res)
 	default:
 		http.NotFound(w, r)
 	}
 }
+
+func (s *handler) isoImageID(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	var spec struct {
+		VM    string `json:"vm"`
+		CDROM string `json:"cdrom"`
+	}
+	if !s.decode(r, w, &spec) {
+		return
+	}
+
+	id := s.id(r)
+	switch s.action(r) {
+	case "mount":
+		lib := s.itemLibrary(id)
+		if lib == nil {
+			log.Printf("library item not found: %q", id)
+			http.NotFound(w, r)
+			return
+		}
+		item := s.Library[lib.ID].Item[id]
+		key, err := s.isoMount(lib, item, vim.ManagedObjectReference{Type: "VirtualMachine", Value: spec.VM})
+		if err != nil {
+			s.fail(w, "com.vmware.vapi.std.errors.invalid_argument")
+			log.Printf("mount %s: %s", id, err)
+			return
+		}
+		s.ok(w, key)
+	case "unmount":
+		err := s.isoUnmount(vim.ManagedObjectReference{Type: "VirtualMachine", Value: id}, spec.CDROM)
+		if err != nil {
+			s.fail(w, "com.vmware.vapi.std.errors.invalid_argument")
+			log.Printf("unmount %s: %s", id, err)
+			return
+		}
+		s.ok(w)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// isoPath returns the datastore path of the given item's .iso file.
+func isoPath(ctx context.Context, c *vim25.Client, lib *library.Library, item *item) (string, error) {
+	for _, f := range item.File {
+		if path.Ext(f.Name) != ".iso" {
+			continue
+		}
+		ds := object.NewDatastore(c, vim.ManagedObjectReference{Type: "Datastore", Value: lib.Storage[0].DatastoreID})
+		name, err := ds.ObjectName(ctx)
+		if err != nil {
+			return "", err
+		}
+		p := object.DatastorePath{Datastore: name, Path: path.Join("contentlib-"+lib.ID, item.ID, f.Name)}
+		return p.String(), nil
+	}
+	return "", fmt.Errorf("library item %s has no .iso file", item.ID)
+}
+
+// isoMount adds a CD-ROM device to the given VM, backed by the library item's ISO image, returning the device key.
+func (s *handler) isoMount(lib *library.Library, item *item, ref vim.ManagedObjectReference) (string, error) {
+	ctx := context.Background()
+	c, err := govmomi.NewClient(ctx, &s.URL, true)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = c.Logout(ctx)
+	}()
+
+	iso, err := isoPath(ctx, c.Client, lib, item)
+	if err != nil {
+		return "", err
+	}
+
+	vm := object.NewVirtualMachine(c.Client, ref)
+	devices, err := vm.Device(ctx)
+	if err != nil {
+		return "", err
+	}
+	ide, err := devices.FindIDEController("")
+	if err != nil {
+		return "", err
+	}
+	cdrom, err := devices.CreateCdrom(ide)
+	if err != nil {
+		return "", err
+	}
+	cdrom.Connectable = &vim.VirtualDeviceConnectInfo{Connected: true, StartConnected: true}
+	if err = vm.AddDevice(ctx, devices.InsertIso(cdrom, iso)); err != nil {
+		return "", err
+	}
+
+	devices, err = vm.Device(ctx)
+	if err != nil {
+		return "", err
+	}
+	for _, d := range devices.SelectByBackingInfo(&vim.VirtualCdromIsoBackingInfo{
+		VirtualDeviceFileBackingInfo: vim.VirtualDeviceFileBackingInfo{FileName: iso},
+	}) {
+		return strconv.Itoa(int(d.GetVirtualDevice().Key)), nil
+	}
+	return "", fmt.Errorf("cdrom backed by %s not found", iso)
+}
+
+// isoUnmount removes a CD-ROM device added by isoMount.
+func (s *handler) isoUnmount(ref vim.ManagedObjectReference, cdrom string) error {
+	key, err := strconv.Atoi(cdrom)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	c, err := govmomi.NewClient(ctx, &s.URL, true)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = c.Logout(ctx)
+	}()
+
+	vm := object.NewVirtualMachine(c.Client, ref)
+	devices, err := vm.Device(ctx)
+	if err != nil {
+		return err
+	}
+	device := devices.FindByKey(int32(key))
+	if device == nil {
+		return fmt.Errorf("device %d not found", key)
+	}
+	backing, ok := device.GetVirtualDevice().Backing.(*vim.VirtualCdromIsoBackingInfo)
+	if !ok || !strings.Contains(backing.FileName, "contentlib-") {
+		return fmt.Errorf("device %d is not backed by a library ISO image", key)
+	}
+	return vm.RemoveDevice(ctx, false, device)
+}
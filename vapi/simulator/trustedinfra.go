@@ -0,0 +1,328 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/vmware/govmomi/vapi/internal"
+	"github.com/vmware/govmomi/vapi/vcenter/trustedinfra"
+)
+
+// trustAuthorityCluster is the Trust Authority configuration of a cluster
+type trustAuthorityCluster struct {
+	State          string
+	EndorsementKey map[string]*trustedinfra.EndorsementKey
+	KMSProvider    map[string]*trustedinfra.KMSProvider
+}
+
+// trustedInfra holds the Trust Authority clusters, keyed by cluster ID, and the registered services, keyed by service ID
+type trustedInfra struct {
+	Cluster     map[string]*trustAuthorityCluster
+	Attestation map[string]*trustedinfra.ServiceSpec
+	KMS         map[string]*trustedinfra.ServiceSpec
+}
+
+func newTrustedInfra() *trustedInfra {
+	return &trustedInfra{
+		Cluster:     make(map[string]*trustAuthorityCluster),
+		Attestation: make(map[string]*trustedinfra.ServiceSpec),
+		KMS:         make(map[string]*trustedinfra.ServiceSpec),
+	}
+}
+
+// trustAuthorityCluster returns the Trust Authority configuration of the given inventory cluster,
+// which is disabled until its state is set.
+func (s *handler) trustAuthorityCluster(ctx context.Context, id string) (*trustAuthorityCluster, error) {
+	names, err := s.computeClusters(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := names[id]; !ok {
+		return nil, nil
+	}
+	cluster, ok := s.Trust.Cluster[id]
+	if !ok {
+		cluster = &trustAuthorityCluster{
+			State:          trustedinfra.StateDisable,
+			EndorsementKey: make(map[string]*trustedinfra.EndorsementKey),
+			KMSProvider:    make(map[string]*trustedinfra.KMSProvider),
+		}
+		s.Trust.Cluster[id] = cluster
+	}
+	return cluster, nil
+}
+
+func (s *handler) trustAuthorityClusters(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		names, err := s.computeClusters(r.Context())
+		if err != nil {
+			s.apiFail(w, http.StatusInternalServerError, "ERROR")
+			return
+		}
+		res := []trustedinfra.ClusterSummary{}
+		for id := range names {
+			cluster, _ := s.trustAuthorityCluster(r.Context(), id)
+			res = append(res, trustedinfra.ClusterSummary{Cluster: id, State: cluster.State})
+		}
+		sort.Slice(res, func(i, j int) bool { return res[i].Cluster < res[j].Cluster })
+		s.apiOK(w, res)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *handler) trustAuthorityClustersID(w http.ResponseWriter, r *http.Request) {
+	p := strings.Split(strings.TrimPrefix(r.URL.Path, internal.TrustedInfrastructurePath+"/trust-authority-clusters/"), "/")
+	cluster, err := s.trustAuthorityCluster(r.Context(), p[0])
+	if err != nil {
+		s.apiFail(w, http.StatusInternalServerError, "ERROR")
+		return
+	}
+	if cluster == nil {
+		s.apiFail(w, http.StatusNotFound, "NOT_FOUND")
+		return
+	}
+
+	switch {
+	case len(p) == 1:
+		s.trustAuthorityClusterState(w, r, p[0], cluster)
+	case len(p) >= 4 && strings.Join(p[1:4], "/") == "attestation/tpm2/endorsement-keys":
+		s.endorsementKeys(w, r, cluster, p[4:])
+	case len(p) >= 3 && strings.Join(p[1:3], "/") == "kms/providers":
+		s.kmsProviders(w, r, cluster, p[3:])
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *handler) trustAuthorityClusterState(w http.ResponseWriter, r *http.Request, id string, cluster *trustAuthorityCluster) {
+	switch r.Method {
+	case http.MethodGet:
+		s.apiOK(w, trustedinfra.ClusterSummary{Cluster: id, State: cluster.State})
+	case http.MethodPatch:
+		var spec struct {
+			State string `json:"state"`
+		}
+		if !s.decode(r, w, &spec) {
+			return
+		}
+		switch spec.State {
+		case trustedinfra.StateEnable, trustedinfra.StateDisable:
+			cluster.State = spec.State
+			s.apiOK(w)
+		default:
+			s.apiFail(w, http.StatusBadRequest, "INVALID_ARGUMENT")
+		}
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// validPEM returns true if the given value is a PEM encoded block
+func validPEM(val string) bool {
+	block, _ := pem.Decode([]byte(val))
+	return block != nil
+}
+
+func (s *handler) endorsementKeys(w http.ResponseWriter, r *http.Request, cluster *trustAuthorityCluster, p []string) {
+	if len(p) == 1 {
+		key, ok := cluster.EndorsementKey[p[0]]
+		if !ok {
+			s.apiFail(w, http.StatusNotFound, "NOT_FOUND")
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			s.apiOK(w, key)
+		case http.MethodDelete:
+			delete(cluster.EndorsementKey, p[0])
+			s.apiOK(w)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		type summary struct {
+			Name string `json:"name"`
+		}
+		res := []summary{}
+		for name := range cluster.EndorsementKey {
+			res = append(res, summary{name})
+		}
+		sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+		s.apiOK(w, res)
+	case http.MethodPost:
+		var key trustedinfra.EndorsementKey
+		if !s.decode(r, w, &key) {
+			return
+		}
+		if cluster.State != trustedinfra.StateEnable {
+			s.apiFail(w, http.StatusBadRequest, "NOT_ALLOWED_IN_CURRENT_STATE")
+			return
+		}
+		// exactly one of the public key or certificate is required
+		if key.Name == "" || (key.PublicKey == "") == (key.Certificate == "") || !validPEM(key.PublicKey+key.Certificate) {
+			s.apiFail(w, http.StatusBadRequest, "INVALID_ARGUMENT")
+			return
+		}
+		if _, ok := cluster.EndorsementKey[key.Name]; ok {
+			s.apiFail(w, http.StatusBadRequest, "ALREADY_EXISTS")
+			return
+		}
+		cluster.EndorsementKey[key.Name] = &key
+		s.apiOK(w)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *handler) kmsProviders(w http.ResponseWriter, r *http.Request, cluster *trustAuthorityCluster, p []string) {
+	if len(p) == 1 {
+		provider, ok := cluster.KMSProvider[p[0]]
+		if !ok {
+			s.apiFail(w, http.StatusNotFound, "NOT_FOUND")
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			s.apiOK(w, provider)
+		case http.MethodDelete:
+			delete(cluster.KMSProvider, p[0])
+			s.apiOK(w)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		type summary struct {
+			Provider string `json:"provider"`
+		}
+		res := []summary{}
+		for name := range cluster.KMSProvider {
+			res = append(res, summary{name})
+		}
+		sort.Slice(res, func(i, j int) bool { return res[i].Provider < res[j].Provider })
+		s.apiOK(w, res)
+	case http.MethodPost:
+		var provider trustedinfra.KMSProvider
+		if !s.decode(r, w, &provider) {
+			return
+		}
+		if cluster.State != trustedinfra.StateEnable {
+			s.apiFail(w, http.StatusBadRequest, "NOT_ALLOWED_IN_CURRENT_STATE")
+			return
+		}
+		ks := provider.KeyServer
+		if provider.Provider == "" || provider.MasterKeyID == "" ||
+			ks.Type != trustedinfra.KeyServerTypeKMIP || ks.KMIPServer == nil || len(ks.KMIPServer.Servers) == 0 {
+			s.apiFail(w, http.StatusBadRequest, "INVALID_ARGUMENT")
+			return
+		}
+		if _, ok := cluster.KMSProvider[provider.Provider]; ok {
+			s.apiFail(w, http.StatusBadRequest, "ALREADY_EXISTS")
+			return
+		}
+		cluster.KMSProvider[provider.Provider] = &provider
+		s.apiOK(w)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *handler) attestationServices(w http.ResponseWriter, r *http.Request) {
+	s.trustedServices(w, r, internal.TrustedInfrastructurePath+"/attestation/services", s.Trust.Attestation)
+}
+
+func (s *handler) kmsServices(w http.ResponseWriter, r *http.Request) {
+	s.trustedServices(w, r, internal.TrustedInfrastructurePath+"/kms/services", s.Trust.KMS)
+}
+
+// trustedServices handles the registration of Attestation and Key Provider services, which share the same API
+func (s *handler) trustedServices(w http.ResponseWriter, r *http.Request, path string, services map[string]*trustedinfra.ServiceSpec) {
+	if id := strings.TrimPrefix(r.URL.Path, path+"/"); id != r.URL.Path {
+		spec, ok := services[id]
+		if !ok {
+			s.apiFail(w, http.StatusNotFound, "NOT_FOUND")
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			s.apiOK(w, spec)
+		case http.MethodDelete:
+			delete(services, id)
+			s.apiOK(w)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		res := []trustedinfra.ServiceSummary{}
+		for id, spec := range services {
+			res = append(res, trustedinfra.ServiceSummary{
+				Service:               id,
+				Address:               spec.Address,
+				Group:                 spec.Group,
+				TrustAuthorityCluster: spec.TrustAuthorityCluster,
+			})
+		}
+		sort.Slice(res, func(i, j int) bool { return res[i].Service < res[j].Service })
+		s.apiOK(w, res)
+	case http.MethodPost:
+		var spec trustedinfra.ServiceSpec
+		if !s.decode(r, w, &spec) {
+			return
+		}
+		if spec.Address.Hostname == "" || len(spec.TrustedCA.CertChain) == 0 {
+			s.apiFail(w, http.StatusBadRequest, "INVALID_ARGUMENT")
+			return
+		}
+		for _, cert := range spec.TrustedCA.CertChain {
+			if !validPEM(cert) {
+				s.apiFail(w, http.StatusBadRequest, "INVALID_ARGUMENT")
+				return
+			}
+		}
+		for _, svc := range services {
+			if svc.Address == spec.Address {
+				s.apiFail(w, http.StatusBadRequest, "ALREADY_EXISTS")
+				return
+			}
+		}
+		id := uuid.New().String()
+		services[id] = &spec
+		s.apiOK(w, id)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
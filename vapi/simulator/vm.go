@@ -0,0 +1,777 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/internal"
+	vcvm "github.com/vmware/govmomi/vapi/vcenter/vm"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// vcenterVMProps are the VirtualMachine properties used by the /api/vcenter/vm handlers
+var vcenterVMProps = []string{"name", "parent", "resourcePool", "runtime", "config", "datastore"}
+
+// withClient calls f with a new vim25 session, which is logged out when f returns.
+func (s *handler) withClient(ctx context.Context, f func(*govmomi.Client) error) error {
+	c, err := govmomi.NewClient(ctx, &s.URL, true)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = c.Logout(ctx)
+	}()
+	return f(c)
+}
+
+// apiFault writes the /api error response for the given vim25 method or task error.
+func (s *handler) apiFault(w http.ResponseWriter, err error) {
+	var fault interface{}
+	var task interface{ Fault() types.BaseMethodFault }
+	switch {
+	case soap.IsSoapFault(err):
+		fault = soap.ToSoapFault(err).VimFault()
+	case soap.IsVimFault(err):
+		fault = soap.ToVimFault(err)
+	case errors.As(err, &task):
+		fault = task.Fault()
+	}
+
+	switch fault.(type) {
+	case *types.ManagedObjectNotFound, types.ManagedObjectNotFound:
+		s.apiFail(w, http.StatusNotFound, "NOT_FOUND")
+	case *types.InvalidPowerState, types.InvalidPowerState, *types.InvalidState, types.InvalidState:
+		s.apiFail(w, http.StatusBadRequest, "NOT_ALLOWED_IN_CURRENT_STATE")
+	case *types.DuplicateName, types.DuplicateName:
+		s.apiFail(w, http.StatusBadRequest, "ALREADY_EXISTS")
+	case nil:
+		s.apiFail(w, http.StatusInternalServerError, "ERROR")
+	default:
+		s.apiFail(w, http.StatusBadRequest, "INVALID_ARGUMENT")
+	}
+}
+
+// apiPowerState converts a vim25 power state to its /api name.
+func apiPowerState(state types.VirtualMachinePowerState) string {
+	switch state {
+	case types.VirtualMachinePowerStatePoweredOn:
+		return vcvm.PowerStateOn
+	case types.VirtualMachinePowerStateSuspended:
+		return vcvm.PowerStateSuspended
+	default:
+		return vcvm.PowerStateOff
+	}
+}
+
+// apiGuestOS converts a vim25 guest ID to its /api name, for example: "otherLinux64Guest" to "OTHER_LINUX_64".
+func apiGuestOS(id string) string {
+	var name []rune
+	prev := '_'
+	for _, c := range strings.TrimSuffix(id, "Guest") {
+		if prev != '_' && c != '_' && (unicode.IsUpper(c) || unicode.IsDigit(c) != unicode.IsDigit(prev)) {
+			name = append(name, '_')
+		}
+		name = append(name, unicode.ToUpper(c))
+		prev = c
+	}
+	return string(name)
+}
+
+// vimGuestID converts an /api guest OS name to its vim25 guest ID, returning an empty string if there is no match.
+func vimGuestID(name string) string {
+	for _, id := range simulator.GuestID {
+		if apiGuestOS(string(id)) == name {
+			return string(id)
+		}
+	}
+	return ""
+}
+
+// vcenterVMInfo converts the given VirtualMachine to its /api representation.
+func vcenterVMInfo(vm *mo.VirtualMachine) vcvm.Info {
+	hw := vm.Config.Hardware
+	info := vcvm.Info{
+		Name:       vm.Name,
+		GuestOS:    apiGuestOS(vm.Config.GuestId),
+		PowerState: apiPowerState(vm.Runtime.PowerState),
+		Identity: &vcvm.Identity{
+			Name:         vm.Name,
+			BiosUUID:     vm.Config.Uuid,
+			InstanceUUID: vm.Config.InstanceUuid,
+		},
+		Hardware: vcenterVMHardware(vm),
+		CPU: vcvm.CPU{
+			Count:            int(hw.NumCPU),
+			CoresPerSocket:   int(hw.NumCoresPerSocket),
+			HotAddEnabled:    isTrue(vm.Config.CpuHotAddEnabled),
+			HotRemoveEnabled: isTrue(vm.Config.CpuHotRemoveEnabled),
+		},
+		Memory: vcvm.Memory{
+			SizeMiB:       int64(hw.MemoryMB),
+			HotAddEnabled: isTrue(vm.Config.MemoryHotAddEnabled),
+		},
+		Disks: make(map[string]vcvm.DiskInfo),
+		Nics:  make(map[string]vcvm.EthernetInfo),
+	}
+
+	devices := object.VirtualDeviceList(hw.Device)
+	for _, device := range devices {
+		id := strconv.Itoa(int(device.GetVirtualDevice().Key))
+		switch device.(type) {
+		case *types.VirtualDisk:
+			info.Disks[id] = vcenterVMDisk(devices, device)
+		case types.BaseVirtualEthernetCard:
+			info.Nics[id] = vcenterVMEthernet(devices, device)
+		}
+	}
+
+	return info
+}
+
+func vcenterVMHardware(vm *mo.VirtualMachine) vcvm.Hardware {
+	hw := vcvm.Hardware{
+		Version:       strings.ToUpper(strings.Replace(vm.Config.Version, "-", "_", 1)),
+		UpgradePolicy: vcvm.UpgradePolicyNever,
+		UpgradeStatus: "NONE",
+	}
+	if up := vm.Config.ScheduledHardwareUpgradeInfo; up != nil {
+		switch up.UpgradePolicy {
+		case string(types.ScheduledHardwareUpgradeInfoHardwareUpgradePolicyOnSoftPowerOff):
+			hw.UpgradePolicy = vcvm.UpgradePolicyAfterCleanShutdown
+		case string(types.ScheduledHardwareUpgradeInfoHardwareUpgradePolicyAlways):
+			hw.UpgradePolicy = vcvm.UpgradePolicyAlways
+		}
+		hw.UpgradeVersion = strings.ToUpper(strings.Replace(up.VersionKey, "-", "_", 1))
+	}
+	return hw
+}
+
+func vcenterVMDisk(devices object.VirtualDeviceList, device types.BaseVirtualDevice) vcvm.DiskInfo {
+	disk := device.(*types.VirtualDisk)
+	info := vcvm.DiskInfo{
+		Label:    disk.DeviceInfo.GetDescription().Label,
+		Capacity: disk.CapacityInBytes,
+		Type:     vcvm.DiskTypeSCSI,
+	}
+	if info.Capacity == 0 {
+		info.Capacity = disk.CapacityInKB * 1024
+	}
+	switch devices.FindByKey(disk.ControllerKey).(type) {
+	case *types.VirtualIDEController:
+		info.Type = vcvm.DiskTypeIDE
+	case types.BaseVirtualSATAController:
+		info.Type = vcvm.DiskTypeSATA
+	case *types.VirtualNVMEController:
+		info.Type = vcvm.DiskTypeNVME
+	}
+	if b, ok := disk.Backing.(types.BaseVirtualDeviceFileBackingInfo); ok {
+		info.Backing = vcvm.DiskBacking{
+			Type:     vcvm.DiskBackingVMDKFile,
+			VMDKFile: b.GetVirtualDeviceFileBackingInfo().FileName,
+		}
+	}
+	return info
+}
+
+func vcenterVMEthernet(devices object.VirtualDeviceList, device types.BaseVirtualDevice) vcvm.EthernetInfo {
+	nic := device.(types.BaseVirtualEthernetCard).GetVirtualEthernetCard()
+	info := vcvm.EthernetInfo{
+		Label:      nic.DeviceInfo.GetDescription().Label,
+		Type:       strings.ToUpper(strings.TrimPrefix(devices.TypeName(device), "Virtual")),
+		MacAddress: nic.MacAddress,
+		State:      "NOT_CONNECTED",
+	}
+	if c := nic.Connectable; c != nil {
+		info.StartConnected = c.StartConnected
+		if c.Connected {
+			info.State = "CONNECTED"
+		}
+	}
+	switch b := nic.Backing.(type) {
+	case *types.VirtualEthernetCardNetworkBackingInfo:
+		info.Backing.Type = vcvm.EthernetBackingStandardPortgroup
+		if b.Network != nil {
+			info.Backing.Network = b.Network.Value
+		}
+	case *types.VirtualEthernetCardDistributedVirtualPortBackingInfo:
+		info.Backing.Type = vcvm.EthernetBackingDistributedPortgroup
+		info.Backing.Network = b.Port.PortgroupKey
+	case *types.VirtualEthernetCardOpaqueNetworkBackingInfo:
+		info.Backing.Type = vcvm.EthernetBackingOpaqueNetwork
+		info.Backing.Network = b.OpaqueNetworkId
+	}
+	return info
+}
+
+func isTrue(b *bool) bool {
+	return b != nil && *b
+}
+
+// contains returns true if values is empty or contains the given value.
+func contains(values []string, value string) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *handler) vcenterVM(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.vcenterVMList(w, r)
+	case http.MethodPost:
+		s.vcenterVMCreate(w, r)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *handler) vcenterVMList(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	q := r.URL.Query()
+	res := []vcvm.Summary{}
+
+	err := s.withClient(ctx, func(c *govmomi.Client) error {
+		var vms []mo.VirtualMachine
+		if err := retrieveInventory(ctx, c, q["datacenters"], []string{"VirtualMachine"}, vcenterVMProps, &vms); err != nil {
+			return err
+		}
+
+		// the cluster of each VM's host, used to filter by cluster
+		parent := make(map[types.ManagedObjectReference]string)
+		if len(q["clusters"]) != 0 {
+			var refs []types.ManagedObjectReference
+			for _, vm := range vms {
+				if vm.Runtime.Host != nil {
+					refs = append(refs, *vm.Runtime.Host)
+				}
+			}
+			var hosts []mo.HostSystem
+			if len(refs) != 0 {
+				if err := property.DefaultCollector(c.Client).Retrieve(ctx, refs, []string{"parent"}, &hosts); err != nil {
+					return err
+				}
+			}
+			for _, host := range hosts {
+				parent[host.Self] = host.Parent.Value
+			}
+		}
+
+		for _, vm := range vms {
+			if vm.Config == nil || vm.Config.Template {
+				continue
+			}
+			var host, pool, cluster string
+			if vm.Runtime.Host != nil {
+				host = vm.Runtime.Host.Value
+				cluster = parent[*vm.Runtime.Host]
+			}
+			if vm.ResourcePool != nil {
+				pool = vm.ResourcePool.Value
+			}
+			state := apiPowerState(vm.Runtime.PowerState)
+
+			match := contains(q["vms"], vm.Self.Value) && contains(q["names"], vm.Name) &&
+				contains(q["folders"], vm.Parent.Value) && contains(q["hosts"], host) &&
+				contains(q["clusters"], cluster) && contains(q["resource_pools"], pool) &&
+				contains(q["power_states"], state)
+			if !match {
+				continue
+			}
+
+			res = append(res, vcvm.Summary{
+				VM:         vm.Self.Value,
+				Name:       vm.Name,
+				PowerState: state,
+				CPUCount:   int(vm.Config.Hardware.NumCPU),
+				MemorySize: int64(vm.Config.Hardware.MemoryMB),
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		s.apiFault(w, err)
+		return
+	}
+
+	s.apiOK(w, res)
+}
+
+func (s *handler) vcenterVMCreate(w http.ResponseWriter, r *http.Request) {
+	var spec vcvm.CreateSpec
+	if !s.decode(r, w, &spec) {
+		return
+	}
+
+	p := spec.Placement
+	guest := vimGuestID(spec.GuestOS)
+	if spec.Name == "" || guest == "" || p.Folder == "" || p.Datastore == "" ||
+		(p.ResourcePool == "" && p.Host == "" && p.Cluster == "") {
+		s.apiFail(w, http.StatusBadRequest, "INVALID_ARGUMENT")
+		return
+	}
+
+	ctx := r.Context()
+	var id string
+
+	err := s.withClient(ctx, func(c *govmomi.Client) error {
+		pc := property.DefaultCollector(c.Client)
+
+		var host *object.HostSystem
+		pool := types.ManagedObjectReference{Type: "ResourcePool", Value: p.ResourcePool}
+		if p.Host != "" {
+			host = object.NewHostSystem(c.Client, types.ManagedObjectReference{Type: "HostSystem", Value: p.Host})
+		}
+		if p.ResourcePool == "" {
+			var ref types.ManagedObjectReference
+			if p.Cluster != "" {
+				ref = types.ManagedObjectReference{Type: "ClusterComputeResource", Value: p.Cluster}
+			} else {
+				var h mo.HostSystem
+				if err := pc.RetrieveOne(ctx, host.Reference(), []string{"parent"}, &h); err != nil {
+					return err
+				}
+				ref = *h.Parent
+			}
+			var cr mo.ComputeResource
+			if err := pc.RetrieveOne(ctx, ref, []string{"resourcePool"}, &cr); err != nil {
+				return err
+			}
+			pool = *cr.ResourcePool
+		}
+
+		var ds mo.Datastore
+		ref := types.ManagedObjectReference{Type: "Datastore", Value: p.Datastore}
+		if err := pc.RetrieveOne(ctx, ref, []string{"name"}, &ds); err != nil {
+			return err
+		}
+
+		scsi, err := object.VirtualDeviceList{}.CreateSCSIController("pvscsi")
+		if err != nil {
+			return err
+		}
+
+		config := types.VirtualMachineConfigSpec{
+			Name:    spec.Name,
+			GuestId: guest,
+			Files:   &types.VirtualMachineFileInfo{VmPathName: "[" + ds.Name + "]"},
+			DeviceChange: []types.BaseVirtualDeviceConfigSpec{
+				&types.VirtualDeviceConfigSpec{Operation: types.VirtualDeviceConfigSpecOperationAdd, Device: scsi},
+			},
+		}
+		if spec.HardwareVersion != "" {
+			config.Version = strings.ToLower(strings.Replace(spec.HardwareVersion, "_", "-", 1))
+		}
+		if spec.CPU != nil {
+			config.NumCPUs = int32(spec.CPU.Count)
+			config.NumCoresPerSocket = int32(spec.CPU.CoresPerSocket)
+			config.CpuHotAddEnabled = spec.CPU.HotAddEnabled
+			config.CpuHotRemoveEnabled = spec.CPU.HotRemoveEnabled
+		}
+		if spec.Memory != nil {
+			config.MemoryMB = spec.Memory.SizeMiB
+			config.MemoryHotAddEnabled = spec.Memory.HotAddEnabled
+		}
+
+		folder := object.NewFolder(c.Client, types.ManagedObjectReference{Type: "Folder", Value: p.Folder})
+		task, err := folder.CreateVM(ctx, config, object.NewResourcePool(c.Client, pool), host)
+		if err != nil {
+			return err
+		}
+		info, err := task.WaitForResult(ctx, nil)
+		if err != nil {
+			return err
+		}
+		id = info.Result.(types.ManagedObjectReference).Value
+		return nil
+	})
+	if err != nil {
+		s.apiFault(w, err)
+		return
+	}
+
+	s.apiOK(w, id)
+}
+
+// withVM calls f with the given VM and its properties, writing an /api error response if f returns an error.
+func (s *handler) withVM(w http.ResponseWriter, r *http.Request, id string, f func(context.Context, *object.VirtualMachine, *mo.VirtualMachine) error) {
+	ctx := r.Context()
+	err := s.withClient(ctx, func(c *govmomi.Client) error {
+		vm := object.NewVirtualMachine(c.Client, types.ManagedObjectReference{Type: "VirtualMachine", Value: id})
+		var props mo.VirtualMachine
+		if err := vm.Properties(ctx, vm.Reference(), vcenterVMProps, &props); err != nil {
+			return err
+		}
+		return f(ctx, vm, &props)
+	})
+	if err != nil {
+		s.apiFault(w, err)
+	}
+}
+
+// wait waits for the given task to complete, if the task was created.
+func wait(ctx context.Context, task *object.Task, err error) error {
+	if err != nil {
+		return err
+	}
+	return task.Wait(ctx)
+}
+
+func (s *handler) vcenterVMID(w http.ResponseWriter, r *http.Request) {
+	p := strings.Split(strings.TrimPrefix(r.URL.Path, internal.VCenterVMPath+"/"), "/")
+	id := p[0]
+
+	switch {
+	case len(p) == 1:
+		s.vcenterVMInstance(w, r, id)
+	case len(p) == 2 && p[1] == "power":
+		s.vcenterVMPower(w, r, id)
+	case p[1] == "hardware" && len(p) == 2:
+		s.vcenterVMHardware(w, r, id)
+	case p[1] == "hardware" && len(p) == 3 && p[2] == "cpu":
+		s.vcenterVMCPU(w, r, id)
+	case p[1] == "hardware" && len(p) == 3 && p[2] == "memory":
+		s.vcenterVMMemory(w, r, id)
+	case p[1] == "hardware" && p[2] == "disk":
+		s.vcenterVMDevice(w, r, id, p[3:], (*types.VirtualDisk)(nil))
+	case p[1] == "hardware" && p[2] == "ethernet":
+		s.vcenterVMDevice(w, r, id, p[3:], (*types.VirtualEthernetCard)(nil))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *handler) vcenterVMInstance(w http.ResponseWriter, r *http.Request, id string) {
+	switch r.Method {
+	case http.MethodGet:
+		s.withVM(w, r, id, func(ctx context.Context, vm *object.VirtualMachine, props *mo.VirtualMachine) error {
+			s.apiOK(w, vcenterVMInfo(props))
+			return nil
+		})
+	case http.MethodDelete:
+		s.withVM(w, r, id, func(ctx context.Context, vm *object.VirtualMachine, props *mo.VirtualMachine) error {
+			if props.Runtime.PowerState != types.VirtualMachinePowerStatePoweredOff {
+				s.apiFail(w, http.StatusBadRequest, "NOT_ALLOWED_IN_CURRENT_STATE")
+				return nil
+			}
+			task, err := vm.Destroy(ctx)
+			if err = wait(ctx, task, err); err != nil {
+				return err
+			}
+			s.apiOK(w)
+			return nil
+		})
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *handler) vcenterVMPower(w http.ResponseWriter, r *http.Request, id string) {
+	switch r.Method {
+	case http.MethodGet:
+		s.withVM(w, r, id, func(ctx context.Context, vm *object.VirtualMachine, props *mo.VirtualMachine) error {
+			s.apiOK(w, vcvm.Power{State: apiPowerState(props.Runtime.PowerState)})
+			return nil
+		})
+	case http.MethodPost:
+		action := r.URL.Query().Get("action")
+		s.withVM(w, r, id, func(ctx context.Context, vm *object.VirtualMachine, props *mo.VirtualMachine) error {
+			state := apiPowerState(props.Runtime.PowerState)
+			var task *object.Task
+			var err error
+			switch {
+			case action == "start" && state == vcvm.PowerStateOn,
+				action == "stop" && state == vcvm.PowerStateOff,
+				action == "suspend" && state == vcvm.PowerStateSuspended:
+				s.apiFail(w, http.StatusBadRequest, "ALREADY_IN_DESIRED_STATE")
+				return nil
+			case (action == "suspend" || action == "reset") && state != vcvm.PowerStateOn:
+				s.apiFail(w, http.StatusBadRequest, "NOT_ALLOWED_IN_CURRENT_STATE")
+				return nil
+			case action == "start":
+				task, err = vm.PowerOn(ctx)
+			case action == "stop":
+				task, err = vm.PowerOff(ctx)
+			case action == "suspend":
+				task, err = vm.Suspend(ctx)
+			case action == "reset":
+				task, err = vm.Reset(ctx)
+			default:
+				s.apiFail(w, http.StatusBadRequest, "INVALID_ARGUMENT")
+				return nil
+			}
+			if err = wait(ctx, task, err); err != nil {
+				return err
+			}
+			s.apiOK(w)
+			return nil
+		})
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *handler) vcenterVMHardware(w http.ResponseWriter, r *http.Request, id string) {
+	switch r.Method {
+	case http.MethodGet:
+		s.withVM(w, r, id, func(ctx context.Context, vm *object.VirtualMachine, props *mo.VirtualMachine) error {
+			s.apiOK(w, vcenterVMHardware(props))
+			return nil
+		})
+	case http.MethodPost:
+		if r.URL.Query().Get("action") != "upgrade" {
+			s.apiFail(w, http.StatusBadRequest, "INVALID_ARGUMENT")
+			return
+		}
+		var spec struct {
+			Version string `json:"version"`
+		}
+		if !s.decode(r, w, &spec) {
+			return
+		}
+		s.withVM(w, r, id, func(ctx context.Context, vm *object.VirtualMachine, props *mo.VirtualMachine) error {
+			version := strings.ToLower(strings.Replace(spec.Version, "_", "-", 1))
+			task, err := vm.UpgradeVM(ctx, version)
+			if err = wait(ctx, task, err); err != nil {
+				return err
+			}
+			s.apiOK(w)
+			return nil
+		})
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *handler) vcenterVMCPU(w http.ResponseWriter, r *http.Request, id string) {
+	switch r.Method {
+	case http.MethodGet:
+		s.withVM(w, r, id, func(ctx context.Context, vm *object.VirtualMachine, props *mo.VirtualMachine) error {
+			s.apiOK(w, vcenterVMInfo(props).CPU)
+			return nil
+		})
+	case http.MethodPatch:
+		var spec vcvm.CPUUpdate
+		if !s.decode(r, w, &spec) {
+			return
+		}
+		s.withVM(w, r, id, func(ctx context.Context, vm *object.VirtualMachine, props *mo.VirtualMachine) error {
+			task, err := vm.Reconfigure(ctx, types.VirtualMachineConfigSpec{
+				NumCPUs:             int32(spec.Count),
+				NumCoresPerSocket:   int32(spec.CoresPerSocket),
+				CpuHotAddEnabled:    spec.HotAddEnabled,
+				CpuHotRemoveEnabled: spec.HotRemoveEnabled,
+			})
+			if err = wait(ctx, task, err); err != nil {
+				return err
+			}
+			s.apiOK(w)
+			return nil
+		})
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *handler) vcenterVMMemory(w http.ResponseWriter, r *http.Request, id string) {
+	switch r.Method {
+	case http.MethodGet:
+		s.withVM(w, r, id, func(ctx context.Context, vm *object.VirtualMachine, props *mo.VirtualMachine) error {
+			s.apiOK(w, vcenterVMInfo(props).Memory)
+			return nil
+		})
+	case http.MethodPatch:
+		var spec vcvm.MemoryUpdate
+		if !s.decode(r, w, &spec) {
+			return
+		}
+		s.withVM(w, r, id, func(ctx context.Context, vm *object.VirtualMachine, props *mo.VirtualMachine) error {
+			task, err := vm.Reconfigure(ctx, types.VirtualMachineConfigSpec{
+				MemoryMB:            spec.SizeMiB,
+				MemoryHotAddEnabled: spec.HotAddEnabled,
+			})
+			if err = wait(ctx, task, err); err != nil {
+				return err
+			}
+			s.apiOK(w)
+			return nil
+		})
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// vcenterVMDevice handles the disk and ethernet hardware collections, where kind is the device type.
+func (s *handler) vcenterVMDevice(w http.ResponseWriter, r *http.Request, id string, p []string, kind types.BaseVirtualDevice) {
+	s.withVM(w, r, id, func(ctx context.Context, vm *object.VirtualMachine, props *mo.VirtualMachine) error {
+		devices := object.VirtualDeviceList(props.Config.Hardware.Device).SelectByType(kind)
+
+		info := func(device types.BaseVirtualDevice) interface{} {
+			if _, ok := device.(*types.VirtualDisk); ok {
+				return vcenterVMDisk(props.Config.Hardware.Device, device)
+			}
+			return vcenterVMEthernet(props.Config.Hardware.Device, device)
+		}
+
+		if len(p) == 1 {
+			key, _ := strconv.Atoi(p[0])
+			device := devices.FindByKey(int32(key))
+			if device == nil {
+				s.apiFail(w, http.StatusNotFound, "NOT_FOUND")
+				return nil
+			}
+			switch r.Method {
+			case http.MethodGet:
+				s.apiOK(w, info(device))
+			case http.MethodDelete:
+				if err := vm.RemoveDevice(ctx, true, device); err != nil {
+					return err
+				}
+				s.apiOK(w)
+			default:
+				w.WriteHeader(http.StatusMethodNotAllowed)
+			}
+			return nil
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			name := "nic"
+			if _, ok := kind.(*types.VirtualDisk); ok {
+				name = "disk"
+			}
+			res := []map[string]string{}
+			for _, device := range devices {
+				res = append(res, map[string]string{name: strconv.Itoa(int(device.GetVirtualDevice().Key))})
+			}
+			s.apiOK(w, res)
+		case http.MethodPost:
+			all := object.VirtualDeviceList(props.Config.Hardware.Device)
+			var device types.BaseVirtualDevice
+			var ok bool
+			if _, isDisk := kind.(*types.VirtualDisk); isDisk {
+				device, ok = s.vcenterVMNewDisk(w, r, all, props)
+			} else {
+				device, ok = s.vcenterVMNewEthernet(ctx, w, r, vm, all)
+			}
+			if !ok {
+				return nil
+			}
+			if err := vm.AddDevice(ctx, device); err != nil {
+				return err
+			}
+			// the new device ID is the key of the device that was not in the list before the change
+			if err := vm.Properties(ctx, vm.Reference(), []string{"config.hardware.device"}, props); err != nil {
+				return err
+			}
+			for _, d := range object.VirtualDeviceList(props.Config.Hardware.Device).SelectByType(kind) {
+				if all.FindByKey(d.GetVirtualDevice().Key) == nil {
+					s.apiOK(w, strconv.Itoa(int(d.GetVirtualDevice().Key)))
+					return nil
+				}
+			}
+			s.apiFail(w, http.StatusInternalServerError, "ERROR")
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+		return nil
+	})
+}
+
+func (s *handler) vcenterVMNewDisk(w http.ResponseWriter, r *http.Request, devices object.VirtualDeviceList, props *mo.VirtualMachine) (types.BaseVirtualDevice, bool) {
+	var spec vcvm.DiskCreateSpec
+	if !s.decode(r, w, &spec) {
+		return nil, false
+	}
+
+	controller, err := devices.FindDiskController(strings.ToLower(spec.Type))
+	if err != nil || (spec.NewVMDK == nil) == (spec.Backing == nil) {
+		s.apiFail(w, http.StatusBadRequest, "INVALID_ARGUMENT")
+		return nil, false
+	}
+
+	var path object.DatastorePath
+	path.FromString(props.Config.Files.VmPathName)
+	name := "[" + path.Datastore + "]"
+	if spec.Backing != nil {
+		name = spec.Backing.VMDKFile
+	} else if spec.NewVMDK.Name != "" {
+		name += " " + spec.NewVMDK.Name
+	}
+
+	disk := devices.CreateDisk(controller, props.Datastore[0], name)
+	if spec.NewVMDK != nil {
+		disk.CapacityInKB = spec.NewVMDK.Capacity / 1024
+	}
+	return disk, true
+}
+
+func (s *handler) vcenterVMNewEthernet(ctx context.Context, w http.ResponseWriter, r *http.Request, vm *object.VirtualMachine, devices object.VirtualDeviceList) (types.BaseVirtualDevice, bool) {
+	var spec vcvm.EthernetCreateSpec
+	if !s.decode(r, w, &spec) {
+		return nil, false
+	}
+
+	ref := types.ManagedObjectReference{Value: spec.Backing.Network}
+	switch spec.Backing.Type {
+	case vcvm.EthernetBackingStandardPortgroup:
+		ref.Type = "Network"
+	case vcvm.EthernetBackingDistributedPortgroup:
+		ref.Type = "DistributedVirtualPortgroup"
+	case vcvm.EthernetBackingOpaqueNetwork:
+		ref.Type = "OpaqueNetwork"
+	default:
+		s.apiFail(w, http.StatusBadRequest, "INVALID_ARGUMENT")
+		return nil, false
+	}
+
+	net, ok := object.NewReference(vm.Client(), ref).(object.NetworkReference)
+	if !ok {
+		s.apiFail(w, http.StatusBadRequest, "INVALID_ARGUMENT")
+		return nil, false
+	}
+	backing, err := net.EthernetCardBackingInfo(ctx)
+	if err != nil {
+		s.apiFault(w, err)
+		return nil, false
+	}
+
+	device, err := devices.CreateEthernetCard(strings.ToLower(spec.Type), backing)
+	if err != nil {
+		s.apiFail(w, http.StatusBadRequest, "INVALID_ARGUMENT")
+		return nil, false
+	}
+	device.GetVirtualDevice().Connectable = &types.VirtualDeviceConnectInfo{StartConnected: spec.StartConnected}
+	return device, true
+}
@@ -0,0 +1,267 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vapi/internal"
+	"github.com/vmware/govmomi/vapi/library"
+	"github.com/vmware/govmomi/vapi/vcenter"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// vmtx is the state of a VM template library item
+type vmtx struct {
+	Versions  []vcenter.TemplateVersion
+	CheckOuts []vcenter.CheckOutSummary
+}
+
+// template returns the ID of the item's current VM template
+func (t *vmtx) template() string {
+	return t.Versions[len(t.Versions)-1].VMTemplate
+}
+
+// vmtxClone clones the given VM as name, using the given placement or that of the source VM,
+// and converts the clone to a template if template is true.
+func vmtxClone(ctx context.Context, c *govmomi.Client, src string, name string, placement *vcenter.Placement, template bool, powerOn bool) (types.ManagedObjectReference, error) {
+	var ref types.ManagedObjectReference
+
+	var vm mo.VirtualMachine
+	source := object.NewVirtualMachine(c.Client, types.ManagedObjectReference{Type: "VirtualMachine", Value: src})
+	if err := source.Properties(ctx, source.Reference(), []string{"parent", "resourcePool"}, &vm); err != nil {
+		return ref, err
+	}
+
+	folder := object.NewFolder(c.Client, *vm.Parent)
+	spec := types.VirtualMachineCloneSpec{PowerOn: powerOn, Template: template}
+	if placement != nil {
+		if placement.Folder != "" {
+			folder = object.NewFolder(c.Client, types.ManagedObjectReference{Type: "Folder", Value: placement.Folder})
+		}
+		if placement.ResourcePool != "" {
+			spec.Location.Pool = &types.ManagedObjectReference{Type: "ResourcePool", Value: placement.ResourcePool}
+		}
+	}
+
+	task, err := source.Clone(ctx, folder, name, spec)
+	if err != nil {
+		return ref, err
+	}
+	res, err := task.WaitForResult(ctx, nil)
+	if err != nil {
+		return ref, err
+	}
+	ref = res.Result.(types.ManagedObjectReference)
+	clone := object.NewVirtualMachine(c.Client, ref)
+
+	if template {
+		return ref, clone.MarkAsTemplate(ctx)
+	}
+
+	if powerOn {
+		task, err = clone.PowerOn(ctx)
+		if err == nil {
+			err = task.Wait(ctx)
+		}
+	}
+
+	return ref, err
+}
+
+func (s *handler) vmtxLibraryItem(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	var spec struct {
+		vcenter.Template `json:"spec"`
+	}
+	if !s.decode(r, w, &spec) {
+		return
+	}
+
+	l, ok := s.Library[spec.Library]
+	if !ok {
+		log.Printf("library not found: %q", spec.Library)
+		http.NotFound(w, r)
+		return
+	}
+	for _, item := range l.Item {
+		if item.Name == spec.Name {
+			s.fail(w, "com.vmware.vapi.std.errors.already_exists")
+			return
+		}
+	}
+
+	var ref types.ManagedObjectReference
+	err := s.withClient(r.Context(), func(c *govmomi.Client) error {
+		var err error
+		ref, err = vmtxClone(r.Context(), c, spec.SourceVM, spec.Name, spec.Placement, true, false)
+		return err
+	})
+	if err != nil {
+		log.Printf("create template %s: %s", spec.Name, err)
+		s.fail(w, "com.vmware.vapi.std.errors.invalid_argument")
+		return
+	}
+
+	now := time.Now()
+	id := uuid.New().String()
+	l.Item[id] = &item{
+		Item: &library.Item{
+			ID:               id,
+			LibraryID:        l.ID,
+			Name:             spec.Name,
+			Description:      spec.Description,
+			Type:             "vm-template",
+			CreationTime:     &now,
+			LastModifiedTime: &now,
+		},
+	}
+	s.VMTX[id] = &vmtx{
+		Versions: []vcenter.TemplateVersion{{Version: "1", VMTemplate: ref.Value}},
+	}
+
+	s.ok(w, id)
+}
+
+func (s *handler) vmtxLibraryItemID(w http.ResponseWriter, r *http.Request) {
+	p := strings.Split(strings.TrimPrefix(r.URL.Path, internal.Path+internal.VCenterVMTXLibraryItem+"/"), "/")
+	id := p[0]
+	t, ok := s.VMTX[id]
+	l := s.itemLibrary(id)
+	if !ok || l == nil {
+		log.Printf("vm template library item not found: %q", id)
+		http.NotFound(w, r)
+		return
+	}
+	item := s.Library[l.ID].Item[id]
+	action := r.URL.Query().Get("action")
+	ctx := r.Context()
+
+	switch {
+	case len(p) == 1 && r.Method == http.MethodGet:
+		var vm mo.VirtualMachine
+		err := s.withClient(ctx, func(c *govmomi.Client) error {
+			ref := types.ManagedObjectReference{Type: "VirtualMachine", Value: t.template()}
+			return c.RetrieveOne(ctx, ref, []string{"config"}, &vm)
+		})
+		if err != nil {
+			s.error(w, err)
+			return
+		}
+		s.ok(w, vcenter.TemplateInfo{
+			GuestOS:    apiGuestOS(vm.Config.GuestId),
+			CPU:        &vcenter.CPU{Count: int(vm.Config.Hardware.NumCPU), CoresPerSocket: int(vm.Config.Hardware.NumCoresPerSocket)},
+			Memory:     &vcenter.Memory{SizeMiB: int(vm.Config.Hardware.MemoryMB)},
+			VMTemplate: t.template(),
+		})
+	case len(p) == 1 && r.Method == http.MethodPost && action == "deploy":
+		var spec struct {
+			vcenter.DeployTemplate `json:"spec"`
+		}
+		if !s.decode(r, w, &spec) {
+			return
+		}
+		var ref types.ManagedObjectReference
+		err := s.withClient(ctx, func(c *govmomi.Client) error {
+			var err error
+			ref, err = vmtxClone(ctx, c, t.template(), spec.Name, spec.Placement, false, spec.PoweredOn)
+			return err
+		})
+		if err != nil {
+			log.Printf("deploy %s: %s", id, err)
+			s.fail(w, "com.vmware.vapi.std.errors.invalid_argument")
+			return
+		}
+		s.ok(w, ref.Value)
+	case len(p) == 2 && p[1] == "check-outs" && r.Method == http.MethodGet:
+		s.ok(w, t.CheckOuts)
+	case len(p) == 2 && p[1] == "check-outs" && r.Method == http.MethodPost && action == "check-out":
+		var spec struct {
+			vcenter.CheckOut `json:"spec"`
+		}
+		if !s.decode(r, w, &spec) {
+			return
+		}
+		if spec.Name == "" {
+			spec.Name = item.Name
+		}
+		var ref types.ManagedObjectReference
+		err := s.withClient(ctx, func(c *govmomi.Client) error {
+			var err error
+			ref, err = vmtxClone(ctx, c, t.template(), spec.Name, spec.Placement, false, spec.PoweredOn)
+			return err
+		})
+		if err != nil {
+			log.Printf("check-out %s: %s", id, err)
+			s.fail(w, "com.vmware.vapi.std.errors.invalid_argument")
+			return
+		}
+		t.CheckOuts = append(t.CheckOuts, vcenter.CheckOutSummary{VM: ref.Value})
+		s.ok(w, ref.Value)
+	case len(p) == 3 && p[1] == "check-outs" && r.Method == http.MethodPost && action == "check-in":
+		var spec struct {
+			vcenter.CheckIn `json:"spec"`
+		}
+		if !s.decode(r, w, &spec) {
+			return
+		}
+		vm := p[2]
+		n := -1
+		for i := range t.CheckOuts {
+			if t.CheckOuts[i].VM == vm {
+				n = i
+			}
+		}
+		if n < 0 {
+			log.Printf("vm %q is not checked out of %q", vm, id)
+			http.NotFound(w, r)
+			return
+		}
+		err := s.withClient(ctx, func(c *govmomi.Client) error {
+			return object.NewVirtualMachine(c.Client, types.ManagedObjectReference{Type: "VirtualMachine", Value: vm}).MarkAsTemplate(ctx)
+		})
+		if err != nil {
+			log.Printf("check-in %s: %s", vm, err)
+			s.fail(w, "com.vmware.vapi.std.errors.not_allowed_in_current_state")
+			return
+		}
+		t.CheckOuts = append(t.CheckOuts[:n], t.CheckOuts[n+1:]...)
+		version := strconv.Itoa(len(t.Versions) + 1)
+		t.Versions = append(t.Versions, vcenter.TemplateVersion{Version: version, VMTemplate: vm})
+		now := time.Now()
+		item.LastModifiedTime = &now
+		s.ok(w, version)
+	case len(p) == 2 && p[1] == "versions" && r.Method == http.MethodGet:
+		s.ok(w, t.Versions)
+	default:
+		http.NotFound(w, r)
+	}
+}
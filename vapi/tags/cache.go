@@ -0,0 +1,213 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tags
+
+import (
+	"sync"
+	"time"
+)
+
+// EnableCache enables an in-memory cache of tags and categories, used by GetTag and GetCategory
+// and the methods that resolve a tag or category name to an ID.
+// Cached entries expire after the given ttl, where a ttl of 0 means entries do not expire.
+// Entries are invalidated by the Manager's update and delete methods, changes made by other clients
+// are not visible until the entry expires or InvalidateCache is called.
+// EnableCache is not safe to call while the Manager is in use by other goroutines.
+func (c *Manager) EnableCache(ttl time.Duration) {
+	c.cache = &cache{ttl: ttl}
+	c.cache.clear()
+}
+
+// InvalidateCache removes all cached tags and categories.
+func (c *Manager) InvalidateCache() {
+	if c.cache != nil {
+		c.cache.Lock()
+		c.cache.clear()
+		c.cache.Unlock()
+	}
+}
+
+type tagEntry struct {
+	tag     Tag
+	expires time.Time
+}
+
+type categoryEntry struct {
+	category Category
+	expires  time.Time
+}
+
+// cache methods are no-ops when the cache is not enabled.
+type cache struct {
+	sync.Mutex
+
+	ttl        time.Duration
+	tags       map[string]tagEntry
+	categories map[string]categoryEntry
+	tagName    map[tagKey]string // category ID + tag name -> ID
+	catName    map[string]string // category name -> ID
+}
+
+// tagKey identifies a tag by name, which is only unique within its category.
+type tagKey struct {
+	categoryID string
+	name       string
+}
+
+// clear removes all entries, the caller must hold the lock if the cache is in use.
+func (c *cache) clear() {
+	c.tags = make(map[string]tagEntry)
+	c.categories = make(map[string]categoryEntry)
+	c.tagName = make(map[tagKey]string)
+	c.catName = make(map[string]string)
+}
+
+func (c *cache) expires() time.Time {
+	if c.ttl == 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(c.ttl)
+}
+
+func expired(t time.Time) bool {
+	return !t.IsZero() && time.Now().After(t)
+}
+
+func (c *cache) tag(id string) (*Tag, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.Lock()
+	defer c.Unlock()
+
+	if isName(id) {
+		// Without a category, the name matches the first tag of any category
+		for _, e := range c.tags {
+			if e.tag.Name == id && !expired(e.expires) {
+				tag := e.tag
+				return &tag, true
+			}
+		}
+		return nil, false
+	}
+	e, ok := c.tags[id]
+	if !ok || expired(e.expires) {
+		return nil, false
+	}
+	tag := e.tag
+	return &tag, true
+}
+
+func (c *cache) findTag(name, categoryID string) (*Tag, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.Lock()
+	defer c.Unlock()
+
+	e, ok := c.tags[c.tagName[tagKey{categoryID, name}]]
+	if !ok || expired(e.expires) {
+		return nil, false
+	}
+	tag := e.tag
+	return &tag, true
+}
+
+func (c *cache) addTag(tag *Tag) {
+	if c == nil {
+		return
+	}
+	c.Lock()
+	defer c.Unlock()
+
+	if e, ok := c.tags[tag.ID]; ok {
+		c.removeTagName(e.tag)
+	}
+	c.tags[tag.ID] = tagEntry{*tag, c.expires()}
+	c.tagName[tagKey{tag.CategoryID, tag.Name}] = tag.ID
+}
+
+// removeTagName removes the name index entry of the given tag, if it still refers to the tag.
+func (c *cache) removeTagName(tag Tag) {
+	key := tagKey{tag.CategoryID, tag.Name}
+	if c.tagName[key] == tag.ID {
+		delete(c.tagName, key)
+	}
+}
+
+func (c *cache) removeTag(id string) {
+	if c == nil {
+		return
+	}
+	c.Lock()
+	defer c.Unlock()
+
+	if e, ok := c.tags[id]; ok {
+		c.removeTagName(e.tag)
+		delete(c.tags, id)
+	}
+}
+
+func (c *cache) category(id string) (*Category, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.Lock()
+	defer c.Unlock()
+
+	if isName(id) {
+		id = c.catName[id]
+	}
+	e, ok := c.categories[id]
+	if !ok || expired(e.expires) {
+		return nil, false
+	}
+	category := e.category
+	return &category, true
+}
+
+func (c *cache) addCategory(category *Category) {
+	if c == nil {
+		return
+	}
+	c.Lock()
+	defer c.Unlock()
+
+	c.categories[category.ID] = categoryEntry{*category, c.expires()}
+	c.catName[category.Name] = category.ID
+}
+
+func (c *cache) removeCategory(id string) {
+	if c == nil {
+		return
+	}
+	c.Lock()
+	defer c.Unlock()
+
+	if e, ok := c.categories[id]; ok {
+		delete(c.catName, e.category.Name)
+		delete(c.categories, id)
+	}
+
+	// Deleting a category also deletes its tags
+	for tid, e := range c.tags {
+		if e.tag.CategoryID == id {
+			c.removeTagName(e.tag)
+			delete(c.tags, tid)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tags_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vapi/tags"
+	"github.com/vmware/govmomi/vim25"
+)
+
+func TestCache(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		requests := 0
+		count := func(next rest.Handler) rest.Handler {
+			return func(ctx context.Context, req *http.Request, resBody interface{}) error {
+				requests++
+				return next(ctx, req, resBody)
+			}
+		}
+
+		c := rest.NewClient(vc, rest.WithMiddleware(count))
+		if err := c.Login(ctx, simulator.DefaultLogin); err != nil {
+			t.Fatal(err)
+		}
+		m := tags.NewManager(c)
+		ids := newTags(ctx, t, m, 1)
+		m.EnableCache(time.Hour)
+
+		lookup := func(id string) int {
+			requests = 0
+			tag, err := m.GetTag(ctx, id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tag.ID != ids[0] {
+				t.Errorf("tag=%#v", tag)
+			}
+			return requests
+		}
+
+		if n := lookup("test-tag-0"); n == 0 {
+			t.Error("expected requests")
+		}
+		if n := lookup("test-tag-0"); n != 0 {
+			t.Errorf("name lookup: %d requests", n)
+		}
+		if n := lookup(ids[0]); n != 0 {
+			t.Errorf("id lookup: %d requests", n)
+		}
+
+		tag, _ := m.GetTag(ctx, ids[0])
+		tag.Name = "renamed"
+		if err := m.UpdateTag(ctx, tag); err != nil {
+			t.Fatal(err)
+		}
+		if n := lookup("renamed"); n == 0 {
+			t.Error("expected requests after update")
+		}
+
+		m.InvalidateCache()
+		if n := lookup(ids[0]); n != 1 {
+			t.Errorf("invalidated: %d requests", n)
+		}
+
+		m.EnableCache(time.Nanosecond)
+		lookup(ids[0])
+		time.Sleep(time.Millisecond)
+		if n := lookup(ids[0]); n != 1 {
+			t.Errorf("expired: %d requests", n)
+		}
+	})
+}
+
+func TestFindTag(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		var requests int32
+		count := func(next rest.Handler) rest.Handler {
+			return func(ctx context.Context, req *http.Request, resBody interface{}) error {
+				atomic.AddInt32(&requests, 1)
+				return next(ctx, req, resBody)
+			}
+		}
+
+		c := rest.NewClient(vc, rest.WithMiddleware(count))
+		if err := c.Login(ctx, simulator.DefaultLogin); err != nil {
+			t.Fatal(err)
+		}
+		m := tags.NewManager(c)
+		ids := newTags(ctx, t, m, 20)
+
+		for _, cache := range []bool{false, true} {
+			if cache {
+				m.EnableCache(0)
+			}
+
+			for i := range ids {
+				name := fmt.Sprintf("test-tag-%d", i)
+				tag, err := m.FindTag(ctx, name, "test-category")
+				if err != nil {
+					t.Fatal(err)
+				}
+				if tag.ID != ids[i] || tag.Name != name {
+					t.Errorf("tag=%#v", tag)
+				}
+			}
+
+			if _, err := m.FindTag(ctx, "enoent", "test-category"); err == nil {
+				t.Error("expected error")
+			}
+		}
+
+		atomic.StoreInt32(&requests, 0)
+		for i := range ids {
+			if _, err := m.FindTag(ctx, fmt.Sprintf("test-tag-%d", i), "test-category"); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if requests := atomic.LoadInt32(&requests); requests != 0 {
+			t.Errorf("%d requests with cache", requests)
+		}
+	})
+}
+
+func TestCacheTagNamePerCategory(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		m := newManager(ctx, t, vc)
+		m.EnableCache(0)
+
+		var ids []string
+		for _, name := range []string{"cat-a", "cat-b"} {
+			category, err := m.CreateCategory(ctx, &tags.Category{Name: name, Cardinality: "MULTIPLE"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			id, err := m.CreateTag(ctx, &tags.Tag{Name: "dup", CategoryID: category})
+			if err != nil {
+				t.Fatal(err)
+			}
+			ids = append(ids, id)
+		}
+
+		for i, name := range []string{"cat-a", "cat-b"} {
+			tag, err := m.FindTag(ctx, "dup", name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tag.ID != ids[i] {
+				t.Errorf("%s: tag=%s, expected=%s", name, tag.ID, ids[i])
+			}
+		}
+
+		if err := m.DeleteTag(ctx, &tags.Tag{ID: ids[0]}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := m.FindTag(ctx, "dup", "cat-a"); err == nil {
+			t.Error("expected error")
+		}
+		tag, err := m.FindTag(ctx, "dup", "cat-b")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tag.ID != ids[1] {
+			t.Errorf("tag=%s, expected=%s", tag.ID, ids[1])
+		}
+	})
+}
+
+func TestCacheInvalidateConcurrent(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		m := newManager(ctx, t, vc)
+		ids := newTags(ctx, t, m, 1)
+		m.EnableCache(0)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 10; j++ {
+					if _, err := m.GetTag(ctx, ids[0]); err != nil {
+						t.Error(err)
+					}
+					m.InvalidateCache()
+				}
+			}()
+		}
+		wg.Wait()
+	})
+}
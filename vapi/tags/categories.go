@@ -24,6 +24,14 @@ import (
 	"github.com/vmware/govmomi/vapi/internal"
 )
 
+// Category Cardinality values.
+const (
+	// CardinalitySingle allows at most one tag from the category to be attached to an object.
+	CardinalitySingle = "SINGLE"
+	// CardinalityMultiple allows any number of tags from the category to be attached to an object.
+	CardinalityMultiple = "MULTIPLE"
+)
+
 // Category provides methods to create, read, update, delete, and enumerate categories.
 type Category struct {
 	ID              string   `json:"id,omitempty"`
@@ -89,7 +97,7 @@ func (c *Manager) CreateCategory(ctx context.Context, category *Category) (strin
 	}
 	url := internal.URL(c, internal.CategoryPath)
 	var res string
-	return res, c.Do(ctx, url.Request(http.MethodPost, spec), &res)
+	return res, c.Do(ctx, url.Request(ctx, http.MethodPost, spec), &res)
 }
 
 // UpdateCategory can update one or more of the AssociableTypes, Cardinality, Description and Name fields.
@@ -104,19 +112,58 @@ func (c *Manager) UpdateCategory(ctx context.Context, category *Category) error
 			Name:            category.Name,
 		},
 	}
+	c.cache.removeCategory(category.ID)
 	url := internal.URL(c, internal.CategoryPath).WithID(category.ID)
-	return c.Do(ctx, url.Request(http.MethodPatch, spec), nil)
+	return c.Do(ctx, url.Request(ctx, http.MethodPatch, spec), nil)
+}
+
+// UpdateCategoryCardinality changes the Cardinality of the given category.
+// Note that vCenter only supports changing from CardinalitySingle to CardinalityMultiple.
+func (c *Manager) UpdateCategoryCardinality(ctx context.Context, id string, cardinality string) error {
+	spec := struct {
+		Category Category `json:"update_spec"`
+	}{
+		Category: Category{Cardinality: cardinality},
+	}
+	c.cache.removeCategory(id)
+	url := internal.URL(c, internal.CategoryPath).WithID(id)
+	return c.Do(ctx, url.Request(ctx, http.MethodPatch, spec), nil)
+}
+
+// AddToUsedBy adds the given entity to the UsedBy list of the category.
+// The entity is typically the name of a solution using the category, such as an extension key.
+func (c *Manager) AddToUsedBy(ctx context.Context, id string, entity string) error {
+	return c.usedBy(ctx, "add-to-used-by", id, entity)
+}
+
+// RemoveFromUsedBy removes the given entity from the UsedBy list of the category.
+func (c *Manager) RemoveFromUsedBy(ctx context.Context, id string, entity string) error {
+	return c.usedBy(ctx, "remove-from-used-by", id, entity)
+}
+
+func (c *Manager) usedBy(ctx context.Context, action string, id string, entity string) error {
+	spec := struct {
+		Entity string `json:"used_by_entity"`
+	}{entity}
+	c.cache.removeCategory(id)
+	url := internal.URL(c, internal.CategoryPath).WithID(id).WithAction(action)
+	return c.Do(ctx, url.Request(ctx, http.MethodPost, spec), nil)
 }
 
 // DeleteCategory deletes an existing category.
 func (c *Manager) DeleteCategory(ctx context.Context, category *Category) error {
+	c.cache.removeCategory(category.ID)
 	url := internal.URL(c, internal.CategoryPath).WithID(category.ID)
-	return c.Do(ctx, url.Request(http.MethodDelete), nil)
+	return c.Do(ctx, url.Request(ctx, http.MethodDelete), nil)
 }
 
 // GetCategory fetches the category information for the given identifier.
 // The id parameter can be a Category ID or Category Name.
 func (c *Manager) GetCategory(ctx context.Context, id string) (*Category, error) {
+	if category, ok := c.cache.category(id); ok {
+		return category, nil
+	}
+
 	if isName(id) {
 		cat, err := c.GetCategories(ctx)
 		if err != nil {
@@ -131,14 +178,18 @@ func (c *Manager) GetCategory(ctx context.Context, id string) (*Category, error)
 	}
 	url := internal.URL(c, internal.CategoryPath).WithID(id)
 	var res Category
-	return &res, c.Do(ctx, url.Request(http.MethodGet), &res)
+	if err := c.Do(ctx, url.Request(ctx, http.MethodGet), &res); err != nil {
+		return &res, err
+	}
+	c.cache.addCategory(&res)
+	return &res, nil
 }
 
 // ListCategories returns all category IDs in the system.
 func (c *Manager) ListCategories(ctx context.Context) ([]string, error) {
 	url := internal.URL(c, internal.CategoryPath)
 	var res []string
-	return res, c.Do(ctx, url.Request(http.MethodGet), &res)
+	return res, c.Do(ctx, url.Request(ctx, http.MethodGet), &res)
 }
 
 // GetCategories fetches an array of category information in the system.
@@ -0,0 +1,93 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tags_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/tags"
+	"github.com/vmware/govmomi/vim25"
+)
+
+func TestCategoryUsedBy(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		m := newManager(ctx, t, vc)
+
+		id, err := m.CreateCategory(ctx, &tags.Category{Name: "my-category", Cardinality: tags.CardinalitySingle})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		usedBy := func(expect ...string) {
+			t.Helper()
+			category, err := m.GetCategory(ctx, id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(category.UsedBy, expect) {
+				t.Errorf("UsedBy=%v, expected=%v", category.UsedBy, expect)
+			}
+		}
+
+		for _, entity := range []string{"com.example.a", "com.example.b", "com.example.a"} {
+			if err = m.AddToUsedBy(ctx, id, entity); err != nil {
+				t.Fatal(err)
+			}
+		}
+		usedBy("com.example.b", "com.example.a")
+
+		if err = m.RemoveFromUsedBy(ctx, id, "com.example.b"); err != nil {
+			t.Fatal(err)
+		}
+		usedBy("com.example.a")
+	})
+}
+
+func TestUpdateCategoryCardinality(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		m := newManager(ctx, t, vc)
+
+		id, err := m.CreateCategory(ctx, &tags.Category{Name: "my-category", Cardinality: tags.CardinalitySingle})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		m.EnableCache(0)
+		if _, err = m.GetCategory(ctx, id); err != nil {
+			t.Fatal(err)
+		}
+
+		if err = m.UpdateCategoryCardinality(ctx, id, tags.CardinalityMultiple); err != nil {
+			t.Fatal(err)
+		}
+
+		category, err := m.GetCategory(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if category.Cardinality != tags.CardinalityMultiple {
+			t.Errorf("Cardinality=%s", category.Cardinality)
+		}
+
+		if err = m.UpdateCategoryCardinality(ctx, id, tags.CardinalitySingle); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
@@ -0,0 +1,185 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tags
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// Taxonomy is the serialized form of categories and tags, as returned by ExportTags and used by ImportTags.
+// IDs are not included, as categories and tags are matched by name when imported.
+type Taxonomy struct {
+	Categories []TaxonomyCategory `json:"categories"`
+}
+
+// TaxonomyCategory is a Category and its tags.
+type TaxonomyCategory struct {
+	Name            string        `json:"name"`
+	Description     string        `json:"description,omitempty"`
+	Cardinality     string        `json:"cardinality"`
+	AssociableTypes []string      `json:"associable_types,omitempty"`
+	Tags            []TaxonomyTag `json:"tags,omitempty"`
+}
+
+// TaxonomyTag is a Tag and optionally the paths of its attached objects.
+type TaxonomyTag struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Objects     []string `json:"objects,omitempty"`
+}
+
+// ObjectPathFunc converts an attached object reference to a path, such as an inventory path.
+type ObjectPathFunc func(context.Context, types.ManagedObjectReference) (string, error)
+
+// ObjectFindFunc converts a path produced by ObjectPathFunc to an object reference.
+type ObjectFindFunc func(context.Context, string) (mo.Reference, error)
+
+// ExportTags returns all categories and tags.
+// If path is not nil, the objects attached to each tag are included, as converted by path.
+func (c *Manager) ExportTags(ctx context.Context, path ObjectPathFunc) (*Taxonomy, error) {
+	categories, err := c.GetCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var t Taxonomy
+
+	for _, category := range categories {
+		tags, err := c.GetTagsForCategory(ctx, category.ID)
+		if err != nil {
+			return nil, fmt.Errorf("get tags for category %s: %s", category.Name, err)
+		}
+
+		objects := make(map[string][]string)
+		if path != nil && len(tags) != 0 {
+			ids := make([]string, len(tags))
+			for i := range tags {
+				ids[i] = tags[i].ID
+			}
+			attached, err := c.ListAttachedObjectsOnTags(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+			for _, a := range attached {
+				for _, ref := range a.ObjectIDs {
+					p, err := path(ctx, ref.Reference())
+					if err != nil {
+						return nil, err
+					}
+					objects[a.TagID] = append(objects[a.TagID], p)
+				}
+				sort.Strings(objects[a.TagID])
+			}
+		}
+
+		tc := TaxonomyCategory{
+			Name:            category.Name,
+			Description:     category.Description,
+			Cardinality:     category.Cardinality,
+			AssociableTypes: category.AssociableTypes,
+		}
+		for _, tag := range tags {
+			tc.Tags = append(tc.Tags, TaxonomyTag{
+				Name:        tag.Name,
+				Description: tag.Description,
+				Objects:     objects[tag.ID],
+			})
+		}
+		sort.Slice(tc.Tags, func(i, j int) bool { return tc.Tags[i].Name < tc.Tags[j].Name })
+
+		t.Categories = append(t.Categories, tc)
+	}
+
+	sort.Slice(t.Categories, func(i, j int) bool { return t.Categories[i].Name < t.Categories[j].Name })
+
+	return &t, nil
+}
+
+// ImportTags creates the categories and tags of the given Taxonomy, matching existing categories and tags by name.
+// Existing categories and tags are not modified.
+// If find is not nil, each tag is attached to its objects, as converted by find.
+func (c *Manager) ImportTags(ctx context.Context, t *Taxonomy, find ObjectFindFunc) error {
+	categories, err := c.GetCategories(ctx)
+	if err != nil {
+		return err
+	}
+
+	ids := make(map[string]string, len(categories))
+	for _, category := range categories {
+		ids[category.Name] = category.ID
+	}
+
+	for _, tc := range t.Categories {
+		id, ok := ids[tc.Name]
+		if !ok {
+			id, err = c.CreateCategory(ctx, &Category{
+				Name:            tc.Name,
+				Description:     tc.Description,
+				Cardinality:     tc.Cardinality,
+				AssociableTypes: tc.AssociableTypes,
+			})
+			if err != nil {
+				return fmt.Errorf("create category %s: %s", tc.Name, err)
+			}
+		}
+
+		tags, err := c.GetTagsForCategory(ctx, id)
+		if err != nil {
+			return fmt.Errorf("get tags for category %s: %s", tc.Name, err)
+		}
+		existing := make(map[string]string, len(tags))
+		for _, tag := range tags {
+			existing[tag.Name] = tag.ID
+		}
+
+		for _, tt := range tc.Tags {
+			tagID, ok := existing[tt.Name]
+			if !ok {
+				tagID, err = c.CreateTag(ctx, &Tag{
+					Name:        tt.Name,
+					Description: tt.Description,
+					CategoryID:  id,
+				})
+				if err != nil {
+					return fmt.Errorf("create tag %s: %s", tt.Name, err)
+				}
+			}
+
+			if find == nil || len(tt.Objects) == 0 {
+				continue
+			}
+
+			refs := make([]mo.Reference, len(tt.Objects))
+			for i, p := range tt.Objects {
+				if refs[i], err = find(ctx, p); err != nil {
+					return err
+				}
+			}
+
+			if err = c.AttachTagToMultipleObjects(ctx, tagID, refs); err != nil {
+				return fmt.Errorf("attach tag %s: %s", tt.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
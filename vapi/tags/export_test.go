@@ -0,0 +1,109 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tags_test
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/tags"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+func TestExportImport(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		m := newManager(ctx, t, vc)
+		ids := newTags(ctx, t, m, 2)
+		refs := vms(ctx, t, vc)
+
+		if err := m.AttachTagToMultipleObjects(ctx, ids[0], refs[:2]); err != nil {
+			t.Fatal(err)
+		}
+
+		finder := find.NewFinder(vc)
+
+		var path tags.ObjectPathFunc = func(ctx context.Context, ref types.ManagedObjectReference) (string, error) {
+			e, err := finder.Element(ctx, ref)
+			if err != nil {
+				return "", err
+			}
+			return e.Path, nil
+		}
+
+		var lookup tags.ObjectFindFunc = func(ctx context.Context, path string) (mo.Reference, error) {
+			l, err := finder.ManagedObjectList(ctx, path)
+			if err != nil {
+				return nil, err
+			}
+			if len(l) != 1 {
+				return nil, fmt.Errorf("%s: %d objects", path, len(l))
+			}
+			return l[0].Object, nil
+		}
+
+		export, err := m.ExportTags(ctx, path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(export.Categories) != 1 || len(export.Categories[0].Tags) != 2 {
+			t.Fatalf("%#v", export)
+		}
+		if objs := export.Categories[0].Tags[0].Objects; len(objs) != 2 {
+			t.Errorf("objects=%v", objs)
+		}
+
+		categories, err := m.GetCategories(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := range categories {
+			if err = m.DeleteCategory(ctx, &categories[i]); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		for i := 0; i < 2; i++ { // import is idempotent
+			if err = m.ImportTags(ctx, export, lookup); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		res, err := m.ExportTags(ctx, path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(export, res) {
+			t.Errorf("export=%#v, import=%#v", export, res)
+		}
+
+		res, err = m.ExportTags(ctx, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, tag := range res.Categories[0].Tags {
+			if len(tag.Objects) != 0 {
+				t.Errorf("%s objects=%v", tag.Name, tag.Objects)
+			}
+		}
+	})
+}
@@ -0,0 +1,153 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tags
+
+import (
+	"context"
+	"strings"
+
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// AttachedObject is an object attached to a tag, as returned by ResolveAttachedObjects.
+type AttachedObject struct {
+	Object types.ManagedObjectReference `json:"object"`
+	Name   string                       `json:"name,omitempty"`
+	Path   string                       `json:"path,omitempty"`
+}
+
+// Reference implements mo.Reference
+func (o AttachedObject) Reference() types.ManagedObjectReference {
+	return o.Object
+}
+
+// ResolveAttachedObjects combines ListAttachedObjects with a single property collector request
+// to populate the Name and inventory Path of each object attached to the given tag.
+// Objects that are not vSphere managed entities, such as content libraries, are returned without a Name or Path.
+func (c *Manager) ResolveAttachedObjects(ctx context.Context, vc *vim25.Client, tagID string) ([]AttachedObject, error) {
+	refs, err := c.ListAttachedObjects(ctx, tagID)
+	if err != nil {
+		return nil, err
+	}
+
+	traverse := []types.BaseSelectionSpec{
+		&types.TraversalSpec{
+			SelectionSpec: types.SelectionSpec{Name: "traverseParent"},
+			Type:          "ManagedEntity",
+			Path:          "parent",
+			Skip:          types.NewBool(false),
+			SelectSet: []types.BaseSelectionSpec{
+				&types.SelectionSpec{Name: "traverseParent"},
+			},
+		},
+		&types.TraversalSpec{
+			Type: "VirtualMachine",
+			Path: "parentVApp",
+			Skip: types.NewBool(false),
+			SelectSet: []types.BaseSelectionSpec{
+				&types.SelectionSpec{Name: "traverseParent"},
+			},
+		},
+	}
+
+	objs := make([]AttachedObject, len(refs))
+	var specs []types.ObjectSpec
+
+	for i := range refs {
+		objs[i].Object = refs[i].Reference()
+		if strings.HasPrefix(objs[i].Object.Type, "com.vmware.") {
+			continue // not a vSphere managed object
+		}
+		specs = append(specs, types.ObjectSpec{
+			Obj:       objs[i].Object,
+			Skip:      types.NewBool(false),
+			SelectSet: traverse,
+		})
+	}
+
+	if len(specs) == 0 {
+		return objs, nil
+	}
+
+	req := types.RetrieveProperties{
+		SpecSet: []types.PropertyFilterSpec{{
+			ObjectSet: specs,
+			PropSet: []types.PropertySpec{
+				{Type: "ManagedEntity", PathSet: []string{"name", "parent"}},
+				{Type: "VirtualMachine", PathSet: []string{"parentVApp"}},
+			},
+		}},
+	}
+
+	res, err := property.DefaultCollector(vc).RetrieveProperties(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	type entity struct {
+		name   string
+		parent *types.ManagedObjectReference
+	}
+
+	entities := make(map[types.ManagedObjectReference]entity, len(res.Returnval))
+
+	for _, content := range res.Returnval {
+		var e entity
+		var vapp *types.ManagedObjectReference
+
+		for _, p := range content.PropSet {
+			ref, isRef := p.Val.(types.ManagedObjectReference)
+			switch p.Name {
+			case "name":
+				e.name, _ = p.Val.(string)
+			case "parent":
+				if isRef {
+					e.parent = &ref
+				}
+			case "parentVApp":
+				if isRef {
+					vapp = &ref
+				}
+			}
+		}
+
+		if e.parent == nil {
+			e.parent = vapp
+		}
+
+		entities[content.Obj] = e
+	}
+
+	for i := range objs {
+		e, ok := entities[objs[i].Object]
+		if !ok {
+			continue
+		}
+		objs[i].Name = e.name
+
+		var names []string
+		for ok && e.parent != nil { // the root folder is not included in the inventory path
+			names = append([]string{e.name}, names...)
+			e, ok = entities[*e.parent]
+		}
+		objs[i].Path = "/" + strings.Join(names, "/")
+	}
+
+	return objs, nil
+}
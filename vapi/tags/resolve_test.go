@@ -0,0 +1,76 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tags_test
+
+import (
+	"context"
+	"path"
+	"testing"
+
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+func TestResolveAttachedObjects(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		m := newManager(ctx, t, vc)
+		ids := newTags(ctx, t, m, 1)
+
+		refs := vms(ctx, t, vc)
+		refs = append(refs,
+			simulator.Map.Any("ClusterComputeResource"),
+			simulator.Map.Any("Network"),
+			types.ManagedObjectReference{Type: "com.vmware.content.Library", Value: "my-library"},
+		)
+
+		if err := m.AttachTagToMultipleObjects(ctx, ids[0], refs); err != nil {
+			t.Fatal(err)
+		}
+
+		objs, err := m.ResolveAttachedObjects(ctx, vc, ids[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(objs) != len(refs) {
+			t.Fatalf("%d objects", len(objs))
+		}
+
+		finder := find.NewFinder(vc)
+
+		for _, obj := range objs {
+			if obj.Object.Type == "com.vmware.content.Library" {
+				if obj.Name != "" || obj.Path != "" {
+					t.Errorf("%#v", obj)
+				}
+				continue
+			}
+
+			e, err := finder.Element(ctx, obj.Reference())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if obj.Path != e.Path {
+				t.Errorf("%s: path=%q, expected=%q", obj.Object, obj.Path, e.Path)
+			}
+			if obj.Name != path.Base(e.Path) {
+				t.Errorf("%s: name=%q", obj.Object, obj.Name)
+			}
+		}
+	})
+}
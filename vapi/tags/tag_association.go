@@ -19,8 +19,10 @@ package tags
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/vmware/govmomi/vapi/internal"
 	"github.com/vmware/govmomi/vim25/mo"
@@ -38,14 +40,18 @@ func (c *Manager) tagID(ctx context.Context, id string) (string, error) {
 }
 
 // AttachTag attaches a tag ID to a managed object.
+// See EnableValidation for client-side validation of attach operations.
 func (c *Manager) AttachTag(ctx context.Context, tagID string, ref mo.Reference) error {
 	id, err := c.tagID(ctx, tagID)
 	if err != nil {
 		return err
 	}
+	if err = c.validateAttach(ctx, []string{id}, []mo.Reference{ref}); err != nil {
+		return err
+	}
 	spec := internal.NewAssociation(ref)
 	url := internal.URL(c, internal.AssociationPath).WithID(id).WithAction("attach")
-	return c.Do(ctx, url.Request(http.MethodPost, spec), nil)
+	return c.Do(ctx, url.Request(ctx, http.MethodPost, spec), nil)
 }
 
 // DetachTag detaches a tag ID from a managed object.
@@ -57,7 +63,161 @@ func (c *Manager) DetachTag(ctx context.Context, tagID string, ref mo.Reference)
 	}
 	spec := internal.NewAssociation(ref)
 	url := internal.URL(c, internal.AssociationPath).WithID(id).WithAction("detach")
-	return c.Do(ctx, url.Request(http.MethodPost, spec), nil)
+	return c.Do(ctx, url.Request(ctx, http.MethodPost, spec), nil)
+}
+
+// BatchError is an error returned for a single item of a batch operation.
+// TagID and Object identify the failed item, when known from the request or the message arguments.
+type BatchError struct {
+	ID      string   `json:"id"`
+	Message string   `json:"default_message"`
+	Args    []string `json:"args,omitempty"`
+
+	TagID  string       `json:"-"`
+	Object mo.Reference `json:"-"`
+}
+
+// BatchErrors is returned by the batch attach and detach operations when the operation failed for one or more items.
+// Any items not in error were successfully attached or detached.
+type BatchErrors []BatchError
+
+func (b BatchErrors) Error() string {
+	msgs := make([]string, len(b))
+	for i := range b {
+		msgs[i] = b[i].Message
+	}
+	return strings.Join(msgs, "; ")
+}
+
+type batchResponse struct {
+	Success bool        `json:"success"`
+	Errors  BatchErrors `json:"error_messages,omitempty"`
+}
+
+// err returns the response errors, if any, with each error's TagID and Object set to the matching item of
+// the request tagIDs and refs. An item matches if it is the only one requested or if it is a message argument.
+func (r *batchResponse) err(tagIDs []string, refs []mo.Reference) error {
+	if r.Success {
+		return nil
+	}
+	if len(r.Errors) == 0 {
+		return errors.New("batch operation failed")
+	}
+
+	for i := range r.Errors {
+		e := &r.Errors[i]
+		if len(tagIDs) == 1 {
+			e.TagID = tagIDs[0]
+		}
+		if len(refs) == 1 {
+			e.Object = refs[0]
+		}
+		for _, arg := range e.Args {
+			for _, id := range tagIDs {
+				if arg == id {
+					e.TagID = id
+				}
+			}
+			for _, ref := range refs {
+				if mref := ref.Reference(); arg == mref.Value || arg == mref.String() {
+					e.Object = ref
+				}
+			}
+		}
+	}
+
+	return r.Errors
+}
+
+func (c *Manager) tagIDs(ctx context.Context, tagIDs []string) ([]string, error) {
+	ids := make([]string, len(tagIDs))
+	for i := range tagIDs {
+		id, err := c.tagID(ctx, tagIDs[i])
+		if err != nil {
+			return nil, err
+		}
+		ids[i] = id
+	}
+	return ids, nil
+}
+
+func associatedObjects(refs []mo.Reference) []internal.AssociatedObject {
+	ids := make([]internal.AssociatedObject, len(refs))
+	for i := range refs {
+		ids[i] = internal.AssociatedObject(refs[i].Reference())
+	}
+	return ids
+}
+
+func (c *Manager) batchTags(ctx context.Context, action string, tagIDs []string, ref mo.Reference) error {
+	ids, err := c.tagIDs(ctx, tagIDs)
+	if err != nil {
+		return err
+	}
+	if action == "attach-multiple-tags-to-object" {
+		if err = c.validateAttach(ctx, ids, []mo.Reference{ref}); err != nil {
+			return err
+		}
+	}
+
+	spec := struct {
+		ObjectID internal.AssociatedObject `json:"object_id"`
+		TagIDs   []string                  `json:"tag_ids"`
+	}{internal.AssociatedObject(ref.Reference()), ids}
+
+	url := internal.URL(c, internal.AssociationPath).WithAction(action)
+	var res batchResponse
+	if err = c.Do(ctx, url.Request(ctx, http.MethodPost, spec), &res); err != nil {
+		return err
+	}
+	return res.err(ids, []mo.Reference{ref})
+}
+
+func (c *Manager) batchObjects(ctx context.Context, action string, tagID string, refs []mo.Reference) error {
+	id, err := c.tagID(ctx, tagID)
+	if err != nil {
+		return err
+	}
+	if action == "attach-tag-to-multiple-objects" {
+		if err = c.validateAttach(ctx, []string{id}, refs); err != nil {
+			return err
+		}
+	}
+
+	spec := struct {
+		ObjectIDs []internal.AssociatedObject `json:"object_ids"`
+	}{associatedObjects(refs)}
+
+	url := internal.URL(c, internal.AssociationPath).WithID(id).WithAction(action)
+	var res batchResponse
+	if err = c.Do(ctx, url.Request(ctx, http.MethodPost, spec), &res); err != nil {
+		return err
+	}
+	return res.err([]string{id}, refs)
+}
+
+// AttachMultipleTagsToObject attaches multiple tag IDs to a managed object.
+// BatchErrors is returned if any of the tags could not be attached.
+func (c *Manager) AttachMultipleTagsToObject(ctx context.Context, tagIDs []string, ref mo.Reference) error {
+	return c.batchTags(ctx, "attach-multiple-tags-to-object", tagIDs, ref)
+}
+
+// AttachTagToMultipleObjects attaches a tag ID to multiple managed objects.
+// BatchErrors is returned if the tag could not be attached to any of the objects.
+func (c *Manager) AttachTagToMultipleObjects(ctx context.Context, tagID string, refs []mo.Reference) error {
+	return c.batchObjects(ctx, "attach-tag-to-multiple-objects", tagID, refs)
+}
+
+// DetachMultipleTagsFromObject detaches multiple tag IDs from a managed object.
+// BatchErrors is returned if any of the tags could not be detached.
+func (c *Manager) DetachMultipleTagsFromObject(ctx context.Context, tagIDs []string, ref mo.Reference) error {
+	return c.batchTags(ctx, "detach-multiple-tags-from-object", tagIDs, ref)
+}
+
+// DetachTagFromMultipleObjects detaches a tag ID from multiple managed objects.
+// BatchErrors is returned if the tag could not be detached from any of the objects.
+func (c *Manager) DetachTagFromMultipleObjects(ctx context.Context, tagID string, refs []mo.Reference) error {
+	return c.batchObjects(ctx, "detach-tag-from-multiple-objects", tagID, refs)
 }
 
 // ListAttachedTags fetches the array of tag IDs attached to the given object.
@@ -65,7 +225,7 @@ func (c *Manager) ListAttachedTags(ctx context.Context, ref mo.Reference) ([]str
 	spec := internal.NewAssociation(ref)
 	url := internal.URL(c, internal.AssociationPath).WithAction("list-attached-tags")
 	var res []string
-	return res, c.Do(ctx, url.Request(http.MethodPost, spec), &res)
+	return res, c.Do(ctx, url.Request(ctx, http.MethodPost, spec), &res)
 }
 
 // GetAttachedTags fetches the array of tags attached to the given object.
@@ -86,6 +246,15 @@ func (c *Manager) GetAttachedTags(ctx context.Context, ref mo.Reference) ([]Tag,
 	return info, nil
 }
 
+// ListAttachableTags fetches the array of tag IDs that can be attached to the given object,
+// omitting tags already attached and tags excluded by their category's AssociableTypes or Cardinality.
+func (c *Manager) ListAttachableTags(ctx context.Context, ref mo.Reference) ([]string, error) {
+	spec := internal.NewAssociation(ref)
+	url := internal.URL(c, internal.AssociationPath).WithAction("list-attachable-tags")
+	var res []string
+	return res, c.Do(ctx, url.Request(ctx, http.MethodPost, spec), &res)
+}
+
 // ListAttachedObjects fetches the array of attached objects for the given tag ID.
 func (c *Manager) ListAttachedObjects(ctx context.Context, tagID string) ([]mo.Reference, error) {
 	id, err := c.tagID(ctx, tagID)
@@ -94,7 +263,7 @@ func (c *Manager) ListAttachedObjects(ctx context.Context, tagID string) ([]mo.R
 	}
 	url := internal.URL(c, internal.AssociationPath).WithID(id).WithAction("list-attached-objects")
 	var res []internal.AssociatedObject
-	if err := c.Do(ctx, url.Request(http.MethodPost, nil), &res); err != nil {
+	if err := c.Do(ctx, url.Request(ctx, http.MethodPost, nil), &res); err != nil {
 		return nil, err
 	}
 
@@ -149,7 +318,7 @@ func (c *Manager) ListAttachedObjectsOnTags(ctx context.Context, tagID []string)
 
 	url := internal.URL(c, internal.AssociationPath).WithAction("list-attached-objects-on-tags")
 	var res []AttachedObjects
-	return res, c.Do(ctx, url.Request(http.MethodPost, spec), &res)
+	return res, c.Do(ctx, url.Request(ctx, http.MethodPost, spec), &res)
 }
 
 // GetAttachedObjectsOnTags combines ListAttachedObjectsOnTags and populates each Tag field.
@@ -170,8 +339,9 @@ func (c *Manager) GetAttachedObjectsOnTags(ctx context.Context, tagID []string)
 			if err != nil {
 				return nil, fmt.Errorf("get tag %s: %s", id, err)
 			}
-			objs[i].Tag = tag
+			tags[id] = tag
 		}
+		objs[i].Tag = tag
 	}
 
 	return objs, nil
@@ -201,20 +371,16 @@ func (t *AttachedTags) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
-// ListAttachedTagsOnObjects fetches the array of attached tag IDs for the given object IDs.
+// ListAttachedTagsOnObjects fetches the array of attached tag IDs for the given object IDs,
+// with a single request that returns the tag IDs grouped per object.
 func (c *Manager) ListAttachedTagsOnObjects(ctx context.Context, objectID []mo.Reference) ([]AttachedTags, error) {
-	var ids []internal.AssociatedObject
-	for i := range objectID {
-		ids = append(ids, internal.AssociatedObject(objectID[i].Reference()))
-	}
-
 	spec := struct {
 		ObjectIDs []internal.AssociatedObject `json:"object_ids"`
-	}{ids}
+	}{associatedObjects(objectID)}
 
 	url := internal.URL(c, internal.AssociationPath).WithAction("list-attached-tags-on-objects")
 	var res []AttachedTags
-	return res, c.Do(ctx, url.Request(http.MethodPost, spec), &res)
+	return res, c.Do(ctx, url.Request(ctx, http.MethodPost, spec), &res)
 }
 
 // GetAttachedTagsOnObjects calls ListAttachedTagsOnObjects and populates each Tags field.
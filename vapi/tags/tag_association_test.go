@@ -0,0 +1,253 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tags_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vapi/tags"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/mo"
+
+	_ "github.com/vmware/govmomi/vapi/simulator"
+)
+
+// newTags creates a category with n tags, returning the tag IDs.
+func newTags(ctx context.Context, t *testing.T, m *tags.Manager, n int) []string {
+	category, err := m.CreateCategory(ctx, &tags.Category{Name: "test-category", Cardinality: "MULTIPLE"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []string
+	for i := 0; i < n; i++ {
+		id, err := m.CreateTag(ctx, &tags.Tag{Name: fmt.Sprintf("test-tag-%d", i), CategoryID: category})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func newManager(ctx context.Context, t *testing.T, vc *vim25.Client) *tags.Manager {
+	c := rest.NewClient(vc)
+	if err := c.Login(ctx, simulator.DefaultLogin); err != nil {
+		t.Fatal(err)
+	}
+	return tags.NewManager(c)
+}
+
+func vms(ctx context.Context, t *testing.T, vc *vim25.Client) []mo.Reference {
+	list, err := find.NewFinder(vc).VirtualMachineList(ctx, "*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	refs := make([]mo.Reference, len(list))
+	for i := range list {
+		refs[i] = list[i]
+	}
+	return refs
+}
+
+func TestBatchAttachDetach(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		m := newManager(ctx, t, vc)
+		ids := newTags(ctx, t, m, 3)
+		refs := vms(ctx, t, vc)
+
+		if err := m.AttachMultipleTagsToObject(ctx, ids, refs[0]); err != nil {
+			t.Fatal(err)
+		}
+		attached, err := m.ListAttachedTags(ctx, refs[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(attached) != len(ids) {
+			t.Errorf("attached=%v", attached)
+		}
+
+		if err = m.AttachTagToMultipleObjects(ctx, ids[0], refs); err != nil {
+			t.Fatal(err)
+		}
+		objs, err := m.ListAttachedObjects(ctx, ids[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(objs) != len(refs) {
+			t.Errorf("objects=%v", objs)
+		}
+
+		if err = m.DetachTagFromMultipleObjects(ctx, ids[0], refs); err != nil {
+			t.Fatal(err)
+		}
+		if err = m.DetachMultipleTagsFromObject(ctx, ids, refs[0]); err != nil {
+			t.Fatal(err)
+		}
+		res, err := m.ListAttachedTagsOnObjects(ctx, refs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, a := range res {
+			if len(a.TagIDs) != 0 {
+				t.Errorf("%s: attached=%v", a.ObjectID, a.TagIDs)
+			}
+		}
+
+		// Per-item errors are returned and the valid tags are still attached
+		enoent := "urn:vmomi:InventoryServiceTag:enoent:GLOBAL"
+		err = m.AttachMultipleTagsToObject(ctx, []string{ids[1], enoent}, refs[0])
+		berr, ok := err.(tags.BatchErrors)
+		if !ok || len(berr) != 1 {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+		if berr[0].ID != "com.vmware.vapi.std.errors.not_found" || berr[0].TagID != enoent || berr[0].Object != refs[0] {
+			t.Errorf("error=%#v", berr[0])
+		}
+		attached, err = m.ListAttachedTags(ctx, refs[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(attached) != 1 || attached[0] != ids[1] {
+			t.Errorf("attached=%v", attached)
+		}
+	})
+}
+
+func TestAttachedTagsOnObjects(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		m := newManager(ctx, t, vc)
+		ids := newTags(ctx, t, m, 2)
+		refs := vms(ctx, t, vc)
+
+		for _, id := range ids {
+			if err := m.AttachTagToMultipleObjects(ctx, id, refs[:2]); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		res, err := m.GetAttachedTagsOnObjects(ctx, refs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(res) != len(refs) {
+			t.Fatalf("%d objects", len(res))
+		}
+		for i, a := range res {
+			expect := 0
+			if i < 2 {
+				expect = len(ids)
+			}
+			if a.ObjectID.Reference() != refs[i].Reference() || len(a.TagIDs) != expect || len(a.Tags) != expect {
+				t.Errorf("%s: %#v", refs[i].Reference(), a)
+			}
+		}
+
+		objs, err := m.GetAttachedObjectsOnTags(ctx, ids)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, o := range objs {
+			if o.Tag == nil || o.Tag.ID != o.TagID || len(o.ObjectIDs) != 2 {
+				t.Errorf("%#v", o)
+			}
+		}
+	})
+}
+
+func TestAttachValidation(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		m := newManager(ctx, t, vc)
+		refs := vms(ctx, t, vc)
+
+		single, err := m.CreateCategory(ctx, &tags.Category{
+			Name:            "single",
+			Cardinality:     tags.CardinalitySingle,
+			AssociableTypes: []string{"VirtualMachine"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		host, err := m.CreateCategory(ctx, &tags.Category{
+			Name:            "host",
+			Cardinality:     tags.CardinalityMultiple,
+			AssociableTypes: []string{"HostSystem"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var ids []string
+		for i, category := range []string{single, single, host} {
+			id, err := m.CreateTag(ctx, &tags.Tag{Name: fmt.Sprintf("tag-%d", i), CategoryID: category})
+			if err != nil {
+				t.Fatal(err)
+			}
+			ids = append(ids, id)
+		}
+
+		attachable, err := m.ListAttachableTags(ctx, refs[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(attachable) != 2 {
+			t.Errorf("attachable=%v", attachable)
+		}
+
+		if err = m.AttachTag(ctx, ids[0], refs[0]); err != nil {
+			t.Fatal(err)
+		}
+
+		attachable, err = m.ListAttachableTags(ctx, refs[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(attachable) != 0 {
+			t.Errorf("attachable=%v", attachable)
+		}
+
+		// SINGLE cardinality
+		if err = m.AttachTag(ctx, ids[1], refs[0]); err == nil {
+			t.Error("expected error")
+		}
+		// AssociableTypes
+		if err = m.AttachTag(ctx, ids[2], refs[0]); err == nil {
+			t.Error("expected error")
+		}
+
+		err = m.AttachTagToMultipleObjects(ctx, ids[1], refs[:2])
+		berr, ok := err.(tags.BatchErrors)
+		if !ok || len(berr) != 1 {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+		if berr[0].TagID != ids[1] || berr[0].Object != refs[0] {
+			t.Errorf("error=%#v", berr[0])
+		}
+		objs, err := m.ListAttachedObjects(ctx, ids[1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(objs) != 1 || objs[0].Reference() != refs[1].Reference() {
+			t.Errorf("objects=%v", objs)
+		}
+	})
+}
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"sync"
 
 	"github.com/vmware/govmomi/vapi/internal"
 	"github.com/vmware/govmomi/vapi/rest"
@@ -29,6 +30,9 @@ import (
 // Manager extends rest.Client, adding tag related methods.
 type Manager struct {
 	*rest.Client
+
+	cache    *cache
+	validate bool
 }
 
 // NewManager creates a new Manager instance with the given client.
@@ -92,7 +96,7 @@ func (c *Manager) CreateTag(ctx context.Context, tag *Tag) (string, error) {
 	}
 	url := internal.URL(c, internal.TagPath)
 	var res string
-	return res, c.Do(ctx, url.Request(http.MethodPost, spec), &res)
+	return res, c.Do(ctx, url.Request(ctx, http.MethodPost, spec), &res)
 }
 
 // UpdateTag can update one or both of the tag Description and Name fields.
@@ -105,19 +109,25 @@ func (c *Manager) UpdateTag(ctx context.Context, tag *Tag) error {
 			Description: tag.Description,
 		},
 	}
+	c.cache.removeTag(tag.ID)
 	url := internal.URL(c, internal.TagPath).WithID(tag.ID)
-	return c.Do(ctx, url.Request(http.MethodPatch, spec), nil)
+	return c.Do(ctx, url.Request(ctx, http.MethodPatch, spec), nil)
 }
 
 // DeleteTag deletes an existing tag.
 func (c *Manager) DeleteTag(ctx context.Context, tag *Tag) error {
+	c.cache.removeTag(tag.ID)
 	url := internal.URL(c, internal.TagPath).WithID(tag.ID)
-	return c.Do(ctx, url.Request(http.MethodDelete), nil)
+	return c.Do(ctx, url.Request(ctx, http.MethodDelete), nil)
 }
 
 // GetTag fetches the tag information for the given identifier.
 // The id parameter can be a Tag ID or Tag Name.
 func (c *Manager) GetTag(ctx context.Context, id string) (*Tag, error) {
+	if tag, ok := c.cache.tag(id); ok {
+		return tag, nil
+	}
+
 	if isName(id) {
 		tags, err := c.GetTags(ctx)
 		if err != nil {
@@ -133,8 +143,11 @@ func (c *Manager) GetTag(ctx context.Context, id string) (*Tag, error) {
 
 	url := internal.URL(c, internal.TagPath).WithID(id)
 	var res Tag
-	return &res, c.Do(ctx, url.Request(http.MethodGet), &res)
-
+	if err := c.Do(ctx, url.Request(ctx, http.MethodGet), &res); err != nil {
+		return &res, err
+	}
+	c.cache.addTag(&res)
+	return &res, nil
 }
 
 // GetTagForCategory fetches the tag information for the given identifier in the given category.
@@ -161,11 +174,79 @@ func (c *Manager) GetTagForCategory(ctx context.Context, id, category string) (*
 	return nil, fmt.Errorf("tag %q not found in category %q", id, category)
 }
 
+// findTagConcurrency is the number of tags FindTag fetches concurrently.
+const findTagConcurrency = 8
+
+// FindTag returns the tag with the given name in the given category, which can be a Category ID or Category Name.
+// The category's tags are fetched concurrently, stopping once a match is found.
+// When the cache is enabled (see EnableCache), the cached tags are searched before making any requests
+// and the fetched tags are cached, such that lookups of other tags in the same category need fewer requests.
+func (c *Manager) FindTag(ctx context.Context, name, category string) (*Tag, error) {
+	cat, err := c.GetCategory(ctx, category)
+	if err != nil {
+		return nil, err
+	}
+
+	if tag, ok := c.cache.findTag(name, cat.ID); ok {
+		return tag, nil
+	}
+
+	ids, err := c.ListTagsForCategory(ctx, cat.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	for start := 0; start < len(ids); start += findTagConcurrency {
+		end := start + findTagConcurrency
+		if end > len(ids) {
+			end = len(ids)
+		}
+		chunk := ids[start:end]
+		tags := make([]*Tag, len(chunk))
+		errs := make([]error, len(chunk))
+
+		var wg sync.WaitGroup
+		for i := range chunk {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				tags[i], errs[i] = c.GetTag(ctx, chunk[i])
+			}(i)
+		}
+		wg.Wait()
+
+		for i := range chunk {
+			if errs[i] != nil {
+				return nil, fmt.Errorf("get tag %s: %s", chunk[i], errs[i])
+			}
+			if tags[i].Name == name {
+				return tags[i], nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("tag %q not found in category %q", name, category)
+}
+
+// listTagsPageSize is the number of tag IDs ListTags requests per page.
+const listTagsPageSize = 1000
+
 // ListTags returns all tag IDs in the system.
+// The IDs are fetched in pages of listTagsPageSize, where the server supports paging.
 func (c *Manager) ListTags(ctx context.Context) ([]string, error) {
-	url := internal.URL(c, internal.TagPath)
+	it := internal.NewResourceIterator(c.Do, func() *internal.Resource {
+		return internal.URL(c, internal.TagPath)
+	}, listTagsPageSize)
+
 	var res []string
-	return res, c.Do(ctx, url.Request(http.MethodGet), &res)
+	for it.Next(ctx) {
+		var id string
+		if err := it.Decode(&id); err != nil {
+			return nil, err
+		}
+		res = append(res, id)
+	}
+	return res, it.Err()
 }
 
 // GetTags fetches an array of tag information in the system.
@@ -203,7 +284,7 @@ func (c *Manager) ListTagsForCategory(ctx context.Context, id string) ([]string,
 	}{id}
 	url := internal.URL(c, internal.TagPath).WithID(id).WithAction("list-tags-for-category")
 	var res []string
-	return res, c.Do(ctx, url.Request(http.MethodPost, body), &res)
+	return res, c.Do(ctx, url.Request(ctx, http.MethodPost, body), &res)
 }
 
 // The id parameter can be a Category ID or Category Name.
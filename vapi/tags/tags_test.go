@@ -0,0 +1,72 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tags_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vapi/tags"
+	"github.com/vmware/govmomi/vim25"
+)
+
+func TestListTagsPaged(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		pages := 0
+		count := func(next rest.Handler) rest.Handler {
+			return func(ctx context.Context, req *http.Request, resBody interface{}) error {
+				if req.Method == http.MethodGet && req.URL.Query().Get("limit") != "" {
+					pages++
+				}
+				return next(ctx, req, resBody)
+			}
+		}
+
+		c := rest.NewClient(vc, rest.WithMiddleware(count))
+		if err := c.Login(ctx, simulator.DefaultLogin); err != nil {
+			t.Fatal(err)
+		}
+		m := tags.NewManager(c)
+
+		n := 1500
+		ids := newTags(ctx, t, m, n)
+
+		list, err := m.ListTags(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(list) != n {
+			t.Errorf("len=%d", len(list))
+		}
+		if pages != 2 {
+			t.Errorf("pages=%d", pages)
+		}
+
+		seen := make(map[string]bool)
+		for _, id := range list {
+			seen[id] = true
+		}
+		for _, id := range ids {
+			if !seen[id] {
+				t.Errorf("missing %s", id)
+			}
+		}
+	})
+}
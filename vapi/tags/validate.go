@@ -0,0 +1,117 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tags
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// EnableValidation enables client-side validation of tag attach operations.
+// When enabled, the attach methods check the tag category's AssociableTypes and Cardinality
+// before sending the attach request, returning an *AttachError if the tag cannot be attached.
+// Validation requires additional requests to fetch tags, categories and attached tags, see EnableCache.
+func (c *Manager) EnableValidation(enable bool) {
+	c.validate = enable
+}
+
+// AttachError is returned by the attach methods when validation is enabled and a tag cannot be attached to an object.
+type AttachError struct {
+	Tag      *Tag
+	Category *Category
+	Object   types.ManagedObjectReference
+	Reason   string
+}
+
+func (e *AttachError) Error() string {
+	return fmt.Sprintf("cannot attach tag %q to %s: %s", e.Tag.Name, e.Object, e.Reason)
+}
+
+// validateAttach checks that each of the given tag IDs can be attached to each of the given objects.
+func (c *Manager) validateAttach(ctx context.Context, tagIDs []string, refs []mo.Reference) error {
+	if !c.validate {
+		return nil
+	}
+
+	attach := make(map[string]*Tag, len(tagIDs))
+	categories := make(map[string]*Category)
+	single := make(map[string]*Tag) // category ID -> tag to attach
+
+	for _, id := range tagIDs {
+		tag, err := c.GetTag(ctx, id)
+		if err != nil {
+			return err
+		}
+		attach[tag.ID] = tag
+
+		category, ok := categories[tag.CategoryID]
+		if !ok {
+			category, err = c.GetCategory(ctx, tag.CategoryID)
+			if err != nil {
+				return err
+			}
+			categories[category.ID] = category
+		}
+
+		for _, ref := range refs {
+			kind := ref.Reference().Type
+			if len(category.AssociableTypes) != 0 && !category.hasType(kind) {
+				return &AttachError{tag, category, ref.Reference(),
+					fmt.Sprintf("category %q is not associable with type %s", category.Name, kind)}
+			}
+		}
+
+		if category.Cardinality == CardinalitySingle {
+			if other, ok := single[category.ID]; ok && other.ID != tag.ID {
+				return &AttachError{tag, category, refs[0].Reference(),
+					fmt.Sprintf("category %q cardinality is %s and tag %q is also being attached", category.Name, CardinalitySingle, other.Name)}
+			}
+			single[category.ID] = tag
+		}
+	}
+
+	if len(single) == 0 || len(refs) == 0 {
+		return nil
+	}
+
+	attached, err := c.ListAttachedTagsOnObjects(ctx, refs)
+	if err != nil {
+		return err
+	}
+
+	for _, obj := range attached {
+		for _, id := range obj.TagIDs {
+			if _, ok := attach[id]; ok {
+				continue
+			}
+			other, err := c.GetTag(ctx, id)
+			if err != nil {
+				return err
+			}
+			if tag, ok := single[other.CategoryID]; ok {
+				category := categories[other.CategoryID]
+				return &AttachError{tag, category, obj.ObjectID.Reference(),
+					fmt.Sprintf("category %q cardinality is %s and tag %q is already attached", category.Name, CardinalitySingle, other.Name)}
+			}
+		}
+	}
+
+	return nil
+}
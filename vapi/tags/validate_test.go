@@ -0,0 +1,98 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tags_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/tags"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/mo"
+)
+
+func TestValidateAttach(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		m := newManager(ctx, t, vc)
+		m.EnableValidation(true)
+		refs := vms(ctx, t, vc)
+
+		single, err := m.CreateCategory(ctx, &tags.Category{
+			Name:            "single",
+			Cardinality:     tags.CardinalitySingle,
+			AssociableTypes: []string{"VirtualMachine"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var ids []string
+		for _, name := range []string{"tag-a", "tag-b"} {
+			id, err := m.CreateTag(ctx, &tags.Tag{Name: name, CategoryID: single})
+			if err != nil {
+				t.Fatal(err)
+			}
+			ids = append(ids, id)
+		}
+
+		if err = m.AttachTag(ctx, ids[0], refs[0]); err != nil {
+			t.Fatal(err)
+		}
+		// attaching the same tag again is valid
+		if err = m.AttachTag(ctx, ids[0], refs[0]); err != nil {
+			t.Fatal(err)
+		}
+
+		host := simulator.Map.Any("HostSystem")
+
+		tests := []struct {
+			name   string
+			attach func() error
+		}{
+			{"cardinality", func() error { return m.AttachTag(ctx, ids[1], refs[0]) }},
+			{"batch cardinality", func() error { return m.AttachTagToMultipleObjects(ctx, ids[1], refs) }},
+			{"batch tags", func() error { return m.AttachMultipleTagsToObject(ctx, ids, refs[1]) }},
+			{"associable types", func() error { return m.AttachTag(ctx, ids[1], host) }},
+			{"batch associable types", func() error {
+				return m.AttachTagToMultipleObjects(ctx, ids[1], []mo.Reference{refs[1], host})
+			}},
+		}
+
+		for _, test := range tests {
+			err := test.attach()
+			var e *tags.AttachError
+			if !errors.As(err, &e) {
+				t.Errorf("%s: unexpected error %T: %s", test.name, err, err)
+				continue
+			}
+			if e.Tag == nil || e.Category == nil || e.Category.ID != single || e.Reason == "" {
+				t.Errorf("%s: %#v", test.name, e)
+			}
+		}
+
+		// nothing was attached by the failed attempts
+		objs, err := m.ListAttachedObjects(ctx, ids[1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(objs) != 0 {
+			t.Errorf("objects=%v", objs)
+		}
+	})
+}
@@ -0,0 +1,105 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/vmware/govmomi/vapi/internal"
+	"github.com/vmware/govmomi/vapi/rest"
+)
+
+// Manager extends rest.Client, adding compute policy related methods.
+type Manager struct {
+	*rest.Client
+}
+
+// NewManager creates a new Manager instance with the given client.
+func NewManager(client *rest.Client) *Manager {
+	return &Manager{
+		Client: client,
+	}
+}
+
+// Compute policy capabilities
+const (
+	CapabilityVMHostAffinity     = "com.vmware.vcenter.compute.policies.capabilities.vm_host_affinity"
+	CapabilityVMHostAntiAffinity = "com.vmware.vcenter.compute.policies.capabilities.vm_host_anti_affinity"
+	CapabilityVMVMAffinity       = "com.vmware.vcenter.compute.policies.capabilities.vm_vm_affinity"
+	CapabilityVMVMAntiAffinity   = "com.vmware.vcenter.compute.policies.capabilities.vm_vm_anti_affinity"
+	CapabilityDisableDRSVMotion  = "com.vmware.vcenter.compute.policies.capabilities.disable_drs_vmotion"
+)
+
+// Policy describes a compute policy, which applies to the VMs with VMTag attached.
+// The host affinity capabilities also require a HostTag, applying the policy between the tagged VMs and hosts.
+type Policy struct {
+	Policy      string `json:"policy,omitempty"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Capability  string `json:"capability"`
+	VMTag       string `json:"vm_tag,omitempty"`
+	HostTag     string `json:"host_tag,omitempty"`
+}
+
+// Capability describes a compute policy capability.
+type Capability struct {
+	Capability  string `json:"capability,omitempty"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+func (c *Manager) policy(id string) *internal.Resource {
+	return internal.URL(c, internal.ComputePoliciesPath).WithSubpath(id)
+}
+
+// ListPolicies returns the compute policies of this vCenter.
+func (c *Manager) ListPolicies(ctx context.Context) ([]Policy, error) {
+	var res []Policy
+	url := internal.URL(c, internal.ComputePoliciesPath)
+	return res, c.Do(ctx, url.Request(ctx, http.MethodGet), &res)
+}
+
+// GetPolicy returns the given compute policy.
+func (c *Manager) GetPolicy(ctx context.Context, id string) (*Policy, error) {
+	var res Policy
+	return &res, c.Do(ctx, c.policy(id).Request(ctx, http.MethodGet), &res)
+}
+
+// CreatePolicy creates a compute policy, returning its ID.
+func (c *Manager) CreatePolicy(ctx context.Context, spec Policy) (string, error) {
+	var res string
+	url := internal.URL(c, internal.ComputePoliciesPath)
+	return res, c.Do(ctx, url.Request(ctx, http.MethodPost, spec), &res)
+}
+
+// DeletePolicy deletes the given compute policy.
+func (c *Manager) DeletePolicy(ctx context.Context, id string) error {
+	return c.Do(ctx, c.policy(id).Request(ctx, http.MethodDelete), nil)
+}
+
+// ListCapabilities returns the compute policy capabilities supported by this vCenter.
+func (c *Manager) ListCapabilities(ctx context.Context) ([]Capability, error) {
+	var res []Capability
+	return res, c.Do(ctx, c.policy("capabilities").Request(ctx, http.MethodGet), &res)
+}
+
+// GetCapability returns the given compute policy capability.
+func (c *Manager) GetCapability(ctx context.Context, id string) (*Capability, error) {
+	var res Capability
+	return &res, c.Do(ctx, c.policy("capabilities").WithSubpath(id).Request(ctx, http.MethodGet), &res)
+}
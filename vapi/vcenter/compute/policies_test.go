@@ -0,0 +1,111 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vapi/tags"
+	"github.com/vmware/govmomi/vapi/vcenter/compute"
+	"github.com/vmware/govmomi/vim25"
+
+	_ "github.com/vmware/govmomi/vapi/simulator"
+)
+
+func TestPolicies(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+		if err := c.Login(ctx, simulator.DefaultLogin); err != nil {
+			t.Fatal(err)
+		}
+
+		tm := tags.NewManager(c)
+		category, err := tm.CreateCategory(ctx, &tags.Category{Name: "policy", Cardinality: "MULTIPLE"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		tag, err := tm.CreateTag(ctx, &tags.Tag{Name: "anti-affinity", CategoryID: category})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		m := compute.NewManager(c)
+
+		capabilities, err := m.ListCapabilities(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(capabilities) == 0 {
+			t.Fatal("no capabilities")
+		}
+		capability, err := m.GetCapability(ctx, compute.CapabilityVMVMAntiAffinity)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if capability.Name == "" {
+			t.Errorf("capability=%#v", capability)
+		}
+
+		spec := compute.Policy{
+			Name:        "my-policy",
+			Description: "Run tagged VMs on different hosts",
+			Capability:  compute.CapabilityVMVMAntiAffinity,
+			VMTag:       tag,
+		}
+
+		var invalid *rest.InvalidArgument
+		bad := spec
+		bad.Capability = compute.CapabilityVMHostAffinity
+		if _, err = m.CreatePolicy(ctx, bad); !errors.As(err, &invalid) {
+			t.Errorf("unexpected error %T: %s", err, err)
+		}
+
+		id, err := m.CreatePolicy(ctx, spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		policies, err := m.ListPolicies(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(policies) != 1 || policies[0].Policy != id || policies[0].Capability != spec.Capability {
+			t.Errorf("policies=%#v", policies)
+		}
+
+		p, err := m.GetPolicy(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if *p != spec {
+			t.Errorf("policy=%#v", p)
+		}
+
+		if err = m.DeletePolicy(ctx, id); err != nil {
+			t.Fatal(err)
+		}
+
+		var notFound *rest.NotFound
+		if _, err = m.GetPolicy(ctx, id); !errors.As(err, &notFound) {
+			t.Errorf("unexpected error %T: %s", err, err)
+		}
+	})
+}
@@ -0,0 +1,143 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deployment
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/vmware/govmomi/vapi/internal"
+	"github.com/vmware/govmomi/vapi/rest"
+)
+
+// Manager extends rest.Client, adding vCenter Server appliance deployment related methods.
+// These methods target an appliance that has been deployed, but not yet configured by an installer.
+type Manager struct {
+	*rest.Client
+}
+
+// NewManager creates a new Manager instance with the given client.
+func NewManager(client *rest.Client) *Manager {
+	return &Manager{
+		Client: client,
+	}
+}
+
+// Appliance deployment states
+const (
+	StateNotInitialized   = "NOT_INITIALIZED"
+	StateInitialized      = "INITIALIZED"
+	StateConfigInProgress = "CONFIG_IN_PROGRESS"
+	StateQuestionRaised   = "QUESTION_RAISED"
+	StateFailed           = "FAILED"
+	StateConfigured       = "CONFIGURED"
+)
+
+// Deployment operations
+const (
+	OperationInstall = "INSTALL"
+	OperationUpgrade = "UPGRADE"
+)
+
+// Task statuses
+const (
+	StatusPending   = "PENDING"
+	StatusRunning   = "RUNNING"
+	StatusBlocked   = "BLOCKED"
+	StatusSucceeded = "SUCCEEDED"
+	StatusFailed    = "FAILED"
+	StatusPaused    = "PAUSED"
+)
+
+// Check statuses
+const (
+	CheckSuccess = "SUCCESS"
+	CheckFailed  = "FAILED"
+)
+
+// Progress of a task, where Completed is out of Total.
+type Progress struct {
+	Total     int64                   `json:"total"`
+	Completed int64                   `json:"completed"`
+	Message   rest.LocalizableMessage `json:"message"`
+}
+
+// Notification is an info, warning or error message reported by a check or task.
+type Notification struct {
+	ID         string                   `json:"id"`
+	Message    rest.LocalizableMessage  `json:"message"`
+	Resolution *rest.LocalizableMessage `json:"resolution,omitempty"`
+}
+
+// Notifications are the messages reported by a check or task, grouped by severity.
+type Notifications struct {
+	Info     []Notification `json:"info,omitempty"`
+	Warnings []Notification `json:"warnings,omitempty"`
+	Errors   []Notification `json:"errors,omitempty"`
+}
+
+// CheckInfo is the result of validating a deployment spec.
+type CheckInfo struct {
+	Status string         `json:"status"`
+	Result *Notifications `json:"result,omitempty"`
+}
+
+// Info describes the deployment state of the appliance and the progress of the current operation.
+type Info struct {
+	State       string                  `json:"state"`
+	Operation   string                  `json:"operation,omitempty"`
+	Status      string                  `json:"status,omitempty"`
+	Description rest.LocalizableMessage `json:"description"`
+	Progress    *Progress               `json:"progress,omitempty"`
+	Result      *Notifications          `json:"result,omitempty"`
+}
+
+// Question is raised by a deployment operation that requires an answer to continue.
+type Question struct {
+	ID              string                  `json:"id"`
+	Question        rest.LocalizableMessage `json:"question"`
+	Type            string                  `json:"type"`
+	DefaultAnswer   string                  `json:"default_answer"`
+	Text            rest.LocalizableMessage `json:"text"`
+	PossibleAnswers []string                `json:"possible_answers"`
+}
+
+// Get returns the deployment state of the appliance.
+func (c *Manager) Get(ctx context.Context) (*Info, error) {
+	url := internal.URL(c, internal.DeploymentPath)
+	var res Info
+	return &res, c.Do(ctx, url.Request(ctx, http.MethodGet), &res)
+}
+
+// ListQuestions returns the questions raised by the current deployment operation, if its state is StateQuestionRaised.
+func (c *Manager) ListQuestions(ctx context.Context) ([]Question, error) {
+	url := internal.URL(c, internal.DeploymentPath).WithSubpath("question")
+	var res struct {
+		Questions []Question `json:"questions"`
+	}
+	return res.Questions, c.Do(ctx, url.Request(ctx, http.MethodGet), &res)
+}
+
+// Answer answers the given question, which must be one of its PossibleAnswers, allowing the deployment to continue.
+func (c *Manager) Answer(ctx context.Context, id string, answer string) error {
+	url := internal.URL(c, internal.DeploymentPath).WithSubpath("question").WithAction("answer")
+	body := struct {
+		QuestionID string `json:"question_id"`
+		AnswerVal  string `json:"answer_val"`
+	}{id, answer}
+	return c.Do(ctx, url.Request(ctx, http.MethodPost, body), nil)
+}
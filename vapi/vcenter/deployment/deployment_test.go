@@ -0,0 +1,196 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deployment_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vapi/vcenter/deployment"
+	"github.com/vmware/govmomi/vim25"
+
+	_ "github.com/vmware/govmomi/vapi/simulator"
+)
+
+// wait polls the deployment state until the current operation is no longer in progress
+func wait(ctx context.Context, t *testing.T, m *deployment.Manager) *deployment.Info {
+	for {
+		info, err := m.Get(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.State != deployment.StateConfigInProgress {
+			return info
+		}
+	}
+}
+
+func TestInstall(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+		if err := c.Login(ctx, simulator.DefaultLogin); err != nil {
+			t.Fatal(err)
+		}
+
+		m := deployment.NewManager(c)
+
+		info, err := m.Get(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.State != deployment.StateInitialized {
+			t.Errorf("info=%#v", info)
+		}
+
+		spec := deployment.InstallSpec{
+			VCSAEmbedded: &deployment.VCSAEmbeddedSpec{
+				Standalone: &deployment.StandaloneSpec{SSODomainName: "vsphere.local"},
+			},
+		}
+
+		check, err := m.CheckInstall(ctx, spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if check.Status != deployment.CheckFailed || len(check.Result.Errors) != 1 {
+			t.Errorf("check=%#v", check)
+		}
+
+		var ia *rest.InvalidArgument
+		if err = m.StartInstall(ctx, spec); !errors.As(err, &ia) {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		spec.VCSAEmbedded.Standalone.SSOAdminPassword = "secret"
+		if err = m.StartInstall(ctx, spec); err != nil {
+			t.Fatal(err)
+		}
+
+		info = wait(ctx, t, m)
+		if info.State != deployment.StateQuestionRaised || info.Operation != deployment.OperationInstall {
+			t.Errorf("info=%#v", info)
+		}
+
+		questions, err := m.ListQuestions(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(questions) != 1 {
+			t.Fatalf("questions=%#v", questions)
+		}
+		if err = m.Answer(ctx, questions[0].ID, questions[0].DefaultAnswer); err != nil {
+			t.Fatal(err)
+		}
+
+		info = wait(ctx, t, m)
+		if info.State != deployment.StateConfigured || info.Status != deployment.StatusSucceeded {
+			t.Errorf("info=%#v", info)
+		}
+
+		install, err := m.GetInstall(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if install.VCSAEmbedded.Standalone.SSODomainName != "vsphere.local" || install.VCSAEmbedded.Standalone.SSOAdminPassword != "" {
+			t.Errorf("install=%#v", install.VCSAEmbedded.Standalone)
+		}
+
+		if err = m.StartInstall(ctx, spec); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
+
+func TestUpgrade(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+		if err := c.Login(ctx, simulator.DefaultLogin); err != nil {
+			t.Fatal(err)
+		}
+
+		m := deployment.NewManager(c)
+		source := deployment.LocationSpec{Hostname: "vc-old.example.com", Username: "root", Password: "secret"}
+		spec := deployment.UpgradeSpec{
+			SourceVC:       source,
+			SourceLocation: source,
+			History:        &deployment.HistoryMigrationSpec{DataSet: deployment.HistoryAll, DeferImport: true},
+			AutoAnswer:     true,
+		}
+
+		var nf *rest.NotFound
+		if _, err := m.GetImportHistory(ctx); !errors.As(err, &nf) {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		check, err := m.CheckUpgrade(ctx, spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if check.Status != deployment.CheckSuccess {
+			t.Errorf("check=%#v", check)
+		}
+
+		if err = m.StartUpgrade(ctx, spec); err != nil {
+			t.Fatal(err)
+		}
+		upgrade, err := m.GetUpgrade(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if upgrade.SourceVC.Hostname != source.Hostname || upgrade.SourceVC.Password != "" {
+			t.Errorf("upgrade=%#v", upgrade)
+		}
+
+		info := wait(ctx, t, m)
+		if info.State != deployment.StateConfigured || info.Operation != deployment.OperationUpgrade {
+			t.Errorf("info=%#v", info)
+		}
+
+		history, err := m.GetImportHistory(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if history.Status != deployment.StatusPending {
+			t.Errorf("history=%#v", history)
+		}
+
+		if err = m.ResumeImportHistory(ctx); err == nil {
+			t.Error("expected error")
+		}
+		if err = m.StartImportHistory(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if err = m.PauseImportHistory(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if err = m.ResumeImportHistory(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		for history.Status != deployment.StatusSucceeded {
+			if history, err = m.GetImportHistory(ctx); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if history.Progress.Completed != history.Progress.Total {
+			t.Errorf("history=%#v", history.Progress)
+		}
+	})
+}
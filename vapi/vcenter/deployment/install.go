@@ -0,0 +1,80 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deployment
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/vmware/govmomi/vapi/internal"
+)
+
+// StandaloneSpec configures the appliance with a new SSO domain.
+type StandaloneSpec struct {
+	SSOAdminPassword string `json:"sso_admin_password"`
+	SSODomainName    string `json:"sso_domain_name,omitempty"`
+}
+
+// ReplicatedSpec configures the appliance to join the SSO domain of an existing partner vCenter.
+type ReplicatedSpec struct {
+	PartnerHostname  string `json:"partner_hostname"`
+	HTTPSPort        int    `json:"https_port,omitempty"`
+	SSOAdminPassword string `json:"sso_admin_password"`
+	SSLThumbprint    string `json:"ssl_thumbprint,omitempty"`
+	SSLVerify        *bool  `json:"ssl_verify,omitempty"`
+}
+
+// VCSAEmbeddedSpec specifies a vCenter Server with an embedded Platform Services Controller,
+// one of Standalone or Replicated is required.
+type VCSAEmbeddedSpec struct {
+	CEIPEnabled bool            `json:"ceip_enabled"`
+	Standalone  *StandaloneSpec `json:"standalone,omitempty"`
+	Replicated  *ReplicatedSpec `json:"replicated,omitempty"`
+}
+
+// InstallSpec specifies the configuration of a new appliance.
+// If AutoAnswer is true, questions raised during the install are answered with their default answer.
+type InstallSpec struct {
+	VCSAEmbedded *VCSAEmbeddedSpec `json:"vcsa_embedded"`
+	AutoAnswer   bool              `json:"auto_answer,omitempty"`
+}
+
+func (c *Manager) install() *internal.Resource {
+	return internal.URL(c, internal.DeploymentPath).WithSubpath("install")
+}
+
+// GetInstall returns the spec of the install in progress, with passwords omitted.
+func (c *Manager) GetInstall(ctx context.Context) (*InstallSpec, error) {
+	var res InstallSpec
+	return &res, c.Do(ctx, c.install().Request(ctx, http.MethodGet), &res)
+}
+
+// CheckInstall validates the given spec without starting the install.
+func (c *Manager) CheckInstall(ctx context.Context, spec InstallSpec) (*CheckInfo, error) {
+	var res CheckInfo
+	return &res, c.Do(ctx, c.install().WithAction("check").Request(ctx, http.MethodPost, spec), &res)
+}
+
+// StartInstall starts configuring the appliance with the given spec, use Get to monitor its progress.
+func (c *Manager) StartInstall(ctx context.Context, spec InstallSpec) error {
+	return c.Do(ctx, c.install().WithAction("start").Request(ctx, http.MethodPost, spec), nil)
+}
+
+// CancelInstall cancels the install in progress.
+func (c *Manager) CancelInstall(ctx context.Context) error {
+	return c.Do(ctx, c.install().WithAction("cancel").Request(ctx, http.MethodPost), nil)
+}
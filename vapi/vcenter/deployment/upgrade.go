@@ -0,0 +1,112 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deployment
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/vmware/govmomi/vapi/internal"
+)
+
+// History data sets to migrate from the source vCenter
+const (
+	HistoryEventsTasks = "EVENTS_TASKS"
+	HistoryAll         = "ALL"
+)
+
+// LocationSpec specifies how to connect to a vCenter or ESXi host.
+type LocationSpec struct {
+	Hostname      string `json:"hostname"`
+	HTTPSPort     int    `json:"https_port,omitempty"`
+	SSLThumbprint string `json:"ssl_thumbprint,omitempty"`
+	SSLVerify     *bool  `json:"ssl_verify,omitempty"`
+	Username      string `json:"username"`
+	Password      string `json:"password"`
+}
+
+// HistoryMigrationSpec specifies the historical data to migrate from the source vCenter.
+// If DeferImport is true, the data is imported after the upgrade, see StartImportHistory.
+type HistoryMigrationSpec struct {
+	DataSet     string `json:"data_set"`
+	DeferImport bool   `json:"defer_import,omitempty"`
+}
+
+// UpgradeSpec specifies the source vCenter appliance to upgrade or migrate to this appliance.
+// SourceLocation is the ESXi host or vCenter managing the source appliance VM.
+type UpgradeSpec struct {
+	SourceVC       LocationSpec          `json:"source_vc_spec"`
+	SourceLocation LocationSpec          `json:"source_location"`
+	History        *HistoryMigrationSpec `json:"history,omitempty"`
+	AutoAnswer     bool                  `json:"auto_answer,omitempty"`
+}
+
+func (c *Manager) upgrade() *internal.Resource {
+	return internal.URL(c, internal.DeploymentPath).WithSubpath("upgrade")
+}
+
+// GetUpgrade returns the spec of the upgrade in progress, with passwords omitted.
+func (c *Manager) GetUpgrade(ctx context.Context) (*UpgradeSpec, error) {
+	var res UpgradeSpec
+	return &res, c.Do(ctx, c.upgrade().Request(ctx, http.MethodGet), &res)
+}
+
+// CheckUpgrade validates the given spec, including connectivity to the source, without starting the upgrade.
+func (c *Manager) CheckUpgrade(ctx context.Context, spec UpgradeSpec) (*CheckInfo, error) {
+	var res CheckInfo
+	return &res, c.Do(ctx, c.upgrade().WithAction("check").Request(ctx, http.MethodPost, spec), &res)
+}
+
+// StartUpgrade starts upgrading the source appliance to this appliance, use Get to monitor its progress.
+func (c *Manager) StartUpgrade(ctx context.Context, spec UpgradeSpec) error {
+	return c.Do(ctx, c.upgrade().WithAction("start").Request(ctx, http.MethodPost, spec), nil)
+}
+
+// CancelUpgrade cancels the upgrade in progress.
+func (c *Manager) CancelUpgrade(ctx context.Context) error {
+	return c.Do(ctx, c.upgrade().WithAction("cancel").Request(ctx, http.MethodPost), nil)
+}
+
+func (c *Manager) importHistory() *internal.Resource {
+	return internal.URL(c, internal.DeploymentPath).WithSubpath("import-history")
+}
+
+// GetImportHistory returns the status of the deferred historical data import.
+func (c *Manager) GetImportHistory(ctx context.Context) (*Info, error) {
+	var res Info
+	return &res, c.Do(ctx, c.importHistory().Request(ctx, http.MethodGet), &res)
+}
+
+// StartImportHistory starts importing the historical data that was deferred during an upgrade.
+func (c *Manager) StartImportHistory(ctx context.Context) error {
+	return c.Do(ctx, c.importHistory().WithAction("start").Request(ctx, http.MethodPost), nil)
+}
+
+// PauseImportHistory pauses the historical data import.
+func (c *Manager) PauseImportHistory(ctx context.Context) error {
+	return c.Do(ctx, c.importHistory().WithAction("pause").Request(ctx, http.MethodPost), nil)
+}
+
+// ResumeImportHistory resumes a paused historical data import.
+func (c *Manager) ResumeImportHistory(ctx context.Context) error {
+	return c.Do(ctx, c.importHistory().WithAction("resume").Request(ctx, http.MethodPost), nil)
+}
+
+// CancelImportHistory cancels the historical data import, the data that was not yet imported is discarded.
+func (c *Manager) CancelImportHistory(ctx context.Context) error {
+	return c.Do(ctx, c.importHistory().WithAction("cancel").Request(ctx, http.MethodPost), nil)
+}
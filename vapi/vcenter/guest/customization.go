@@ -0,0 +1,234 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package guest
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/vmware/govmomi/vapi/internal"
+	"github.com/vmware/govmomi/vapi/rest"
+)
+
+// Manager extends rest.Client, adding guest customization related methods.
+type Manager struct {
+	*rest.Client
+}
+
+// NewManager creates a new Manager instance with the given client.
+func NewManager(client *rest.Client) *Manager {
+	return &Manager{
+		Client: client,
+	}
+}
+
+// Guest OS types
+const (
+	OSTypeLinux   = "LINUX"
+	OSTypeWindows = "WINDOWS"
+)
+
+// Customization spec export formats
+const (
+	FormatJSON = "JSON"
+	FormatXML  = "XML"
+)
+
+// Hostname generator types
+const (
+	HostnameFixed             = "FIXED"
+	HostnamePrefix            = "PREFIX"
+	HostnameVirtualMachine    = "VIRTUAL_MACHINE"
+	HostnameUserInputRequired = "USER_INPUT_REQUIRED"
+)
+
+// IPv4 settings types
+const (
+	IPv4DHCP              = "DHCP"
+	IPv4Static            = "STATIC"
+	IPv4UserInputRequired = "USER_INPUT_REQUIRED"
+)
+
+// Windows domain membership types
+const (
+	WindowsDomainWorkgroup    = "WORKGROUP"
+	WindowsDomainDomain       = "DOMAIN"
+	WindowsDomainUnchanged    = "UNCHANGED"
+	WindowsDomainUnconfigured = "UNCONFIGURED"
+)
+
+// HostnameGenerator specifies how the guest hostname or computer name is generated.
+// FixedName is required for HostnameFixed and Prefix for HostnamePrefix.
+type HostnameGenerator struct {
+	Type      string `json:"type"`
+	FixedName string `json:"fixed_name,omitempty"`
+	Prefix    string `json:"prefix,omitempty"`
+}
+
+// LinuxConfiguration specifies the customization of a Linux guest.
+type LinuxConfiguration struct {
+	Hostname   *HostnameGenerator `json:"hostname,omitempty"`
+	Domain     string             `json:"domain,omitempty"`
+	TimeZone   string             `json:"time_zone,omitempty"`
+	ScriptText string             `json:"script_text,omitempty"`
+}
+
+// WindowsUserData specifies the Windows computer name and registration information.
+type WindowsUserData struct {
+	ComputerName HostnameGenerator `json:"computer_name"`
+	FullName     string            `json:"full_name"`
+	Organization string            `json:"organization"`
+	ProductKey   string            `json:"product_key,omitempty"`
+}
+
+// WindowsDomain specifies the workgroup or domain a Windows guest joins.
+type WindowsDomain struct {
+	Type           string `json:"type"`
+	Workgroup      string `json:"workgroup,omitempty"`
+	Domain         string `json:"domain,omitempty"`
+	DomainUsername string `json:"domain_username,omitempty"`
+	DomainPassword string `json:"domain_password,omitempty"`
+}
+
+// WindowsSysprep specifies the sysprep answers of a Windows guest.
+type WindowsSysprep struct {
+	UserData       WindowsUserData `json:"user_data"`
+	Domain         *WindowsDomain  `json:"domain,omitempty"`
+	Password       string          `json:"password,omitempty"`
+	AutoLogon      bool            `json:"auto_logon,omitempty"`
+	AutoLogonCount int             `json:"auto_logon_count,omitempty"`
+	TimeZone       int             `json:"time_zone,omitempty"`
+}
+
+// WindowsConfiguration specifies the customization of a Windows guest.
+type WindowsConfiguration struct {
+	Reboot  string          `json:"reboot,omitempty"`
+	Sysprep *WindowsSysprep `json:"sysprep,omitempty"`
+}
+
+// ConfigurationSpec specifies the guest OS customization, one of LinuxConfig or WindowsConfig is required.
+type ConfigurationSpec struct {
+	LinuxConfig   *LinuxConfiguration   `json:"linux_config,omitempty"`
+	WindowsConfig *WindowsConfiguration `json:"windows_config,omitempty"`
+}
+
+// GlobalDNSSettings specifies the DNS settings that apply to all network adapters of a guest.
+type GlobalDNSSettings struct {
+	DNSSuffixList []string `json:"dns_suffix_list,omitempty"`
+	DNSServers    []string `json:"dns_servers,omitempty"`
+}
+
+// IPv4 specifies the IPv4 settings of a network adapter. IPAddress, Prefix and Gateways apply to IPv4Static only.
+type IPv4 struct {
+	Type      string   `json:"type"`
+	IPAddress string   `json:"ip_address,omitempty"`
+	Prefix    int      `json:"prefix,omitempty"`
+	Gateways  []string `json:"gateways,omitempty"`
+}
+
+// IPSettings specifies the IP settings of a network adapter.
+type IPSettings struct {
+	IPv4       *IPv4    `json:"ipv4,omitempty"`
+	DNSServers []string `json:"dns_servers,omitempty"`
+}
+
+// InterfaceMapping applies IP settings to the network adapter with the given MAC address,
+// or to adapters in order if MacAddress is empty.
+type InterfaceMapping struct {
+	MacAddress string     `json:"mac_address,omitempty"`
+	Adapter    IPSettings `json:"adapter"`
+}
+
+// Spec is a guest customization specification.
+type Spec struct {
+	ConfigurationSpec ConfigurationSpec  `json:"configuration_spec"`
+	GlobalDNSSettings GlobalDNSSettings  `json:"global_DNS_settings"`
+	Interfaces        []InterfaceMapping `json:"interfaces"`
+}
+
+// CustomizationSpecSummary describes a customization spec.
+type CustomizationSpecSummary struct {
+	Name         string    `json:"name"`
+	Description  string    `json:"description"`
+	OSType       string    `json:"OS_type"`
+	LastModified time.Time `json:"last_modified"`
+}
+
+// CustomizationSpec is a named guest customization spec.
+// Fingerprint is returned by GetCustomizationSpec and must be passed to SetCustomizationSpec,
+// which fails if the spec was modified in the meantime.
+type CustomizationSpec struct {
+	Name         string     `json:"name"`
+	Description  string     `json:"description"`
+	Fingerprint  string     `json:"fingerprint,omitempty"`
+	LastModified *time.Time `json:"last_modified,omitempty"`
+	Spec         Spec       `json:"spec"`
+}
+
+func (c *Manager) customizationSpec(name string) *internal.Resource {
+	return internal.URL(c, internal.CustomizationSpecsPath).WithSubpath(name)
+}
+
+// ListCustomizationSpecs returns the customization specs registered with vCenter.
+func (c *Manager) ListCustomizationSpecs(ctx context.Context) ([]CustomizationSpecSummary, error) {
+	url := internal.URL(c, internal.CustomizationSpecsPath)
+	var res []CustomizationSpecSummary
+	return res, c.Do(ctx, url.Request(ctx, http.MethodGet), &res)
+}
+
+// GetCustomizationSpec returns the given customization spec.
+func (c *Manager) GetCustomizationSpec(ctx context.Context, name string) (*CustomizationSpec, error) {
+	var res CustomizationSpec
+	return &res, c.Do(ctx, c.customizationSpec(name).Request(ctx, http.MethodGet), &res)
+}
+
+// CreateCustomizationSpec creates the given customization spec.
+func (c *Manager) CreateCustomizationSpec(ctx context.Context, spec CustomizationSpec) error {
+	url := internal.URL(c, internal.CustomizationSpecsPath)
+	return c.Do(ctx, url.Request(ctx, http.MethodPost, spec), nil)
+}
+
+// SetCustomizationSpec replaces the given customization spec, which can also be renamed via spec.Name.
+func (c *Manager) SetCustomizationSpec(ctx context.Context, name string, spec CustomizationSpec) error {
+	return c.Do(ctx, c.customizationSpec(name).Request(ctx, http.MethodPut, spec), nil)
+}
+
+// DeleteCustomizationSpec deletes the given customization spec.
+func (c *Manager) DeleteCustomizationSpec(ctx context.Context, name string) error {
+	return c.Do(ctx, c.customizationSpec(name).Request(ctx, http.MethodDelete), nil)
+}
+
+// ExportCustomizationSpec returns the given customization spec in FormatJSON or FormatXML,
+// which can be imported into another vCenter using ImportCustomizationSpec.
+func (c *Manager) ExportCustomizationSpec(ctx context.Context, name string, format string) (string, error) {
+	body := struct {
+		Format string `json:"format"`
+	}{format}
+	var res string
+	return res, c.Do(ctx, c.customizationSpec(name).WithAction("export").Request(ctx, http.MethodPost, body), &res)
+}
+
+// ImportCustomizationSpec converts an exported customization spec, returning the spec to pass to CreateCustomizationSpec.
+func (c *Manager) ImportCustomizationSpec(ctx context.Context, content string) (*CustomizationSpec, error) {
+	url := internal.URL(c, internal.CustomizationSpecsPath).WithAction("import")
+	body := struct {
+		Spec string `json:"spec"`
+	}{content}
+	var res CustomizationSpec
+	return &res, c.Do(ctx, url.Request(ctx, http.MethodPost, body), &res)
+}
@@ -0,0 +1,140 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package guest_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vapi/vcenter/guest"
+	"github.com/vmware/govmomi/vim25"
+
+	_ "github.com/vmware/govmomi/vapi/simulator"
+)
+
+// jsonContentType fails the test if a PUT request is sent without the JSON Content-Type.
+func jsonContentType(t *testing.T) rest.Middleware {
+	return func(next rest.Handler) rest.Handler {
+		return func(ctx context.Context, req *http.Request, resBody interface{}) error {
+			if req.Method == http.MethodPut && req.Header.Get("Content-Type") != "application/json" {
+				t.Errorf("PUT %s: Content-Type=%q", req.URL.Path, req.Header.Get("Content-Type"))
+			}
+			return next(ctx, req, resBody)
+		}
+	}
+}
+
+func TestCustomizationSpecs(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc, rest.WithMiddleware(jsonContentType(t)))
+		if err := c.Login(ctx, simulator.DefaultLogin); err != nil {
+			t.Fatal(err)
+		}
+
+		m := guest.NewManager(c)
+
+		spec := guest.CustomizationSpec{
+			Name:        "linux web",
+			Description: "web servers",
+			Spec: guest.Spec{
+				ConfigurationSpec: guest.ConfigurationSpec{
+					LinuxConfig: &guest.LinuxConfiguration{
+						Hostname: &guest.HostnameGenerator{Type: guest.HostnamePrefix, Prefix: "web-"},
+						Domain:   "example.com",
+					},
+				},
+				GlobalDNSSettings: guest.GlobalDNSSettings{DNSServers: []string{"10.0.0.2"}},
+				Interfaces: []guest.InterfaceMapping{
+					{Adapter: guest.IPSettings{IPv4: &guest.IPv4{Type: guest.IPv4DHCP}}},
+				},
+			},
+		}
+
+		if err := m.CreateCustomizationSpec(ctx, spec); err != nil {
+			t.Fatal(err)
+		}
+
+		var ae *rest.AlreadyExists
+		if err := m.CreateCustomizationSpec(ctx, spec); !errors.As(err, &ae) {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		var ia *rest.InvalidArgument
+		invalid := spec
+		invalid.Name = "invalid"
+		invalid.Spec.Interfaces = []guest.InterfaceMapping{
+			{Adapter: guest.IPSettings{IPv4: &guest.IPv4{Type: guest.IPv4Static}}},
+		}
+		if err := m.CreateCustomizationSpec(ctx, invalid); !errors.As(err, &ia) {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		specs, err := m.ListCustomizationSpecs(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(specs) != 1 || specs[0].Name != spec.Name || specs[0].OSType != guest.OSTypeLinux {
+			t.Errorf("specs=%#v", specs)
+		}
+
+		current, err := m.GetCustomizationSpec(ctx, spec.Name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if current.Fingerprint == "" || current.Spec.ConfigurationSpec.LinuxConfig.Domain != "example.com" {
+			t.Errorf("spec=%#v", current)
+		}
+
+		update := *current
+		update.Description = "updated"
+		if err = m.SetCustomizationSpec(ctx, spec.Name, update); err != nil {
+			t.Fatal(err)
+		}
+		// the fingerprint changed with the previous update
+		if err = m.SetCustomizationSpec(ctx, spec.Name, update); err == nil {
+			t.Error("expected error")
+		}
+
+		export, err := m.ExportCustomizationSpec(ctx, spec.Name, guest.FormatJSON)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = m.DeleteCustomizationSpec(ctx, spec.Name); err != nil {
+			t.Fatal(err)
+		}
+
+		var nf *rest.NotFound
+		if _, err = m.GetCustomizationSpec(ctx, spec.Name); !errors.As(err, &nf) {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		imported, err := m.ImportCustomizationSpec(ctx, export)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if imported.Name != spec.Name || imported.Description != "updated" {
+			t.Errorf("imported=%#v", imported)
+		}
+		if err = m.CreateCustomizationSpec(ctx, *imported); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
@@ -0,0 +1,148 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package identity
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/vmware/govmomi/vapi/internal"
+	"github.com/vmware/govmomi/vapi/rest"
+)
+
+// Manager extends rest.Client, adding identity provider federation related methods.
+type Manager struct {
+	*rest.Client
+}
+
+// NewManager creates a new Manager instance with the given client.
+func NewManager(client *rest.Client) *Manager {
+	return &Manager{
+		Client: client,
+	}
+}
+
+// Identity provider configuration types
+const (
+	ConfigTypeOAuth2 = "Oauth2"
+	ConfigTypeOIDC   = "Oidc"
+)
+
+// Identity management protocols, used to query users and groups of the provider
+const (
+	IDMProtocolREST  = "REST"
+	IDMProtocolSCIM  = "SCIM"
+	IDMProtocolSCIM2 = "SCIM2_0"
+	IDMProtocolLDAP  = "LDAP"
+)
+
+// ClaimMap maps a vCenter group to token claims, keyed by claim category then claim name.
+type ClaimMap map[string]map[string][]string
+
+// OAuth2 is the configuration of an OAuth2 identity provider, such as ADFS.
+type OAuth2 struct {
+	AuthEndpoint         string              `json:"auth_endpoint,omitempty"`
+	TokenEndpoint        string              `json:"token_endpoint,omitempty"`
+	PublicKeyURI         string              `json:"public_key_uri,omitempty"`
+	ClientID             string              `json:"client_id,omitempty"`
+	ClientSecret         string              `json:"client_secret,omitempty"`
+	ClaimMap             ClaimMap            `json:"claim_map,omitempty"`
+	Issuer               string              `json:"issuer,omitempty"`
+	AuthenticationMethod string              `json:"authentication_method,omitempty"`
+	AuthQueryParams      map[string][]string `json:"auth_query_params,omitempty"`
+}
+
+// OIDC is the configuration of an OpenID Connect identity provider,
+// where the endpoints are discovered from the DiscoveryEndpoint.
+type OIDC struct {
+	DiscoveryEndpoint string   `json:"discovery_endpoint,omitempty"`
+	LogoutEndpoint    string   `json:"logout_endpoint,omitempty"`
+	AuthEndpoint      string   `json:"auth_endpoint,omitempty"`
+	TokenEndpoint     string   `json:"token_endpoint,omitempty"`
+	PublicKeyURI      string   `json:"public_key_uri,omitempty"`
+	ClientID          string   `json:"client_id,omitempty"`
+	ClientSecret      string   `json:"client_secret,omitempty"`
+	ClaimMap          ClaimMap `json:"claim_map,omitempty"`
+	Issuer            string   `json:"issuer,omitempty"`
+}
+
+// Provider describes an identity provider, where ConfigTag determines which of OAuth2 or OIDC is set.
+// The client secret of a provider is never returned.
+type Provider struct {
+	Provider        string              `json:"provider,omitempty"`
+	Name            string              `json:"name,omitempty"`
+	ConfigTag       string              `json:"config_tag"`
+	OAuth2          *OAuth2             `json:"oauth2,omitempty"`
+	OIDC            *OIDC               `json:"oidc,omitempty"`
+	IsDefault       bool                `json:"is_default"`
+	DomainNames     []string            `json:"domain_names,omitempty"`
+	AuthQueryParams map[string][]string `json:"auth_query_params,omitempty"`
+	IDMProtocol     string              `json:"idm_protocol,omitempty"`
+	UPNClaim        string              `json:"upn_claim,omitempty"`
+	GroupsClaim     string              `json:"groups_claim,omitempty"`
+}
+
+// UpdateSpec specifies the identity provider fields to update, fields not set are left unchanged.
+type UpdateSpec struct {
+	ConfigTag           string              `json:"config_tag"`
+	OAuth2              *OAuth2             `json:"oauth2,omitempty"`
+	OIDC                *OIDC               `json:"oidc,omitempty"`
+	MakeDefault         bool                `json:"make_default,omitempty"`
+	Name                string              `json:"name,omitempty"`
+	DomainNamesToAdd    []string            `json:"domain_names_to_add,omitempty"`
+	DomainNamesToRemove []string            `json:"domain_names_to_remove,omitempty"`
+	AuthQueryParams     map[string][]string `json:"auth_query_params,omitempty"`
+	IDMProtocol         string              `json:"idm_protocol,omitempty"`
+	UPNClaim            string              `json:"upn_claim,omitempty"`
+	ResetUPNClaim       bool                `json:"reset_upn_claim,omitempty"`
+	GroupsClaim         string              `json:"groups_claim,omitempty"`
+	ResetGroupsClaim    bool                `json:"reset_groups_claim,omitempty"`
+}
+
+func (c *Manager) provider(id string) *internal.Resource {
+	return internal.URL(c, internal.IdentityProvidersPath).WithSubpath(id)
+}
+
+// ListProviders returns the identity providers of this vCenter.
+func (c *Manager) ListProviders(ctx context.Context) ([]Provider, error) {
+	var res []Provider
+	url := internal.URL(c, internal.IdentityProvidersPath)
+	return res, c.Do(ctx, url.Request(ctx, http.MethodGet), &res)
+}
+
+// GetProvider returns the given identity provider.
+func (c *Manager) GetProvider(ctx context.Context, id string) (*Provider, error) {
+	var res Provider
+	return &res, c.Do(ctx, c.provider(id).Request(ctx, http.MethodGet), &res)
+}
+
+// CreateProvider creates an identity provider, returning its ID.
+func (c *Manager) CreateProvider(ctx context.Context, spec Provider) (string, error) {
+	var res string
+	url := internal.URL(c, internal.IdentityProvidersPath)
+	return res, c.Do(ctx, url.Request(ctx, http.MethodPost, spec), &res)
+}
+
+// UpdateProvider updates the given identity provider.
+func (c *Manager) UpdateProvider(ctx context.Context, id string, spec UpdateSpec) error {
+	return c.Do(ctx, c.provider(id).Request(ctx, http.MethodPatch, spec), nil)
+}
+
+// DeleteProvider deletes the given identity provider.
+func (c *Manager) DeleteProvider(ctx context.Context, id string) error {
+	return c.Do(ctx, c.provider(id).Request(ctx, http.MethodDelete), nil)
+}
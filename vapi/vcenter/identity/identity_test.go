@@ -0,0 +1,144 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package identity_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vapi/vcenter/identity"
+	"github.com/vmware/govmomi/vim25"
+
+	_ "github.com/vmware/govmomi/vapi/simulator"
+)
+
+func TestProviders(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+		if err := c.Login(ctx, simulator.DefaultLogin); err != nil {
+			t.Fatal(err)
+		}
+
+		m := identity.NewManager(c)
+
+		providers, err := m.ListProviders(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(providers) != 0 {
+			t.Errorf("providers=%#v", providers)
+		}
+
+		adfs := identity.Provider{
+			Name:      "adfs",
+			ConfigTag: identity.ConfigTypeOAuth2,
+			OAuth2: &identity.OAuth2{
+				AuthEndpoint:  "https://adfs.example.com/adfs/oauth2/authorize",
+				TokenEndpoint: "https://adfs.example.com/adfs/oauth2/token",
+				ClientID:      "vcenter",
+				ClientSecret:  "secret",
+				ClaimMap:      identity.ClaimMap{"Administrators": {"group": {"vc-admins"}}},
+			},
+			IsDefault:   true,
+			DomainNames: []string{"example.com"},
+			UPNClaim:    "upn",
+		}
+
+		var invalid *rest.InvalidArgument
+		spec := adfs
+		spec.ConfigTag = identity.ConfigTypeOIDC
+		if _, err = m.CreateProvider(ctx, spec); !errors.As(err, &invalid) {
+			t.Errorf("unexpected error %T: %s", err, err)
+		}
+
+		id, err := m.CreateProvider(ctx, adfs)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var exists *rest.AlreadyExists
+		if _, err = m.CreateProvider(ctx, adfs); !errors.As(err, &exists) {
+			t.Errorf("unexpected error %T: %s", err, err)
+		}
+
+		p, err := m.GetProvider(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.Provider != id || !p.IsDefault || p.OAuth2 == nil || p.OAuth2.ClientSecret != "" {
+			t.Errorf("provider=%#v", p)
+		}
+		if !reflect.DeepEqual(p.OAuth2.ClaimMap, adfs.OAuth2.ClaimMap) {
+			t.Errorf("claims=%#v", p.OAuth2.ClaimMap)
+		}
+
+		oidc := identity.Provider{
+			Name:      "oidc",
+			ConfigTag: identity.ConfigTypeOIDC,
+			OIDC: &identity.OIDC{
+				DiscoveryEndpoint: "https://oidc.example.com/.well-known/openid-configuration",
+				ClientID:          "vcenter",
+			},
+		}
+		oid, err := m.CreateProvider(ctx, oidc)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		update := identity.UpdateSpec{
+			ConfigTag:           identity.ConfigTypeOIDC,
+			MakeDefault:         true,
+			DomainNamesToAdd:    []string{"example.org", "example.net"},
+			DomainNamesToRemove: []string{"example.net"},
+			GroupsClaim:         "groups",
+		}
+		if err = m.UpdateProvider(ctx, oid, update); err != nil {
+			t.Fatal(err)
+		}
+
+		providers, err = m.ListProviders(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(providers) != 2 {
+			t.Fatalf("providers=%#v", providers)
+		}
+		for _, p := range providers {
+			if p.IsDefault != (p.Provider == oid) {
+				t.Errorf("provider=%#v", p)
+			}
+			if p.Provider == oid && (p.GroupsClaim != "groups" || !reflect.DeepEqual(p.DomainNames, []string{"example.org"})) {
+				t.Errorf("provider=%#v", p)
+			}
+		}
+
+		for _, id := range []string{id, oid} {
+			if err = m.DeleteProvider(ctx, id); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		var notFound *rest.NotFound
+		if _, err = m.GetProvider(ctx, id); !errors.As(err, &notFound) {
+			t.Errorf("unexpected error %T: %s", err, err)
+		}
+	})
+}
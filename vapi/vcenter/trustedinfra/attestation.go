@@ -0,0 +1,127 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trustedinfra
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/vmware/govmomi/vapi/internal"
+)
+
+// EndorsementKey is a trusted TPM 2.0 endorsement key, used by the Attestation Service to verify ESXi hosts.
+// One of the PEM encoded PublicKey or Certificate is required.
+type EndorsementKey struct {
+	Name        string `json:"name"`
+	PublicKey   string `json:"public_key,omitempty"`
+	Certificate string `json:"certificate,omitempty"`
+}
+
+// ServiceSpec registers a Trust Authority service, an Attestation Service or Key Provider Service,
+// with the trusted clusters of this vCenter.
+type ServiceSpec struct {
+	Address               NetworkAddress `json:"address"`
+	TrustedCA             X509CertChain  `json:"trusted_CA"`
+	Group                 string         `json:"group,omitempty"`
+	TrustAuthorityCluster string         `json:"trust_authority_cluster,omitempty"`
+}
+
+// ServiceSummary describes a registered Trust Authority service.
+type ServiceSummary struct {
+	Service               string         `json:"service"`
+	Address               NetworkAddress `json:"address"`
+	Group                 string         `json:"group,omitempty"`
+	TrustAuthorityCluster string         `json:"trust_authority_cluster,omitempty"`
+}
+
+func (c *Manager) endorsementKeys(cluster string) *internal.Resource {
+	return c.trustAuthorityCluster(cluster).WithSubpath("attestation/tpm2/endorsement-keys")
+}
+
+// ListEndorsementKeys returns the names of the trusted TPM endorsement keys of the given Trust Authority cluster.
+func (c *Manager) ListEndorsementKeys(ctx context.Context, cluster string) ([]string, error) {
+	var res []struct {
+		Name string `json:"name"`
+	}
+	if err := c.Do(ctx, c.endorsementKeys(cluster).Request(ctx, http.MethodGet), &res); err != nil {
+		return nil, err
+	}
+	names := make([]string, len(res))
+	for i := range res {
+		names[i] = res[i].Name
+	}
+	return names, nil
+}
+
+// GetEndorsementKey returns the given trusted TPM endorsement key.
+func (c *Manager) GetEndorsementKey(ctx context.Context, cluster string, name string) (*EndorsementKey, error) {
+	var res EndorsementKey
+	return &res, c.Do(ctx, c.endorsementKeys(cluster).WithSubpath(name).Request(ctx, http.MethodGet), &res)
+}
+
+// CreateEndorsementKey adds a trusted TPM endorsement key to the given Trust Authority cluster.
+func (c *Manager) CreateEndorsementKey(ctx context.Context, cluster string, key EndorsementKey) error {
+	return c.Do(ctx, c.endorsementKeys(cluster).Request(ctx, http.MethodPost, key), nil)
+}
+
+// DeleteEndorsementKey removes the given trusted TPM endorsement key.
+func (c *Manager) DeleteEndorsementKey(ctx context.Context, cluster string, name string) error {
+	return c.Do(ctx, c.endorsementKeys(cluster).WithSubpath(name).Request(ctx, http.MethodDelete), nil)
+}
+
+func (c *Manager) services(kind string) *internal.Resource {
+	return c.resource(kind).WithSubpath("services")
+}
+
+func (c *Manager) listServices(ctx context.Context, kind string) ([]ServiceSummary, error) {
+	var res []ServiceSummary
+	return res, c.Do(ctx, c.services(kind).Request(ctx, http.MethodGet), &res)
+}
+
+func (c *Manager) getService(ctx context.Context, kind string, id string) (*ServiceSpec, error) {
+	var res ServiceSpec
+	return &res, c.Do(ctx, c.services(kind).WithSubpath(id).Request(ctx, http.MethodGet), &res)
+}
+
+func (c *Manager) createService(ctx context.Context, kind string, spec ServiceSpec) (string, error) {
+	var res string
+	return res, c.Do(ctx, c.services(kind).Request(ctx, http.MethodPost, spec), &res)
+}
+
+func (c *Manager) deleteService(ctx context.Context, kind string, id string) error {
+	return c.Do(ctx, c.services(kind).WithSubpath(id).Request(ctx, http.MethodDelete), nil)
+}
+
+// ListAttestationServices returns the registered Attestation Services.
+func (c *Manager) ListAttestationServices(ctx context.Context) ([]ServiceSummary, error) {
+	return c.listServices(ctx, "attestation")
+}
+
+// GetAttestationService returns the given Attestation Service.
+func (c *Manager) GetAttestationService(ctx context.Context, id string) (*ServiceSpec, error) {
+	return c.getService(ctx, "attestation", id)
+}
+
+// CreateAttestationService registers an Attestation Service, returning its ID.
+func (c *Manager) CreateAttestationService(ctx context.Context, spec ServiceSpec) (string, error) {
+	return c.createService(ctx, "attestation", spec)
+}
+
+// DeleteAttestationService removes the given Attestation Service registration.
+func (c *Manager) DeleteAttestationService(ctx context.Context, id string) error {
+	return c.deleteService(ctx, "attestation", id)
+}
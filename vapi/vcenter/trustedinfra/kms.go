@@ -0,0 +1,109 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trustedinfra
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/vmware/govmomi/vapi/internal"
+)
+
+// KeyServerTypeKMIP is the type of a KMIP compliant key server.
+const KeyServerTypeKMIP = "KMIP"
+
+// KMIPServer is a KMIP compliant key server.
+type KMIPServer struct {
+	Name    string         `json:"name"`
+	Address NetworkAddress `json:"address"`
+}
+
+// KMIPServerSpec specifies the KMIP servers of a key provider, which are expected to replicate keys between them.
+type KMIPServerSpec struct {
+	Servers  []KMIPServer `json:"servers"`
+	Username string       `json:"username,omitempty"`
+}
+
+// KeyServer specifies the key servers of a key provider.
+type KeyServer struct {
+	Type        string          `json:"type"`
+	Description string          `json:"description,omitempty"`
+	KMIPServer  *KMIPServerSpec `json:"kmip_server,omitempty"`
+}
+
+// KMSProvider is a key provider of a Trust Authority cluster, where MasterKeyID is the key
+// used to encrypt the keys provided to trusted hosts.
+type KMSProvider struct {
+	Provider    string    `json:"provider"`
+	MasterKeyID string    `json:"master_key_id"`
+	KeyServer   KeyServer `json:"key_server"`
+}
+
+func (c *Manager) kmsProviders(cluster string) *internal.Resource {
+	return c.trustAuthorityCluster(cluster).WithSubpath("kms/providers")
+}
+
+// ListKMSProviders returns the names of the key providers of the given Trust Authority cluster.
+func (c *Manager) ListKMSProviders(ctx context.Context, cluster string) ([]string, error) {
+	var res []struct {
+		Provider string `json:"provider"`
+	}
+	if err := c.Do(ctx, c.kmsProviders(cluster).Request(ctx, http.MethodGet), &res); err != nil {
+		return nil, err
+	}
+	names := make([]string, len(res))
+	for i := range res {
+		names[i] = res[i].Provider
+	}
+	return names, nil
+}
+
+// GetKMSProvider returns the given key provider.
+func (c *Manager) GetKMSProvider(ctx context.Context, cluster string, provider string) (*KMSProvider, error) {
+	var res KMSProvider
+	return &res, c.Do(ctx, c.kmsProviders(cluster).WithSubpath(provider).Request(ctx, http.MethodGet), &res)
+}
+
+// CreateKMSProvider adds a key provider to the given Trust Authority cluster.
+func (c *Manager) CreateKMSProvider(ctx context.Context, cluster string, provider KMSProvider) error {
+	return c.Do(ctx, c.kmsProviders(cluster).Request(ctx, http.MethodPost, provider), nil)
+}
+
+// DeleteKMSProvider removes the given key provider.
+func (c *Manager) DeleteKMSProvider(ctx context.Context, cluster string, provider string) error {
+	return c.Do(ctx, c.kmsProviders(cluster).WithSubpath(provider).Request(ctx, http.MethodDelete), nil)
+}
+
+// ListKMSServices returns the registered Key Provider Services.
+func (c *Manager) ListKMSServices(ctx context.Context) ([]ServiceSummary, error) {
+	return c.listServices(ctx, "kms")
+}
+
+// GetKMSService returns the given Key Provider Service.
+func (c *Manager) GetKMSService(ctx context.Context, id string) (*ServiceSpec, error) {
+	return c.getService(ctx, "kms", id)
+}
+
+// CreateKMSService registers a Key Provider Service, returning its ID.
+func (c *Manager) CreateKMSService(ctx context.Context, spec ServiceSpec) (string, error) {
+	return c.createService(ctx, "kms", spec)
+}
+
+// DeleteKMSService removes the given Key Provider Service registration.
+func (c *Manager) DeleteKMSService(ctx context.Context, id string) error {
+	return c.deleteService(ctx, "kms", id)
+}
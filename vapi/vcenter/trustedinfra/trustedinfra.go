@@ -0,0 +1,88 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trustedinfra
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/vmware/govmomi/vapi/internal"
+	"github.com/vmware/govmomi/vapi/rest"
+)
+
+// Manager extends rest.Client, adding vSphere Trust Authority related methods.
+type Manager struct {
+	*rest.Client
+}
+
+// NewManager creates a new Manager instance with the given client.
+func NewManager(client *rest.Client) *Manager {
+	return &Manager{
+		Client: client,
+	}
+}
+
+// Trust Authority cluster states
+const (
+	StateEnable  = "ENABLE"
+	StateDisable = "DISABLE"
+)
+
+// ClusterSummary describes the Trust Authority state of a cluster.
+type ClusterSummary struct {
+	Cluster string `json:"cluster"`
+	State   string `json:"state"`
+}
+
+// NetworkAddress of a Trust Authority service.
+type NetworkAddress struct {
+	Hostname string `json:"hostname"`
+	Port     int    `json:"port,omitempty"`
+}
+
+// X509CertChain is a chain of PEM encoded X.509 certificates.
+type X509CertChain struct {
+	CertChain []string `json:"cert_chain"`
+}
+
+func (c *Manager) resource(subpath string) *internal.Resource {
+	return internal.URL(c, internal.TrustedInfrastructurePath).WithSubpath(subpath)
+}
+
+func (c *Manager) trustAuthorityCluster(cluster string) *internal.Resource {
+	return c.resource("trust-authority-clusters").WithSubpath(cluster)
+}
+
+// ListTrustAuthorityClusters returns the Trust Authority state of each cluster.
+func (c *Manager) ListTrustAuthorityClusters(ctx context.Context) ([]ClusterSummary, error) {
+	var res []ClusterSummary
+	return res, c.Do(ctx, c.resource("trust-authority-clusters").Request(ctx, http.MethodGet), &res)
+}
+
+// GetTrustAuthorityCluster returns the Trust Authority state of the given cluster.
+func (c *Manager) GetTrustAuthorityCluster(ctx context.Context, cluster string) (*ClusterSummary, error) {
+	var res ClusterSummary
+	return &res, c.Do(ctx, c.trustAuthorityCluster(cluster).Request(ctx, http.MethodGet), &res)
+}
+
+// SetTrustAuthorityClusterState enables or disables the Trust Authority services of the given cluster.
+func (c *Manager) SetTrustAuthorityClusterState(ctx context.Context, cluster string, state string) error {
+	body := struct {
+		State string `json:"state"`
+	}{state}
+	return c.Do(ctx, c.trustAuthorityCluster(cluster).Request(ctx, http.MethodPatch, body), nil)
+}
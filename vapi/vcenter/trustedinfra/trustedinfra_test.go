@@ -0,0 +1,205 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trustedinfra_test
+
+import (
+	"context"
+	"encoding/pem"
+	"errors"
+	"testing"
+
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vapi/vcenter/trustedinfra"
+	"github.com/vmware/govmomi/vim25"
+
+	_ "github.com/vmware/govmomi/vapi/simulator"
+)
+
+var testPEM = string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: []byte("govmomi")}))
+
+func TestTrustAuthorityCluster(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+		if err := c.Login(ctx, simulator.DefaultLogin); err != nil {
+			t.Fatal(err)
+		}
+
+		m := trustedinfra.NewManager(c)
+		cluster := simulator.Map.Any("ClusterComputeResource").Reference().Value
+
+		clusters, err := m.ListTrustAuthorityClusters(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(clusters) == 0 || clusters[0].State != trustedinfra.StateDisable {
+			t.Fatalf("clusters=%#v", clusters)
+		}
+
+		var notFound *rest.NotFound
+		if _, err = m.GetTrustAuthorityCluster(ctx, "domain-c0"); !errors.As(err, &notFound) {
+			t.Errorf("unexpected error %T: %s", err, err)
+		}
+
+		key := trustedinfra.EndorsementKey{Name: "esx-ek", PublicKey: testPEM}
+		var stdErr *rest.StdError
+		err = m.CreateEndorsementKey(ctx, cluster, key)
+		if !errors.As(err, &stdErr) || stdErr.Kind() != "not_allowed_in_current_state" {
+			t.Errorf("unexpected error %T: %s", err, err)
+		}
+
+		if err = m.SetTrustAuthorityClusterState(ctx, cluster, trustedinfra.StateEnable); err != nil {
+			t.Fatal(err)
+		}
+		summary, err := m.GetTrustAuthorityCluster(ctx, cluster)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if summary.State != trustedinfra.StateEnable {
+			t.Errorf("summary=%#v", summary)
+		}
+
+		if err = m.CreateEndorsementKey(ctx, cluster, key); err != nil {
+			t.Fatal(err)
+		}
+		var exists *rest.AlreadyExists
+		if err = m.CreateEndorsementKey(ctx, cluster, key); !errors.As(err, &exists) {
+			t.Errorf("unexpected error %T: %s", err, err)
+		}
+		var invalid *rest.InvalidArgument
+		bad := trustedinfra.EndorsementKey{Name: "bad", PublicKey: testPEM, Certificate: testPEM}
+		if err = m.CreateEndorsementKey(ctx, cluster, bad); !errors.As(err, &invalid) {
+			t.Errorf("unexpected error %T: %s", err, err)
+		}
+
+		names, err := m.ListEndorsementKeys(ctx, cluster)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(names) != 1 || names[0] != key.Name {
+			t.Errorf("names=%v", names)
+		}
+		ek, err := m.GetEndorsementKey(ctx, cluster, key.Name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if *ek != key {
+			t.Errorf("key=%#v", ek)
+		}
+		if err = m.DeleteEndorsementKey(ctx, cluster, key.Name); err != nil {
+			t.Fatal(err)
+		}
+		if _, err = m.GetEndorsementKey(ctx, cluster, key.Name); !errors.As(err, &notFound) {
+			t.Errorf("unexpected error %T: %s", err, err)
+		}
+
+		provider := trustedinfra.KMSProvider{
+			Provider:    "kms-provider",
+			MasterKeyID: "master-key",
+			KeyServer: trustedinfra.KeyServer{
+				Type: trustedinfra.KeyServerTypeKMIP,
+				KMIPServer: &trustedinfra.KMIPServerSpec{
+					Servers: []trustedinfra.KMIPServer{
+						{Name: "kmip", Address: trustedinfra.NetworkAddress{Hostname: "kmip.example.com", Port: 5696}},
+					},
+				},
+			},
+		}
+		if err = m.CreateKMSProvider(ctx, cluster, provider); err != nil {
+			t.Fatal(err)
+		}
+		providers, err := m.ListKMSProviders(ctx, cluster)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(providers) != 1 || providers[0] != provider.Provider {
+			t.Errorf("providers=%v", providers)
+		}
+		p, err := m.GetKMSProvider(ctx, cluster, provider.Provider)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.MasterKeyID != provider.MasterKeyID || len(p.KeyServer.KMIPServer.Servers) != 1 {
+			t.Errorf("provider=%#v", p)
+		}
+		if err = m.DeleteKMSProvider(ctx, cluster, provider.Provider); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func TestTrustedServices(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+		if err := c.Login(ctx, simulator.DefaultLogin); err != nil {
+			t.Fatal(err)
+		}
+
+		m := trustedinfra.NewManager(c)
+
+		services := []struct {
+			create func(context.Context, trustedinfra.ServiceSpec) (string, error)
+			list   func(context.Context) ([]trustedinfra.ServiceSummary, error)
+			get    func(context.Context, string) (*trustedinfra.ServiceSpec, error)
+			delete func(context.Context, string) error
+		}{
+			{m.CreateAttestationService, m.ListAttestationServices, m.GetAttestationService, m.DeleteAttestationService},
+			{m.CreateKMSService, m.ListKMSServices, m.GetKMSService, m.DeleteKMSService},
+		}
+
+		for _, svc := range services {
+			spec := trustedinfra.ServiceSpec{
+				Address:   trustedinfra.NetworkAddress{Hostname: "trust.example.com", Port: 443},
+				TrustedCA: trustedinfra.X509CertChain{CertChain: []string{testPEM}},
+				Group:     "host-group",
+			}
+
+			id, err := svc.create(ctx, spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var exists *rest.AlreadyExists
+			if _, err = svc.create(ctx, spec); !errors.As(err, &exists) {
+				t.Errorf("unexpected error %T: %s", err, err)
+			}
+
+			list, err := svc.list(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(list) != 1 || list[0].Service != id || list[0].Address != spec.Address {
+				t.Errorf("list=%#v", list)
+			}
+
+			info, err := svc.get(ctx, id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Group != spec.Group || len(info.TrustedCA.CertChain) != 1 {
+				t.Errorf("info=%#v", info)
+			}
+
+			if err = svc.delete(ctx, id); err != nil {
+				t.Fatal(err)
+			}
+			var notFound *rest.NotFound
+			if _, err = svc.get(ctx, id); !errors.As(err, &notFound) {
+				t.Errorf("unexpected error %T: %s", err, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,359 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vcenter
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/vmware/govmomi/vapi/internal"
+)
+
+// Folder types
+const (
+	FolderTypeDatacenter     = "DATACENTER"
+	FolderTypeDatastore      = "DATASTORE"
+	FolderTypeHost           = "HOST"
+	FolderTypeNetwork        = "NETWORK"
+	FolderTypeVirtualMachine = "VIRTUAL_MACHINE"
+)
+
+// Host connection states
+const (
+	HostConnectionStateConnected     = "CONNECTED"
+	HostConnectionStateDisconnected  = "DISCONNECTED"
+	HostConnectionStateNotResponding = "NOT_RESPONDING"
+)
+
+// Host power states
+const (
+	HostPowerStateOn      = "POWERED_ON"
+	HostPowerStateOff     = "POWERED_OFF"
+	HostPowerStateStandby = "STANDBY"
+)
+
+// Network types
+const (
+	NetworkTypeStandardPortgroup    = "STANDARD_PORTGROUP"
+	NetworkTypeDistributedPortgroup = "DISTRIBUTED_PORTGROUP"
+	NetworkTypeOpaqueNetwork        = "OPAQUE_NETWORK"
+)
+
+// filterParam is a list query parameter, which matches any of the given values
+type filterParam struct {
+	name   string
+	values []string
+}
+
+// list returns the inventory objects at the given path, matching all of the given filter params
+func (c *Manager) list(ctx context.Context, path string, params []filterParam, res interface{}) error {
+	url := internal.URL(c, path)
+	for _, p := range params {
+		for _, value := range p.values {
+			url = url.WithParameter(p.name, value)
+		}
+	}
+	return c.Do(ctx, url.Request(ctx, http.MethodGet), res)
+}
+
+// ResourcePoolFilterSpec filters the ResourcePools returned by ListResourcePools, where empty fields match any value.
+type ResourcePoolFilterSpec struct {
+	ResourcePools       []string
+	Names               []string
+	ParentResourcePools []string
+	Datacenters         []string
+	Hosts               []string
+	Clusters            []string
+}
+
+// ResourcePoolSummary is the summary of a ResourcePool.
+type ResourcePoolSummary struct {
+	ResourcePool string `json:"resource_pool"`
+	Name         string `json:"name"`
+}
+
+// Shares of a resource allocation.
+type Shares struct {
+	Level  string `json:"level"`
+	Shares int32  `json:"shares,omitempty"`
+}
+
+// ResourceAllocation is the CPU or memory allocation of a ResourcePool,
+// where Reservation and Limit are in MHz for CPU and MB for memory.
+type ResourceAllocation struct {
+	Reservation           int64  `json:"reservation"`
+	ExpandableReservation bool   `json:"expandable_reservation"`
+	Limit                 int64  `json:"limit"`
+	Shares                Shares `json:"shares"`
+}
+
+// ResourcePoolInfo describes a ResourcePool, where ResourcePools are the IDs of its child pools.
+type ResourcePoolInfo struct {
+	Name             string              `json:"name"`
+	ResourcePools    []string            `json:"resource_pools"`
+	CPUAllocation    *ResourceAllocation `json:"cpu_allocation,omitempty"`
+	MemoryAllocation *ResourceAllocation `json:"memory_allocation,omitempty"`
+}
+
+// ListResourcePools returns the ResourcePools matching the given filter, up to 1000 pools.
+func (c *Manager) ListResourcePools(ctx context.Context, filter ResourcePoolFilterSpec) ([]ResourcePoolSummary, error) {
+	params := []filterParam{
+		{"resource_pools", filter.ResourcePools},
+		{"names", filter.Names},
+		{"parent_resource_pools", filter.ParentResourcePools},
+		{"datacenters", filter.Datacenters},
+		{"hosts", filter.Hosts},
+		{"clusters", filter.Clusters},
+	}
+	var res []ResourcePoolSummary
+	return res, c.list(ctx, internal.VCenterResourcePoolPath, params, &res)
+}
+
+// GetResourcePool returns the given ResourcePool.
+func (c *Manager) GetResourcePool(ctx context.Context, id string) (*ResourcePoolInfo, error) {
+	url := internal.URL(c, internal.VCenterResourcePoolPath).WithID(id)
+	var res ResourcePoolInfo
+	return &res, c.Do(ctx, url.Request(ctx, http.MethodGet), &res)
+}
+
+// FolderFilterSpec filters the Folders returned by ListFolders, where empty fields match any value.
+type FolderFilterSpec struct {
+	Folders       []string
+	Names         []string
+	Type          string
+	ParentFolders []string
+	Datacenters   []string
+}
+
+// FolderSummary is the summary of a Folder, where Type is the kind of objects the Folder contains.
+type FolderSummary struct {
+	Folder string `json:"folder"`
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+}
+
+// ListFolders returns the Folders matching the given filter, up to 1000 folders.
+func (c *Manager) ListFolders(ctx context.Context, filter FolderFilterSpec) ([]FolderSummary, error) {
+	params := []filterParam{
+		{"folders", filter.Folders},
+		{"names", filter.Names},
+		{"parent_folders", filter.ParentFolders},
+		{"datacenters", filter.Datacenters},
+	}
+	if filter.Type != "" {
+		params = append(params, filterParam{"type", []string{filter.Type}})
+	}
+	var res []FolderSummary
+	return res, c.list(ctx, internal.VCenterFolderPath, params, &res)
+}
+
+// DatacenterFilterSpec filters the Datacenters returned by ListDatacenters, where empty fields match any value.
+type DatacenterFilterSpec struct {
+	Datacenters []string
+	Names       []string
+	Folders     []string
+}
+
+// DatacenterSummary is the summary of a Datacenter.
+type DatacenterSummary struct {
+	Datacenter string `json:"datacenter"`
+	Name       string `json:"name"`
+}
+
+// DatacenterInfo describes a Datacenter and its root folders.
+type DatacenterInfo struct {
+	Name            string `json:"name"`
+	DatastoreFolder string `json:"datastore_folder"`
+	HostFolder      string `json:"host_folder"`
+	NetworkFolder   string `json:"network_folder"`
+	VMFolder        string `json:"vm_folder"`
+	Parent          string `json:"parent"`
+}
+
+// ListDatacenters returns the Datacenters matching the given filter, up to 1000 datacenters.
+func (c *Manager) ListDatacenters(ctx context.Context, filter DatacenterFilterSpec) ([]DatacenterSummary, error) {
+	params := []filterParam{
+		{"datacenters", filter.Datacenters},
+		{"names", filter.Names},
+		{"folders", filter.Folders},
+	}
+	var res []DatacenterSummary
+	return res, c.list(ctx, internal.VCenterDatacenterPath, params, &res)
+}
+
+// GetDatacenter returns the given Datacenter.
+func (c *Manager) GetDatacenter(ctx context.Context, id string) (*DatacenterInfo, error) {
+	url := internal.URL(c, internal.VCenterDatacenterPath).WithID(id)
+	var res DatacenterInfo
+	return &res, c.Do(ctx, url.Request(ctx, http.MethodGet), &res)
+}
+
+// ClusterFilterSpec filters the clusters returned by ListClusters, where empty fields match any value.
+type ClusterFilterSpec struct {
+	Clusters    []string
+	Names       []string
+	Folders     []string
+	Datacenters []string
+}
+
+// ClusterSummary is the summary of a cluster.
+type ClusterSummary struct {
+	Cluster    string `json:"cluster"`
+	Name       string `json:"name"`
+	HAEnabled  bool   `json:"ha_enabled"`
+	DRSEnabled bool   `json:"drs_enabled"`
+}
+
+// ClusterInfo describes a cluster, where ResourcePool is the ID of its root ResourcePool.
+type ClusterInfo struct {
+	Name         string `json:"name"`
+	ResourcePool string `json:"resource_pool"`
+}
+
+// ListClusters returns the clusters matching the given filter, up to 1000 clusters.
+func (c *Manager) ListClusters(ctx context.Context, filter ClusterFilterSpec) ([]ClusterSummary, error) {
+	params := []filterParam{
+		{"clusters", filter.Clusters},
+		{"names", filter.Names},
+		{"folders", filter.Folders},
+		{"datacenters", filter.Datacenters},
+	}
+	var res []ClusterSummary
+	return res, c.list(ctx, internal.VCenterClusterPath, params, &res)
+}
+
+// GetCluster returns the given cluster.
+func (c *Manager) GetCluster(ctx context.Context, id string) (*ClusterInfo, error) {
+	url := internal.URL(c, internal.VCenterClusterPath).WithID(id)
+	var res ClusterInfo
+	return &res, c.Do(ctx, url.Request(ctx, http.MethodGet), &res)
+}
+
+// HostFilterSpec filters the hosts returned by ListHosts, where empty fields match any value.
+// Standalone, if set, matches hosts that are (or are not) outside of a cluster.
+type HostFilterSpec struct {
+	Hosts            []string
+	Names            []string
+	Folders          []string
+	Datacenters      []string
+	Standalone       *bool
+	Clusters         []string
+	ConnectionStates []string
+}
+
+// HostSummary is the summary of a host, where PowerState is only set if the host is connected.
+type HostSummary struct {
+	Host            string `json:"host"`
+	Name            string `json:"name"`
+	ConnectionState string `json:"connection_state"`
+	PowerState      string `json:"power_state,omitempty"`
+}
+
+// ListHosts returns the hosts matching the given filter, up to 2500 hosts.
+func (c *Manager) ListHosts(ctx context.Context, filter HostFilterSpec) ([]HostSummary, error) {
+	params := []filterParam{
+		{"hosts", filter.Hosts},
+		{"names", filter.Names},
+		{"folders", filter.Folders},
+		{"datacenters", filter.Datacenters},
+		{"clusters", filter.Clusters},
+		{"connection_states", filter.ConnectionStates},
+	}
+	if filter.Standalone != nil {
+		params = append(params, filterParam{"standalone", []string{strconv.FormatBool(*filter.Standalone)}})
+	}
+	var res []HostSummary
+	return res, c.list(ctx, internal.VCenterHostPath, params, &res)
+}
+
+// DatastoreFilterSpec filters the Datastores returned by ListDatastores, where empty fields match any value.
+type DatastoreFilterSpec struct {
+	Datastores  []string
+	Names       []string
+	Types       []string
+	Folders     []string
+	Datacenters []string
+}
+
+// DatastoreSummary is the summary of a Datastore, with space in bytes.
+type DatastoreSummary struct {
+	Datastore string `json:"datastore"`
+	Name      string `json:"name"`
+	Type      string `json:"type"`
+	FreeSpace int64  `json:"free_space,omitempty"`
+	Capacity  int64  `json:"capacity,omitempty"`
+}
+
+// DatastoreInfo describes a Datastore, with space in bytes.
+type DatastoreInfo struct {
+	Name                      string `json:"name"`
+	Type                      string `json:"type"`
+	Accessible                bool   `json:"accessible"`
+	FreeSpace                 int64  `json:"free_space,omitempty"`
+	MultipleHostAccess        bool   `json:"multiple_host_access"`
+	ThinProvisioningSupported bool   `json:"thin_provisioning_supported"`
+}
+
+// ListDatastores returns the Datastores matching the given filter, up to 2500 datastores.
+func (c *Manager) ListDatastores(ctx context.Context, filter DatastoreFilterSpec) ([]DatastoreSummary, error) {
+	params := []filterParam{
+		{"datastores", filter.Datastores},
+		{"names", filter.Names},
+		{"types", filter.Types},
+		{"folders", filter.Folders},
+		{"datacenters", filter.Datacenters},
+	}
+	var res []DatastoreSummary
+	return res, c.list(ctx, internal.VCenterDatastorePath, params, &res)
+}
+
+// GetDatastore returns the given Datastore.
+func (c *Manager) GetDatastore(ctx context.Context, id string) (*DatastoreInfo, error) {
+	url := internal.URL(c, internal.VCenterDatastorePath).WithID(id)
+	var res DatastoreInfo
+	return &res, c.Do(ctx, url.Request(ctx, http.MethodGet), &res)
+}
+
+// NetworkFilterSpec filters the networks returned by ListNetworks, where empty fields match any value.
+type NetworkFilterSpec struct {
+	Networks    []string
+	Names       []string
+	Types       []string
+	Folders     []string
+	Datacenters []string
+}
+
+// NetworkSummary is the summary of a network.
+type NetworkSummary struct {
+	Network string `json:"network"`
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+}
+
+// ListNetworks returns the networks matching the given filter, up to 1000 networks.
+func (c *Manager) ListNetworks(ctx context.Context, filter NetworkFilterSpec) ([]NetworkSummary, error) {
+	params := []filterParam{
+		{"networks", filter.Networks},
+		{"names", filter.Names},
+		{"types", filter.Types},
+		{"folders", filter.Folders},
+		{"datacenters", filter.Datacenters},
+	}
+	var res []NetworkSummary
+	return res, c.list(ctx, internal.VCenterNetworkPath, params, &res)
+}
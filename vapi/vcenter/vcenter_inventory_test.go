@@ -0,0 +1,155 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vcenter_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vapi/vcenter"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+
+	_ "github.com/vmware/govmomi/vapi/simulator"
+)
+
+func TestInventory(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+		if err := c.Login(ctx, simulator.DefaultLogin); err != nil {
+			t.Fatal(err)
+		}
+		m := vcenter.NewManager(c)
+
+		dc := simulator.Map.Any("Datacenter").(*simulator.Datacenter)
+		cluster := simulator.Map.Any("ClusterComputeResource").(*simulator.ClusterComputeResource)
+
+		datacenters, err := m.ListDatacenters(ctx, vcenter.DatacenterFilterSpec{Names: []string{dc.Name}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(datacenters) != 1 || datacenters[0].Datacenter != dc.Self.Value {
+			t.Errorf("datacenters=%#v", datacenters)
+		}
+		info, err := m.GetDatacenter(ctx, dc.Self.Value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.VMFolder != dc.VmFolder.Value || info.HostFolder != dc.HostFolder.Value {
+			t.Errorf("datacenter=%#v", info)
+		}
+
+		var notFound *rest.NotFound
+		if _, err = m.GetDatacenter(ctx, "datacenter-0"); !errors.As(err, &notFound) {
+			t.Errorf("unexpected error %T: %s", err, err)
+		}
+
+		folders, err := m.ListFolders(ctx, vcenter.FolderFilterSpec{Type: vcenter.FolderTypeVirtualMachine, Datacenters: []string{dc.Self.Value}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(folders) != 1 || folders[0].Folder != dc.VmFolder.Value {
+			t.Errorf("folders=%#v", folders)
+		}
+
+		clusters, err := m.ListClusters(ctx, vcenter.ClusterFilterSpec{Folders: []string{dc.HostFolder.Value}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(clusters) != 1 || clusters[0].Cluster != cluster.Self.Value || !clusters[0].DRSEnabled {
+			t.Errorf("clusters=%#v", clusters)
+		}
+		clusterInfo, err := m.GetCluster(ctx, cluster.Self.Value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if clusterInfo.ResourcePool != cluster.ResourcePool.Value {
+			t.Errorf("cluster=%#v", clusterInfo)
+		}
+
+		hosts, err := m.ListHosts(ctx, vcenter.HostFilterSpec{Clusters: []string{cluster.Self.Value}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(hosts) != len(cluster.Host) || hosts[0].ConnectionState != vcenter.HostConnectionStateConnected {
+			t.Errorf("hosts=%#v", hosts)
+		}
+		standalone := true
+		hosts, err = m.ListHosts(ctx, vcenter.HostFilterSpec{Standalone: &standalone})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(hosts) != 1 || hosts[0].PowerState != vcenter.HostPowerStateOn {
+			t.Errorf("hosts=%#v", hosts)
+		}
+
+		host := cluster.Host[0].Value
+		pools, err := m.ListResourcePools(ctx, vcenter.ResourcePoolFilterSpec{Hosts: []string{host}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(pools) != 1 || pools[0].ResourcePool != cluster.ResourcePool.Value {
+			t.Errorf("pools=%#v", pools)
+		}
+		pool, err := m.GetResourcePool(ctx, cluster.ResourcePool.Value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pool.Name != "Resources" || pool.CPUAllocation == nil || pool.MemoryAllocation == nil {
+			t.Errorf("pool=%#v", pool)
+		}
+
+		ds := simulator.Map.Any("Datastore").(*simulator.Datastore)
+		datastores, err := m.ListDatastores(ctx, vcenter.DatastoreFilterSpec{Names: []string{ds.Name}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(datastores) != 1 || datastores[0].Datastore != ds.Self.Value || datastores[0].Capacity == 0 {
+			t.Errorf("datastores=%#v", datastores)
+		}
+		dsInfo, err := m.GetDatastore(ctx, ds.Self.Value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if dsInfo.Name != ds.Name || !dsInfo.Accessible || dsInfo.Type != datastores[0].Type {
+			t.Errorf("datastore=%#v", dsInfo)
+		}
+
+		for _, kind := range []string{vcenter.NetworkTypeStandardPortgroup, vcenter.NetworkTypeDistributedPortgroup} {
+			networks, err := m.ListNetworks(ctx, vcenter.NetworkFilterSpec{Types: []string{kind}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(networks) == 0 {
+				t.Fatalf("no %s networks", kind)
+			}
+			for _, n := range networks {
+				ref := types.ManagedObjectReference{Type: "Network", Value: n.Network}
+				if kind == vcenter.NetworkTypeDistributedPortgroup {
+					ref.Type = "DistributedVirtualPortgroup"
+				}
+				if obj, ok := simulator.Map.Get(ref).(mo.Entity); !ok || obj.Entity().Name != n.Name {
+					t.Errorf("network=%#v", n)
+				}
+			}
+		}
+	})
+}
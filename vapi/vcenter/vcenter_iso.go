@@ -0,0 +1,46 @@
+/*
+Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vcenter
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/vmware/govmomi/vapi/internal"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// MountISO mounts the ISO image of a library item on a new CD-ROM device of the given VM,
+// returning the device key of the CD-ROM.
+func (c *Manager) MountISO(ctx context.Context, libraryItemID string, vm types.ManagedObjectReference) (string, error) {
+	url := internal.URL(c, internal.VCenterISOImage).WithID(libraryItemID).WithAction("mount")
+	spec := struct {
+		VM string `json:"vm"`
+	}{vm.Value}
+	var res string
+	return res, c.Do(ctx, url.Request(ctx, http.MethodPost, spec), &res)
+}
+
+// UnmountISO unmounts a library item ISO image previously mounted on the given VM's CD-ROM device,
+// as returned by MountISO.
+func (c *Manager) UnmountISO(ctx context.Context, vm types.ManagedObjectReference, cdrom string) error {
+	url := internal.URL(c, internal.VCenterISOImage).WithID(vm.Value).WithAction("unmount")
+	spec := struct {
+		CDROM string `json:"cdrom"`
+	}{cdrom}
+	return c.Do(ctx, url.Request(ctx, http.MethodPost, spec), nil)
+}
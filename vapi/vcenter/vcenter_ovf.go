@@ -117,6 +117,12 @@ type StorageGroupMapping struct {
 	Provisioning     string `json:"provisioning,omitempty"`
 }
 
+// StorageGroupMapping types
+const (
+	StorageGroupMappingDatastore      = "DATASTORE"
+	StorageGroupMappingStorageProfile = "STORAGE_PROFILE"
+)
+
 // StorageMapping specifies the target storage to use for sections of type vmw:StorageGroupSection in the OVF descriptor
 type StorageMapping struct {
 	Key   string              `json:"key"`
@@ -219,12 +225,69 @@ type FilterRequest struct {
 type FilterResponse struct {
 	EULAs            []string           `json:"EULAs,omitempty"`
 	AdditionalParams []AdditionalParams `json:"additional_params,omitempty"`
-	Annotation       string             `json:"Annotation,omitempty"`
+	Annotation       string             `json:"annotation,omitempty"`
 	Name             string             `json:"name,omitempty"`
-	Networks         []string           `json:"Networks,omitempty"`
+	Networks         []string           `json:"networks,omitempty"`
 	StorageGroups    []string           `json:"storage_groups,omitempty"`
 }
 
+// DeploymentSpec returns a DeploymentSpec initialized with the Name and Annotation of the filter response,
+// along with its PropertyParams and DeploymentOptionParams, such that defaults can be changed using
+// SetProperty and SetDeploymentOption.
+// Any Networks and StorageGroups must be mapped using AddNetworkMapping and AddStorageMapping,
+// and AcceptAllEULA must be set if there are EULAs.
+func (r *FilterResponse) DeploymentSpec() DeploymentSpec {
+	spec := DeploymentSpec{
+		Name:       r.Name,
+		Annotation: r.Annotation,
+	}
+	for _, p := range r.AdditionalParams {
+		switch p.Type {
+		case TypePropertyParams, TypeDeploymentOptionParams:
+			spec.AdditionalParams = append(spec.AdditionalParams, p)
+		}
+	}
+	return spec
+}
+
+// AddNetworkMapping maps the OVF network name to the given network ID.
+func (s *DeploymentSpec) AddNetworkMapping(name, networkID string) {
+	s.NetworkMappings = append(s.NetworkMappings, NetworkMapping{Key: name, Value: networkID})
+}
+
+// AddStorageMapping maps the OVF storage group to the given storage.
+func (s *DeploymentSpec) AddStorageMapping(group string, storage StorageGroupMapping) {
+	s.StorageMappings = append(s.StorageMappings, StorageMapping{Key: group, Value: storage})
+}
+
+// params returns the AdditionalParams of the given type, adding them if needed.
+func (s *DeploymentSpec) params(kind string) *AdditionalParams {
+	for i := range s.AdditionalParams {
+		if s.AdditionalParams[i].Type == kind {
+			return &s.AdditionalParams[i]
+		}
+	}
+	s.AdditionalParams = append(s.AdditionalParams, AdditionalParams{Class: ClassOvfParams, Type: kind})
+	return &s.AdditionalParams[len(s.AdditionalParams)-1]
+}
+
+// SetProperty sets the value of the OVF property with the given ID, via the spec's PropertyParams.
+func (s *DeploymentSpec) SetProperty(id, value string) {
+	p := s.params(TypePropertyParams)
+	for i := range p.Properties {
+		if p.Properties[i].ID == id {
+			p.Properties[i].Value = value
+			return
+		}
+	}
+	p.Properties = append(p.Properties, Property{ID: id, Value: value})
+}
+
+// SetDeploymentOption selects the OVF deployment option with the given key, via the spec's DeploymentOptionParams.
+func (s *DeploymentSpec) SetDeploymentOption(key string) {
+	s.params(TypeDeploymentOptionParams).SelectedKey = key
+}
+
 // Manager extends rest.Client, adding content library related methods.
 type Manager struct {
 	*rest.Client
@@ -241,7 +304,7 @@ func NewManager(client *rest.Client) *Manager {
 func (c *Manager) DeployLibraryItem(ctx context.Context, libraryItemID string, deploy Deploy) (*types.ManagedObjectReference, error) {
 	url := internal.URL(c, internal.VCenterOVFLibraryItem).WithID(libraryItemID).WithAction("deploy")
 	var res Deployment
-	err := c.Do(ctx, url.Request(http.MethodPost, deploy), &res)
+	err := c.Do(ctx, url.Request(ctx, http.MethodPost, deploy), &res)
 	if err != nil {
 		return nil, err
 	}
@@ -256,5 +319,5 @@ func (c *Manager) DeployLibraryItem(ctx context.Context, libraryItemID string, d
 func (c *Manager) FilterLibraryItem(ctx context.Context, libraryItemID string, filter FilterRequest) (FilterResponse, error) {
 	url := internal.URL(c, internal.VCenterOVFLibraryItem).WithID(libraryItemID).WithAction("filter")
 	var res FilterResponse
-	return res, c.Do(ctx, url.Request(http.MethodPost, filter), &res)
+	return res, c.Do(ctx, url.Request(ctx, http.MethodPost, filter), &res)
 }
@@ -0,0 +1,179 @@
+/*
+Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vcenter_test
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/library"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vapi/vcenter"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/soap"
+
+	_ "github.com/vmware/govmomi/vapi/simulator"
+)
+
+const testOVF = `<?xml version="1.0" encoding="UTF-8"?>
+<Envelope xmlns="http://schemas.dmtf.org/ovf/envelope/1" xmlns:ovf="http://schemas.dmtf.org/ovf/envelope/1">
+  <References/>
+  <NetworkSection>
+    <Info>Networks</Info>
+    <Network ovf:name="VM Network"/>
+  </NetworkSection>
+  <DeploymentOptionSection>
+    <Info>Deployment options</Info>
+    <Configuration ovf:id="small" ovf:default="true"><Label>Small</Label></Configuration>
+    <Configuration ovf:id="large"><Label>Large</Label></Configuration>
+  </DeploymentOptionSection>
+  <VirtualSystem ovf:id="test">
+    <Info>A virtual machine</Info>
+    <AnnotationSection>
+      <Info>Annotation</Info>
+      <Annotation>test appliance</Annotation>
+    </AnnotationSection>
+    <ProductSection>
+      <Info>Properties</Info>
+      <Property ovf:key="hostname" ovf:type="string" ovf:value="localhost"/>
+    </ProductSection>
+    <EulaSection>
+      <Info>License</Info>
+      <License>Terms and conditions</License>
+    </EulaSection>
+    <VirtualHardwareSection>
+      <Info>Virtual hardware</Info>
+      <System>
+        <vssd:ElementName xmlns:vssd="http://schemas.dmtf.org/wbem/wscim/1/cim-schema/2/CIM_VirtualSystemSettingData">Virtual Hardware Family</vssd:ElementName>
+        <vssd:InstanceID xmlns:vssd="http://schemas.dmtf.org/wbem/wscim/1/cim-schema/2/CIM_VirtualSystemSettingData">0</vssd:InstanceID>
+        <vssd:VirtualSystemType xmlns:vssd="http://schemas.dmtf.org/wbem/wscim/1/cim-schema/2/CIM_VirtualSystemSettingData">vmx-13</vssd:VirtualSystemType>
+      </System>
+    </VirtualHardwareSection>
+  </VirtualSystem>
+</Envelope>
+`
+
+// newOVFItem creates a library item containing testOVF, returning the item ID.
+func newOVFItem(ctx context.Context, t *testing.T, c *rest.Client) string {
+	m := library.NewManager(c)
+
+	lib, err := m.CreateLibrary(ctx, library.Library{
+		Name: "test-library",
+		Type: "LOCAL",
+		Storage: []library.StorageBackings{{
+			DatastoreID: simulator.Map.Any("Datastore").Reference().Value,
+			Type:        "DATASTORE",
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	item, err := m.CreateLibraryItem(ctx, library.Item{Name: "test-item", Type: "ovf", LibraryID: lib})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	session, err := m.CreateLibraryItemUpdateSession(ctx, library.Session{LibraryItemID: item})
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := m.AddLibraryItemFile(ctx, session, library.UpdateFile{
+		Name:       "test.ovf",
+		SourceType: "PUSH",
+		Size:       int64(len(testOVF)),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := url.Parse(info.UploadEndpoint.URI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := soap.DefaultUpload
+	p.ContentLength = int64(len(testOVF))
+	if err = c.Upload(ctx, strings.NewReader(testOVF), u, &p); err != nil {
+		t.Fatal(err)
+	}
+	if err = m.CompleteLibraryItemUpdateSession(ctx, session); err != nil {
+		t.Fatal(err)
+	}
+
+	return item
+}
+
+func TestFilterLibraryItem(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+		if err := c.Login(ctx, simulator.DefaultLogin); err != nil {
+			t.Fatal(err)
+		}
+		m := vcenter.NewManager(c)
+		item := newOVFItem(ctx, t, c)
+
+		pool := simulator.Map.Any("ResourcePool").Reference()
+		net := simulator.Map.Any("Network").Reference()
+
+		target := vcenter.Target{ResourcePoolID: pool.Value}
+		filter, err := m.FilterLibraryItem(ctx, item, vcenter.FilterRequest{Target: target})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if filter.Annotation != "test appliance" || len(filter.EULAs) != 1 || len(filter.Networks) != 1 || filter.Networks[0] != "VM Network" {
+			t.Errorf("filter=%#v", filter)
+		}
+
+		spec := filter.DeploymentSpec()
+		if len(spec.AdditionalParams) != 2 {
+			t.Fatalf("params=%#v", spec.AdditionalParams)
+		}
+		spec.Name = "filtered-vm"
+		spec.AddNetworkMapping(filter.Networks[0], net.Value)
+		spec.SetProperty("hostname", "test.example.com")
+		spec.SetDeploymentOption("large")
+
+		for _, p := range spec.AdditionalParams {
+			switch p.Type {
+			case vcenter.TypePropertyParams:
+				if len(p.Properties) != 1 || p.Properties[0].Value != "test.example.com" {
+					t.Errorf("properties=%#v", p.Properties)
+				}
+			case vcenter.TypeDeploymentOptionParams:
+				if p.SelectedKey != "large" {
+					t.Errorf("selected=%s", p.SelectedKey)
+				}
+			}
+		}
+
+		deploy := vcenter.Deploy{DeploymentSpec: spec, Target: target}
+		if _, err = m.DeployLibraryItem(ctx, item, deploy); err == nil {
+			t.Error("expected error, EULA not accepted")
+		}
+
+		deploy.AcceptAllEULA = true
+		ref, err := m.DeployLibraryItem(ctx, item, deploy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ref.Type != "VirtualMachine" {
+			t.Errorf("ref=%s", ref)
+		}
+	})
+}
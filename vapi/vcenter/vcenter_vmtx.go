@@ -0,0 +1,242 @@
+/*
+Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vcenter
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/vmware/govmomi/vapi/internal"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// Placement information used to place a virtual machine template or its deployed VM.
+type Placement struct {
+	ResourcePool string `json:"resource_pool,omitempty"`
+	Host         string `json:"host,omitempty"`
+	Folder       string `json:"folder,omitempty"`
+	Cluster      string `json:"cluster,omitempty"`
+}
+
+// StoragePolicy for DiskStorage
+type StoragePolicy struct {
+	Policy string `json:"policy,omitempty"`
+	Type   string `json:"type"`
+}
+
+// DiskStorage defines the storage specification for VM files
+type DiskStorage struct {
+	Datastore     string         `json:"datastore,omitempty"`
+	StoragePolicy *StoragePolicy `json:"storage_policy,omitempty"`
+}
+
+// DiskStorageOverride storage specification for individual disks, keyed by the disk ID
+type DiskStorageOverride struct {
+	Key   string      `json:"key"`
+	Value DiskStorage `json:"value"`
+}
+
+// Template create spec, used to create a VM template library item from a source VM
+type Template struct {
+	Name                 string                `json:"name,omitempty"`
+	Description          string                `json:"description,omitempty"`
+	SourceVM             string                `json:"source_vm,omitempty"`
+	Library              string                `json:"library,omitempty"`
+	Placement            *Placement            `json:"placement,omitempty"`
+	VMHomeStorage        *DiskStorage          `json:"vm_home_storage,omitempty"`
+	DiskStorage          *DiskStorage          `json:"disk_storage,omitempty"`
+	DiskStorageOverrides []DiskStorageOverride `json:"disk_storage_overrides,omitempty"`
+}
+
+// CPU configuration of a VM template
+type CPU struct {
+	Count          int `json:"count,omitempty"`
+	CoresPerSocket int `json:"cores_per_socket,omitempty"`
+}
+
+// Memory configuration of a VM template
+type Memory struct {
+	SizeMiB int `json:"size_MiB,omitempty"`
+}
+
+// TemplateDiskInfo is the disk information of a VM template
+type TemplateDiskInfo struct {
+	Capacity    int64        `json:"capacity,omitempty"`
+	DiskStorage *DiskStorage `json:"disk_storage,omitempty"`
+}
+
+// TemplateDisk is a disk of a VM template, keyed by the disk ID
+type TemplateDisk struct {
+	Key   string           `json:"key"`
+	Value TemplateDiskInfo `json:"value"`
+}
+
+// TemplateNicInfo is the network adapter information of a VM template
+type TemplateNicInfo struct {
+	BackingType string `json:"backing_type,omitempty"`
+	MacType     string `json:"mac_type,omitempty"`
+	Network     string `json:"network,omitempty"`
+}
+
+// TemplateNic is a network adapter of a VM template, keyed by the adapter ID
+type TemplateNic struct {
+	Key   string          `json:"key"`
+	Value TemplateNicInfo `json:"value"`
+}
+
+// TemplateInfo is the information about a VM template library item
+type TemplateInfo struct {
+	GuestOS       string         `json:"guest_OS,omitempty"`
+	CPU           *CPU           `json:"cpu,omitempty"`
+	Memory        *Memory        `json:"memory,omitempty"`
+	VMHomeStorage *DiskStorage   `json:"vm_home_storage,omitempty"`
+	Disks         []TemplateDisk `json:"disks,omitempty"`
+	Nics          []TemplateNic  `json:"nics,omitempty"`
+	VMTemplate    string         `json:"vm_template,omitempty"`
+}
+
+// GuestCustomization spec, referencing a customization specification by name
+type GuestCustomization struct {
+	Name string `json:"name,omitempty"`
+}
+
+// CPUUpdate spec used to change the CPU configuration of a deployed VM
+type CPUUpdate struct {
+	NumCPUs           int `json:"num_cpus,omitempty"`
+	NumCoresPerSocket int `json:"num_cores_per_socket,omitempty"`
+}
+
+// MemoryUpdate spec used to change the memory size (in MiB) of a deployed VM
+type MemoryUpdate struct {
+	Memory int `json:"memory,omitempty"`
+}
+
+// HardwareCustomization spec
+type HardwareCustomization struct {
+	CPUUpdate     *CPUUpdate    `json:"cpu_update,omitempty"`
+	MemoryUpdate  *MemoryUpdate `json:"memory_update,omitempty"`
+	DisksToRemove []string      `json:"disks_to_remove,omitempty"`
+}
+
+// DeployTemplate is the spec used to deploy a VM from a VM template library item
+type DeployTemplate struct {
+	Name                  string                 `json:"name,omitempty"`
+	Description           string                 `json:"description,omitempty"`
+	Placement             *Placement             `json:"placement,omitempty"`
+	VMHomeStorage         *DiskStorage           `json:"vm_home_storage,omitempty"`
+	DiskStorage           *DiskStorage           `json:"disk_storage,omitempty"`
+	DiskStorageOverrides  []DiskStorageOverride  `json:"disk_storage_overrides,omitempty"`
+	PoweredOn             bool                   `json:"powered_on,omitempty"`
+	GuestCustomization    *GuestCustomization    `json:"guest_customization,omitempty"`
+	HardwareCustomization *HardwareCustomization `json:"hardware_customization,omitempty"`
+}
+
+// CheckOut is the spec used to check out a VM template library item, creating a VM which can be edited
+type CheckOut struct {
+	Name      string     `json:"name,omitempty"`
+	Placement *Placement `json:"placement,omitempty"`
+	PoweredOn bool       `json:"powered_on,omitempty"`
+}
+
+// CheckOutSummary is a VM checked out from a VM template library item
+type CheckOutSummary struct {
+	VM string `json:"vm"`
+}
+
+// CheckIn is the spec used to check in a VM into a VM template library item, creating a new version
+type CheckIn struct {
+	Message string `json:"message"`
+}
+
+// TemplateVersion is a version of a VM template library item
+type TemplateVersion struct {
+	Version    string `json:"version"`
+	VMTemplate string `json:"vm_template"`
+}
+
+// vmRef returns a VirtualMachine reference for the given VM ID
+func vmRef(id string) *types.ManagedObjectReference {
+	return &types.ManagedObjectReference{Type: "VirtualMachine", Value: id}
+}
+
+// CreateTemplate creates a VM template library item from the spec's SourceVM, returning the library item ID
+func (c *Manager) CreateTemplate(ctx context.Context, vmtx Template) (string, error) {
+	url := internal.URL(c, internal.VCenterVMTXLibraryItem)
+	spec := struct {
+		Template `json:"spec"`
+	}{vmtx}
+	var res string
+	return res, c.Do(ctx, url.Request(ctx, http.MethodPost, spec), &res)
+}
+
+// GetTemplateLibraryItem returns the information about a VM template library item
+func (c *Manager) GetTemplateLibraryItem(ctx context.Context, libraryItemID string) (*TemplateInfo, error) {
+	url := internal.URL(c, internal.VCenterVMTXLibraryItem).WithSubpath(libraryItemID)
+	var res TemplateInfo
+	return &res, c.Do(ctx, url.Request(ctx, http.MethodGet), &res)
+}
+
+// DeployTemplateLibraryItem deploys a VM from a VM template library item, returning the VM reference
+func (c *Manager) DeployTemplateLibraryItem(ctx context.Context, libraryItemID string, deploy DeployTemplate) (*types.ManagedObjectReference, error) {
+	// Unlike most /rest paths, the vm-template actions use the "action" parameter rather than "~action"
+	url := internal.URL(c, internal.VCenterVMTXLibraryItem).WithSubpath(libraryItemID).WithParameter("action", "deploy")
+	spec := struct {
+		DeployTemplate `json:"spec"`
+	}{deploy}
+	var res string
+	if err := c.Do(ctx, url.Request(ctx, http.MethodPost, spec), &res); err != nil {
+		return nil, err
+	}
+	return vmRef(res), nil
+}
+
+// CheckOut checks out a VM template library item, returning a reference to the VM which can be edited
+func (c *Manager) CheckOut(ctx context.Context, libraryItemID string, vm *CheckOut) (*types.ManagedObjectReference, error) {
+	url := internal.URL(c, internal.VCenterVMTXLibraryItem).WithSubpath(libraryItemID).WithSubpath("check-outs").WithParameter("action", "check-out")
+	spec := struct {
+		*CheckOut `json:"spec,omitempty"`
+	}{vm}
+	var res string
+	if err := c.Do(ctx, url.Request(ctx, http.MethodPost, spec), &res); err != nil {
+		return nil, err
+	}
+	return vmRef(res), nil
+}
+
+// CheckIn checks in a VM previously checked out of a VM template library item, returning the new version
+func (c *Manager) CheckIn(ctx context.Context, libraryItemID string, vm types.ManagedObjectReference, msg *CheckIn) (string, error) {
+	url := internal.URL(c, internal.VCenterVMTXLibraryItem).WithSubpath(libraryItemID).WithSubpath("check-outs").WithSubpath(vm.Value).WithParameter("action", "check-in")
+	spec := struct {
+		*CheckIn `json:"spec"`
+	}{msg}
+	var res string
+	return res, c.Do(ctx, url.Request(ctx, http.MethodPost, spec), &res)
+}
+
+// ListCheckOuts returns the VMs checked out of a VM template library item
+func (c *Manager) ListCheckOuts(ctx context.Context, libraryItemID string) ([]CheckOutSummary, error) {
+	url := internal.URL(c, internal.VCenterVMTXLibraryItem).WithSubpath(libraryItemID).WithSubpath("check-outs")
+	var res []CheckOutSummary
+	return res, c.Do(ctx, url.Request(ctx, http.MethodGet), &res)
+}
+
+// ListTemplateVersions returns the versions of a VM template library item
+func (c *Manager) ListTemplateVersions(ctx context.Context, libraryItemID string) ([]TemplateVersion, error) {
+	url := internal.URL(c, internal.VCenterVMTXLibraryItem).WithSubpath(libraryItemID).WithSubpath("versions")
+	var res []TemplateVersion
+	return res, c.Do(ctx, url.Request(ctx, http.MethodGet), &res)
+}
@@ -0,0 +1,124 @@
+/*
+Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vcenter_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/library"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vapi/vcenter"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/types"
+
+	_ "github.com/vmware/govmomi/vapi/simulator"
+)
+
+func TestTemplateLibraryItem(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+		if err := c.Login(ctx, simulator.DefaultLogin); err != nil {
+			t.Fatal(err)
+		}
+		m := vcenter.NewManager(c)
+
+		lib, err := library.NewManager(c).CreateLibrary(ctx, library.Library{
+			Name: "test-library",
+			Type: "LOCAL",
+			Storage: []library.StorageBackings{{
+				DatastoreID: simulator.Map.Any("Datastore").Reference().Value,
+				Type:        "DATASTORE",
+			}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		vm := simulator.Map.Any("VirtualMachine").(*simulator.VirtualMachine)
+		task, err := object.NewVirtualMachine(vc, vm.Self).PowerOff(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = task.Wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		id, err := m.CreateTemplate(ctx, vcenter.Template{
+			Name:     "test-template",
+			SourceVM: vm.Self.Value,
+			Library:  lib,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		info, err := m.GetTemplateLibraryItem(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		template := simulator.Map.Get(types.ManagedObjectReference{Type: "VirtualMachine", Value: info.VMTemplate}).(*simulator.VirtualMachine)
+		if !template.Config.Template || template.Name != "test-template" || info.CPU.Count != int(vm.Config.Hardware.NumCPU) {
+			t.Errorf("info=%#v", info)
+		}
+
+		ref, err := m.DeployTemplateLibraryItem(ctx, id, vcenter.DeployTemplate{Name: "test-deploy", PoweredOn: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		deployed := simulator.Map.Get(*ref).(*simulator.VirtualMachine)
+		if deployed.Config.Template || deployed.Runtime.PowerState != types.VirtualMachinePowerStatePoweredOn {
+			t.Errorf("deployed %s template=%t power=%s", deployed.Name, deployed.Config.Template, deployed.Runtime.PowerState)
+		}
+
+		ref, err = m.CheckOut(ctx, id, &vcenter.CheckOut{Name: "test-edit"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkouts, err := m.ListCheckOuts(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(checkouts) != 1 || checkouts[0].VM != ref.Value {
+			t.Errorf("checkouts=%#v", checkouts)
+		}
+
+		version, err := m.CheckIn(ctx, id, *ref, &vcenter.CheckIn{Message: "edited"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if version != "2" {
+			t.Errorf("version=%s", version)
+		}
+		if checkouts, _ = m.ListCheckOuts(ctx, id); len(checkouts) != 0 {
+			t.Errorf("checkouts=%#v", checkouts)
+		}
+
+		versions, err := m.ListTemplateVersions(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(versions) != 2 || versions[1].VMTemplate != ref.Value {
+			t.Errorf("versions=%#v", versions)
+		}
+		if info, _ = m.GetTemplateLibraryItem(ctx, id); info.VMTemplate != ref.Value {
+			t.Errorf("template=%s", info.VMTemplate)
+		}
+	})
+}
@@ -0,0 +1,249 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vm
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/vmware/govmomi/vapi/internal"
+)
+
+// Virtual hardware upgrade policies
+const (
+	UpgradePolicyNever              = "NEVER"
+	UpgradePolicyAfterCleanShutdown = "AFTER_CLEAN_SHUTDOWN"
+	UpgradePolicyAlways             = "ALWAYS"
+)
+
+// Virtual disk host bus adapter types
+const (
+	DiskTypeIDE  = "IDE"
+	DiskTypeSCSI = "SCSI"
+	DiskTypeSATA = "SATA"
+	DiskTypeNVME = "NVME"
+)
+
+// DiskBackingVMDKFile is the backing type of a disk backed by a VMDK file.
+const DiskBackingVMDKFile = "VMDK_FILE"
+
+// Virtual Ethernet adapter types
+const (
+	EthernetTypeE1000   = "E1000"
+	EthernetTypeE1000E  = "E1000E"
+	EthernetTypePCNET32 = "PCNET32"
+	EthernetTypeVMXNET  = "VMXNET"
+	EthernetTypeVMXNET2 = "VMXNET2"
+	EthernetTypeVMXNET3 = "VMXNET3"
+)
+
+// Virtual Ethernet adapter backing types
+const (
+	EthernetBackingStandardPortgroup    = "STANDARD_PORTGROUP"
+	EthernetBackingDistributedPortgroup = "DISTRIBUTED_PORTGROUP"
+	EthernetBackingOpaqueNetwork        = "OPAQUE_NETWORK"
+)
+
+// Hardware describes the virtual hardware version of a VM, such as "VMX_13".
+type Hardware struct {
+	Version        string `json:"version"`
+	UpgradePolicy  string `json:"upgrade_policy"`
+	UpgradeVersion string `json:"upgrade_version,omitempty"`
+	UpgradeStatus  string `json:"upgrade_status"`
+}
+
+// CPU describes the CPU configuration of a VM.
+type CPU struct {
+	Count            int  `json:"count"`
+	CoresPerSocket   int  `json:"cores_per_socket"`
+	HotAddEnabled    bool `json:"hot_add_enabled"`
+	HotRemoveEnabled bool `json:"hot_remove_enabled"`
+}
+
+// CPUUpdate specifies changes to the CPU configuration of a VM, where unset fields are left unchanged.
+type CPUUpdate struct {
+	Count            int   `json:"count,omitempty"`
+	CoresPerSocket   int   `json:"cores_per_socket,omitempty"`
+	HotAddEnabled    *bool `json:"hot_add_enabled,omitempty"`
+	HotRemoveEnabled *bool `json:"hot_remove_enabled,omitempty"`
+}
+
+// Memory describes the memory configuration of a VM.
+type Memory struct {
+	SizeMiB       int64 `json:"size_MiB"`
+	HotAddEnabled bool  `json:"hot_add_enabled"`
+}
+
+// MemoryUpdate specifies changes to the memory configuration of a VM, where unset fields are left unchanged.
+type MemoryUpdate struct {
+	SizeMiB       int64 `json:"size_MiB,omitempty"`
+	HotAddEnabled *bool `json:"hot_add_enabled,omitempty"`
+}
+
+// DiskBacking is the backing of a virtual disk.
+type DiskBacking struct {
+	Type     string `json:"type"`
+	VMDKFile string `json:"vmdk_file,omitempty"`
+}
+
+// DiskInfo describes a virtual disk, where Capacity is in bytes.
+type DiskInfo struct {
+	Label    string      `json:"label"`
+	Type     string      `json:"type"`
+	Capacity int64       `json:"capacity,omitempty"`
+	Backing  DiskBacking `json:"backing"`
+}
+
+// NewVMDK specifies a VMDK file to create for a new virtual disk, where Capacity is in bytes.
+type NewVMDK struct {
+	Name     string `json:"name,omitempty"`
+	Capacity int64  `json:"capacity"`
+}
+
+// DiskCreateSpec specifies a new virtual disk, either backed by a NewVMDK or an existing file.
+type DiskCreateSpec struct {
+	Type    string       `json:"type,omitempty"`
+	NewVMDK *NewVMDK     `json:"new_vmdk,omitempty"`
+	Backing *DiskBacking `json:"backing,omitempty"`
+}
+
+// EthernetBacking is the backing of a virtual Ethernet adapter, where Network is the network ID.
+type EthernetBacking struct {
+	Type    string `json:"type"`
+	Network string `json:"network,omitempty"`
+}
+
+// EthernetInfo describes a virtual Ethernet adapter.
+type EthernetInfo struct {
+	Label          string          `json:"label"`
+	Type           string          `json:"type"`
+	MacAddress     string          `json:"mac_address,omitempty"`
+	StartConnected bool            `json:"start_connected"`
+	State          string          `json:"state"`
+	Backing        EthernetBacking `json:"backing"`
+}
+
+// EthernetCreateSpec specifies a new virtual Ethernet adapter.
+type EthernetCreateSpec struct {
+	Type           string          `json:"type,omitempty"`
+	StartConnected bool            `json:"start_connected,omitempty"`
+	Backing        EthernetBacking `json:"backing"`
+}
+
+func (c *Manager) hardware(id string) *internal.Resource {
+	return c.vm(id).WithSubpath("hardware")
+}
+
+// GetHardware returns the virtual hardware version of the given VM.
+func (c *Manager) GetHardware(ctx context.Context, id string) (*Hardware, error) {
+	var res Hardware
+	return &res, c.Do(ctx, c.hardware(id).Request(ctx, http.MethodGet), &res)
+}
+
+// UpgradeHardware upgrades the given VM to the virtual hardware version, defaulting to the latest supported version.
+func (c *Manager) UpgradeHardware(ctx context.Context, id string, version string) error {
+	body := struct {
+		Version string `json:"version,omitempty"`
+	}{version}
+	return c.Do(ctx, c.hardware(id).WithAction("upgrade").Request(ctx, http.MethodPost, body), nil)
+}
+
+// GetCPU returns the CPU configuration of the given VM.
+func (c *Manager) GetCPU(ctx context.Context, id string) (*CPU, error) {
+	var res CPU
+	return &res, c.Do(ctx, c.hardware(id).WithSubpath("cpu").Request(ctx, http.MethodGet), &res)
+}
+
+// UpdateCPU changes the CPU configuration of the given VM.
+func (c *Manager) UpdateCPU(ctx context.Context, id string, spec CPUUpdate) error {
+	return c.Do(ctx, c.hardware(id).WithSubpath("cpu").Request(ctx, http.MethodPatch, spec), nil)
+}
+
+// GetMemory returns the memory configuration of the given VM.
+func (c *Manager) GetMemory(ctx context.Context, id string) (*Memory, error) {
+	var res Memory
+	return &res, c.Do(ctx, c.hardware(id).WithSubpath("memory").Request(ctx, http.MethodGet), &res)
+}
+
+// UpdateMemory changes the memory configuration of the given VM.
+func (c *Manager) UpdateMemory(ctx context.Context, id string, spec MemoryUpdate) error {
+	return c.Do(ctx, c.hardware(id).WithSubpath("memory").Request(ctx, http.MethodPatch, spec), nil)
+}
+
+// ListDisks returns the IDs of the virtual disks of the given VM.
+func (c *Manager) ListDisks(ctx context.Context, id string) ([]string, error) {
+	var res []struct {
+		Disk string `json:"disk"`
+	}
+	if err := c.Do(ctx, c.hardware(id).WithSubpath("disk").Request(ctx, http.MethodGet), &res); err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(res))
+	for i := range res {
+		ids[i] = res[i].Disk
+	}
+	return ids, nil
+}
+
+// GetDisk returns the given virtual disk of a VM.
+func (c *Manager) GetDisk(ctx context.Context, id string, disk string) (*DiskInfo, error) {
+	var res DiskInfo
+	return &res, c.Do(ctx, c.hardware(id).WithSubpath("disk").WithSubpath(disk).Request(ctx, http.MethodGet), &res)
+}
+
+// CreateDisk adds a virtual disk to the given VM, returning the disk ID.
+func (c *Manager) CreateDisk(ctx context.Context, id string, spec DiskCreateSpec) (string, error) {
+	var res string
+	return res, c.Do(ctx, c.hardware(id).WithSubpath("disk").Request(ctx, http.MethodPost, spec), &res)
+}
+
+// DeleteDisk removes the given virtual disk from a VM, without deleting its backing file.
+func (c *Manager) DeleteDisk(ctx context.Context, id string, disk string) error {
+	return c.Do(ctx, c.hardware(id).WithSubpath("disk").WithSubpath(disk).Request(ctx, http.MethodDelete), nil)
+}
+
+// ListEthernet returns the IDs of the virtual Ethernet adapters of the given VM.
+func (c *Manager) ListEthernet(ctx context.Context, id string) ([]string, error) {
+	var res []struct {
+		NIC string `json:"nic"`
+	}
+	if err := c.Do(ctx, c.hardware(id).WithSubpath("ethernet").Request(ctx, http.MethodGet), &res); err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(res))
+	for i := range res {
+		ids[i] = res[i].NIC
+	}
+	return ids, nil
+}
+
+// GetEthernet returns the given virtual Ethernet adapter of a VM.
+func (c *Manager) GetEthernet(ctx context.Context, id string, nic string) (*EthernetInfo, error) {
+	var res EthernetInfo
+	return &res, c.Do(ctx, c.hardware(id).WithSubpath("ethernet").WithSubpath(nic).Request(ctx, http.MethodGet), &res)
+}
+
+// CreateEthernet adds a virtual Ethernet adapter to the given VM, returning the adapter ID.
+func (c *Manager) CreateEthernet(ctx context.Context, id string, spec EthernetCreateSpec) (string, error) {
+	var res string
+	return res, c.Do(ctx, c.hardware(id).WithSubpath("ethernet").Request(ctx, http.MethodPost, spec), &res)
+}
+
+// DeleteEthernet removes the given virtual Ethernet adapter from a VM.
+func (c *Manager) DeleteEthernet(ctx context.Context, id string, nic string) error {
+	return c.Do(ctx, c.hardware(id).WithSubpath("ethernet").WithSubpath(nic).Request(ctx, http.MethodDelete), nil)
+}
@@ -0,0 +1,65 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vm
+
+import (
+	"context"
+	"net/http"
+)
+
+// VM power states
+const (
+	PowerStateOn        = "POWERED_ON"
+	PowerStateOff       = "POWERED_OFF"
+	PowerStateSuspended = "SUSPENDED"
+)
+
+// Power describes the power state of a VM.
+type Power struct {
+	State string `json:"state"`
+}
+
+// GetPower returns the power state of the given VM.
+func (c *Manager) GetPower(ctx context.Context, id string) (*Power, error) {
+	var res Power
+	return &res, c.Do(ctx, c.vm(id).WithSubpath("power").Request(ctx, http.MethodGet), &res)
+}
+
+func (c *Manager) power(ctx context.Context, id string, action string) error {
+	url := c.vm(id).WithSubpath("power").WithAction(action)
+	return c.Do(ctx, url.Request(ctx, http.MethodPost), nil)
+}
+
+// PowerOn powers on the given VM, which must be powered off or suspended.
+func (c *Manager) PowerOn(ctx context.Context, id string) error {
+	return c.power(ctx, id, "start")
+}
+
+// PowerOff powers off the given VM, without shutting down the guest.
+func (c *Manager) PowerOff(ctx context.Context, id string) error {
+	return c.power(ctx, id, "stop")
+}
+
+// Suspend suspends the given VM, which must be powered on.
+func (c *Manager) Suspend(ctx context.Context, id string) error {
+	return c.power(ctx, id, "suspend")
+}
+
+// Reset resets the given VM, which must be powered on.
+func (c *Manager) Reset(ctx context.Context, id string) error {
+	return c.power(ctx, id, "reset")
+}
@@ -0,0 +1,145 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vm
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/vmware/govmomi/vapi/internal"
+	"github.com/vmware/govmomi/vapi/rest"
+)
+
+// Manager extends rest.Client, adding vcenter VM related methods.
+type Manager struct {
+	*rest.Client
+}
+
+// NewManager creates a new Manager instance with the given client.
+func NewManager(client *rest.Client) *Manager {
+	return &Manager{
+		Client: client,
+	}
+}
+
+// FilterSpec specifies the VMs to match in a List request, an empty field matches any VM.
+type FilterSpec struct {
+	VMs           []string
+	Names         []string
+	Folders       []string
+	Datacenters   []string
+	Hosts         []string
+	Clusters      []string
+	ResourcePools []string
+	PowerStates   []string
+}
+
+// Summary describes a VM.
+type Summary struct {
+	VM         string `json:"vm"`
+	Name       string `json:"name"`
+	PowerState string `json:"power_state"`
+	CPUCount   int    `json:"cpu_count,omitempty"`
+	MemorySize int64  `json:"memory_size_MiB,omitempty"`
+}
+
+// Identity of a VM.
+type Identity struct {
+	Name         string `json:"name"`
+	BiosUUID     string `json:"bios_uuid"`
+	InstanceUUID string `json:"instance_uuid"`
+}
+
+// Info describes a VM in detail, where Disks and Nics are keyed by device ID.
+type Info struct {
+	Name       string                  `json:"name"`
+	GuestOS    string                  `json:"guest_OS"`
+	PowerState string                  `json:"power_state"`
+	Identity   *Identity               `json:"identity,omitempty"`
+	Hardware   Hardware                `json:"hardware"`
+	CPU        CPU                     `json:"cpu"`
+	Memory     Memory                  `json:"memory"`
+	Disks      map[string]DiskInfo     `json:"disks"`
+	Nics       map[string]EthernetInfo `json:"nics"`
+}
+
+// PlacementSpec specifies where a new VM is created.
+// Folder and Datastore are required, along with one of ResourcePool, Host or Cluster.
+type PlacementSpec struct {
+	Folder       string `json:"folder"`
+	ResourcePool string `json:"resource_pool,omitempty"`
+	Host         string `json:"host,omitempty"`
+	Cluster      string `json:"cluster,omitempty"`
+	Datastore    string `json:"datastore"`
+}
+
+// CreateSpec specifies a new VM.
+type CreateSpec struct {
+	Name            string        `json:"name"`
+	GuestOS         string        `json:"guest_OS"`
+	Placement       PlacementSpec `json:"placement"`
+	HardwareVersion string        `json:"hardware_version,omitempty"`
+	CPU             *CPUUpdate    `json:"cpu,omitempty"`
+	Memory          *MemoryUpdate `json:"memory,omitempty"`
+}
+
+func (c *Manager) vm(id string) *internal.Resource {
+	return internal.URL(c, internal.VCenterVMPath).WithSubpath(id)
+}
+
+// List returns the VMs matching the given filter, up to 4000 VMs.
+func (c *Manager) List(ctx context.Context, filter FilterSpec) ([]Summary, error) {
+	url := internal.URL(c, internal.VCenterVMPath)
+	params := []struct {
+		name   string
+		values []string
+	}{
+		{"vms", filter.VMs},
+		{"names", filter.Names},
+		{"folders", filter.Folders},
+		{"datacenters", filter.Datacenters},
+		{"hosts", filter.Hosts},
+		{"clusters", filter.Clusters},
+		{"resource_pools", filter.ResourcePools},
+		{"power_states", filter.PowerStates},
+	}
+	for _, p := range params {
+		for _, value := range p.values {
+			url = url.WithParameter(p.name, value)
+		}
+	}
+	var res []Summary
+	return res, c.Do(ctx, url.Request(ctx, http.MethodGet), &res)
+}
+
+// Get returns the given VM.
+func (c *Manager) Get(ctx context.Context, id string) (*Info, error) {
+	var res Info
+	return &res, c.Do(ctx, c.vm(id).Request(ctx, http.MethodGet), &res)
+}
+
+// Create creates a VM, returning its ID.
+func (c *Manager) Create(ctx context.Context, spec CreateSpec) (string, error) {
+	url := internal.URL(c, internal.VCenterVMPath)
+	var res string
+	return res, c.Do(ctx, url.Request(ctx, http.MethodPost, spec), &res)
+}
+
+// Delete deletes the given VM, which must be powered off.
+func (c *Manager) Delete(ctx context.Context, id string) error {
+	return c.Do(ctx, c.vm(id).Request(ctx, http.MethodDelete), nil)
+}
@@ -0,0 +1,227 @@
+/*
+Copyright (c) 2019 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vm_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vapi/vcenter/vm"
+	"github.com/vmware/govmomi/vim25"
+
+	_ "github.com/vmware/govmomi/vapi/simulator"
+)
+
+func TestVM(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+		if err := c.Login(ctx, simulator.DefaultLogin); err != nil {
+			t.Fatal(err)
+		}
+
+		m := vm.NewManager(c)
+
+		vms, err := m.List(ctx, vm.FilterSpec{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(vms) == 0 {
+			t.Fatal("no vms")
+		}
+
+		vms, err = m.List(ctx, vm.FilterSpec{Names: []string{vms[0].Name}, PowerStates: []string{vms[0].PowerState}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(vms) != 1 {
+			t.Errorf("vms=%#v", vms)
+		}
+
+		dc := simulator.Map.Any("Datacenter").(*simulator.Datacenter)
+		spec := vm.CreateSpec{
+			Name:    "rest-vm",
+			GuestOS: "OTHER_LINUX_64",
+			Placement: vm.PlacementSpec{
+				Folder:    dc.VmFolder.Value,
+				Cluster:   simulator.Map.Any("ClusterComputeResource").Reference().Value,
+				Datastore: simulator.Map.Any("Datastore").Reference().Value,
+			},
+			CPU:    &vm.CPUUpdate{Count: 2},
+			Memory: &vm.MemoryUpdate{SizeMiB: 2048},
+		}
+
+		var ia *rest.InvalidArgument
+		invalid := spec
+		invalid.GuestOS = "PLAN_9"
+		if _, err = m.Create(ctx, invalid); !errors.As(err, &ia) {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		id, err := m.Create(ctx, spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		info, err := m.Get(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Name != spec.Name || info.GuestOS != spec.GuestOS || info.PowerState != vm.PowerStateOff ||
+			info.CPU.Count != 2 || info.Memory.SizeMiB != 2048 {
+			t.Errorf("info=%#v", info)
+		}
+
+		if err = m.PowerOn(ctx, id); err != nil {
+			t.Fatal(err)
+		}
+		if err = m.PowerOn(ctx, id); err == nil {
+			t.Error("expected error")
+		}
+		power, err := m.GetPower(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if power.State != vm.PowerStateOn {
+			t.Errorf("power=%#v", power)
+		}
+		if err = m.Delete(ctx, id); err == nil {
+			t.Error("expected error")
+		}
+		if err = m.PowerOff(ctx, id); err != nil {
+			t.Fatal(err)
+		}
+
+		vms, err = m.List(ctx, vm.FilterSpec{VMs: []string{id}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(vms) != 1 || vms[0].PowerState != vm.PowerStateOff || vms[0].CPUCount != 2 {
+			t.Errorf("vms=%#v", vms)
+		}
+
+		if err = m.Delete(ctx, id); err != nil {
+			t.Fatal(err)
+		}
+		var nf *rest.NotFound
+		if _, err = m.Get(ctx, id); !errors.As(err, &nf) {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
+
+func TestVMHardware(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+		if err := c.Login(ctx, simulator.DefaultLogin); err != nil {
+			t.Fatal(err)
+		}
+
+		m := vm.NewManager(c)
+		id := simulator.Map.Any("VirtualMachine").Reference().Value
+
+		hw, err := m.GetHardware(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hw.Version == "" || hw.UpgradePolicy != vm.UpgradePolicyNever {
+			t.Errorf("hardware=%#v", hw)
+		}
+
+		enabled := true
+		if err = m.UpdateCPU(ctx, id, vm.CPUUpdate{Count: 4, HotAddEnabled: &enabled}); err != nil {
+			t.Fatal(err)
+		}
+		cpu, err := m.GetCPU(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cpu.Count != 4 || !cpu.HotAddEnabled {
+			t.Errorf("cpu=%#v", cpu)
+		}
+
+		if err = m.UpdateMemory(ctx, id, vm.MemoryUpdate{SizeMiB: 4096}); err != nil {
+			t.Fatal(err)
+		}
+		memory, err := m.GetMemory(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if memory.SizeMiB != 4096 {
+			t.Errorf("memory=%#v", memory)
+		}
+
+		disks, err := m.ListDisks(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		disk, err := m.CreateDisk(ctx, id, vm.DiskCreateSpec{NewVMDK: &vm.NewVMDK{Capacity: 1 << 30}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		info, err := m.GetDisk(ctx, id, disk)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Type != vm.DiskTypeSCSI || info.Capacity != 1<<30 || info.Backing.VMDKFile == "" {
+			t.Errorf("disk=%#v", info)
+		}
+		if err = m.DeleteDisk(ctx, id, disk); err != nil {
+			t.Fatal(err)
+		}
+		after, err := m.ListDisks(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(after) != len(disks) {
+			t.Errorf("disks=%v", after)
+		}
+
+		network := simulator.Map.Any("Network").Reference().Value
+		nic, err := m.CreateEthernet(ctx, id, vm.EthernetCreateSpec{
+			Type:    vm.EthernetTypeVMXNET3,
+			Backing: vm.EthernetBacking{Type: vm.EthernetBackingStandardPortgroup, Network: network},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		eth, err := m.GetEthernet(ctx, id, nic)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if eth.Type != vm.EthernetTypeVMXNET3 || eth.Backing.Network != network || eth.MacAddress == "" {
+			t.Errorf("ethernet=%#v", eth)
+		}
+		nics, err := m.ListEthernet(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = m.DeleteEthernet(ctx, id, nic); err != nil {
+			t.Fatal(err)
+		}
+
+		var nf *rest.NotFound
+		if _, err = m.GetEthernet(ctx, id, nic); !errors.As(err, &nf) {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if len(nics) < 2 {
+			t.Errorf("nics=%v", nics)
+		}
+	})
+}
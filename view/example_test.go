@@ -27,6 +27,7 @@ import (
 	"github.com/vmware/govmomi/view"
 	"github.com/vmware/govmomi/vim25"
 	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
 )
 
 // Create a view of all hosts in the inventory, printing host names that belong to a cluster and excluding standalone hosts.
@@ -96,3 +97,106 @@ func ExampleContainerView_RetrieveWithFilter() {
 	})
 	// Output: [DC0_C0_RP0_VM1 DC0_H0_VM1]
 }
+
+// Retrieve the power state of all VMs in the inventory, without managing a ContainerView.
+func ExampleManager_Retrieve() {
+	simulator.Run(func(ctx context.Context, c *vim25.Client) error {
+		m := view.NewManager(c)
+
+		var vms []mo.VirtualMachine
+		var names []string
+
+		err := m.Retrieve(ctx, c.ServiceContent.RootFolder, []string{"VirtualMachine"}, []string{"name", "runtime.powerState"}, &vms)
+		if err != nil {
+			return err
+		}
+
+		for _, vm := range vms {
+			names = append(names, fmt.Sprintf("%s=%s", vm.Name, vm.Runtime.PowerState))
+		}
+
+		sort.Strings(names)
+		fmt.Println(names)
+
+		return nil
+	})
+	// Output: [DC0_C0_RP0_VM0=poweredOn DC0_C0_RP0_VM1=poweredOn DC0_H0_VM0=poweredOn DC0_H0_VM1=poweredOn]
+}
+
+// Retrieve VM names that end with "_VM1", without managing a ContainerView.
+func ExampleManager_RetrieveWithFilter() {
+	simulator.Run(func(ctx context.Context, c *vim25.Client) error {
+		m := view.NewManager(c)
+
+		var vms []mo.VirtualMachine
+		var names []string
+
+		err := m.RetrieveWithFilter(ctx, c.ServiceContent.RootFolder, []string{"VirtualMachine"}, []string{"name"}, &vms, property.Filter{"name": "*_VM1"})
+		if err != nil {
+			return err
+		}
+
+		for _, vm := range vms {
+			names = append(names, vm.Name)
+		}
+
+		sort.Strings(names)
+		fmt.Println(names)
+
+		return nil
+	})
+	// Output: [DC0_C0_RP0_VM1 DC0_H0_VM1]
+}
+
+// Watch the power state of a dynamic set of VMs using a ListView.
+func ExampleListView_Modify() {
+	simulator.Run(func(ctx context.Context, c *vim25.Client) error {
+		m := view.NewManager(c)
+		kind := []string{"VirtualMachine"}
+
+		var vms []mo.VirtualMachine
+		err := m.Retrieve(ctx, c.ServiceContent.RootFolder, kind, []string{"name"}, &vms)
+		if err != nil {
+			return err
+		}
+
+		names := make(map[types.ManagedObjectReference]string)
+		for _, vm := range vms {
+			names[vm.Self] = vm.Name
+		}
+		sort.Slice(vms, func(i, j int) bool { return vms[i].Name < vms[j].Name })
+
+		v, err := m.CreateListView(ctx, []types.ManagedObjectReference{vms[0].Self})
+		if err != nil {
+			return err
+		}
+
+		filter := v.WaitFilter(kind, []string{"runtime.powerState"})
+
+		swapped := false
+
+		err = property.WaitForUpdates(ctx, property.DefaultCollector(c), filter, func(updates []types.ObjectUpdate) bool {
+			for _, update := range updates {
+				fmt.Printf("%s %s\n", update.Kind, names[update.Obj])
+			}
+
+			if swapped {
+				return true
+			}
+
+			// Swap the first VM for the second
+			swapped = true
+			_, err = v.Modify(ctx, []types.ManagedObjectReference{vms[1].Self}, []types.ManagedObjectReference{vms[0].Self})
+			return err != nil
+		})
+		if err != nil {
+			return err
+		}
+
+		return v.Destroy(ctx)
+	})
+	// Output:
+	// enter DC0_C0_RP0_VM0
+	// leave DC0_C0_RP0_VM0
+	// enter DC0_C0_RP0_VM1
+}
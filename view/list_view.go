@@ -34,22 +34,32 @@ func NewListView(c *vim25.Client, ref types.ManagedObjectReference) *ListView {
 	}
 }
 
+// Add adds the given objects to the view, see Modify for the objects that could not be resolved.
 func (v ListView) Add(ctx context.Context, refs []types.ManagedObjectReference) error {
-	req := types.ModifyListView{
-		This: v.Reference(),
-		Add:  refs,
-	}
-	_, err := methods.ModifyListView(ctx, v.Client(), &req)
+	_, err := v.Modify(ctx, refs, nil)
 	return err
 }
 
+// Remove removes the given objects from the view.
 func (v ListView) Remove(ctx context.Context, refs []types.ManagedObjectReference) error {
+	_, err := v.Modify(ctx, nil, refs)
+	return err
+}
+
+// Modify adds and removes the given objects to and from the view, returning the objects that could not be resolved.
+func (v ListView) Modify(ctx context.Context, add []types.ManagedObjectReference, remove []types.ManagedObjectReference) ([]types.ManagedObjectReference, error) {
 	req := types.ModifyListView{
 		This:   v.Reference(),
-		Remove: refs,
+		Add:    add,
+		Remove: remove,
 	}
-	_, err := methods.ModifyListView(ctx, v.Client(), &req)
-	return err
+
+	res, err := methods.ModifyListView(ctx, v.Client(), &req)
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Returnval, nil
 }
 
 func (v ListView) Reset(ctx context.Context, refs []types.ManagedObjectReference) error {
@@ -20,6 +20,7 @@ import (
 	"context"
 
 	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/property"
 	"github.com/vmware/govmomi/vim25"
 	"github.com/vmware/govmomi/vim25/methods"
 	"github.com/vmware/govmomi/vim25/types"
@@ -42,6 +43,35 @@ func (v *ManagedObjectView) TraversalSpec() *types.TraversalSpec {
 	}
 }
 
+// WaitFilter returns a property.WaitFilter for the properties ps of the objects in the view of the types
+// specified by kind, for use with property.WaitForUpdates. If ps is empty, all properties are included.
+// Objects added to or removed from the view are reported as enter and leave updates respectively.
+func (v *ManagedObjectView) WaitFilter(kind []string, ps []string) *property.WaitFilter {
+	filter := new(property.WaitFilter)
+
+	filter.Spec.ObjectSet = []types.ObjectSpec{{
+		Obj:       v.Reference(),
+		Skip:      types.NewBool(true),
+		SelectSet: []types.BaseSelectionSpec{v.TraversalSpec()},
+	}}
+
+	for _, t := range kind {
+		spec := types.PropertySpec{
+			Type: t,
+		}
+
+		if len(ps) == 0 {
+			spec.All = types.NewBool(true)
+		} else {
+			spec.PathSet = ps
+		}
+
+		filter.Spec.PropSet = append(filter.Spec.PropSet, spec)
+	}
+
+	return filter
+}
+
 func (v *ManagedObjectView) Destroy(ctx context.Context) error {
 	req := types.DestroyView{
 		This: v.Reference(),
@@ -20,6 +20,7 @@ import (
 	"context"
 
 	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/property"
 	"github.com/vmware/govmomi/vim25"
 	"github.com/vmware/govmomi/vim25/methods"
 	"github.com/vmware/govmomi/vim25/types"
@@ -67,3 +68,35 @@ func (m Manager) CreateContainerView(ctx context.Context, container types.Manage
 
 	return NewContainerView(m.Client(), res.Returnval), nil
 }
+
+// withContainerView creates a recursive ContainerView of the given root and kind, calling f with the view
+// before destroying it.
+func (m Manager) withContainerView(ctx context.Context, root types.ManagedObjectReference, kind []string, f func(*ContainerView) error) error {
+	v, err := m.CreateContainerView(ctx, root, kind, true)
+	if err != nil {
+		return err
+	}
+
+	// Attempt to destroy the view using the background context, as the
+	// specified context may have timed out or have been canceled.
+	defer func() {
+		_ = v.Destroy(context.Background())
+	}()
+
+	return f(v)
+}
+
+// Retrieve populates dst as ContainerView.Retrieve does, for all entities of the types specified by kind
+// contained within root, using a ContainerView that is created and destroyed by this method.
+func (m Manager) Retrieve(ctx context.Context, root types.ManagedObjectReference, kind []string, ps []string, dst interface{}) error {
+	return m.withContainerView(ctx, root, kind, func(v *ContainerView) error {
+		return v.Retrieve(ctx, kind, ps, dst)
+	})
+}
+
+// RetrieveWithFilter populates dst as Retrieve does, but only for entities matching the given filter.
+func (m Manager) RetrieveWithFilter(ctx context.Context, root types.ManagedObjectReference, kind []string, ps []string, dst interface{}, filter property.Filter) error {
+	return m.withContainerView(ctx, root, kind, func(v *ContainerView) error {
+		return v.RetrieveWithFilter(ctx, kind, ps, dst, filter)
+	})
+}
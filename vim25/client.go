@@ -56,6 +56,9 @@ type Client struct {
 	// the RoundTripper interface can be wrapped by separate implementations for
 	// extra functionality (for example, reauthentication on session timeout).
 	RoundTripper soap.RoundTripper
+
+	// RetryPolicy, if set, is used to retry transient errors returned by RoundTripper.
+	RetryPolicy *RetryPolicy
 }
 
 // NewClient creates and returns a new client with the ServiceContent field
@@ -117,6 +120,9 @@ func (c *Client) UseServiceVersion() error {
 
 // RoundTrip dispatches to the RoundTripper field.
 func (c *Client) RoundTrip(ctx context.Context, req, res soap.HasFault) error {
+	if c.RetryPolicy != nil {
+		return c.RetryPolicy.RoundTrip(ctx, c.RoundTripper, req, res)
+	}
 	return c.RoundTripper.RoundTrip(ctx, req, res)
 }
 
@@ -32,6 +32,8 @@ type Provider interface {
 
 var currentProvider Provider = nil
 
+var redact = true
+
 func SetProvider(p Provider) {
 	if currentProvider != nil {
 		currentProvider.Flush()
@@ -44,6 +46,17 @@ func Enabled() bool {
 	return currentProvider != nil
 }
 
+// SetRedaction enables or disables redaction of credentials, such as passwords,
+// session cookies and SAML tokens, from debug captures. Redaction is enabled by default.
+func SetRedaction(enabled bool) {
+	redact = enabled
+}
+
+// Redaction returns whether credentials are redacted from debug captures.
+func Redaction() bool {
+	return redact
+}
+
 // NewFile dispatches to the current provider's NewFile function.
 func NewFile(s string) io.WriteCloser {
 	return currentProvider.NewFile(s)
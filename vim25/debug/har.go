@@ -0,0 +1,312 @@
+/*
+Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// HARProvider implements a debugging provider that collects each request and
+// response into an HTTP Archive (HAR) entry, such that traces can be loaded
+// into standard HTTP analysis tools. The archive is written to Path when the
+// Flush function is called.
+// Request URLs are recorded with the https scheme, as the scheme is not
+// included in the captured request headers.
+type HARProvider struct {
+	Path string
+
+	mu      sync.Mutex
+	entries map[string]*harRoundTrip
+}
+
+// harRoundTrip collects the files of a single round trip.
+type harRoundTrip struct {
+	start, wait, done time.Time
+
+	reqHeaders, resHeaders []byte
+	reqBody, resBody       []byte
+	ext                    string
+}
+
+// harFile buffers the content of a single debug file, passing it to fn when closed.
+type harFile struct {
+	bytes.Buffer
+	fn func([]byte)
+}
+
+func (f *harFile) Close() error {
+	f.fn(f.Bytes())
+	return nil
+}
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
+
+// NewFile is called with names such as "1-0001.req.headers", "1-0001.res.xml" or "1-client.log".
+func (hp *HARProvider) NewFile(p string) io.WriteCloser {
+	now := time.Now()
+
+	parts := strings.SplitN(p, ".", 3)
+	if len(parts) != 3 {
+		return nopCloser{ioutil.Discard} // client log
+	}
+	id, kind, ext := parts[0], parts[1], parts[2]
+
+	hp.mu.Lock()
+	defer hp.mu.Unlock()
+
+	if hp.entries == nil {
+		hp.entries = make(map[string]*harRoundTrip)
+	}
+
+	rt, ok := hp.entries[id]
+	if !ok {
+		rt = &harRoundTrip{start: now}
+		hp.entries[id] = rt
+	}
+
+	var fn func([]byte)
+
+	switch kind + "." + ext {
+	case "req.headers":
+		fn = func(b []byte) { rt.reqHeaders = b }
+	case "res.headers":
+		rt.wait = now
+		fn = func(b []byte) { rt.resHeaders = b }
+	default:
+		if kind == "req" {
+			fn = func(b []byte) { rt.reqBody = b }
+		} else {
+			rt.ext = ext
+			fn = func(b []byte) { rt.resBody = b }
+		}
+	}
+
+	return &harFile{fn: func(b []byte) {
+		b = append([]byte(nil), b...)
+
+		hp.mu.Lock()
+		defer hp.mu.Unlock()
+
+		fn(b)
+		if kind == "res" {
+			rt.done = time.Now()
+		}
+	}}
+}
+
+// Flush writes all round trips captured so far to Path.
+func (hp *HARProvider) Flush() {
+	hp.mu.Lock()
+	ids := make([]string, 0, len(hp.entries))
+	for id := range hp.entries {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return hp.entries[ids[i]].start.Before(hp.entries[ids[j]].start)
+	})
+
+	har := harLog{
+		Version: "1.2",
+		Creator: harCreator{Name: "govmomi", Version: "1.0"},
+		Entries: make([]harEntry, 0, len(ids)),
+	}
+	for _, id := range ids {
+		har.Entries = append(har.Entries, hp.entries[id].entry())
+	}
+	hp.mu.Unlock()
+
+	f, err := os.Create(hp.Path)
+	if err != nil {
+		panic(err)
+	}
+	defer f.Close()
+
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	err = enc.Encode(struct {
+		Log harLog `json:"log"`
+	}{har})
+	if err != nil {
+		panic(err)
+	}
+}
+
+func harHeaders(h http.Header) []harNameValue {
+	nv := []harNameValue{}
+	for name, values := range h {
+		for _, value := range values {
+			nv = append(nv, harNameValue{Name: name, Value: value})
+		}
+	}
+	sort.Slice(nv, func(i, j int) bool { return nv[i].Name < nv[j].Name })
+	return nv
+}
+
+func harMillis(d time.Duration) float64 {
+	if d < 0 {
+		return -1
+	}
+	return float64(d) / float64(time.Millisecond)
+}
+
+func (rt *harRoundTrip) entry() harEntry {
+	e := harEntry{
+		StartedDateTime: rt.start.Format(time.RFC3339Nano),
+		Request: harRequest{
+			Headers:     []harNameValue{},
+			QueryString: []harNameValue{},
+			Cookies:     []harNameValue{},
+			HeadersSize: -1,
+			BodySize:    len(rt.reqBody),
+		},
+		Response: harResponse{
+			Headers:     []harNameValue{},
+			Cookies:     []harNameValue{},
+			HeadersSize: -1,
+			BodySize:    len(rt.resBody),
+		},
+		Cache: struct{}{},
+	}
+
+	if req, err := http.ReadRequest(bufio.NewReader(bytes.NewReader(rt.reqHeaders))); err == nil {
+		req.URL.Scheme = "https"
+		req.URL.Host = req.Host
+
+		e.Request.Method = req.Method
+		e.Request.URL = req.URL.String()
+		e.Request.HTTPVersion = req.Proto
+		e.Request.Headers = harHeaders(req.Header)
+		for name, values := range req.URL.Query() {
+			for _, value := range values {
+				e.Request.QueryString = append(e.Request.QueryString, harNameValue{Name: name, Value: value})
+			}
+		}
+		if len(rt.reqBody) != 0 {
+			e.Request.PostData = &harPostData{
+				MimeType: req.Header.Get("Content-Type"),
+				Text:     string(rt.reqBody),
+			}
+		}
+	}
+
+	e.Response.Content.Size = len(rt.resBody)
+	e.Response.Content.Text = string(rt.resBody)
+
+	if res, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(rt.resHeaders)), nil); err == nil {
+		e.Response.Status = res.StatusCode
+		e.Response.StatusText = http.StatusText(res.StatusCode)
+		e.Response.HTTPVersion = res.Proto
+		e.Response.Headers = harHeaders(res.Header)
+		e.Response.Content.MimeType = res.Header.Get("Content-Type")
+	}
+	if e.Response.Content.MimeType == "" && rt.ext != "" {
+		e.Response.Content.MimeType = "application/" + rt.ext
+	}
+
+	e.Timings.Send = 0
+	e.Timings.Wait = -1
+	e.Timings.Receive = -1
+	if !rt.wait.IsZero() {
+		e.Timings.Wait = harMillis(rt.wait.Sub(rt.start))
+		if !rt.done.IsZero() {
+			e.Timings.Receive = harMillis(rt.done.Sub(rt.wait))
+		}
+		e.Time = e.Timings.Wait + e.Timings.Receive
+	}
+
+	return e
+}
+
+// HAR 1.2 format, see http://www.softwareishard.com/blog/har-12-spec/
+
+type harLog struct {
+	Version string     `json:"version"`
+	Creator harCreator `json:"creator"`
+	Entries []harEntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         struct {
+		Send    float64 `json:"send"`
+		Wait    float64 `json:"wait"`
+		Receive float64 `json:"receive"`
+	} `json:"timings"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	Cookies     []harNameValue `json:"cookies"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Headers     []harNameValue `json:"headers"`
+	Cookies     []harNameValue `json:"cookies"`
+	Content     struct {
+		Size     int    `json:"size"`
+		MimeType string `json:"mimeType"`
+		Text     string `json:"text"`
+	} `json:"content"`
+	RedirectURL string `json:"redirectURL"`
+	HeadersSize int    `json:"headersSize"`
+	BodySize    int    `json:"bodySize"`
+}
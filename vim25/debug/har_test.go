@@ -0,0 +1,105 @@
+/*
+Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/debug"
+	"github.com/vmware/govmomi/vim25/methods"
+)
+
+func TestHARProvider(t *testing.T) {
+	dir, err := ioutil.TempDir("", "govmomi-har")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	p := &debug.HARProvider{Path: filepath.Join(dir, "trace.har")}
+	debug.SetProvider(p)
+	defer debug.SetProvider(nil)
+
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		_, err := methods.GetCurrentTime(ctx, c)
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	debug.Flush()
+
+	b, err := ioutil.ReadFile(p.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var har struct {
+		Log struct {
+			Version string
+			Entries []struct {
+				Request struct {
+					Method   string
+					URL      string
+					PostData struct {
+						Text string
+					}
+				}
+				Response struct {
+					Status  int
+					Content struct {
+						Text string
+					}
+				}
+			}
+		}
+	}
+
+	if err = json.Unmarshal(b, &har); err != nil {
+		t.Fatal(err)
+	}
+
+	if har.Log.Version != "1.2" {
+		t.Errorf("version=%s", har.Log.Version)
+	}
+
+	found := false
+	for _, e := range har.Log.Entries {
+		if !strings.Contains(e.Request.PostData.Text, "<CurrentTime") {
+			continue
+		}
+		found = true
+		if e.Request.Method != "POST" || !strings.HasSuffix(e.Request.URL, "/sdk") {
+			t.Errorf("request=%s %s", e.Request.Method, e.Request.URL)
+		}
+		if e.Response.Status != 200 || !strings.Contains(e.Response.Content.Text, "CurrentTimeResponse") {
+			t.Errorf("response=%d %s", e.Response.Status, e.Response.Content.Text)
+		}
+	}
+
+	if !found {
+		t.Errorf("CurrentTime entry not found in %d entries", len(har.Log.Entries))
+	}
+}
@@ -256,3 +256,18 @@ func (t *typeInfo) LoadFromObjectContent(o types.ObjectContent) (reflect.Value,
 
 	return v, nil
 }
+
+// IsProperty returns true if name is a property of the managed object type kind.
+// Only the top-level property of a nested property path, such as "summary.runtime", is validated.
+func IsProperty(kind string, name string) bool {
+	if _, ok := t[kind]; !ok {
+		return false
+	}
+
+	if i := strings.IndexAny(name, ".["); i > 0 {
+		name = name[:i]
+	}
+
+	_, ok := typeInfoForType(kind).props[name]
+	return ok
+}
@@ -41,6 +41,26 @@ func TestApplyPropertyChange(t *testing.T) {
 	ApplyPropertyChange(vm, changes)
 }
 
+func TestIsProperty(t *testing.T) {
+	tests := []struct {
+		kind, name string
+		ok         bool
+	}{
+		{"VirtualMachine", "name", true},
+		{"VirtualMachine", "summary.runtime.powerState", true},
+		{"VirtualMachine", "config.hardware.device[4000]", true},
+		{"ClusterComputeResource", "host", true},
+		{"VirtualMachine", "host", false},
+		{"NoSuchType", "name", false},
+	}
+
+	for _, test := range tests {
+		if IsProperty(test.kind, test.name) != test.ok {
+			t.Errorf("%s.%s: expected %t", test.kind, test.name, test.ok)
+		}
+	}
+}
+
 // The virtual machine managed object has about 500 nested properties.
 // It's likely to be indicative of the function's performance in general.
 func BenchmarkLoadVirtualMachine(b *testing.B) {
@@ -18,11 +18,16 @@ package vim25
 
 import (
 	"context"
+	"io"
 	"net"
 	"net/url"
+	"os"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/govmomi/vim25/types"
 )
 
 type RetryFunc func(err error) (retry bool, delay time.Duration)
@@ -103,3 +108,153 @@ func (r *retry) RoundTrip(ctx context.Context, req, res soap.HasFault) error {
 
 	return err
 }
+
+// RetryPolicy configures automatic retries for vim25.Client.RoundTrip.
+// Retries are opt-in; a nil Client.RetryPolicy disables them.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first.
+	// Values less than 2 disable retries.
+	MaxAttempts int
+
+	// Retryable reports whether err is transient.
+	// Defaults to TransientError when nil.
+	Retryable func(err error) bool
+
+	// Backoff returns the delay before the given retry attempt (starting at 1).
+	// Defaults to no delay when nil.
+	Backoff func(attempt int) time.Duration
+
+	// Idempotent reports whether the named method may be safely replayed.
+	// Non-idempotent methods are only retried if the request never reached the server.
+	// Defaults to IdempotentMethod when nil.
+	Idempotent func(method string) bool
+}
+
+// ExponentialBackoff returns a RetryPolicy.Backoff func that doubles the
+// delay on each attempt, starting at base and capped at max.
+func ExponentialBackoff(base, max time.Duration) func(int) time.Duration {
+	return func(attempt int) time.Duration {
+		delay := base
+		for i := 1; i < attempt && delay < max; i++ {
+			delay *= 2
+		}
+		if delay > max {
+			delay = max
+		}
+		return delay
+	}
+}
+
+// idempotentPrefixes are method name prefixes of read-only vim25 methods.
+var idempotentPrefixes = []string{
+	"Retrieve",
+	"Query",
+	"Find",
+	"Fetch",
+	"CurrentTime",
+	"HasPrivilege",
+	"HasUserPrivilege",
+}
+
+// IdempotentMethod returns true if the named vim25 method does not modify server state.
+func IdempotentMethod(method string) bool {
+	for _, prefix := range idempotentPrefixes {
+		if strings.HasPrefix(method, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// TransientError returns true for errors that are likely to succeed if the call is retried:
+// temporary network errors, connection resets, HTTP 503 responses and NotAuthenticated faults.
+// Retrying a NotAuthenticated fault is only useful if the session is being re-established,
+// for example by a session.KeepAliveHandler.
+func TransientError(err error) bool {
+	if soap.IsSoapFault(err) {
+		switch soap.ToSoapFault(err).VimFault().(type) {
+		case types.NotAuthenticated, *types.NotAuthenticated:
+			return true
+		}
+		return false
+	}
+
+	if soap.IsRegularError(err) {
+		err = soap.ToRegularError(err)
+	}
+
+	if uerr, ok := err.(*url.Error); ok {
+		err = uerr.Err
+	}
+
+	if nerr, ok := err.(net.Error); ok && nerr.Temporary() {
+		return true
+	}
+
+	if isConnReset(err) {
+		return true
+	}
+
+	return strings.HasPrefix(err.Error(), "503 ")
+}
+
+func isConnReset(err error) bool {
+	if oerr, ok := err.(*net.OpError); ok {
+		err = oerr.Err
+	}
+	if serr, ok := err.(*os.SyscallError); ok {
+		err = serr.Err
+	}
+	return err == syscall.ECONNRESET || err == io.ErrUnexpectedEOF
+}
+
+// isDialError returns true if err indicates the request was never sent.
+func isDialError(err error) bool {
+	if soap.IsRegularError(err) {
+		err = soap.ToRegularError(err)
+	}
+	if uerr, ok := err.(*url.Error); ok {
+		err = uerr.Err
+	}
+	oerr, ok := err.(*net.OpError)
+	return ok && oerr.Op == "dial"
+}
+
+func (p *RetryPolicy) retryable(method string, err error) bool {
+	idempotent := p.Idempotent
+	if idempotent == nil {
+		idempotent = IdempotentMethod
+	}
+	if !idempotent(method) && !isDialError(err) {
+		return false
+	}
+
+	retryable := p.Retryable
+	if retryable == nil {
+		retryable = TransientError
+	}
+	return retryable(err)
+}
+
+// RoundTrip invokes rt, retrying transient errors as configured by the policy.
+func (p *RetryPolicy) RoundTrip(ctx context.Context, rt soap.RoundTripper, req, res soap.HasFault) error {
+	method := soap.MethodName(req)
+
+	for attempt := 1; ; attempt++ {
+		err := rt.RoundTrip(ctx, req, res)
+		if err == nil || attempt >= p.MaxAttempts || !p.retryable(method, err) {
+			return err
+		}
+
+		var delay time.Duration
+		if p.Backoff != nil {
+			delay = p.Backoff(attempt)
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+	}
+}
@@ -18,9 +18,14 @@ package vim25
 
 import (
 	"context"
+	"errors"
+	"net"
 	"testing"
+	"time"
 
+	"github.com/vmware/govmomi/vim25/methods"
 	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/govmomi/vim25/types"
 )
 
 type tempError struct{}
@@ -82,3 +87,65 @@ func TestRetry(t *testing.T) {
 		}
 	}
 }
+
+func TestRetryPolicy(t *testing.T) {
+	notAuthenticated := &soap.Fault{}
+	notAuthenticated.Detail.Fault = types.NotAuthenticated{}
+	unavailable := soap.WrapRegularError(errors.New("503 Service Unavailable"))
+	dial := soap.WrapRegularError(&net.OpError{Op: "dial", Err: tempError{}})
+
+	var tcs = []struct {
+		req      soap.HasFault
+		errs     []error
+		expected error
+	}{
+		{
+			req:      &methods.RetrievePropertiesBody{},
+			errs:     []error{tempError{}, soap.WrapSoapFault(notAuthenticated), unavailable, nil},
+			expected: nil,
+		},
+		{
+			req:      &methods.RetrievePropertiesBody{},
+			errs:     []error{unavailable, unavailable, unavailable, unavailable},
+			expected: unavailable,
+		},
+		{
+			req:      &methods.RetrievePropertiesBody{},
+			errs:     []error{nonTempError{}},
+			expected: nonTempError{},
+		},
+		{
+			req:      &methods.PowerOnVM_TaskBody{},
+			errs:     []error{unavailable},
+			expected: unavailable,
+		},
+		{
+			req:      &methods.PowerOnVM_TaskBody{},
+			errs:     []error{dial, nil},
+			expected: nil,
+		},
+	}
+
+	for i, tc := range tcs {
+		rt := &fakeRoundTripper{errs: tc.errs}
+		policy := &RetryPolicy{
+			MaxAttempts: 4,
+			Backoff:     ExponentialBackoff(time.Millisecond, 2*time.Millisecond),
+		}
+
+		err := policy.RoundTrip(context.TODO(), rt, tc.req, nil)
+		if err != tc.expected {
+			t.Errorf("%d: Expected: %v, got: %v", i, tc.expected, err)
+		}
+	}
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	backoff := ExponentialBackoff(time.Second, 5*time.Second)
+
+	for attempt, expected := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second} {
+		if delay := backoff(attempt + 1); delay != expected {
+			t.Errorf("attempt %d: expected %s, got %s", attempt+1, expected, delay)
+		}
+	}
+}
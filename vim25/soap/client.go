@@ -19,10 +19,13 @@ package soap
 import (
 	"bufio"
 	"bytes"
+	"compress/gzip"
 	"context"
 	"crypto/sha1"
+	"crypto/sha256"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -31,7 +34,6 @@ import (
 	"log"
 	"net"
 	"net/http"
-	"net/http/cookiejar"
 	"net/url"
 	"os"
 	"path/filepath"
@@ -74,6 +76,22 @@ type Client struct {
 	Types     types.Func
 	UserAgent string
 
+	// AcceptGzip requests gzip compressed responses, which are transparently decompressed.
+	// Unlike http.Transport's compression, this applies to any Transport and the debug trace
+	// captures the decompressed response body.
+	AcceptGzip bool
+	// GzipRequest compresses SOAP request bodies, the server must support gzip Content-Encoding.
+	GzipRequest bool
+
+	// RoundTripHook, if set, is called after each SOAP RoundTrip, for example to export metrics.
+	RoundTripHook func(context.Context, RoundTripInfo)
+
+	// StickyCookies retains the full cookie jar, rather than only the cookies for the Client's URL,
+	// for example where a load balancer uses additional cookies for session affinity.
+	// When true, clients created by NewServiceClient share this Client's cookie jar
+	// and MarshalJSON persists all cookies along with their attributes.
+	StickyCookies bool
+
 	cookie string
 }
 
@@ -146,16 +164,18 @@ func NewClient(u *url.URL, insecure bool) *Client {
 	c.t.TLSClientConfig = &tls.Config{InsecureSkipVerify: c.k}
 	// Don't bother setting DialTLS if InsecureSkipVerify=true
 	if !c.k {
-		c.t.DialTLS = c.dialTLS
+		c.t.DialTLSContext = c.dialTLS
 	}
 
 	c.Client.Transport = c.t
-	c.Client.Jar, _ = cookiejar.New(nil)
+	c.Client.Jar = newCookieJar()
 
 	// Remove user information from a copy of the URL
 	c.u = c.URL()
 	c.u.User = nil
 
+	c.useSocksProxyFromEnvironment()
+
 	return &c
 }
 
@@ -173,6 +193,9 @@ func (c *Client) NewServiceClient(path string, namespace string) *Client {
 
 	client := NewClient(u, c.k)
 	client.Namespace = "urn:" + namespace
+	client.SetTransportOptions(c.TransportOptions())
+	client.SetDialContext(c.t.DialContext)
+	client.t.Proxy = c.t.Proxy
 	client.Transport.(*http.Transport).TLSClientConfig = c.Transport.(*http.Transport).TLSClientConfig
 	if cert := c.Certificate(); cert != nil {
 		client.SetCertificate(*cert)
@@ -185,8 +208,13 @@ func (c *Client) NewServiceClient(path string, namespace string) *Client {
 	}
 	c.hostsMu.Unlock()
 
-	// Copy the cookies
-	client.Client.Jar.SetCookies(u, c.Client.Jar.Cookies(u))
+	// Share or copy the cookies
+	if _, ok := c.Client.Jar.(*cookieJar); ok && c.StickyCookies {
+		client.Client.Jar = c.Client.Jar
+		client.StickyCookies = true
+	} else {
+		client.Client.Jar.SetCookies(u, c.Client.Jar.Cookies(u))
+	}
 
 	// Set SOAP Header cookie
 	for _, cookie := range client.Jar.Cookies(u) {
@@ -200,6 +228,9 @@ func (c *Client) NewServiceClient(path string, namespace string) *Client {
 	client.u.RawQuery = vc.RawQuery
 
 	client.UserAgent = c.UserAgent
+	client.AcceptGzip = c.AcceptGzip
+	client.GzipRequest = c.GzipRequest
+	client.RoundTripHook = c.RoundTripHook
 
 	vimTypes := c.Types
 	client.Types = func(name string) (reflect.Type, bool) {
@@ -215,6 +246,59 @@ func (c *Client) NewServiceClient(path string, namespace string) *Client {
 	return client
 }
 
+// TransportOptions configures the connection pool and protocol of the Client's http.Transport,
+// without having to replace the Transport and its TLS settings.
+type TransportOptions struct {
+	// MaxIdleConns is the maximum number of idle (keep-alive) connections across all hosts, zero means no limit.
+	MaxIdleConns int
+	// MaxIdleConnsPerHost is the maximum number of idle connections per host,
+	// zero means http.DefaultMaxIdleConnsPerHost.
+	MaxIdleConnsPerHost int
+	// MaxConnsPerHost limits the number of connections per host, including those in use, zero means no limit.
+	// Requests block until a connection is available when the limit is reached.
+	MaxConnsPerHost int
+	// IdleConnTimeout is how long an idle connection remains in the pool, zero means no limit.
+	IdleConnTimeout time.Duration
+	// HTTP2 attempts to use HTTP/2, when supported by the server.
+	HTTP2 bool
+}
+
+// TransportOptions returns the current connection pool and protocol settings of the Client's http.Transport.
+func (c *Client) TransportOptions() TransportOptions {
+	return TransportOptions{
+		MaxIdleConns:        c.t.MaxIdleConns,
+		MaxIdleConnsPerHost: c.t.MaxIdleConnsPerHost,
+		MaxConnsPerHost:     c.t.MaxConnsPerHost,
+		IdleConnTimeout:     c.t.IdleConnTimeout,
+		HTTP2:               c.t.ForceAttemptHTTP2,
+	}
+}
+
+// SetTransportOptions configures the connection pool and protocol of the Client's http.Transport,
+// typically starting with the current settings, for example:
+//
+//	opts := c.TransportOptions()
+//	opts.MaxIdleConnsPerHost = 32
+//	c.SetTransportOptions(opts)
+//
+// The HTTP2 option only takes effect if set before the Client sends its first request.
+// Clients created by NewServiceClient inherit these settings.
+func (c *Client) SetTransportOptions(opts TransportOptions) {
+	c.t.MaxIdleConns = opts.MaxIdleConns
+	c.t.MaxIdleConnsPerHost = opts.MaxIdleConnsPerHost
+	c.t.MaxConnsPerHost = opts.MaxConnsPerHost
+	c.t.IdleConnTimeout = opts.IdleConnTimeout
+	c.t.ForceAttemptHTTP2 = opts.HTTP2
+}
+
+// SetDialContext sets the function used to create network connections, for example to connect via a tunnel
+// or a unix domain socket. The function is also used for TLS connections, such that thumbprint based
+// verification still applies. A nil function uses net.Dialer.
+// Clients created by NewServiceClient inherit this setting.
+func (c *Client) SetDialContext(dial func(ctx context.Context, network, addr string) (net.Conn, error)) {
+	c.t.DialContext = dial
+}
+
 // SetRootCAs defines the set of root certificate authorities
 // that clients use when verifying server certificates.
 // By default TLS uses the host's root CA set.
@@ -251,11 +335,12 @@ func hostAddr(addr string) string {
 }
 
 // SetThumbprint sets the known certificate thumbprint for the given host.
-// A custom DialTLS function is used to support thumbprint based verification.
+// The thumbprint may be SHA-1 or SHA-256, see MatchThumbprint.
+// A custom DialTLSContext function is used to support thumbprint based verification.
 // We first try tls.Dial with the default tls.Config, only falling back to thumbprint verification
 // if it fails with an x509.UnknownAuthorityError or x509.HostnameError
 //
-// See: http.Client.Transport.DialTLS
+// See: http.Client.Transport.DialTLSContext
 func (c *Client) SetThumbprint(host string, thumbprint string) {
 	host = hostAddr(host)
 
@@ -276,8 +361,34 @@ func (c *Client) Thumbprint(host string) string {
 	return c.hosts[host]
 }
 
+// ThumbprintSHA1 returns the thumbprint known for the given host in the SHA-1 format required by the SDK,
+// such as HostConnectSpec.SslThumbprint. A known SHA-1 thumbprint is returned without any "sha1:" prefix.
+// A SHA-256 thumbprint cannot be converted, so the SHA-1 thumbprint of the given peer cert is returned
+// instead, if the cert matches the known thumbprint. Otherwise, an empty string is returned.
+func (c *Client) ThumbprintSHA1(host string, cert *x509.Certificate) string {
+	known := c.Thumbprint(host)
+	if known == "" {
+		return ""
+	}
+
+	if i := strings.Index(known, ":"); i > 0 && strings.EqualFold(known[:i], "sha1") {
+		known = known[i+1:]
+	}
+	if sum, err := hex.DecodeString(strings.Replace(known, ":", "", -1)); err == nil && len(sum) == sha1.Size {
+		return thumbprint(sum)
+	}
+
+	if cert != nil && MatchThumbprint(known, cert) {
+		return ThumbprintSHA1(cert)
+	}
+
+	return ""
+}
+
 // LoadThumbprints from file with the give name.
 // If name is empty or name does not exist this function will return nil.
+// Each line of the file is in the format "host thumbprint", where thumbprint is SHA-1 or SHA-256,
+// optionally prefixed with "sha1:" or "sha256:". See MatchThumbprint.
 func (c *Client) LoadThumbprints(file string) error {
 	if file == "" {
 		return nil
@@ -323,6 +434,16 @@ func (c *Client) loadThumbprints(name string) error {
 // See: SSLVerifyFault.Thumbprint, SessionManagerGenericServiceTicket.Thumbprint, HostConnectSpec.SslThumbprint
 func ThumbprintSHA1(cert *x509.Certificate) string {
 	sum := sha1.Sum(cert.Raw)
+	return thumbprint(sum[:])
+}
+
+// ThumbprintSHA256 returns the SHA-256 thumbprint of the given cert, in the same format as ThumbprintSHA1.
+func ThumbprintSHA256(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	return thumbprint(sum[:])
+}
+
+func thumbprint(sum []byte) string {
 	hex := make([]string, len(sum))
 	for i, b := range sum {
 		hex[i] = fmt.Sprintf("%02X", b)
@@ -330,20 +451,82 @@ func ThumbprintSHA1(cert *x509.Certificate) string {
 	return strings.Join(hex, ":")
 }
 
-func (c *Client) dialTLS(network string, addr string) (net.Conn, error) {
+// MatchThumbprint returns true if the given thumbprint matches the cert.
+// The thumbprint may be SHA-1 or SHA-256, with an optional "sha1:" or "sha256:" prefix.
+// Without a prefix, the algorithm is detected by length. Case and ':' separators are ignored.
+func MatchThumbprint(thumbprint string, cert *x509.Certificate) bool {
+	algo := ""
+	if i := strings.Index(thumbprint, ":"); i > 0 {
+		switch prefix := strings.ToLower(thumbprint[:i]); prefix {
+		case "sha1", "sha256":
+			algo = prefix
+			thumbprint = thumbprint[i+1:]
+		}
+	}
+
+	thumbprint = strings.ToUpper(strings.Replace(thumbprint, ":", "", -1))
+
+	if algo == "" {
+		switch len(thumbprint) {
+		case sha1.Size * 2:
+			algo = "sha1"
+		case sha256.Size * 2:
+			algo = "sha256"
+		}
+	}
+
+	var peer string
+	switch algo {
+	case "sha1":
+		peer = ThumbprintSHA1(cert)
+	case "sha256":
+		peer = ThumbprintSHA256(cert)
+	default:
+		return false
+	}
+
+	return thumbprint == strings.Replace(peer, ":", "", -1)
+}
+
+// tlsDial is similar to tls.Dial, but uses the Transport's DialContext to create the connection.
+func (c *Client) tlsDial(ctx context.Context, network string, addr string, config *tls.Config) (*tls.Conn, error) {
+	dial := c.t.DialContext
+	if dial == nil {
+		dial = new(net.Dialer).DialContext
+	}
+
+	conn, err := dial(ctx, network, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.ServerName == "" {
+		config = config.Clone()
+		config.ServerName, _, _ = net.SplitHostPort(addr)
+	}
+
+	tc := tls.Client(conn, config)
+	if err = tc.HandshakeContext(ctx); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+
+	return tc, nil
+}
+
+func (c *Client) dialTLS(ctx context.Context, network string, addr string) (net.Conn, error) {
 	// Would be nice if there was a tls.Config.Verify func,
 	// see tls.clientHandshakeState.doFullHandshake
 
-	conn, err := tls.Dial(network, addr, c.t.TLSClientConfig)
+	conn, err := c.tlsDial(ctx, network, addr, c.t.TLSClientConfig)
 
 	if err == nil {
 		return conn, nil
 	}
 
-	switch err.(type) {
-	case x509.UnknownAuthorityError:
-	case x509.HostnameError:
-	default:
+	var authErr x509.UnknownAuthorityError
+	var hostErr x509.HostnameError
+	if !errors.As(err, &authErr) && !errors.As(err, &hostErr) {
 		return nil, err
 	}
 
@@ -352,15 +535,14 @@ func (c *Client) dialTLS(network string, addr string) (net.Conn, error) {
 		return nil, err
 	}
 
-	config := &tls.Config{InsecureSkipVerify: true}
-	conn, err = tls.Dial(network, addr, config)
+	config := &tls.Config{InsecureSkipVerify: true, NextProtos: c.t.TLSClientConfig.NextProtos}
+	conn, err = c.tlsDial(ctx, network, addr, config)
 	if err != nil {
 		return nil, err
 	}
 
 	cert := conn.ConnectionState().PeerCertificates[0]
-	peer := ThumbprintSHA1(cert)
-	if thumbprint != peer {
+	if !MatchThumbprint(thumbprint, cert) {
 		_ = conn.Close()
 
 		return nil, fmt.Errorf("host %q thumbprint does not match %q", addr, thumbprint)
@@ -429,9 +611,57 @@ func (c *Client) Tunnel() *Client {
 
 	// Rewrite url Host to use the sdk tunnel, required for a certificate request.
 	tunnel.u.Host = sdkTunnel
+
+	// When http.Transport.Proxy is used, DialTLSContext and its thumbprint checker are bypassed
+	// and the certificate presented by vCenter is not valid for the sdkTunnel host name.
+	// Verify the certificate against the vCenter host name or known thumbprint instead.
+	// A SOCKS proxy (see SetSocksProxy) is replaced by the tunnel proxy, along with its VerifyConnection func.
+	t.TLSClientConfig = t.TLSClientConfig.Clone()
+	if !t.TLSClientConfig.InsecureSkipVerify || t.TLSClientConfig.VerifyConnection != nil {
+		t.TLSClientConfig.InsecureSkipVerify = true
+		t.TLSClientConfig.VerifyConnection = nil
+		t.TLSClientConfig.VerifyPeerCertificate = c.verifyTunnel(t.TLSClientConfig.RootCAs)
+	}
+
 	return tunnel
 }
 
+// verifyTunnel returns a tls.Config.VerifyPeerCertificate function that verifies the
+// certificate chain using the Client's host name, falling back to a known thumbprint.
+func (c *Client) verifyTunnel(roots *x509.CertPool) func([][]byte, [][]*x509.Certificate) error {
+	name := c.u.Hostname()
+	thumbprint := c.Thumbprint(c.u.Host)
+
+	return func(raw [][]byte, _ [][]*x509.Certificate) error {
+		certs := make([]*x509.Certificate, len(raw))
+		for i := range raw {
+			cert, err := x509.ParseCertificate(raw[i])
+			if err != nil {
+				return err
+			}
+			certs[i] = cert
+		}
+		if len(certs) == 0 {
+			return errors.New("no peer certificate")
+		}
+
+		opts := x509.VerifyOptions{
+			DNSName:       name,
+			Roots:         roots,
+			Intermediates: x509.NewCertPool(),
+		}
+		for _, cert := range certs[1:] {
+			opts.Intermediates.AddCert(cert)
+		}
+
+		_, err := certs[0].Verify(opts)
+		if err != nil && thumbprint != "" && MatchThumbprint(thumbprint, certs[0]) {
+			return nil
+		}
+		return err
+	}
+}
+
 func (c *Client) URL() *url.URL {
 	urlCopy := *c.u
 	return &urlCopy
@@ -442,6 +672,7 @@ type marshaledClient struct {
 	URL      *url.URL
 	Insecure bool
 	Version  string
+	Jar      []jarCookie `json:",omitempty"`
 }
 
 func (c *Client) MarshalJSON() ([]byte, error) {
@@ -452,6 +683,10 @@ func (c *Client) MarshalJSON() ([]byte, error) {
 		Version:  c.Version,
 	}
 
+	if jar, ok := c.Jar.(*cookieJar); ok && c.StickyCookies {
+		m.Jar = jar.all()
+	}
+
 	return json.Marshal(m)
 }
 
@@ -467,11 +702,47 @@ func (c *Client) UnmarshalJSON(b []byte) error {
 	c.Version = m.Version
 	c.Jar.SetCookies(m.URL, m.Cookies)
 
+	if len(m.Jar) != 0 {
+		c.StickyCookies = true
+		c.Jar.(*cookieJar).replay(m.Jar)
+	}
+
 	return nil
 }
 
 type kindContext struct{}
 
+// gzipBody closes both the gzip.Reader and the response body it decompresses.
+type gzipBody struct {
+	*gzip.Reader
+	body io.Closer
+}
+
+func (b gzipBody) Close() error {
+	_ = b.Reader.Close()
+	return b.body.Close()
+}
+
+// gzipRequest replaces the request body with its gzip compressed content.
+func gzipRequest(req *http.Request) error {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	_, err := io.Copy(gz, req.Body)
+	_ = req.Body.Close()
+	if err != nil {
+		return err
+	}
+	if err = gz.Close(); err != nil {
+		return err
+	}
+
+	req.Body = ioutil.NopCloser(&buf)
+	req.ContentLength = int64(buf.Len())
+	req.GetBody = nil
+	req.Header.Set("Content-Encoding", "gzip")
+	return nil
+}
+
 func (c *Client) Do(ctx context.Context, req *http.Request, f func(*http.Response) error) error {
 	if ctx == nil {
 		ctx = context.Background()
@@ -486,23 +757,41 @@ func (c *Client) Do(ctx context.Context, req *http.Request, f func(*http.Respons
 		req.Header.Set(`User-Agent`, c.UserAgent)
 	}
 
+	// Only decompress responses if the caller did not request its own encoding
+	gunzip := c.AcceptGzip && req.Header.Get("Accept-Encoding") == ""
+	if gunzip {
+		req.Header.Set("Accept-Encoding", "gzip")
+	}
+
 	ext := ""
 	if d.enabled() {
 		ext = d.debugRequest(req)
 	}
 
+	// Compress after debugRequest, so the debug trace captures the uncompressed request body
+	if _, ok := ctx.Value(kindContext{}).(HasFault); ok && c.GzipRequest && req.Body != nil {
+		if err := gzipRequest(req); err != nil {
+			return err
+		}
+	}
+
 	tstart := time.Now()
 	res, err := c.Client.Do(req.WithContext(ctx))
 	tstop := time.Now()
 
 	if d.enabled() {
-		var name string
 		if kind, ok := ctx.Value(kindContext{}).(HasFault); ok {
-			name = fmt.Sprintf("%T", kind)
+			d.logf("%6dms (%T)", tstop.Sub(tstart)/time.Millisecond, kind)
 		} else {
-			name = fmt.Sprintf("%s %s", req.Method, req.URL)
+			// Non-SOAP requests, such as the vAPI REST client, also log the response status
+			var status string
+			if err == nil {
+				status = res.Status
+			} else {
+				status = err.Error()
+			}
+			d.logf("%6dms (%s %s) %s", tstop.Sub(tstart)/time.Millisecond, req.Method, req.URL, status)
 		}
-		d.logf("%6dms (%s)", tstop.Sub(tstart)/time.Millisecond, name)
 	}
 
 	if err != nil {
@@ -511,6 +800,18 @@ func (c *Client) Do(ctx context.Context, req *http.Request, f func(*http.Respons
 
 	defer res.Body.Close()
 
+	if gunzip && res.Header.Get("Content-Encoding") == "gzip" {
+		gz, err := gzip.NewReader(res.Body)
+		if err != nil {
+			return err
+		}
+		res.Body = gzipBody{gz, res.Body}
+		res.Header.Del("Content-Encoding")
+		res.Header.Del("Content-Length")
+		res.ContentLength = -1
+		res.Uncompressed = true
+	}
+
 	if d.enabled() {
 		d.debugResponse(res, ext)
 	}
@@ -532,9 +833,77 @@ func (c *Client) WithHeader(ctx context.Context, header Header) context.Context
 	return context.WithValue(ctx, headerContext{}, header)
 }
 
+// RoundTripInfo describes a completed SOAP RoundTrip, as passed to Client.RoundTripHook.
+type RoundTripInfo struct {
+	Method       string        // Method name, such as "RetrieveProperties"
+	Duration     time.Duration // Time from sending the request until the response was decoded
+	RequestSize  int64         // Request body size in bytes, before compression
+	ResponseSize int64         // Response body bytes read, after decompression
+	StatusCode   int           // HTTP status code, zero if no response was received
+	Fault        string        // Fault type name, such as "NotAuthenticated", empty if none
+	Err          error         // Error returned by RoundTrip
+}
+
+// MethodName returns the method name of the given request body, such as "RetrieveProperties".
+// The name is taken from the type of the body's Req field if any, otherwise from the body type name.
+func MethodName(body HasFault) string {
+	if body == nil {
+		return ""
+	}
+
+	v := reflect.ValueOf(body)
+	for v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+
+	t := v.Type()
+	if v.Kind() == reflect.Struct {
+		if f, ok := t.FieldByName("Req"); ok {
+			t = f.Type
+			for t.Kind() == reflect.Ptr {
+				t = t.Elem()
+			}
+			return t.Name()
+		}
+	}
+
+	return strings.TrimSuffix(t.Name(), "Body")
+}
+
+// faultName returns the vim fault type name of err, or the SOAP fault code if the fault has no detail.
+func faultName(err error) string {
+	if !IsSoapFault(err) {
+		return ""
+	}
+
+	f := ToSoapFault(err)
+	if f.Detail.Fault == nil {
+		return f.Code
+	}
+
+	t := reflect.TypeOf(f.Detail.Fault)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Name()
+}
+
+// countingReader counts the number of bytes read from r.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
 func (c *Client) RoundTrip(ctx context.Context, reqBody, resBody HasFault) error {
 	var err error
 	var b []byte
+	var info RoundTripInfo
 
 	reqEnv := Envelope{Body: reqBody}
 	resEnv := Envelope{Body: resBody}
@@ -580,7 +949,12 @@ func (c *Client) RoundTrip(ctx context.Context, reqBody, resBody HasFault) error
 	}
 	req.Header.Set(`SOAPAction`, action)
 
-	return c.Do(context.WithValue(ctx, kindContext{}, resBody), req, func(res *http.Response) error {
+	info.RequestSize = int64(len(xml.Header) + len(b))
+	start := time.Now()
+
+	err = c.Do(context.WithValue(ctx, kindContext{}, resBody), req, func(res *http.Response) error {
+		info.StatusCode = res.StatusCode
+
 		switch res.StatusCode {
 		case http.StatusOK:
 			// OK
@@ -590,7 +964,10 @@ func (c *Client) RoundTrip(ctx context.Context, reqBody, resBody HasFault) error
 			return errors.New(res.Status)
 		}
 
-		dec := xml.NewDecoder(res.Body)
+		body := &countingReader{r: res.Body}
+		defer func() { info.ResponseSize = body.n }()
+
+		dec := xml.NewDecoder(body)
 		dec.TypeFunc = c.Types
 		err = dec.Decode(&resEnv)
 		if err != nil {
@@ -603,6 +980,16 @@ func (c *Client) RoundTrip(ctx context.Context, reqBody, resBody HasFault) error
 
 		return err
 	})
+
+	if c.RoundTripHook != nil {
+		info.Method = MethodName(reqBody)
+		info.Duration = time.Since(start)
+		info.Fault = faultName(err)
+		info.Err = err
+		c.RoundTripHook(ctx, info)
+	}
+
+	return err
 }
 
 func (c *Client) CloseIdleConnections() {
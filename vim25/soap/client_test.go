@@ -17,11 +17,56 @@ limitations under the License.
 package soap
 
 import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 )
 
+type testMethod struct{}
+
+type testMethodBody struct {
+	Fault_ *Fault `xml:"http://schemas.xmlsoap.org/soap/envelope/ Fault,omitempty"`
+}
+
+func (b *testMethodBody) Fault() *Fault { return b.Fault_ }
+
+type testStreamBody struct {
+	Req    *testMethod
+	Fault_ *Fault `xml:"http://schemas.xmlsoap.org/soap/envelope/ Fault,omitempty"`
+}
+
+func (b *testStreamBody) Fault() *Fault { return b.Fault_ }
+
+func TestMethodName(t *testing.T) {
+	tests := []struct {
+		body HasFault
+		name string
+	}{
+		{nil, ""},
+		{new(testMethodBody), "testMethod"},
+		{new(testStreamBody), "testMethod"},
+	}
+
+	for _, test := range tests {
+		if name := MethodName(test.body); name != test.name {
+			t.Errorf("%T: name=%q", test.body, name)
+		}
+	}
+}
+
 func TestSplitHostPort(t *testing.T) {
 	tests := []struct {
 		url  string
@@ -97,3 +142,277 @@ func setCAsOnClient(cas string) error {
 
 	return client.SetRootCAs(cas)
 }
+
+func TestTransportOptions(t *testing.T) {
+	s := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Proto))
+	}))
+	s.EnableHTTP2 = true
+	s.StartTLS()
+	defer s.Close()
+
+	u, err := url.Parse(s.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, insecure := range []bool{true, false} {
+		c := NewClient(u, insecure)
+		if !insecure {
+			// verified connections use Client.dialTLS
+			c.t.TLSClientConfig.RootCAs = x509.NewCertPool()
+			c.t.TLSClientConfig.RootCAs.AddCert(s.Certificate())
+		}
+
+		opts := c.TransportOptions()
+		opts.MaxIdleConnsPerHost = 16
+		opts.IdleConnTimeout = time.Minute
+		opts.HTTP2 = true
+		c.SetTransportOptions(opts)
+
+		if sc := c.NewServiceClient("/sdk", "vim25"); sc.TransportOptions() != opts {
+			t.Errorf("service client options=%#v", sc.TransportOptions())
+		}
+
+		req, err := http.NewRequest(http.MethodGet, s.URL, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = c.Do(context.Background(), req, func(res *http.Response) error {
+			if res.Proto != "HTTP/2.0" {
+				t.Errorf("insecure=%t: proto=%s", insecure, res.Proto)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestDialContext(t *testing.T) {
+	dir, err := ioutil.TempDir("", "govmomi-soap")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	sock := filepath.Join(dir, "vcsim.sock")
+
+	for _, scheme := range []string{"http", "https"} {
+		l, err := net.Listen("unix", sock)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(r.Host))
+		}))
+		s.Listener = l
+		if scheme == "http" {
+			s.Start()
+		} else {
+			s.StartTLS()
+		}
+
+		u := &url.URL{Scheme: scheme, Host: "vcsim.local", Path: "/sdk"}
+		c := NewClient(u, false)
+		if scheme == "https" {
+			// verified connections use Client.dialTLS
+			c.t.TLSClientConfig.RootCAs = x509.NewCertPool()
+			c.t.TLSClientConfig.RootCAs.AddCert(s.Certificate())
+			c.t.TLSClientConfig.ServerName = "example.com" // httptest certificate name
+		}
+
+		dials := 0
+		c.SetDialContext(func(ctx context.Context, _, _ string) (net.Conn, error) {
+			dials++
+			return new(net.Dialer).DialContext(ctx, "unix", sock)
+		})
+
+		req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = c.Do(context.Background(), req, func(res *http.Response) error {
+			b, err := ioutil.ReadAll(res.Body)
+			if string(b) != u.Host {
+				t.Errorf("%s: host=%s", scheme, b)
+			}
+			return err
+		})
+		if err != nil {
+			t.Fatalf("%s: %s", scheme, err)
+		}
+
+		if dials != 1 {
+			t.Errorf("%s: dials=%d", scheme, dials)
+		}
+
+		s.Close()
+	}
+}
+
+func TestStickyCookies(t *testing.T) {
+	node := 0
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/sdk" {
+			node++
+			http.SetCookie(w, &http.Cookie{Name: "lb", Value: fmt.Sprintf("node%d", node), Path: "/"})
+			http.SetCookie(w, &http.Cookie{Name: "pinned", Value: "yes", Path: "/rest", MaxAge: 60})
+		}
+		var names []string
+		for _, cookie := range r.Cookies() {
+			names = append(names, cookie.String())
+		}
+		sort.Strings(names)
+		_, _ = w.Write([]byte(strings.Join(names, ";")))
+	}))
+	defer s.Close()
+
+	get := func(c *Client, path string) string {
+		u := c.URL()
+		u.Path = path
+		req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var cookies string
+		err = c.Do(context.Background(), req, func(res *http.Response) error {
+			b, err := ioutil.ReadAll(res.Body)
+			cookies = string(b)
+			return err
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return cookies
+	}
+
+	u, _ := url.Parse(s.URL + "/sdk")
+
+	for _, sticky := range []bool{false, true} {
+		c := NewClient(u, false)
+		c.StickyCookies = sticky
+		get(c, "/sdk")
+
+		rc := c.NewServiceClient("/rest", "")
+		get(c, "/sdk") // affinity cookie changes after the service client was created
+
+		expect := "lb=node1;pinned=yes" // copied when the service client was created
+		if sticky {
+			expect = "lb=node" + strconv.Itoa(node) + ";pinned=yes"
+		}
+		if cookies := get(rc, "/rest"); cookies != expect {
+			t.Errorf("sticky=%t service client cookies=%q", sticky, cookies)
+		}
+
+		b, err := json.Marshal(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		restored := new(Client)
+		if err = json.Unmarshal(b, restored); err != nil {
+			t.Fatal(err)
+		}
+
+		expect = "lb=node" + strconv.Itoa(node)
+		if sticky {
+			expect += ";pinned=yes"
+		}
+		if cookies := get(restored, "/rest"); cookies != expect {
+			t.Errorf("sticky=%t restored cookies=%q", sticky, cookies)
+		}
+		if restored.StickyCookies != sticky {
+			t.Errorf("sticky=%t restored StickyCookies=%t", sticky, restored.StickyCookies)
+		}
+
+		node = 0
+	}
+}
+
+func TestMatchThumbprint(t *testing.T) {
+	s := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer s.Close()
+
+	cert := s.Certificate()
+	sha1 := ThumbprintSHA1(cert)
+	sha256 := ThumbprintSHA256(cert)
+
+	tests := []struct {
+		thumbprint string
+		match      bool
+	}{
+		{sha1, true},
+		{sha256, true},
+		{strings.ToLower(sha1), true},
+		{strings.Replace(sha256, ":", "", -1), true},
+		{"sha1:" + sha1, true},
+		{"SHA256:" + sha256, true},
+		{"sha1:" + sha256, false},
+		{"sha256:" + sha1, false},
+		{sha256[3:], false},
+		{"", false},
+	}
+
+	for _, test := range tests {
+		if MatchThumbprint(test.thumbprint, cert) != test.match {
+			t.Errorf("%q match=%t", test.thumbprint, !test.match)
+		}
+	}
+
+	u, _ := url.Parse(s.URL + "/sdk")
+
+	for _, thumbprint := range []string{sha1, sha256, "sha256:" + sha1} {
+		c := NewClient(u, false)
+		c.SetThumbprint(u.Host, thumbprint)
+
+		req, err := http.NewRequest(http.MethodGet, s.URL, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = c.Do(context.Background(), req, func(*http.Response) error { return nil })
+		if MatchThumbprint(thumbprint, cert) {
+			if err != nil {
+				t.Errorf("%s: %s", thumbprint, err)
+			}
+		} else if err == nil {
+			t.Errorf("%s: expected error", thumbprint)
+		}
+	}
+}
+
+func TestClientThumbprintSHA1(t *testing.T) {
+	s := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer s.Close()
+	cert := s.Certificate()
+	other := &x509.Certificate{Raw: []byte("other")}
+	sha1 := ThumbprintSHA1(cert)
+	sha256 := ThumbprintSHA256(cert)
+
+	tests := []struct {
+		known  string
+		cert   *x509.Certificate
+		expect string
+	}{
+		{"", cert, ""},
+		{sha1, nil, sha1},
+		{"sha1:" + sha1, nil, sha1},
+		{strings.ToLower(strings.Replace(sha1, ":", "", -1)), nil, sha1},
+		{sha256, nil, ""},
+		{sha256, cert, sha1},
+		{"sha256:" + sha256, cert, sha1},
+		{sha256, other, ""},
+	}
+
+	u, _ := url.Parse(s.URL + "/sdk")
+	c := NewClient(u, false)
+
+	for _, test := range tests {
+		c.SetThumbprint(u.Host, test.known)
+		if tp := c.ThumbprintSHA1(u.Host, test.cert); tp != test.expect {
+			t.Errorf("%q: %q", test.known, tp)
+		}
+	}
+}
@@ -17,10 +17,13 @@ limitations under the License.
 package soap
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httputil"
+	"regexp"
+	"strings"
 	"sync/atomic"
 	"time"
 
@@ -40,6 +43,89 @@ func newTeeReader(rc io.ReadCloser, w io.Writer) io.ReadCloser {
 	}
 }
 
+const redacted = "********"
+
+// redactHeaders are the http headers containing credentials.
+var redactHeaders = []string{
+	"Authorization",
+	"Cookie",
+	"Set-Cookie",
+	"vmware-api-session-id",
+}
+
+// redactElements are the names of XML elements containing credentials, including SAML tokens.
+const redactElements = `(?:\w+:)?(?:password|[a-z]\w*Password|sessionI[Dd]|cloneTicket|base64Token|Assertion|BinarySecurityToken)`
+
+var (
+	// redactXML matches the content of redactElements.
+	redactXML = regexp.MustCompile(`(?s)(<` + redactElements + `(?:\s[^>]*[^/>])?>).*?(</` + redactElements + `>)`)
+
+	// redactJSON matches the value of JSON password fields.
+	redactJSON = regexp.MustCompile(`("\w*[Pp]assword"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+
+	// redactSession matches the session ID of a vAPI session create response: {"value":"id"} or "id".
+	redactSession = regexp.MustCompile(`^(\s*(?:\{\s*"value"\s*:\s*)?)"(?:[^"\\]|\\.)*"`)
+)
+
+// isSessionCreate returns true if req creates a vAPI session, the response of which is a session ID.
+func isSessionCreate(req *http.Request) bool {
+	if req == nil || req.Method != http.MethodPost || req.URL.RawQuery != "" {
+		return false
+	}
+	return strings.HasSuffix(req.URL.Path, "/com/vmware/cis/session") || strings.HasSuffix(req.URL.Path, "/api/session")
+}
+
+func redactBody(b []byte) []byte {
+	b = redactXML.ReplaceAll(b, []byte("${1}"+redacted+"${2}"))
+	return redactJSON.ReplaceAll(b, []byte(`${1}"`+redacted+`"`))
+}
+
+// redactHeader returns a copy of h with credentials redacted, or h itself if redaction is disabled.
+func redactHeader(h http.Header) http.Header {
+	if !debug.Redaction() {
+		return h
+	}
+
+	c := make(http.Header, len(h))
+	for k, v := range h {
+		c[k] = v
+	}
+
+	for _, k := range redactHeaders {
+		if c.Get(k) != "" {
+			c.Set(k, redacted)
+		}
+	}
+
+	return c
+}
+
+// redactWriter buffers writes, redacting credentials when closed.
+type redactWriter struct {
+	bytes.Buffer
+	wc      io.WriteCloser
+	session bool // redact the session ID of a session create response
+}
+
+func newRedactWriter(wc io.WriteCloser) io.WriteCloser {
+	if !debug.Redaction() {
+		return wc
+	}
+	return &redactWriter{wc: wc}
+}
+
+func (w *redactWriter) Close() error {
+	b := redactBody(w.Bytes())
+	if w.session {
+		b = redactSession.ReplaceAll(b, []byte(`${1}"`+redacted+`"`))
+	}
+	_, err := w.wc.Write(b)
+	if cerr := w.wc.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
 // debugRoundTrip contains state and logic needed to debug a single round trip.
 type debugRoundTrip struct {
 	cn  uint64         // Client number
@@ -85,13 +171,15 @@ func (d *debugRoundTrip) debugRequest(req *http.Request) string {
 
 	// Capture headers
 	var wc io.WriteCloser = d.newFile("req.headers")
-	b, _ := httputil.DumpRequest(req, false)
+	dreq := *req
+	dreq.Header = redactHeader(req.Header)
+	b, _ := httputil.DumpRequest(&dreq, false)
 	wc.Write(b)
 	wc.Close()
 
 	ext := d.ext(req.Header)
 	// Capture body
-	wc = d.newFile("req." + ext)
+	wc = newRedactWriter(d.newFile("req." + ext))
 	req.Body = newTeeReader(req.Body, wc)
 
 	// Delay closing until marked done
@@ -107,12 +195,17 @@ func (d *debugRoundTrip) debugResponse(res *http.Response, ext string) {
 
 	// Capture headers
 	var wc io.WriteCloser = d.newFile("res.headers")
-	b, _ := httputil.DumpResponse(res, false)
+	dres := *res
+	dres.Header = redactHeader(res.Header)
+	b, _ := httputil.DumpResponse(&dres, false)
 	wc.Write(b)
 	wc.Close()
 
 	// Capture body
-	wc = d.newFile("res." + ext)
+	wc = newRedactWriter(d.newFile("res." + ext))
+	if w, ok := wc.(*redactWriter); ok {
+		w.session = isSessionCreate(res.Request)
+	}
 	res.Body = newTeeReader(res.Body, wc)
 
 	// Delay closing until marked done
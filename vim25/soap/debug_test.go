@@ -0,0 +1,80 @@
+/*
+Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package soap
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestRedactBody(t *testing.T) {
+	tests := []struct {
+		in, out string
+	}{
+		{
+			`<Login><userName>root</userName><password>secret</password></Login>`,
+			`<Login><userName>root</userName><password>********</password></Login>`,
+		},
+		{
+			`<ChangePassword><oldPassword xsi:type="xsd:string">a</oldPassword><newPassword>b</newPassword></ChangePassword>`,
+			`<ChangePassword><oldPassword xsi:type="xsd:string">********</oldPassword><newPassword>********</newPassword></ChangePassword>`,
+		},
+		{
+			"<Header><saml2:Assertion ID=\"_1\">\n<saml2:Subject>x</saml2:Subject>\n</saml2:Assertion></Header>",
+			`<Header><saml2:Assertion ID="_1">********</saml2:Assertion></Header>`,
+		},
+		{
+			`<password/><sessionID>52a</sessionID>`,
+			`<password/><sessionID>********</sessionID>`,
+		},
+		{
+			`{"user":"root","password":"se\"cret"}`,
+			`{"user":"root","password":"********"}`,
+		},
+	}
+
+	for _, test := range tests {
+		out := string(redactBody([]byte(test.in)))
+		if out != test.out {
+			t.Errorf("redactBody(%s)=%s", test.in, out)
+		}
+	}
+}
+
+func TestRedactHeader(t *testing.T) {
+	h := http.Header{}
+	h.Set("Cookie", "vmware_soap_session=secret")
+	h.Set("vmware-api-session-id", "secret")
+	h.Set("Content-Type", "text/xml")
+
+	r := redactHeader(h)
+
+	for k := range r {
+		if strings.Contains(r.Get(k), "secret") {
+			t.Errorf("%s=%s", k, r.Get(k))
+		}
+	}
+
+	if r.Get("Content-Type") != "text/xml" {
+		t.Errorf("Content-Type=%s", r.Get("Content-Type"))
+	}
+
+	if h.Get("Cookie") != "vmware_soap_session=secret" {
+		t.Error("original header was modified")
+	}
+}
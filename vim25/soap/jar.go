@@ -0,0 +1,112 @@
+/*
+Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package soap
+
+import (
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// jarCookie is a cookie along with the URL of the response that set it.
+type jarCookie struct {
+	URL    string
+	Cookie *http.Cookie
+}
+
+// cookieJar wraps cookiejar.Jar, recording each cookie set with its attributes,
+// as cookiejar.Jar.Cookies only returns name and value for a given URL.
+// The recorded cookies can then be replayed to restore the jar in full,
+// including cookies for paths other than the SOAP endpoint, such as load balancer affinity cookies.
+type cookieJar struct {
+	*cookiejar.Jar
+
+	mu      sync.Mutex
+	cookies map[string]jarCookie
+}
+
+func newCookieJar() *cookieJar {
+	jar, _ := cookiejar.New(nil)
+
+	return &cookieJar{
+		Jar:     jar,
+		cookies: make(map[string]jarCookie),
+	}
+}
+
+func (j *cookieJar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	j.Jar.SetCookies(u, cookies)
+
+	now := time.Now()
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	for _, cookie := range cookies {
+		c := *cookie
+		key := u.Host + ";" + c.Domain + ";" + c.Path + ";" + c.Name
+
+		if c.MaxAge < 0 || (!c.Expires.IsZero() && c.Expires.Before(now)) {
+			delete(j.cookies, key)
+			continue
+		}
+
+		if c.MaxAge > 0 {
+			// MaxAge is relative to when the cookie was set, convert to Expires for replay
+			c.Expires = now.Add(time.Duration(c.MaxAge) * time.Second)
+			c.MaxAge = 0
+		}
+
+		// Drop the raw attributes, the parsed fields are used for replay
+		c.Raw = ""
+		c.Unparsed = nil
+
+		j.cookies[key] = jarCookie{URL: u.Scheme + "://" + u.Host + u.Path, Cookie: &c}
+	}
+}
+
+// all returns the recorded cookies that have not expired.
+func (j *cookieJar) all() []jarCookie {
+	now := time.Now()
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	cookies := make([]jarCookie, 0, len(j.cookies))
+	for key, c := range j.cookies {
+		if !c.Cookie.Expires.IsZero() && c.Cookie.Expires.Before(now) {
+			delete(j.cookies, key)
+			continue
+		}
+		cookies = append(cookies, c)
+	}
+
+	return cookies
+}
+
+// replay sets each of the given cookies, as recorded by all.
+func (j *cookieJar) replay(cookies []jarCookie) {
+	for _, c := range cookies {
+		u, err := url.Parse(c.URL)
+		if err != nil {
+			continue
+		}
+		j.SetCookies(u, []*http.Cookie{c.Cookie})
+	}
+}
@@ -0,0 +1,211 @@
+/*
+Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package soap
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// SetSocksProxy configures the Client to connect via the given SOCKS5 proxy, with URL scheme "socks5"
+// or "socks5h". Host names are resolved by the proxy with either scheme, see http.Transport.Proxy.
+// Credentials for the proxy can be specified using URL.User. The proxy is used for all connections
+// made by the Client, including file transfers, and replaces any HTTP proxy configuration.
+// Clients created by NewServiceClient inherit this setting.
+func (c *Client) SetSocksProxy(proxy *url.URL) error {
+	switch proxy.Scheme {
+	case "socks5", "socks5h":
+	default:
+		return fmt.Errorf("unsupported proxy scheme %q", proxy.Scheme)
+	}
+
+	c.setSocksProxy(http.ProxyURL(proxy))
+
+	return nil
+}
+
+// setSocksProxy sets the Transport.Proxy func and the certificate verification required when using it.
+func (c *Client) setSocksProxy(proxy func(*http.Request) (*url.URL, error)) {
+	c.t.Proxy = proxy
+
+	// When http.Transport.Proxy is used, DialTLSContext and its thumbprint checker are bypassed.
+	// Verify the certificate against the host name or known thumbprint instead.
+	if !c.t.TLSClientConfig.InsecureSkipVerify {
+		c.t.TLSClientConfig = c.t.TLSClientConfig.Clone()
+		c.t.TLSClientConfig.InsecureSkipVerify = true
+		c.t.TLSClientConfig.VerifyConnection = c.verifyConnection
+	}
+}
+
+// verifyConnection verifies the peer certificate chain using the connection's server name,
+// falling back to a thumbprint known for that server name.
+func (c *Client) verifyConnection(cs tls.ConnectionState) error {
+	certs := cs.PeerCertificates
+	if len(certs) == 0 {
+		return errors.New("no peer certificate")
+	}
+
+	opts := x509.VerifyOptions{
+		DNSName:       cs.ServerName,
+		Roots:         c.t.TLSClientConfig.RootCAs,
+		Intermediates: x509.NewCertPool(),
+	}
+	for _, cert := range certs[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+
+	_, err := certs[0].Verify(opts)
+	if err != nil && c.matchHostThumbprint(cs.ServerName, certs[0]) {
+		return nil
+	}
+	return err
+}
+
+// matchHostThumbprint returns true if cert matches a thumbprint known for the given host name, on any port.
+func (c *Client) matchHostThumbprint(name string, cert *x509.Certificate) bool {
+	c.hostsMu.Lock()
+	defer c.hostsMu.Unlock()
+
+	for addr, thumbprint := range c.hosts {
+		if host, _ := splitHostPort(addr); strings.Trim(host, "[]") == name && MatchThumbprint(thumbprint, cert) {
+			return true
+		}
+	}
+	return false
+}
+
+// socksProxyFromEnvironment returns the ALL_PROXY (or all_proxy) environment variable value,
+// if set to a SOCKS5 URL.
+func socksProxyFromEnvironment() *url.URL {
+	for _, key := range []string{"ALL_PROXY", "all_proxy"} {
+		if val := os.Getenv(key); val != "" {
+			u, err := url.Parse(val)
+			if err == nil && (u.Scheme == "socks5" || u.Scheme == "socks5h") {
+				return u
+			}
+			return nil
+		}
+	}
+	return nil
+}
+
+// socksNoProxy reports whether NO_PROXY excludes addr (host or host:port) from proxying.
+// Entries follow the rules of http.ProxyFromEnvironment: "*" matches all hosts, an IP address
+// or CIDR matches IP hosts, a domain name matches itself and its subdomains and a domain with a
+// leading "." matches subdomains only. Any entry may include a port.
+func socksNoProxy(addr string) bool {
+	val := os.Getenv("NO_PROXY")
+	if val == "" {
+		val = os.Getenv("no_proxy")
+	}
+	if val == "" {
+		return false
+	}
+
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		host, port = addr, ""
+	}
+	host = strings.ToLower(strings.Trim(host, "[]"))
+	ip := net.ParseIP(host)
+
+	for _, entry := range strings.Split(val, ",") {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+		if entry == "*" {
+			return true
+		}
+		if _, cidr, err := net.ParseCIDR(entry); err == nil {
+			if ip != nil && cidr.Contains(ip) {
+				return true
+			}
+			continue
+		}
+
+		name, eport, err := net.SplitHostPort(entry)
+		if err != nil {
+			name, eport = entry, ""
+		}
+		name = strings.Trim(name, "[]")
+		if eport != "" && eport != port {
+			continue
+		}
+
+		if eip := net.ParseIP(name); eip != nil {
+			if ip != nil && eip.Equal(ip) {
+				return true
+			}
+			continue
+		}
+
+		name = strings.TrimPrefix(name, "*")
+		if strings.HasPrefix(name, ".") {
+			if strings.HasSuffix(host, name) {
+				return true
+			}
+			continue
+		}
+		if host == name || strings.HasSuffix(host, "."+name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// useSocksProxyFromEnvironment configures the SOCKS5 proxy specified by ALL_PROXY.
+// The proxy is used for each request unless NO_PROXY excludes the request's host
+// or an HTTP proxy applies to the request.
+func (c *Client) useSocksProxyFromEnvironment() {
+	proxy := socksProxyFromEnvironment()
+	if proxy == nil {
+		return
+	}
+
+	httpProxy := c.t.Proxy
+
+	c.setSocksProxy(func(req *http.Request) (*url.URL, error) {
+		if httpProxy != nil {
+			if u, err := httpProxy(req); err != nil || u != nil {
+				return u, err
+			}
+		}
+
+		addr := req.URL.Host
+		if _, port := splitHostPort(addr); port == "" {
+			port = "443"
+			if req.URL.Scheme == "http" {
+				port = "80"
+			}
+			addr = net.JoinHostPort(strings.Trim(addr, "[]"), port)
+		}
+		if socksNoProxy(addr) {
+			return nil, nil
+		}
+
+		return proxy, nil
+	})
+}
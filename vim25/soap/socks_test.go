@@ -0,0 +1,304 @@
+/*
+Copyright (c) 2018 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package soap
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strconv"
+	"sync/atomic"
+	"testing"
+)
+
+// SOCKS5 protocol constants, see RFC 1928 and RFC 1929
+const (
+	socksVersion      = 0x05
+	socksAuthNone     = 0x00
+	socksAuthPassword = 0x02
+	socksAddrIPv4     = 0x01
+	socksAddrDomain   = 0x03
+)
+
+// socksServer is a minimal SOCKS5 proxy, supporting the CONNECT command with optional username/password authentication.
+type socksServer struct {
+	net.Listener
+	user     *url.Userinfo
+	connects int32
+}
+
+func newSocksServer(t *testing.T, user *url.Userinfo) *socksServer {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &socksServer{Listener: l, user: user}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *socksServer) serve(conn net.Conn) {
+	defer conn.Close()
+
+	buf := make([]byte, 262)
+	if _, err := io.ReadFull(conn, buf[:2]); err != nil {
+		return
+	}
+	if _, err := io.ReadFull(conn, buf[:buf[1]]); err != nil {
+		return
+	}
+
+	if s.user == nil {
+		_, _ = conn.Write([]byte{socksVersion, socksAuthNone})
+	} else {
+		_, _ = conn.Write([]byte{socksVersion, socksAuthPassword})
+		if _, err := io.ReadFull(conn, buf[:2]); err != nil {
+			return
+		}
+		user := make([]byte, buf[1])
+		_, _ = io.ReadFull(conn, user)
+		_, _ = io.ReadFull(conn, buf[:1])
+		password := make([]byte, buf[0])
+		_, _ = io.ReadFull(conn, password)
+		p, _ := s.user.Password()
+		if string(user) != s.user.Username() || string(password) != p {
+			_, _ = conn.Write([]byte{0x01, 0x01})
+			return
+		}
+		_, _ = conn.Write([]byte{0x01, 0x00})
+	}
+
+	if _, err := io.ReadFull(conn, buf[:4]); err != nil {
+		return
+	}
+
+	var host string
+	switch buf[3] {
+	case socksAddrIPv4:
+		_, _ = io.ReadFull(conn, buf[:net.IPv4len])
+		host = net.IP(buf[:net.IPv4len]).String()
+	case socksAddrDomain:
+		_, _ = io.ReadFull(conn, buf[:1])
+		name := make([]byte, buf[0])
+		_, _ = io.ReadFull(conn, name)
+		host = string(name)
+	default:
+		return
+	}
+	_, _ = io.ReadFull(conn, buf[:2])
+	port := binary.BigEndian.Uint16(buf[:2])
+
+	target, err := net.Dial("tcp", net.JoinHostPort(host, strconv.Itoa(int(port))))
+	if err != nil {
+		_, _ = conn.Write([]byte{socksVersion, 0x05, 0, socksAddrIPv4, 0, 0, 0, 0, 0, 0})
+		return
+	}
+	defer target.Close()
+
+	atomic.AddInt32(&s.connects, 1)
+	_, _ = conn.Write([]byte{socksVersion, 0, 0, socksAddrIPv4, 0, 0, 0, 0, 0, 0})
+
+	go func() { _, _ = io.Copy(target, conn) }()
+	_, _ = io.Copy(conn, target)
+}
+
+func TestSocksProxy(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer s.Close()
+
+	_, port, _ := net.SplitHostPort(s.Listener.Addr().String())
+	u := &url.URL{Scheme: "http", Host: net.JoinHostPort("localhost", port), Path: "/sdk"}
+
+	get := func(c *Client) error {
+		req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+		if err != nil {
+			return err
+		}
+		return c.Do(context.Background(), req, func(res *http.Response) error {
+			b, err := ioutil.ReadAll(res.Body)
+			if string(b) != "ok" {
+				t.Errorf("body=%s", b)
+			}
+			return err
+		})
+	}
+
+	tests := []struct {
+		scheme string
+		user   *url.Userinfo
+	}{
+		{"socks5", nil},
+		{"socks5h", nil},
+		{"socks5h", url.UserPassword("user", "pass")},
+	}
+
+	for _, test := range tests {
+		proxy := newSocksServer(t, test.user)
+
+		c := NewClient(u, true)
+		err := c.SetSocksProxy(&url.URL{Scheme: test.scheme, Host: proxy.Addr().String(), User: test.user})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err = get(c.NewServiceClient("/sdk", "vim25")); err != nil {
+			t.Errorf("%s: %s", test.scheme, err)
+		}
+		if atomic.LoadInt32(&proxy.connects) != 1 {
+			t.Errorf("%s: connects=%d", test.scheme, proxy.connects)
+		}
+
+		if test.user != nil {
+			c = NewClient(u, true)
+			_ = c.SetSocksProxy(&url.URL{Scheme: test.scheme, Host: proxy.Addr().String(), User: url.UserPassword("user", "invalid")})
+			if err = get(c); err == nil {
+				t.Error("expected authentication error")
+			}
+		}
+
+		_ = proxy.Close()
+	}
+
+	if err := NewClient(u, true).SetSocksProxy(&url.URL{Scheme: "http", Host: "proxy"}); err == nil {
+		t.Error("expected unsupported scheme error")
+	}
+
+	proxy := newSocksServer(t, nil)
+	defer proxy.Close()
+
+	os.Setenv("ALL_PROXY", "socks5://"+proxy.Addr().String())
+	defer os.Unsetenv("ALL_PROXY")
+
+	if err := get(NewClient(u, true)); err != nil {
+		t.Fatal(err)
+	}
+	if atomic.LoadInt32(&proxy.connects) != 1 {
+		t.Errorf("ALL_PROXY: connects=%d", proxy.connects)
+	}
+
+	os.Setenv("NO_PROXY", "localhost")
+	defer os.Unsetenv("NO_PROXY")
+
+	if err := get(NewClient(u, true)); err != nil {
+		t.Fatal(err)
+	}
+	if atomic.LoadInt32(&proxy.connects) != 1 {
+		t.Errorf("NO_PROXY: connects=%d", proxy.connects)
+	}
+
+	// NO_PROXY applies per request, not only to the Client's URL
+	os.Setenv("NO_PROXY", "127.0.0.1")
+	c := NewClient(u, true)
+	if err := get(c); err != nil {
+		t.Fatal(err)
+	}
+	if atomic.LoadInt32(&proxy.connects) != 2 {
+		t.Errorf("NO_PROXY: connects=%d", proxy.connects)
+	}
+	req, _ := http.NewRequest(http.MethodGet, s.URL, nil)
+	if err := c.Do(context.Background(), req, func(*http.Response) error { return nil }); err != nil {
+		t.Fatal(err)
+	}
+	if atomic.LoadInt32(&proxy.connects) != 2 {
+		t.Errorf("NO_PROXY: connects=%d", proxy.connects)
+	}
+}
+
+func TestSocksProxyThumbprint(t *testing.T) {
+	s := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer s.Close()
+
+	proxy := newSocksServer(t, nil)
+	defer proxy.Close()
+
+	// The test certificate is not valid for localhost, requiring the thumbprint
+	_, port, _ := net.SplitHostPort(s.Listener.Addr().String())
+	u := &url.URL{Scheme: "https", Host: net.JoinHostPort("localhost", port), Path: "/sdk"}
+
+	for _, thumbprint := range []string{"", ThumbprintSHA256(s.Certificate())} {
+		c := NewClient(u, false)
+		c.SetThumbprint(u.Host, thumbprint)
+		if err := c.SetSocksProxy(&url.URL{Scheme: "socks5", Host: proxy.Addr().String()}); err != nil {
+			t.Fatal(err)
+		}
+
+		req, _ := http.NewRequest(http.MethodGet, u.String(), nil)
+		err := c.Do(context.Background(), req, func(*http.Response) error { return nil })
+		if thumbprint == "" {
+			if err == nil {
+				t.Error("expected error")
+			}
+		} else if err != nil {
+			t.Error(err)
+		}
+	}
+
+	if atomic.LoadInt32(&proxy.connects) != 2 {
+		t.Errorf("connects=%d", proxy.connects)
+	}
+}
+
+func TestSocksNoProxy(t *testing.T) {
+	tests := []struct {
+		noProxy string
+		addr    string
+		match   bool
+	}{
+		{"", "vc.example.com:443", false},
+		{"*", "vc.example.com:443", true},
+		{"example.com", "vc.example.com:443", true},
+		{"example.com", "example.com", true},
+		{"example.com", "vcexample.com:443", false},
+		{".example.com", "vc.example.com:443", true},
+		{".example.com", "example.com:443", false},
+		{"*.example.com", "vc.example.com", true},
+		{"other.com, VC.example.com", "vc.example.com:443", true},
+		{"vc.example.com:8443", "vc.example.com:443", false},
+		{"vc.example.com:443", "vc.example.com:443", true},
+		{"10.0.0.0/8", "10.1.2.3:443", true},
+		{"10.0.0.0/8", "192.168.1.1:443", false},
+		{"192.168.1.1", "192.168.1.1:443", true},
+		{"::1", "[::1]:443", true},
+		{"[::1]:443", "[::1]:443", true},
+	}
+
+	defer os.Unsetenv("NO_PROXY")
+
+	for _, test := range tests {
+		os.Setenv("NO_PROXY", test.noProxy)
+		if match := socksNoProxy(test.addr); match != test.match {
+			t.Errorf("NO_PROXY=%q %s: match=%t", test.noProxy, test.addr, match)
+		}
+	}
+}
@@ -123,27 +123,14 @@ func (m ObjectManager) PlaceDisk(ctx context.Context, spec *types.VslmCreateSpec
 	return nil
 }
 
-func (m ObjectManager) CreateDisk(ctx context.Context, spec types.VslmCreateSpec) (*object.Task, error) {
-	req := types.CreateDisk_Task{
-		This: m.Reference(),
-		Spec: spec,
-	}
-
-	if m.isVC {
-		res, err := methods.CreateDisk_Task(ctx, m.c, &req)
-		if err != nil {
-			return nil, err
-		}
-
-		return object.NewTask(m.c, res.Returnval), nil
-	}
-
-	res, err := methods.HostCreateDisk_Task(ctx, m.c, (*types.HostCreateDisk_Task)(&req))
-	if err != nil {
-		return nil, err
-	}
+// object returns an object.VStorageObjectManager with the same reference as m.
+func (m ObjectManager) object() *object.VStorageObjectManager {
+	return object.NewVStorageObjectManager(m.c, m.Reference())
+}
 
-	return object.NewTask(m.c, res.Returnval), nil
+// CreateDisk creates a First Class Disk, see object.VStorageObjectManager.CreateDisk.
+func (m ObjectManager) CreateDisk(ctx context.Context, spec types.VslmCreateSpec) (*object.Task, error) {
+	return m.object().CreateDisk(ctx, spec)
 }
 
 func (m ObjectManager) Rename(ctx context.Context, ds mo.Reference, id, name string) error {
@@ -163,75 +150,29 @@ func (m ObjectManager) Rename(ctx context.Context, ds mo.Reference, id, name str
 	return err
 }
 
+// Delete deletes disk id from datastore ds, see object.VStorageObjectManager.Delete.
 func (m ObjectManager) Delete(ctx context.Context, ds mo.Reference, id string) (*object.Task, error) {
-	req := types.DeleteVStorageObject_Task{
-		This:      m.Reference(),
-		Datastore: ds.Reference(),
-		Id:        types.ID{Id: id},
-	}
-
-	if m.isVC {
-		res, err := methods.DeleteVStorageObject_Task(ctx, m.c, &req)
-		if err != nil {
-			return nil, err
-		}
-
-		return object.NewTask(m.c, res.Returnval), nil
-	}
+	return m.object().Delete(ctx, ds, id)
+}
 
-	res, err := methods.HostDeleteVStorageObject_Task(ctx, m.c, (*types.HostDeleteVStorageObject_Task)(&req))
-	if err != nil {
-		return nil, err
-	}
+// AttachDisk attaches disk id on datastore ds to the given VM, see object.VStorageObjectManager.AttachDisk.
+func (m ObjectManager) AttachDisk(ctx context.Context, vm mo.Reference, ds mo.Reference, id string, controllerKey int32, unitNumber *int32) (*object.Task, error) {
+	return m.object().AttachDisk(ctx, vm, ds, id, controllerKey, unitNumber)
+}
 
-	return object.NewTask(m.c, res.Returnval), nil
+// DetachDisk detaches disk id from the given VM, see object.VStorageObjectManager.DetachDisk.
+func (m ObjectManager) DetachDisk(ctx context.Context, vm mo.Reference, id string) (*object.Task, error) {
+	return m.object().DetachDisk(ctx, vm, id)
 }
 
+// Retrieve returns disk id on datastore ds, see object.VStorageObjectManager.Retrieve.
 func (m ObjectManager) Retrieve(ctx context.Context, ds mo.Reference, id string) (*types.VStorageObject, error) {
-	req := types.RetrieveVStorageObject{
-		This:      m.Reference(),
-		Datastore: ds.Reference(),
-		Id:        types.ID{Id: id},
-	}
-
-	if m.isVC {
-		res, err := methods.RetrieveVStorageObject(ctx, m.c, &req)
-		if err != nil {
-			return nil, err
-		}
-
-		return &res.Returnval, nil
-	}
-
-	res, err := methods.HostRetrieveVStorageObject(ctx, m.c, (*types.HostRetrieveVStorageObject)(&req))
-	if err != nil {
-		return nil, err
-	}
-
-	return &res.Returnval, nil
+	return m.object().Retrieve(ctx, ds, id)
 }
 
+// List returns the disk IDs on datastore ds, see object.VStorageObjectManager.List.
 func (m ObjectManager) List(ctx context.Context, ds mo.Reference) ([]types.ID, error) {
-	req := types.ListVStorageObject{
-		This:      m.Reference(),
-		Datastore: ds.Reference(),
-	}
-
-	if m.isVC {
-		res, err := methods.ListVStorageObject(ctx, m.c, &req)
-		if err != nil {
-			return nil, err
-		}
-
-		return res.Returnval, nil
-	}
-
-	res, err := methods.HostListVStorageObject(ctx, m.c, (*types.HostListVStorageObject)(&req))
-	if err != nil {
-		return nil, err
-	}
-
-	return res.Returnval, nil
+	return m.object().List(ctx, ds)
 }
 
 func (m ObjectManager) RegisterDisk(ctx context.Context, path, name string) (*types.VStorageObject, error) {